@@ -17,15 +17,17 @@ type addOptions struct {
 	withValidation bool
 	withStatus     bool
 	withVersioning bool
+	yaml           bool
 	packageName    string
+	from           string
 }
 
 func newAddCommand() *cobra.Command {
 	opts := &addOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "add resource [name]",
-		Short: "Add a new resource to your project",
+		Use:   "add resource [name] | add version [resource] [version]",
+		Short: "Add a new resource, or a schema version of one, to your project",
 		Long: `Add a new resource definition to your project.
 
 This creates:
@@ -34,22 +36,34 @@ This creates:
   - Optional validation
   - Registration code
 
+'add version' adds a schema version of a resource as a package below the
+resource's, e.g. pkg/resources/device/v2. It creates:
+  - A copy of the resource's types, marked with +fabrica:version
+  - Conversion stubs between the version and v1, the stored version
+  - Registration of the version with the server, in cmd/server
+
 Example:
   fabrica add resource Device
   fabrica add resource Product --with-validation
+  fabrica add resource Rack --yaml   # YAML definition generating the Go types
+  fabrica add version Device v2
+  fabrica add version Device v3 --from v2
 `,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
-			if args[0] != "resource" {
-				return fmt.Errorf("unknown resource type: %s (only 'resource' is supported)", args[0])
-			}
-
-			if len(args) < 2 {
-				return fmt.Errorf("resource name required")
+			switch args[0] {
+			case "resource":
+				if len(args) < 2 {
+					return fmt.Errorf("resource name required")
+				}
+				return runAddResource(args[1], opts)
+			case "version":
+				if len(args) < 3 {
+					return fmt.Errorf("resource name and version required, e.g. 'fabrica add version Device v2'")
+				}
+				return runAddVersion(args[1], args[2], opts.from)
 			}
-
-			resourceName := args[1]
-			return runAddResource(resourceName, opts)
+			return fmt.Errorf("unknown type: %s (only 'resource' and 'version' are supported)", args[0])
 		},
 	}
 
@@ -57,6 +71,8 @@ Example:
 	cmd.Flags().BoolVar(&opts.withStatus, "with-status", true, "Include Status struct")
 	cmd.Flags().BoolVar(&opts.withVersioning, "with-versioning", false, "Enable per-resource spec versioning (snapshots). Status is never versioned.")
 	cmd.Flags().StringVar(&opts.packageName, "package", "", "Package name (defaults to lowercase resource name)")
+	cmd.Flags().BoolVar(&opts.yaml, "yaml", false, "Define the resource in YAML, generating its Go types")
+	cmd.Flags().StringVar(&opts.from, "from", "", "Version whose types a new version copies (default: the resource's own types)")
 
 	return cmd
 }
@@ -96,9 +112,15 @@ func runAddResource(resourceName string, opts *addOptions) error {
 		return fmt.Errorf("failed to create package directory: %w", err)
 	}
 
-	// Generate resource file
+	// Generate resource file, or a definition generating it
 	resourceFile := filepath.Join(pkgDir, opts.packageName+".go")
-	if err := generateResourceFile(resourceFile, resourceName, opts); err != nil {
+	if opts.yaml {
+		definitionFile, err := writeResourceDefinition(resourceName, opts)
+		if err != nil {
+			return err
+		}
+		resourceFile = definitionFile
+	} else if err := generateResourceFile(resourceFile, resourceName, opts); err != nil {
 		return err
 	}
 
@@ -178,9 +200,10 @@ type %sSpec struct {`, resourceName, resourceName, resourceName)
 		content += fmt.Sprintf(`
 // %sStatus defines the observed state of %s
 type %sStatus struct {
-	Phase      string `+"`json:\"phase,omitempty\"`"+`
-	Message    string `+"`json:\"message,omitempty\"`"+`
-	Ready      bool   `+"`json:\"ready\"`"+`
+	Phase      string               `+"`json:\"phase,omitempty\"`"+`
+	Message    string               `+"`json:\"message,omitempty\"`"+`
+	Ready      bool                 `+"`json:\"ready\"`"+`
+	Conditions []resource.Condition `+"`json:\"conditions,omitempty\"`"+` // Ready, Degraded (see resource.ConditionReady)
 `, resourceName, resourceName, resourceName)
 
 		if opts.withVersioning {
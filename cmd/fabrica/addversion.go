@@ -0,0 +1,439 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/openchami/fabrica/pkg/versioning"
+)
+
+// storedVersion is the schema version of a resource's own type, which the
+// server stores resources in
+const storedVersion = "v1"
+
+// runAddVersion scaffolds a schema version of a resource as a package below
+// the resource's, e.g. pkg/resources/device/v2: a copy of the resource's
+// types, or of those of another version, marked with +fabrica:version,
+// conversion stubs between the version and the stored one, and the
+// registration of the version with the server.
+func runAddVersion(resourceName, version, from string) error {
+	if err := versioning.ValidateVersion(version); err != nil {
+		return err
+	}
+	if version == storedVersion {
+		return fmt.Errorf("%s is the version of the resource type itself", storedVersion)
+	}
+
+	modulePath, err := getModulePath()
+	if err != nil {
+		return fmt.Errorf("failed to read module path: %w (make sure you're in a Go module)", err)
+	}
+	resourceFile, kind, resourcePackage, err := findResourceFile(resourceName)
+	if err != nil {
+		return err
+	}
+	resourceDir := filepath.Dir(resourceFile)
+	versionDir := filepath.Join(resourceDir, version)
+	if _, err := os.Stat(versionDir); err == nil {
+		return fmt.Errorf("%s already exists", versionDir)
+	}
+
+	sourceFile := resourceFile
+	if from != "" && from != storedVersion {
+		sourceFile = filepath.Join(resourceDir, from, strings.ToLower(kind)+".go")
+	}
+	source, err := os.ReadFile(sourceFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the types of %s: %w", kind, err)
+	}
+	if bytes.HasPrefix(source, []byte(definitionHeader)) {
+		return fmt.Errorf("%s is generated from a resource definition; add %s to the versions of the definition instead", sourceFile, version)
+	}
+
+	fmt.Printf("📦 Adding version %s of %s...\n", version, kind)
+
+	types, err := versionTypes(source, kind, version)
+	if err != nil {
+		return fmt.Errorf("failed to copy the types of %s: %w", sourceFile, err)
+	}
+
+	rel, _ := filepath.Rel(filepath.Join("pkg", "resources"), resourceDir)
+	data := map[string]string{
+		"Kind":            kind,
+		"Version":         version,
+		"Stored":          storedVersion,
+		"Module":          modulePath,
+		"ResourceDir":     filepath.ToSlash(rel),
+		"StoredName":      strings.ToUpper(storedVersion[:1]) + storedVersion[1:],
+		"ResourcePackage": resourcePackage,
+		"Alias":           resourcePackage + version,
+	}
+	conversion, err := renderVersionFile(versionConversionTemplate, data)
+	if err != nil {
+		return err
+	}
+	registration, err := renderVersionFile(versionRegistrationTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	registrationFile := filepath.Join("cmd", "server", fmt.Sprintf("%s_%s_version.go", strings.ToLower(kind), version))
+	files := []struct {
+		path    string
+		content []byte
+	}{
+		{filepath.Join(versionDir, strings.ToLower(kind)+".go"), types},
+		{filepath.Join(versionDir, "conversion.go"), conversion},
+		{registrationFile, registration},
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(file.path, file.content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+		fmt.Printf("  ✓ Created %s\n", file.path)
+	}
+
+	fmt.Println()
+	fmt.Println("✅ Version added successfully!")
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Printf("  1. Change the types in %s\n", files[0].path)
+	fmt.Printf("  2. Convert the fields that changed in %s\n", files[1].path)
+	fmt.Println("  3. Run 'fabrica check-compat', acknowledging intentional breaking changes")
+	fmt.Println("  4. Run 'fabrica generate'")
+	if config, err := LoadConfig(""); err == nil && config.Features.Versioning.Enabled {
+		switch config.Features.Versioning.Strategy {
+		case "url", "both":
+			fmt.Printf("  5. Add %s to features.versioning.versions in %s to serve /%s routes\n", version, ConfigFileName, version)
+		}
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// findResourceFile returns the Go file declaring a resource, matching its
+// name case-insensitively, with the resource's kind and package name
+func findResourceFile(resourceName string) (string, string, string, error) {
+	var path, kind, pkg string
+	err := filepath.Walk(filepath.Join("pkg", "resources"), func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != "" || info.IsDir() || !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil // Skip files that don't parse
+		}
+		markers := versionMarkers(node)
+		for _, decl := range node.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if _, ok := markers[typeSpec]; ok || !embedsResource(typeSpec) {
+					continue
+				}
+				if strings.EqualFold(typeSpec.Name.Name, resourceName) {
+					path, kind, pkg = file, typeSpec.Name.Name, node.Name.Name
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return "", "", "", fmt.Errorf("failed to discover resources: %w", err)
+	}
+	if path == "" {
+		return "", "", "", fmt.Errorf("resource %s not found in pkg/resources", resourceName)
+	}
+	return path, kind, pkg, nil
+}
+
+// versionTypes copies the Go file of a resource's types into the package of
+// version: the package is renamed, the resource type is marked with the
+// version, and init functions, which register the resource, are left out
+func versionTypes(source []byte, kind, version string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	// Edits replace byte ranges of the source, applied from the end
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+
+	packageDoc := fmt.Sprintf("// Package %s is the %s schema of %s.\n", version, version, kind)
+	if file.Doc != nil {
+		edits = append(edits, edit{offset(file.Doc.Pos()), offset(file.Package), packageDoc})
+	} else {
+		edits = append(edits, edit{offset(file.Package), offset(file.Package), packageDoc})
+	}
+	edits = append(edits, edit{offset(file.Name.Pos()), offset(file.Name.End()), version})
+
+	marked := false
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil && decl.Name.Name == "init" {
+				start := decl.Pos()
+				if decl.Doc != nil {
+					start = decl.Doc.Pos()
+				}
+				edits = append(edits, edit{offset(start), offset(decl.End()), ""})
+			}
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Name.Name != kind || !embedsResource(typeSpec) {
+					continue
+				}
+				marked = true
+
+				// Mark the type, dropping the markers of the version copied
+				doc := typeSpec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				var lines []string
+				if doc != nil {
+					for _, c := range doc.List {
+						text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+						if !strings.HasPrefix(text, "+fabrica:version=") && !strings.HasPrefix(text, "+fabrica:breaking=") {
+							lines = append(lines, c.Text)
+						}
+					}
+				} else {
+					lines = append(lines, fmt.Sprintf("// %s is the %s schema of %s", kind, version, kind))
+				}
+				lines = append(lines, "// +fabrica:version="+version)
+				text := strings.Join(lines, "\n")
+
+				switch {
+				case doc != nil:
+					edits = append(edits, edit{offset(doc.Pos()), offset(doc.End()), text})
+				case decl.Lparen.IsValid():
+					edits = append(edits, edit{offset(typeSpec.Pos()), offset(typeSpec.Pos()), text + "\n"})
+				default:
+					edits = append(edits, edit{offset(decl.Pos()), offset(decl.Pos()), text + "\n"})
+				}
+			}
+		}
+	}
+	if !marked {
+		return nil, fmt.Errorf("no type %s embedding resource.Resource", kind)
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), source...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	out, err = removeUnusedImports(out)
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(out)
+}
+
+// removeUnusedImports drops the imports a file no longer refers to, such as
+// those of the init functions left out of a version's types
+func removeUnusedImports(source []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", source, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var unused []*ast.ImportSpec
+	for _, spec := range file.Imports {
+		path := strings.Trim(spec.Path.Value, `"`)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "_" && name != "." && !used[name] {
+			unused = append(unused, spec)
+		}
+	}
+
+	out := append([]byte(nil), source...)
+	for i := len(unused) - 1; i >= 0; i-- {
+		start, end := fset.Position(unused[i].Pos()).Offset, fset.Position(unused[i].End()).Offset
+		out = append(out[:start], out[end:]...)
+	}
+	return out, nil
+}
+
+// renderVersionFile executes a template of add version and formats the Go
+// source it renders
+func renderVersionFile(text string, data map[string]string) ([]byte, error) {
+	tmpl, err := template.New("version").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// versionConversionTemplate renders conversion.go of a version package
+const versionConversionTemplate = `// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package {{.Version}}
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"{{.Module}}/pkg/resources/{{.ResourceDir}}"
+	"github.com/openchami/fabrica/pkg/versioning"
+)
+
+// Register registers {{.Version}} of {{.Kind}} with a version registry, converting
+// from and to {{.Stored}}, the version the server stores, with Converter. {{.Stored}} is
+// registered as the default version unless registered already.
+func Register(registry *versioning.VersionRegistry) error {
+	if _, ok := registry.GetVersion("{{.Kind}}", "{{.Stored}}"); !ok {
+		err := registry.RegisterVersion("{{.Kind}}", "{{.Stored}}", versioning.ResourceTypeInfo{
+			Type:        reflect.TypeOf(&{{.ResourcePackage}}.{{.Kind}}{}),
+			Constructor: func() interface{} { return &{{.ResourcePackage}}.{{.Kind}}{} },
+			Metadata:    versioning.SchemaVersion{Version: "{{.Stored}}", IsDefault: true, Stability: "stable"},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return registry.RegisterVersion("{{.Kind}}", "{{.Version}}", versioning.ResourceTypeInfo{
+		Type:        reflect.TypeOf(&{{.Kind}}{}),
+		Constructor: func() interface{} { return &{{.Kind}}{} },
+		Converter:   Converter{},
+		Metadata:    versioning.SchemaVersion{Version: "{{.Version}}", Stability: versioning.GetStabilityLevel("{{.Version}}")},
+	})
+}
+
+// Converter converts {{.Kind}} resources between {{.Stored}} and {{.Version}}
+type Converter struct{}
+
+// CanConvert reports whether the versions are {{.Stored}} and {{.Version}}
+func (Converter) CanConvert(fromVersion, toVersion string) bool {
+	return (fromVersion == "{{.Stored}}" && toVersion == "{{.Version}}") || (fromVersion == "{{.Version}}" && toVersion == "{{.Stored}}")
+}
+
+// Convert converts a *{{.ResourcePackage}}.{{.Kind}} to a *{{.Kind}}, or back
+func (Converter) Convert(resource interface{}, fromVersion, toVersion string) (interface{}, error) {
+	switch resource := resource.(type) {
+	case *{{.ResourcePackage}}.{{.Kind}}:
+		return From{{.StoredName}}(resource)
+	case *{{.Kind}}:
+		return To{{.StoredName}}(resource)
+	}
+	return nil, fmt.Errorf("cannot convert %T from %s to %s", resource, fromVersion, toVersion)
+}
+
+// ConvertSpec isn't supported: the server converts whole resources
+func (Converter) ConvertSpec(spec interface{}, fromVersion, toVersion string) (interface{}, error) {
+	return nil, fmt.Errorf("converting the spec of {{.Kind}} alone isn't supported")
+}
+
+// ConvertStatus isn't supported: the server converts whole resources
+func (Converter) ConvertStatus(status interface{}, fromVersion, toVersion string) (interface{}, error) {
+	return nil, fmt.Errorf("converting the status of {{.Kind}} alone isn't supported")
+}
+
+// From{{.StoredName}} converts a {{.Kind}} from {{.Stored}} to {{.Version}}. Fields with the same JSON
+// name and type are copied; convert the fields that differ here, e.g.:
+//
+//	out.Spec.Address = in.Spec.IP
+func From{{.StoredName}}(in *{{.ResourcePackage}}.{{.Kind}}) (*{{.Kind}}, error) {
+	out := &{{.Kind}}{}
+	if err := copyFields(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// To{{.StoredName}} converts a {{.Kind}} from {{.Version}} to {{.Stored}}, the inverse of From{{.StoredName}}
+func To{{.StoredName}}(in *{{.Kind}}) (*{{.ResourcePackage}}.{{.Kind}}, error) {
+	out := &{{.ResourcePackage}}.{{.Kind}}{}
+	if err := copyFields(in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// copyFields copies the fields of one version into another through JSON
+func copyFields(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+`
+
+// versionRegistrationTemplate renders the file of cmd/server registering a
+// version with the server
+const versionRegistrationTemplate = `// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	{{.Alias}} "{{.Module}}/pkg/resources/{{.ResourceDir}}/{{.Version}}"
+	"github.com/openchami/fabrica/pkg/versioning"
+)
+
+// Serve {{.Version}} of {{.Kind}}, converted from and to the stored version
+func init() {
+	if err := {{.Alias}}.Register(versioning.GlobalVersionRegistry); err != nil {
+		panic(err)
+	}
+}
+`
@@ -0,0 +1,260 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaVersionType is a Go type declaring another schema version of a
+// resource, marked in its doc comment:
+//
+//	// +fabrica:version=v2
+//	// +fabrica:breaking=username
+//	type DeviceV2 struct { ... }
+type schemaVersionType struct {
+	Resource string   // Resource declared in the same package (e.g., "Device")
+	Version  string   // Version from the +fabrica:version marker (e.g., "v2")
+	Type     string   // Go type name (e.g., "DeviceV2")
+	Package  string   // Go package name (e.g., "device")
+	Dir      string   // Package directory below pkg/resources (e.g., "device")
+	Breaking []string // Spec fields from the +fabrica:breaking marker
+}
+
+func newCheckCompatCommand() *cobra.Command {
+	var debug bool
+
+	cmd := &cobra.Command{
+		Use:   "check-compat",
+		Short: "Check schema versions for breaking changes",
+		Long: `Compare the spec fields of each resource's schema versions, in version
+order, and report breaking changes: fields that were removed, changed type,
+or became required.
+
+Schema versions are Go types in a resource's package marked with
++fabrica:version. A breaking change is acknowledged by listing the field's
+JSON name in a +fabrica:breaking marker on the later version:
+
+  // +fabrica:version=v2
+  // +fabrica:breaking=username,port
+  type DeviceV2 struct { ... }
+
+The command exits non-zero when a breaking change isn't acknowledged;
+'fabrica generate' fails on the same changes.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			modulePath, err := getModulePath()
+			if err != nil {
+				return fmt.Errorf("failed to read module path: %w (make sure you're in a Go module)", err)
+			}
+
+			resources, err := discoverResources()
+			if err != nil {
+				return fmt.Errorf("failed to discover resources: %w", err)
+			}
+			if len(resources) == 0 {
+				fmt.Println("⚠️  No resources found in pkg/resources/")
+				return nil
+			}
+
+			if _, err := os.Stat("pkg/resources/register_generated.go"); os.IsNotExist(err) {
+				if err := generateRegistrationFile(debug); err != nil {
+					return fmt.Errorf("failed to generate registration file: %w", err)
+				}
+			}
+
+			fmt.Println("🔍 Checking schema compatibility...")
+			if err := generateCodeWithRunner(modulePath, ".", "compat", false, false, false, false, debug, false); err != nil {
+				return fmt.Errorf("compatibility check failed: %w", err)
+			}
+			fmt.Println("✅ Schema versions are compatible")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&debug, "debug", false, "Show detailed debug output")
+
+	return cmd
+}
+
+// discoverSchemaVersions scans pkg/resources for types marked with
+// +fabrica:version and matches each to the resource of its package
+func discoverSchemaVersions() ([]schemaVersionType, error) {
+	resourcesDir := "pkg/resources"
+	if _, err := os.Stat(resourcesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var versions []schemaVersionType
+	owners := make(map[string][]string) // package directory -> resources
+
+	err := filepath.Walk(resourcesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil // Skip files that don't parse
+		}
+
+		dir, _ := filepath.Rel(resourcesDir, filepath.Dir(path))
+		dir = filepath.ToSlash(dir)
+		markers := versionMarkers(node)
+
+		for _, decl := range node.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if v, ok := markers[typeSpec]; ok {
+					v.Package = node.Name.Name
+					v.Dir = dir
+					versions = append(versions, v)
+				} else if embedsResource(typeSpec) {
+					owners[dir] = append(owners[dir], typeSpec.Name.Name)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range versions {
+		owner := owners[v.Dir]
+		if len(owner) == 0 && strings.Contains(v.Dir, "/") {
+			// Version packages, such as device/v2 of fabrica add version,
+			// belong to the resource of their parent package
+			owner = owners[path.Dir(v.Dir)]
+		}
+		switch len(owner) {
+		case 1:
+			versions[i].Resource = owner[0]
+		case 0:
+			return nil, fmt.Errorf("%s: schema version type %s has no resource in its package", v.Dir, v.Type)
+		default:
+			return nil, fmt.Errorf("%s: schema version type %s is ambiguous, the package declares %s", v.Dir, v.Type, strings.Join(owner, ", "))
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Resource < versions[j].Resource
+	})
+	return versions, nil
+}
+
+// versionMarkers returns the types of a file whose doc comment has a
+// +fabrica:version marker, with the version and acknowledged breaking
+// changes filled in
+func versionMarkers(file *ast.File) map[*ast.TypeSpec]schemaVersionType {
+	markers := make(map[*ast.TypeSpec]schemaVersionType)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			doc := typeSpec.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			if doc == nil {
+				continue
+			}
+
+			v := schemaVersionType{Type: typeSpec.Name.Name}
+			for _, c := range doc.List {
+				text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+				if value, ok := strings.CutPrefix(text, "+fabrica:version="); ok {
+					v.Version = strings.TrimSpace(value)
+				}
+				if value, ok := strings.CutPrefix(text, "+fabrica:breaking="); ok {
+					for _, field := range strings.Split(value, ",") {
+						if field = strings.TrimSpace(field); field != "" {
+							v.Breaking = append(v.Breaking, field)
+						}
+					}
+				}
+			}
+			if v.Version != "" {
+				markers[typeSpec] = v
+			}
+		}
+	}
+	return markers
+}
+
+// embedsResource reports whether a type is a struct embedding
+// resource.Resource
+func embedsResource(typeSpec *ast.TypeSpec) bool {
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return false
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) != 0 {
+			continue
+		}
+		if sel, ok := field.Type.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "resource" && sel.Sel.Name == "Resource" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaVersionRegistrations returns the imports and runner code that
+// register the discovered schema version types with the generator
+func schemaVersionRegistrations(modulePath string, versions []schemaVersionType) (string, string) {
+	var imports, calls strings.Builder
+	imported := make(map[string]bool)
+
+	for _, v := range versions {
+		// Version packages of different resources share names such as v2,
+		// so nested packages are imported as e.g. devicev2
+		name := v.Package
+		if strings.Contains(v.Dir, "/") {
+			name = strings.NewReplacer("/", "", "-", "", ".", "").Replace(v.Dir)
+		}
+		if !imported[v.Dir] {
+			imported[v.Dir] = true
+			if name == v.Package {
+				imports.WriteString(fmt.Sprintf("\t\"%s/pkg/resources/%s\"\n", modulePath, v.Dir))
+			} else {
+				imports.WriteString(fmt.Sprintf("\t%s \"%s/pkg/resources/%s\"\n", name, modulePath, v.Dir))
+			}
+		}
+		args := []string{fmt.Sprintf("%q", v.Resource), fmt.Sprintf("%q", v.Version), fmt.Sprintf("&%s.%s{}", name, v.Type)}
+		for _, field := range v.Breaking {
+			args = append(args, fmt.Sprintf("%q", field))
+		}
+		calls.WriteString(fmt.Sprintf("\tif err := gen.RegisterResourceVersion(%s); err != nil {\n", strings.Join(args, ", ")))
+		calls.WriteString("\t\tlog.Fatalf(\"Failed to register schema version: %v\", err)\n")
+		calls.WriteString("\t}\n")
+	}
+	if calls.Len() > 0 {
+		calls.WriteString("\n")
+	}
+	return imports.String(), calls.String()
+}
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/openchami/fabrica/pkg/codegen"
+	"github.com/openchami/fabrica/pkg/versioning"
 	"gopkg.in/yaml.v3"
 )
 
@@ -38,9 +40,35 @@ type FeaturesConfig struct {
 	Conditional    ConditionalConfig    `yaml:"conditional"`
 	Versioning     VersioningConfig     `yaml:"versioning"`
 	Auth           AuthConfig           `yaml:"auth"`
+	RBAC           RBACConfig           `yaml:"rbac,omitempty"`
+	APIKeys        APIKeysConfig        `yaml:"api_keys,omitempty"`
+	Namespaces     NamespacesConfig     `yaml:"namespaces,omitempty"`
 	Storage        StorageConfig        `yaml:"storage"`
 	Metrics        MetricsConfig        `yaml:"metrics,omitempty"`
 	Reconciliation ReconciliationConfig `yaml:"reconciliation,omitempty"`
+	Quota          QuotaConfig          `yaml:"quota,omitempty"`
+	Revisions      RevisionsConfig      `yaml:"revisions,omitempty"`
+	EventLog       EventLogConfig       `yaml:"event_log,omitempty"`
+	Admission      AdmissionConfig      `yaml:"admission,omitempty"`
+	ReferenceCheck ReferenceCheckConfig `yaml:"reference_check,omitempty"`
+	Locking        LockingConfig        `yaml:"locking,omitempty"`
+	Encryption     EncryptionConfig     `yaml:"encryption,omitempty"`
+	I18n           I18nConfig           `yaml:"i18n,omitempty"`
+	Blobs          BlobsConfig          `yaml:"blobs,omitempty"`
+	Cache          CacheConfig          `yaml:"cache,omitempty"`
+	Import         ImportConfig         `yaml:"import,omitempty"`
+	Backup         BackupConfig         `yaml:"backup,omitempty"`
+	Webhooks       WebhooksConfig       `yaml:"webhooks,omitempty"`
+	Protobuf       ProtobufConfig       `yaml:"protobuf,omitempty"`
+	CBOR           CBORConfig           `yaml:"cbor,omitempty"`
+	Streaming      StreamingConfig      `yaml:"streaming,omitempty"`
+	Compression    CompressionConfig    `yaml:"compression,omitempty"`
+	CORS           CORSConfig           `yaml:"cors,omitempty"`
+	Limits         LimitsConfig         `yaml:"limits,omitempty"`
+	CRDs           CRDsConfig           `yaml:"crds,omitempty"`
+	Pagination     PaginationConfig     `yaml:"pagination,omitempty"`
+	Docs           DocsConfig           `yaml:"docs,omitempty"`
+	Watch          WatchConfig          `yaml:"watch,omitempty"`
 }
 
 // ValidationConfig controls validation behavior.
@@ -52,39 +80,87 @@ type ValidationConfig struct {
 // EventsConfig controls CloudEvents integration.
 type EventsConfig struct {
 	Enabled bool   `yaml:"enabled"`
-	BusType string `yaml:"bus_type"` // memory, nats, kafka
+	BusType string `yaml:"bus_type"`         // memory, or a bus registered with events.RegisterBus
+	Outbox  bool   `yaml:"outbox,omitempty"` // Write events to an outbox table in the transaction of the change (sql, ent)
+
+	DeadLetter DeadLetterConfig `yaml:"dead_letter,omitempty"`
+}
+
+// DeadLetterConfig controls the dead-letter queue of failed event handlers
+// and reconciliations.
+type DeadLetterConfig struct {
+	Enabled     bool `yaml:"enabled"`                // Retry failed handlers and store what still fails for the /admin/dead-letters API
+	MaxAttempts int  `yaml:"max_attempts,omitempty"` // Attempts per handler or reconciliation, including the first (default: 5)
 }
 
 // ConditionalConfig controls ETag and conditional request handling.
 type ConditionalConfig struct {
 	Enabled       bool   `yaml:"enabled"`
-	ETagAlgorithm string `yaml:"etag_algorithm"` // sha256, md5
+	ETagAlgorithm string `yaml:"etag_algorithm"`          // sha256, md5
+	CacheControl  string `yaml:"cache_control,omitempty"` // Cache-Control of GET responses (default: "private, no-cache")
+	Weak          bool   `yaml:"weak,omitempty"`          // Weak ETags from metadata and spec, ignoring status
 }
 
 // VersioningConfig controls API versioning.
 type VersioningConfig struct {
-	Enabled        bool   `yaml:"enabled"`
-	Strategy       string `yaml:"strategy"`        // header, url, both
-	DefaultVersion string `yaml:"default_version"` // v1, v2, etc.
+	Enabled        bool     `yaml:"enabled"`
+	Strategy       string   `yaml:"strategy"`           // header, url, both
+	DefaultVersion string   `yaml:"default_version"`    // v1, v2, etc.
+	Versions       []string `yaml:"versions,omitempty"` // Versions served as /v1, /v2, ... route trees with the url and both strategies (default: default_version)
 }
 
 // AuthConfig controls authorization/authentication.
 type AuthConfig struct {
 	Enabled  bool   `yaml:"enabled"`
 	Provider string `yaml:"provider,omitempty"` // jwt, oauth2, custom
+	JWKSURL  string `yaml:"jwks_url,omitempty"` // JWKS of the token issuer, e.g. https://idp/.well-known/jwks.json
+	Issuer   string `yaml:"issuer,omitempty"`   // Required "iss" claim
+	Audience string `yaml:"audience,omitempty"` // Required "aud" entry
+}
+
+// RBACConfig controls role-based authorization of generated routes.
+type RBACConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	PolicyFile  string `yaml:"policy_file,omitempty"`  // YAML/JSON roles and bindings (default: predefined roles only)
+	RoleClaim   string `yaml:"role_claim,omitempty"`   // Token claim listing roles (default: roles)
+	GroupClaim  string `yaml:"group_claim,omitempty"`  // Token claim listing groups (default: groups)
+	Engine      string `yaml:"engine,omitempty"`       // policy (default) or opa
+	OPAURL      string `yaml:"opa_url,omitempty"`      // OPA server for the opa engine (default: http://localhost:8181)
+	OPADecision string `yaml:"opa_decision,omitempty"` // Decision path in OPA's data tree (default: fabrica/authz)
+}
+
+// APIKeysConfig controls API key authentication of machine-to-machine callers.
+type APIKeysConfig struct {
+	Enabled bool `yaml:"enabled"` // Accept X-API-Key headers and generate the /apikeys admin API (requires auth)
+}
+
+// NamespacesConfig controls namespace (tenant) scoping of resources.
+type NamespacesConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Claim   string `yaml:"claim,omitempty"` // Token claim listing accessible namespaces (default: namespaces)
 }
 
 // StorageConfig controls storage backend.
 type StorageConfig struct {
-	Enabled  bool   `yaml:"enabled"`
-	Type     string `yaml:"type"`                // file, ent
-	DBDriver string `yaml:"db_driver,omitempty"` // postgres, mysql, sqlite, sqlite3
+	Enabled     bool   `yaml:"enabled"`
+	Type        string `yaml:"type"`                  // file, ent, redis, s3, sql
+	DBDriver    string `yaml:"db_driver,omitempty"`   // postgres, mysql, sqlite, sqlite3
+	Compression string `yaml:"compression,omitempty"` // gzip or zstd: compress stored resources (file and ent)
+	Migrations  string `yaml:"migrations,omitempty"`  // auto (default) or versioned: Atlas migration files applied by the server's migrate command (ent)
+
+	SoftDelete SoftDeleteConfig `yaml:"soft_delete,omitempty"` // Keep deleted resources as soft-deleted rows until purged (ent)
+}
+
+// SoftDeleteConfig controls soft deletion in Ent storage.
+type SoftDeleteConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	RetentionSeconds int  `yaml:"retention_seconds,omitempty"` // How long deleted resources are kept before they are purged (default: 2592000)
 }
 
 // MetricsConfig controls metrics/observability.
 type MetricsConfig struct {
 	Enabled  bool   `yaml:"enabled"`
-	Provider string `yaml:"provider,omitempty"` // prometheus, datadog
+	Provider string `yaml:"provider,omitempty"` // prometheus (the only provider)
 }
 
 // ReconciliationConfig controls reconciliation framework.
@@ -94,6 +170,162 @@ type ReconciliationConfig struct {
 	RequeueDelay int  `yaml:"requeue_delay,omitempty"` // Default requeue delay in minutes (default: 5)
 }
 
+// QuotaConfig controls the resource quota subsystem.
+type QuotaConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// RevisionsConfig controls revision history and rollback.
+type RevisionsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Limit   int  `yaml:"limit,omitempty"` // Revisions kept per resource (default: 10)
+}
+
+// EventLogConfig controls per-resource event logs.
+type EventLogConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	TTLSeconds int  `yaml:"ttl_seconds,omitempty"` // How long events are kept (default: 86400)
+	Limit      int  `yaml:"limit,omitempty"`       // Events kept per resource (default: 100)
+}
+
+// AdmissionConfig controls admission hooks in create and update handlers.
+type AdmissionConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// ReferenceCheckConfig controls checks that referenced resources exist.
+type ReferenceCheckConfig struct {
+	Enabled bool `yaml:"enabled"` // Reject creates and updates whose references point to missing resources
+}
+
+// LockingConfig controls lease-based resource locks.
+type LockingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Enforce bool `yaml:"enforce,omitempty"` // Reject mutations of resources locked by another holder
+}
+
+// EncryptionConfig controls encryption at rest of stored resources.
+type EncryptionConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Mode         string `yaml:"mode,omitempty"`           // fields (default), envelope, or both
+	KeyEnv       string `yaml:"key_env,omitempty"`        // Env var with the base64 AES key (default: FABRICA_ENCRYPTION_KEY)
+	RedactInList bool   `yaml:"redact_in_list,omitempty"` // Redact sensitive fields in list responses
+}
+
+// I18nConfig controls localization of error and validation messages.
+type I18nConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	CatalogDir string `yaml:"catalog_dir,omitempty"` // Directory of <lang>.json message catalogs (default: i18n)
+}
+
+// BlobsConfig controls binary file attachments on resources.
+type BlobsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Backend string `yaml:"backend,omitempty"`  // file (default) or s3
+	Dir     string `yaml:"dir,omitempty"`      // Directory for the file backend (default: ./data/blobs)
+	MaxSize int64  `yaml:"max_size,omitempty"` // Largest accepted upload in bytes (default: 100 MiB)
+}
+
+// CacheConfig controls the response cache for GET requests.
+type CacheConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Backend    string `yaml:"backend,omitempty"`     // memory (default) or redis
+	TTLSeconds int    `yaml:"ttl_seconds,omitempty"` // How long a response is cached (default: 30)
+	MaxEntries int    `yaml:"max_entries,omitempty"` // Responses kept by the memory backend (default: 10000)
+}
+
+// ImportConfig controls the CSV import endpoints.
+type ImportConfig struct {
+	Enabled  bool  `yaml:"enabled"`
+	MaxRows  int   `yaml:"max_rows,omitempty"`  // Rows accepted in one import (default: 10000)
+	MaxBytes int64 `yaml:"max_bytes,omitempty"` // Import body size limit (default: 32 MiB)
+}
+
+// BackupConfig controls the export and import endpoints.
+type BackupConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// WebhooksConfig controls outbound webhooks on resource changes.
+type WebhooksConfig struct {
+	Enabled     bool `yaml:"enabled"`                // Push resource events to subscribed URLs and generate the /webhooks API (requires events)
+	Workers     int  `yaml:"workers,omitempty"`      // Concurrent deliveries (default: 4)
+	MaxAttempts int  `yaml:"max_attempts,omitempty"` // Attempts per delivery, including the first (default: 5)
+}
+
+// ProtobufConfig controls protobuf responses and .proto generation.
+type ProtobufConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Package string `yaml:"package,omitempty"` // Protobuf package (default: <project>.v1)
+}
+
+// CBORConfig controls CBOR requests and responses.
+type CBORConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// StreamingConfig controls streaming of list responses.
+type StreamingConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	FlushEvery int  `yaml:"flush_every,omitempty"` // Resources between flushes (default: 100)
+}
+
+// CompressionConfig controls gzip/zstd compression of responses.
+type CompressionConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	MinSize      int      `yaml:"min_size,omitempty"`      // Smallest body compressed, in bytes (default: 1024)
+	ContentTypes []string `yaml:"content_types,omitempty"` // Media types compressed (default: JSON, NDJSON, tar, YAML, CBOR and text)
+	Encodings    []string `yaml:"encodings,omitempty"`     // zstd and/or gzip, in order of preference (default: [zstd, gzip])
+	Level        int      `yaml:"level,omitempty"`         // Compression level (default: each encoding's default)
+}
+
+// CORSConfig controls Cross-Origin Resource Sharing for browser clients.
+type CORSConfig struct {
+	Enabled          bool     `yaml:"enabled"`
+	AllowedOrigins   []string `yaml:"allowed_origins,omitempty"`   // Origins allowed to call the API; "*" for any (overridable with FABRICA_CORS_ALLOWED_ORIGINS)
+	AllowedMethods   []string `yaml:"allowed_methods,omitempty"`   // Methods allowed in preflights (default: GET, HEAD, POST, PUT, PATCH, DELETE)
+	AllowedHeaders   []string `yaml:"allowed_headers,omitempty"`   // Request headers allowed in preflights (default: cors.DefaultHeaders)
+	ExposedHeaders   []string `yaml:"exposed_headers,omitempty"`   // Response headers pages may read (default: cors.DefaultExposedHeaders)
+	AllowCredentials bool     `yaml:"allow_credentials,omitempty"` // Allow cookies and credentialed requests
+	MaxAge           int      `yaml:"max_age,omitempty"`           // Seconds browsers may cache preflights (default: 600)
+}
+
+// LimitsConfig controls the request body size limit and timeout.
+type LimitsConfig struct {
+	Enabled     bool  `yaml:"enabled"`
+	MaxBodySize int64 `yaml:"max_body_size,omitempty"` // Largest request body in bytes (default: 10 MiB; overridable with FABRICA_MAX_BODY_SIZE)
+	Timeout     int   `yaml:"timeout,omitempty"`       // Seconds a request may take (default: 10; overridable with FABRICA_REQUEST_TIMEOUT)
+}
+
+// CRDsConfig controls Kubernetes CustomResourceDefinition generation.
+type CRDsConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Group      string `yaml:"group,omitempty"`      // API group (default: <project>.example.com)
+	Scope      string `yaml:"scope,omitempty"`      // Namespaced (default) or Cluster
+	Conversion bool   `yaml:"conversion,omitempty"` // Webhook conversion stubs in pkg/crdconversion/
+	Controller bool   `yaml:"controller,omitempty"` // controller-runtime adapter syncing custom resources to the server, in pkg/crdcontroller/
+}
+
+// PaginationConfig controls pagination of list endpoints.
+type PaginationConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Mode         string `yaml:"mode,omitempty"`          // offset (default) or cursor
+	DefaultLimit int    `yaml:"default_limit,omitempty"` // Page size without ?limit= (default: 100)
+	MaxLimit     int    `yaml:"max_limit,omitempty"`     // Largest page size (default: 1000)
+}
+
+// DocsConfig controls the API console served at /docs.
+type DocsConfig struct {
+	Enabled *bool  `yaml:"enabled,omitempty"` // Serve the console (default: true)
+	UI      string `yaml:"ui,omitempty"`      // swagger (default) or redoc
+}
+
+// WatchConfig controls the watch endpoints and the client informers built
+// on them.
+type WatchConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
 // GenerationConfig controls what gets generated.
 type GenerationConfig struct {
 	Handlers       bool `yaml:"handlers"`
@@ -103,6 +335,20 @@ type GenerationConfig struct {
 	Events         bool `yaml:"events"`
 	Middleware     bool `yaml:"middleware"`
 	Reconciliation bool `yaml:"reconciliation"`
+	Tests          bool `yaml:"tests,omitempty"`        // Handler and storage conformance test suites
+	FakeServer     bool `yaml:"fakeserver,omitempty"`   // In-process test server in pkg/fakeserver
+	ClientFake     bool `yaml:"clientfake,omitempty"`   // In-memory fake of the client in pkg/clientfake
+	LoadTest       bool `yaml:"loadtest,omitempty"`     // k6 load test scenarios in loadtest/
+	E2E            bool `yaml:"e2e,omitempty"`          // End-to-end tests in e2e/ (needs the client)
+	Integration    bool `yaml:"integration,omitempty"`  // Integration test suite of the server, with the integration build tag
+	Reproducible   bool `yaml:"reproducible,omitempty"` // Omit generation timestamps so regenerating unchanged inputs changes nothing
+	Deploy         bool `yaml:"deploy,omitempty"`       // Dockerfile and Kubernetes manifests in deploy/k8s/
+	Helm           bool `yaml:"helm,omitempty"`         // Helm chart in deploy/helm/ (with deploy)
+
+	BasePath string `yaml:"base_path,omitempty"` // Path all generated routes, OpenAPI paths, clients and the CLI share, e.g. /apis/inventory/v1
+	Router   string `yaml:"router,omitempty"`    // Router the chi route tree is mounted on: chi (default), or gorilla (gorilla/mux) or stdlib (net/http ServeMux) for an adapter
+
+	Plugins []string `yaml:"plugins,omitempty"` // Commands of generator plugins, run after the server code is generated
 }
 
 // LoadConfig reads .fabrica.yaml from the specified directory.
@@ -171,13 +417,32 @@ func ValidateConfig(config *FabricaConfig) error {
 		config.Features.Validation.Enabled = false
 	}
 
-	// Validate event bus type
-	if config.Features.Events.Enabled {
-		validBusTypes := map[string]bool{"memory": true, "nats": true, "kafka": true}
-		if !validBusTypes[config.Features.Events.BusType] {
-			return fmt.Errorf("invalid events.bus_type: %s (must be 'memory', 'nats', or 'kafka')",
-				config.Features.Events.BusType)
+	// Validate event bus type. Buses other than memory are registered with
+	// events.RegisterBus by packages the server imports, so only the name
+	// is checked here; the server checks it is registered at startup
+	if config.Features.Events.Enabled && config.Features.Events.BusType == "" {
+		return fmt.Errorf("events.bus_type must be set (e.g. 'memory' or a bus registered with events.RegisterBus)")
+	}
+
+	// Validate the event outbox
+	if config.Features.Events.Outbox {
+		if !config.Features.Events.Enabled {
+			return fmt.Errorf("events.outbox requires events (features.events.enabled)")
 		}
+		if config.Features.Storage.Type != "sql" && config.Features.Storage.Type != "ent" {
+			return fmt.Errorf("events.outbox requires storage.type 'sql' or 'ent', not '%s'",
+				config.Features.Storage.Type)
+		}
+	}
+
+	// Validate the dead-letter queue
+	if config.Features.Events.DeadLetter.Enabled && !config.Features.Events.Enabled {
+		return fmt.Errorf("events.dead_letter requires events (features.events.enabled)")
+	}
+
+	// Validate webhooks
+	if config.Features.Webhooks.Enabled && !config.Features.Events.Enabled {
+		return fmt.Errorf("webhooks require events (features.events.enabled)")
 	}
 
 	// Validate ETag algorithm
@@ -196,23 +461,116 @@ func ValidateConfig(config *FabricaConfig) error {
 			return fmt.Errorf("invalid versioning.strategy: %s (must be 'header', 'url', or 'both')",
 				config.Features.Versioning.Strategy)
 		}
+		seen := make(map[string]bool)
+		for _, version := range config.Features.Versioning.Versions {
+			if err := versioning.ValidateVersion(version); err != nil {
+				return fmt.Errorf("invalid versioning.versions: %w", err)
+			}
+			if seen[version] {
+				return fmt.Errorf("invalid versioning.versions: %s is listed twice", version)
+			}
+			seen[version] = true
+		}
+		if len(seen) > 0 && config.Features.Versioning.DefaultVersion != "" && !seen[config.Features.Versioning.DefaultVersion] {
+			return fmt.Errorf("versioning.versions must include the default_version %s", config.Features.Versioning.DefaultVersion)
+		}
+	}
+
+	// Validate compression encodings
+	if config.Features.Compression.Enabled {
+		validEncodings := map[string]bool{"zstd": true, "gzip": true}
+		for _, encoding := range config.Features.Compression.Encodings {
+			if !validEncodings[encoding] {
+				return fmt.Errorf("invalid compression.encodings: %s (must be 'zstd' or 'gzip')", encoding)
+			}
+		}
+	}
+
+	// Validate docs UI
+	if ui := config.Features.Docs.UI; ui != "" && ui != "swagger" && ui != "redoc" {
+		return fmt.Errorf("invalid docs.ui: %s (must be 'swagger' or 'redoc')", ui)
+	}
+
+	// Validate base path
+	if err := codegen.ValidateBasePath(config.Generation.BasePath); err != nil {
+		return fmt.Errorf("invalid generation.base_path: %w", err)
+	}
+
+	// Validate router
+	if router := config.Generation.Router; router != "" && router != "chi" && router != "gorilla" && router != "stdlib" {
+		return fmt.Errorf("invalid generation.router: %s (must be 'chi', 'gorilla' or 'stdlib')", router)
+	}
+
+	// Validate encryption mode
+	if config.Features.Encryption.Enabled {
+		validEncryptionModes := map[string]bool{"fields": true, "envelope": true, "both": true}
+		if config.Features.Encryption.Mode != "" && !validEncryptionModes[config.Features.Encryption.Mode] {
+			return fmt.Errorf("invalid encryption.mode: %s (must be 'fields', 'envelope', or 'both')",
+				config.Features.Encryption.Mode)
+		}
 	}
 
 	// Validate storage type
 	if config.Features.Storage.Enabled {
-		validTypes := map[string]bool{"file": true, "ent": true}
+		validTypes := map[string]bool{"file": true, "ent": true, "redis": true, "s3": true, "sql": true}
 		if !validTypes[config.Features.Storage.Type] {
-			return fmt.Errorf("invalid storage.type: %s (must be 'file' or 'ent')",
+			return fmt.Errorf("invalid storage.type: %s (must be 'file', 'ent', 'redis', 's3' or 'sql')",
 				config.Features.Storage.Type)
 		}
 
-		// Validate DB driver if using ent
-		if config.Features.Storage.Type == "ent" && config.Features.Storage.DBDriver != "" {
-			validDrivers := map[string]bool{"postgres": true, "mysql": true, "sqlite": true, "sqlite3": true}
+		// Validate DB driver if using a database
+		if (config.Features.Storage.Type == "ent" || config.Features.Storage.Type == "sql") && config.Features.Storage.DBDriver != "" {
+			validDrivers := map[string]bool{"postgres": true, "mysql": true, "sqlite": true, "sqlite3": true, "cockroach": true, "sqlserver": true}
 			if !validDrivers[config.Features.Storage.DBDriver] {
-				return fmt.Errorf("invalid storage.db_driver: %s (must be 'postgres', 'mysql', 'sqlite', or 'sqlite3')",
+				return fmt.Errorf("invalid storage.db_driver: %s (must be 'postgres', 'mysql', 'cockroach', 'sqlserver', 'sqlite', or 'sqlite3')",
 					config.Features.Storage.DBDriver)
 			}
+			// CockroachDB needs the serialization retries of the Ent storage
+			if config.Features.Storage.Type == "sql" && config.Features.Storage.DBDriver == "cockroach" {
+				return fmt.Errorf("storage.db_driver cockroach requires storage.type 'ent'")
+			}
+			// Ent has no SQL Server dialect
+			if config.Features.Storage.Type == "ent" && config.Features.Storage.DBDriver == "sqlserver" {
+				return fmt.Errorf("storage.db_driver sqlserver requires storage.type 'sql' (Ent does not support SQL Server)")
+			}
+		}
+
+		// Validate compression of stored resources
+		if config.Features.Storage.Compression != "" {
+			if config.Features.Storage.Compression != "gzip" && config.Features.Storage.Compression != "zstd" {
+				return fmt.Errorf("invalid storage.compression: %s (must be 'gzip' or 'zstd')",
+					config.Features.Storage.Compression)
+			}
+			if config.Features.Storage.Type != "file" && config.Features.Storage.Type != "ent" {
+				return fmt.Errorf("storage.compression is only supported with 'file' and 'ent' storage, not '%s'",
+					config.Features.Storage.Type)
+			}
+		}
+
+		// Validate schema migrations
+		if config.Features.Storage.Migrations != "" {
+			if config.Features.Storage.Migrations != "auto" && config.Features.Storage.Migrations != "versioned" {
+				return fmt.Errorf("invalid storage.migrations: %s (must be 'auto' or 'versioned')",
+					config.Features.Storage.Migrations)
+			}
+			if config.Features.Storage.Migrations == "versioned" && config.Features.Storage.Type != "ent" {
+				return fmt.Errorf("storage.migrations 'versioned' requires storage.type 'ent', not '%s'",
+					config.Features.Storage.Type)
+			}
+		}
+
+		// Validate soft deletion
+		if config.Features.Storage.SoftDelete.Enabled && config.Features.Storage.Type != "ent" {
+			return fmt.Errorf("storage.soft_delete requires storage.type 'ent', not '%s'",
+				config.Features.Storage.Type)
+		}
+		// Ent storage can't be watched
+		if config.Features.Watch.Enabled && config.Features.Storage.Type == "ent" {
+			return fmt.Errorf("watch requires storage.type 'file', 'redis', 's3' or 'sql', not 'ent'")
+		}
+		if config.Features.Storage.SoftDelete.RetentionSeconds < 0 {
+			return fmt.Errorf("invalid storage.soft_delete.retention_seconds: %d (must not be negative)",
+				config.Features.Storage.SoftDelete.RetentionSeconds)
 		}
 	}
 
@@ -263,6 +621,7 @@ func NewDefaultConfig(name, module string) *FabricaConfig {
 			OpenAPI:    true,
 			Events:     false,
 			Middleware: true,
+			Tests:      true,
 		},
 	}
 }
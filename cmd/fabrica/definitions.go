@@ -0,0 +1,158 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/codegen"
+	"gopkg.in/yaml.v3"
+)
+
+// definitionHeader starts the Go files generated from resource definitions
+const definitionHeader = "// Code generated by fabrica from "
+
+// definitionTypesPath returns the Go file of the types of a defined kind,
+// where the generator looks for its markers
+func definitionTypesPath(kind string) string {
+	pkg := strings.ToLower(kind)
+	return filepath.Join("pkg", "resources", pkg, pkg+".go")
+}
+
+// discoverDefinitions returns the YAML files in pkg/resources whose kind is
+// ResourceDefinition; other YAML files are left alone
+func discoverDefinitions() ([]string, error) {
+	resourcesDir := "pkg/resources"
+	if _, err := os.Stat(resourcesDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var paths []string
+	err := filepath.Walk(resourcesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || (!strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml")) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var manifest struct {
+			Kind string `yaml:"kind"`
+		}
+		if yaml.Unmarshal(data, &manifest) == nil && manifest.Kind == codegen.ResourceDefinitionKind {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// generateDefinitionTypes writes the Go types of the resource definitions
+// in pkg/resources, so the rest of generation finds them as if written by
+// hand. In a dry run, it prints how they would change instead.
+func generateDefinitionTypes(modulePath string, dryRun, debug bool) error {
+	paths, err := discoverDefinitions()
+	if err != nil {
+		return fmt.Errorf("failed to discover resource definitions: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	fmt.Printf("📄 Generating types of %d resource definition(s)...\n", len(paths))
+	gen := codegen.NewGenerator("pkg/resources", "resources", modulePath)
+	gen.DryRun = dryRun
+	kinds := make(map[string]string) // kind -> definition
+	for _, path := range paths {
+		if debug {
+			fmt.Printf("  Reading %s\n", path)
+		}
+		def, err := codegen.LoadResourceDefinition(path)
+		if err != nil {
+			return err
+		}
+		kind := def.Spec.Names.Kind
+		if other, ok := kinds[kind]; ok {
+			return fmt.Errorf("%s and %s both define %s", other, path, kind)
+		}
+		kinds[kind] = path
+
+		target := definitionTypesPath(kind)
+		if existing, err := os.ReadFile(target); err == nil && !bytes.HasPrefix(existing, []byte(definitionHeader)) {
+			return fmt.Errorf("%s defines %s, but %s wasn't generated from a definition; remove one of them", path, kind, target)
+		}
+		src, err := def.GoSource(filepath.ToSlash(path))
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := gen.WriteArtifact(target, src); err != nil {
+			return err
+		}
+	}
+
+	if dryRun && len(gen.Changes()) > 0 {
+		fmt.Println("  Generation below uses the types as they are on disk")
+		return gen.WriteChanges(os.Stdout)
+	}
+	return nil
+}
+
+// writeResourceDefinition writes the starting definition of a resource
+// and its Go types, for 'fabrica add resource --yaml'
+func writeResourceDefinition(resourceName string, opts *addOptions) (string, error) {
+	pkg := strings.ToLower(resourceName)
+	if opts.packageName != pkg {
+		return "", fmt.Errorf("the package of a defined resource is its lowercase name, %s", pkg)
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, `# Definition of the %[1]s resource. 'fabrica generate' writes its Go types
+# to %[2]s.go; add methods, such as Validate, in other files of the package.
+apiVersion: fabrica.openchami.io/v1
+kind: ResourceDefinition
+spec:
+  names:
+    kind: %[1]s
+`, resourceName, pkg)
+	if opts.withVersioning {
+		content.WriteString("  versioning: true\n")
+	}
+	content.WriteString("  spec:\n    - name: description\n      type: string\n")
+	if opts.withValidation {
+		content.WriteString("      validate: max=200\n")
+	}
+	content.WriteString("    # Add your spec fields here\n")
+	content.WriteString("  # status defaults to phase, message, ready and conditions\n")
+
+	definitionPath := filepath.Join("pkg", "resources", pkg, pkg+".yaml")
+	if _, err := os.Stat(definitionPath); err == nil {
+		return "", fmt.Errorf("%s already exists", definitionPath)
+	}
+	if _, err := os.Stat(definitionTypesPath(resourceName)); err == nil {
+		return "", fmt.Errorf("%s already exists", definitionTypesPath(resourceName))
+	}
+	def, err := codegen.ParseResourceDefinition([]byte(content.String()))
+	if err != nil {
+		return "", err
+	}
+	src, err := def.GoSource(filepath.ToSlash(definitionPath))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(definitionPath, []byte(content.String()), 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(definitionTypesPath(resourceName), src, 0644); err != nil {
+		return "", err
+	}
+	return definitionPath, nil
+}
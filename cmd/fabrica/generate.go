@@ -5,6 +5,7 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -40,9 +41,12 @@ func newGenerateCommand() *cobra.Command {
 		storage  bool
 		client   bool
 		openapi  bool
+		crds     bool
+		deploy   bool
 		all      bool
 		debug    bool
 		force    bool
+		dryRun   bool
 	)
 
 	cmd := &cobra.Command{
@@ -55,13 +59,20 @@ Examples:
   fabrica generate                    # Generate all
   fabrica generate --handlers         # Just handlers
   fabrica generate --client --openapi # Client + OpenAPI
+  fabrica generate --crds             # Kubernetes CRDs in deploy/crds/
+  fabrica generate --deploy           # Dockerfile and Kubernetes manifests
+  fabrica generate --dry-run          # Show what would change, write nothing
 `,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			if !handlers && !storage && !client && !openapi {
+			if !handlers && !storage && !client && !openapi && !crds && !deploy {
 				all = true
 			}
 
-			fmt.Println("🔧 Generating code...")
+			if dryRun {
+				fmt.Println("🔧 Generating code (dry run)...")
+			} else {
+				fmt.Println("🔧 Generating code...")
+			}
 
 			// Read go.mod to get module path
 			if debug {
@@ -75,6 +86,11 @@ Examples:
 				fmt.Printf("  Module: %s\n", modulePath)
 			}
 
+			// Resources defined in YAML get their Go types first
+			if err := generateDefinitionTypes(modulePath, dryRun, debug); err != nil {
+				return err
+			}
+
 			// Discover resources in pkg/resources
 			if debug {
 				fmt.Println("🔍 Discovering resources in pkg/resources/...")
@@ -117,6 +133,9 @@ Examples:
 			}
 
 			// Auto-generate registration file if missing
+			if needsRegistration && dryRun {
+				return fmt.Errorf("%s not found: run 'fabrica generate' once before a dry run", regFile)
+			}
 			if needsRegistration {
 				fmt.Println()
 				fmt.Println("📝 Registration file not found, creating it...")
@@ -138,31 +157,60 @@ Examples:
 				if debug {
 					fmt.Println("📦 Generating server code...")
 				}
-				if err := generateCodeWithRunner(modulePath, "cmd/server", "main", all || handlers, all || storage, all || openapi, false, debug); err != nil {
+				if err := generateCodeWithRunner(modulePath, "cmd/server", "main", all || handlers, all || storage, all || openapi, false, debug, dryRun); err != nil {
 					return fmt.Errorf("failed to generate server code: %w", err)
 				}
 			}
 
+			// Generate Kubernetes CRDs on their own (with all, they follow features.crds)
+			if crds && !all {
+				if err := generateCodeWithRunner(modulePath, "deploy/crds", "crds", false, false, false, false, debug, dryRun); err != nil {
+					return fmt.Errorf("failed to generate CRDs: %w", err)
+				}
+			}
+
+			// Generate deployment artifacts on their own (with all, they follow generation.deploy)
+			if deploy && !all {
+				if err := generateCodeWithRunner(modulePath, "deploy", "deploy", false, false, false, false, debug, dryRun); err != nil {
+					return fmt.Errorf("failed to generate deployment artifacts: %w", err)
+				}
+			}
+
 			// Generate client code
 			if all || client {
 				fmt.Println("📦 Generating client code...")
-				if err := generateCodeWithRunner(modulePath, "pkg/client", "client", false, false, false, true, debug); err != nil {
+				if err := generateCodeWithRunner(modulePath, "pkg/client", "client", false, false, false, true, debug, dryRun); err != nil {
 					return fmt.Errorf("failed to generate client code: %w", err)
 				}
 			}
 
 			// Check if reconciliation is enabled in config
 			config, err := readFabricaConfig()
+
+			// Generate the in-process fake server for client tests
+			if err == nil && config != nil && config.Generation.FakeServer && (all || handlers) {
+				if err := generateCodeWithRunner(modulePath, "pkg/fakeserver", "fakeserver", false, false, false, false, debug, dryRun); err != nil {
+					return fmt.Errorf("failed to generate fake server: %w", err)
+				}
+			}
+			// Generate the in-memory fake of the client for consumers' unit tests
+			if err == nil && config != nil && config.Generation.ClientFake && (all || client) {
+				if err := generateCodeWithRunner(modulePath, "pkg/clientfake", "clientfake", false, false, false, false, debug, dryRun); err != nil {
+					return fmt.Errorf("failed to generate client fake: %w", err)
+				}
+			}
 			if err == nil && config != nil && config.Features.Reconciliation.Enabled {
 				fmt.Println("🔄 Generating reconciliation code...")
-				if err := generateCodeWithRunner(modulePath, "pkg/reconcilers", "reconcile", false, false, false, false, debug); err != nil {
+				if err := generateCodeWithRunner(modulePath, "pkg/reconcilers", "reconcile", false, false, false, false, debug, dryRun); err != nil {
 					return fmt.Errorf("failed to generate reconciliation code: %w", err)
 				}
 			}
 
 			// Auto-generate Ent client code if using Ent storage
 			storageType := detectStorageType()
-			if storageType == "ent" && (all || storage) {
+			if storageType == "ent" && (all || storage) && dryRun {
+				fmt.Println("ℹ️  Skipping Ent client code, which 'go generate' writes")
+			} else if storageType == "ent" && (all || storage) {
 				fmt.Println("🔄 Generating Ent client code...")
 
 				if err := generateEntCode(debug); err != nil {
@@ -174,8 +222,23 @@ Examples:
 				}
 			}
 
+			// Write the OpenAPI document as a standalone artifact
+			if (all || openapi) && dryRun {
+				fmt.Println("ℹ️  Skipping api/openapi.json and api/openapi.yaml, which the built server writes")
+			} else if all || openapi {
+				fmt.Println("📄 Writing api/openapi.json and api/openapi.yaml...")
+				if err := writeOpenAPIArtifacts(debug); err != nil {
+					fmt.Printf("⚠️  Could not write the OpenAPI document: %v\n", err)
+					fmt.Println("   Run 'go run ./cmd/server openapi' once the server builds (e.g. after 'go mod tidy')")
+				}
+			}
+
 			fmt.Println("  └─ Done!")
 			fmt.Println()
+			if dryRun {
+				fmt.Println("✅ Dry run complete, nothing was written")
+				return nil
+			}
 			fmt.Println("✅ Code generation complete!")
 			fmt.Println()
 			fmt.Println("Next steps:")
@@ -190,8 +253,11 @@ Examples:
 	cmd.Flags().BoolVar(&storage, "storage", false, "Generate storage adapters")
 	cmd.Flags().BoolVar(&client, "client", false, "Generate client code")
 	cmd.Flags().BoolVar(&openapi, "openapi", false, "Generate OpenAPI spec")
+	cmd.Flags().BoolVar(&crds, "crds", false, "Generate Kubernetes CustomResourceDefinitions")
+	cmd.Flags().BoolVar(&deploy, "deploy", false, "Generate a Dockerfile, Kubernetes manifests and, with generation.helm, a Helm chart")
 	cmd.Flags().BoolVar(&debug, "debug", false, "Enable debug output showing detailed generation steps")
 	cmd.Flags().BoolVar(&force, "force", false, "Force regeneration even with version warnings")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the files generation would change, without writing them")
 
 	return cmd
 }
@@ -220,10 +286,8 @@ func detectStorageType() string {
 	// First, check .fabrica.yaml configuration
 	if config, err := readFabricaConfig(); err == nil && config != nil {
 		switch config.Features.Storage.Type {
-		case "ent":
-			return "ent"
-		case "file":
-			return "file"
+		case "ent", "file", "redis", "s3", "sql":
+			return config.Features.Storage.Type
 		}
 	}
 
@@ -241,14 +305,18 @@ func detectStorageType() string {
 	return "file"
 }
 
-// generateCodeWithRunner creates and runs a temporary codegen program
-func generateCodeWithRunner(modulePath, outputDir, packageName string, handlers, storage, openapi, client, debug bool) error {
+// generateCodeWithRunner creates and runs a temporary codegen program.
+// In a dry run, the program prints how the generated files differ from
+// those on disk instead of writing them.
+func generateCodeWithRunner(modulePath, outputDir, packageName string, handlers, storage, openapi, client, debug, dryRun bool) error {
 	// Create output directory if it doesn't exist
-	if debug {
-		fmt.Printf("  Creating output directory: %s\n", outputDir)
-	}
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	if !dryRun {
+		if debug {
+			fmt.Printf("  Creating output directory: %s\n", outputDir)
+		}
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
 	}
 
 	// Create runner in the project's cmd directory to have access to go.mod
@@ -269,7 +337,14 @@ func generateCodeWithRunner(modulePath, outputDir, packageName string, handlers,
 		fmt.Printf("  Detected storage type: %s\n", storageType)
 	}
 
-	runnerCode := generateRunnerCode(modulePath, outputDir, packageName, handlers, storage, openapi, client, debug, storageType)
+	// Schema version types are registered after the resources so their
+	// spec fields can be checked for breaking changes
+	versions, err := discoverSchemaVersions()
+	if err != nil {
+		return fmt.Errorf("failed to discover schema versions: %w", err)
+	}
+
+	runnerCode := generateRunnerCode(modulePath, outputDir, packageName, handlers, storage, openapi, client, debug, dryRun, storageType, versions)
 
 	runnerPath := filepath.Join(runnerDir, "main.go")
 	if err := os.WriteFile(runnerPath, []byte(runnerCode), 0644); err != nil {
@@ -280,6 +355,15 @@ func generateCodeWithRunner(modulePath, outputDir, packageName string, handlers,
 	if debug {
 		fmt.Println("  Running code generator...")
 	}
+	// A dry run leaves go.mod and go.sum as it found them
+	if dryRun {
+		for _, name := range []string{"go.mod", "go.sum"} {
+			if data, err := os.ReadFile(name); err == nil {
+				defer os.WriteFile(name, data, 0644) // nolint:errcheck
+			}
+		}
+	}
+
 	// Use relative path starting with ./ so go run uses the project's go.mod and replace directives
 	// Use -mod=mod to allow go.mod updates during code generation (needed for Ent and other generators)
 	cmd := exec.Command("go", "run", "-mod=mod", "./"+runnerDir)
@@ -295,7 +379,7 @@ func generateCodeWithRunner(modulePath, outputDir, packageName string, handlers,
 }
 
 // generateRunnerCode creates the source code for the temporary codegen runner
-func generateRunnerCode(modulePath, outputDir, packageName string, handlers, storage, openapi, client, debug bool, storageType string) string {
+func generateRunnerCode(modulePath, outputDir, packageName string, handlers, storage, openapi, client, debug, dryRun bool, storageType string, versions []schemaVersionType) string {
 	var generationCalls strings.Builder
 
 	if packageName == "main" {
@@ -307,10 +391,116 @@ func generateRunnerCode(modulePath, outputDir, packageName string, handlers, sto
 		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to load templates: %v\", err)\n")
 		generationCalls.WriteString("\t}\n")
 
+		// Plugins run once per generation, with the server
+		generationCalls.WriteString("\tif config, err := loadConfig(); err == nil {\n")
+		generationCalls.WriteString("\t\tfor _, plugin := range config.Generation.Plugins {\n")
+		generationCalls.WriteString("\t\t\tgen.RegisterPlugin(plugin)\n")
+		generationCalls.WriteString("\t\t}\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tif err := gen.RunHooks(codegen.HookBeforeGenerate); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to run hooks: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+
 		if handlers {
 			generationCalls.WriteString("\tif err := gen.GenerateHandlers(); err != nil {\n")
 			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate handlers: %v\", err)\n")
 			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateHandlerTests(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate handler tests: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateIntegrationTests(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate integration tests: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateQuota(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate quota API: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateRevisions(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate revision helpers: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateEventLog(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate event log helpers: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateAdmission(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate admission hooks: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateAuth(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate authentication middleware: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateRBAC(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate RBAC middleware: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateAPIKeys(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate API key authentication: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateNamespaces(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate namespace scoping: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateLocks(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate lock helpers: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateWatch(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate watch endpoints: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateExpiry(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate expiry sweeper: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateSoftDelete(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate soft delete purger: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateI18n(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate localization setup: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateBlobs(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate file attachment helpers: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateCache(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate response cache: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateCORS(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate CORS middleware: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateLimits(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate request limits: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateGraph(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate graph endpoint: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateImport(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate CSV import endpoints: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateBackup(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate backup endpoints: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateWebhooks(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate webhooks: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateDeadLetters(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate dead-letter queue: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateOutbox(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate event outbox relay: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateMigrate(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate migrate command: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateProto(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate protobuf definitions: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateLoadTests(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate load tests: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateE2ETests(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate end-to-end tests: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateCRDs(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate CRDs: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateDeploy(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate deployment artifacts: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
+			generationCalls.WriteString("\tif err := gen.GenerateCompose(); err != nil {\n")
+			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate docker-compose.yaml: %v\", err)\n")
+			generationCalls.WriteString("\t}\n")
 			// Always generate middleware when generating handlers
 			generationCalls.WriteString("\tif err := gen.GenerateMiddleware(); err != nil {\n")
 			generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate middleware: %v\", err)\n")
@@ -345,6 +535,10 @@ func generateRunnerCode(modulePath, outputDir, packageName string, handlers, sto
 		generationCalls.WriteString("\tif err := gen.GenerateModels(); err != nil {\n")
 		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate models: %v\", err)\n")
 		generationCalls.WriteString("\t}\n")
+
+		generationCalls.WriteString("\tif err := gen.RunHooks(codegen.HookAfterGenerate); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to run hooks: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
 	} else if client {
 		// Client-side generation
 		if debug {
@@ -368,12 +562,47 @@ func generateRunnerCode(modulePath, outputDir, packageName string, handlers, sto
 		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate client models: %v\", err)\n")
 		generationCalls.WriteString("\t}\n")
 
+		if debug {
+			generationCalls.WriteString("\tfmt.Println(\"  Generating client informers...\")\n")
+		}
+		generationCalls.WriteString("\tif err := gen.GenerateClientInformers(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate client informers: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+
 		if debug {
 			generationCalls.WriteString("\tfmt.Println(\"  Generating client CLI...\")\n")
 		}
 		generationCalls.WriteString("\tif err := gen.GenerateClientCmd(); err != nil {\n")
 		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate client CLI: %v\", err)\n")
 		generationCalls.WriteString("\t}\n")
+	} else if packageName == "crds" {
+		// Kubernetes CRDs only (fabrica generate --crds)
+		generationCalls.WriteString("\tif err := gen.LoadTemplates(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to load templates: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tgen.Config.CRDsEnabled = true\n")
+		generationCalls.WriteString("\tif err := gen.GenerateCRDs(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate CRDs: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+	} else if packageName == "deploy" {
+		// Deployment artifacts only (fabrica generate --deploy)
+		generationCalls.WriteString("\tif err := gen.LoadTemplates(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to load templates: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tgen.Config.DeployEnabled = true\n")
+		generationCalls.WriteString("\tif err := gen.GenerateDeploy(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate deployment artifacts: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+	} else if packageName == "clientfake" {
+		// Client fake generation (fabrica generate with generation.clientfake)
+		generationCalls.WriteString("\tif err := gen.GenerateAll(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate client fake: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+	} else if packageName == "fakeserver" {
+		// Fake server generation reuses the server templates
+		generationCalls.WriteString("\tif err := gen.GenerateAll(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate fake server: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
 	} else if packageName == "reconcile" {
 		// Reconciliation code generation
 		if debug {
@@ -403,12 +632,71 @@ func generateRunnerCode(modulePath, outputDir, packageName string, handlers, sto
 		generationCalls.WriteString("\tif err := gen.GenerateEventHandlers(); err != nil {\n")
 		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate event handlers: %v\", err)\n")
 		generationCalls.WriteString("\t}\n")
+
+		if debug {
+			generationCalls.WriteString("\tfmt.Println(\"  Generating reconciler test harness...\")\n")
+		}
+		generationCalls.WriteString("\tif err := gen.GenerateReconcilerHarness(); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to generate reconciler test harness: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+	} else if packageName == "compat" {
+		// Schema compatibility check only (fabrica check-compat)
+		generationCalls.WriteString("\tissues := gen.CheckCompatibility()\n")
+		generationCalls.WriteString("\tbreaking := false\n")
+		generationCalls.WriteString("\tfor _, issue := range issues {\n")
+		generationCalls.WriteString("\t\tmark := \"✅\"\n")
+		generationCalls.WriteString("\t\tif !issue.Acknowledged {\n")
+		generationCalls.WriteString("\t\t\tmark, breaking = \"❌\", true\n")
+		generationCalls.WriteString("\t\t}\n")
+		generationCalls.WriteString("\t\tfmt.Printf(\"  %s %s\\n\", mark, issue)\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tif len(issues) == 0 {\n")
+		generationCalls.WriteString("\t\tfmt.Println(\"  No breaking changes between schema versions\")\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tif breaking {\n")
+		generationCalls.WriteString("\t\tfmt.Println(\"  Acknowledge intentional changes with +fabrica:breaking=<field> on the later version\")\n")
+		generationCalls.WriteString("\t\tos.Exit(1)\n")
+		generationCalls.WriteString("\t}\n")
+	} else if packageName == "validate" {
+		// Resource checks only (fabrica validate)
+		generationCalls.WriteString("\tfailed := false\n")
+		generationCalls.WriteString("\tfor _, issue := range gen.CheckResources() {\n")
+		generationCalls.WriteString("\t\tmark := \"❌\"\n")
+		generationCalls.WriteString("\t\tif issue.Warning {\n")
+		generationCalls.WriteString("\t\t\tmark = \"⚠️ \"\n")
+		generationCalls.WriteString("\t\t} else {\n")
+		generationCalls.WriteString("\t\t\tfailed = true\n")
+		generationCalls.WriteString("\t\t}\n")
+		generationCalls.WriteString("\t\tfmt.Printf(\"  %s %s\\n     → %s\\n\", mark, issue, issue.Fix)\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tfor _, issue := range gen.CheckCompatibility() {\n")
+		generationCalls.WriteString("\t\tif !issue.Acknowledged {\n")
+		generationCalls.WriteString("\t\t\tfailed = true\n")
+		generationCalls.WriteString("\t\t\tfmt.Printf(\"  ❌ %s\\n     → acknowledge intentional changes with +fabrica:breaking=<field> on the later version\\n\", issue)\n")
+		generationCalls.WriteString("\t\t}\n")
+		generationCalls.WriteString("\t}\n")
+		generationCalls.WriteString("\tif failed {\n")
+		generationCalls.WriteString("\t\tos.Exit(1)\n")
+		generationCalls.WriteString("\t}\n")
 	}
 
-	verboseFlag := "false"
+	if dryRun && packageName != "compat" && packageName != "validate" {
+		generationCalls.WriteString("\tif err := gen.WriteChanges(os.Stdout); err != nil {\n")
+		generationCalls.WriteString("\t\tlog.Fatalf(\"Failed to print changes: %v\", err)\n")
+		generationCalls.WriteString("\t}\n")
+	}
+
+	versionImports, versionCalls := schemaVersionRegistrations(modulePath, versions)
+
+	verboseFlag, dryRunFlag := "false", "false"
 	fmtImport := ""
 	if debug {
 		verboseFlag = "true"
+	}
+	if dryRun {
+		dryRunFlag = "true"
+	}
+	if debug || packageName == "compat" || packageName == "validate" {
 		fmtImport = "\t\"fmt\"\n"
 	}
 
@@ -420,20 +708,62 @@ import (
 
 	"github.com/openchami/fabrica/pkg/codegen"
 	"%s/pkg/resources"
-	"gopkg.in/yaml.v3"
+%s	"gopkg.in/yaml.v3"
 )
 
 // FabricaConfig structures to load .fabrica.yaml
 type FabricaConfig struct {
-	Features FeaturesConfig `+"`yaml:\"features\"`"+`
+	Features   FeaturesConfig   `+"`yaml:\"features\"`"+`
+	Generation GenerationConfig `+"`yaml:\"generation\"`"+`
+}
+
+type GenerationConfig struct {
+	Tests        bool     `+"`yaml:\"tests\"`"+`
+	LoadTest     bool     `+"`yaml:\"loadtest\"`"+`
+	E2E          bool     `+"`yaml:\"e2e\"`"+`
+	Integration  bool     `+"`yaml:\"integration\"`"+`
+	Reproducible bool     `+"`yaml:\"reproducible\"`"+`
+	Deploy       bool     `+"`yaml:\"deploy\"`"+`
+	Helm         bool     `+"`yaml:\"helm\"`"+`
+	BasePath     string   `+"`yaml:\"base_path\"`"+`
+	Router       string   `+"`yaml:\"router\"`"+`
+	Plugins      []string `+"`yaml:\"plugins\"`"+`
 }
 
 type FeaturesConfig struct {
-	Validation  ValidationConfig  `+"`yaml:\"validation\"`"+`
-	Conditional ConditionalConfig `+"`yaml:\"conditional\"`"+`
-	Versioning  VersioningConfig  `+"`yaml:\"versioning\"`"+`
-	Events      EventsConfig      `+"`yaml:\"events\"`"+`
-	Storage     StorageConfig     `+"`yaml:\"storage\"`"+`
+	Validation     ValidationConfig     `+"`yaml:\"validation\"`"+`
+	Conditional    ConditionalConfig    `+"`yaml:\"conditional\"`"+`
+	Versioning     VersioningConfig     `+"`yaml:\"versioning\"`"+`
+	Events         EventsConfig         `+"`yaml:\"events\"`"+`
+	Storage        StorageConfig        `+"`yaml:\"storage\"`"+`
+	Quota          QuotaConfig          `+"`yaml:\"quota\"`"+`
+	Revisions      RevisionsConfig      `+"`yaml:\"revisions\"`"+`
+	EventLog       EventLogConfig       `+"`yaml:\"event_log\"`"+`
+	Admission      AdmissionConfig      `+"`yaml:\"admission\"`"+`
+	Auth           AuthConfig           `+"`yaml:\"auth\"`"+`
+	RBAC           RBACConfig           `+"`yaml:\"rbac\"`"+`
+	APIKeys        APIKeysConfig        `+"`yaml:\"api_keys\"`"+`
+	Namespaces     NamespacesConfig     `+"`yaml:\"namespaces\"`"+`
+	Metrics        MetricsConfig        `+"`yaml:\"metrics\"`"+`
+	ReferenceCheck ReferenceCheckConfig `+"`yaml:\"reference_check\"`"+`
+	Locking        LockingConfig        `+"`yaml:\"locking\"`"+`
+	Encryption     EncryptionConfig     `+"`yaml:\"encryption\"`"+`
+	I18n           I18nConfig           `+"`yaml:\"i18n\"`"+`
+	Blobs          BlobsConfig          `+"`yaml:\"blobs\"`"+`
+	Cache          CacheConfig          `+"`yaml:\"cache\"`"+`
+	Import         ImportConfig         `+"`yaml:\"import\"`"+`
+	Backup         BackupConfig         `+"`yaml:\"backup\"`"+`
+	Webhooks       WebhooksConfig       `+"`yaml:\"webhooks\"`"+`
+	Protobuf       ProtobufConfig       `+"`yaml:\"protobuf\"`"+`
+	CBOR           CBORConfig           `+"`yaml:\"cbor\"`"+`
+	Streaming      StreamingConfig      `+"`yaml:\"streaming\"`"+`
+	Compression    CompressionConfig    `+"`yaml:\"compression\"`"+`
+	CORS           CORSConfig           `+"`yaml:\"cors\"`"+`
+	Limits         LimitsConfig         `+"`yaml:\"limits\"`"+`
+	CRDs           CRDsConfig           `+"`yaml:\"crds\"`"+`
+	Pagination     PaginationConfig     `+"`yaml:\"pagination\"`"+`
+	Docs           DocsConfig           `+"`yaml:\"docs\"`"+`
+	Watch          WatchConfig          `+"`yaml:\"watch\"`"+`
 }
 
 type ValidationConfig struct {
@@ -444,21 +774,203 @@ type ValidationConfig struct {
 type ConditionalConfig struct {
 	Enabled       bool   `+"`yaml:\"enabled\"`"+`
 	ETagAlgorithm string `+"`yaml:\"etag_algorithm\"`"+`
+	CacheControl  string `+"`yaml:\"cache_control\"`"+`
+	Weak          bool   `+"`yaml:\"weak\"`"+`
 }
 
 type EventsConfig struct {
 	Enabled bool   `+"`yaml:\"enabled\"`"+`
 	BusType string `+"`yaml:\"bus_type\"`"+`
+	Outbox  bool   `+"`yaml:\"outbox\"`"+`
+
+	DeadLetter DeadLetterConfig `+"`yaml:\"dead_letter\"`"+`
+}
+
+type DeadLetterConfig struct {
+	Enabled     bool `+"`yaml:\"enabled\"`"+`
+	MaxAttempts int  `+"`yaml:\"max_attempts\"`"+`
 }
 
 type VersioningConfig struct {
-	Enabled  bool   `+"`yaml:\"enabled\"`"+`
-	Strategy string `+"`yaml:\"strategy\"`"+`
+	Enabled        bool     `+"`yaml:\"enabled\"`"+`
+	Strategy       string   `+"`yaml:\"strategy\"`"+`
+	DefaultVersion string   `+"`yaml:\"default_version\"`"+`
+	Versions       []string `+"`yaml:\"versions\"`"+`
 }
 
 type StorageConfig struct {
-	Type     string `+"`yaml:\"type\"`"+`
-	DBDriver string `+"`yaml:\"db_driver\"`"+`
+	Type        string           `+"`yaml:\"type\"`"+`
+	DBDriver    string           `+"`yaml:\"db_driver\"`"+`
+	Compression string           `+"`yaml:\"compression\"`"+`
+	Migrations  string           `+"`yaml:\"migrations\"`"+`
+	SoftDelete  SoftDeleteConfig `+"`yaml:\"soft_delete\"`"+`
+}
+
+type SoftDeleteConfig struct {
+	Enabled          bool `+"`yaml:\"enabled\"`"+`
+	RetentionSeconds int  `+"`yaml:\"retention_seconds\"`"+`
+}
+
+type QuotaConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type RevisionsConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+	Limit   int  `+"`yaml:\"limit\"`"+`
+}
+
+type EventLogConfig struct {
+	Enabled    bool `+"`yaml:\"enabled\"`"+`
+	TTLSeconds int  `+"`yaml:\"ttl_seconds\"`"+`
+	Limit      int  `+"`yaml:\"limit\"`"+`
+}
+
+type AdmissionConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type AuthConfig struct {
+	Enabled  bool   `+"`yaml:\"enabled\"`"+`
+	JWKSURL  string `+"`yaml:\"jwks_url\"`"+`
+	Issuer   string `+"`yaml:\"issuer\"`"+`
+	Audience string `+"`yaml:\"audience\"`"+`
+}
+
+type RBACConfig struct {
+	Enabled     bool   `+"`yaml:\"enabled\"`"+`
+	PolicyFile  string `+"`yaml:\"policy_file\"`"+`
+	RoleClaim   string `+"`yaml:\"role_claim\"`"+`
+	GroupClaim  string `+"`yaml:\"group_claim\"`"+`
+	Engine      string `+"`yaml:\"engine\"`"+`
+	OPAURL      string `+"`yaml:\"opa_url\"`"+`
+	OPADecision string `+"`yaml:\"opa_decision\"`"+`
+}
+
+type APIKeysConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type NamespacesConfig struct {
+	Enabled bool   `+"`yaml:\"enabled\"`"+`
+	Claim   string `+"`yaml:\"claim\"`"+`
+}
+
+type MetricsConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type ReferenceCheckConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type LockingConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+	Enforce bool `+"`yaml:\"enforce\"`"+`
+}
+
+type EncryptionConfig struct {
+	Enabled      bool   `+"`yaml:\"enabled\"`"+`
+	Mode         string `+"`yaml:\"mode\"`"+`
+	KeyEnv       string `+"`yaml:\"key_env\"`"+`
+	RedactInList bool   `+"`yaml:\"redact_in_list\"`"+`
+}
+
+type I18nConfig struct {
+	Enabled    bool   `+"`yaml:\"enabled\"`"+`
+	CatalogDir string `+"`yaml:\"catalog_dir\"`"+`
+}
+
+type BlobsConfig struct {
+	Enabled bool   `+"`yaml:\"enabled\"`"+`
+	Backend string `+"`yaml:\"backend\"`"+`
+	Dir     string `+"`yaml:\"dir\"`"+`
+	MaxSize int64  `+"`yaml:\"max_size\"`"+`
+}
+
+type CacheConfig struct {
+	Enabled    bool   `+"`yaml:\"enabled\"`"+`
+	Backend    string `+"`yaml:\"backend\"`"+`
+	TTLSeconds int    `+"`yaml:\"ttl_seconds\"`"+`
+	MaxEntries int    `+"`yaml:\"max_entries\"`"+`
+}
+
+type ImportConfig struct {
+	Enabled  bool  `+"`yaml:\"enabled\"`"+`
+	MaxRows  int   `+"`yaml:\"max_rows\"`"+`
+	MaxBytes int64 `+"`yaml:\"max_bytes\"`"+`
+}
+
+type BackupConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type WebhooksConfig struct {
+	Enabled     bool `+"`yaml:\"enabled\"`"+`
+	Workers     int  `+"`yaml:\"workers\"`"+`
+	MaxAttempts int  `+"`yaml:\"max_attempts\"`"+`
+}
+
+type ProtobufConfig struct {
+	Enabled bool   `+"`yaml:\"enabled\"`"+`
+	Package string `+"`yaml:\"package\"`"+`
+}
+
+type CBORConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
+}
+
+type StreamingConfig struct {
+	Enabled    bool `+"`yaml:\"enabled\"`"+`
+	FlushEvery int  `+"`yaml:\"flush_every\"`"+`
+}
+
+type CompressionConfig struct {
+	Enabled      bool     `+"`yaml:\"enabled\"`"+`
+	MinSize      int      `+"`yaml:\"min_size\"`"+`
+	ContentTypes []string `+"`yaml:\"content_types\"`"+`
+	Encodings    []string `+"`yaml:\"encodings\"`"+`
+	Level        int      `+"`yaml:\"level\"`"+`
+}
+
+type CORSConfig struct {
+	Enabled          bool     `+"`yaml:\"enabled\"`"+`
+	AllowedOrigins   []string `+"`yaml:\"allowed_origins\"`"+`
+	AllowedMethods   []string `+"`yaml:\"allowed_methods\"`"+`
+	AllowedHeaders   []string `+"`yaml:\"allowed_headers\"`"+`
+	ExposedHeaders   []string `+"`yaml:\"exposed_headers\"`"+`
+	AllowCredentials bool     `+"`yaml:\"allow_credentials\"`"+`
+	MaxAge           int      `+"`yaml:\"max_age\"`"+`
+}
+
+type LimitsConfig struct {
+	Enabled     bool  `+"`yaml:\"enabled\"`"+`
+	MaxBodySize int64 `+"`yaml:\"max_body_size\"`"+`
+	Timeout     int   `+"`yaml:\"timeout\"`"+`
+}
+
+type CRDsConfig struct {
+	Enabled    bool   `+"`yaml:\"enabled\"`"+`
+	Group      string `+"`yaml:\"group\"`"+`
+	Scope      string `+"`yaml:\"scope\"`"+`
+	Conversion bool   `+"`yaml:\"conversion\"`"+`
+	Controller bool   `+"`yaml:\"controller\"`"+`
+}
+
+type PaginationConfig struct {
+	Enabled      bool   `+"`yaml:\"enabled\"`"+`
+	Mode         string `+"`yaml:\"mode\"`"+`
+	DefaultLimit int    `+"`yaml:\"default_limit\"`"+`
+	MaxLimit     int    `+"`yaml:\"max_limit\"`"+`
+}
+
+type DocsConfig struct {
+	Enabled *bool  `+"`yaml:\"enabled\"`"+`
+	UI      string `+"`yaml:\"ui\"`"+`
+}
+
+type WatchConfig struct {
+	Enabled bool `+"`yaml:\"enabled\"`"+`
 }
 
 func loadConfig() (*FabricaConfig, error) {
@@ -478,6 +990,8 @@ func loadConfig() (*FabricaConfig, error) {
 func main() {
 	gen := codegen.NewGenerator("%s", "%s", "%s")
 	gen.Verbose = %s
+	gen.DryRun = %s
+	gen.TemplateDir = "templates" // Project templates overriding the embedded ones
 	gen.Version = "%s" // Fabrica version used for generation
 
 	// Configure storage type - passed from main generate command
@@ -493,10 +1007,171 @@ func main() {
 		gen.Config.ValidationMode = config.Features.Validation.Mode
 		gen.Config.ConditionalEnabled = config.Features.Conditional.Enabled
 		gen.Config.ETagAlgorithm = config.Features.Conditional.ETagAlgorithm
+		gen.Config.ConditionalCacheControl = config.Features.Conditional.CacheControl
+		gen.Config.ETagWeak = config.Features.Conditional.Weak
 		gen.Config.VersioningEnabled = config.Features.Versioning.Enabled
 		gen.Config.VersionStrategy = config.Features.Versioning.Strategy
+		if config.Features.Versioning.DefaultVersion != "" {
+			gen.Config.DefaultAPIVersion = config.Features.Versioning.DefaultVersion
+		}
+		gen.Config.APIVersions = config.Features.Versioning.Versions
 		gen.Config.EventsEnabled = config.Features.Events.Enabled
 		gen.Config.EventBusType = config.Features.Events.BusType
+		gen.Config.EventOutboxEnabled = config.Features.Events.Outbox
+		gen.Config.DeadLetterEnabled = config.Features.Events.DeadLetter.Enabled
+		if config.Features.Events.DeadLetter.MaxAttempts > 0 {
+			gen.Config.DeadLetterMaxAttempts = config.Features.Events.DeadLetter.MaxAttempts
+		}
+		gen.Config.QuotaEnabled = config.Features.Quota.Enabled
+		gen.Config.RevisionsEnabled = config.Features.Revisions.Enabled
+		gen.Config.RevisionHistoryLimit = config.Features.Revisions.Limit
+		gen.Config.EventLogEnabled = config.Features.EventLog.Enabled
+		if config.Features.EventLog.TTLSeconds > 0 {
+			gen.Config.EventLogTTLSeconds = config.Features.EventLog.TTLSeconds
+		}
+		if config.Features.EventLog.Limit > 0 {
+			gen.Config.EventLogLimit = config.Features.EventLog.Limit
+		}
+		gen.Config.AdmissionEnabled = config.Features.Admission.Enabled
+		gen.Config.AuthEnabled = config.Features.Auth.Enabled
+		gen.Config.AuthJWKSURL = config.Features.Auth.JWKSURL
+		gen.Config.AuthIssuer = config.Features.Auth.Issuer
+		gen.Config.AuthAudience = config.Features.Auth.Audience
+		gen.Config.RBACEnabled = config.Features.RBAC.Enabled
+		gen.Config.RBACPolicyFile = config.Features.RBAC.PolicyFile
+		gen.Config.RBACRoleClaim = config.Features.RBAC.RoleClaim
+		gen.Config.RBACGroupClaim = config.Features.RBAC.GroupClaim
+		if config.Features.RBAC.Engine != "" {
+			gen.Config.RBACEngine = config.Features.RBAC.Engine
+		}
+		if config.Features.RBAC.OPAURL != "" {
+			gen.Config.OPAURL = config.Features.RBAC.OPAURL
+		}
+		if config.Features.RBAC.OPADecision != "" {
+			gen.Config.OPADecision = config.Features.RBAC.OPADecision
+		}
+		gen.Config.APIKeysEnabled = config.Features.APIKeys.Enabled
+		gen.Config.NamespacesEnabled = config.Features.Namespaces.Enabled
+		if config.Features.Namespaces.Claim != "" {
+			gen.Config.NamespacesClaim = config.Features.Namespaces.Claim
+		}
+		gen.Config.MetricsEnabled = config.Features.Metrics.Enabled
+		gen.Config.ReferenceCheckEnabled = config.Features.ReferenceCheck.Enabled
+		gen.Config.LockingEnabled = config.Features.Locking.Enabled
+		gen.Config.LockingEnforced = config.Features.Locking.Enforce
+		// Modes: fields (default) encrypts tagged fields, envelope the whole
+		// spec and status, both does both
+		gen.Config.EncryptionEnabled = config.Features.Encryption.Enabled && config.Features.Encryption.Mode != "envelope"
+		gen.Config.EncryptionEnvelope = config.Features.Encryption.Enabled &&
+			(config.Features.Encryption.Mode == "envelope" || config.Features.Encryption.Mode == "both")
+		gen.Config.EncryptionRedactInList = config.Features.Encryption.RedactInList
+		if config.Features.Encryption.KeyEnv != "" {
+			gen.Config.EncryptionKeyEnv = config.Features.Encryption.KeyEnv
+		}
+		gen.Config.I18nEnabled = config.Features.I18n.Enabled
+		if config.Features.I18n.CatalogDir != "" {
+			gen.Config.I18nCatalogDir = config.Features.I18n.CatalogDir
+		}
+		gen.Config.BlobsEnabled = config.Features.Blobs.Enabled
+		if config.Features.Blobs.Backend != "" {
+			gen.Config.BlobBackend = config.Features.Blobs.Backend
+		}
+		if config.Features.Blobs.Dir != "" {
+			gen.Config.BlobDir = config.Features.Blobs.Dir
+		}
+		if config.Features.Blobs.MaxSize > 0 {
+			gen.Config.BlobMaxSize = config.Features.Blobs.MaxSize
+		}
+		gen.Config.CacheEnabled = config.Features.Cache.Enabled
+		if config.Features.Cache.Backend != "" {
+			gen.Config.CacheBackend = config.Features.Cache.Backend
+		}
+		if config.Features.Cache.TTLSeconds > 0 {
+			gen.Config.CacheTTLSeconds = config.Features.Cache.TTLSeconds
+		}
+		if config.Features.Cache.MaxEntries > 0 {
+			gen.Config.CacheMaxEntries = config.Features.Cache.MaxEntries
+		}
+		gen.Config.ImportEnabled = config.Features.Import.Enabled
+		if config.Features.Import.MaxRows > 0 {
+			gen.Config.ImportMaxRows = config.Features.Import.MaxRows
+		}
+		if config.Features.Import.MaxBytes > 0 {
+			gen.Config.ImportMaxBytes = config.Features.Import.MaxBytes
+		}
+		gen.Config.BackupEnabled = config.Features.Backup.Enabled
+		gen.Config.WebhooksEnabled = config.Features.Webhooks.Enabled
+		if config.Features.Webhooks.Workers > 0 {
+			gen.Config.WebhookWorkers = config.Features.Webhooks.Workers
+		}
+		if config.Features.Webhooks.MaxAttempts > 0 {
+			gen.Config.WebhookMaxAttempts = config.Features.Webhooks.MaxAttempts
+		}
+		gen.Config.ProtobufEnabled = config.Features.Protobuf.Enabled
+		gen.Config.ProtobufPackage = config.Features.Protobuf.Package
+		gen.Config.CBOREnabled = config.Features.CBOR.Enabled
+		gen.Config.StreamingEnabled = config.Features.Streaming.Enabled
+		if config.Features.Streaming.FlushEvery > 0 {
+			gen.Config.StreamingFlushEvery = config.Features.Streaming.FlushEvery
+		}
+		gen.Config.CompressionEnabled = config.Features.Compression.Enabled
+		if config.Features.Compression.MinSize > 0 {
+			gen.Config.CompressionMinSize = config.Features.Compression.MinSize
+		}
+		gen.Config.CompressionContentTypes = config.Features.Compression.ContentTypes
+		gen.Config.CompressionEncodings = config.Features.Compression.Encodings
+		gen.Config.CompressionLevel = config.Features.Compression.Level
+		gen.Config.CORSEnabled = config.Features.CORS.Enabled
+		gen.Config.CORSAllowedOrigins = config.Features.CORS.AllowedOrigins
+		gen.Config.CORSAllowedMethods = config.Features.CORS.AllowedMethods
+		gen.Config.CORSAllowedHeaders = config.Features.CORS.AllowedHeaders
+		gen.Config.CORSExposedHeaders = config.Features.CORS.ExposedHeaders
+		gen.Config.CORSAllowCredentials = config.Features.CORS.AllowCredentials
+		if config.Features.CORS.MaxAge > 0 {
+			gen.Config.CORSMaxAge = config.Features.CORS.MaxAge
+		}
+		gen.Config.LimitsEnabled = config.Features.Limits.Enabled
+		if config.Features.Limits.MaxBodySize > 0 {
+			gen.Config.MaxRequestBodySize = config.Features.Limits.MaxBodySize
+		}
+		if config.Features.Limits.Timeout > 0 {
+			gen.Config.RequestTimeout = config.Features.Limits.Timeout
+		}
+		gen.Config.CRDsEnabled = config.Features.CRDs.Enabled
+		gen.Config.CRDGroup = config.Features.CRDs.Group
+		if config.Features.CRDs.Scope != "" {
+			gen.Config.CRDScope = config.Features.CRDs.Scope
+		}
+		gen.Config.CRDConversion = config.Features.CRDs.Conversion
+		gen.Config.CRDController = config.Features.CRDs.Controller
+		gen.Config.PaginationEnabled = config.Features.Pagination.Enabled
+		if config.Features.Pagination.Mode != "" {
+			gen.Config.PaginationMode = config.Features.Pagination.Mode
+		}
+		if config.Features.Pagination.DefaultLimit > 0 {
+			gen.Config.PaginationDefaultLimit = config.Features.Pagination.DefaultLimit
+		}
+		if config.Features.Pagination.MaxLimit > 0 {
+			gen.Config.PaginationMaxLimit = config.Features.Pagination.MaxLimit
+		}
+		if config.Features.Docs.Enabled != nil {
+			gen.Config.DocsEnabled = *config.Features.Docs.Enabled
+		}
+		if config.Features.Docs.UI != "" {
+			gen.Config.DocsUI = config.Features.Docs.UI
+		}
+		gen.Config.WatchEnabled = config.Features.Watch.Enabled
+		gen.Config.TestsEnabled = config.Generation.Tests
+		gen.Config.LoadTestEnabled = config.Generation.LoadTest
+		gen.Config.E2EEnabled = config.Generation.E2E
+		gen.Config.IntegrationEnabled = config.Generation.Integration
+		gen.Config.Reproducible = config.Generation.Reproducible
+		gen.Config.DeployEnabled = config.Generation.Deploy
+		gen.Config.HelmEnabled = config.Generation.Helm
+		gen.Config.BasePath = config.Generation.BasePath
+		if config.Generation.Router != "" {
+			gen.Config.RouterType = config.Generation.Router
+		}
 
 		// Override storage config from .fabrica.yaml if present
 		if config.Features.Storage.Type != "" {
@@ -507,14 +1182,22 @@ func main() {
 			gen.SetDBDriver(config.Features.Storage.DBDriver)
 			gen.Config.DBDriver = config.Features.Storage.DBDriver
 		}
+		gen.Config.StorageCompression = config.Features.Storage.Compression
+		if config.Features.Storage.Migrations != "" {
+			gen.Config.StorageMigrations = config.Features.Storage.Migrations
+		}
+		gen.Config.SoftDeleteEnabled = config.Features.Storage.SoftDelete.Enabled
+		if config.Features.Storage.SoftDelete.RetentionSeconds > 0 {
+			gen.Config.SoftDeleteRetentionSeconds = config.Features.Storage.SoftDelete.RetentionSeconds
+		}
 	}
 
 	if err := resources.RegisterAllResources(gen); err != nil {
 		log.Fatalf("Failed to register resources: %%v", err)
 	}
 
-%s}
-`, fmtImport, modulePath, outputDir, packageName, modulePath, verboseFlag, version, storageType, storageType, generationCalls.String())
+%s%s}
+`, fmtImport, modulePath, versionImports, outputDir, packageName, modulePath, verboseFlag, dryRunFlag, version, storageType, storageType, versionCalls, generationCalls.String())
 }
 
 // discoverResources scans pkg/resources for resource definitions
@@ -545,12 +1228,17 @@ func discoverResources() ([]string, error) {
 			return nil // Skip files that don't parse
 		}
 
-		// Look for struct types that embed resource.Resource
+		// Look for struct types that embed resource.Resource, other than
+		// those declaring a schema version of another resource
+		versions := versionMarkers(node)
 		ast.Inspect(node, func(n ast.Node) bool {
 			typeSpec, ok := n.(*ast.TypeSpec)
 			if !ok {
 				return true
 			}
+			if _, ok := versions[typeSpec]; ok {
+				return false
+			}
 
 			structType, ok := typeSpec.Type.(*ast.StructType)
 			if !ok {
@@ -653,9 +1341,35 @@ func generateRegistrationCode(modulePath string, resources []string) string {
 		// After registration, set per-resource tags if markers are present.
 		// Marker: // +fabrica:resource-versioning=enabled on the resource source file
 		registrations.WriteString("\t// Set per-resource tags based on source markers\n")
-		registrations.WriteString(fmt.Sprintf("\tif hasVersioningMarker(\"%s\") {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\tif hasMarker(\"%s\", \"+fabrica:resource-versioning=enabled\") {\n", resource))
 		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"versioning\", \"enabled\")\n", resource))
 		registrations.WriteString("\t}\n")
+		// Marker: // +fabrica:unique-name=enabled enforces unique metadata.name
+		registrations.WriteString(fmt.Sprintf("\tif hasMarker(\"%s\", \"+fabrica:unique-name=enabled\") {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"uniqueName\", \"enabled\")\n", resource))
+		registrations.WriteString("\t}\n")
+		// Marker: // +fabrica:actions=powerOn,reset declares custom action subresources
+		registrations.WriteString(fmt.Sprintf("\tif actions := markerValue(\"%s\", \"+fabrica:actions=\"); actions != \"\" {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"actions\", actions)\n", resource))
+		registrations.WriteString("\t}\n")
+		// Markers: // +fabrica:etag=weak and // +fabrica:etag-algorithm=md5 override the conditional settings
+		registrations.WriteString(fmt.Sprintf("\tif etag := markerValue(\"%s\", \"+fabrica:etag=\"); etag != \"\" {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"etag\", etag)\n", resource))
+		registrations.WriteString("\t}\n")
+		registrations.WriteString(fmt.Sprintf("\tif algorithm := markerValue(\"%s\", \"+fabrica:etag-algorithm=\"); algorithm != \"\" {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"etagAlgorithm\", algorithm)\n", resource))
+		registrations.WriteString("\t}\n")
+		// Markers: // +fabrica:ttl=24h and // +fabrica:on-expire=soft-delete expire resources
+		registrations.WriteString(fmt.Sprintf("\tif ttl := markerValue(\"%s\", \"+fabrica:ttl=\"); ttl != \"\" {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"ttl\", ttl)\n", resource))
+		registrations.WriteString("\t}\n")
+		registrations.WriteString(fmt.Sprintf("\tif onExpire := markerValue(\"%s\", \"+fabrica:on-expire=\"); onExpire != \"\" {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"onExpire\", onExpire)\n", resource))
+		registrations.WriteString("\t}\n")
+		// Marker: // +fabrica:id-strategy=uuidv7 chooses how UIDs are minted
+		registrations.WriteString(fmt.Sprintf("\tif strategy := markerValue(\"%s\", \"+fabrica:id-strategy=\"); strategy != \"\" {\n", resource))
+		registrations.WriteString(fmt.Sprintf("\t\tgen.SetResourceTag(\"%s\", \"idStrategy\", strategy)\n", resource))
+		registrations.WriteString("\t}\n")
 	}
 
 	return fmt.Sprintf(`// Code generated by fabrica codegen init. DO NOT EDIT.
@@ -677,8 +1391,8 @@ func RegisterAllResources(gen *codegen.Generator) error {
 	return nil
 }
 
-	// hasVersioningMarker inspects the resource source file for the versioning marker comment.
-	func hasVersioningMarker(resourceName string) bool {
+	// hasMarker inspects the resource source file for a marker comment.
+	func hasMarker(resourceName, marker string) bool {
 		// Derive path: pkg/resources/<lower(resourceName)>/<lower(resourceName)>.go
 		pkg := strings.ToLower(resourceName)
 		path := filepath.Join("pkg", "resources", pkg, pkg+".go")
@@ -687,7 +1401,24 @@ func RegisterAllResources(gen *codegen.Generator) error {
 			return false
 		}
 		content := string(data)
-		return strings.Contains(content, "+fabrica:resource-versioning=enabled")
+		return strings.Contains(content, marker)
+	}
+
+	// markerValue returns the value following a marker prefix in the resource
+	// source file, e.g. "powerOn,reset" for "+fabrica:actions=", or "" if absent.
+	func markerValue(resourceName, prefix string) string {
+		pkg := strings.ToLower(resourceName)
+		path := filepath.Join("pkg", "resources", pkg, pkg+".go")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if _, value, ok := strings.Cut(line, prefix); ok {
+				return strings.TrimSpace(value)
+			}
+		}
+		return ""
 	}
 `, imports.String(), registrations.String())
 }
@@ -722,6 +1453,28 @@ func generateEntCode(debug bool) error {
 	return nil
 }
 
+// writeOpenAPIArtifacts runs the generated server's openapi command, which
+// writes the spec served at /openapi.json to api/openapi.json and
+// api/openapi.yaml
+func writeOpenAPIArtifacts(debug bool) error {
+	cmd := exec.Command("go", "run", "-mod=mod", "./cmd/server", "openapi", "--output", "api")
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if debug {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(output.String()); msg != "" {
+			return fmt.Errorf("%w\n%s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
 // Version comparison and checking functions
 
 // parseVersion extracts version from a string like "v1.2.3" or "1.2.3"
@@ -6,7 +6,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,7 +33,7 @@ type initOptions struct {
 	// New feature flags for core features
 	validationMode  string // strict, warn, disabled
 	withEvents      bool   // Enable CloudEvents support
-	eventBusType    string // memory, nats, kafka
+	eventBusType    string // memory, or a bus registered with events.RegisterBus
 	versionStrategy string // header, url, both
 
 	// Reconciliation options
@@ -40,8 +42,8 @@ type initOptions struct {
 	reconcileRequeueMs int  // Default requeue delay in minutes
 
 	// Storage options
-	storageType string // file, ent
-	dbDriver    string // postgres, mysql, sqlite
+	storageType string // file, ent, redis, s3
+	dbDriver    string // postgres, mysql, cockroach, sqlserver, sqlite
 }
 
 // Template data structure
@@ -81,7 +83,7 @@ func newInitCommand() *cobra.Command {
 		Long: `Initialize a new Fabrica project with configurable features.
 
 Instead of complex modes, use feature flags to customize your project:
-  --auth          Enable authentication with TokenSmith
+  --auth          Enable JWT bearer authentication (JWKS or public key)
   --storage       Enable persistent storage (file or database)
   --metrics       Enable Prometheus metrics
 
@@ -96,10 +98,21 @@ or by providing the name of an existing directory.`,
 				projectName = args[0]
 			}
 
-			// If a non-default database driver is specified, automatically use ent storage
-			if opts.dbDriver == "postgres" || opts.dbDriver == "mysql" {
+			// If a non-default database driver is specified, automatically use
+			// ent storage unless SQL storage was chosen
+			if (opts.dbDriver == "postgres" || opts.dbDriver == "mysql" || opts.dbDriver == "cockroach") && opts.storageType != "sql" {
 				opts.storageType = "ent"
 			}
+			if opts.dbDriver == "cockroach" && opts.storageType != "ent" {
+				return fmt.Errorf("--db cockroach requires --storage-type ent")
+			}
+			// Ent has no SQL Server dialect, so SQL Server uses SQL storage
+			if opts.dbDriver == "sqlserver" {
+				if opts.storageType == "ent" {
+					return fmt.Errorf("--db sqlserver requires --storage-type sql (Ent does not support SQL Server)")
+				}
+				opts.storageType = "sql"
+			}
 
 			if opts.interactive {
 				return runInteractiveInit(projectName, opts)
@@ -115,7 +128,7 @@ or by providing the name of an existing directory.`,
 	cmd.Flags().StringVar(&opts.description, "description", "", "Project description")
 
 	// Feature flags
-	cmd.Flags().BoolVar(&opts.withAuth, "auth", false, "Enable authentication with TokenSmith")
+	cmd.Flags().BoolVar(&opts.withAuth, "auth", false, "Enable JWT bearer authentication, verifying tokens with a JWKS endpoint or public key")
 	cmd.Flags().BoolVar(&opts.withStorage, "storage", true, "Enable persistent storage")
 	cmd.Flags().BoolVar(&opts.withMetrics, "metrics", false, "Enable Prometheus metrics")
 	cmd.Flags().BoolVar(&opts.withVersion, "version", true, "Enable version command")
@@ -123,7 +136,7 @@ or by providing the name of an existing directory.`,
 	// Core feature configuration
 	cmd.Flags().StringVar(&opts.validationMode, "validation-mode", "strict", "Validation mode: strict, warn, or disabled")
 	cmd.Flags().BoolVar(&opts.withEvents, "events", false, "Enable CloudEvents support")
-	cmd.Flags().StringVar(&opts.eventBusType, "events-bus", "memory", "Event bus type: memory, nats, or kafka")
+	cmd.Flags().StringVar(&opts.eventBusType, "events-bus", "memory", "Event bus: memory, or the name of a bus registered with events.RegisterBus")
 	cmd.Flags().StringVar(&opts.versionStrategy, "version-strategy", "header", "API versioning strategy: header, url, or both")
 
 	// Reconciliation configuration
@@ -132,8 +145,8 @@ or by providing the name of an existing directory.`,
 	cmd.Flags().IntVar(&opts.reconcileRequeueMs, "reconcile-requeue", 5, "Default requeue delay in minutes")
 
 	// Storage options
-	cmd.Flags().StringVar(&opts.storageType, "storage-type", "file", "Storage backend: file or ent")
-	cmd.Flags().StringVar(&opts.dbDriver, "db", "sqlite", "Database driver for Ent: postgres, mysql, or sqlite")
+	cmd.Flags().StringVar(&opts.storageType, "storage-type", "file", "Storage backend: file, ent, redis, s3 or sql")
+	cmd.Flags().StringVar(&opts.dbDriver, "db", "sqlite", "Database driver for Ent and SQL storage: postgres, mysql, cockroach (Ent only), sqlserver (SQL only), or sqlite")
 
 	return cmd
 }
@@ -176,7 +189,7 @@ func runInteractiveInit(projectName string, opts *initOptions) error {
 	fmt.Println("🚀 Features to enable:")
 
 	// Authentication
-	fmt.Print("Enable authentication with TokenSmith? [y/N]: ")
+	fmt.Print("Enable JWT bearer authentication (JWKS or public key)? [y/N]: ")
 	input, _ = reader.ReadString('\n')
 	opts.withAuth = strings.HasPrefix(strings.ToLower(strings.TrimSpace(input)), "y")
 
@@ -191,18 +204,33 @@ func runInteractiveInit(projectName string, opts *initOptions) error {
 		// Storage type
 		fmt.Println("Storage backend:")
 		fmt.Println("  1) File-based storage (simple)")
-		fmt.Println("  2) Database with Ent (postgres/mysql/sqlite)")
+		fmt.Println("  2) Database with Ent (postgres/mysql/cockroach/sqlite)")
+		fmt.Println("  3) Redis (low-latency, optionally persistent)")
+		fmt.Println("  4) S3-compatible object storage (archival)")
+		fmt.Println("  5) Database with plain SQL (postgres/mysql/sqlserver/sqlite, no Ent)")
 		fmt.Print("Choose [1]: ")
 		input, _ = reader.ReadString('\n')
-		switch strings.TrimSpace(input) {
-		case "2":
+		switch choice := strings.TrimSpace(input); choice {
+		case "3":
+			opts.storageType = "redis"
+		case "4":
+			opts.storageType = "s3"
+		case "2", "5":
 			opts.storageType = "ent"
+			if choice == "5" {
+				opts.storageType = "sql"
+			}
 
 			// Database driver
 			fmt.Println("Database driver:")
 			fmt.Println("  1) SQLite (file-based)")
 			fmt.Println("  2) PostgreSQL")
 			fmt.Println("  3) MySQL")
+			if opts.storageType == "ent" {
+				fmt.Println("  4) CockroachDB")
+			} else {
+				fmt.Println("  4) SQL Server")
+			}
 			fmt.Print("Choose [1]: ")
 			input, _ = reader.ReadString('\n')
 			switch strings.TrimSpace(input) {
@@ -210,6 +238,11 @@ func runInteractiveInit(projectName string, opts *initOptions) error {
 				opts.dbDriver = "postgres"
 			case "3":
 				opts.dbDriver = "mysql"
+			case "4":
+				opts.dbDriver = "sqlserver"
+				if opts.storageType == "ent" {
+					opts.dbDriver = "cockroach"
+				}
 			default:
 				opts.dbDriver = "sqlite"
 			}
@@ -235,7 +268,7 @@ func runInteractiveInit(projectName string, opts *initOptions) error {
 	fmt.Printf("    Authentication: %s\n", map[bool]string{true: "enabled", false: "disabled"}[opts.withAuth])
 	if opts.withStorage {
 		fmt.Printf("    Storage: %s", opts.storageType)
-		if opts.storageType == "ent" {
+		if opts.storageType == "ent" || opts.storageType == "sql" {
 			fmt.Printf(" (%s)", opts.dbDriver)
 		}
 		fmt.Println()
@@ -337,7 +370,7 @@ func createProjectStructure(targetDir, projectName string, opts *initOptions) er
 		EventBusType:     opts.eventBusType,
 		ReconcileWorkers: opts.reconcileWorkers,
 		FabricaVersion:   version,
-		GeneratedAt:      time.Now().Format(time.RFC3339),
+		GeneratedAt:      codegen.GenerationTimestamp(false),
 		FeaturesText:     "", // Will be populated later
 	}
 
@@ -348,6 +381,7 @@ func createProjectStructure(targetDir, projectName string, opts *initOptions) er
 	dirs := []string{
 		"cmd/server",
 		"pkg/resources",
+		"internal/config",
 		"internal/storage",
 	}
 
@@ -363,6 +397,14 @@ func createProjectStructure(targetDir, projectName string, opts *initOptions) er
 		return err
 	}
 
+	// Generate the config package from template
+	if err := generateFromTemplate("init/config.go.tmpl", filepath.Join(targetDir, "internal/config/config.go"), data); err != nil {
+		return err
+	}
+	if err := generateFromTemplate("init/config_test.go.tmpl", filepath.Join(targetDir, "internal/config/config_test.go"), data); err != nil {
+		return err
+	}
+
 	// Create go.mod from template
 	if err := generateFromTemplate("init/go.mod.tmpl", filepath.Join(targetDir, "go.mod"), data); err != nil {
 		return err
@@ -411,14 +453,22 @@ func generateFromTemplate(templateName, outputPath string, data templateData) er
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", outputPath, err)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
 	}
-	defer file.Close() //nolint:errcheck
 
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	output := buf.Bytes()
+	if strings.HasSuffix(outputPath, ".go") {
+		formatted, err := format.Source(output)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", outputPath, err)
+		}
+		output = formatted
+	}
+
+	if err := os.WriteFile(outputPath, output, 0644); err != nil {
+		return fmt.Errorf("failed to create file %s: %w", outputPath, err)
 	}
 
 	return nil
@@ -428,11 +478,17 @@ func generateFeaturesText(data templateData) string {
 	var features []string
 
 	if data.WithAuth {
-		features = append(features, "- 🔐 Authentication with TokenSmith")
+		features = append(features, "- 🔐 JWT bearer authentication (JWKS or public key)")
 	}
 	if data.WithStorage {
 		if data.StorageType == "ent" {
 			features = append(features, fmt.Sprintf("- 💾 Database storage (%s)", data.DBDriver))
+		} else if data.StorageType == "redis" {
+			features = append(features, "- 💾 Redis storage")
+		} else if data.StorageType == "s3" {
+			features = append(features, "- 💾 S3 object storage")
+		} else if data.StorageType == "sql" {
+			features = append(features, fmt.Sprintf("- 💾 SQL database storage (%s)", data.DBDriver))
 		} else {
 			features = append(features, "- 💾 File-based storage")
 		}
@@ -517,6 +573,7 @@ func createFabricaConfig(targetDir string, opts *initOptions) error {
 			Events:         opts.withEvents,
 			Middleware:     true, // Core features always include middleware
 			Reconciliation: opts.withReconcile,
+			Tests:          true,
 		},
 	}
 
@@ -554,7 +611,7 @@ func createStubStorage(targetDir string, data templateData) error {
 	// Create stub storage.go file
 	var stubContent string
 	switch data.StorageType {
-	case "file":
+	case "file", "redis", "s3", "sql":
 		stubContent = `// Code generated by Fabrica. DO NOT EDIT manually.
 // This is a stub file created during init to prevent import errors.
 // It will be replaced when you run 'fabrica generate --storage'
@@ -40,6 +40,9 @@ The CLI provides commands for:
 	rootCmd.AddCommand(newAddCommand())
 	rootCmd.AddCommand(newGenerateCommand())
 	rootCmd.AddCommand(newEntCommand())
+	rootCmd.AddCommand(newCheckCompatCommand())
+	rootCmd.AddCommand(newValidateCommand())
+	rootCmd.AddCommand(newUpgradeCommand())
 	rootCmd.AddCommand(newVersionCommand())
 
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,503 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/codegen"
+	"github.com/spf13/cobra"
+)
+
+// fabricaModule is the module of Fabrica, whose version in a project's
+// go.mod provides the templates of its generated code
+const fabricaModule = "github.com/openchami/fabrica"
+
+// fabricaHeaderPattern matches the lines of generated file headers naming
+// the Fabrica version
+var fabricaHeaderPattern = regexp.MustCompile(`(?m)^.*[Gg]enerated by Fabrica .*\n?`)
+
+func newUpgradeCommand() *cobra.Command {
+	var (
+		to     string
+		yes    bool
+		dryRun bool
+		debug  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade generated code to a new Fabrica version, reviewing each change",
+		Long: `Regenerate the project with a new version of Fabrica in a staging copy,
+then review the diff of each file that would change and apply it.
+
+The copy is regenerated twice: first with the Fabrica version in go.mod,
+to find generated files edited outside their custom regions, whose edits
+the upgrade would drop, then after upgrading Fabrica in go.mod. Changes to
+such files are flagged as customized. Nothing in the project is written
+until a change is applied.
+
+Examples:
+  fabrica upgrade                 # Upgrade to the version of this fabrica
+  fabrica upgrade --to v0.5.0     # Upgrade to another version
+  fabrica upgrade --dry-run       # Show the changes, apply none
+  fabrica upgrade --yes           # Apply every change without asking
+`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runUpgrade(to, yes, dryRun, debug)
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Fabrica version to upgrade to (default: the version of this fabrica)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply every change without asking")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the diff of each file that would change, without applying any")
+	cmd.Flags().BoolVar(&debug, "debug", false, "Show the output of regenerating the staging copy")
+
+	return cmd
+}
+
+func runUpgrade(to string, yes, dryRun, debug bool) error {
+	if _, err := os.Stat("pkg/resources/register_generated.go"); err != nil {
+		return fmt.Errorf("no generated code found: run 'fabrica generate' before upgrading")
+	}
+	project, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	mod, err := readGoMod(".")
+	if err != nil {
+		return err
+	}
+
+	current, replacement := mod.fabrica()
+	target := to
+	switch {
+	case replacement != "" && to != "":
+		return fmt.Errorf("go.mod replaces %s with %s: point the replacement at the new version instead of using --to", fabricaModule, replacement)
+	case replacement != "":
+		fmt.Printf("ℹ️  go.mod replaces %s with %s, so the upgrade regenerates with it as it is\n", fabricaModule, replacement)
+	case to == "" && (version == "dev" || version == "none"):
+		return fmt.Errorf("this fabrica is a development build: name the version to upgrade to with --to")
+	case to == "":
+		target = version
+	}
+
+	from := detectGeneratedVersion()
+	if from == "" {
+		from = "an unknown version"
+	}
+	if target != "" {
+		fmt.Printf("⬆️  Upgrading code generated by Fabrica %s to %s...\n", from, target)
+	} else {
+		fmt.Printf("⬆️  Upgrading code generated by Fabrica %s...\n", from)
+	}
+
+	stage, err := os.MkdirTemp("", "fabrica-upgrade-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stage) // nolint:errcheck
+
+	if debug {
+		fmt.Printf("  Staging directory: %s\n", stage)
+	}
+	fmt.Println("📁 Copying the project to a staging directory...")
+	if err := copyProject(".", stage); err != nil {
+		return fmt.Errorf("failed to copy the project: %w", err)
+	}
+	if err := mod.stageReplacements(project, stage); err != nil {
+		return err
+	}
+
+	// Regenerating with the current version changes exactly the files
+	// edited outside their custom regions
+	customized := make(map[string]bool)
+	if replacement != "" {
+		fmt.Println("ℹ️  Files edited outside custom regions can't be found when regenerating with a replacement")
+	} else {
+		fmt.Printf("🔧 Regenerating the copy with Fabrica %s to find customized files...\n", current)
+		if err := generateStaged(stage, debug); err != nil {
+			fmt.Printf("⚠️  Could not regenerate with Fabrica %s: %v\n", current, err)
+			fmt.Println("   Files edited outside custom regions can't be told from those the upgrade changes")
+		} else {
+			changes, err := compareTrees(".", stage, func(data []byte) []byte {
+				return fabricaHeaderPattern.ReplaceAll(data, nil)
+			})
+			if err != nil {
+				return err
+			}
+			for _, change := range changes {
+				if change.Path != "go.mod" && change.Path != "go.sum" {
+					customized[change.Path] = true
+				}
+			}
+		}
+
+		fmt.Printf("⬆️  Upgrading %s to %s in the copy...\n", fabricaModule, target)
+		cmd := exec.Command("go", "get", fabricaModule+"@"+target)
+		cmd.Dir = stage
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to upgrade %s to %s: %w\n%s", fabricaModule, target, err, output)
+		}
+	}
+
+	fmt.Println("🔧 Regenerating the copy...")
+	if err := generateStaged(stage, debug); err != nil {
+		return fmt.Errorf("failed to regenerate the copy: %w", err)
+	}
+	changes, err := compareTrees(".", stage, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if overrides := templateOverrides(); len(overrides) > 0 {
+		fmt.Printf("⚠️  templates/ overrides %d template(s), which the new version uses too:\n", len(overrides))
+		for _, path := range overrides {
+			fmt.Printf("     %s\n", path)
+		}
+		fmt.Println("   Compare them with the templates of the new version, which may have changed")
+		fmt.Println()
+	}
+	if len(changes) == 0 {
+		fmt.Println("✅ Generated code is up to date")
+		return nil
+	}
+
+	fmt.Printf("📋 %d file(s) change:\n", len(changes))
+	for _, change := range changes {
+		printUpgradeChange(change, customized[change.Path])
+	}
+	fmt.Println()
+
+	if dryRun {
+		for _, change := range changes {
+			fmt.Print(change.Diff)
+		}
+		fmt.Println("✅ Dry run complete, nothing was applied")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	applied, all := 0, yes
+review:
+	for _, change := range changes {
+		if !all {
+			if change.Diff != "" {
+				fmt.Print(change.Diff)
+			}
+			printUpgradeChange(change, customized[change.Path])
+			switch askUpgrade(reader) {
+			case "n":
+				continue
+			case "a":
+				all = true
+			case "q":
+				break review
+			}
+		}
+		if err := applyUpgradeChange(stage, change); err != nil {
+			return err
+		}
+		applied++
+	}
+
+	fmt.Println()
+	fmt.Printf("✅ Applied %d of %d change(s)\n", applied, len(changes))
+	if applied < len(changes) {
+		fmt.Println("   Run 'fabrica upgrade' again to review the others")
+	}
+	if applied > 0 {
+		fmt.Println()
+		fmt.Println("Next steps:")
+		fmt.Println("  go mod tidy                     # Update dependencies")
+		fmt.Println("  go build ./... && go test ./... # Check the upgraded code")
+	}
+	return nil
+}
+
+// askUpgrade asks whether to apply a change until answered, returning
+// "y", "n", "a" or "q"; the end of input quits
+func askUpgrade(reader *bufio.Reader) string {
+	for {
+		fmt.Print("Apply? [y]es, [n]o, [a]ll remaining, [q]uit: ")
+		input, err := reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(input))
+		switch {
+		case answer == "y" || answer == "yes" || answer == "n" || answer == "no" ||
+			answer == "a" || answer == "all" || answer == "q" || answer == "quit":
+			return answer[:1]
+		case err != nil:
+			fmt.Println()
+			return "q"
+		}
+	}
+}
+
+// printUpgradeChange prints the summary line of a change, flagging files
+// with customizations
+func printUpgradeChange(change codegen.FileChange, customized bool) {
+	fmt.Printf("  %-9s %s (+%d -%d)\n", change.Action, change.Path, change.Added, change.Removed)
+	if customized {
+		fmt.Println("     ⚠️  customized outside its custom regions: applying drops the edits")
+	}
+	if data, err := os.ReadFile(change.Path); err == nil && codegen.HasCustomCode(data) {
+		fmt.Println("     ✎  has custom code, which applying keeps")
+	}
+}
+
+// applyUpgradeChange applies a change of the staging copy to the project
+func applyUpgradeChange(stage string, change codegen.FileChange) error {
+	if change.Action == "deleted" {
+		if err := os.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+		}
+		return nil
+	}
+	src := filepath.Join(stage, change.Path)
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(change.Path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(change.Path, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", change.Path, err)
+	}
+	return nil
+}
+
+// generateStaged runs 'fabrica generate' in the staging copy, showing its
+// output only when it fails, or with debug
+func generateStaged(stage string, debug bool) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"generate", "--force"}
+	if debug {
+		args = append(args, "--debug")
+	}
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = stage
+	if debug {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
+	return nil
+}
+
+// skipProjectDir reports whether a directory of the project is left out of
+// the staging copy and its comparison
+func skipProjectDir(rel string) bool {
+	return rel == ".git" || rel == filepath.Join("cmd", ".fabrica-codegen")
+}
+
+// copyProject copies the files of a project to dst
+func copyProject(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir() && skipProjectDir(rel):
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
+
+// projectFiles returns the regular files of a project, relative to dir
+func projectFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && skipProjectDir(rel) {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			files[rel] = true
+		}
+		return nil
+	})
+	return files, err
+}
+
+// compareTrees returns the changes turning the files of the project into
+// those of the staging copy, sorted by path. normalize, when not nil,
+// rewrites both versions of each file before they're compared.
+func compareTrees(project, stage string, normalize func([]byte) []byte) ([]codegen.FileChange, error) {
+	oldFiles, err := projectFiles(project)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := projectFiles(stage)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(newFiles))
+	for path := range newFiles {
+		paths = append(paths, path)
+	}
+	for path := range oldFiles {
+		if !newFiles[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []codegen.FileChange
+	for _, path := range paths {
+		var old, new []byte
+		if oldFiles[path] {
+			if old, err = os.ReadFile(filepath.Join(project, path)); err != nil {
+				return nil, err
+			}
+		}
+		if newFiles[path] {
+			if new, err = os.ReadFile(filepath.Join(stage, path)); err != nil {
+				return nil, err
+			}
+		}
+		if normalize != nil {
+			old, new = normalize(old), normalize(new)
+		}
+		if change, ok := codegen.CompareFiles(filepath.ToSlash(path), old, new, oldFiles[path], newFiles[path]); ok {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// templateOverrides returns the templates in templates/ overriding
+// embedded ones
+func templateOverrides() []string {
+	var overrides []string
+	_ = filepath.WalkDir("templates", func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".tmpl") {
+			overrides = append(overrides, filepath.ToSlash(path))
+		}
+		return nil
+	})
+	return overrides
+}
+
+// goModule is a module version in go.mod
+type goModule struct {
+	Path    string
+	Version string
+}
+
+// goMod is the part of a go.mod read by upgrade, as 'go mod edit -json'
+// prints it
+type goMod struct {
+	Require []goModule
+	Replace []struct {
+		Old goModule
+		New goModule
+	}
+}
+
+// readGoMod reads the go.mod of dir
+func readGoMod(dir string) (*goMod, error) {
+	cmd := exec.Command("go", "mod", "edit", "-json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	var mod goMod
+	if err := json.Unmarshal(output, &mod); err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	return &mod, nil
+}
+
+// fabrica returns the version of Fabrica the module requires, and what
+// replaces it, if anything
+func (m *goMod) fabrica() (version, replacement string) {
+	for _, req := range m.Require {
+		if req.Path == fabricaModule {
+			version = req.Version
+		}
+	}
+	for _, rep := range m.Replace {
+		if rep.Old.Path == fabricaModule {
+			replacement = rep.New.Path
+			if rep.New.Version != "" {
+				replacement += "@" + rep.New.Version
+			}
+		}
+	}
+	return version, replacement
+}
+
+// stageReplacements points the replacements of go.mod by relative
+// directories, which the staging copy doesn't have beside it, at the
+// directories of the project's
+func (m *goMod) stageReplacements(project, stage string) error {
+	for _, rep := range m.Replace {
+		if rep.New.Version != "" || filepath.IsAbs(rep.New.Path) {
+			continue
+		}
+		old := rep.Old.Path
+		if rep.Old.Version != "" {
+			old += "@" + rep.Old.Version
+		}
+		cmd := exec.Command("go", "mod", "edit", "-replace", old+"="+filepath.Join(project, rep.New.Path))
+		cmd.Dir = stage
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to stage the replacement of %s: %w\n%s", rep.Old.Path, err, output)
+		}
+	}
+	return nil
+}
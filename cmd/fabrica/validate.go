@@ -0,0 +1,270 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/codegen"
+	"github.com/openchami/fabrica/pkg/versioning"
+	"github.com/spf13/cobra"
+)
+
+// projectIssue is a problem found by fabrica validate in the sources of a
+// project, before its resources are loaded
+type projectIssue struct {
+	where   string // File or resource the problem is in
+	problem string // What is wrong
+	fix     string // How to fix it
+	warning bool   // Whether generated code works regardless
+	blocker bool   // Whether the resources can't be loaded until it's fixed
+}
+
+func newValidateCommand() *cobra.Command {
+	var debug bool
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check resources and project layout before generation",
+		Long: `Check the project for problems that would fail 'fabrica generate' or the
+generated server, and explain how to fix each one:
+
+  - .fabrica.yaml, go.mod and pkg/resources missing or invalid
+  - resource definitions (YAML) that don't load
+  - resources without Spec or Status structs, or with fields that enabled
+    features need missing
+  - kinds sharing a name or plural
+  - RegisterResourcePrefix calls registering a kind or prefix twice, invalid
+    prefixes, and resources without a prefix
+  - validate tags that can't be evaluated
+  - schema versions that are invalid, duplicated, not served, or break
+    compatibility without acknowledging it (see 'fabrica check-compat')
+
+The command exits non-zero when it finds problems other than warnings.`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			fmt.Println("🔍 Validating project...")
+
+			issues, modulePath := checkProjectSources()
+			failed, blocked := false, false
+			for _, issue := range issues {
+				printIssue(issue.where+": "+issue.problem, issue.fix, issue.warning)
+				failed = failed || !issue.warning
+				blocked = blocked || issue.blocker
+			}
+			if blocked {
+				return fmt.Errorf("validation failed: fix the problems above so the resources can be loaded and checked")
+			}
+
+			if _, err := os.Stat("pkg/resources/register_generated.go"); os.IsNotExist(err) {
+				if err := generateRegistrationFile(debug); err != nil {
+					return fmt.Errorf("failed to generate registration file: %w", err)
+				}
+			}
+			if err := generateCodeWithRunner(modulePath, ".", "validate", false, false, false, false, debug, false); err != nil {
+				return fmt.Errorf("validation failed: %w", err)
+			}
+			if failed {
+				return fmt.Errorf("validation failed")
+			}
+
+			fmt.Println("✅ Project is valid")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&debug, "debug", false, "Show detailed debug output")
+
+	return cmd
+}
+
+// printIssue prints a problem and how to fix it
+func printIssue(problem, fix string, warning bool) {
+	mark := "❌"
+	if warning {
+		mark = "⚠️ "
+	}
+	fmt.Printf("  %s %s\n", mark, problem)
+	if fix != "" {
+		fmt.Printf("     → %s\n", fix)
+	}
+}
+
+// checkProjectSources checks the configuration, module and resource sources
+// of the project in the current directory, returning the problems found and
+// the module path
+func checkProjectSources() ([]projectIssue, string) {
+	var issues []projectIssue
+
+	if config, err := LoadConfig(""); err != nil {
+		issues = append(issues, projectIssue{where: ConfigFileName, problem: err.Error(),
+			fix: "run 'fabrica init', or fix the YAML"})
+	} else if err := ValidateConfig(config); err != nil {
+		issues = append(issues, projectIssue{where: ConfigFileName, problem: err.Error(),
+			fix: "correct the setting named in the error"})
+	}
+
+	modulePath, err := getModulePath()
+	if err != nil {
+		issues = append(issues, projectIssue{where: "go.mod", problem: err.Error(),
+			fix: "run the command in the project's root directory, or 'go mod init <module>'", blocker: true})
+	}
+
+	definitions, err := discoverDefinitions()
+	if err != nil {
+		issues = append(issues, projectIssue{where: "pkg/resources", problem: err.Error(), blocker: true})
+	}
+	for _, path := range definitions {
+		if _, err := codegen.LoadResourceDefinition(path); err != nil {
+			issues = append(issues, projectIssue{where: path, problem: err.Error(),
+				fix: "correct the definition; 'fabrica generate' writes no types for it until then"})
+		}
+	}
+
+	resources, err := discoverResources()
+	if err != nil {
+		issues = append(issues, projectIssue{where: "pkg/resources", problem: err.Error(), blocker: true})
+	} else if len(resources) == 0 {
+		issues = append(issues, projectIssue{where: "pkg/resources", problem: "no resources found",
+			fix: "run 'fabrica add resource <name>'", blocker: true})
+	}
+
+	issues = append(issues, checkResourcePrefixes(resources)...)
+	issues = append(issues, checkSchemaVersions()...)
+	return issues, modulePath
+}
+
+// prefixRegistration is a resource.RegisterResourcePrefix call with
+// literal arguments
+type prefixRegistration struct {
+	kind, prefix, file string
+}
+
+// checkResourcePrefixes checks the RegisterResourcePrefix calls in
+// pkg/resources, which panic when the server starts if a kind or prefix is
+// registered twice or a prefix is invalid
+func checkResourcePrefixes(resources []string) []projectIssue {
+	var registrations []prefixRegistration
+	dynamic := false // Whether some calls register kinds not known until run
+	_ = filepath.Walk(filepath.Join("pkg", "resources"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil // Skip files that don't parse
+		}
+		ast.Inspect(node, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "RegisterResourcePrefix" {
+				return true
+			}
+			if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != "resource" {
+				return true
+			}
+			kind, kindOK := stringLiteral(call.Args[0])
+			prefix, prefixOK := stringLiteral(call.Args[1])
+			if kindOK && prefixOK {
+				registrations = append(registrations, prefixRegistration{kind, prefix, path})
+			} else {
+				dynamic = true
+			}
+			return true
+		})
+		return nil
+	})
+
+	var issues []projectIssue
+	kinds := make(map[string]prefixRegistration)
+	prefixes := make(map[string]prefixRegistration)
+	for _, r := range registrations {
+		if r.prefix == "" || strings.IndexFunc(r.prefix, func(c rune) bool { return (c < 'a' || c > 'z') && (c < '0' || c > '9') }) >= 0 {
+			issues = append(issues, projectIssue{where: r.file, problem: fmt.Sprintf("prefix %q of %s may only have lowercase letters and digits", r.prefix, r.kind),
+				fix: fmt.Sprintf("use e.g. %q", strings.ToLower(r.kind[:min(3, len(r.kind))])), blocker: true})
+		}
+		if other, ok := kinds[r.kind]; ok {
+			issues = append(issues, projectIssue{where: r.file, problem: fmt.Sprintf("%s is registered with prefix %q here and %q in %s", r.kind, r.prefix, other.prefix, other.file),
+				fix: "remove one of the RegisterResourcePrefix calls", blocker: true})
+		}
+		if other, ok := prefixes[r.prefix]; ok && other.kind != r.kind {
+			issues = append(issues, projectIssue{where: r.file, problem: fmt.Sprintf("prefix %q of %s is also the prefix of %s in %s", r.prefix, r.kind, other.kind, other.file),
+				fix: "give each kind its own prefix; UIDs such as dev-1a2b3c4d name their kind by it", blocker: true})
+		}
+		kinds[r.kind] = r
+		prefixes[r.prefix] = r
+	}
+
+	known := make(map[string]bool)
+	for _, kind := range resources {
+		known[kind] = true
+		if _, ok := kinds[kind]; !ok {
+			issues = append(issues, projectIssue{where: kind, problem: "has no prefix registered with resource.RegisterResourcePrefix, so creating one fails",
+				fix:     fmt.Sprintf("call resource.RegisterResourcePrefix(%q, %q) in an init function of its package", kind, strings.ToLower(kind[:min(3, len(kind))])),
+				warning: dynamic})
+		}
+	}
+	for _, r := range registrations {
+		if !known[r.kind] {
+			issues = append(issues, projectIssue{where: r.file, problem: fmt.Sprintf("registers a prefix for %s, which isn't a resource", r.kind),
+				fix: "check the kind's spelling, or remove the call", warning: true})
+		}
+	}
+	return issues
+}
+
+// stringLiteral returns the value of a string literal expression
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// checkSchemaVersions checks the schema version types of pkg/resources,
+// which fail loading the resources when invalid or duplicated
+func checkSchemaVersions() []projectIssue {
+	versions, err := discoverSchemaVersions()
+	if err != nil {
+		return []projectIssue{{where: "pkg/resources", problem: err.Error(),
+			fix: "move the version type into a package of its resource, or below it", blocker: true}}
+	}
+
+	var issues []projectIssue
+	seen := make(map[string]schemaVersionType) // resource/version -> type
+	for _, v := range versions {
+		where := fmt.Sprintf("pkg/resources/%s: %s", v.Dir, v.Type)
+		if err := versioning.ValidateVersion(v.Version); err != nil {
+			issues = append(issues, projectIssue{where: where, problem: err.Error(), blocker: true,
+				fix: "mark it with a version such as +fabrica:version=v2 or +fabrica:version=v2beta1"})
+			continue
+		}
+		if v.Version == storedVersion {
+			issues = append(issues, projectIssue{where: where, problem: fmt.Sprintf("declares %s, the version of %s itself", storedVersion, v.Resource), blocker: true,
+				fix: "mark it with a later version"})
+			continue
+		}
+		key := v.Resource + "/" + v.Version
+		if other, ok := seen[key]; ok {
+			issues = append(issues, projectIssue{where: where, problem: fmt.Sprintf("declares %s of %s, as %s in pkg/resources/%s does", v.Version, v.Resource, other.Type, other.Dir), blocker: true,
+				fix: "remove one of the types, or mark it with another version"})
+			continue
+		}
+		seen[key] = v
+	}
+	return issues
+}
@@ -0,0 +1,101 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package admin provides the profiling and runtime debug endpoints of
+// generated servers.
+//
+// The endpoints are meant for operators investigating a running service, so
+// they are served by a separate admin server rather than the API router. Bind
+// it to a loopback or internal address: profiles and variables expose
+// internals of the process and are never authenticated.
+//
+// Endpoints:
+//
+//	GET /debug/pprof/           index of the runtime profiles
+//	GET /debug/pprof/profile    CPU profile (?seconds=30)
+//	GET /debug/pprof/trace      execution trace (?seconds=5)
+//	GET /debug/pprof/{profile}  heap, goroutine, allocs, block, mutex, threadcreate
+//	GET /debug/vars             expvar variables (memstats, cmdline, response_cache, ...)
+//	GET /debug/buildinfo        module versions and build settings of the binary
+//
+// Usage:
+//
+//	srv := admin.NewServer("127.0.0.1:6060")
+//	go func() {
+//	    if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+//	        log.Printf("admin server failed: %v", err)
+//	    }
+//	}()
+//	defer srv.Shutdown(ctx)
+//
+// Profiles are then collected with the standard tooling:
+//
+//	go tool pprof http://127.0.0.1:6060/debug/pprof/heap
+package admin
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"time"
+)
+
+// Handler returns the admin endpoints.
+//
+// Returns:
+//   - http.Handler: Serves /debug/pprof/, /debug/vars and /debug/buildinfo
+//
+// Example:
+//
+//	// Serve the endpoints on an existing internal listener
+//	internalMux.Handle("/debug/", admin.Handler())
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/buildinfo", buildInfo)
+
+	return mux
+}
+
+// NewServer creates the admin server listening on addr.
+//
+// The server has no write timeout, since CPU profiles and traces stream for
+// as long as requested.
+//
+// Parameters:
+//   - addr: Listen address (e.g., "127.0.0.1:6060")
+//
+// Returns:
+//   - *http.Server: Server serving Handler, not yet started
+//
+// Example:
+//
+//	srv := admin.NewServer(fmt.Sprintf("%s:%d", config.AdminHost, config.AdminPort))
+//	go srv.ListenAndServe()
+func NewServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+}
+
+// buildInfo writes the build information embedded in the binary
+func buildInfo(w http.ResponseWriter, r *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		http.Error(w, "build information is not available", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(info.String())) //nolint:errcheck
+}
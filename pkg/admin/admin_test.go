@@ -0,0 +1,61 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	expvar.NewString("admin_test").Set("published")
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/debug/pprof/", http.StatusOK, "goroutine"},
+		{"/debug/pprof/goroutine?debug=1", http.StatusOK, "goroutine profile"},
+		{"/debug/pprof/cmdline", http.StatusOK, ""},
+		{"/debug/vars", http.StatusOK, `"admin_test": "published"`},
+		{"/debug/nothing", http.StatusNotFound, ""},
+		{"/devices", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", tt.path, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("GET %s: %v", tt.path, err)
+		}
+
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("GET %s status = %d, want %d", tt.path, resp.StatusCode, tt.wantStatus)
+		}
+		if !strings.Contains(string(body), tt.wantBody) {
+			t.Errorf("GET %s body doesn't contain %q:\n%s", tt.path, tt.wantBody, string(body))
+		}
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	srv := NewServer("127.0.0.1:6060")
+	if srv.Addr != "127.0.0.1:6060" || srv.Handler == nil {
+		t.Errorf("unexpected server: %+v", srv)
+	}
+	if srv.WriteTimeout != 0 {
+		t.Error("admin server must not cut off streaming profiles")
+	}
+}
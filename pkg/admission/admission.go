@@ -0,0 +1,186 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package admission runs policy hooks on resources before they are saved, in
+// the spirit of Kubernetes admission controllers.
+//
+// Hooks are registered per resource kind and phase:
+//   - Mutating hooks run first and may change the resource, e.g. to apply
+//     defaults or add labels.
+//   - Validating hooks run after the resource has passed validation and
+//     may only accept or reject it.
+//
+// Generated create and update handlers run the mutating hooks, validate the
+// resource, then run the validating hooks. A hook rejects a request by
+// returning an error; errors built with Deny are reported to clients as a
+// denial (403 Forbidden), any other error as a failure of the hook.
+//
+// Usage:
+//
+//	chain := admission.NewChain()
+//	chain.Register("Device", admission.Mutating, func(ctx context.Context, req *admission.Request) error {
+//	    device := req.Object.(*device.Device)
+//	    device.SetLabel("team", "infra")
+//	    return nil
+//	})
+//	chain.Register(admission.AllKinds, admission.Validating, func(ctx context.Context, req *admission.Request) error {
+//	    if req.Operation == admission.Update && req.Meta.Labels["frozen"] == "true" {
+//	        return admission.Deny("%s %s is frozen", req.Kind, req.Meta.Name)
+//	    }
+//	    return nil
+//	})
+//
+//	err := chain.Run(ctx, admission.Mutating, req)
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+// Phase is the stage of admission a hook runs in.
+type Phase string
+
+// Admission phases, in the order they run
+const (
+	// Mutating hooks may modify the resource before it is validated
+	Mutating Phase = "Mutating"
+	// Validating hooks accept or reject the validated resource
+	Validating Phase = "Validating"
+)
+
+// Operation is the kind of change being admitted.
+type Operation string
+
+// Admitted operations
+const (
+	// Create admits a new resource
+	Create Operation = "CREATE"
+	// Update admits a changed spec (PUT, PATCH and server-side apply)
+	Update Operation = "UPDATE"
+)
+
+// AllKinds registers a hook for every resource kind.
+const AllKinds = "*"
+
+// Request describes a change being admitted.
+type Request struct {
+	// Kind is the resource kind (e.g., "Device")
+	Kind string
+
+	// Operation is Create or Update
+	Operation Operation
+
+	// Meta is the metadata of the resource being admitted
+	Meta *resource.Metadata
+
+	// Object is a pointer to the resource being admitted. Mutating hooks may
+	// modify it; validating hooks must not.
+	Object interface{}
+
+	// OldObject is the stored resource for updates, nil for creates
+	OldObject interface{}
+
+	// DryRun is true when the change won't be saved. Hooks with side
+	// effects should skip them.
+	DryRun bool
+}
+
+// Hook admits or rejects a request.
+// Returning nil admits it; the error of a rejected request is reported to the client.
+type Hook func(ctx context.Context, req *Request) error
+
+// DeniedError is returned by hooks that reject a request on policy grounds.
+type DeniedError struct {
+	Reason string
+}
+
+// Error returns the denial reason.
+func (e *DeniedError) Error() string {
+	return "admission denied: " + e.Reason
+}
+
+// Deny returns a *DeniedError with a formatted reason.
+//
+// Example:
+//
+//	return admission.Deny("rack %s is full", rackName)
+func Deny(format string, args ...interface{}) error {
+	return &DeniedError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// IsDenied reports whether err is (or wraps) a *DeniedError.
+func IsDenied(err error) bool {
+	var denied *DeniedError
+	return errors.As(err, &denied)
+}
+
+// Chain holds the hooks registered for each kind and phase.
+// It is safe for concurrent use.
+type Chain struct {
+	mu    sync.RWMutex
+	hooks map[Phase]map[string][]Hook
+}
+
+// NewChain returns an empty chain.
+func NewChain() *Chain {
+	return &Chain{hooks: make(map[Phase]map[string][]Hook)}
+}
+
+// Register adds a hook for a kind (or AllKinds) and phase.
+// Hooks of a phase run in registration order, with AllKinds hooks first.
+//
+// Panics on an unknown phase or a nil hook, as registration happens at startup.
+func (c *Chain) Register(kind string, phase Phase, hook Hook) {
+	if phase != Mutating && phase != Validating {
+		panic(fmt.Sprintf("admission: unknown phase %q", phase))
+	}
+	if hook == nil {
+		panic("admission: nil hook")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hooks[phase] == nil {
+		c.hooks[phase] = make(map[string][]Hook)
+	}
+	c.hooks[phase][kind] = append(c.hooks[phase][kind], hook)
+}
+
+// Has reports whether any hook is registered for a kind and phase.
+func (c *Chain) Has(kind string, phase Phase) bool {
+	return len(c.hooksFor(kind, phase)) > 0
+}
+
+// Run runs the hooks of a phase registered for req.Kind, stopping at the
+// first error.
+//
+// Returns:
+//   - error: nil if every hook admitted the request, otherwise the first
+//     hook error, wrapped with the kind and phase
+func (c *Chain) Run(ctx context.Context, phase Phase, req *Request) error {
+	for _, hook := range c.hooksFor(req.Kind, phase) {
+		if err := hook(ctx, req); err != nil {
+			return fmt.Errorf("%s admission of %s: %w", phase, req.Kind, err)
+		}
+	}
+	return nil
+}
+
+// hooksFor returns a snapshot of the hooks to run for kind
+func (c *Chain) hooksFor(kind string, phase Phase) []Hook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	byKind := c.hooks[phase]
+	hooks := make([]Hook, 0, len(byKind[AllKinds])+len(byKind[kind]))
+	hooks = append(hooks, byKind[AllKinds]...)
+	if kind != AllKinds {
+		hooks = append(hooks, byKind[kind]...)
+	}
+	return hooks
+}
@@ -0,0 +1,104 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package admission
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+func TestChain_RunOrder(t *testing.T) {
+	c := NewChain()
+	var calls []string
+	record := func(name string) Hook {
+		return func(ctx context.Context, req *Request) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	c.Register("Device", Mutating, record("device-1"))
+	c.Register(AllKinds, Mutating, record("all"))
+	c.Register("Device", Mutating, record("device-2"))
+	c.Register("Rack", Mutating, record("rack"))
+	c.Register("Device", Validating, record("validating"))
+
+	if err := c.Run(context.Background(), Mutating, &Request{Kind: "Device"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if want := []string{"all", "device-1", "device-2"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected hooks %v, got %v", want, calls)
+	}
+	if !c.Has("Device", Validating) || c.Has("Rack", Validating) {
+		t.Error("Has reported the wrong hooks")
+	}
+}
+
+func TestChain_MutatesAndDenies(t *testing.T) {
+	c := NewChain()
+	c.Register("Device", Mutating, func(ctx context.Context, req *Request) error {
+		if req.Meta.Labels == nil {
+			req.Meta.Labels = map[string]string{}
+		}
+		req.Meta.Labels["team"] = "infra"
+		return nil
+	})
+	c.Register("Device", Validating, func(ctx context.Context, req *Request) error {
+		if req.Operation == Update && req.Meta.Labels["frozen"] == "true" {
+			return Deny("%s %s is frozen", req.Kind, req.Meta.Name)
+		}
+		return nil
+	})
+	later := false
+	c.Register("Device", Validating, func(ctx context.Context, req *Request) error {
+		later = true
+		return nil
+	})
+
+	meta := &resource.Metadata{Name: "node-1"}
+	req := &Request{Kind: "Device", Operation: Create, Meta: meta}
+	if err := c.Run(context.Background(), Mutating, req); err != nil {
+		t.Fatal(err)
+	}
+	if meta.Labels["team"] != "infra" {
+		t.Errorf("Expected the mutating hook to label the resource, got %v", meta.Labels)
+	}
+
+	meta.Labels["frozen"] = "true"
+	req.Operation = Update
+	err := c.Run(context.Background(), Validating, req)
+	if !IsDenied(err) || !strings.Contains(err.Error(), "Device node-1 is frozen") {
+		t.Fatalf("Expected a denial, got %v", err)
+	}
+	if later {
+		t.Error("Expected hooks after a rejection not to run")
+	}
+}
+
+func TestChain_HookFailure(t *testing.T) {
+	c := NewChain()
+	boom := errors.New("policy service unavailable")
+	c.Register(AllKinds, Validating, func(ctx context.Context, req *Request) error {
+		return boom
+	})
+
+	err := c.Run(context.Background(), Validating, &Request{Kind: "Device"})
+	if !errors.Is(err, boom) || IsDenied(err) {
+		t.Errorf("Expected the hook error without a denial, got %v", err)
+	}
+}
+
+func TestChain_RegisterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Register to panic on an unknown phase")
+		}
+	}()
+	NewChain().Register("Device", Phase("Later"), func(ctx context.Context, req *Request) error { return nil })
+}
@@ -0,0 +1,323 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package apikey authenticates machine-to-machine callers with API keys.
+//
+// An administrator mints a key with a name, the subject it acts as and its
+// scopes; the secret is returned once and only its SHA-256 hash is kept.
+// Callers send it in the X-API-Key header:
+//
+//	manager := apikey.NewManager(nil) // in-memory only
+//	secret, key, err := manager.Mint(ctx, "inventory-sync", apikey.APIKeySpec{Scopes: []string{"viewer"}})
+//
+//	r.Use(manager.Middleware)
+//	// X-API-Key: fab_key-1a2b3c4d_...
+//
+// Authenticated requests carry auth.Claims like JWT-authenticated ones: the
+// key's subject, and its scopes in the "roles" and "scope" claims, so RBAC
+// policies grant keys permissions through their scopes.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/auth"
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/logging"
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Kind is the resource kind used for API keys.
+const Kind = "APIKey"
+
+// Header is the request header carrying API keys.
+const Header = "X-API-Key"
+
+// secretPrefix starts every secret, so leaked keys are easy to recognize
+const secretPrefix = "fab"
+
+// ErrInvalidKey is returned for malformed, unknown, expired or revoked keys.
+var ErrInvalidKey = errors.New("invalid API key")
+
+// APIKeySpec defines what an API key may do.
+//
+//nolint:revive // "APIKeySpec" name is intentional; matches generated <Kind>Spec naming
+type APIKeySpec struct {
+	// Subject is the identity of callers using the key (default
+	// "apikey:<name>")
+	Subject string `json:"subject,omitempty" yaml:"subject,omitempty"`
+
+	// Scopes are the key's permissions, also its roles under RBAC
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+
+	// ExpiresAt is when the key stops working; nil never expires
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+}
+
+// APIKeyStatus reports the state of an API key.
+//
+//nolint:revive // "APIKeyStatus" name is intentional; matches generated <Kind>Status naming
+type APIKeyStatus struct {
+	// Revoked reports whether the key was revoked
+	Revoked bool `json:"revoked" yaml:"revoked"`
+
+	// RevokedAt is when the key was revoked
+	RevokedAt *time.Time `json:"revokedAt,omitempty" yaml:"revokedAt,omitempty"`
+}
+
+// APIKey is an API key, without its secret.
+type APIKey struct {
+	resource.Resource
+	Spec   APIKeySpec   `json:"spec" yaml:"spec"`
+	Status APIKeyStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Subject returns the identity of callers using the key.
+func (k *APIKey) Subject() string {
+	if k.Spec.Subject != "" {
+		return k.Spec.Subject
+	}
+	return "apikey:" + k.GetName()
+}
+
+// Claims returns the claims of requests authenticated with the key.
+func (k *APIKey) Claims() *auth.Claims {
+	scopes := make([]interface{}, len(k.Spec.Scopes))
+	for i, s := range k.Spec.Scopes {
+		scopes[i] = s
+	}
+	return &auth.Claims{
+		Subject: k.Subject(),
+		ID:      k.GetUID(),
+		Raw: map[string]interface{}{
+			"sub":    k.Subject(),
+			"apikey": k.GetUID(),
+			"roles":  scopes,
+			"scope":  strings.Join(k.Spec.Scopes, " "),
+		},
+	}
+}
+
+// stored is an API key as persisted: with the hash of its secret
+type stored struct {
+	*APIKey
+	Hash string `json:"hash"`
+}
+
+// Manager mints, verifies and revokes API keys.
+//
+// Keys are kept in memory and, when a storage backend is supplied, written
+// through to the backend under the "APIKey" kind so they survive restarts.
+type Manager struct {
+	mu      sync.RWMutex
+	keys    map[string]stored
+	backend storage.StorageBackend
+	now     func() time.Time
+}
+
+// NewManager creates an API key manager.
+//
+// Parameters:
+//   - backend: Optional storage backend for persistence; nil keeps keys in memory only
+//
+// Returns:
+//   - *Manager: A manager with any keys already persisted in backend loaded
+func NewManager(backend storage.StorageBackend) *Manager {
+	m := &Manager{
+		keys:    make(map[string]stored),
+		backend: backend,
+		now:     time.Now,
+	}
+	if backend != nil {
+		if raw, err := backend.LoadAll(context.Background(), Kind); err == nil {
+			for _, data := range raw {
+				var s stored
+				if err := json.Unmarshal(data, &s); err == nil && s.APIKey != nil && s.GetUID() != "" && s.Hash != "" {
+					m.keys[s.GetUID()] = s
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Mint creates an API key.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - name: Name identifying the key (e.g., the calling service)
+//   - spec: Subject, scopes and expiry of the key
+//
+// Returns:
+//   - string: The secret to hand to the caller; it can't be retrieved later
+//   - *APIKey: The key
+//   - error: An error if the name is empty or the key can't be saved
+func (m *Manager) Mint(ctx context.Context, name string, spec APIKeySpec) (string, *APIKey, error) {
+	if name == "" {
+		return "", nil, fmt.Errorf("API key name is required")
+	}
+	if spec.ExpiresAt != nil && !spec.ExpiresAt.After(m.now()) {
+		return "", nil, fmt.Errorf("API key expiresAt must be in the future")
+	}
+	uid, err := resource.GenerateUID("key")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key UID: %w", err)
+	}
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	secret := secretPrefix + "_" + uid + "_" + base64.RawURLEncoding.EncodeToString(random)
+
+	key := &APIKey{Spec: spec}
+	key.APIVersion = "v1"
+	key.Kind = Kind
+	key.Metadata.Initialize(name, uid)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := stored{APIKey: key, Hash: hash(secret)}
+	if err := m.persist(ctx, s); err != nil {
+		return "", nil, err
+	}
+	m.keys[uid] = s
+	return secret, key, nil
+}
+
+// Get returns the API key with the given UID.
+func (m *Manager) Get(uid string) (*APIKey, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.keys[uid]
+	return s.APIKey, ok
+}
+
+// List returns all API keys, including revoked ones, sorted by UID.
+func (m *Manager) List() []*APIKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*APIKey, 0, len(m.keys))
+	for _, s := range m.keys {
+		result = append(result, s.APIKey)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetUID() < result[j].GetUID()
+	})
+	return result
+}
+
+// Revoke revokes an API key. Revoked keys stay listed but no longer
+// authenticate.
+//
+// Returns storage.ErrNotFound if the key doesn't exist.
+func (m *Manager) Revoke(ctx context.Context, uid string) (*APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.keys[uid]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	if s.Status.Revoked {
+		return s.APIKey, nil
+	}
+	revoked := *s.APIKey
+	now := m.now()
+	revoked.Status = APIKeyStatus{Revoked: true, RevokedAt: &now}
+	revoked.Touch()
+	s.APIKey = &revoked
+	if err := m.persist(ctx, s); err != nil {
+		return nil, err
+	}
+	m.keys[uid] = s
+	return s.APIKey, nil
+}
+
+// Verify checks an API key secret.
+//
+// Returns:
+//   - *APIKey: The key
+//   - error: An error wrapping ErrInvalidKey if the secret is malformed,
+//     unknown, expired or revoked
+func (m *Manager) Verify(secret string) (*APIKey, error) {
+	parts := strings.SplitN(secret, "_", 3)
+	if len(parts) != 3 || parts[0] != secretPrefix {
+		return nil, fmt.Errorf("%w: malformed key", ErrInvalidKey)
+	}
+	m.mu.RLock()
+	s, ok := m.keys[parts[1]]
+	m.mu.RUnlock()
+	if !ok || subtle.ConstantTimeCompare([]byte(s.Hash), []byte(hash(secret))) != 1 {
+		return nil, fmt.Errorf("%w: unknown key", ErrInvalidKey)
+	}
+	if s.Status.Revoked {
+		return nil, fmt.Errorf("%w: key %s was revoked", ErrInvalidKey, s.GetUID())
+	}
+	if s.Spec.ExpiresAt != nil && m.now().After(*s.Spec.ExpiresAt) {
+		return nil, fmt.Errorf("%w: key %s expired at %s", ErrInvalidKey, s.GetUID(), s.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return s.APIKey, nil
+}
+
+// Middleware authenticates requests sending an X-API-Key header, adding the
+// key's claims to the request context (see auth.FromContext) and its
+// subject to the request logger. Requests with an invalid key are rejected
+// with 401; requests without the header pass through unauthenticated.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secret := r.Header.Get(Header)
+		if secret == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, err := m.Verify(secret)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("APIKey header=%q", Header))
+			w.Header().Set("Content-Type", errcode.ContentType)
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(errcode.NewProblem(http.StatusUnauthorized, errcode.Wrap(errcode.Unauthorized, err)))
+			return
+		}
+		ctx := auth.NewContext(r.Context(), key.Claims())
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("subject", key.Subject(), "apikey", key.GetUID()))
+		logging.AddRequestAttrs(ctx, "subject", key.Subject(), "apikey", key.GetUID())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// persist writes a key to the backend if one is configured. Callers hold m.mu.
+func (m *Manager) persist(ctx context.Context, s stored) error {
+	if m.backend == nil {
+		return nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	if err := m.backend.Save(ctx, Kind, s.GetUID(), data); err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+	return nil
+}
+
+// hash returns the hex SHA-256 hash of a secret
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
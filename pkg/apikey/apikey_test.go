@@ -0,0 +1,142 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/auth"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+func TestManager_MintVerifyRevoke(t *testing.T) {
+	ctx := context.Background()
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend failed: %v", err)
+	}
+	m := NewManager(backend)
+
+	secret, key, err := m.Mint(ctx, "inventory-sync", APIKeySpec{Scopes: []string{"viewer", "devices:write"}})
+	if err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, "fab_"+key.GetUID()+"_") {
+		t.Errorf("Unexpected secret format %q", secret)
+	}
+	got, err := m.Verify(secret)
+	if err != nil || got.GetUID() != key.GetUID() {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	claims := got.Claims()
+	if claims.Subject != "apikey:inventory-sync" || strings.Join(claims.Strings("roles"), ",") != "viewer,devices:write" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	// Keys and their hashes survive restarts, secrets aren't stored
+	reloaded := NewManager(backend)
+	if _, err := reloaded.Verify(secret); err != nil {
+		t.Errorf("Expected the reloaded key to verify, got %v", err)
+	}
+	raw, _ := backend.Load(ctx, Kind, key.GetUID())
+	if strings.Contains(string(raw), secret[len(secret)-20:]) || !strings.Contains(string(raw), `"hash"`) {
+		t.Errorf("Expected only the hash to be stored, got %s", raw)
+	}
+
+	tests := map[string]string{
+		"malformed":  "not-a-key",
+		"wrong":      secret[:len(secret)-2] + "xx",
+		"unknown id": "fab_key-00000000_" + strings.SplitN(secret, "_", 3)[2],
+	}
+	for name, s := range tests {
+		if _, err := m.Verify(s); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%s: expected ErrInvalidKey, got %v", name, err)
+		}
+	}
+
+	revoked, err := m.Revoke(ctx, key.GetUID())
+	if err != nil || !revoked.Status.Revoked || revoked.Status.RevokedAt == nil {
+		t.Fatalf("Revoke failed: %+v, %v", revoked, err)
+	}
+	if _, err := m.Verify(secret); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected a revoked key to fail, got %v", err)
+	}
+	if _, err := NewManager(backend).Verify(secret); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected the revocation to persist, got %v", err)
+	}
+	if _, err := m.Revoke(ctx, "key-missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if len(m.List()) != 1 {
+		t.Errorf("Expected revoked keys to stay listed, got %d", len(m.List()))
+	}
+}
+
+func TestManager_Expiry(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	expires := now.Add(time.Hour)
+	secret, _, err := m.Mint(ctx, "ci", APIKeySpec{Subject: "svc-ci", ExpiresAt: &expires})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Verify(secret); err != nil {
+		t.Errorf("Expected a valid key, got %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := m.Verify(secret); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected an expired key to fail, got %v", err)
+	}
+
+	past := now.Add(-time.Minute)
+	if _, _, err := m.Mint(ctx, "old", APIKeySpec{ExpiresAt: &past}); err == nil {
+		t.Error("Expected a key expiring in the past to be rejected")
+	}
+	if _, _, err := m.Mint(ctx, "", APIKeySpec{}); err == nil {
+		t.Error("Expected a key without a name to be rejected")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	m := NewManager(nil)
+	secret, _, err := m.Mint(context.Background(), "sync", APIKeySpec{Subject: "svc-sync"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seen string
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = auth.Subject(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	do := func(key string) int {
+		seen = ""
+		req := httptest.NewRequest(http.MethodGet, "/devices", nil)
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := do(secret); code != http.StatusNoContent || seen != "svc-sync" {
+		t.Errorf("Expected the request through as svc-sync, got %d as %q", code, seen)
+	}
+	if code := do("fab_key-1_bad"); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a bad key, got %d", code)
+	}
+	if code := do(""); code != http.StatusNoContent || seen != "" {
+		t.Errorf("Expected requests without a key through unauthenticated, got %d as %q", code, seen)
+	}
+}
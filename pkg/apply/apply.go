@@ -0,0 +1,415 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package apply implements server-side apply: declarative updates that track
+// which field manager owns which fields of a resource.
+//
+// A manager applies a partial configuration holding only the fields it cares
+// about. Apply merges it into the resource and records the applied fields as
+// owned by the manager in Metadata.ManagedFields. Two managers can then share
+// a resource without clobbering each other:
+//
+//   - Fields the manager applied before but leaves out now are removed, unless
+//     another manager owns them too.
+//   - Applying a different value to a field another manager owns is a
+//     conflict. The apply fails with a ConflictError unless it is forced, in
+//     which case the manager takes the field over.
+//   - Applying the value a field already has shares its ownership.
+//
+// Ordinary updates record the fields they change with Update, which takes
+// those fields from the other managers.
+//
+// Documents are JSON objects. Fields are dotted paths of leaf values; objects
+// are descended into, while arrays are owned as a whole.
+//
+// Usage:
+//
+//	result, err := apply.Apply(current, config, device.Metadata.ManagedFields, apply.Options{
+//	    Manager: "rack-controller",
+//	})
+//	var conflict *apply.ConflictError
+//	if errors.As(err, &conflict) {
+//	    // Retry with Force, or drop the conflicting fields
+//	}
+//	device.Metadata.ManagedFields = result.ManagedFields
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+// Options configure an apply.
+type Options struct {
+	// Manager is the name of the field manager; required
+	Manager string
+
+	// Force takes over conflicting fields instead of failing
+	Force bool
+}
+
+// Result is the outcome of an apply or update.
+type Result struct {
+	// Updated is the resulting document
+	Updated []byte
+
+	// ManagedFields is the new field ownership
+	ManagedFields []resource.ManagedFieldsEntry
+}
+
+// Conflict is a field owned by another manager with a different value.
+type Conflict struct {
+	Manager string `json:"manager"`
+	Field   string `json:"field"`
+}
+
+// ConflictError is returned by Apply when the configuration changes fields
+// owned by other managers.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+// Error implements error
+func (e *ConflictError) Error() string {
+	parts := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		parts[i] = fmt.Sprintf("%s (owned by %s)", c.Field, c.Manager)
+	}
+	return fmt.Sprintf("apply conflicts with %d field(s): %s", len(e.Conflicts), strings.Join(parts, ", "))
+}
+
+// Apply merges a configuration into a document on behalf of a field manager.
+//
+// Parameters:
+//   - current: The current document, a JSON object
+//   - config: The applied configuration, a JSON object with the fields the manager owns
+//   - managed: The current field ownership (Metadata.ManagedFields); not modified
+//   - opts: The field manager and whether to force conflicts
+//
+// Returns:
+//   - *Result: The merged document and the new field ownership
+//   - error: A *ConflictError for conflicts, or an error for invalid documents
+//
+// Example:
+//
+//	current := []byte(`{"spec":{"rack":"r1","bmc":{"address":"10.0.0.1"}}}`)
+//	config := []byte(`{"spec":{"bmc":{"address":"10.0.0.2"}}}`)
+//	result, err := apply.Apply(current, config, nil, apply.Options{Manager: "bmc-controller"})
+//	// result.Updated: {"spec":{"bmc":{"address":"10.0.0.2"},"rack":"r1"}}
+//	// result.ManagedFields: [{bmc-controller Apply ... [spec.bmc.address]}]
+func Apply(current, config []byte, managed []resource.ManagedFieldsEntry, opts Options) (*Result, error) {
+	if opts.Manager == "" {
+		return nil, fmt.Errorf("a field manager is required")
+	}
+	doc, err := decodeObject(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	cfg, err := decodeObject(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	entries := cloneEntries(managed)
+	applied := leafPaths(cfg, "")
+
+	// Find fields of other managers that the configuration changes
+	var conflicts []Conflict
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Manager == opts.Manager {
+			continue
+		}
+		kept := entry.Fields[:0]
+		for _, owned := range entry.Fields {
+			conflicting := false
+			for _, field := range applied {
+				if resource.FieldsOverlap(owned, field) && !reflect.DeepEqual(lookup(doc, field), lookup(cfg, field)) {
+					conflicting = true
+					conflicts = append(conflicts, Conflict{Manager: entry.Manager, Field: field})
+				}
+			}
+			if !conflicting {
+				kept = append(kept, owned)
+			}
+		}
+		entry.Fields = kept
+	}
+	if len(conflicts) > 0 && !opts.Force {
+		return nil, &ConflictError{Conflicts: dedupeConflicts(conflicts)}
+	}
+
+	// Remove the fields the manager stopped applying, unless they're shared
+	if previous := findEntry(entries, opts.Manager, resource.ManagedFieldsApply); previous != nil {
+		for _, field := range previous.Fields {
+			if containsField(applied, field) || ownedByOthers(entries, opts.Manager, field) {
+				continue
+			}
+			remove(doc, strings.Split(field, "."))
+		}
+	}
+
+	for _, field := range applied {
+		set(doc, strings.Split(field, "."), lookup(cfg, field))
+	}
+
+	entries = setEntry(entries, resource.ManagedFieldsEntry{
+		Manager:   opts.Manager,
+		Operation: resource.ManagedFieldsApply,
+		Time:      time.Now().UTC(),
+		Fields:    applied,
+	})
+	return encodeResult(doc, entries)
+}
+
+// Update records the fields changed between two versions of a document as
+// owned by a field manager, and removes them from the other managers.
+//
+// Parameters:
+//   - before: The document before the update, a JSON object
+//   - after: The document after the update, a JSON object
+//   - managed: The current field ownership (Metadata.ManagedFields); not modified
+//   - manager: Name of the field manager making the update
+//
+// Returns:
+//   - *Result: The after document unchanged and the new field ownership
+//   - error: If a document is invalid or the manager is empty
+func Update(before, after []byte, managed []resource.ManagedFieldsEntry, manager string) (*Result, error) {
+	if manager == "" {
+		return nil, fmt.Errorf("a field manager is required")
+	}
+	oldDoc, err := decodeObject(before)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	newDoc, err := decodeObject(after)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	var changed []string
+	for _, field := range mergeFields(leafPaths(oldDoc, ""), leafPaths(newDoc, "")) {
+		if !reflect.DeepEqual(lookup(oldDoc, field), lookup(newDoc, field)) {
+			changed = append(changed, field)
+		}
+	}
+	entries := cloneEntries(managed)
+	if len(changed) == 0 {
+		return encodeResult(newDoc, entries)
+	}
+
+	// Removed fields aren't owned by anyone afterwards
+	var owned []string
+	for _, field := range changed {
+		if lookup(newDoc, field) != nil {
+			owned = append(owned, field)
+		}
+	}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Manager == manager && entry.Operation == resource.ManagedFieldsUpdate {
+			continue
+		}
+		kept := entry.Fields[:0]
+		for _, field := range entry.Fields {
+			if !overlapsAny(changed, field) {
+				kept = append(kept, field)
+			}
+		}
+		entry.Fields = kept
+	}
+	if previous := findEntry(entries, manager, resource.ManagedFieldsUpdate); previous != nil {
+		for _, field := range previous.Fields {
+			if !overlapsAny(changed, field) {
+				owned = append(owned, field)
+			}
+		}
+	}
+	sort.Strings(owned)
+	entries = setEntry(entries, resource.ManagedFieldsEntry{
+		Manager:   manager,
+		Operation: resource.ManagedFieldsUpdate,
+		Time:      time.Now().UTC(),
+		Fields:    owned,
+	})
+	return encodeResult(newDoc, entries)
+}
+
+// decodeObject decodes a JSON object; null decodes to an empty object
+func decodeObject(data []byte) (map[string]interface{}, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+	return doc, nil
+}
+
+// encodeResult marshals the document and drops entries without fields
+func encodeResult(doc map[string]interface{}, entries []resource.ManagedFieldsEntry) (*Result, error) {
+	updated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
+	var kept []resource.ManagedFieldsEntry
+	for _, entry := range entries {
+		if len(entry.Fields) > 0 {
+			kept = append(kept, entry)
+		}
+	}
+	return &Result{Updated: updated, ManagedFields: kept}, nil
+}
+
+// leafPaths returns the sorted dotted paths of the leaf values of an object.
+// Empty objects hold no fields, so applying {} owns nothing.
+func leafPaths(doc map[string]interface{}, prefix string) []string {
+	var paths []string
+	for key, value := range doc {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if child, ok := value.(map[string]interface{}); ok {
+			paths = append(paths, leafPaths(child, path)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// lookup returns the value at a dotted path, or nil
+func lookup(doc map[string]interface{}, field string) interface{} {
+	var current interface{} = doc
+	for _, part := range strings.Split(field, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// set stores a value at a path, replacing non-object parents; nil removes it
+func set(doc map[string]interface{}, path []string, value interface{}) {
+	if value == nil {
+		remove(doc, path)
+		return
+	}
+	for _, part := range path[:len(path)-1] {
+		child, ok := doc[part].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			doc[part] = child
+		}
+		doc = child
+	}
+	doc[path[len(path)-1]] = value
+}
+
+// remove deletes the value at a path and any parents left empty
+func remove(doc map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(doc, path[0])
+		return
+	}
+	child, ok := doc[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+	remove(child, path[1:])
+	if len(child) == 0 {
+		delete(doc, path[0])
+	}
+}
+
+// cloneEntries deep-copies managed field entries
+func cloneEntries(entries []resource.ManagedFieldsEntry) []resource.ManagedFieldsEntry {
+	clone := make([]resource.ManagedFieldsEntry, len(entries))
+	for i, entry := range entries {
+		clone[i] = entry.Clone()
+	}
+	return clone
+}
+
+// findEntry returns the entry of a manager for an operation, or nil
+func findEntry(entries []resource.ManagedFieldsEntry, manager, operation string) *resource.ManagedFieldsEntry {
+	for i := range entries {
+		if entries[i].Manager == manager && entries[i].Operation == operation {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// setEntry replaces the entry of the same manager and operation, or appends it
+func setEntry(entries []resource.ManagedFieldsEntry, entry resource.ManagedFieldsEntry) []resource.ManagedFieldsEntry {
+	if existing := findEntry(entries, entry.Manager, entry.Operation); existing != nil {
+		*existing = entry
+		return entries
+	}
+	return append(entries, entry)
+}
+
+// ownedByOthers reports whether a manager other than the given one owns a field
+func ownedByOthers(entries []resource.ManagedFieldsEntry, manager, field string) bool {
+	for _, entry := range entries {
+		if entry.Manager != manager && overlapsAny(entry.Fields, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapsAny reports whether any of the fields overlaps the given field
+func overlapsAny(fields []string, field string) bool {
+	for _, f := range fields {
+		if resource.FieldsOverlap(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsField reports whether a sorted field list contains a field
+func containsField(fields []string, field string) bool {
+	i := sort.SearchStrings(fields, field)
+	return i < len(fields) && fields[i] == field
+}
+
+// mergeFields returns the sorted union of two sorted field lists
+func mergeFields(a, b []string) []string {
+	merged := append(append([]string{}, a...), b...)
+	sort.Strings(merged)
+	unique := merged[:0]
+	for i, field := range merged {
+		if i == 0 || field != merged[i-1] {
+			unique = append(unique, field)
+		}
+	}
+	return unique
+}
+
+// dedupeConflicts removes repeated conflicts, keeping the first of each
+func dedupeConflicts(conflicts []Conflict) []Conflict {
+	seen := make(map[Conflict]bool)
+	var unique []Conflict
+	for _, c := range conflicts {
+		if !seen[c] {
+			seen[c] = true
+			unique = append(unique, c)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,145 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package apply
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+// fieldsOf maps managers to their fields for comparison
+func fieldsOf(entries []resource.ManagedFieldsEntry) map[string][]string {
+	fields := make(map[string][]string)
+	for _, entry := range entries {
+		fields[entry.Manager+"/"+entry.Operation] = entry.Fields
+	}
+	return fields
+}
+
+func TestApply_SharedOwnership(t *testing.T) {
+	current := []byte(`{"spec":{"rack":"r1","bmc":{"address":"10.0.0.1","user":"root"}}}`)
+
+	first, err := Apply(current, []byte(`{"spec":{"rack":"r2"}}`), nil, Options{Manager: "rack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Apply(first.Updated, []byte(`{"spec":{"bmc":{"address":"10.0.0.2"}}}`), first.ManagedFields, Options{Manager: "bmc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"spec":{"bmc":{"address":"10.0.0.2","user":"root"},"rack":"r2"}}`
+	if string(second.Updated) != want {
+		t.Errorf("got %s\nwant %s", second.Updated, want)
+	}
+	wantFields := map[string][]string{"rack/Apply": {"spec.rack"}, "bmc/Apply": {"spec.bmc.address"}}
+	if got := fieldsOf(second.ManagedFields); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("got fields %v, want %v", got, wantFields)
+	}
+
+	// The same value doesn't conflict and shares the field
+	shared, err := Apply(second.Updated, []byte(`{"spec":{"rack":"r2"}}`), second.ManagedFields, Options{Manager: "bmc"})
+	if err != nil {
+		t.Fatalf("applying an equal value: %v", err)
+	}
+	if got := fieldsOf(shared.ManagedFields)["rack/Apply"]; !reflect.DeepEqual(got, []string{"spec.rack"}) {
+		t.Errorf("expected rack to keep spec.rack, got %v", got)
+	}
+}
+
+func TestApply_Conflicts(t *testing.T) {
+	current := []byte(`{"spec":{"rack":"r1"}}`)
+	owned, err := Apply(current, []byte(`{"spec":{"rack":"r1"}}`), nil, Options{Manager: "rack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Apply(owned.Updated, []byte(`{"spec":{"rack":"r9"}}`), owned.ManagedFields, Options{Manager: "other"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a ConflictError, got %v", err)
+	}
+	if want := []Conflict{{Manager: "rack", Field: "spec.rack"}}; !reflect.DeepEqual(conflict.Conflicts, want) {
+		t.Errorf("got conflicts %v, want %v", conflict.Conflicts, want)
+	}
+
+	forced, err := Apply(owned.Updated, []byte(`{"spec":{"rack":"r9"}}`), owned.ManagedFields, Options{Manager: "other", Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(forced.Updated) != `{"spec":{"rack":"r9"}}` {
+		t.Errorf("unexpected document %s", forced.Updated)
+	}
+	wantFields := map[string][]string{"other/Apply": {"spec.rack"}}
+	if got := fieldsOf(forced.ManagedFields); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("got fields %v, want %v", got, wantFields)
+	}
+}
+
+func TestApply_RemovesDroppedFields(t *testing.T) {
+	current := []byte(`{"spec":{}}`)
+	first, _ := Apply(current, []byte(`{"spec":{"a":1,"b":{"c":2}}}`), nil, Options{Manager: "m1"})
+	shared, _ := Apply(first.Updated, []byte(`{"spec":{"a":1}}`), first.ManagedFields, Options{Manager: "m2"})
+
+	// m1 drops both fields; a is still owned by m2
+	result, err := Apply(shared.Updated, []byte(`{"spec":{}}`), shared.ManagedFields, Options{Manager: "m1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Updated) != `{"spec":{"a":1}}` {
+		t.Errorf("unexpected document %s", result.Updated)
+	}
+	wantFields := map[string][]string{"m2/Apply": {"spec.a"}}
+	if got := fieldsOf(result.ManagedFields); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("got fields %v, want %v", got, wantFields)
+	}
+}
+
+func TestApply_Invalid(t *testing.T) {
+	if _, err := Apply([]byte(`{}`), []byte(`{}`), nil, Options{}); err == nil {
+		t.Error("expected an error without a manager")
+	}
+	if _, err := Apply([]byte(`{}`), []byte(`[1]`), nil, Options{Manager: "m"}); err == nil {
+		t.Error("expected an error for a configuration that isn't an object")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	applied, err := Apply([]byte(`{"spec":{"a":1,"b":2}}`), []byte(`{"spec":{"a":1,"b":2}}`), nil, Options{Manager: "ctrl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Update(applied.Updated, []byte(`{"spec":{"a":5,"b":2,"c":3}}`), applied.ManagedFields, "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFields := map[string][]string{"ctrl/Apply": {"spec.b"}, "cli/Update": {"spec.a", "spec.c"}}
+	if got := fieldsOf(result.ManagedFields); !reflect.DeepEqual(got, wantFields) {
+		t.Errorf("got fields %v, want %v", got, wantFields)
+	}
+
+	// An apply of the updated field now conflicts
+	_, err = Apply(result.Updated, []byte(`{"spec":{"a":1,"b":2}}`), result.ManagedFields, Options{Manager: "ctrl"})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Conflicts[0].Manager != "cli" {
+		t.Errorf("expected a conflict with cli, got %v", err)
+	}
+
+	// Removed fields are owned by no one
+	removed, err := Update(result.Updated, []byte(`{"spec":{"a":5,"b":2}}`), result.ManagedFields, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fieldsOf(removed.ManagedFields)["cli/Update"]; !reflect.DeepEqual(got, []string{"spec.a"}) {
+		t.Errorf("expected cli to keep spec.a, got %v", got)
+	}
+	if _, ok := fieldsOf(removed.ManagedFields)["other/Update"]; ok {
+		t.Error("expected no entry for a manager that only removed fields")
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package auth authenticates HTTP requests with JWT bearer tokens.
+//
+// An Authenticator verifies the signature of each token against the keys of
+// a JWKS endpoint (such as an OIDC provider's jwks_uri) or a static public
+// key, checks its expiry, issuer and audience, and puts its claims in the
+// request context:
+//
+//	a, err := auth.New(auth.Options{
+//		JWKSURL:  "https://idp.example.com/.well-known/jwks.json",
+//		Issuer:   "https://idp.example.com",
+//		Audience: "inventory",
+//	})
+//	r.Use(a.Middleware)
+//
+//	// In a handler
+//	claims, ok := auth.FromContext(r.Context())
+//
+// Requests without a valid token are rejected with 401 and a
+// WWW-Authenticate header, unless the Authenticator is non-enforcing, in
+// which case failures are only logged.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/logging"
+)
+
+// DefaultLeeway is the clock skew allowed when checking exp and nbf if
+// Options.Leeway is zero
+const DefaultLeeway = time.Minute
+
+// ErrMissingToken is returned for requests without a bearer token.
+var ErrMissingToken = errors.New("missing bearer token")
+
+// Options configures an Authenticator. One of JWKSURL, PublicKey or Keys is
+// required.
+type Options struct {
+	// JWKSURL is the URL of the JSON Web Key Set of the token issuer
+	JWKSURL string
+
+	// PublicKey is a PEM-encoded public key or certificate verifying tokens
+	// (static validation, without a JWKS endpoint)
+	PublicKey string
+
+	// Keys overrides JWKSURL and PublicKey with a custom key set
+	Keys KeySet
+
+	// Issuer is the required "iss" claim; empty accepts any issuer
+	Issuer string
+
+	// Audience must be listed in the "aud" claim; empty accepts any audience
+	Audience string
+
+	// Leeway is the clock skew allowed for exp and nbf (default DefaultLeeway)
+	Leeway time.Duration
+
+	// AllowMissingExpiry accepts tokens without an "exp" claim, which never
+	// expire; by default they are rejected
+	AllowMissingExpiry bool
+
+	// NonEnforcing logs authentication failures and lets the requests
+	// through without claims, for rolling out authentication gradually
+	NonEnforcing bool
+
+	// HTTPClient fetches the JWKS (default http.DefaultClient)
+	HTTPClient *http.Client
+
+	// RefreshInterval is how long JWKS keys are cached (default
+	// DefaultRefreshInterval)
+	RefreshInterval time.Duration
+}
+
+// Authenticator verifies bearer tokens.
+type Authenticator struct {
+	keys         KeySet
+	issuer       string
+	audience     string
+	leeway       time.Duration
+	nonEnforcing bool
+	allowNoExp   bool
+	now          func() time.Time
+}
+
+// New creates an Authenticator.
+//
+// Returns:
+//   - *Authenticator: The authenticator; JWKS keys are fetched on first use
+//   - error: An error if no key source is configured or PublicKey is invalid
+func New(opts Options) (*Authenticator, error) {
+	keys := opts.Keys
+	switch {
+	case keys != nil:
+	case opts.JWKSURL != "":
+		keys = NewRemoteKeySet(opts.JWKSURL, opts.HTTPClient, opts.RefreshInterval)
+	case opts.PublicKey != "":
+		pub, err := ParsePublicKey([]byte(opts.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		keys = StaticKeySet{{Public: pub}}
+	default:
+		return nil, errors.New("auth: a JWKS URL, public key or key set is required")
+	}
+	leeway := opts.Leeway
+	if leeway == 0 {
+		leeway = DefaultLeeway
+	}
+	return &Authenticator{
+		keys:         keys,
+		issuer:       opts.Issuer,
+		audience:     opts.Audience,
+		leeway:       leeway,
+		nonEnforcing: opts.NonEnforcing,
+		allowNoExp:   opts.AllowMissingExpiry,
+		now:          time.Now,
+	}, nil
+}
+
+// Verify checks a token's signature and claims.
+//
+// Returns:
+//   - *Claims: The token's claims
+//   - error: An error wrapping ErrInvalidToken if the token is malformed,
+//     badly signed, without exp (unless allowed), expired, not yet valid or
+//     for another issuer or audience, or the error of fetching keys
+func (a *Authenticator) Verify(ctx context.Context, token string) (*Claims, error) {
+	jws, alg, kid, err := parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := a.keys.Keys(ctx, kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get verification keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: unknown key ID %q", ErrInvalidToken, kid)
+	}
+	var payload []byte
+	verified := false
+	for _, k := range keys {
+		if payload, err = verifySignature(jws, alg, k.Public); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+	}
+	raw, err := decodeClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := newClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+	now := a.now()
+	if claims.ExpiresAt.IsZero() && !a.allowNoExp {
+		return nil, fmt.Errorf("%w: token has no exp claim", ErrInvalidToken)
+	}
+	if !claims.ExpiresAt.IsZero() && now.After(claims.ExpiresAt.Add(a.leeway)) {
+		return nil, fmt.Errorf("%w: token expired at %s", ErrInvalidToken, claims.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if !claims.NotBefore.IsZero() && now.Add(a.leeway).Before(claims.NotBefore) {
+		return nil, fmt.Errorf("%w: token not valid before %s", ErrInvalidToken, claims.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	if a.audience != "" && !slices.Contains(claims.Audience, a.audience) {
+		return nil, fmt.Errorf("%w: token is not for audience %q", ErrInvalidToken, a.audience)
+	}
+	return claims, nil
+}
+
+// Middleware authenticates requests by their Authorization: Bearer header,
+// adding the token's claims to the request context (see FromContext) and
+// the subject to the request logger.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := a.authenticate(r)
+		if err != nil {
+			if a.nonEnforcing {
+				logging.FromContext(r.Context()).Warn("authentication failed (non-enforcing)", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			unauthorized(w, r, err)
+			return
+		}
+		ctx := NewContext(r.Context(), claims)
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("subject", claims.Subject))
+		logging.AddRequestAttrs(ctx, "subject", claims.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticate verifies the bearer token of a request
+func (a *Authenticator) authenticate(r *http.Request) (*Claims, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return nil, ErrMissingToken
+	}
+	return a.Verify(r.Context(), token)
+}
+
+// BearerToken returns the token of a request's Authorization: Bearer header.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized writes a 401 problem document with a WWW-Authenticate
+// challenge (RFC 6750)
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	challenge := "Bearer"
+	switch {
+	case errors.Is(err, ErrInvalidToken):
+		challenge = fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, err.Error())
+	case !errors.Is(err, ErrMissingToken):
+		// Keys couldn't be fetched; the token may be fine
+		logging.FromContext(r.Context()).Error("authentication failed", "error", err)
+		err = errors.New("token could not be verified")
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.Header().Set("Content-Type", errcode.ContentType)
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(w).Encode(errcode.NewProblem(http.StatusUnauthorized, errcode.Wrap(errcode.Unauthorized, err)))
+}
+
+// contextKey keys the claims in request contexts
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying claims.
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromContext returns the claims of an authenticated request, or false for
+// unauthenticated requests (e.g. with a non-enforcing Authenticator).
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(contextKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// Subject returns the subject of an authenticated request, or "".
+func Subject(ctx context.Context) string {
+	if claims, ok := FromContext(ctx); ok {
+		return claims.Subject
+	}
+	return ""
+}
@@ -0,0 +1,297 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newKeys(t *testing.T) map[string]crypto.Signer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]crypto.Signer{"rsa": rsaKey, "ec": ecKey, "ed": edKey}
+}
+
+// jwks encodes the public keys of signers as a JWKS document
+func jwks(t *testing.T, signers map[string]crypto.Signer) []byte {
+	t.Helper()
+	enc := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	var keys []map[string]string
+	for kid, s := range signers {
+		switch pub := s.Public().(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "RSA", "kid": kid, "use": "sig",
+				"n": enc(pub.N.Bytes()), "e": enc([]byte{1, 0, 1})})
+		case *ecdsa.PublicKey:
+			keys = append(keys, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256",
+				"x": enc(pub.X.FillBytes(make([]byte, 32))), "y": enc(pub.Y.FillBytes(make([]byte, 32)))})
+		case ed25519.PublicKey:
+			keys = append(keys, map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": enc(pub)})
+		}
+	}
+	// Encryption keys are skipped
+	keys = append(keys, map[string]string{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"})
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func sign(t *testing.T, claims map[string]interface{}, key crypto.Signer, kid string) string {
+	t.Helper()
+	token, err := Sign(claims, key, kid)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return token
+}
+
+func TestVerify_JWKS(t *testing.T) {
+	signers := newKeys(t)
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write(jwks(t, signers))
+	}))
+	defer srv.Close()
+
+	a, err := New(Options{JWKSURL: srv.URL, Issuer: "https://idp", Audience: "inventory"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	for kid, key := range signers {
+		t.Run(kid, func(t *testing.T) {
+			token := sign(t, map[string]interface{}{
+				"sub": "alice", "iss": "https://idp", "aud": []string{"inventory", "other"},
+				"exp": exp, "groups": []string{"admins"},
+			}, key, kid)
+			claims, err := a.Verify(context.Background(), token)
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if claims.Subject != "alice" || claims.ExpiresAt.Unix() != exp {
+				t.Errorf("Unexpected claims %+v", claims)
+			}
+			if groups := claims.Strings("groups"); len(groups) != 1 || groups[0] != "admins" {
+				t.Errorf("Expected groups [admins], got %v", groups)
+			}
+		})
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("Expected the JWKS to be fetched once, got %d", n)
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	signers := newKeys(t)
+	key := signers["ec"]
+	a, err := New(Options{
+		Keys:     StaticKeySet{{ID: "ec", Public: key.Public()}},
+		Issuer:   "https://idp",
+		Audience: "inventory",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{"sub": "alice", "iss": "https://idp", "aud": "inventory",
+			"exp": time.Now().Add(time.Hour).Unix()}
+	}
+	with := func(name string, value interface{}) map[string]interface{} {
+		c := valid()
+		if value == nil {
+			delete(c, name)
+		} else {
+			c[name] = value
+		}
+		return c
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"mallory"}`)) + "."
+
+	// ES384 with the P-256 key: ecdsa.Verify would truncate the SHA-384
+	// digest and accept it
+	payload, _ := json.Marshal(valid())
+	content := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES384","kid":"ec"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := crypto.SHA384.New()
+	digest.Write([]byte(content))
+	r, s, err := ecdsa.Sign(rand.Reader, key.(*ecdsa.PrivateKey), digest.Sum(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongCurve := content + "." + base64.RawURLEncoding.EncodeToString(append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...))
+
+	tests := map[string]string{
+		"expired":       sign(t, with("exp", time.Now().Add(-time.Hour).Unix()), key, "ec"),
+		"not yet valid": sign(t, with("nbf", time.Now().Add(time.Hour).Unix()), key, "ec"),
+		"wrong issuer":  sign(t, with("iss", "https://other"), key, "ec"),
+		"wrong aud":     sign(t, with("aud", "other"), key, "ec"),
+		"no aud":        sign(t, with("aud", nil), key, "ec"),
+		"no exp":        sign(t, with("exp", nil), key, "ec"),
+		"wrong curve":   wrongCurve,
+		"unknown kid":   sign(t, valid(), key, "rotated"),
+		"other key":     sign(t, valid(), signers["rsa"], "ec"),
+		"alg none":      unsigned,
+		"malformed":     "not-a-token",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := a.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	// Within the leeway
+	if _, err := a.Verify(context.Background(), sign(t, with("exp", time.Now().Add(-30*time.Second).Unix()), key, "")); err != nil {
+		t.Errorf("Expected a token expired within the leeway to verify, got %v", err)
+	}
+}
+
+func TestRemoteKeySet_Rotation(t *testing.T) {
+	signers := newKeys(t)
+	current := map[string]crypto.Signer{"old": signers["rsa"]}
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write(jwks(t, current))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	keys := NewRemoteKeySet(srv.URL, nil, time.Hour)
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if got, err := keys.Keys(ctx, "old"); err != nil || len(got) != 1 {
+		t.Fatalf("Expected the old key, got %v, %v", got, err)
+	}
+	current = map[string]crypto.Signer{"new": signers["ec"]}
+
+	// Unknown key IDs refetch at most every minRefreshInterval
+	if got, _ := keys.Keys(ctx, "new"); len(got) != 0 || fetches.Load() != 1 {
+		t.Fatalf("Expected no refetch right after a fetch, got %v after %d fetches", got, fetches.Load())
+	}
+	now = now.Add(minRefreshInterval)
+	if got, _ := keys.Keys(ctx, "new"); len(got) != 1 || fetches.Load() != 2 {
+		t.Fatalf("Expected the rotated key, got %v after %d fetches", got, fetches.Load())
+	}
+
+	// Cached keys are kept while the endpoint fails
+	srv.Close()
+	now = now.Add(2 * time.Hour)
+	if got, err := keys.Keys(ctx, "new"); err != nil || len(got) != 1 {
+		t.Errorf("Expected the cached key while the endpoint is down, got %v, %v", got, err)
+	}
+}
+
+func TestNew_PublicKey(t *testing.T) {
+	key := newKeys(t)["rsa"]
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(Options{PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	if _, err := a.Verify(context.Background(), sign(t, map[string]interface{}{"sub": "svc", "exp": exp}, key, "")); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	// Tokens without exp only with AllowMissingExpiry
+	noExp := sign(t, map[string]interface{}{"sub": "svc"}, key, "")
+	if _, err := a.Verify(context.Background(), noExp); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a token without exp to be rejected, got %v", err)
+	}
+	a, _ = New(Options{Keys: StaticKeySet{{Public: key.Public()}}, AllowMissingExpiry: true})
+	if _, err := a.Verify(context.Background(), noExp); err != nil {
+		t.Errorf("Expected a token without exp to verify with AllowMissingExpiry, got %v", err)
+	}
+
+	if _, err := New(Options{PublicKey: "garbage"}); err == nil {
+		t.Error("Expected an invalid public key to fail")
+	}
+	if _, err := New(Options{}); err == nil {
+		t.Error("Expected options without keys to fail")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	key := newKeys(t)["ed"]
+	keys := StaticKeySet{{Public: key.Public()}}
+	var seen string
+	handler := func(a *Authenticator) http.Handler {
+		return a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = Subject(r.Context())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	}
+	do := func(h http.Handler, authorization string) *httptest.ResponseRecorder {
+		seen = ""
+		req := httptest.NewRequest(http.MethodGet, "/devices", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	a, _ := New(Options{Keys: keys})
+	token := sign(t, map[string]interface{}{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}, key, "")
+	if rec := do(handler(a), "Bearer "+token); rec.Code != http.StatusNoContent || seen != "alice" {
+		t.Errorf("Expected the request through as alice, got %d as %q", rec.Code, seen)
+	}
+
+	rec := do(handler(a), "")
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Errorf("Expected 401 with a Bearer challenge, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"UNAUTHORIZED"`) {
+		t.Errorf("Expected an UNAUTHORIZED problem, got %s", rec.Body)
+	}
+	rec = do(handler(a), "Bearer "+token+"x")
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Header().Get("WWW-Authenticate"), `error="invalid_token"`) {
+		t.Errorf("Expected 401 invalid_token, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+
+	lenient, _ := New(Options{Keys: keys, NonEnforcing: true})
+	if rec := do(handler(lenient), "Basic dXNlcjpwYXNz"); rec.Code != http.StatusNoContent || seen != "" {
+		t.Errorf("Expected a non-enforcing pass without claims, got %d as %q", rec.Code, seen)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// Key is a public key that verifies tokens.
+type Key struct {
+	// ID is the key ID matched against the "kid" header of tokens; empty
+	// matches any token
+	ID string
+	// Public is an *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey
+	Public crypto.PublicKey
+}
+
+// KeySet provides the keys that verify tokens.
+type KeySet interface {
+	// Keys returns the keys that may have signed a token with the key ID
+	// (all keys if kid is empty).
+	Keys(ctx context.Context, kid string) ([]Key, error)
+}
+
+// StaticKeySet is a fixed set of keys.
+type StaticKeySet []Key
+
+// Keys returns the keys with the ID, and keys without one.
+func (s StaticKeySet) Keys(ctx context.Context, kid string) ([]Key, error) {
+	return matchKeys(s, kid), nil
+}
+
+// matchKeys returns the keys with the ID, or all keys if kid is empty
+func matchKeys(keys []Key, kid string) []Key {
+	if kid == "" {
+		return keys
+	}
+	var matched []Key
+	for _, k := range keys {
+		if k.ID == kid || k.ID == "" {
+			matched = append(matched, k)
+		}
+	}
+	return matched
+}
+
+// ParsePublicKey parses a PEM-encoded public key (PKIX "PUBLIC KEY" or
+// PKCS #1 "RSA PUBLIC KEY") or certificate.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// DefaultRefreshInterval is how long a RemoteKeySet caches keys when no
+// interval is given
+const DefaultRefreshInterval = time.Hour
+
+// minRefreshInterval limits fetches triggered by tokens with unknown key
+// IDs, so bad tokens can't make the server hammer the JWKS endpoint
+const minRefreshInterval = 30 * time.Second
+
+// maxJWKSSize limits the size of a fetched JWKS document
+const maxJWKSSize = 1 << 20
+
+// RemoteKeySet fetches keys from a JWKS endpoint (e.g. an OIDC provider's
+// jwks_uri). Keys are cached for the refresh interval and fetched again
+// early when a token names an unknown key ID, so signing key rotations are
+// picked up without a restart.
+type RemoteKeySet struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	keys    []Key
+	fetched time.Time
+}
+
+// NewRemoteKeySet creates a key set for a JWKS URL. A nil client uses
+// http.DefaultClient and a zero refresh uses DefaultRefreshInterval. Keys
+// are fetched on first use.
+func NewRemoteKeySet(url string, client *http.Client, refresh time.Duration) *RemoteKeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if refresh <= 0 {
+		refresh = DefaultRefreshInterval
+	}
+	return &RemoteKeySet{url: url, client: client, refresh: refresh, now: time.Now}
+}
+
+// Keys returns the keys with the ID, fetching the JWKS when the cache is
+// stale or doesn't have the key.
+func (s *RemoteKeySet) Keys(ctx context.Context, kid string) ([]Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := s.now().Sub(s.fetched)
+	stale := s.fetched.IsZero() || age >= s.refresh
+	if !stale {
+		if keys := matchKeys(s.keys, kid); len(keys) > 0 || age < minRefreshInterval {
+			return keys, nil
+		}
+	}
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		if s.keys != nil {
+			// Keep serving the cached keys while the endpoint is unavailable
+			return matchKeys(s.keys, kid), nil
+		}
+		return nil, err
+	}
+	s.keys, s.fetched = keys, s.now()
+	return matchKeys(keys, kid), nil
+}
+
+// fetch downloads and parses the JWKS
+func (s *RemoteKeySet) fetch(ctx context.Context) ([]Key, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS from %s: %s", s.url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set with go-jose. RSA, EC (P-256, P-384,
+// P-521) and OKP (Ed25519) signing keys are returned; encryption keys and
+// other key types are skipped.
+func ParseJWKS(data []byte) ([]Key, error) {
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	var keys []Key
+	for _, raw := range set.Keys {
+		var params struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+		}
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse JWK: %w", err)
+		}
+		if params.Use != "" && params.Use != "sig" || !signingKeyType(params.Kty, params.Crv) {
+			continue
+		}
+		var k jose.JSONWebKey
+		if err := k.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", params.Kid, err)
+		}
+		if !k.Valid() || !k.IsPublic() {
+			return nil, fmt.Errorf("invalid JWK %q: not a valid public key", params.Kid)
+		}
+		keys = append(keys, Key{ID: k.KeyID, Public: k.Key})
+	}
+	return keys, nil
+}
+
+// signingKeyType reports whether a JWK key type and curve are supported
+func signingKeyType(kty, crv string) bool {
+	switch kty {
+	case "RSA":
+		return true
+	case "EC":
+		return crv == "P-256" || crv == "P-384" || crv == "P-521"
+	case "OKP":
+		return crv == "Ed25519"
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+// Supported signing algorithms. Tokens signed with "none" or with shared
+// secrets (HS256) are rejected. Tokens are parsed and their signatures
+// checked with go-jose.
+const (
+	RS256 = "RS256"
+	RS384 = "RS384"
+	RS512 = "RS512"
+	PS256 = "PS256"
+	PS384 = "PS384"
+	PS512 = "PS512"
+	ES256 = "ES256"
+	ES384 = "ES384"
+	ES512 = "ES512"
+	EdDSA = "EdDSA"
+)
+
+// ErrInvalidToken is wrapped by the errors of tokens that fail verification.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the claims of a verified token.
+type Claims struct {
+	// Subject identifies the caller (the "sub" claim)
+	Subject string
+	// Issuer is the "iss" claim
+	Issuer string
+	// Audience lists the "aud" claim, a string or a list in tokens
+	Audience []string
+	// ExpiresAt is the "exp" claim, zero if absent
+	ExpiresAt time.Time
+	// NotBefore is the "nbf" claim, zero if absent
+	NotBefore time.Time
+	// IssuedAt is the "iat" claim, zero if absent
+	IssuedAt time.Time
+	// ID is the "jti" claim
+	ID string
+	// Raw holds every claim, including the registered ones above
+	Raw map[string]interface{}
+}
+
+// Strings returns a claim as a list of strings: a list claim's string
+// items, or a string claim split on spaces (like OAuth "scope").
+func (c *Claims) Strings(name string) []string {
+	switch v := c.Raw[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// signatureAlgorithms are the algorithms tokens may be signed with
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512, EdDSA,
+}
+
+// parse parses a compact JWS without verifying it, returning the token and
+// the algorithm and key ID of its header
+func parse(token string) (*jose.JSONWebSignature, string, string, error) {
+	jws, err := jose.ParseSignedCompact(token, signatureAlgorithms)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if len(jws.Signatures) != 1 {
+		return nil, "", "", fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+	h := jws.Signatures[0].Protected
+	return jws, h.Algorithm, h.KeyID, nil
+}
+
+// decodeClaims decodes the verified payload of a token
+func decodeClaims(payload []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil || raw == nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	return raw, nil
+}
+
+// newClaims reads the registered claims of raw
+func newClaims(raw map[string]interface{}) (*Claims, error) {
+	c := &Claims{Raw: raw}
+	var ok bool
+	for name, dst := range map[string]*string{"sub": &c.Subject, "iss": &c.Issuer, "jti": &c.ID} {
+		if v, found := raw[name]; found {
+			if *dst, ok = v.(string); !ok {
+				return nil, fmt.Errorf("%w: %s claim is not a string", ErrInvalidToken, name)
+			}
+		}
+	}
+	switch aud := raw["aud"].(type) {
+	case nil:
+	case string:
+		c.Audience = []string{aud}
+	case []interface{}:
+		for _, item := range aud {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: aud claim is not a list of strings", ErrInvalidToken)
+			}
+			c.Audience = append(c.Audience, s)
+		}
+	default:
+		return nil, fmt.Errorf("%w: aud claim is not a string or list", ErrInvalidToken)
+	}
+	for name, dst := range map[string]*time.Time{"exp": &c.ExpiresAt, "nbf": &c.NotBefore, "iat": &c.IssuedAt} {
+		v, found := raw[name]
+		if !found {
+			continue
+		}
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s claim is not a number", ErrInvalidToken, name)
+		}
+		secs, err := n.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s claim is not a number", ErrInvalidToken, name)
+		}
+		*dst = time.Unix(0, int64(secs*float64(time.Second)))
+	}
+	return c, nil
+}
+
+// curveBits returns the curve size an ECDSA algorithm is defined for
+func curveBits(alg string) int {
+	switch alg {
+	case ES256:
+		return 256
+	case ES384:
+		return 384
+	case ES512:
+		return 521
+	}
+	return 0
+}
+
+// verifySignature checks the signature of a token with a public key and
+// returns its payload
+func verifySignature(jws *jose.JSONWebSignature, alg string, key crypto.PublicKey) ([]byte, error) {
+	// Each ES algorithm pairs one hash with one curve (RFC 7518 3.4);
+	// go-jose only checks the size of the signature
+	if pub, ok := key.(*ecdsa.PublicKey); ok && curveBits(alg) != pub.Curve.Params().BitSize {
+		return nil, fmt.Errorf("key does not match algorithm %s", alg)
+	}
+	return jws.Verify(key)
+}
+
+// Sign issues a token for claims, for tests and development tools that need
+// tokens the Authenticator accepts. The algorithm follows the key: RS256 for
+// RSA, ES256, ES384 or ES512 for ECDSA by curve, and EdDSA for Ed25519.
+//
+// Parameters:
+//   - claims: Claims of the token, e.g. {"sub": "alice", "exp": 1700000000}
+//   - key: *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey
+//   - kid: Key ID written to the header, matched against JWKS keys; may be empty
+//
+// Returns:
+//   - string: The compact token
+//   - error: An error if the key type is unsupported or signing fails
+func Sign(claims map[string]interface{}, key crypto.Signer, kid string) (string, error) {
+	var alg jose.SignatureAlgorithm
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		alg = RS256
+	case *ecdsa.PrivateKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			alg = ES256
+		case 384:
+			alg = ES384
+		case 521:
+			alg = ES512
+		default:
+			return "", fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+		}
+	case ed25519.PrivateKey:
+		alg = EdDSA
+	default:
+		return "", fmt.Errorf("unsupported key type %T", key)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode claims: %w", err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: alg,
+		Key:       jose.JSONWebKey{Key: key, KeyID: kid},
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create signer: %w", err)
+	}
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return jws.CompactSerialize()
+}
@@ -0,0 +1,366 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package backup streams resources out of and into a server, for backups and
+// for migrations between storage backends.
+//
+// An export is a stream of resource documents as they are stored: UIDs,
+// timestamps, labels, annotations and the status (including status.version)
+// are kept, so an import recreates the resources exactly. Two formats are
+// supported:
+//
+//	ndjson   application/x-ndjson   one resource document per line
+//	tar      application/x-tar      one <kind>/<uid>.json file per resource
+//
+// The generated server serves GET /export with a Writer and POST /import with
+// a Reader. A Filter selects the exported or imported resources by kind and
+// labels.
+//
+// Usage:
+//
+//	w, err := backup.NewWriter(out, backup.FormatTar)
+//	for _, device := range devices {
+//	    data, _ := json.Marshal(device)
+//	    if err := w.Write(data); err != nil { ... }
+//	}
+//	err = w.Close()
+//
+//	r, err := backup.NewReader(in, backup.FormatTar)
+//	for {
+//	    data, err := r.Next()
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    item, err := backup.Parse(data)
+//	    ...
+//	}
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// Supported formats
+const (
+	FormatNDJSON = "ndjson"
+	FormatTar    = "tar"
+)
+
+// Media types of the formats
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeTar    = "application/x-tar"
+)
+
+// MaxItemSize is the largest resource document accepted by a Reader
+const MaxItemSize = 16 << 20
+
+// ContentType returns the media type of a format, or "" for unknown formats.
+func ContentType(format string) string {
+	switch format {
+	case FormatNDJSON:
+		return ContentTypeNDJSON
+	case FormatTar:
+		return ContentTypeTar
+	}
+	return ""
+}
+
+// FormatOf returns the format of a media type, or "" if it isn't supported.
+// Parameters of the media type are ignored.
+func FormatOf(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case ContentTypeNDJSON:
+		return FormatNDJSON
+	case ContentTypeTar:
+		return FormatTar
+	}
+	return ""
+}
+
+// Item identifies an exported resource document.
+type Item struct {
+	Kind   string
+	UID    string
+	Labels map[string]string
+
+	// Data is the resource document
+	Data json.RawMessage
+}
+
+// Parse reads the kind, UID and labels of a resource document.
+//
+// Returns:
+//   - Item: The identified document
+//   - error: If data isn't a JSON object with a kind and metadata.uid
+func Parse(data []byte) (Item, error) {
+	var doc struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			UID    string            `json:"uid"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Item{}, fmt.Errorf("invalid resource document: %w", err)
+	}
+	if doc.Kind == "" || doc.Metadata.UID == "" {
+		return Item{}, errors.New("resource document needs a kind and metadata.uid")
+	}
+	return Item{Kind: doc.Kind, UID: doc.Metadata.UID, Labels: doc.Metadata.Labels, Data: data}, nil
+}
+
+// Filter selects resources by kind and labels. The zero Filter selects
+// every resource.
+type Filter struct {
+	// Kinds are the selected kinds; empty selects all kinds
+	Kinds []string
+
+	// Labels must all be set on a selected resource with the same values
+	Labels map[string]string
+}
+
+// ParseFilter reads a filter from query parameters: kind (comma-separated,
+// repeatable), e.g. ?kind=Device,Location, and labels as comma-separated
+// key=value pairs, e.g. ?labels=rack=r12,env=prod.
+//
+// Returns:
+//   - Filter: The filter
+//   - error: If a label isn't a key=value pair
+func ParseFilter(query url.Values) (Filter, error) {
+	var f Filter
+	for _, value := range query["kind"] {
+		for _, kind := range strings.Split(value, ",") {
+			if kind = strings.TrimSpace(kind); kind != "" {
+				f.Kinds = append(f.Kinds, kind)
+			}
+		}
+	}
+	for _, value := range query["labels"] {
+		for _, pair := range strings.Split(value, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, val, ok := strings.Cut(pair, "=")
+			if key = strings.TrimSpace(key); !ok || key == "" {
+				return Filter{}, fmt.Errorf("invalid label %q, expected key=value", pair)
+			}
+			if f.Labels == nil {
+				f.Labels = make(map[string]string)
+			}
+			f.Labels[key] = strings.TrimSpace(val)
+		}
+	}
+	return f, nil
+}
+
+// MatchesKind reports whether the filter selects resources of a kind.
+func (f Filter) MatchesKind(kind string) bool {
+	if len(f.Kinds) == 0 {
+		return true
+	}
+	for _, k := range f.Kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the filter selects an item.
+func (f Filter) Matches(item Item) bool {
+	if !f.MatchesKind(item.Kind) {
+		return false
+	}
+	for key, value := range f.Labels {
+		if v, ok := item.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Writer writes resource documents in one of the formats.
+type Writer struct {
+	w     io.Writer
+	tw    *tar.Writer
+	now   time.Time
+	count int
+}
+
+// NewWriter creates a writer for a format.
+//
+// Parameters:
+//   - w: Destination of the export
+//   - format: FormatNDJSON or FormatTar
+//
+// Returns:
+//   - *Writer: The writer; Close must be called to finish a tar archive
+//   - error: If the format is unknown
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	switch format {
+	case FormatNDJSON:
+		return &Writer{w: w}, nil
+	case FormatTar:
+		return &Writer{w: w, tw: tar.NewWriter(w), now: time.Now()}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// Write writes one resource document.
+//
+// Returns:
+//   - error: If the document has no kind or UID, or writing fails
+func (w *Writer) Write(data []byte) error {
+	item, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	w.count++
+
+	if w.tw == nil {
+		var line bytes.Buffer
+		if err := json.Compact(&line, data); err != nil {
+			return fmt.Errorf("invalid resource document: %w", err)
+		}
+		line.WriteByte('\n')
+		_, err := w.w.Write(line.Bytes())
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    path.Join(item.Kind, item.UID+".json"),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: w.now,
+	}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = w.tw.Write(data)
+	return err
+}
+
+// Count returns the number of documents written.
+func (w *Writer) Count() int {
+	return w.count
+}
+
+// Close finishes the export. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.tw != nil {
+		return w.tw.Close()
+	}
+	return nil
+}
+
+// Reader reads resource documents in one of the formats.
+type Reader struct {
+	scanner *bufio.Scanner
+	tr      *tar.Reader
+}
+
+// NewReader creates a reader for a format.
+//
+// Parameters:
+//   - r: Source of the import
+//   - format: FormatNDJSON or FormatTar
+//
+// Returns:
+//   - *Reader: The reader
+//   - error: If the format is unknown
+func NewReader(r io.Reader, format string) (*Reader, error) {
+	switch format {
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), MaxItemSize)
+		return &Reader{scanner: scanner}, nil
+	case FormatTar:
+		return &Reader{tr: tar.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+// Next returns the next resource document. Blank NDJSON lines and tar
+// entries that aren't regular .json files are skipped.
+//
+// Returns:
+//   - []byte: The document, which isn't checked; see Parse
+//   - error: io.EOF at the end of the stream, or an error reading it
+func (r *Reader) Next() ([]byte, error) {
+	if r.tr == nil {
+		for r.scanner.Scan() {
+			line := bytes.TrimSpace(r.scanner.Bytes())
+			if len(line) > 0 {
+				return append([]byte(nil), line...), nil
+			}
+		}
+		if err := r.scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read import: %w", err)
+		}
+		return nil, io.EOF
+	}
+
+	for {
+		header, err := r.tr.Next()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg || path.Ext(header.Name) != ".json" {
+			continue
+		}
+		if header.Size > MaxItemSize {
+			return nil, fmt.Errorf("%s exceeds %d bytes", header.Name, MaxItemSize)
+		}
+		data, err := io.ReadAll(r.tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		return data, nil
+	}
+}
+
+// Result reports the outcome of an import.
+type Result struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+
+	// Errors describes the documents that weren't imported
+	Errors []ItemError `json:"errors,omitempty"`
+}
+
+// ItemError describes a document that couldn't be imported.
+type ItemError struct {
+	// Index is the position of the document in the import, starting at 0
+	Index int    `json:"index"`
+	Kind  string `json:"kind,omitempty"`
+	UID   string `json:"uid,omitempty"`
+	Error string `json:"error"`
+}
+
+// Fail records a document that couldn't be imported.
+func (r *Result) Fail(index int, item Item, err error) {
+	r.Failed++
+	r.Errors = append(r.Errors, ItemError{Index: index, Kind: item.Kind, UID: item.UID, Error: err.Error()})
+}
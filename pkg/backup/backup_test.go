@@ -0,0 +1,136 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testDocuments = []string{
+	`{"kind":"Device","metadata":{"uid":"dev-1","labels":{"rack":"r1"}},"spec":{"ip":"10.0.0.1"}}`,
+	`{"kind":"Location","metadata":{"uid":"loc-1"},"status":{"version":"v7"}}`,
+}
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	for _, format := range []string{FormatNDJSON, FormatTar} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, doc := range testDocuments {
+				if err := w.Write([]byte(doc)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if w.Count() != 2 {
+				t.Errorf("expected 2 documents written, got %d", w.Count())
+			}
+
+			r, err := NewReader(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for {
+				data, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, string(data))
+			}
+			if !reflect.DeepEqual(got, testDocuments) {
+				t.Errorf("got %v\nwant %v", got, testDocuments)
+			}
+		})
+	}
+}
+
+func TestWriter_TarLayout(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(&buf, FormatTar)
+	w.Write([]byte(testDocuments[0]))
+	w.Close()
+
+	header, err := tar.NewReader(&buf).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Name != "Device/dev-1.json" {
+		t.Errorf("unexpected entry name %q", header.Name)
+	}
+}
+
+func TestWriter_RejectsUnidentifiedDocuments(t *testing.T) {
+	w, _ := NewWriter(io.Discard, FormatNDJSON)
+	if err := w.Write([]byte(`{"kind":"Device","metadata":{}}`)); err == nil {
+		t.Error("expected an error for a document without a UID")
+	}
+	if _, err := NewWriter(io.Discard, "zip"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestReader_SkipsBlankLines(t *testing.T) {
+	r, _ := NewReader(strings.NewReader("\n"+testDocuments[1]+"\n\n"), FormatNDJSON)
+	data, err := r.Next()
+	if err != nil || string(data) != testDocuments[1] {
+		t.Fatalf("got %s, %v", data, err)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f, err := ParseFilter(url.Values{"kind": {"device, location"}, "labels": {"rack=r1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	device, _ := Parse([]byte(testDocuments[0]))
+	location, _ := Parse([]byte(testDocuments[1]))
+
+	if !f.MatchesKind("Device") || f.MatchesKind("Connection") {
+		t.Errorf("unexpected kinds of %+v", f)
+	}
+	if !f.Matches(device) || f.Matches(location) {
+		t.Error("expected only the labeled device to match")
+	}
+	if !(Filter{}).Matches(location) {
+		t.Error("expected the zero filter to match everything")
+	}
+
+	if _, err := ParseFilter(url.Values{"labels": {"rack"}}); err == nil {
+		t.Error("expected an error for a label without a value")
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]string{
+		"application/x-ndjson":                FormatNDJSON,
+		"application/x-ndjson; charset=utf-8": FormatNDJSON,
+		"application/x-tar":                   FormatTar,
+		"application/json":                    "",
+		"":                                    "",
+	}
+	for contentType, want := range tests {
+		if got := FormatOf(contentType); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
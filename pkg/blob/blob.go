@@ -0,0 +1,174 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package blob stores binary attachments of resources (firmware images,
+// config bundles, ...) in a pluggable blob store.
+//
+// Generated servers expose attachments as a files subresource:
+//
+//	PUT    /devices/{uid}/files/{name}   (upload; body is the raw file)
+//	GET    /devices/{uid}/files/{name}   (download)
+//	GET    /devices/{uid}/files          (list attachments)
+//	DELETE /devices/{uid}/files/{name}   (delete)
+//
+// Two stores are included: FileStore keeps blobs in a local directory, and
+// S3Store talks to any S3-compatible object store (AWS S3, MinIO, Ceph RGW).
+// Every stored blob records its size, content type and SHA-256 checksum.
+//
+// Usage:
+//
+//	store, err := blob.NewFileStore("./data/blobs")
+//	info, err := store.Put(ctx, blob.Key("Device", uid, "firmware.bin"), f, "application/octet-stream")
+//	fmt.Println(info.Size, info.SHA256)
+//
+//	rc, info, err := store.Get(ctx, blob.Key("Device", uid, "firmware.bin"))
+//	defer rc.Close()
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a blob doesn't exist.
+	ErrNotFound = errors.New("blob not found")
+
+	// ErrInvalidName is returned for attachment names that aren't safe to use as keys.
+	ErrInvalidName = errors.New("invalid blob name")
+
+	// ErrChecksumMismatch is returned when content doesn't match its expected checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// MaxNameLength is the longest accepted attachment name.
+const MaxNameLength = 255
+
+// Info describes a stored blob.
+type Info struct {
+	// Name is the attachment name (the last element of the key)
+	Name string `json:"name" yaml:"name"`
+
+	// Size is the blob size in bytes
+	Size int64 `json:"size" yaml:"size"`
+
+	// ContentType is the media type given on upload
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 checksum of the content
+	SHA256 string `json:"sha256" yaml:"sha256"`
+
+	// ModifiedAt is when the blob was last written
+	ModifiedAt time.Time `json:"modifiedAt" yaml:"modifiedAt"`
+}
+
+// Store persists blobs under slash-separated keys.
+//
+// Implementations must be safe for concurrent use. Put replaces existing
+// blobs atomically: readers see either the old or the new content.
+type Store interface {
+	// Put stores the content of r under key and returns its metadata.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (*Info, error)
+
+	// Get opens the blob stored under key. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Info, error)
+
+	// Stat returns the metadata of the blob stored under key.
+	Stat(ctx context.Context, key string) (*Info, error)
+
+	// Delete removes the blob stored under key.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the metadata of the blobs directly under a "directory"
+	// prefix ending in '/' (see Prefix), sorted by name.
+	List(ctx context.Context, prefix string) ([]Info, error)
+}
+
+// Key builds the blob key of a resource attachment.
+//
+// Example:
+//
+//	blob.Key("Device", "dev-1a2b3c4d", "firmware.bin") // "device/dev-1a2b3c4d/firmware.bin"
+func Key(kind, uid, name string) string {
+	return Prefix(kind, uid) + name
+}
+
+// Prefix returns the key prefix of all attachments of a resource.
+func Prefix(kind, uid string) string {
+	return strings.ToLower(kind) + "/" + uid + "/"
+}
+
+// ValidateName checks that an attachment name is safe to use in a key.
+// Names may contain letters, digits, '.', '-' and '_', and must not start with '.'.
+func ValidateName(name string) error {
+	if name == "" || len(name) > MaxNameLength || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			return fmt.Errorf("%w: %q (allowed: letters, digits, '.', '-', '_')", ErrInvalidName, name)
+		}
+	}
+	return nil
+}
+
+// VerifySHA256 wraps r so that reading it to the end fails with
+// ErrChecksumMismatch unless the content's SHA-256 equals want (hex).
+//
+// Stores only commit a blob after reading it completely, so passing the
+// wrapped reader to Put leaves any existing blob untouched on a mismatch:
+//
+//	info, err := store.Put(ctx, key, blob.VerifySHA256(body, sum), contentType)
+//	if errors.Is(err, blob.ErrChecksumMismatch) { ... }
+func VerifySHA256(r io.Reader, want string) io.Reader {
+	return &verifyingReader{checksumReader: newChecksumReader(r), want: strings.ToLower(want)}
+}
+
+// verifyingReader checks the checksum when the underlying reader is exhausted
+type verifyingReader struct {
+	*checksumReader
+	want string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.checksumReader.Read(p)
+	if err == io.EOF && v.sum() != v.want {
+		return n, fmt.Errorf("%w: got %s, want %s", ErrChecksumMismatch, v.sum(), v.want)
+	}
+	return n, err
+}
+
+// checksumReader counts and hashes everything read through it
+type checksumReader struct {
+	r    io.Reader
+	h    hash.Hash
+	size int64
+}
+
+func newChecksumReader(r io.Reader) *checksumReader {
+	return &checksumReader{r: r, h: sha256.New()}
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.size += int64(n)
+	c.h.Write(p[:n])
+	return n, err
+}
+
+func (c *checksumReader) sum() string {
+	return hex.EncodeToString(c.h.Sum(nil))
+}
+
+// keyName returns the last element of a key
+func keyName(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
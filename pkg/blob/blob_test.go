@@ -0,0 +1,208 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// exerciseStore runs the same lifecycle against any Store implementation
+func exerciseStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	key := Key("Device", "dev-1", "firmware.bin")
+
+	info, err := store.Put(ctx, key, strings.NewReader("hello"), "application/octet-stream")
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	const helloSHA = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if info.Name != "firmware.bin" || info.Size != 5 || info.SHA256 != helloSHA {
+		t.Errorf("Unexpected info: %+v", info)
+	}
+
+	rc, got, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "hello" || got.SHA256 != helloSHA || got.ContentType != "application/octet-stream" {
+		t.Errorf("Unexpected blob %q %+v", data, got)
+	}
+
+	if _, err := store.Put(ctx, Key("Device", "dev-1", "config.tar"), strings.NewReader("cfg"), ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := store.Put(ctx, Key("Device", "dev-2", "other.bin"), strings.NewReader("x"), ""); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	list, err := store.List(ctx, Prefix("Device", "dev-1"))
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "config.tar" || list[1].Name != "firmware.bin" {
+		t.Errorf("Unexpected list: %+v", list)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := store.Delete(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+	if list, _ := store.List(ctx, Prefix("Device", "missing")); len(list) != 0 {
+		t.Errorf("Expected empty list, got %+v", list)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseStore(t, store)
+
+	if _, err := store.Put(context.Background(), "../escape", strings.NewReader("x"), ""); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Expected ErrInvalidName for traversal, got %v", err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"firmware.bin", "BIOS_v1.2-rc1.img"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".hidden", "a/b", "..", "sp ace", strings.Repeat("a", 256)} {
+		if err := ValidateName(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("ValidateName(%q) = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
+
+// fakeS3 is a minimal in-memory S3 endpoint
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned", http.StatusForbidden)
+		return
+	}
+	if r.URL.Query().Get("list-type") == "2" {
+		prefix := strings.Trim(r.URL.Path, "/") + "/" + r.URL.Query().Get("prefix")
+		fmt.Fprint(w, "<ListBucketResult>")
+		for key := range f.objects {
+			if strings.HasPrefix(key, prefix) && !strings.Contains(key[len(prefix):], "/") {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", strings.SplitN(key, "/", 2)[1])
+			}
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			data = decodeAWSChunked(data)
+		}
+		f.objects[key] = data
+		f.headers[key] = r.Header.Clone()
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+			}
+			return
+		}
+		w.Header().Set("Content-Type", f.headers[key].Get("Content-Type"))
+		w.Header().Set(s3ChecksumHeader, f.headers[key].Get(s3ChecksumHeader))
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("ETag", `"etag"`)
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// decodeAWSChunked strips the chunk headers of a streaming-signed upload
+func decodeAWSChunked(data []byte) []byte {
+	var out []byte
+	for {
+		header, rest, _ := strings.Cut(string(data), "\r\n")
+		sizeHex, _, _ := strings.Cut(header, ";")
+		var size int
+		fmt.Sscanf(sizeHex, "%x", &size)
+		if size == 0 || len(rest) < size {
+			return out
+		}
+		out = append(out, rest[:size]...)
+		data = []byte(strings.TrimPrefix(rest[size:], "\r\n"))
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}, headers: map[string]http.Header{}})
+	defer srv.Close()
+
+	store, err := NewS3Store(S3Config{Endpoint: srv.URL, Bucket: "blobs", AccessKey: "AKID", SecretKey: "secret"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exerciseStore(t, store)
+
+	if _, err := NewS3Store(S3Config{Endpoint: srv.URL}, nil); err == nil {
+		t.Error("Expected error without bucket")
+	}
+	if _, err := NewS3Store(S3Config{Endpoint: srv.URL + "/s3", Bucket: "blobs"}, nil); err == nil {
+		t.Error("Expected error for an endpoint with a path")
+	}
+}
+
+func TestVerifySHA256(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	const helloSHA = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	if _, err := store.Put(ctx, "device/dev-1/a.bin", VerifySHA256(strings.NewReader("hello"), strings.ToUpper(helloSHA)), ""); err != nil {
+		t.Fatalf("Put with matching checksum failed: %v", err)
+	}
+	_, err = store.Put(ctx, "device/dev-1/a.bin", VerifySHA256(strings.NewReader("tampered"), helloSHA), "")
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Expected ErrChecksumMismatch, got %v", err)
+	}
+	if info, _ := store.Stat(ctx, "device/dev-1/a.bin"); info == nil || info.SHA256 != helloSHA {
+		t.Errorf("Expected the previous blob to be kept, got %+v", info)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package blob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// metaSuffix names the metadata file stored next to each blob
+const metaSuffix = ".meta.json"
+
+// FileStore stores blobs as files in a local directory.
+//
+// Each blob is a file at <dir>/<key>, with its metadata in <key>.meta.json.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a file store rooted at dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Put writes the blob to a temporary file and renames it into place.
+func (s *FileStore) Put(ctx context.Context, key string, r io.Reader, contentType string) (*Info, error) {
+	if strings.HasSuffix(key, metaSuffix) {
+		return nil, fmt.Errorf("%w: %q is reserved for metadata", ErrInvalidName, key)
+	}
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	cr := newChecksumReader(r)
+	_, err = io.Copy(tmp, cr)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write blob: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{
+		Name:        keyName(key),
+		Size:        cr.size,
+		ContentType: contentType,
+		SHA256:      cr.sum(),
+		ModifiedAt:  stat.ModTime().UTC(),
+	}
+	meta, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to store blob: %w", err)
+	}
+	if err := os.WriteFile(path+metaSuffix, meta, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write blob metadata: %w", err)
+	}
+	return info, nil
+}
+
+// Get opens the blob file.
+func (s *FileStore) Get(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	path, _ := s.path(key)
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open blob: %w", err)
+	}
+	return f, info, nil
+}
+
+// Stat reads the blob's metadata file.
+func (s *FileStore) Stat(_ context.Context, key string) (*Info, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path + metaSuffix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob metadata: %w", err)
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid blob metadata: %w", err)
+	}
+	return &info, nil
+}
+
+// Delete removes the blob and its metadata.
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path + metaSuffix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// List reads the metadata files under the prefix directory.
+func (s *FileStore) List(ctx context.Context, prefix string) ([]Info, error) {
+	dir, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Info{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	infos := []Info{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), metaSuffix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := s.Stat(ctx, strings.TrimSuffix(prefix, "/")+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// path maps a key to a file path, rejecting keys that escape the store directory
+func (s *FileStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean != "/"+strings.TrimSuffix(key, "/") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
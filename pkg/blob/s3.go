@@ -0,0 +1,215 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3ChecksumHeader stores the SHA-256 of a blob as object metadata
+const s3ChecksumHeader = "X-Amz-Meta-Sha256"
+
+// S3Config configures an S3Store.
+type S3Config struct {
+	// Endpoint is the base URL of the object store (e.g., "https://s3.us-east-1.amazonaws.com", "http://minio:9000")
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+
+	// Bucket is the bucket blobs are stored in
+	Bucket string `json:"bucket" yaml:"bucket"`
+
+	// Region is used for request signing (default: us-east-1)
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// AccessKey and SecretKey are the credentials used to sign requests
+	AccessKey string `json:"-" yaml:"-"`
+	SecretKey string `json:"-" yaml:"-"`
+
+	// Prefix is prepended to every key (e.g., "fabrica/")
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
+
+// S3ConfigFromEnv reads an S3Config from environment variables:
+// FABRICA_S3_ENDPOINT, FABRICA_S3_BUCKET, FABRICA_S3_REGION, FABRICA_S3_PREFIX,
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+func S3ConfigFromEnv() S3Config {
+	return S3Config{
+		Endpoint:  os.Getenv("FABRICA_S3_ENDPOINT"),
+		Bucket:    os.Getenv("FABRICA_S3_BUCKET"),
+		Region:    os.Getenv("FABRICA_S3_REGION"),
+		Prefix:    os.Getenv("FABRICA_S3_PREFIX"),
+		AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+	}
+}
+
+// S3Store stores blobs in an S3-compatible object store with the MinIO
+// client, like the S3 storage backend.
+//
+// Objects are addressed path-style (<endpoint>/<bucket>/<key>), which works
+// with AWS S3 as well as MinIO and Ceph RGW. The checksum is stored as
+// x-amz-meta-sha256.
+type S3Store struct {
+	cfg    S3Config
+	client *minio.Client
+	now    func() time.Time
+}
+
+// NewS3Store creates an S3 store.
+//
+// Parameters:
+//   - cfg: Endpoint and bucket are required; credentials may be empty for public buckets
+//   - httpClient: HTTP client whose transport is used (the MinIO default if nil)
+func NewS3Store(cfg S3Config, httpClient *http.Client) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 endpoint and bucket are required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if endpoint.Path != "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: must not have a path", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	opts := &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       cfg.Region,
+		BucketLookup: minio.BucketLookupPath,
+	}
+	if httpClient != nil {
+		opts.Transport = httpClient.Transport
+	}
+	client, err := minio.New(endpoint.Host, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	return &S3Store{cfg: cfg, client: client, now: time.Now}, nil
+}
+
+// Put uploads the blob. The content is spooled to a temporary file first,
+// because the checksum is stored with the object and must be known before
+// the upload starts.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, contentType string) (*Info, error) {
+	tmp, err := os.CreateTemp("", "fabrica-blob-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to buffer blob: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	cr := newChecksumReader(r)
+	if _, err := io.Copy(tmp, cr); err != nil {
+		return nil, fmt.Errorf("failed to buffer blob: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	_, err = s.client.PutObject(ctx, s.cfg.Bucket, s.cfg.Prefix+key, tmp, cr.size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: map[string]string{"sha256": cr.sum()},
+	})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+
+	return &Info{
+		Name:        keyName(key),
+		Size:        cr.size,
+		ContentType: contentType,
+		SHA256:      cr.sum(),
+		ModifiedAt:  s.now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Get downloads the blob.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	object, err := s.client.GetObject(ctx, s.cfg.Bucket, s.cfg.Prefix+key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, s3Error(err)
+	}
+	// GetObject is lazy: Stat sends the request and reports missing objects
+	oi, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, nil, s3Error(err)
+	}
+	return object, infoFromObject(key, oi), nil
+}
+
+// Stat returns the blob metadata from a HEAD request.
+func (s *S3Store) Stat(ctx context.Context, key string) (*Info, error) {
+	oi, err := s.client.StatObject(ctx, s.cfg.Bucket, s.cfg.Prefix+key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return infoFromObject(key, oi), nil
+}
+
+// Delete removes the blob. S3 doesn't report missing objects on delete, so
+// the blob is checked first to return ErrNotFound consistently.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if _, err := s.Stat(ctx, key); err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.cfg.Bucket, s.cfg.Prefix+key, minio.RemoveObjectOptions{}); err != nil {
+		return s3Error(err)
+	}
+	return nil
+}
+
+// List lists the objects directly under prefix and stats each one to read
+// its checksum.
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Info, error) {
+	infos := []Info{}
+	for object := range s.client.ListObjects(ctx, s.cfg.Bucket, minio.ListObjectsOptions{Prefix: s.cfg.Prefix + prefix}) {
+		if object.Err != nil {
+			return nil, s3Error(object.Err)
+		}
+		if strings.HasSuffix(object.Key, "/") {
+			continue // common prefix of nested keys
+		}
+		info, err := s.Stat(ctx, strings.TrimPrefix(object.Key, s.cfg.Prefix))
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, *info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// s3Error maps missing objects to ErrNotFound
+func s3Error(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return fmt.Errorf("S3 request failed: %w", err)
+}
+
+// infoFromObject builds blob metadata from object info
+func infoFromObject(key string, oi minio.ObjectInfo) *Info {
+	return &Info{
+		Name:        keyName(key),
+		Size:        oi.Size,
+		ContentType: oi.ContentType,
+		SHA256:      oi.Metadata.Get(s3ChecksumHeader),
+		ModifiedAt:  oi.LastModified.UTC(),
+	}
+}
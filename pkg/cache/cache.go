@@ -0,0 +1,420 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package cache provides a response cache for read requests.
+//
+// The cache stores successful GET responses per resource kind, keyed by the
+// request path and query (the selector) plus the headers that change a
+// response (Accept, Accept-Language, Authorization). Each cached response gets
+// an ETag, so clients revalidating with If-None-Match get a 304 without the
+// handler running.
+//
+// Invalidation is per kind: every kind has a generation counter in the store
+// that is part of every key, and Invalidate bumps it. Old entries are never
+// read again and age out by TTL. Writes through the middleware (any non-GET
+// request) invalidate their kind, including on other replicas sharing a Redis
+// store. SubscribeEvents also invalidates a kind on every resource event,
+// which covers writes that don't go through the API, such as reconcilers.
+//
+// Usage:
+//
+//	c := cache.New(cache.NewMemoryStore(10000), cache.Options{TTL: time.Minute})
+//	r.Route("/devices", func(r chi.Router) {
+//	    r.Use(c.Middleware("Device"))
+//	    ...
+//	})
+//	c.SubscribeEvents(bus, events.GetEventConfig().EventTypePrefix)
+//
+// Responses carry an X-Cache header (HIT or MISS); Stats reports the counts.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/conditional"
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// DefaultTTL is how long responses are cached when Options.TTL is zero.
+const DefaultTTL = 30 * time.Second
+
+// DefaultMaxEntrySize is the largest response body cached when Options.MaxEntrySize is zero.
+const DefaultMaxEntrySize = 1 << 20
+
+// StatusHeader reports whether a response was served from the cache.
+const StatusHeader = "X-Cache"
+
+// uncachedHeaders are response headers not replayed from entries: the ones
+// stored separately and the ones that only apply to one response
+var uncachedHeaders = map[string]bool{
+	"Content-Type":   true,
+	"Content-Length": true,
+	"Date":           true,
+	"Etag":           true,
+	"Set-Cookie":     true,
+	"X-Cache":        true,
+	"X-Request-Id":   true,
+}
+
+// defaultVary lists the request headers that select between responses
+var defaultVary = []string{"Accept", "Accept-Language", "Authorization"}
+
+// Store holds cache entries. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored under key, and false if there is none
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Incr atomically increments the counter under key and returns the new value.
+	// Counters don't expire.
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+// Options configures a Cache.
+type Options struct {
+	// TTL is how long a response is cached (default: DefaultTTL)
+	TTL time.Duration
+
+	// KeyPrefix is prepended to every key, to share a store between services
+	KeyPrefix string
+
+	// MaxEntrySize is the largest response body cached (default: DefaultMaxEntrySize)
+	MaxEntrySize int
+
+	// Vary lists the request headers that select between responses
+	// (default: Accept, Accept-Language and Authorization)
+	Vary []string
+
+	// Bypass, when set, skips the cache for matching GET requests
+	Bypass func(r *http.Request) bool
+}
+
+// Stats reports cache activity since the Cache was created.
+type Stats struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Invalidations int64 `json:"invalidations"`
+	Errors        int64 `json:"errors"`
+}
+
+// Cache caches GET responses per resource kind.
+type Cache struct {
+	store Store
+	opts  Options
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+	errors        atomic.Int64
+}
+
+// entry is a cached response
+type entry struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	ETag        string `json:"etag"`
+	Body        []byte `json:"body"`
+
+	// Header holds the other headers set by the handler, such as Link and
+	// X-Total-Count on paginated lists
+	Header http.Header `json:"header,omitempty"`
+}
+
+// New creates a Cache backed by store.
+//
+// Parameters:
+//   - store: Where entries are kept (NewMemoryStore or NewRedisStore)
+//   - opts: TTL, key prefix and bypass rules
+//
+// Returns:
+//   - *Cache: A cache with zeroed statistics
+//
+// Example:
+//
+//	c := cache.New(cache.NewMemoryStore(10000), cache.Options{TTL: time.Minute})
+func New(store Store, opts Options) *Cache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	if opts.MaxEntrySize <= 0 {
+		opts.MaxEntrySize = DefaultMaxEntrySize
+	}
+	if opts.Vary == nil {
+		opts.Vary = defaultVary
+	}
+	return &Cache{store: store, opts: opts}
+}
+
+// Stats returns the hit, miss, invalidation and error counts.
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+		Errors:        c.errors.Load(),
+	}
+}
+
+// Invalidate drops every cached response of a kind.
+//
+// Parameters:
+//   - ctx: Context for the store operation
+//   - kind: Resource kind (e.g., "Device")
+//
+// Returns:
+//   - error: If the store can't be updated
+func (c *Cache) Invalidate(ctx context.Context, kind string) error {
+	if _, err := c.store.Incr(ctx, c.generationKey(kind)); err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("failed to invalidate %s cache: %w", kind, err)
+	}
+	c.invalidations.Add(1)
+	return nil
+}
+
+// SubscribeEvents invalidates a kind whenever a resource event for it is published.
+//
+// Parameters:
+//   - bus: The event bus resource events are published on
+//   - prefix: The resource event type prefix (events.GetEventConfig().EventTypePrefix)
+//
+// Returns:
+//   - events.SubscriptionID: The subscription, for Unsubscribe
+//   - error: If the subscription fails
+func (c *Cache) SubscribeEvents(bus events.EventBus, prefix string) (events.SubscriptionID, error) {
+	return bus.Subscribe(prefix+".**", func(ctx context.Context, event events.Event) error {
+		kind := event.ResourceKind()
+		if kind == "" {
+			return nil
+		}
+		return c.Invalidate(ctx, kind)
+	})
+}
+
+// Middleware caches the GET responses of one resource kind and invalidates
+// the kind after successful writes.
+//
+// Parameters:
+//   - kind: Resource kind served by the wrapped routes (e.g., "Device")
+//
+// Returns:
+//   - func(http.Handler) http.Handler: Middleware for the kind's routes
+func (c *Cache) Middleware(kind string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+				next.ServeHTTP(rec, r)
+				if rec.status < http.StatusBadRequest {
+					c.Invalidate(r.Context(), kind) // nolint:errcheck
+				}
+				return
+			}
+			if c.opts.Bypass != nil && c.opts.Bypass(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.Header.Get("Cache-Control") == "no-cache" {
+				c.misses.Add(1)
+				w.Header().Set(StatusHeader, "MISS")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := c.key(r, kind)
+			if err != nil {
+				c.errors.Add(1)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if e, ok := c.load(r.Context(), key); ok {
+				c.hits.Add(1)
+				w.Header().Set(StatusHeader, "HIT")
+				e.write(w, r)
+				return
+			}
+
+			c.misses.Add(1)
+			rec := &bodyRecorder{ResponseWriter: w, header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			e := entry{Status: rec.status, ContentType: rec.header.Get("Content-Type"), Body: rec.body.Bytes()}
+			e.ETag = rec.header.Get("ETag")
+			for name, values := range rec.header {
+				if !uncachedHeaders[name] {
+					if e.Header == nil {
+						e.Header = http.Header{}
+					}
+					e.Header[name] = values
+				}
+			}
+			if e.ETag == "" {
+				e.ETag = conditional.DefaultETagGenerator(e.Body)
+			}
+			if rec.status == http.StatusOK && rec.header.Get("Set-Cookie") == "" && len(e.Body) <= c.opts.MaxEntrySize {
+				c.save(r.Context(), key, e)
+				rec.header.Set("ETag", e.ETag)
+			}
+
+			for name, values := range rec.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(StatusHeader, "MISS")
+			if rec.status == http.StatusOK {
+				e.write(w, r)
+				return
+			}
+			w.WriteHeader(rec.status)
+			w.Write(e.Body) // nolint:errcheck
+		})
+	}
+}
+
+// key builds the cache key of a GET request
+func (c *Cache) key(r *http.Request, kind string) (string, error) {
+	gen, err := c.generation(r.Context(), kind)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Query().Encode()))
+	for _, name := range c.opts.Vary {
+		h.Write([]byte{0})
+		h.Write([]byte(r.Header.Get(name)))
+	}
+	return fmt.Sprintf("%sresponse:%s:%d:%s", c.opts.KeyPrefix, kind, gen, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// generationKey is the store key of a kind's generation counter
+func (c *Cache) generationKey(kind string) string {
+	return c.opts.KeyPrefix + "generation:" + kind
+}
+
+// generation returns the current generation of a kind.
+// It is read from the store on every request, so replicas sharing a store
+// see each other's invalidations.
+func (c *Cache) generation(ctx context.Context, kind string) (int64, error) {
+	raw, found, err := c.store.Get(ctx, c.generationKey(kind))
+	if err != nil || !found {
+		return 0, err
+	}
+	gen, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache generation for %s: %w", kind, err)
+	}
+	return gen, nil
+}
+
+// load reads an entry from the store
+func (c *Cache) load(ctx context.Context, key string) (entry, bool) {
+	var e entry
+	raw, found, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.errors.Add(1)
+		return e, false
+	}
+	if !found || json.Unmarshal(raw, &e) != nil {
+		return e, false
+	}
+	return e, true
+}
+
+// save writes an entry to the store
+func (c *Cache) save(ctx context.Context, key string, e entry) {
+	raw, err := json.Marshal(e)
+	if err == nil {
+		err = c.store.Set(ctx, key, raw, c.opts.TTL)
+	}
+	if err != nil {
+		c.errors.Add(1)
+	}
+}
+
+// write sends a cached response, or 304 when the client has it
+func (e entry) write(w http.ResponseWriter, r *http.Request) {
+	for name, values := range e.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("ETag", e.ETag)
+	if inm := r.Header.Get("If-None-Match"); inm != "" && conditional.MatchesETag(inm, e.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if e.ContentType != "" {
+		w.Header().Set("Content-Type", e.ContentType)
+	}
+	w.WriteHeader(e.Status)
+	w.Write(e.Body) // nolint:errcheck
+}
+
+// statusRecorder captures the status of a response while passing it through
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// bodyRecorder buffers a response so it can be cached.
+// The wrapped writer is only exposed through Unwrap, so middleware further
+// in (such as i18n) can still find its own writer.
+type bodyRecorder struct {
+	http.ResponseWriter
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bodyRecorder) Header() http.Header {
+	return b.header
+}
+
+func (b *bodyRecorder) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status = status
+		b.wroteHeader = true
+	}
+}
+
+func (b *bodyRecorder) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (b *bodyRecorder) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
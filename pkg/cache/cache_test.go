@@ -0,0 +1,394 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// countingHandler serves a fixed body and counts the GETs it handles
+type countingHandler struct {
+	gets   atomic.Int64
+	status int
+	body   string
+	header http.Header
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		h.gets.Add(1)
+	}
+	for name, values := range h.header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	io.WriteString(w, h.body) // nolint:errcheck
+}
+
+func serve(t *testing.T, h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareHitAndMiss(t *testing.T) {
+	c := New(NewMemoryStore(0), Options{})
+	backend := &countingHandler{status: http.StatusOK, body: `[{"name":"a"}]`, header: http.Header{"X-Total-Count": {"1"}}}
+	h := c.Middleware("Device")(backend)
+
+	first := serve(t, h, "GET", "/devices", nil)
+	if first.Header().Get(StatusHeader) != "MISS" || first.Body.String() != backend.body {
+		t.Fatalf("first request: %s %q", first.Header().Get(StatusHeader), first.Body.String())
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag on the cached response")
+	}
+
+	second := serve(t, h, "GET", "/devices", nil)
+	if second.Header().Get(StatusHeader) != "HIT" || second.Body.String() != backend.body {
+		t.Fatalf("second request: %s %q", second.Header().Get(StatusHeader), second.Body.String())
+	}
+	if second.Header().Get("Content-Type") != "application/json" || second.Header().Get("ETag") != etag || second.Header().Get("X-Total-Count") != "1" {
+		t.Errorf("cached headers: %v", second.Header())
+	}
+
+	revalidate := serve(t, h, "GET", "/devices", http.Header{"If-None-Match": {etag}})
+	if revalidate.Code != http.StatusNotModified || revalidate.Body.Len() != 0 {
+		t.Errorf("If-None-Match: expected 304 without body, got %d %q", revalidate.Code, revalidate.Body.String())
+	}
+
+	if got := backend.gets.Load(); got != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", got)
+	}
+	if stats := c.Stats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestMiddlewareUnwrap(t *testing.T) {
+	c := New(NewMemoryStore(0), Options{})
+	outer := httptest.NewRecorder()
+	var unwrapped http.ResponseWriter
+	h := c.Middleware("Device")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		unwrapped = w.(interface{ Unwrap() http.ResponseWriter }).Unwrap()
+	}))
+	h.ServeHTTP(outer, httptest.NewRequest("GET", "/devices", nil))
+	if unwrapped != outer {
+		t.Error("expected the recorder to unwrap to the outer writer")
+	}
+}
+
+func TestMiddlewareKeys(t *testing.T) {
+	c := New(NewMemoryStore(0), Options{})
+	backend := &countingHandler{status: http.StatusOK, body: `[]`}
+	h := c.Middleware("Device")(backend)
+
+	for _, req := range []struct {
+		target string
+		header http.Header
+	}{
+		{"/devices?query=a&limit=1", nil},
+		{"/devices?limit=1&query=a", nil}, // same selector, different order
+		{"/devices?query=b", nil},
+		{"/devices?query=b", http.Header{"Accept-Language": {"fr"}}},
+		{"/devices?query=b", http.Header{"Authorization": {"Bearer other"}}},
+	} {
+		serve(t, h, "GET", req.target, req.header)
+	}
+	if got := backend.gets.Load(); got != 4 {
+		t.Errorf("expected 4 distinct keys, handler ran %d times", got)
+	}
+}
+
+func TestMiddlewareWritesInvalidate(t *testing.T) {
+	c := New(NewMemoryStore(0), Options{})
+	backend := &countingHandler{status: http.StatusOK, body: `[]`}
+	h := c.Middleware("Device")(backend)
+
+	serve(t, h, "GET", "/devices", nil)
+
+	// A failed write leaves the cache alone
+	backend.status = http.StatusBadRequest
+	serve(t, h, "POST", "/devices", nil)
+	backend.status = http.StatusOK
+	if rec := serve(t, h, "GET", "/devices", nil); rec.Header().Get(StatusHeader) != "HIT" {
+		t.Errorf("expected a hit after a failed write, got %s", rec.Header().Get(StatusHeader))
+	}
+
+	backend.status = http.StatusCreated
+	serve(t, h, "POST", "/devices", nil)
+	backend.status = http.StatusOK
+	if rec := serve(t, h, "GET", "/devices", nil); rec.Header().Get(StatusHeader) != "MISS" {
+		t.Errorf("expected a miss after a write, got %s", rec.Header().Get(StatusHeader))
+	}
+
+	// Other kinds keep their entries
+	other := c.Middleware("Rack")(backend)
+	serve(t, other, "GET", "/racks", nil)
+	serve(t, h, "DELETE", "/devices/dev-1", nil)
+	if rec := serve(t, other, "GET", "/racks", nil); rec.Header().Get(StatusHeader) != "HIT" {
+		t.Errorf("expected writes to Device to keep Rack entries, got %s", rec.Header().Get(StatusHeader))
+	}
+}
+
+func TestMiddlewareSkipsUncacheable(t *testing.T) {
+	c := New(NewMemoryStore(0), Options{
+		MaxEntrySize: 8,
+		Bypass:       func(r *http.Request) bool { return strings.HasSuffix(r.URL.Path, "/lock") },
+	})
+
+	notFound := &countingHandler{status: http.StatusNotFound, body: `{}`}
+	large := &countingHandler{status: http.StatusOK, body: `"larger than eight bytes"`}
+	lock := &countingHandler{status: http.StatusOK, body: `{}`}
+	for name, tc := range map[string]struct {
+		handler *countingHandler
+		target  string
+	}{
+		"error":  {notFound, "/devices/missing"},
+		"large":  {large, "/devices"},
+		"bypass": {lock, "/devices/dev-1/lock"},
+	} {
+		h := c.Middleware("Device")(tc.handler)
+		first := serve(t, h, "GET", tc.target, nil)
+		serve(t, h, "GET", tc.target, nil)
+		if got := tc.handler.gets.Load(); got != 2 {
+			t.Errorf("%s: expected both requests to reach the handler, got %d", name, got)
+		}
+		if first.Code != tc.handler.status || first.Body.String() != tc.handler.body {
+			t.Errorf("%s: response changed: %d %q", name, first.Code, first.Body.String())
+		}
+	}
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	bus := events.NewInMemoryEventBus(10, 1)
+	bus.Start()
+	defer bus.Close()
+
+	c := New(NewMemoryStore(0), Options{})
+	if _, err := c.SubscribeEvents(bus, "io.fabrica"); err != nil {
+		t.Fatal(err)
+	}
+	backend := &countingHandler{status: http.StatusOK, body: `[]`}
+	h := c.Middleware("Device")(backend)
+	serve(t, h, "GET", "/devices", nil)
+
+	event, err := events.NewEvent("io.fabrica.device.updated", "test", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event.SetExtension("resourcekind", "Device")
+	if err := bus.Publish(context.Background(), *event); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Stats().Invalidations == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rec := serve(t, h, "GET", "/devices", nil); rec.Header().Get(StatusHeader) != "MISS" {
+		t.Errorf("expected a miss after a resource event, got %s", rec.Header().Get(StatusHeader))
+	}
+}
+
+func TestMemoryStoreExpiryAndEviction(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(2)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	store.Set(ctx, "a", []byte("1"), time.Minute) // nolint:errcheck
+	store.Set(ctx, "b", []byte("2"), time.Second) // nolint:errcheck
+	store.Get(ctx, "a")                           // nolint:errcheck // a is now the most recently used
+	store.Set(ctx, "c", []byte("3"), time.Minute) // nolint:errcheck
+
+	if _, ok, _ := store.Get(ctx, "b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if v, ok, _ := store.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Errorf("expected a to be kept, got %q %v", v, ok)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := store.Get(ctx, "c"); ok {
+		t.Error("expected c to expire")
+	}
+
+	for i := 1; i <= 3; i++ {
+		if n, _ := store.Incr(ctx, "gen"); n != int64(i) {
+			t.Errorf("Incr = %d, want %d", n, i)
+		}
+	}
+	if v, ok, _ := store.Get(ctx, "gen"); !ok || string(v) != "3" {
+		t.Errorf("expected counter to read as 3, got %q", v)
+	}
+}
+
+// fakeRedis is a minimal RESP server supporting the commands RedisStore uses
+type fakeRedis struct {
+	mu       sync.Mutex
+	values   map[string]string
+	password string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	f := &fakeRedis{values: map[string]string{}, password: password}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, l.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		if n == 0 {
+			return
+		}
+		for i := range args {
+			header, _ := r.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			arg := make([]byte, size+2)
+			if _, err := io.ReadFull(r, arg); err != nil {
+				return
+			}
+			args[i] = string(arg[:size])
+		}
+
+		f.mu.Lock()
+		name := strings.ToUpper(args[0])
+		var reply string
+		switch {
+		case name == "HELLO" || name == "CLIENT":
+			// RESP3 and client info are optional; clients fall back without them
+			reply = "-ERR unknown command\r\n"
+		case name == "AUTH":
+			authed = args[len(args)-1] == f.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case name == "SELECT":
+			reply = "+OK\r\n"
+		case name == "GET":
+			v, ok := f.values[args[1]]
+			reply = "$-1\r\n"
+			if ok {
+				reply = "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+			}
+		case name == "SET":
+			f.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case name == "INCR":
+			n, _ := strconv.Atoi(f.values[args[1]])
+			f.values[args[1]] = strconv.Itoa(n + 1)
+			reply = ":" + f.values[args[1]] + "\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if !strings.HasPrefix(reply, "-ERR unknown") {
+			f.commands = append(f.commands, name)
+		}
+		f.mu.Unlock()
+		conn.Write([]byte(reply)) // nolint:errcheck
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	fake, addr := startFakeRedis(t, "secret")
+	store := NewRedisStore(RedisConfig{Addr: addr, Password: "secret", DB: 2})
+	defer store.Close()
+
+	if _, ok, err := store.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Get(missing) = %v, %v", ok, err)
+	}
+	value := "line one\r\nline two"
+	if err := store.Set(ctx, "k", []byte(value), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := store.Get(ctx, "k"); !ok || err != nil || string(v) != value {
+		t.Fatalf("Get(k) = %q, %v, %v", v, ok, err)
+	}
+	if n, err := store.Incr(ctx, "gen"); n != 1 || err != nil {
+		t.Fatalf("Incr = %d, %v", n, err)
+	}
+
+	fake.mu.Lock()
+	commands := strings.Join(fake.commands, " ")
+	fake.mu.Unlock()
+	if commands != "AUTH SELECT GET SET GET INCR" {
+		t.Errorf("expected one connection to be reused, got commands %q", commands)
+	}
+
+	bad := NewRedisStore(RedisConfig{Addr: addr, Password: "wrong"})
+	defer bad.Close()
+	if _, _, err := bad.Get(ctx, "k"); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("expected an authentication error, got %v", err)
+	}
+}
+
+func TestRedisBackedMiddleware(t *testing.T) {
+	_, addr := startFakeRedis(t, "")
+	store := NewRedisStore(RedisConfig{Addr: addr})
+	defer store.Close()
+
+	// Two replicas sharing a store see each other's invalidations
+	backend := &countingHandler{status: http.StatusOK, body: `[]`}
+	a := New(store, Options{KeyPrefix: "svc:"}).Middleware("Device")(backend)
+	b := New(store, Options{KeyPrefix: "svc:"}).Middleware("Device")(backend)
+
+	serve(t, a, "GET", "/devices", nil)
+	if rec := serve(t, b, "GET", "/devices", nil); rec.Header().Get(StatusHeader) != "HIT" {
+		t.Fatalf("expected replica b to hit the shared entry, got %s", rec.Header().Get(StatusHeader))
+	}
+	serve(t, a, "PUT", "/devices/dev-1", nil)
+	if rec := serve(t, b, "GET", "/devices", nil); rec.Header().Get(StatusHeader) != "MISS" {
+		t.Errorf("expected replica b to miss after a write on a, got %s", rec.Header().Get(StatusHeader))
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries is the size of a MemoryStore created with maxEntries <= 0.
+const DefaultMaxEntries = 10000
+
+// MemoryStore is a Store kept in process memory.
+// When full, it evicts the least recently used entry.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	counters   map[string]int64
+	now        func() time.Time
+}
+
+// memoryItem is an entry of the LRU list
+type memoryItem struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore creates an in-memory Store holding up to maxEntries responses.
+//
+// Parameters:
+//   - maxEntries: Largest number of cached responses (default: DefaultMaxEntries)
+//
+// Returns:
+//   - *MemoryStore: An empty store
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		counters:   map[string]int64{},
+		now:        time.Now,
+	}
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if n, ok := m.counters[key]; ok {
+		return []byte(strconv.FormatInt(n, 10)), true, nil
+	}
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := el.Value.(*memoryItem)
+	if !m.now().Before(item.expires) {
+		m.remove(el)
+		return nil, false, nil
+	}
+	m.lru.MoveToFront(el)
+	return item.value, true, nil
+}
+
+// Set implements Store.
+func (m *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expires := m.now().Add(ttl)
+	if el, ok := m.entries[key]; ok {
+		item := el.Value.(*memoryItem)
+		item.value, item.expires = value, expires
+		m.lru.MoveToFront(el)
+		return nil
+	}
+	m.entries[key] = m.lru.PushFront(&memoryItem{key: key, value: value, expires: expires})
+	for m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+	return nil
+}
+
+// Incr implements Store.
+func (m *MemoryStore) Incr(_ context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[key]++
+	return m.counters[key], nil
+}
+
+// Len returns the number of cached responses, including expired ones not yet evicted.
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+// remove drops an entry; the caller holds the lock
+func (m *MemoryStore) remove(el *list.Element) {
+	m.lru.Remove(el)
+	delete(m.entries, el.Value.(*memoryItem).key)
+}
@@ -0,0 +1,118 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig configures a RedisStore.
+type RedisConfig struct {
+	// Addr is the host:port of the Redis server (default: localhost:6379)
+	Addr string `json:"addr" yaml:"addr"`
+
+	// Username and Password authenticate with AUTH when set
+	Username string `json:"-" yaml:"-"`
+	Password string `json:"-" yaml:"-"`
+
+	// DB is the database number selected on connect
+	DB int `json:"db,omitempty" yaml:"db,omitempty"`
+
+	// Timeout bounds dialing and each command (default: 2s)
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// PoolSize is the most connections open at once (default: 10)
+	PoolSize int `json:"poolSize,omitempty" yaml:"poolSize,omitempty"`
+}
+
+// RedisConfigFromEnv reads a RedisConfig from environment variables:
+// FABRICA_REDIS_ADDR, FABRICA_REDIS_USERNAME, FABRICA_REDIS_PASSWORD and FABRICA_REDIS_DB.
+func RedisConfigFromEnv() RedisConfig {
+	db, _ := strconv.Atoi(os.Getenv("FABRICA_REDIS_DB"))
+	return RedisConfig{
+		Addr:     os.Getenv("FABRICA_REDIS_ADDR"),
+		Username: os.Getenv("FABRICA_REDIS_USERNAME"),
+		Password: os.Getenv("FABRICA_REDIS_PASSWORD"),
+		DB:       db,
+	}
+}
+
+// RedisStore is a Store kept in Redis, so replicas share cached responses
+// and see each other's invalidations.
+//
+// It uses the go-redis client, like the Redis storage backend, and only
+// runs GET, SET with an expiry and INCR, so it also works with compatible
+// servers such as Valkey and KeyDB.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a Redis store. Connections are opened on first use.
+//
+// Parameters:
+//   - cfg: Server address and credentials
+//
+// Returns:
+//   - *RedisStore: A store; call Close to release its connections
+func NewRedisStore(cfg RedisConfig) *RedisStore {
+	if cfg.Addr == "" {
+		cfg.Addr = "localhost:6379"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 10
+	}
+	return &RedisStore{client: redis.NewClient(&redis.Options{
+		Addr:                  cfg.Addr,
+		Username:              cfg.Username,
+		Password:              cfg.Password,
+		DB:                    cfg.DB,
+		DialTimeout:           cfg.Timeout,
+		ReadTimeout:           cfg.Timeout,
+		WriteTimeout:          cfg.Timeout,
+		ContextTimeoutEnabled: true,
+		PoolSize:              cfg.PoolSize,
+		// Failing fast serves the request uncached instead of retrying
+		MaxRetries: -1,
+	})}
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set implements Store.
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < time.Millisecond {
+		ttl = time.Millisecond
+	}
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Incr implements Store.
+func (s *RedisStore) Incr(ctx context.Context, key string) (int64, error) {
+	return s.client.Incr(ctx, key).Result()
+}
+
+// Close closes the connections.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
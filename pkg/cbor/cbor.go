@@ -0,0 +1,123 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package cbor converts between JSON and CBOR (RFC 8949).
+//
+// Generated servers answer requests sent with Accept: application/cbor in
+// CBOR, and accept CBOR request bodies, for embedded clients such as BMCs
+// that can't afford to parse JSON. CBOR has the same data model as JSON, so
+// a value is encoded through its JSON form (RFC 8949, section 6.2): field
+// names, omitempty and custom JSON marshalers all carry over, and the Go
+// types don't need CBOR-specific tags.
+//
+// Encoding is deterministic: map keys are sorted, and lengths and numbers
+// use their shortest form. Integers stay integers, and floats use the
+// smallest precision that holds them exactly.
+//
+// Usage:
+//
+//	data, err := cbor.Marshal(device)
+//	err = cbor.Unmarshal(data, &device)
+package cbor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+)
+
+// ContentType is the media type of CBOR requests and responses
+const ContentType = "application/cbor"
+
+// IsCBOR reports whether a Content-Type or Accept media type is CBOR.
+//
+// Parameters:
+//   - mediaType: Header value, with or without parameters
+//
+// Returns:
+//   - bool: True for application/cbor
+func IsCBOR(mediaType string) bool {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	return err == nil && parsed == ContentType
+}
+
+// Marshal encodes the JSON form of v as CBOR.
+//
+// Parameters:
+//   - v: Any value encoding/json can marshal
+//
+// Returns:
+//   - []byte: The CBOR data item
+//   - error: If v can't be marshaled
+func Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
+// Unmarshal decodes a CBOR data item into v, like json.Unmarshal decodes
+// the equivalent JSON document.
+//
+// Parameters:
+//   - data: A CBOR data item
+//   - v: Pointer to the value to fill
+//
+// Returns:
+//   - error: If data isn't valid CBOR or doesn't fit v
+func Unmarshal(data []byte, v interface{}) error {
+	doc, err := ToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(doc, v)
+}
+
+// FromJSON converts a JSON document to CBOR.
+//
+// Parameters:
+//   - data: A JSON document
+//
+// Returns:
+//   - []byte: The CBOR data item
+//   - error: If data isn't valid JSON
+func FromJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("cbor: invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("cbor: invalid JSON: more than one value")
+	}
+	return appendValue(nil, doc)
+}
+
+// ToJSON converts a CBOR data item to JSON. Byte strings become base64
+// strings, as encoding/json writes []byte values, and tags are dropped.
+//
+// Parameters:
+//   - data: A CBOR data item
+//
+// Returns:
+//   - []byte: The JSON document
+//   - error: If data isn't valid CBOR or has no JSON equivalent (e.g., NaN)
+func ToJSON(data []byte) ([]byte, error) {
+	d := &decoder{data: data}
+	doc, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("cbor: %d bytes after the data item", len(d.data)-d.pos)
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,188 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cbor
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Examples from RFC 8949, appendix A
+var examples = []struct {
+	json string
+	cbor string
+}{
+	{`0`, "00"},
+	{`23`, "17"},
+	{`24`, "1818"},
+	{`1000`, "1903e8"},
+	{`1000000000000`, "1b000000e8d4a51000"},
+	{`18446744073709551615`, "1bffffffffffffffff"},
+	{`-1`, "20"},
+	{`-1000`, "3903e7"},
+	{`100000.0`, "fa47c35000"},
+	{`1.1`, "fb3ff199999999999a"},
+	{`-4.1`, "fbc010666666666666"},
+	{`false`, "f4"},
+	{`true`, "f5"},
+	{`null`, "f6"},
+	{`""`, "60"},
+	{`"IETF"`, "6449455446"},
+	{`"ü"`, "62c3bc"},
+	{`[1,[2,3],[4,5]]`, "8301820203820405"},
+	{`{"a":1,"b":[2,3]}`, "a26161016162820203"},
+	{`{"aa":1,"b":2}`, "a261620262616101"}, // shorter keys first
+}
+
+func TestFromJSON(t *testing.T) {
+	for _, ex := range examples {
+		got, err := FromJSON([]byte(ex.json))
+		if err != nil {
+			t.Errorf("FromJSON(%s): %v", ex.json, err)
+			continue
+		}
+		if hex.EncodeToString(got) != ex.cbor {
+			t.Errorf("FromJSON(%s) = %x, want %s", ex.json, got, ex.cbor)
+		}
+	}
+	if _, err := FromJSON([]byte(`{"a":`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	decoded := []struct {
+		cbor string
+		json string
+	}{
+		{"f90000", `0`},   // half-precision float
+		{"f93e00", `1.5`}, // half-precision float
+		{"c074323031332d30332d32315432303a30343a30305a", `"2013-03-21T20:04:00Z"`}, // tag 0
+		{"4401020304", `"AQIDBA=="`}, // byte string
+		{"7f657374726561646d696e67ff", `"streaming"`},
+		{"9f018202039f0405ffff", `[1,[2,3],[4,5]]`},
+		{"bf61610161629f0203ffff", `{"a":1,"b":[2,3]}`},
+		{"a10102", `{"1":2}`},
+		{"3bffffffffffffffff", `-18446744073709551616`},
+		{"f7", `null`},
+	}
+	for _, ex := range examples {
+		decoded = append(decoded, struct{ cbor, json string }{ex.cbor, ex.json})
+	}
+	for _, d := range decoded {
+		data, _ := hex.DecodeString(d.cbor)
+		got, err := ToJSON(data)
+		if err != nil {
+			t.Errorf("ToJSON(%s): %v", d.cbor, err)
+			continue
+		}
+		if string(got) != d.json && !(d.json == `100000.0` && string(got) == `100000`) {
+			t.Errorf("ToJSON(%s) = %s, want %s", d.cbor, got, d.json)
+		}
+	}
+}
+
+func TestToJSON_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",         // empty
+		"1903",     // truncated argument
+		"62c3",     // truncated string
+		"f97e00",   // NaN
+		"a1f600",   // null key
+		"0000",     // trailing data
+		"1c",       // reserved additional information
+		"ff",       // break outside an indefinite-length item
+		"7f6161",   // unterminated indefinite-length string
+		"7f4161ff", // byte chunk in a text string
+		"62c328",   // invalid UTF-8
+	} {
+		data, _ := hex.DecodeString(input)
+		if _, err := ToJSON(data); err == nil {
+			t.Errorf("ToJSON(%s): expected an error", input)
+		}
+	}
+
+	deep := strings.Repeat("81", maxDepth+1) + "00"
+	data, _ := hex.DecodeString(deep)
+	if _, err := ToJSON(data); err == nil {
+		t.Error("expected an error for deeply nested arrays")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	type spec struct {
+		Model   string            `json:"model"`
+		Ports   []int             `json:"ports"`
+		Weight  float64           `json:"weight"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Created time.Time         `json:"created"`
+		Blob    []byte            `json:"blob"`
+	}
+	in := spec{Model: "r650", Ports: []int{1, -2}, Weight: 0.1, Created: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Blob: []byte{0, 1}}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out spec
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip changed the value:\n in  %+v\n out %+v", in, out)
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/cbor", true},
+		{"application/cbor, application/json", true},
+		{"application/json, application/cbor", false},
+		{"application/cbor;q=0.5, */*", false},
+		{"text/html, application/cbor;q=0.1", true},
+	}
+	for _, tt := range tests {
+		if got := Preferred(tt.accept); got != tt.want {
+			t.Errorf("Preferred(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var requested bool
+	var body, contentType string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = Requested(w)
+		data, _ := io.ReadAll(r.Body)
+		body, contentType = string(data), r.Header.Get("Content-Type")
+	}))
+
+	data, _ := hex.DecodeString("a1646e616d65656465762d31") // {"name":"dev-1"}
+	req := httptest.NewRequest(http.MethodPost, "/devices", strings.NewReader(string(data)))
+	req.Header.Set("Content-Type", "application/cbor;version=v2")
+	req.Header.Set("Accept", ContentType)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !requested || body != `{"name":"dev-1"}` || contentType != "application/json; version=v2" {
+		t.Errorf("unexpected request: requested=%v body=%s content type=%s", requested, body, contentType)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/devices", strings.NewReader("\xff"))
+	req.Header.Set("Content-Type", ContentType)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid body, got %d", rec.Code)
+	}
+}
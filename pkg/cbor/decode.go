@@ -0,0 +1,214 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cbor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+)
+
+// maxDepth limits the nesting of arrays, maps and tags
+const maxDepth = 1000
+
+var errTruncated = errors.New("cbor: data item is truncated")
+
+// decoder reads CBOR data items into JSON values
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+// value decodes the data item at the current position
+func (d *decoder) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.New("cbor: data item is nested too deeply")
+	}
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	indefinite := info == 31
+
+	switch major {
+	case majorUint:
+		return json.Number(strconv.FormatUint(arg, 10)), nil
+	case majorNegint:
+		if arg <= math.MaxInt64 {
+			return json.Number(strconv.FormatInt(-1-int64(arg), 10)), nil
+		}
+		n := new(big.Int).SetUint64(arg)
+		return json.Number(n.Neg(n.Add(n, big.NewInt(1))).String()), nil
+	case majorBytes, majorText:
+		s, err := d.str(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == majorBytes {
+			return s, nil
+		}
+		if !utf8.Valid(s) {
+			return nil, errors.New("cbor: text string is not valid UTF-8")
+		}
+		return string(s), nil
+	case majorArray:
+		items := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case majorMap:
+		m := map[string]interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			value, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch k := key.(type) {
+			case string:
+				m[k] = value
+			case json.Number:
+				m[string(k)] = value
+			default:
+				return nil, fmt.Errorf("cbor: map key of type %T has no JSON equivalent", key)
+			}
+		}
+		return m, nil
+	case majorTag:
+		// Tags (e.g., 0 for date strings) only qualify their content
+		return d.value(depth + 1)
+	}
+
+	switch info {
+	case simpleFalse:
+		return false, nil
+	case simpleTrue:
+		return true, nil
+	case simpleNull, simpleUndefined:
+		return nil, nil
+	case float16Info:
+		return finite(halfToFloat(uint16(arg)))
+	case float32Info:
+		return finite(float64(math.Float32frombits(uint32(arg))))
+	case float64Info:
+		return finite(math.Float64frombits(arg))
+	}
+	return nil, fmt.Errorf("cbor: unsupported simple value %d", arg)
+}
+
+// head reads the initial byte of a data item and its argument
+func (d *decoder) head() (major byte, info byte, arg uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, 0, errTruncated
+	}
+	major, info = d.data[d.pos]>>5, d.data[d.pos]&0x1f
+	d.pos++
+
+	size := 0
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size = 1 << (info - 24)
+	case info == 31 && major >= majorBytes && major <= majorMap:
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("cbor: invalid initial byte 0x%02x", d.data[d.pos-1])
+	}
+	if len(d.data)-d.pos < size {
+		return 0, 0, 0, errTruncated
+	}
+	for _, c := range d.data[d.pos : d.pos+size] {
+		arg = arg<<8 | uint64(c)
+	}
+	d.pos += size
+	return major, info, arg, nil
+}
+
+// str reads the content of a byte or text string; indefinite-length strings
+// are the concatenation of definite-length chunks of the same type
+func (d *decoder) str(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		if uint64(len(d.data)-d.pos) < arg {
+			return nil, errTruncated
+		}
+		s := d.data[d.pos : d.pos+int(arg)]
+		d.pos += int(arg)
+		return s, nil
+	}
+	var s []byte
+	for !d.atBreak() {
+		chunkMajor, info, size, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || info == 31 {
+			return nil, errors.New("cbor: invalid chunk in an indefinite-length string")
+		}
+		chunk, err := d.str(major, size, false)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, chunk...)
+	}
+	return s, nil
+}
+
+// atBreak consumes the break byte ending an indefinite-length item
+func (d *decoder) atBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == breakByte {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+// finite rejects floats that JSON can't represent
+func finite(f float64) (interface{}, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("cbor: %v has no JSON equivalent", f)
+	}
+	return f, nil
+}
+
+// halfToFloat converts an IEEE 754 half-precision float (RFC 8949, appendix D)
+func halfToFloat(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1f, float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,127 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cbor
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Major types
+const (
+	majorUint   = 0
+	majorNegint = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Simple values and float headers of major type 7
+const (
+	simpleFalse     = 20
+	simpleTrue      = 21
+	simpleNull      = 22
+	simpleUndefined = 23
+	float16Info     = 25
+	float32Info     = 26
+	float64Info     = 27
+	breakByte       = 0xff
+)
+
+// appendValue appends a value decoded from JSON with UseNumber
+func appendValue(b []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case nil:
+		return append(b, majorSimple<<5|simpleNull), nil
+	case bool:
+		if v {
+			return append(b, majorSimple<<5|simpleTrue), nil
+		}
+		return append(b, majorSimple<<5|simpleFalse), nil
+	case json.Number:
+		return appendNumber(b, v)
+	case string:
+		b = appendHead(b, majorText, uint64(len(v)))
+		return append(b, v...), nil
+	case []interface{}:
+		b = appendHead(b, majorArray, uint64(len(v)))
+		for _, item := range v {
+			if b, err = appendValue(b, item); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]interface{}:
+		// Deterministic order: shorter keys first, then bytewise, which is
+		// the order of the encoded keys (RFC 8949, section 4.2.1)
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		b = appendHead(b, majorMap, uint64(len(v)))
+		for _, k := range keys {
+			b = appendHead(b, majorText, uint64(len(k)))
+			b = append(b, k...)
+			if b, err = appendValue(b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("cbor: unexpected JSON value %T", v)
+}
+
+// appendNumber appends an integer, or a float in the smallest precision
+// that holds it exactly
+func appendNumber(b []byte, n json.Number) ([]byte, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		if i < 0 {
+			return appendHead(b, majorNegint, uint64(-1-i)), nil
+		}
+		return appendHead(b, majorUint, uint64(i)), nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return appendHead(b, majorUint, u), nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: invalid number %s", n)
+	}
+	if float64(float32(f)) == f {
+		b = append(b, majorSimple<<5|float32Info)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(float32(f))), nil
+	}
+	b = append(b, majorSimple<<5|float64Info)
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f)), nil
+}
+
+// appendHead appends the initial byte of a data item and its argument
+func appendHead(b []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(b, major<<5|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, major<<5|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, major<<5|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, major<<5|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(b, major<<5|27), arg)
+}
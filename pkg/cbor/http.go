@@ -0,0 +1,115 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cbor
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Preferred reports whether an Accept header prefers CBOR to JSON. The
+// media type with the highest quality wins, and the earlier one on a tie.
+// Wildcards count as JSON, so clients have to ask for CBOR explicitly.
+//
+// Parameters:
+//   - accept: Accept header value
+//
+// Returns:
+//   - bool: True if the response should be CBOR
+func Preferred(accept string) bool {
+	best, preferred := 0.0, false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		isCBOR := mediaType == ContentType
+		switch {
+		case isCBOR, mediaType == "application/json", mediaType == "application/*", mediaType == "*/*":
+		default:
+			continue
+		}
+		if q > best {
+			best, preferred = q, isCBOR
+		}
+	}
+	return preferred
+}
+
+// Middleware negotiates CBOR responses from the Accept header and converts
+// CBOR request bodies to JSON.
+//
+// The negotiated encoding is stored on the response writer (see Requested),
+// so the helpers writing responses can pick it without the request. Request
+// bodies sent with Content-Type application/cbor reach the handlers as
+// JSON, with the media type parameters (such as version) kept. A body that
+// isn't valid CBOR gets a 400 response.
+//
+// Example:
+//
+//	r.Group(func(r chi.Router) {
+//	    r.Use(cbor.Middleware)
+//	    r.Post("/devices", CreateDevice)
+//	})
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType := r.Header.Get("Content-Type"); IsCBOR(contentType) && r.Body != nil {
+			data, err := io.ReadAll(r.Body)
+			if err == nil {
+				data, err = ToJSON(data)
+			}
+			if err != nil {
+				http.Error(w, "invalid CBOR request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, params, _ := mime.ParseMediaType(contentType)
+			r = r.Clone(r.Context())
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			r.ContentLength = int64(len(data))
+			r.Header.Set("Content-Type", mime.FormatMediaType("application/json", params))
+		}
+		if Preferred(r.Header.Get("Accept")) {
+			w = &cborWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Requested reports whether Middleware chose CBOR for a response. Writers
+// wrapped by other middleware are unwrapped through their Unwrap method.
+func Requested(w http.ResponseWriter) bool {
+	for w != nil {
+		if _, ok := w.(*cborWriter); ok {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+	return false
+}
+
+// cborWriter marks a response that should be CBOR
+type cborWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController
+func (w *cborWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
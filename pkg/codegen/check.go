@@ -0,0 +1,129 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/validation"
+)
+
+// ResourceIssue is a problem of a registered resource, or of the project's
+// resources as a whole, found by CheckResources before generation
+type ResourceIssue struct {
+	Resource string // Resource name (e.g., "Device"), empty for the project
+	Problem  string // What is wrong (e.g., "has no Status field")
+	Fix      string // How to fix it
+	Warning  bool   // Whether generated code works regardless
+}
+
+// String returns the issue as "Device: has no Status field"
+func (i ResourceIssue) String() string {
+	if i.Resource == "" {
+		return i.Problem
+	}
+	return fmt.Sprintf("%s: %s", i.Resource, i.Problem)
+}
+
+// CheckResources checks the registered resources for problems that fail
+// generation or the generated code: Spec and Status structs missing or
+// named other than generated code expects, fields that enabled features
+// need, kinds sharing a name or plural, validate tags that can't be
+// evaluated, and schema versions that aren't served or are served by no
+// resource.
+func (g *Generator) CheckResources() []ResourceIssue {
+	var issues []ResourceIssue
+	issue := func(resource, problem, fix string) {
+		issues = append(issues, ResourceIssue{Resource: resource, Problem: problem, Fix: fix})
+	}
+	warning := func(resource, problem, fix string) {
+		issues = append(issues, ResourceIssue{Resource: resource, Problem: problem, Fix: fix, Warning: true})
+	}
+
+	kinds := make(map[string]string)   // kind -> package
+	plurals := make(map[string]string) // plural -> kind
+	versions := make(map[string]bool)  // schema versions of any resource
+	for _, res := range g.Resources {
+		if pkg, ok := kinds[res.Name]; ok && pkg != res.Package {
+			issue(res.Name, fmt.Sprintf("is declared in both %s and %s", pkg, res.Package),
+				"rename one of the kinds; generated handlers, storage and routes are named after the kind")
+		}
+		kinds[res.Name] = res.Package
+
+		if kind, ok := plurals[res.PluralName]; ok && kind != res.Name {
+			issue(res.Name, fmt.Sprintf("has the plural %q of %s, so both are served at /%s", res.PluralName, kind, res.PluralName),
+				fmt.Sprintf("set another plural with a %s:\"...\" tag on the Spec field of one of them", PluralTag))
+		}
+		plurals[res.PluralName] = res.Name
+
+		for _, v := range res.Versions {
+			versions[v.Version] = true
+		}
+
+		if res.goType == nil {
+			continue
+		}
+		for _, part := range []string{"Spec", "Status"} {
+			want := res.Name + part
+			// Fields promoted from resource.Resource don't count
+			field, ok := res.goType.FieldByName(part)
+			switch {
+			case !ok || len(field.Index) > 1:
+				issue(res.Name, fmt.Sprintf("has no %s field", part),
+					fmt.Sprintf("add `%s %s` with a %s struct; generated code uses both Spec and Status", part, want, want))
+				continue
+			case field.Type.Name() != want || field.Type.PkgPath() != res.goType.PkgPath():
+				issue(res.Name, fmt.Sprintf("has a %s field of type %s", part, field.Type),
+					fmt.Sprintf("declare %s as a %s struct in the package of %s; generated code refers to it by name", part, want, res.Name))
+				continue
+			case field.Type.Kind() != reflect.Struct:
+				issue(res.Name, fmt.Sprintf("%s is not a struct", want), fmt.Sprintf("declare %s as a struct", want))
+				continue
+			}
+			if part == "Status" && res.Tags["versioning"] == "enabled" {
+				if version, ok := field.Type.FieldByName("Version"); !ok || version.Type.Kind() != reflect.String {
+					issue(res.Name, "has per-resource versioning but no Status.Version string field",
+						fmt.Sprintf("add `Version string `json:\"version,omitempty\"`` to %s, which records the current snapshot", want))
+				}
+			}
+		}
+
+		if err := validation.CheckTags(res.goType); err != nil {
+			issue(res.Name, fmt.Sprintf("has a validate tag that can't be evaluated: %v", err),
+				"fix the tag, or register custom validations with validation.RegisterCustomValidator in an init function of the resource's package")
+		}
+	}
+
+	// Route trees serve every resource in the listed versions, converting
+	// from the stored version; schema versions outside them aren't served
+	if g.Config.VersioningEnabled && (g.Config.VersionStrategy == "url" || g.Config.VersionStrategy == "both") {
+		served := make(map[string]bool)
+		for _, version := range g.Config.URLVersions() {
+			served[version] = true
+			if !versions[version] {
+				warning("", fmt.Sprintf("version %s is served, but no resource has a %s schema", version, version),
+					fmt.Sprintf("add one with 'fabrica add version <resource> %s', or resources are served in %s unchanged", version, version))
+			}
+		}
+		for _, res := range g.Resources {
+			var unserved []string
+			for _, v := range res.Versions {
+				if !served[v.Version] {
+					unserved = append(unserved, v.Version)
+				}
+			}
+			sort.Strings(unserved)
+			if len(unserved) > 0 {
+				warning(res.Name, fmt.Sprintf("has schema version(s) %s that aren't served", strings.Join(unserved, ", ")),
+					"add them to features.versioning.versions in .fabrica.yaml")
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,395 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package codegentest provides a golden-file harness for Fabrica code generation.
+//
+// Projects that customize templates (or depend on the exact shape of the
+// generated code) can lock in the output of the generator with a single test.
+// When fabrica is upgraded or a template override changes, the test fails with
+// a per-file report of what changed instead of the difference surfacing later
+// as a compile error or a behavior change.
+//
+// Render runs the generator the same way "fabrica generate" does (server,
+// client and, optionally, reconcilers) into a temporary directory.
+// AssertGolden compares that tree against a directory of golden files.
+// Timestamps in "Generated:" headers are normalized so the output is stable
+// across runs.
+//
+// Usage:
+//
+//	func TestGeneratedCode(t *testing.T) {
+//	    codegentest.AssertGolden(t, "testdata/golden", codegentest.Options{
+//	        ModulePath: "github.com/example/inventory",
+//	        Configure: func(g *codegen.Generator) {
+//	            g.Config.EventsEnabled = true
+//	        },
+//	    }, &device.Device{}, &rack.Rack{})
+//	}
+//
+// To create or refresh the golden files after an intended change, run the
+// test with FABRICA_UPDATE_GOLDEN=1 and review the diff:
+//
+//	FABRICA_UPDATE_GOLDEN=1 go test ./... -run TestGeneratedCode
+//
+// Golden files are stored with a ".golden" suffix so the Go tooling never
+// tries to compile them, wherever the golden directory lives.
+package codegentest
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/codegen"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite the
+// golden files instead of comparing against them.
+const UpdateEnv = "FABRICA_UPDATE_GOLDEN"
+
+// goldenSuffix is appended to every file name in the golden directory
+const goldenSuffix = ".golden"
+
+// maxReportedDiffs limits the number of file differences listed in a failure
+const maxReportedDiffs = 20
+
+// generatedAtPattern matches the generation timestamp in file headers
+var generatedAtPattern = regexp.MustCompile(`(?m)^(\s*(?://|#)?\s*Generated: ).*$`)
+
+// chdirMu serializes rendering, since the generator writes some files
+// relative to the working directory
+var chdirMu sync.Mutex
+
+// Options controls how Render runs the generator.
+type Options struct {
+	// ModulePath is the Go module path of the generated project.
+	// Defaults to "example.com/golden".
+	ModulePath string
+
+	// StorageType is the storage backend to generate ("file" or "ent").
+	// Defaults to "file".
+	StorageType string
+
+	// DBDriver is the database driver for Ent storage.
+	// Defaults to "sqlite".
+	DBDriver string
+
+	// Reconcile also generates reconcilers into pkg/reconcilers.
+	Reconcile bool
+
+	// Configure is called on each generator after resources are registered
+	// and before generation, to enable features or set resource tags.
+	Configure func(*codegen.Generator)
+
+	// Update rewrites the golden files instead of comparing against them.
+	// It is also enabled by setting FABRICA_UPDATE_GOLDEN=1.
+	Update bool
+}
+
+// withDefaults returns a copy of the options with defaults filled in
+func (o Options) withDefaults() Options {
+	if o.ModulePath == "" {
+		o.ModulePath = "example.com/golden"
+	}
+	if o.StorageType == "" {
+		o.StorageType = "file"
+	}
+	if o.DBDriver == "" {
+		o.DBDriver = "sqlite"
+	}
+	return o
+}
+
+// Render generates code for resources into a temporary directory.
+//
+// The layout matches a project generated by "fabrica generate": server code
+// in cmd/server, storage and middleware in internal/, the client library in
+// pkg/client and the client CLI in cmd/client.
+//
+// Because the generator writes some files relative to the working directory,
+// Render changes directory while generating. Renders are serialized with each
+// other, but tests that depend on the working directory should not run in
+// parallel with it.
+//
+// Parameters:
+//   - t: The test; generation errors fail it immediately
+//   - opts: Generator options
+//   - resources: Resource types to register, e.g. &device.Device{}
+//
+// Returns:
+//   - string: The root of the rendered tree, removed when the test ends
+//
+// Example:
+//
+//	dir := codegentest.Render(t, codegentest.Options{}, &device.Device{})
+//	data, _ := os.ReadFile(filepath.Join(dir, "cmd/server/routes_generated.go"))
+func Render(t testing.TB, opts Options, resources ...interface{}) string {
+	t.Helper()
+	opts = opts.withDefaults()
+	dir := t.TempDir()
+	if err := render(dir, opts, resources); err != nil {
+		t.Fatalf("codegentest: %v", err)
+	}
+	return dir
+}
+
+// render runs each generator with dir as the working directory
+func render(dir string, opts Options, resources []interface{}) (err error) {
+	chdirMu.Lock()
+	defer chdirMu.Unlock()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return err
+	}
+	defer func() {
+		if chdirErr := os.Chdir(wd); chdirErr != nil && err == nil {
+			err = chdirErr
+		}
+	}()
+
+	steps := []struct {
+		outputDir, packageName string
+	}{
+		{"cmd/server", "main"},
+		{"pkg/client", "client"},
+	}
+	if opts.Reconcile {
+		steps = append(steps, struct{ outputDir, packageName string }{"pkg/reconcilers", "reconcile"})
+	}
+
+	for _, step := range steps {
+		gen, err := newGenerator(step.outputDir, step.packageName, opts, resources)
+		if err != nil {
+			return err
+		}
+		if err := gen.GenerateAll(); err != nil {
+			return fmt.Errorf("failed to generate %s code: %w", step.packageName, err)
+		}
+		if step.packageName == "client" {
+			if err := gen.GenerateClientCmd(); err != nil {
+				return fmt.Errorf("failed to generate client CLI: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// newGenerator creates a configured generator with resources registered
+func newGenerator(outputDir, packageName string, opts Options, resources []interface{}) (*codegen.Generator, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	gen := codegen.NewGenerator(outputDir, packageName, opts.ModulePath)
+	gen.SetStorageType(opts.StorageType)
+	gen.SetDBDriver(opts.DBDriver)
+	gen.Config.StorageType = opts.StorageType
+	gen.Config.DBDriver = opts.DBDriver
+
+	for _, resource := range resources {
+		if err := gen.RegisterResource(resource); err != nil {
+			return nil, fmt.Errorf("failed to register %T: %w", resource, err)
+		}
+	}
+	if opts.Configure != nil {
+		opts.Configure(gen)
+	}
+	return gen, nil
+}
+
+// AssertGolden renders code for resources and compares it with the golden
+// files in goldenDir.
+//
+// Every generated file must have a golden counterpart with identical content,
+// and every golden file must still be generated. The test fails with a list
+// of missing, unexpected and changed files, showing the first differing line
+// of each change.
+//
+// With Options.Update or FABRICA_UPDATE_GOLDEN=1, the golden files in goldenDir
+// are replaced with the rendered output instead. Files without the ".golden"
+// suffix are left untouched.
+//
+// Parameters:
+//   - t: The test to fail on differences
+//   - goldenDir: Directory holding the golden files, typically under testdata/
+//   - opts: Generator options
+//   - resources: Resource types to register
+//
+// Example:
+//
+//	codegentest.AssertGolden(t, "testdata/golden", codegentest.Options{}, &device.Device{})
+func AssertGolden(t testing.TB, goldenDir string, opts Options, resources ...interface{}) {
+	t.Helper()
+	dir := Render(t, opts, resources...)
+
+	if opts.Update || os.Getenv(UpdateEnv) != "" {
+		if err := updateGolden(goldenDir, dir); err != nil {
+			t.Fatalf("codegentest: failed to update golden files: %v", err)
+		}
+		t.Logf("codegentest: updated golden files in %s", goldenDir)
+		return
+	}
+
+	diffs, err := compare(goldenDir, dir)
+	if err != nil {
+		t.Fatalf("codegentest: %v", err)
+	}
+	if len(diffs) == 0 {
+		return
+	}
+
+	shown := diffs
+	if len(shown) > maxReportedDiffs {
+		shown = shown[:maxReportedDiffs]
+	}
+	report := strings.Join(shown, "\n")
+	if len(diffs) > len(shown) {
+		report += fmt.Sprintf("\n... and %d more", len(diffs)-len(shown))
+	}
+	t.Fatalf("generated code differs from %s (rerun with %s=1 to update):\n%s", goldenDir, UpdateEnv, report)
+}
+
+// readTree returns the normalized contents of every file under root, keyed by
+// slash-separated relative path with suffix removed
+func readTree(root, suffix string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if suffix != "" {
+			if !strings.HasSuffix(rel, suffix) {
+				return nil
+			}
+			rel = strings.TrimSuffix(rel, suffix)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = normalize(data)
+		return nil
+	})
+	return files, err
+}
+
+// normalize strips content that changes between otherwise identical runs
+func normalize(data []byte) []byte {
+	return generatedAtPattern.ReplaceAll(data, []byte("${1}<timestamp>"))
+}
+
+// compare lists the differences between the golden files and a rendered tree
+func compare(goldenDir, renderedDir string) ([]string, error) {
+	if _, err := os.Stat(goldenDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("golden directory %s does not exist (run with %s=1 to create it)", goldenDir, UpdateEnv)
+		}
+		return nil, err
+	}
+
+	golden, err := readTree(goldenDir, goldenSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden files: %w", err)
+	}
+	rendered, err := readTree(renderedDir, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered files: %w", err)
+	}
+
+	var diffs []string
+	for _, name := range sortedKeys(golden) {
+		got, ok := rendered[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("  missing: %s is no longer generated", name))
+			continue
+		}
+		if !bytes.Equal(golden[name], got) {
+			diffs = append(diffs, fmt.Sprintf("  changed: %s\n%s", name, firstDifference(golden[name], got)))
+		}
+	}
+	for _, name := range sortedKeys(rendered) {
+		if _, ok := golden[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("  unexpected: %s has no golden file", name))
+		}
+	}
+	return diffs, nil
+}
+
+// firstDifference describes the first line where want and got differ
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	line := 0
+	for line < len(wantLines) && line < len(gotLines) && wantLines[line] == gotLines[line] {
+		line++
+	}
+
+	lineAt := func(lines []string) string {
+		if line >= len(lines) {
+			return "<end of file>"
+		}
+		return fmt.Sprintf("%q", lines[line])
+	}
+	return fmt.Sprintf("    line %d:\n      want: %s\n      got:  %s", line+1, lineAt(wantLines), lineAt(gotLines))
+}
+
+// updateGolden replaces the golden files in goldenDir with the rendered tree.
+// Other files in goldenDir are left alone.
+func updateGolden(goldenDir, renderedDir string) error {
+	rendered, err := readTree(renderedDir, "")
+	if err != nil {
+		return err
+	}
+
+	stale, err := readTree(goldenDir, goldenSuffix)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for name := range stale {
+		if err := os.Remove(filepath.Join(goldenDir, filepath.FromSlash(name)+goldenSuffix)); err != nil {
+			return err
+		}
+	}
+	for name, data := range rendered {
+		path := filepath.Join(goldenDir, filepath.FromSlash(name)+goldenSuffix)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of files in order
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
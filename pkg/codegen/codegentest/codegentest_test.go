@@ -0,0 +1,186 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegentest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/codegen"
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+// Widget is a resource used to exercise the harness
+type Widget struct {
+	resource.Resource
+	Spec   WidgetSpec   `json:"spec"`
+	Status WidgetStatus `json:"status,omitempty"`
+}
+
+type WidgetSpec struct {
+	Description string `json:"description,omitempty"`
+	Size        int    `json:"size" validate:"min=1"`
+}
+
+type WidgetStatus struct {
+	Phase string `json:"phase,omitempty"`
+}
+
+// Link is a resource with references nested in structs and lists
+type Link struct {
+	resource.Resource
+	Spec LinkSpec `json:"spec"`
+}
+
+type LinkSpec struct {
+	Endpoint LinkEndpoint   `json:"endpoint"`
+	Hops     []LinkEndpoint `json:"hops,omitempty"`
+}
+
+type LinkEndpoint struct {
+	DeviceID string `json:"deviceId" fabrica:"ref=Widget"`
+}
+
+func TestRenderLayout(t *testing.T) {
+	dir := Render(t, Options{Reconcile: true}, &Widget{})
+
+	for _, name := range []string{
+		"cmd/server/widget_handlers_generated.go",
+		"cmd/server/routes_generated.go",
+		"internal/storage/storage_generated.go",
+		"pkg/client/client_generated.go",
+		"cmd/client/main.go",
+		"pkg/reconcilers/widget_reconciler_generated.go",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be rendered: %v", name, err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasPrefix(wd, dir) {
+		t.Errorf("working directory was not restored: %s", wd)
+	}
+}
+
+func TestRenderClearsNestedReferencesInTestSpecs(t *testing.T) {
+	dir := Render(t, Options{Configure: func(g *codegen.Generator) {
+		g.Config.ReferenceCheckEnabled = true
+		g.Config.TestsEnabled = true
+		g.Config.IntegrationEnabled = true
+	}}, &Widget{}, &Link{})
+
+	// Example values aren't UIDs of existing resources, so the generated
+	// tests drop every reference of the example spec
+	for _, name := range []string{
+		"cmd/server/link_handlers_generated_test.go",
+		"cmd/server/integration_generated_test.go",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `[]string{"endpoint.deviceId", "hops.deviceId"}`) {
+			t.Errorf("expected %s to clear the nested references", name)
+		}
+	}
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "golden")
+
+	AssertGolden(t, golden, Options{Update: true}, &Widget{})
+	if _, err := os.Stat(filepath.Join(golden, "cmd", "server", "routes_generated.go.golden")); err != nil {
+		t.Fatalf("expected golden files to be written: %v", err)
+	}
+
+	// A second render differs only in timestamps, which are normalized
+	dir := Render(t, Options{}, &Widget{})
+	diffs, err := compare(golden, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("expected no differences, got:\n%s", strings.Join(diffs, "\n"))
+	}
+}
+
+func TestCompareReportsDifferences(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "golden")
+	AssertGolden(t, golden, Options{Update: true}, &Widget{})
+
+	// Enabling a feature changes existing files and adds new ones
+	dir := Render(t, Options{Configure: func(g *codegen.Generator) {
+		g.Config.QuotaEnabled = true
+	}}, &Widget{})
+
+	routes := filepath.Join(golden, "cmd", "server", "routes_generated.go.golden")
+	if err := os.WriteFile(routes, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(golden, "cmd", "server", "removed_generated.go.golden")
+	if err := os.WriteFile(stale, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := compare(golden, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := strings.Join(diffs, "\n")
+	for _, want := range []string{
+		"changed: cmd/server/routes_generated.go",
+		"missing: cmd/server/removed_generated.go",
+		"unexpected: cmd/server/quota_generated.go",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+}
+
+func TestUpdateGoldenKeepsOtherFiles(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "golden")
+	if err := os.MkdirAll(golden, 0755); err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(golden, "README.md")
+	if err := os.WriteFile(readme, []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(golden, "stale.go.golden")
+	if err := os.WriteFile(stale, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	AssertGolden(t, golden, Options{Update: true}, &Widget{})
+
+	if _, err := os.Stat(readme); err != nil {
+		t.Errorf("expected non-golden file to be kept: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected stale golden file to be removed, got %v", err)
+	}
+}
+
+func TestCompareMissingGoldenDir(t *testing.T) {
+	_, err := compare(filepath.Join(t.TempDir(), "missing"), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), UpdateEnv) {
+		t.Fatalf("expected error pointing at %s, got %v", UpdateEnv, err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	in := "// Generated: 2025-01-02T03:04:05Z\npackage main\n# Generated: 2025-01-02T03:04:05+01:00\n"
+	want := "// Generated: <timestamp>\npackage main\n# Generated: <timestamp>\n"
+	if got := string(normalize([]byte(in))); got != want {
+		t.Errorf("normalize() = %q, want %q", got, want)
+	}
+}
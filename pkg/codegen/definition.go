@@ -0,0 +1,574 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/versioning"
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceDefinitionKind is the kind of resource definition manifests
+const ResourceDefinitionKind = "ResourceDefinition"
+
+// ResourceDefinition declares a resource in YAML, in the manner of a
+// Kubernetes CustomResourceDefinition, for teams who would rather not write
+// the Go types. GoSource turns it into the Go types that RegisterResource
+// consumes:
+//
+//	apiVersion: fabrica.openchami.io/v1
+//	kind: ResourceDefinition
+//	spec:
+//	  names:
+//	    kind: Device
+//	  spec:
+//	    - name: ipAddress
+//	      type: string
+//	      required: true
+//	      validate: ip
+//	    - name: ports
+//	      type: array
+//	      items:
+//	        type: integer
+type ResourceDefinition struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"` // Always ResourceDefinitionKind
+	Spec       ResourceDefinitionSpec `yaml:"spec"`
+}
+
+// ResourceDefinitionSpec is the content of a ResourceDefinition.
+type ResourceDefinitionSpec struct {
+	Names       ResourceNames       `yaml:"names"`
+	Description string              `yaml:"description"` // Doc comment of the resource type
+	Versioning  bool                `yaml:"versioning"`  // Spec snapshots (+fabrica:resource-versioning=enabled)
+	UniqueName  bool                `yaml:"uniqueName"`  // Unique metadata.name (+fabrica:unique-name=enabled)
+	Actions     []string            `yaml:"actions"`     // Custom actions, as camelCase verbs (+fabrica:actions=)
+	IDStrategy  string              `yaml:"idStrategy"`  // How UIDs are minted: prefix, ulid, uuidv7, uuid or name (+fabrica:id-strategy=)
+	Spec        []FieldDefinition   `yaml:"spec"`        // Fields of the spec, the v1 schema
+	Status      []FieldDefinition   `yaml:"status"`      // Fields of the status; phase, message, ready and conditions when omitted
+	Versions    []VersionDefinition `yaml:"versions"`    // Later schema versions (+fabrica:version=)
+}
+
+// ResourceNames names a defined resource.
+type ResourceNames struct {
+	Kind   string `yaml:"kind"`   // Go type name (e.g., "Device")
+	Plural string `yaml:"plural"` // Lowercase plural (e.g., "chassis"); inflected from the kind by default
+	Prefix string `yaml:"prefix"` // UID prefix; the first three letters of the lowercase kind by default
+}
+
+// FieldDefinition is a field of a defined spec, status or nested object.
+//
+// Types are those of OpenAPI schemas: "string" ("date-time" format for
+// time.Time), "integer" ("int32" or "int64" format), "number" ("float"
+// format for float32), "boolean", "array" (of Items), and "object", a struct
+// of Properties or a map of AdditionalProperties. Status fields may also be
+// of type "conditions", a []resource.Condition.
+type FieldDefinition struct {
+	Name                 string            `yaml:"name"` // JSON name (e.g., "ipAddress")
+	Type                 string            `yaml:"type"`
+	Format               string            `yaml:"format"`
+	Description          string            `yaml:"description"`          // Doc comment of the field
+	Required             bool              `yaml:"required"`             // validate:"required" (except for booleans), and no omitempty
+	Enum                 []string          `yaml:"enum"`                 // validate:"oneof=..."
+	Validate             string            `yaml:"validate"`             // Further validate tag rules (e.g., "max=200")
+	Example              string            `yaml:"example"`              // example tag
+	Print                string            `yaml:"print"`                // print tag of the CLI's table output
+	Items                *FieldDefinition  `yaml:"items"`                // Elements of an array
+	Properties           []FieldDefinition `yaml:"properties"`           // Fields of an object
+	AdditionalProperties *FieldDefinition  `yaml:"additionalProperties"` // Values of a map
+}
+
+// VersionDefinition is a later schema version of a defined resource.
+type VersionDefinition struct {
+	Name     string            `yaml:"name"`     // e.g., "v2"
+	Breaking []string          `yaml:"breaking"` // JSON names of intentionally broken spec fields (+fabrica:breaking=)
+	Spec     []FieldDefinition `yaml:"spec"`
+}
+
+// defaultStatus is the status of definitions without one, that of
+// 'fabrica add resource'
+var defaultStatus = []FieldDefinition{
+	{Name: "phase", Type: "string"},
+	{Name: "message", Type: "string"},
+	{Name: "ready", Type: "boolean", Required: true},
+	{Name: "conditions", Type: "conditions", Description: "Ready, Degraded (see resource.ConditionReady)"},
+}
+
+// goInitialisms are words written in capitals in Go field names
+var goInitialisms = map[string]bool{
+	"api": true, "cpu": true, "dns": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "mac": true, "tls": true, "ttl": true, "uid": true, "uri": true, "url": true, "uuid": true,
+}
+
+// LoadResourceDefinition reads and validates a resource definition file.
+func LoadResourceDefinition(path string) (*ResourceDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	def, err := ParseResourceDefinition(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return def, nil
+}
+
+// ParseResourceDefinition decodes and validates a resource definition.
+// Unknown keys are errors, to catch misspellings.
+func ParseResourceDefinition(data []byte) (*ResourceDefinition, error) {
+	var def ResourceDefinition
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid resource definition: %w", err)
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// Validate checks the names, fields and versions of a definition.
+func (d *ResourceDefinition) Validate() error {
+	if d.Kind != ResourceDefinitionKind {
+		return fmt.Errorf("kind must be %s, not %q", ResourceDefinitionKind, d.Kind)
+	}
+	kind := d.Spec.Names.Kind
+	if !token.IsIdentifier(kind) || !token.IsExported(kind) {
+		return fmt.Errorf("spec.names.kind %q must be an exported Go identifier, such as Device", kind)
+	}
+	if token.IsKeyword(strings.ToLower(kind)) {
+		return fmt.Errorf("spec.names.kind %q can't name a Go package, as %q is a keyword", kind, strings.ToLower(kind))
+	}
+	if _, _, err := pluralNames(kind, d.Spec.Names.Plural); err != nil {
+		return fmt.Errorf("spec.names.plural: %w", err)
+	}
+	for _, action := range d.Spec.Actions {
+		if actionPath(action) == "" {
+			return fmt.Errorf("spec.actions: invalid action %q", action)
+		}
+	}
+	if d.Spec.IDStrategy != "" {
+		if _, err := resource.IDStrategyByName(d.Spec.IDStrategy); err != nil {
+			return fmt.Errorf("spec.idStrategy: %w", err)
+		}
+	}
+	if len(d.Spec.Spec) == 0 {
+		return fmt.Errorf("spec.spec must declare at least one field")
+	}
+	if err := validateFields("spec.spec", d.Spec.Spec, false); err != nil {
+		return err
+	}
+	if err := validateFields("spec.status", d.Spec.Status, true); err != nil {
+		return err
+	}
+
+	seen := map[string]bool{"v1": true}
+	for i, v := range d.Spec.Versions {
+		path := fmt.Sprintf("spec.versions[%d]", i)
+		if err := versioning.ValidateVersion(v.Name); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("%s: version %s is declared twice (v1 is the spec itself)", path, v.Name)
+		}
+		seen[v.Name] = true
+		if len(v.Spec) == 0 {
+			return fmt.Errorf("%s.spec must declare at least one field", path)
+		}
+		if err := validateFields(path+".spec", v.Spec, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFields checks the fields of a struct, and those nested in them
+func validateFields(path string, fields []FieldDefinition, status bool) error {
+	names := make(map[string]bool)
+	goNames := make(map[string]bool)
+	for i, field := range fields {
+		fieldPath := fmt.Sprintf("%s[%d]", path, i)
+		if field.Name == "" {
+			return fmt.Errorf("%s: name is required", fieldPath)
+		}
+		fieldPath = path + "." + field.Name
+		goName := goFieldName(field.Name)
+		if !token.IsIdentifier(goName) {
+			return fmt.Errorf("%s: name must be made of letters, digits, '-' and '_'", fieldPath)
+		}
+		if names[field.Name] || goNames[goName] {
+			return fmt.Errorf("%s: field is declared twice", fieldPath)
+		}
+		names[field.Name], goNames[goName] = true, true
+		if err := validateFieldType(fieldPath, field, status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFieldType checks the type of a field and its elements
+func validateFieldType(path string, field FieldDefinition, status bool) error {
+	formats := map[string][]string{
+		"string":     {"", "date-time"},
+		"integer":    {"", "int32", "int64"},
+		"number":     {"", "float", "double"},
+		"boolean":    {""},
+		"array":      {""},
+		"object":     {""},
+		"conditions": {""},
+	}
+	allowed, ok := formats[field.Type]
+	if !ok || (field.Type == "conditions" && !status) {
+		return fmt.Errorf("%s: unsupported type %q (string, integer, number, boolean, array or object)", path, field.Type)
+	}
+	valid := false
+	for _, format := range allowed {
+		valid = valid || field.Format == format
+	}
+	if !valid {
+		return fmt.Errorf("%s: unsupported format %q for type %s", path, field.Format, field.Type)
+	}
+
+	switch field.Type {
+	case "array":
+		if field.Items == nil {
+			return fmt.Errorf("%s: arrays need items", path)
+		}
+		return validateFieldType(path+".items", *field.Items, false)
+	case "object":
+		if (len(field.Properties) == 0) == (field.AdditionalProperties == nil) {
+			return fmt.Errorf("%s: objects need either properties or additionalProperties", path)
+		}
+		if field.AdditionalProperties != nil {
+			return validateFieldType(path+".additionalProperties", *field.AdditionalProperties, false)
+		}
+		return validateFields(path+".properties", field.Properties, false)
+	}
+	if field.Items != nil || len(field.Properties) > 0 || field.AdditionalProperties != nil {
+		return fmt.Errorf("%s: items and properties are only allowed on arrays and objects", path)
+	}
+	return nil
+}
+
+// GoSource returns the Go source of the resource package of a definition,
+// package strings.ToLower(kind): the resource, spec and status types, their
+// nested types, the types of later schema versions, the methods of
+// resource.Resource kinds and the registration of the UID prefix. source
+// names the definition file in the header of the code.
+func (d *ResourceDefinition) GoSource(source string) ([]byte, error) {
+	kind := d.Spec.Names.Kind
+	pkg := strings.ToLower(kind)
+	prefix := d.Spec.Names.Prefix
+	if prefix == "" {
+		prefix = pkg[:min(3, len(pkg))]
+	}
+	status := d.Spec.Status
+	if len(status) == 0 {
+		status = defaultStatus
+	}
+	if d.Spec.Versioning && !hasField(status, "version") {
+		status = append(append([]FieldDefinition{}, status...), FieldDefinition{
+			Name: "version", Type: "string", Description: "Version is the current spec version identifier (server-managed)",
+		})
+	}
+
+	w := &goWriter{imports: make(map[string]bool), types: make(map[string]bool)}
+	w.printf("// Code generated by fabrica from %s. DO NOT EDIT.\n", source)
+	w.printf("// Edit the definition and run 'fabrica generate' to change the types.\n\n")
+	w.printf("package %s\n\n", pkg)
+	w.printf("import (\n%%IMPORTS%%)\n\n")
+
+	if d.Spec.Versioning {
+		w.printf("// +fabrica:resource-versioning=enabled\n")
+	}
+	if d.Spec.UniqueName {
+		w.printf("// +fabrica:unique-name=enabled\n")
+	}
+	if len(d.Spec.Actions) > 0 {
+		w.printf("// +fabrica:actions=%s\n", strings.Join(d.Spec.Actions, ","))
+	}
+	if d.Spec.IDStrategy != "" {
+		w.printf("// +fabrica:id-strategy=%s\n", d.Spec.IDStrategy)
+	}
+	if d.Spec.Versioning || d.Spec.UniqueName || len(d.Spec.Actions) > 0 || d.Spec.IDStrategy != "" {
+		w.printf("\n")
+	}
+	description := d.Spec.Description
+	if description == "" {
+		description = fmt.Sprintf("represents a %s resource", kind)
+	}
+	w.comment("", kind+" "+description)
+	w.printf("type %s struct {\n\tresource.Resource\n", kind)
+	if plural := d.Spec.Names.Plural; plural != "" {
+		w.printf("\tSpec %sSpec `json:\"spec\" validate:\"required\" plural:%q`\n", kind, plural)
+	} else {
+		w.printf("\tSpec %sSpec `json:\"spec\" validate:\"required\"`\n", kind)
+	}
+	w.printf("\tStatus %sStatus `json:\"status,omitempty\"`\n}\n\n", kind)
+
+	w.comment("", fmt.Sprintf("%sSpec defines the desired state of %s", kind, kind))
+	if err := w.structType(kind+"Spec", kind, d.Spec.Spec); err != nil {
+		return nil, err
+	}
+	w.comment("", fmt.Sprintf("%sStatus defines the observed state of %s", kind, kind))
+	if err := w.structType(kind+"Status", kind, status); err != nil {
+		return nil, err
+	}
+
+	for _, v := range d.Spec.Versions {
+		name := kind + strings.ToUpper(v.Name[:1]) + v.Name[1:]
+		w.comment("", fmt.Sprintf("%s is the %s schema of %s", name, v.Name, kind))
+		w.printf("// +fabrica:version=%s\n", v.Name)
+		if len(v.Breaking) > 0 {
+			w.printf("// +fabrica:breaking=%s\n", strings.Join(v.Breaking, ","))
+		}
+		w.printf("type %s struct {\n", name)
+		w.printf("\tSpec %sSpec `json:\"spec\" validate:\"required\"`\n", name)
+		w.printf("\tStatus %sStatus `json:\"status,omitempty\"`\n}\n\n", kind)
+		w.comment("", fmt.Sprintf("%sSpec is the spec of the %s schema of %s", name, v.Name, kind))
+		if err := w.structType(name+"Spec", name, v.Spec); err != nil {
+			return nil, err
+		}
+	}
+
+	w.printf(`// GetKind returns the kind of the resource
+func (r *%[1]s) GetKind() string {
+	return %[1]q
+}
+
+// GetName returns the name of the resource
+func (r *%[1]s) GetName() string {
+	return r.Metadata.Name
+}
+
+// GetUID returns the UID of the resource
+func (r *%[1]s) GetUID() string {
+	return r.Metadata.UID
+}
+
+func init() {
+	// Register resource type prefix for storage
+	resource.RegisterResourcePrefix(%[1]q, %[2]q)
+}
+`, kind, prefix)
+
+	var imports strings.Builder
+	if w.imports["time"] {
+		imports.WriteString("\t\"time\"\n\n")
+	}
+	imports.WriteString("\t\"github.com/openchami/fabrica/pkg/resource\"\n")
+	src := strings.Replace(w.buf.String(), "%IMPORTS%", imports.String(), 1)
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the types of %s: %w", kind, err)
+	}
+	return formatted, nil
+}
+
+// goWriter accumulates the Go source of a definition
+type goWriter struct {
+	buf     bytes.Buffer
+	imports map[string]bool // Imported packages besides pkg/resource
+	types   map[string]bool // Declared type names
+	pending []pendingType   // Nested types to declare after the current one
+}
+
+// pendingType is a nested struct type to declare
+type pendingType struct {
+	name, owner string
+	fields      []FieldDefinition
+	doc         string
+}
+
+func (w *goWriter) printf(format string, args ...any) {
+	fmt.Fprintf(&w.buf, format, args...)
+}
+
+// comment writes text as a comment, with indent before each line
+func (w *goWriter) comment(indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		w.printf("%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// structType writes a struct type of fields, then its nested types. owner
+// prefixes the names of the nested types.
+func (w *goWriter) structType(name, owner string, fields []FieldDefinition) error {
+	if w.types[name] {
+		return fmt.Errorf("type %s is declared twice; rename one of the fields it is named after", name)
+	}
+	w.types[name] = true
+
+	w.printf("type %s struct {\n", name)
+	for _, field := range fields {
+		goName := goFieldName(field.Name)
+		goType := w.goType(field, owner, goName, "the "+field.Name)
+		if field.Description != "" {
+			w.comment("\t", field.Description)
+		}
+		w.printf("\t%s %s %s\n", goName, goType, fieldTag(field))
+	}
+	w.printf("}\n\n")
+
+	pending := w.pending
+	w.pending = nil
+	for _, p := range pending {
+		w.comment("", p.doc)
+		if err := w.structType(p.name, p.owner, p.fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goType returns the Go type of a field, queueing the nested struct types
+// it needs. goName is the Go name of the field, naming nested types, and
+// what describes it in their doc comments.
+func (w *goWriter) goType(field FieldDefinition, owner, goName, what string) string {
+	switch field.Type {
+	case "string":
+		if field.Format == "date-time" {
+			w.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if field.Format != "" {
+			return field.Format
+		}
+		return "int"
+	case "number":
+		if field.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "conditions":
+		return "[]resource.Condition"
+	case "array":
+		return "[]" + w.goType(*field.Items, owner, singular(goName), "an element of "+what)
+	}
+	if field.AdditionalProperties != nil {
+		return "map[string]" + w.goType(*field.AdditionalProperties, owner, goName+"Value", "a value of "+what)
+	}
+	name := owner + goName
+	w.pending = append(w.pending, pendingType{
+		name:   name,
+		owner:  name,
+		fields: field.Properties,
+		doc:    fmt.Sprintf("%s is %s of a %s", name, what, owner),
+	})
+	return name
+}
+
+// fieldTag returns the struct tag of a field
+func fieldTag(field FieldDefinition) string {
+	json := field.Name
+	if !field.Required {
+		json += ",omitempty"
+	}
+	tags := []string{fmt.Sprintf("json:%q", json)}
+
+	// The validator's required rejects false, so required booleans are
+	// only always serialized
+	var rules []string
+	if field.Required && field.Type != "boolean" {
+		rules = append(rules, "required")
+	}
+	if len(field.Enum) > 0 {
+		rules = append(rules, "oneof="+strings.Join(field.Enum, " "))
+	}
+	if field.Validate != "" {
+		rules = append(rules, field.Validate)
+	}
+	if len(rules) > 0 {
+		if !field.Required {
+			// Rules apply to values that are set
+			rules = append([]string{"omitempty"}, rules...)
+		}
+		tags = append(tags, fmt.Sprintf("validate:%q", strings.Join(rules, ",")))
+	}
+	if field.Example != "" {
+		tags = append(tags, fmt.Sprintf("example:%q", field.Example))
+	}
+	if field.Print != "" {
+		tags = append(tags, fmt.Sprintf("print:%q", field.Print))
+	}
+	tag := strings.Join(tags, " ")
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// goFieldName returns the exported Go name of a JSON field name, writing
+// initialisms in capitals: "ipAddress" is IPAddress and "location_uid" is
+// LocationUID.
+func goFieldName(name string) string {
+	var words []string
+	start := 0
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			words = append(words, string(runes[start:i]))
+			start = i + 1
+		case i > start && unicode.IsUpper(r) && !unicode.IsUpper(runes[i-1]):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+
+	var goName strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		if goInitialisms[strings.ToLower(word)] {
+			goName.WriteString(strings.ToUpper(word))
+			continue
+		}
+		r := []rune(word)
+		goName.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	return goName.String()
+}
+
+// singular returns a Go name in the singular, for the element types of
+// arrays: Ports is Port and Addresses is Address
+func singular(name string) string {
+	for _, suffix := range []string{"sses", "shes", "ches", "xes"} {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, "es")
+		}
+	}
+	if strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1 {
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
+
+// hasField reports whether fields has one of a JSON name
+func hasField(fields []FieldDefinition, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FileChange is a file that a dry run would create, modify or delete.
+type FileChange struct {
+	Path    string
+	Action  string // "created", "modified" or "deleted"
+	Added   int    // Lines added
+	Removed int    // Lines removed
+	Diff    string // Unified diff of a modified file; empty for other actions
+}
+
+// diffContext is the number of unchanged lines around the changes of a hunk
+const diffContext = 3
+
+// maxDiffEdits bounds the work of diffing a file; files with more changed
+// lines are shown as replaced
+const maxDiffEdits = 4000
+
+// generatedLinePattern matches the generation timestamp of file headers,
+// which changes on every run and isn't reported as a change
+var generatedLinePattern = regexp.MustCompile(`^\s*(?://|#)?\s*Generated: `)
+
+// Changes returns the files that a dry run would change, in the order they
+// were generated.
+func (g *Generator) Changes() []FileChange {
+	return g.changes
+}
+
+// WriteChanges writes the changes of a dry run to w: the unified diff of
+// each modified file, then a summary line per file.
+func (g *Generator) WriteChanges(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, change := range g.changes {
+		buf.WriteString(change.Diff)
+	}
+	if len(g.changes) == 0 {
+		buf.WriteString("  No changes\n")
+	}
+	for _, change := range g.changes {
+		fmt.Fprintf(&buf, "  %-9s %s (+%d -%d)\n", change.Action, change.Path, change.Added, change.Removed)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeFile writes a generated file, keeping the code of its custom regions
+// (see mergeCustomRegions), or records how it differs from the file on disk
+// in a dry run
+func (g *Generator) writeFile(path string, data []byte) error {
+	data, err := mergeCustomRegions(path, data)
+	if err != nil {
+		return err
+	}
+	if !g.DryRun {
+		return os.WriteFile(path, data, 0644)
+	}
+	old, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if change, ok := CompareFiles(path, old, data, err == nil, true); ok {
+		g.changes = append(g.changes, change)
+	}
+	return nil
+}
+
+// CompareFiles returns how a file changes from its old content to its new
+// one, as a dry run reports it: created when it didn't exist, deleted when
+// it no longer does, and modified, with a unified diff, otherwise. It
+// returns false when the contents only differ in generation timestamps.
+func CompareFiles(path string, old, new []byte, oldExists, newExists bool) (FileChange, bool) {
+	switch {
+	case !oldExists && !newExists:
+		return FileChange{}, false
+	case !oldExists:
+		return FileChange{Path: path, Action: "created", Added: len(splitLines(new))}, true
+	case !newExists:
+		return FileChange{Path: path, Action: "deleted", Removed: len(splitLines(old))}, true
+	}
+	diff, added, removed := unifiedDiff(path, splitLines(old), splitLines(new))
+	if added == 0 && removed == 0 {
+		return FileChange{}, false
+	}
+	return FileChange{Path: path, Action: "modified", Added: added, Removed: removed, Diff: diff}, true
+}
+
+// mkdirAll creates a directory for generated files, except in a dry run
+func (g *Generator) mkdirAll(path string) error {
+	if g.DryRun {
+		return nil
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+// removeFile removes a previously generated file, or records its deletion
+// in a dry run
+func (g *Generator) removeFile(path string) error {
+	if !g.DryRun {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		return nil
+	}
+	old, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	change, _ := CompareFiles(path, old, nil, true, false)
+	g.changes = append(g.changes, change)
+	return nil
+}
+
+// splitLines splits a file into its lines, without their newlines
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// sameLine reports whether two lines are equal, taking generation
+// timestamps as equal
+func sameLine(a, b string) bool {
+	return a == b || (generatedLinePattern.MatchString(a) && generatedLinePattern.MatchString(b))
+}
+
+// edit is an operation of an edit script: ' ' keeps a line, '-' removes it
+// and '+' adds it
+type edit struct {
+	op   byte
+	line string
+}
+
+// diffLines returns an edit script turning a into b, computed with Myers'
+// algorithm, or replacing all of a when they differ by more than
+// maxDiffEdits lines
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := n + m
+	if limit > maxDiffEdits {
+		limit = maxDiffEdits
+	}
+	offset := limit + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[-d-1:d+2] before step d, all backtrack reads of it
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && sameLine(a[x], b[y]) {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	// Too many changes to diff in reasonable time
+	edits := make([]edit, 0, n+m)
+	for _, line := range a {
+		edits = append(edits, edit{'-', line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{'+', line})
+	}
+	return edits
+}
+
+// backtrack follows the trace of diffLines back from the ends of a and b
+func backtrack(a, b []string, trace [][]int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, offset := trace[d], d+1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{'+', b[y-1]})
+			} else {
+				edits = append(edits, edit{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// unifiedDiff returns the unified diff turning old into new, and the
+// numbers of lines it adds and removes
+func unifiedDiff(path string, old, new []string) (string, int, int) {
+	edits := diffLines(old, new)
+	added, removed := 0, 0
+	for _, e := range edits {
+		switch e.op {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "", 0, 0
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", path, path)
+	oldLine, newLine := 1, 1 // Lines of the edit at i
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			oldLine++
+			newLine++
+			continue
+		}
+
+		// A hunk runs from the context before a change to the context
+		// after the last change closer than two contexts
+		start := i
+		for start > 0 && i-start < diffContext && edits[start-1].op == ' ' {
+			start--
+		}
+		end := i
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(edits) && edits[next].op == ' ' {
+				next++
+			}
+			if next == len(edits) || next-end > 2*diffContext {
+				end += min(next-end, diffContext)
+				break
+			}
+			end = next
+		}
+
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		var body strings.Builder
+		oldCount, newCount := 0, 0
+		for _, e := range edits[start:end] {
+			body.WriteByte(e.op)
+			body.WriteString(e.line)
+			body.WriteByte('\n')
+			if e.op != '+' {
+				oldCount++
+			}
+			if e.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		buf.WriteString(body.String())
+
+		for _, e := range edits[i:end] {
+			if e.op != '+' {
+				oldLine++
+			}
+			if e.op != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return buf.String(), added, removed
+}
+
+// hunkRange formats the range of lines of a hunk, as diff -u does
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start-- // The line before an empty range
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
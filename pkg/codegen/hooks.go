@@ -0,0 +1,160 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// HookPhase is a point of generation where hooks run.
+type HookPhase string
+
+const (
+	// HookBeforeGenerate runs after the templates are loaded and the
+	// resources validated, before any file is generated
+	HookBeforeGenerate HookPhase = "before-generate"
+	// HookAfterGenerate runs once all files are generated
+	HookAfterGenerate HookPhase = "after-generate"
+)
+
+// Hook extends generation, typically emitting extra artifacts such as SDKs,
+// documentation or policies with WriteArtifact. A hook returning an error
+// fails the generation.
+type Hook func(g *Generator) error
+
+// RegisterHook registers a hook to run at a phase of GenerateAll, after
+// the hooks registered before it. Callers running the Generate methods
+// themselves run the hooks with RunHooks.
+//
+// Example:
+//
+//	gen.RegisterHook(codegen.HookAfterGenerate, func(g *codegen.Generator) error {
+//	    var buf bytes.Buffer
+//	    for _, r := range g.Resources {
+//	        fmt.Fprintf(&buf, "- %s: %s\n", r.Name, r.URLPath)
+//	    }
+//	    return g.WriteArtifact("docs/resources.md", buf.Bytes())
+//	})
+func (g *Generator) RegisterHook(phase HookPhase, hook Hook) {
+	if g.hooks == nil {
+		g.hooks = make(map[HookPhase][]Hook)
+	}
+	g.hooks[phase] = append(g.hooks[phase], hook)
+}
+
+// RunHooks runs the hooks registered for a phase, in order, stopping at the
+// first error.
+func (g *Generator) RunHooks(phase HookPhase) error {
+	for _, hook := range g.hooks[phase] {
+		if err := hook(g); err != nil {
+			return fmt.Errorf("%s hook failed: %w", phase, err)
+		}
+	}
+	return nil
+}
+
+// WriteArtifact writes a file of a hook, relative to the working directory,
+// creating its directory. In a dry run, it records the change instead, as
+// for generated files.
+func (g *Generator) WriteArtifact(path string, data []byte) error {
+	if err := g.mkdirAll(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := g.writeFile(path, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Printf("  ✓ Generated %s\n", path)
+	return nil
+}
+
+// PluginRequest is the JSON document a plugin reads on its standard input.
+type PluginRequest struct {
+	Phase       HookPhase
+	ModulePath  string
+	PackageName string
+	OutputDir   string
+	Version     string // Fabrica version
+	DryRun      bool   // Whether the files returned are only compared with those on disk
+	Config      *GeneratorConfig
+	Resources   []ResourceMetadata
+}
+
+// PluginResponse is the JSON document a plugin writes on its standard
+// output. An empty output means no files.
+type PluginResponse struct {
+	Files []PluginFile
+}
+
+// PluginFile is a file emitted by a plugin.
+type PluginFile struct {
+	Path    string // Relative to the project root, without ".."
+	Content string
+}
+
+// RegisterPlugin registers an external plugin as a HookAfterGenerate hook.
+// The command, split on spaces into a program and its arguments, reads a
+// PluginRequest as JSON on its standard input and writes a PluginResponse
+// on its standard output; its standard error is passed through. The files
+// it returns are written with WriteArtifact, so plugins take part in dry
+// runs without knowing about them.
+func (g *Generator) RegisterPlugin(command string) {
+	g.RegisterHook(HookAfterGenerate, func(g *Generator) error {
+		return g.runPlugin(command)
+	})
+}
+
+// runPlugin runs a plugin and writes the files it returns
+func (g *Generator) runPlugin(command string) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return fmt.Errorf("empty plugin command")
+	}
+	fmt.Printf("🔌 Running plugin %s...\n", command)
+
+	request, err := json.Marshal(PluginRequest{
+		Phase:       HookAfterGenerate,
+		ModulePath:  g.ModulePath,
+		PackageName: g.PackageName,
+		OutputDir:   g.OutputDir,
+		Version:     g.Version,
+		DryRun:      g.DryRun,
+		Config:      g.Config,
+		Resources:   g.Resources,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode the request of plugin %s: %w", command, err)
+	}
+	var stdout bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s failed: %w", command, err)
+	}
+
+	var response PluginResponse
+	if len(bytes.TrimSpace(stdout.Bytes())) > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+			return fmt.Errorf("plugin %s wrote an invalid response: %w", command, err)
+		}
+	}
+	for _, file := range response.Files {
+		path := filepath.Clean(filepath.FromSlash(file.Path))
+		if file.Path == "" || filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("plugin %s returned a file outside the project: %q", command, file.Path)
+		}
+		if err := g.WriteArtifact(path, []byte(file.Content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// renderedFile is a generated file, rendered and formatted but not written yet
+type renderedFile struct {
+	Path    string
+	Content []byte
+}
+
+// workers returns the number of resources rendered at once (see Generator.Workers)
+func (g *Generator) workers() int {
+	if g.Workers > 0 {
+		return g.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// renderResources renders the files of every resource with a pool of
+// workers, since executing and formatting templates dominates the time
+// generation takes for projects with many resources. Files are returned in
+// resource order, for callers to write serially: generated files and log
+// lines come out as with serial generation.
+//
+// Every resource is rendered even when some fail, and the errors of all of
+// them are joined in resource order.
+func (g *Generator) renderResources(render func(resource ResourceMetadata) ([]renderedFile, error)) ([][]renderedFile, error) {
+	files := make([][]renderedFile, len(g.Resources))
+	errs := make([]error, len(g.Resources))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(g.workers(), len(g.Resources)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				files[i], errs[i] = render(g.Resources[i])
+			}
+		}()
+	}
+	for i := range g.Resources {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return files, errors.Join(errs...)
+}
+
+// writeRendered writes rendered files in order, logging each one
+func (g *Generator) writeRendered(files []renderedFile) error {
+	for _, file := range files {
+		if err := g.writeFile(file.Path, file.Content); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", file.Path, err)
+		}
+		fmt.Printf("  ✓ Generated %s\n", file.Path)
+	}
+	return nil
+}
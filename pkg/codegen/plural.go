@@ -0,0 +1,66 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/inflection"
+)
+
+// PluralTag is the struct tag of a resource's Spec field overriding the
+// plural of its kind, e.g. `json:"spec" plural:"chassis"`.
+const PluralTag = "plural"
+
+// validPlural matches plurals usable in URL paths and generated names
+var validPlural = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+func init() {
+	// Chassis are common in hardware inventories, and the plural is the
+	// same word
+	inflection.AddUncountable("chassis")
+}
+
+// pluralize returns the plural of a kind, e.g. "Devices" for "Device" and
+// "Inventories" for "Inventory". Kinds in capitals, such as "BMC", are
+// acronyms taking an "s".
+func pluralize(kind string) string {
+	if strings.ToUpper(kind) == kind && strings.IndexFunc(kind, unicode.IsLetter) >= 0 {
+		return kind + "s"
+	}
+	return inflection.Plural(kind)
+}
+
+// pluralNames returns the plural of a kind in URL paths (e.g., "inventories")
+// and in generated Go names (e.g., "Inventories"), from an override such as
+// "chassis" or the kind. Kinds whose plural is the kind itself get "List"
+// in Go names ("ChassisList"), so lists and single resources are told
+// apart.
+func pluralNames(kind, override string) (string, string, error) {
+	goName := pluralize(kind)
+	plural := strings.ToLower(goName)
+	if override != "" {
+		if !validPlural.MatchString(override) || token.IsKeyword(override) {
+			return "", "", fmt.Errorf("plural %q of %s must be lowercase letters and digits, and not a Go keyword", override, kind)
+		}
+		plural = override
+		switch {
+		case strings.ToLower(goName) == override:
+		case strings.HasPrefix(override, strings.ToLower(kind)):
+			// Keep the kind's capitals (e.g., "PowerSupplyUnits")
+			goName = kind + override[len(kind):]
+		default:
+			goName = strings.ToUpper(override[:1]) + override[1:]
+		}
+	}
+	if goName == kind {
+		goName += "List"
+	}
+	return plural, goName, nil
+}
@@ -0,0 +1,144 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Custom regions are the parts of generated files kept by regeneration:
+//
+//	// fabrica:begin custom create
+//	if device.Spec.Location == "" {
+//	    device.Spec.Location = "unassigned"
+//	}
+//	// fabrica:end custom create
+//
+// Templates mark where custom code may go with empty regions, named
+// uniquely within their file. When a file is regenerated, the lines
+// between the markers of its regions on disk replace those of the
+// regions of the same name in the new content.
+const (
+	regionBegin = "// fabrica:begin custom "
+	regionEnd   = "// fabrica:end custom "
+)
+
+// customRegion is a custom region of a file
+type customRegion struct {
+	name  string
+	lines [][]byte // Lines between the markers, with their newlines
+	begin int      // Line number of the begin marker
+}
+
+// customRegions returns the custom regions of a file, in order
+func customRegions(data []byte) ([]customRegion, error) {
+	var regions []customRegion
+	var current *customRegion
+	seen := make(map[string]bool)
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		text := strings.TrimSpace(string(line))
+		switch {
+		case strings.HasPrefix(text, regionBegin):
+			name := strings.TrimSpace(strings.TrimPrefix(text, regionBegin))
+			if current != nil {
+				return nil, fmt.Errorf("line %d: custom region %q begins inside region %q", i+1, name, current.name)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("line %d: custom region %q is repeated", i+1, name)
+			}
+			seen[name] = true
+			current = &customRegion{name: name, begin: i + 1}
+		case strings.HasPrefix(text, regionEnd):
+			name := strings.TrimSpace(strings.TrimPrefix(text, regionEnd))
+			if current == nil || current.name != name {
+				return nil, fmt.Errorf("line %d: custom region %q ends without beginning", i+1, name)
+			}
+			regions = append(regions, *current)
+			current = nil
+		case current != nil:
+			current.lines = append(current.lines, line)
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("line %d: custom region %q doesn't end", current.begin, current.name)
+	}
+	return regions, nil
+}
+
+// mergeCustomRegions returns the generated content of a file with the
+// custom code of the file on disk in its regions. Custom code whose region
+// is no longer generated is an error, rather than being lost.
+func mergeCustomRegions(path string, generated []byte) ([]byte, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil || !bytes.Contains(existing, []byte(regionBegin)) {
+		return generated, nil
+	}
+	regions, err := customRegions(existing)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w; fix the markers to regenerate it", path, err)
+	}
+	custom := make(map[string][][]byte)
+	for _, region := range regions {
+		if len(bytes.TrimSpace(bytes.Join(region.lines, nil))) > 0 {
+			custom[region.name] = region.lines
+		}
+	}
+	if len(custom) == 0 {
+		return generated, nil
+	}
+
+	newRegions, err := customRegions(generated)
+	if err != nil {
+		return nil, fmt.Errorf("generated %s: %w", path, err)
+	}
+	kept := make(map[string]bool)
+	for _, region := range newRegions {
+		kept[region.name] = true
+	}
+	for _, region := range regions {
+		if _, ok := custom[region.name]; ok && !kept[region.name] {
+			return nil, fmt.Errorf("%s: custom region %q (line %d) is no longer generated; move its code elsewhere to regenerate the file", path, region.name, region.begin)
+		}
+	}
+
+	var merged bytes.Buffer
+	var lines [][]byte
+	inside := false
+	for _, line := range bytes.SplitAfter(generated, []byte("\n")) {
+		text := strings.TrimSpace(string(line))
+		switch {
+		case strings.HasPrefix(text, regionBegin):
+			merged.Write(line)
+			lines, inside = custom[strings.TrimSpace(strings.TrimPrefix(text, regionBegin))]
+			for _, l := range lines {
+				merged.Write(l)
+			}
+		case strings.HasPrefix(text, regionEnd):
+			merged.Write(line)
+			inside = false
+		case !inside:
+			merged.Write(line)
+		}
+	}
+	return merged.Bytes(), nil
+}
+
+// HasCustomCode reports whether a generated file has code in its custom
+// regions, which regeneration keeps
+func HasCustomCode(data []byte) bool {
+	regions, err := customRegions(data)
+	if err != nil {
+		return bytes.Contains(data, []byte(regionBegin)) // Markers broken by hand
+	}
+	for _, region := range regions {
+		if len(bytes.TrimSpace(bytes.Join(region.lines, nil))) > 0 {
+			return true
+		}
+	}
+	return false
+}
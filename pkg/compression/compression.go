@@ -0,0 +1,271 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package compression compresses HTTP responses with gzip or zstd.
+//
+// Resource lists and exports are repetitive JSON and typically shrink about
+// 10:1. Compressor negotiates an encoding from the request's
+// Accept-Encoding header and compresses responses whose content type is in
+// the configured list and whose body reaches a minimum size:
+//
+//	c := compression.New(compression.Options{MinSize: 1024})
+//	r.Use(c.Middleware)
+//
+// Bodies are buffered only up to the minimum size, so streamed responses
+// stay streamed: once the body is known to be large enough it is
+// compressed as it is written, and flushing the response flushes the
+// encoder too.
+//
+// Responses that already have a Content-Encoding, partial content (206),
+// and bodiless responses (HEAD, 204, 304) are left alone.
+package compression
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported encodings
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// DefaultMinSize is the smallest body compressed when Options.MinSize is zero.
+// Smaller bodies gain little and cost a round of encoder setup.
+const DefaultMinSize = 1024
+
+// DefaultEncodings are the encodings offered when Options.Encodings is
+// empty, in order of preference
+var DefaultEncodings = []string{Zstd, Gzip}
+
+// DefaultContentTypes are the media types compressed when
+// Options.ContentTypes is empty
+var DefaultContentTypes = []string{
+	"application/json",
+	"application/*+json",
+	"application/x-ndjson",
+	"application/x-tar",
+	"application/yaml",
+	"application/cbor",
+	"text/*",
+}
+
+// Options configures a Compressor.
+type Options struct {
+	// MinSize is the smallest body, in bytes, that is compressed
+	// (default: DefaultMinSize).
+	MinSize int
+
+	// ContentTypes lists the media types that are compressed (default:
+	// DefaultContentTypes). "text/*" matches every text type and
+	// "application/*+json" every JSON-based type, such as
+	// application/problem+json.
+	ContentTypes []string
+
+	// Encodings lists the offered encodings in order of preference, used
+	// when a client accepts several equally (default: DefaultEncodings).
+	Encodings []string
+
+	// Level is the compression level: gzip levels (1-9) for gzip, and for
+	// zstd 1 (fastest) to 4 (best). Zero uses each encoding's default.
+	Level int
+}
+
+// Compressor compresses responses of the handlers it wraps.
+type Compressor struct {
+	minSize      int
+	contentTypes []string
+	encodings    []string
+	pools        map[string]*sync.Pool
+}
+
+// encoder is the part of gzip.Writer and zstd.Encoder Compressor uses
+type encoder interface {
+	io.Writer
+	Flush() error
+	Close() error
+	Reset(w io.Writer)
+}
+
+// Supported reports whether an encoding can be used in Options.Encodings.
+func Supported(encoding string) bool {
+	return encoding == Gzip || encoding == Zstd
+}
+
+// New creates a Compressor.
+//
+// New panics if Options.Encodings contains an encoding that isn't
+// Supported or Options.Level is out of range, since both are fixed when
+// the server is built.
+//
+// Parameters:
+//   - opts: Sizes, content types and encodings; zero values use the defaults
+//
+// Returns:
+//   - *Compressor: The compressor; use its Middleware
+func New(opts Options) *Compressor {
+	c := &Compressor{
+		minSize:      opts.MinSize,
+		contentTypes: opts.ContentTypes,
+		encodings:    opts.Encodings,
+		pools:        map[string]*sync.Pool{},
+	}
+	if c.minSize == 0 {
+		c.minSize = DefaultMinSize
+	}
+	if len(c.contentTypes) == 0 {
+		c.contentTypes = DefaultContentTypes
+	}
+	if len(c.encodings) == 0 {
+		c.encodings = DefaultEncodings
+	}
+
+	for _, encoding := range c.encodings {
+		var newEncoder func() encoder
+		switch encoding {
+		case Gzip:
+			level := gzip.DefaultCompression
+			if opts.Level != 0 {
+				level = opts.Level
+			}
+			if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+				panic(fmt.Sprintf("compression: %v", err))
+			}
+			newEncoder = func() encoder {
+				w, _ := gzip.NewWriterLevel(io.Discard, level)
+				return w
+			}
+		case Zstd:
+			level := zstd.SpeedDefault
+			if opts.Level != 0 {
+				if opts.Level < int(zstd.SpeedFastest) || opts.Level > int(zstd.SpeedBestCompression) {
+					panic(fmt.Sprintf("compression: invalid zstd level %d", opts.Level))
+				}
+				level = zstd.EncoderLevel(opts.Level)
+			}
+			newEncoder = func() encoder {
+				w, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+				return w
+			}
+		default:
+			panic(fmt.Sprintf("compression: unsupported encoding %q", encoding))
+		}
+		c.pools[encoding] = &sync.Pool{New: func() interface{} { return newEncoder() }}
+	}
+	return c
+}
+
+// Negotiate picks the encoding for a response from an Accept-Encoding
+// header (RFC 9110, section 12.5.3): the offered encoding with the highest
+// quality wins, and the more preferred one on a tie. "*" covers encodings
+// not listed, and q=0 refuses one.
+//
+// Parameters:
+//   - acceptEncoding: Accept-Encoding header value
+//
+// Returns:
+//   - string: The encoding, or "" to send the response uncompressed
+func (c *Compressor) Negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		qualities[coding] = q
+	}
+
+	best, chosen := 0.0, ""
+	for _, encoding := range c.encodings {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > best {
+			best, chosen = q, encoding
+		}
+	}
+	return chosen
+}
+
+// compressible reports whether a Content-Type is in the configured list
+func (c *Compressor) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range c.contentTypes {
+		switch {
+		case pattern == mediaType:
+			return true
+		case strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*")):
+			return true
+		case strings.Contains(pattern, "/*+"):
+			prefix, suffix, _ := strings.Cut(pattern, "*")
+			if strings.HasPrefix(mediaType, prefix) && strings.HasSuffix(mediaType, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getEncoder takes an encoder writing to w from the pool
+func (c *Compressor) getEncoder(encoding string, w io.Writer) encoder {
+	enc := c.pools[encoding].Get().(encoder)
+	enc.Reset(w)
+	return enc
+}
+
+// putEncoder returns a closed encoder to the pool
+func (c *Compressor) putEncoder(encoding string, enc encoder) {
+	enc.Reset(io.Discard)
+	c.pools[encoding].Put(enc)
+}
+
+// Middleware compresses the responses of next for clients accepting one of
+// the offered encodings. Every response gets "Vary: Accept-Encoding", so
+// caches keep the compressed and uncompressed forms apart.
+//
+// Example:
+//
+//	r.Use(compression.New(compression.Options{}).Middleware)
+func (c *Compressor) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := c.Negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, c: c, encoding: encoding}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
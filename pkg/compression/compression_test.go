@@ -0,0 +1,248 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiate(t *testing.T) {
+	c := New(Options{})
+	for accept, want := range map[string]string{
+		"":                          "",
+		"gzip":                      Gzip,
+		"gzip, deflate, br, zstd":   Zstd,
+		"zstd;q=0.5, gzip":          Gzip,
+		"GZIP;Q=0.8":                Gzip,
+		"*":                         Zstd,
+		"*;q=0.5, zstd;q=0":         Gzip,
+		"gzip;q=0, zstd;q=0":        "",
+		"identity":                  "",
+		"br, deflate":               "",
+		"gzip;q=bogus, zstd;q=0.1":  Zstd,
+		" zstd ; q=1 , gzip ; q=1 ": Zstd,
+	} {
+		if got := c.Negotiate(accept); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", accept, got, want)
+		}
+	}
+
+	gzipFirst := New(Options{Encodings: []string{Gzip, Zstd}})
+	if got := gzipFirst.Negotiate("zstd, gzip"); got != Gzip {
+		t.Errorf("expected the preferred encoding on a tie, got %q", got)
+	}
+}
+
+func TestCompressible(t *testing.T) {
+	c := New(Options{})
+	for contentType, want := range map[string]bool{
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"application/problem+json":        true,
+		"application/merge-patch+json":    true,
+		"application/x-ndjson":            true,
+		"text/csv":                        true,
+		"text/plain; charset=utf-8":       true,
+		"image/png":                       false,
+		"application/octet-stream":        false,
+		"application/protobuf":            false,
+		"":                                false,
+	} {
+		if got := c.compressible(contentType); got != want {
+			t.Errorf("compressible(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	for name, opts := range map[string]Options{
+		"unknown encoding": {Encodings: []string{"br"}},
+		"gzip level":       {Encodings: []string{Gzip}, Level: 10},
+		"zstd level":       {Encodings: []string{Zstd}, Level: 5},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected New to panic", name)
+				}
+			}()
+			New(opts)
+		}()
+	}
+}
+
+// serve runs a handler through the middleware and returns the recorded response
+func serve(t *testing.T, opts Options, method, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/devices", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	New(opts).Middleware(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+// decode returns the decoded body of a response
+func decode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var r io.Reader
+	switch rec.Header().Get("Content-Encoding") {
+	case "":
+		return rec.Body.String()
+	case Gzip:
+		gz, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gz
+	case Zstd:
+		zr, err := zstd.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		t.Fatalf("unexpected Content-Encoding %q", rec.Header().Get("Content-Encoding"))
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to decode the body: %v", err)
+	}
+	return string(data)
+}
+
+// writeJSON writes body as JSON in small pieces
+func writeJSON(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		for i := 0; i < len(body); i += 100 {
+			_, _ = io.WriteString(w, body[i:min(i+100, len(body))])
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	large := "[" + strings.Repeat(`{"kind":"Device","spec":{"rack":"r1"}},`, 200) + "{}]"
+
+	for _, encoding := range []string{Gzip, Zstd} {
+		rec := serve(t, Options{}, "GET", encoding, writeJSON(large))
+		if got := rec.Header().Get("Content-Encoding"); got != encoding {
+			t.Fatalf("%s: expected Content-Encoding %s, got %q", encoding, encoding, got)
+		}
+		if rec.Header().Get("Content-Length") != "" {
+			t.Errorf("%s: expected Content-Length to be removed", encoding)
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: expected Vary: Accept-Encoding, got %q", encoding, rec.Header().Get("Vary"))
+		}
+		if rec.Body.Len() >= len(large)/5 {
+			t.Errorf("%s: expected the body to shrink, got %d of %d bytes", encoding, rec.Body.Len(), len(large))
+		}
+		if got := decode(t, rec); got != large {
+			t.Errorf("%s: decoded body differs from the original", encoding)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		opts           Options
+		handler        http.HandlerFunc
+	}{
+		{"no Accept-Encoding", "GET", "", Options{}, writeJSON(large)},
+		{"HEAD", "HEAD", Gzip, Options{}, writeJSON(large)},
+		{"small body", "GET", Gzip, Options{}, writeJSON(`{"kind":"Device"}`)},
+		{"larger minimum", "GET", Gzip, Options{MinSize: 1 << 20}, writeJSON(large)},
+		{"content type not listed", "GET", Gzip, Options{ContentTypes: []string{"text/csv"}}, writeJSON(large)},
+		{"already encoded", "GET", Gzip, Options{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			writeJSON(large)(w, r)
+		}},
+		{"not modified", "GET", Gzip, Options{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotModified)
+		}},
+		{"partial content", "GET", Gzip, Options{}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Range", "bytes 0-1999/5000")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = io.WriteString(w, strings.Repeat("a", 2000))
+		}},
+	}
+	for _, tt := range tests {
+		rec := serve(t, tt.opts, tt.method, tt.acceptEncoding, tt.handler)
+		if got := rec.Header().Get("Content-Encoding"); got == Gzip {
+			t.Errorf("%s: expected an uncompressed response", tt.name)
+		}
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: expected Vary: Accept-Encoding", tt.name)
+		}
+	}
+
+	// Small bodies keep their status and Content-Length
+	rec := serve(t, Options{}, "GET", Gzip, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"status":404}`)
+	})
+	if rec.Code != http.StatusNotFound || rec.Body.String() != `{"status":404}` {
+		t.Errorf("expected the small 404 unchanged, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMiddleware_DetectsContentType(t *testing.T) {
+	body := "<html>" + strings.Repeat("<p>device</p>", 200) + "</html>"
+	rec := serve(t, Options{}, "GET", Gzip, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, body)
+	})
+	if rec.Header().Get("Content-Encoding") != Gzip || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("expected a sniffed, compressed text/html response, got %v", rec.Header())
+	}
+	if decode(t, rec) != body {
+		t.Error("decoded body differs from the original")
+	}
+}
+
+func TestMiddleware_Flush(t *testing.T) {
+	var afterFlush string
+	rec := serve(t, Options{}, "GET", Gzip, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "[1")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("flush failed: %v", err)
+		}
+		// The flushed part decodes before the handler finishes
+		gz, err := gzip.NewReader(bytes.NewReader(w.(*compressWriter).ResponseWriter.(*httptest.ResponseRecorder).Body.Bytes()))
+		if err != nil {
+			t.Fatalf("expected a compressed stream after the flush: %v", err)
+		}
+		data, _ := io.ReadAll(gz)
+		afterFlush = string(data)
+		_, _ = io.WriteString(w, ",2]")
+	})
+	if !rec.Flushed || rec.Header().Get("Content-Encoding") != Gzip {
+		t.Fatalf("expected a flushed gzip response, got %v", rec.Header())
+	}
+	if afterFlush != "[1" {
+		t.Errorf("expected [1 after the flush, got %q", afterFlush)
+	}
+	if got := decode(t, rec); got != "[1,2]" {
+		t.Errorf("expected [1,2], got %q", got)
+	}
+}
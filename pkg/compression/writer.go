@@ -0,0 +1,144 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package compression
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// compressWriter decides whether to compress a response once its headers
+// and the first MinSize bytes of its body are known
+type compressWriter struct {
+	http.ResponseWriter
+	c        *Compressor
+	encoding string
+
+	status  int    // Status set by the handler, or 0
+	decided bool   // Whether the response is compressed or passed through is settled
+	buf     []byte // Body held back while undecided
+	enc     encoder
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// WriteHeader holds the status back until the body shows whether to
+// compress. Responses that can't be compressed are passed through at once.
+func (w *compressWriter) WriteHeader(status int) {
+	switch {
+	case w.decided:
+		w.ResponseWriter.WriteHeader(status)
+	case w.status != 0:
+		// Superfluous call while the status is held back
+	case status < http.StatusOK:
+		w.ResponseWriter.WriteHeader(status)
+	default:
+		w.status = status
+		if !w.eligible() {
+			w.passThrough()
+		}
+	}
+}
+
+// Write buffers the body until it reaches the minimum size, then writes it
+// compressed
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided && w.status == 0 {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case w.enc != nil:
+		return w.enc.Write(p)
+	case w.decided:
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.c.minSize {
+		if err := w.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends what has been written so far. An undecided response is
+// compressed, since the handler is streaming and its final size can't be
+// known.
+func (w *compressWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		if err := w.startCompression(); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// eligible reports whether the response headers allow compression
+func (w *compressWriter) eligible() bool {
+	h := w.Header()
+	switch {
+	case w.status == http.StatusNoContent, w.status == http.StatusNotModified, w.status == http.StatusPartialContent:
+		return false
+	case h.Get("Content-Encoding") != "", h.Get("Content-Range") != "":
+		return false
+	case !w.c.compressible(h.Get("Content-Type")):
+		return false
+	}
+	if length, err := strconv.Atoi(h.Get("Content-Length")); err == nil && length < w.c.minSize {
+		return false
+	}
+	return true
+}
+
+// passThrough sends the response uncompressed, with any buffered body
+func (w *compressWriter) passThrough() {
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+// startCompression sends the headers of a compressed response and the
+// buffered body
+func (w *compressWriter) startCompression() error {
+	w.decided = true
+	h := w.Header()
+	h.Set("Content-Encoding", w.encoding)
+	h.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+
+	w.enc = w.c.getEncoder(w.encoding, w.ResponseWriter)
+	buf := w.buf
+	w.buf = nil
+	_, err := w.enc.Write(buf)
+	return err
+}
+
+// close ends the response: a body still under the minimum size is sent
+// uncompressed, and a compressed one is finished
+func (w *compressWriter) close() {
+	switch {
+	case w.enc != nil:
+		_ = w.enc.Close()
+		w.c.putEncoder(w.encoding, w.enc)
+		w.enc = nil
+	case !w.decided && w.status != 0:
+		w.passThrough()
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package conditional
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5" //nolint:gosec // ETags identify representations, they don't protect them
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultCacheControl is the Cache-Control of GET responses when
+// Options.CacheControl is empty: clients may keep responses but must
+// revalidate them, which costs a 304 when nothing changed.
+const DefaultCacheControl = "private, no-cache"
+
+// DefaultMaxBodySize is the largest response body Middleware hashes into an
+// ETag when Options.MaxBodySize is zero
+const DefaultMaxBodySize = 1 << 20
+
+// MD5ETagGenerator generates ETags from the MD5 hash of the data
+func MD5ETagGenerator(data []byte) string {
+	hash := md5.Sum(data) //nolint:gosec // See import
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(hash[:]))
+}
+
+// GeneratorFor returns the ETag generator of a hash algorithm.
+//
+// Parameters:
+//   - algorithm: "sha256" (or "" for the default) or "md5"
+//
+// Returns:
+//   - ETagGenerator: The generator
+//   - error: If the algorithm isn't supported
+func GeneratorFor(algorithm string) (ETagGenerator, error) {
+	switch algorithm {
+	case "", "sha256":
+		return DefaultETagGenerator, nil
+	case "md5":
+		return MD5ETagGenerator, nil
+	default:
+		return nil, fmt.Errorf("unknown ETag algorithm %q (must be sha256 or md5)", algorithm)
+	}
+}
+
+// Weak marks an ETag as weak (W/"..."): it identifies content that is
+// equivalent for the client, such as a resource's spec, rather than the
+// exact bytes of a response. Weak ETags are returned unchanged.
+func Weak(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return "W/" + etag
+}
+
+// CollectionETag returns the ETag of a collection from the ETags of its
+// members, in order: it changes when a member is added, removed, moved or
+// changed. The result is weak if any member's ETag is weak.
+//
+// Parameters:
+//   - generator: Hashes the members' ETags (nil: DefaultETagGenerator)
+//   - members: ETags of the members, in the order they are listed
+//
+// Returns:
+//   - string: The collection's ETag
+func CollectionETag(generator ETagGenerator, members []string) string {
+	if generator == nil {
+		generator = DefaultETagGenerator
+	}
+	weak := false
+	for _, etag := range members {
+		weak = weak || strings.HasPrefix(etag, "W/")
+	}
+	etag := generator([]byte(strings.Join(members, "\n")))
+	if weak {
+		return Weak(etag)
+	}
+	return etag
+}
+
+// Options configures Middleware.
+type Options struct {
+	// Generator computes the ETag of a response body (default:
+	// DefaultETagGenerator).
+	Generator ETagGenerator
+
+	// CacheControl is the Cache-Control header of GET and HEAD responses
+	// that don't set their own (default: DefaultCacheControl). "-" sends
+	// none.
+	CacheControl string
+
+	// MaxBodySize is the largest body, in bytes, buffered to compute an
+	// ETag (default: DefaultMaxBodySize). Larger responses are sent
+	// without one.
+	MaxBodySize int
+}
+
+// Middleware makes GET responses conditional, lists included.
+//
+// Successful GET responses are buffered and get an ETag computed from
+// their body, unless the handler set one, so a list has a collection-level
+// ETag that changes whenever any of its members, or the list itself,
+// changes. A request whose If-None-Match matches gets 304 Not Modified
+// without the body. GET and HEAD responses also get Cache-Control.
+//
+// The body is hashed as sent, after content negotiation, so each
+// representation (JSON, protobuf, a ?fields= selection) has its own ETag.
+// Responses that are flushed while being written (streamed lists), or that
+// exceed Options.MaxBodySize, are sent as they are, without an ETag.
+//
+// Example:
+//
+//	r.Use(conditional.Middleware(conditional.Options{
+//	    CacheControl: "private, max-age=5",
+//	}))
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	if opts.Generator == nil {
+		opts.Generator = DefaultETagGenerator
+	}
+	if opts.CacheControl == "" {
+		opts.CacheControl = DefaultCacheControl
+	}
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if opts.CacheControl != "-" {
+				w.Header().Set("Cache-Control", opts.CacheControl)
+			}
+			if r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := &bufferedResponseWriter{ResponseWriter: w, maxSize: opts.MaxBodySize}
+			next.ServeHTTP(bw, r)
+			bw.finish(r, opts.Generator)
+		})
+	}
+}
+
+// bufferedResponseWriter holds back a 200 response until the handler
+// returns, so its ETag can be computed from the whole body
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	maxSize     int
+	status      int
+	buf         bytes.Buffer
+	passthrough bool // headers sent; writes go straight to the client
+}
+
+func (bw *bufferedResponseWriter) WriteHeader(status int) {
+	if bw.status != 0 || bw.passthrough {
+		return
+	}
+	bw.status = status
+	if status != http.StatusOK {
+		bw.passthrough = true
+		bw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (bw *bufferedResponseWriter) Write(data []byte) (int, error) {
+	if bw.status == 0 {
+		bw.WriteHeader(http.StatusOK)
+	}
+	if bw.passthrough {
+		return bw.ResponseWriter.Write(data)
+	}
+	if bw.buf.Len()+len(data) > bw.maxSize {
+		if err := bw.release(); err != nil {
+			return 0, err
+		}
+		return bw.ResponseWriter.Write(data)
+	}
+	return bw.buf.Write(data)
+}
+
+// Flush sends what was buffered and streams the rest of the response
+func (bw *bufferedResponseWriter) Flush() {
+	if bw.status == 0 {
+		bw.WriteHeader(http.StatusOK)
+	}
+	if !bw.passthrough {
+		bw.release() //nolint:errcheck // Reported by the next Write
+	}
+	if f, ok := bw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets websocket and similar handlers take over the connection
+func (bw *bufferedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := bw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("conditional: response writer doesn't support hijacking")
+	}
+	bw.passthrough = true
+	return h.Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (bw *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return bw.ResponseWriter
+}
+
+// release sends the status and buffered body without an ETag, unless the
+// handler set one, and switches to passthrough
+func (bw *bufferedResponseWriter) release() error {
+	bw.passthrough = true
+	bw.ResponseWriter.WriteHeader(bw.status)
+	_, err := bw.ResponseWriter.Write(bw.buf.Bytes())
+	bw.buf.Reset()
+	return err
+}
+
+// finish sends a buffered response: 304 when If-None-Match matches its
+// ETag, otherwise the response with the ETag
+func (bw *bufferedResponseWriter) finish(r *http.Request, generator ETagGenerator) {
+	if bw.passthrough {
+		return
+	}
+	if bw.status == 0 {
+		// The handler wrote nothing
+		bw.ResponseWriter.WriteHeader(http.StatusOK)
+		return
+	}
+
+	header := bw.ResponseWriter.Header()
+	etag := header.Get("ETag")
+	if etag == "" {
+		etag = generator(bw.buf.Bytes())
+		header.Set("ETag", etag)
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && MatchesETag(strings.TrimSpace(ifNoneMatch), etag) {
+		for _, name := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+			header.Del(name)
+		}
+		bw.ResponseWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+	bw.ResponseWriter.WriteHeader(http.StatusOK)
+	bw.ResponseWriter.Write(bw.buf.Bytes()) //nolint:errcheck // The client went away
+}
@@ -0,0 +1,166 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package conditional
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// listHandler serves a list whose content can be changed between requests
+func listHandler(body *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(*body))
+	})
+}
+
+func get(h http.Handler, method, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/devices", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareListETag(t *testing.T) {
+	body := `[{"uid":"dev-1"},{"uid":"dev-2"}]`
+	h := Middleware(Options{})(listHandler(&body))
+
+	rec := get(h, http.MethodGet, "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || rec.Body.String() != body {
+		t.Fatalf("expected the list, got %d %q", rec.Code, rec.Body.String())
+	}
+	if etag != DefaultETagGenerator([]byte(body)) {
+		t.Errorf("ETag = %q, want the hash of the body", etag)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != DefaultCacheControl {
+		t.Errorf("Cache-Control = %q, want %q", got, DefaultCacheControl)
+	}
+
+	// Unchanged list: 304 without a body
+	rec = get(h, http.MethodGet, `"other", `+etag)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+	if rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+		t.Errorf("expected no body or Content-Type, got %q %v", rec.Body.String(), rec.Header())
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("expected the ETag on the 304, got %q", rec.Header().Get("ETag"))
+	}
+
+	// A member changes: new ETag, full response
+	body = `[{"uid":"dev-1"},{"uid":"dev-2","spec":{"rack":"r2"}}]`
+	rec = get(h, http.MethodGet, etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("expected a new ETag after a change, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// Weak validators match for If-None-Match
+	rec = get(h, http.MethodGet, "W/"+rec.Header().Get("ETag"))
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected a weak match to give 304, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareKeepsHandlerETag(t *testing.T) {
+	h := Middleware(Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v7"`)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Write([]byte("{}"))
+	}))
+	rec := get(h, http.MethodGet, `"v7"`)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected the handler's ETag to be compared, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("expected the handler's Cache-Control, got %q", got)
+	}
+}
+
+func TestMiddlewarePassthrough(t *testing.T) {
+	// Errors and writes are left alone
+	h := Middleware(Options{CacheControl: "-"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	rec := get(h, http.MethodGet, "*")
+	if rec.Code != http.StatusNotFound || rec.Header().Get("ETag") != "" || rec.Header().Get("Cache-Control") != "" {
+		t.Errorf("expected a plain 404, got %d %v", rec.Code, rec.Header())
+	}
+	rec = get(h, http.MethodPost, "")
+	if rec.Code != http.StatusCreated || rec.Header().Get("ETag") != "" {
+		t.Errorf("expected a plain 201, got %d %v", rec.Code, rec.Header())
+	}
+
+	// Large bodies are sent without an ETag
+	large := strings.Repeat("x", 100)
+	h = Middleware(Options{MaxBodySize: 64})(listHandler(&large))
+	rec = get(h, http.MethodGet, "")
+	if rec.Body.String() != large || rec.Header().Get("ETag") != "" {
+		t.Errorf("expected the large body without an ETag, got %d bytes, ETag %q", rec.Body.Len(), rec.Header().Get("ETag"))
+	}
+
+	// Streamed responses stay streamed
+	h = Middleware(Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("["))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("]"))
+	}))
+	rec = get(h, http.MethodGet, "")
+	if !rec.Flushed || rec.Body.String() != "[]" || rec.Header().Get("ETag") != "" {
+		t.Errorf("expected a flushed response without an ETag, got %q %v", rec.Body.String(), rec.Header())
+	}
+}
+
+func TestGeneratorFor(t *testing.T) {
+	for _, algorithm := range []string{"", "sha256", "md5"} {
+		generator, err := GeneratorFor(algorithm)
+		if err != nil {
+			t.Fatalf("GeneratorFor(%q): %v", algorithm, err)
+		}
+		if etag := generator([]byte("data")); !strings.HasPrefix(etag, `"`) {
+			t.Errorf("GeneratorFor(%q) produced %q", algorithm, etag)
+		}
+	}
+	if _, err := GeneratorFor("crc32"); err == nil {
+		t.Error("expected an error for an unknown algorithm")
+	}
+}
+
+func TestCollectionETag(t *testing.T) {
+	members := []string{`"a"`, `"b"`}
+	etag := CollectionETag(nil, members)
+	if etag != CollectionETag(nil, []string{`"a"`, `"b"`}) {
+		t.Error("expected the same members to give the same ETag")
+	}
+	for _, changed := range [][]string{{`"b"`, `"a"`}, {`"a"`}, {`"a"`, `"c"`}, nil} {
+		if CollectionETag(nil, changed) == etag {
+			t.Errorf("expected %v to change the collection's ETag", changed)
+		}
+	}
+	if strings.HasPrefix(etag, "W/") {
+		t.Errorf("expected a strong ETag from strong members, got %s", etag)
+	}
+	if weak := CollectionETag(MD5ETagGenerator, []string{`W/"a"`, `"b"`}); !strings.HasPrefix(weak, `W/"`) {
+		t.Errorf("expected a weak ETag from a weak member, got %s", weak)
+	}
+
+	if got := Weak(`"a"`); got != `W/"a"` {
+		t.Errorf(`Weak("a") = %s`, got)
+	}
+	if got := Weak(`W/"a"`); got != `W/"a"` {
+		t.Errorf(`Weak(W/"a") = %s`, got)
+	}
+}
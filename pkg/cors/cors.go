@@ -0,0 +1,320 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package cors lets browsers call an API from other origins (Cross-Origin
+// Resource Sharing).
+//
+// A browser-based dashboard served from https://dash.example.com can only
+// read responses of an API on another origin when the API allows it with
+// Access-Control-* headers, and it asks first with a preflight OPTIONS
+// request before requests other than simple GETs and POSTs. CORS answers
+// preflights and adds the headers to the responses of the handlers it wraps:
+//
+//	c := cors.New(cors.Options{
+//	    AllowedOrigins: []string{"https://dash.example.com", "https://*.example.org"},
+//	    MaxAge:         10 * time.Minute,
+//	})
+//	r.Use(c.Handler)
+//
+// Use it on the router itself, not on a group of routes: preflights must be
+// answered before routing, since routes rarely accept OPTIONS.
+//
+// Requests from origins that aren't allowed are served as usual, without
+// the headers, so the browser hides the response from the page. Requests
+// without an Origin header (curl, other servers) are never affected, and
+// without allowed origins the handler does nothing.
+package cors
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMethods are the methods allowed when Options.AllowedMethods is empty
+var DefaultMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// DefaultHeaders are the request headers allowed when Options.AllowedHeaders
+// is empty: those of content negotiation, authentication, conditional
+// requests and request tracing
+var DefaultHeaders = []string{
+	"Accept",
+	"Accept-Language",
+	"Authorization",
+	"Content-Type",
+	"If-Match",
+	"If-None-Match",
+	"X-API-Key",
+	"X-Request-ID",
+}
+
+// DefaultExposedHeaders are the response headers pages may read when
+// Options.ExposedHeaders is empty, besides those browsers always expose
+// (Content-Type, Content-Language, Cache-Control, ...)
+var DefaultExposedHeaders = []string{
+	"ETag",
+	"Link",
+	"Location",
+	"Warning",
+	"X-API-Version",
+	"X-Request-ID",
+}
+
+// Options configures CORS.
+type Options struct {
+	// AllowedOrigins lists the origins allowed to call the API, as
+	// scheme://host[:port]. "*" allows every origin, and a "*" in place of
+	// the first host label allows its subdomains ("https://*.example.com").
+	// No origins disables CORS.
+	AllowedOrigins []string
+
+	// AllowedMethods lists the methods allowed in preflights (default:
+	// DefaultMethods).
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers allowed in preflights
+	// (default: DefaultHeaders). "*" allows every requested header.
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers pages may read (default:
+	// DefaultExposedHeaders).
+	ExposedHeaders []string
+
+	// AllowCredentials lets pages send cookies and Authorization headers
+	// with credentials: "include". The origin is then echoed even when
+	// every origin is allowed, since browsers refuse "*" with credentials.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight response. Zero
+	// leaves it to the browser (5 seconds by default).
+	MaxAge time.Duration
+}
+
+// Enabled reports whether any origin is allowed
+func (o Options) Enabled() bool {
+	return len(o.AllowedOrigins) > 0
+}
+
+// CORS adds CORS headers to the responses of the handlers it wraps.
+type CORS struct {
+	anyOrigin        bool
+	origins          map[string]bool
+	wildcards        [][2]string // prefix and suffix of "scheme://*.domain" origins
+	methods          string
+	anyHeader        bool
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+// New creates a CORS handler.
+//
+// Parameters:
+//   - opts: Allowed origins, methods and headers; empty lists use the defaults
+//
+// Returns:
+//   - *CORS: The handler; use its Handler as middleware
+func New(opts Options) *CORS {
+	c := &CORS{
+		origins:          map[string]bool{},
+		allowCredentials: opts.AllowCredentials,
+	}
+	for _, origin := range opts.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "*":
+			c.anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, domain, _ := strings.Cut(origin, "*")
+			c.wildcards = append(c.wildcards, [2]string{scheme, domain})
+		case origin != "":
+			c.origins[origin] = true
+		}
+	}
+
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = DefaultMethods
+	}
+	var upper []string
+	for _, method := range methods {
+		upper = append(upper, strings.ToUpper(strings.TrimSpace(method)))
+	}
+	c.methods = strings.Join(upper, ", ")
+
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultHeaders
+	}
+	var allowed []string
+	for _, header := range headers {
+		header = strings.TrimSpace(header)
+		if header == "*" {
+			c.anyHeader = true
+			continue
+		}
+		allowed = append(allowed, header)
+	}
+	c.allowedHeaders = strings.Join(allowed, ", ")
+
+	exposed := opts.ExposedHeaders
+	if len(exposed) == 0 {
+		exposed = DefaultExposedHeaders
+	}
+	c.exposedHeaders = strings.Join(exposed, ", ")
+
+	if opts.MaxAge > 0 {
+		c.maxAge = strconv.Itoa(int(opts.MaxAge / time.Second))
+	}
+	return c
+}
+
+// Handler wraps next, answering preflight requests of allowed origins and
+// adding CORS headers to other responses.
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !c.enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		header := w.Header()
+		// Responses differ by origin, so caches must not share them
+		header.Add("Vary", "Origin")
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !c.AllowsOrigin(origin) {
+			if preflight {
+				// Without Access-Control-Allow-Origin the browser refuses
+				// the request
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if c.anyOrigin && !c.allowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if c.allowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if c.exposedHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", c.exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header.Set("Access-Control-Allow-Methods", c.methods)
+		if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+			if c.anyHeader {
+				header.Set("Access-Control-Allow-Headers", requested)
+			} else if c.allowedHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", c.allowedHeaders)
+			}
+		}
+		if c.maxAge != "" {
+			header.Set("Access-Control-Max-Age", c.maxAge)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// enabled reports whether any origin is allowed
+func (c *CORS) enabled() bool {
+	return c.anyOrigin || len(c.origins) > 0 || len(c.wildcards) > 0
+}
+
+// AllowsOrigin reports whether requests from an origin get CORS headers.
+//
+// Parameters:
+//   - origin: Origin request header, e.g. "https://dash.example.com"
+//
+// Returns:
+//   - bool: True if the origin is allowed
+func (c *CORS) AllowsOrigin(origin string) bool {
+	if c.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if c.origins[origin] {
+		return true
+	}
+	for _, wildcard := range c.wildcards {
+		prefix, suffix := wildcard[0], wildcard[1]
+		if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// OptionsFromEnv overrides options with environment variables, so a
+// deployment can change the allowed origins without regenerating:
+//   - FABRICA_CORS_ALLOWED_ORIGINS: comma-separated origins
+//   - FABRICA_CORS_ALLOWED_METHODS: comma-separated methods
+//   - FABRICA_CORS_ALLOWED_HEADERS: comma-separated request headers
+//   - FABRICA_CORS_EXPOSED_HEADERS: comma-separated response headers
+//   - FABRICA_CORS_ALLOW_CREDENTIALS: "true" or "false"
+//   - FABRICA_CORS_MAX_AGE: preflight cache duration in seconds
+//
+// Variables that aren't set keep the value of opts.
+//
+// Parameters:
+//   - opts: Options to override, e.g. those of .fabrica.yaml
+//
+// Returns:
+//   - Options: The options with the environment applied
+func OptionsFromEnv(opts Options) Options {
+	lists := map[string]*[]string{
+		"FABRICA_CORS_ALLOWED_ORIGINS": &opts.AllowedOrigins,
+		"FABRICA_CORS_ALLOWED_METHODS": &opts.AllowedMethods,
+		"FABRICA_CORS_ALLOWED_HEADERS": &opts.AllowedHeaders,
+		"FABRICA_CORS_EXPOSED_HEADERS": &opts.ExposedHeaders,
+	}
+	for name, list := range lists {
+		if v, ok := os.LookupEnv(name); ok {
+			*list = SplitList(v)
+		}
+	}
+	if v, err := strconv.ParseBool(os.Getenv("FABRICA_CORS_ALLOW_CREDENTIALS")); err == nil {
+		opts.AllowCredentials = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("FABRICA_CORS_MAX_AGE")); err == nil && v >= 0 {
+		opts.MaxAge = time.Duration(v) * time.Second
+	}
+	return opts
+}
+
+// SplitList splits a comma-separated list, dropping empty entries
+func SplitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
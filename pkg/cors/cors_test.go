@@ -0,0 +1,200 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// serve runs a request through c, in front of a handler answering 200 OK
+func serve(c *CORS, method, origin string, headers map[string]string) (*httptest.ResponseRecorder, bool) {
+	called := false
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(method, "/nodes", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, called
+}
+
+func TestAllowsOrigin(t *testing.T) {
+	c := New(Options{AllowedOrigins: []string{"https://dash.example.com", "https://*.example.org/", "HTTP://Localhost:3000"}})
+	for origin, want := range map[string]bool{
+		"https://dash.example.com":     true,
+		"https://DASH.example.com":     true,
+		"http://dash.example.com":      false,
+		"https://dash.example.com:444": false,
+		"https://a.example.org":        true,
+		"https://a.b.example.org":      true,
+		"https://example.org":          false,
+		"https://.example.org":         false,
+		"http://a.example.org":         false,
+		"https://evilexample.org":      false,
+		"http://localhost:3000":        true,
+		"null":                         false,
+	} {
+		if got := c.AllowsOrigin(origin); got != want {
+			t.Errorf("AllowsOrigin(%q) = %v, want %v", origin, got, want)
+		}
+	}
+
+	if !New(Options{AllowedOrigins: []string{"*"}}).AllowsOrigin("https://anything.test") {
+		t.Error("expected * to allow every origin")
+	}
+}
+
+func TestSimpleRequest(t *testing.T) {
+	c := New(Options{AllowedOrigins: []string{"https://dash.example.com"}})
+
+	rec, called := serve(c, http.MethodGet, "https://dash.example.com", nil)
+	if !called || rec.Code != http.StatusOK {
+		t.Fatalf("expected the request to be served, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "ETag, Link, Location, Warning, X-API-Version, X-Request-ID" {
+		t.Errorf("Access-Control-Expose-Headers = %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no credentials header, got %q", got)
+	}
+	if got := rec.Header().Values("Vary"); !reflect.DeepEqual(got, []string{"Origin"}) {
+		t.Errorf("Vary = %v", got)
+	}
+
+	// Other origins are served without the headers
+	rec, called = serve(c, http.MethodGet, "https://evil.test", nil)
+	if !called || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected a disallowed origin to be served without CORS headers, got %v", rec.Header())
+	}
+
+	// And requests without an Origin are left alone
+	rec, called = serve(c, http.MethodGet, "", nil)
+	if !called || len(rec.Header()) != 0 {
+		t.Errorf("expected no headers without an Origin, got %v", rec.Header())
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	c := New(Options{
+		AllowedOrigins: []string{"https://dash.example.com"},
+		AllowedMethods: []string{"get", "put"},
+		MaxAge:         10 * time.Minute,
+	})
+	rec, called := serve(c, http.MethodOptions, "https://dash.example.com", map[string]string{
+		"Access-Control-Request-Method":  "PUT",
+		"Access-Control-Request-Headers": "content-type, if-match",
+	})
+	if called {
+		t.Error("expected the preflight to be answered without calling the handler")
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+	for name, want := range map[string]string{
+		"Access-Control-Allow-Origin":   "https://dash.example.com",
+		"Access-Control-Allow-Methods":  "GET, PUT",
+		"Access-Control-Allow-Headers":  "Accept, Accept-Language, Authorization, Content-Type, If-Match, If-None-Match, X-API-Key, X-Request-ID",
+		"Access-Control-Max-Age":        "600",
+		"Access-Control-Expose-Headers": "",
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if got := rec.Header().Values("Vary"); len(got) != 3 {
+		t.Errorf("Vary = %v", got)
+	}
+
+	// A disallowed origin gets an answer without the headers
+	rec, called = serve(c, http.MethodOptions, "https://evil.test", map[string]string{"Access-Control-Request-Method": "PUT"})
+	if called || rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("expected a bare 204 for a disallowed origin, got %d %v", rec.Code, rec.Header())
+	}
+
+	// OPTIONS without Access-Control-Request-Method isn't a preflight
+	_, called = serve(c, http.MethodOptions, "https://dash.example.com", nil)
+	if !called {
+		t.Error("expected a plain OPTIONS request to reach the handler")
+	}
+}
+
+func TestAnyOriginAndHeaders(t *testing.T) {
+	c := New(Options{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}})
+	rec, _ := serve(c, http.MethodOptions, "https://dash.example.com", map[string]string{
+		"Access-Control-Request-Method":  "DELETE",
+		"Access-Control-Request-Headers": "x-custom",
+	})
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "x-custom" {
+		t.Errorf("expected the requested headers to be echoed, got %q", got)
+	}
+
+	// Credentials require the origin itself
+	c = New(Options{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	rec, _ = serve(c, http.MethodGet, "https://dash.example.com", nil)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the origin", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q", got)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	c := New(Options{})
+	rec, called := serve(c, http.MethodOptions, "https://dash.example.com", map[string]string{"Access-Control-Request-Method": "PUT"})
+	if !called || len(rec.Header()) != 0 {
+		t.Errorf("expected requests to pass through without allowed origins, got %v", rec.Header())
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	defaults := Options{
+		AllowedOrigins: []string{"https://dash.example.com"},
+		AllowedMethods: []string{"GET"},
+		MaxAge:         time.Minute,
+	}
+	if got := OptionsFromEnv(defaults); !reflect.DeepEqual(got, defaults) {
+		t.Errorf("expected the options unchanged without variables, got %+v", got)
+	}
+
+	t.Setenv("FABRICA_CORS_ALLOWED_ORIGINS", " https://a.test, ,https://b.test ")
+	t.Setenv("FABRICA_CORS_EXPOSED_HEADERS", "ETag")
+	t.Setenv("FABRICA_CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("FABRICA_CORS_MAX_AGE", "3600")
+	got := OptionsFromEnv(defaults)
+	want := Options{
+		AllowedOrigins:   []string{"https://a.test", "https://b.test"},
+		AllowedMethods:   []string{"GET"},
+		ExposedHeaders:   []string{"ETag"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("OptionsFromEnv = %+v, want %+v", got, want)
+	}
+
+	// An empty variable turns CORS off
+	t.Setenv("FABRICA_CORS_ALLOWED_ORIGINS", "")
+	if OptionsFromEnv(defaults).Enabled() {
+		t.Error("expected an empty FABRICA_CORS_ALLOWED_ORIGINS to disable CORS")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package crd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ConversionReview is the apiextensions.k8s.io/v1 ConversionReview exchanged
+// with a conversion webhook
+type ConversionReview struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Request    *ConversionRequest  `json:"request,omitempty"`
+	Response   *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest asks the webhook to convert objects to a version
+type ConversionRequest struct {
+	UID               string                   `json:"uid"`
+	DesiredAPIVersion string                   `json:"desiredAPIVersion"`
+	Objects           []map[string]interface{} `json:"objects"`
+}
+
+// ConversionResponse holds the converted objects, in request order
+type ConversionResponse struct {
+	UID              string                   `json:"uid"`
+	ConvertedObjects []map[string]interface{} `json:"convertedObjects"`
+	Result           ConversionResult         `json:"result"`
+}
+
+// ConversionResult is the outcome of a conversion
+type ConversionResult struct {
+	Status  string `json:"status"` // Success or Failure
+	Message string `json:"message,omitempty"`
+}
+
+// ConvertFunc converts an object in place from one version of its group to
+// another, e.g. from "v1" to "v2beta1". The apiVersion field is set by the
+// handler after the function returns.
+type ConvertFunc func(obj map[string]interface{}, fromVersion, toVersion string) error
+
+// ConversionHandler serves a conversion webhook.
+//
+// It decodes a ConversionReview, calls convert for every object whose
+// version differs from the desired one, and responds with the converted
+// objects. If any object fails, the whole review fails, as required by the
+// API server.
+//
+// Parameters:
+//   - convert: Converts one object between versions
+//
+// Returns:
+//   - http.Handler: Handler for POST requests from the API server
+func ConversionHandler(convert ConvertFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var review ConversionReview
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(w, "invalid ConversionReview", http.StatusBadRequest)
+			return
+		}
+
+		req := review.Request
+		resp := &ConversionResponse{UID: req.UID, Result: ConversionResult{Status: "Success"}}
+		if err := convertObjects(req, convert, resp); err != nil {
+			resp.ConvertedObjects = nil
+			resp.Result = ConversionResult{Status: "Failure", Message: err.Error()}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ConversionReview{
+			APIVersion: review.APIVersion,
+			Kind:       review.Kind,
+			Response:   resp,
+		})
+	})
+}
+
+// convertObjects converts the objects of a request into resp
+func convertObjects(req *ConversionRequest, convert ConvertFunc, resp *ConversionResponse) error {
+	group, toVersion, ok := strings.Cut(req.DesiredAPIVersion, "/")
+	if !ok {
+		return fmt.Errorf("invalid desiredAPIVersion %q", req.DesiredAPIVersion)
+	}
+
+	resp.ConvertedObjects = make([]map[string]interface{}, 0, len(req.Objects))
+	for _, obj := range req.Objects {
+		apiVersion, _ := obj["apiVersion"].(string)
+		objGroup, fromVersion, ok := strings.Cut(apiVersion, "/")
+		if !ok || objGroup != group {
+			return fmt.Errorf("object has apiVersion %q, expected group %s", apiVersion, group)
+		}
+		if fromVersion != toVersion {
+			if err := convert(obj, fromVersion, toVersion); err != nil {
+				meta, _ := obj["metadata"].(map[string]interface{})
+				name, _ := meta["name"].(string)
+				return fmt.Errorf("failed to convert %q from %s to %s: %w", name, fromVersion, toVersion, err)
+			}
+		}
+		obj["apiVersion"] = req.DesiredAPIVersion
+		resp.ConvertedObjects = append(resp.ConvertedObjects, obj)
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package crd builds Kubernetes CustomResourceDefinitions for Fabrica
+// resources.
+//
+// A Fabrica resource (APIVersion, Kind, Metadata, Spec, Status) maps onto a
+// Kubernetes custom resource with the same spec and status. New builds the
+// apiextensions.k8s.io/v1 CustomResourceDefinition of a resource type, with a
+// structural OpenAPI v3 schema derived from the Go types of its Spec and
+// Status (see SchemaOf). The types in this package mirror the subset of the
+// apiextensions API that Fabrica emits, so it needs no Kubernetes
+// dependencies.
+//
+// Resources with several schema versions can convert between them with a
+// conversion webhook. ConversionHandler serves the ConversionReview protocol
+// and calls a ConvertFunc for each object.
+//
+// Usage:
+//
+//	def, err := crd.New(reflect.TypeOf(device.Device{}), crd.Options{
+//	    Group:  "inventory.example.com",
+//	    Plural: "devices",
+//	})
+//	data, err := def.YAML()
+package crd
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scopes of a custom resource
+const (
+	ScopeNamespaced = "Namespaced"
+	ScopeCluster    = "Cluster"
+)
+
+// CustomResourceDefinition is an apiextensions.k8s.io/v1 CustomResourceDefinition
+type CustomResourceDefinition struct {
+	APIVersion string     `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string     `json:"kind" yaml:"kind"`
+	Metadata   ObjectMeta `json:"metadata" yaml:"metadata"`
+	Spec       Spec       `json:"spec" yaml:"spec"`
+}
+
+// ObjectMeta is the metadata of a CustomResourceDefinition
+type ObjectMeta struct {
+	Name        string            `json:"name" yaml:"name"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// Spec describes the custom resource
+type Spec struct {
+	Group      string      `json:"group" yaml:"group"`
+	Names      Names       `json:"names" yaml:"names"`
+	Scope      string      `json:"scope" yaml:"scope"`
+	Versions   []Version   `json:"versions" yaml:"versions"`
+	Conversion *Conversion `json:"conversion,omitempty" yaml:"conversion,omitempty"`
+}
+
+// Names are the names of the custom resource
+type Names struct {
+	Kind       string   `json:"kind" yaml:"kind"`
+	ListKind   string   `json:"listKind" yaml:"listKind"`
+	Plural     string   `json:"plural" yaml:"plural"`
+	Singular   string   `json:"singular" yaml:"singular"`
+	ShortNames []string `json:"shortNames,omitempty" yaml:"shortNames,omitempty"`
+	Categories []string `json:"categories,omitempty" yaml:"categories,omitempty"`
+}
+
+// Version is a served version of the custom resource
+type Version struct {
+	Name                     string          `json:"name" yaml:"name"`
+	Served                   bool            `json:"served" yaml:"served"`
+	Storage                  bool            `json:"storage" yaml:"storage"`
+	Deprecated               bool            `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Schema                   Validation      `json:"schema" yaml:"schema"`
+	Subresources             *Subresources   `json:"subresources,omitempty" yaml:"subresources,omitempty"`
+	AdditionalPrinterColumns []PrinterColumn `json:"additionalPrinterColumns,omitempty" yaml:"additionalPrinterColumns,omitempty"`
+}
+
+// Validation holds the schema of a version
+type Validation struct {
+	OpenAPIV3Schema *Schema `json:"openAPIV3Schema" yaml:"openAPIV3Schema"`
+}
+
+// Subresources are the subresources of a version
+type Subresources struct {
+	Status *struct{} `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// PrinterColumn is a column shown by kubectl get
+type PrinterColumn struct {
+	Name     string `json:"name" yaml:"name"`
+	Type     string `json:"type" yaml:"type"`
+	JSONPath string `json:"jsonPath" yaml:"jsonPath"`
+}
+
+// Conversion configures conversion between versions
+type Conversion struct {
+	Strategy string             `json:"strategy" yaml:"strategy"` // None or Webhook
+	Webhook  *WebhookConversion `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+}
+
+// WebhookConversion points the API server at a conversion webhook
+type WebhookConversion struct {
+	ClientConfig             WebhookClientConfig `json:"clientConfig" yaml:"clientConfig"`
+	ConversionReviewVersions []string            `json:"conversionReviewVersions" yaml:"conversionReviewVersions"`
+}
+
+// WebhookClientConfig locates the conversion webhook service
+type WebhookClientConfig struct {
+	Service ServiceReference `json:"service" yaml:"service"`
+}
+
+// ServiceReference is the in-cluster service serving the webhook
+type ServiceReference struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Path      string `json:"path,omitempty" yaml:"path,omitempty"`
+	Port      int32  `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// VersionOptions describes one version of the custom resource
+type VersionOptions struct {
+	Name       string // e.g. "v1", "v2beta1"
+	Storage    bool   // Whether objects are stored in this version (exactly one)
+	Deprecated bool
+}
+
+// Options controls the generated definition
+type Options struct {
+	// Group is the API group, e.g. "inventory.example.com" (required)
+	Group string
+
+	// Plural is the plural resource name (default: lowercase kind + "s")
+	Plural string
+
+	// Scope is ScopeNamespaced (default) or ScopeCluster
+	Scope string
+
+	// Versions are the served versions (default: v1). Every version uses the
+	// schema of the Go type.
+	Versions []VersionOptions
+
+	// Categories group resources for kubectl get <category>
+	Categories []string
+
+	// Webhook enables webhook conversion between versions through this service
+	Webhook *ServiceReference
+}
+
+// New builds the CustomResourceDefinition of a Fabrica resource type.
+//
+// The type must have Spec and Status fields. Their Go types become the spec
+// and status schemas; metadata, apiVersion and kind are left to Kubernetes.
+// The status subresource is enabled, and kubectl shows status.phase when the
+// status has a phase field.
+//
+// Parameters:
+//   - t: Resource struct type, e.g. reflect.TypeOf(device.Device{})
+//   - opts: Group, names, scope and versions
+//
+// Returns:
+//   - *CustomResourceDefinition: The definition
+//   - error: If the options are invalid or the type has no Spec
+func New(t reflect.Type, opts Options) (*CustomResourceDefinition, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+	if opts.Group == "" || !strings.Contains(opts.Group, ".") {
+		return nil, fmt.Errorf("group %q must be a domain name such as inventory.example.com", opts.Group)
+	}
+
+	kind := t.Name()
+	plural := opts.Plural
+	if plural == "" {
+		plural = strings.ToLower(kind) + "s"
+	}
+	scope := opts.Scope
+	switch scope {
+	case "":
+		scope = ScopeNamespaced
+	case ScopeNamespaced, ScopeCluster:
+	default:
+		return nil, fmt.Errorf("scope must be %s or %s, got %q", ScopeNamespaced, ScopeCluster, scope)
+	}
+
+	spec, ok := t.FieldByName("Spec")
+	if !ok {
+		return nil, fmt.Errorf("%s has no Spec field", kind)
+	}
+	root := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"apiVersion": {Type: "string"},
+			"kind":       {Type: "string"},
+			"metadata":   {Type: "object"},
+			"spec":       SchemaOf(spec.Type),
+		},
+	}
+	if status, ok := t.FieldByName("Status"); ok {
+		root.Properties["status"] = SchemaOf(status.Type)
+	}
+
+	columns := []PrinterColumn{{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"}}
+	if status := root.Properties["status"]; status != nil && status.Properties["phase"] != nil {
+		columns = append([]PrinterColumn{{Name: "Phase", Type: "string", JSONPath: ".status.phase"}}, columns...)
+	}
+
+	versions := opts.Versions
+	if len(versions) == 0 {
+		versions = []VersionOptions{{Name: "v1", Storage: true}}
+	}
+	storage := 0
+	for _, v := range versions {
+		if v.Storage {
+			storage++
+		}
+	}
+	if storage != 1 {
+		return nil, fmt.Errorf("exactly one version must be the storage version, got %d", storage)
+	}
+
+	def := &CustomResourceDefinition{
+		APIVersion: "apiextensions.k8s.io/v1",
+		Kind:       "CustomResourceDefinition",
+		Metadata:   ObjectMeta{Name: plural + "." + opts.Group},
+		Spec: Spec{
+			Group: opts.Group,
+			Names: Names{
+				Kind:       kind,
+				ListKind:   kind + "List",
+				Plural:     plural,
+				Singular:   strings.ToLower(kind),
+				Categories: opts.Categories,
+			},
+			Scope: scope,
+		},
+	}
+	for _, v := range versions {
+		def.Spec.Versions = append(def.Spec.Versions, Version{
+			Name:                     v.Name,
+			Served:                   true,
+			Storage:                  v.Storage,
+			Deprecated:               v.Deprecated,
+			Schema:                   Validation{OpenAPIV3Schema: root},
+			Subresources:             &Subresources{Status: &struct{}{}},
+			AdditionalPrinterColumns: columns,
+		})
+	}
+	if opts.Webhook != nil {
+		def.Spec.Conversion = &Conversion{
+			Strategy: "Webhook",
+			Webhook: &WebhookConversion{
+				ClientConfig:             WebhookClientConfig{Service: *opts.Webhook},
+				ConversionReviewVersions: []string{"v1"},
+			},
+		}
+	}
+	return def, nil
+}
+
+// YAML encodes the definition as a YAML manifest
+func (d *CustomResourceDefinition) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,238 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package crd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"gopkg.in/yaml.v3"
+)
+
+type Node struct {
+	resource.Resource
+	Spec   NodeSpec   `json:"spec"`
+	Status NodeStatus `json:"status,omitempty"`
+}
+
+type NodeSpec struct {
+	Hostname   string            `json:"hostname" validate:"required,hostname" example:"node01"`
+	Role       string            `json:"role,omitempty" validate:"omitempty,oneof=compute storage"`
+	Cores      int32             `json:"cores,omitempty" validate:"min=1,max=512" example:"64"`
+	Weight     float64           `json:"weight,omitempty" validate:"gt=0"`
+	MACs       []string          `json:"macs,omitempty" validate:"max=4,dive,mac"`
+	Speeds     []int             `json:"speeds,omitempty" enum:"10,25,100"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	BMC        *BMC              `json:"bmc,omitempty"`
+	Extra      interface{}       `json:"extra,omitempty"`
+	Raw        json.RawMessage   `json:"raw,omitempty"`
+	Firmware   []byte            `json:"firmware,omitempty"`
+	Seen       time.Time         `json:"seen,omitempty"`
+	Internal   string            `json:"-"`
+	unexported string
+}
+
+type BMC struct {
+	Address string `json:"address" validate:"required,ipv4"`
+	Peer    *BMC   `json:"peer,omitempty"`
+}
+
+type NodeStatus struct {
+	Phase string `json:"phase,omitempty"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := SchemaOf(reflect.TypeOf(NodeSpec{}))
+	if s.Type != "object" || !reflect.DeepEqual(s.Required, []string{"hostname"}) {
+		t.Fatalf("unexpected root %+v", s)
+	}
+	if len(s.Properties) != 12 || s.Properties["Internal"] != nil || s.Properties["unexported"] != nil {
+		t.Errorf("unexpected properties %v", s.Properties)
+	}
+
+	p := s.Properties
+	if p["hostname"].Format != "hostname" || p["hostname"].Example != "node01" {
+		t.Errorf("hostname: %+v", p["hostname"])
+	}
+	if !reflect.DeepEqual(p["role"].Enum, []interface{}{"compute", "storage"}) {
+		t.Errorf("role: %+v", p["role"])
+	}
+	if c := p["cores"]; c.Type != "integer" || c.Format != "int32" || *c.Minimum != 1 || *c.Maximum != 512 || c.Example != float64(64) {
+		t.Errorf("cores: %+v", c)
+	}
+	if w := p["weight"]; *w.Minimum != 0 || !w.ExclusiveMinimum {
+		t.Errorf("weight: %+v", w)
+	}
+	if m := p["macs"]; m.Type != "array" || *m.MaxItems != 4 || m.Items.Type != "string" || m.Items.Format != "" {
+		t.Errorf("macs: %+v", m)
+	}
+	if sp := p["speeds"]; sp.Type != "array" || !reflect.DeepEqual(sp.Items.Enum, []interface{}{int64(10), int64(25), int64(100)}) {
+		t.Errorf("speeds: %+v", sp)
+	}
+	if l := p["labels"]; l.Type != "object" || l.AdditionalProperties.Type != "string" {
+		t.Errorf("labels: %+v", l)
+	}
+	bmc := p["bmc"]
+	if bmc.Type != "object" || bmc.Properties["address"].Format != "ipv4" || !reflect.DeepEqual(bmc.Required, []string{"address"}) {
+		t.Errorf("bmc: %+v", bmc)
+	}
+	if peer := bmc.Properties["peer"]; !peer.PreserveUnknownFields {
+		t.Errorf("recursive types should accept any value, got %+v", peer)
+	}
+	if !p["extra"].PreserveUnknownFields || !p["raw"].PreserveUnknownFields {
+		t.Errorf("extra/raw: %+v %+v", p["extra"], p["raw"])
+	}
+	if p["firmware"].Format != "byte" || p["seen"].Format != "date-time" {
+		t.Errorf("firmware/seen: %+v %+v", p["firmware"], p["seen"])
+	}
+}
+
+func TestComponentSchemaOf(t *testing.T) {
+	name := func(t reflect.Type) string {
+		if t == reflect.TypeOf(NodeSpec{}) || t == reflect.TypeOf(BMC{}) {
+			return t.Name()
+		}
+		return ""
+	}
+	components := map[string]*Schema{}
+	s := ComponentSchemaOf(reflect.TypeOf(Node{}), name, components)
+
+	// Node isn't named, so it is inlined, referencing its spec
+	if s.Type != "object" || s.Properties["spec"].Ref != "#/components/schemas/NodeSpec" {
+		t.Fatalf("unexpected root %+v", s)
+	}
+	if s.Properties["status"].Type != "object" {
+		t.Errorf("status should be inlined, got %+v", s.Properties["status"])
+	}
+	if len(components) != 2 {
+		t.Fatalf("expected NodeSpec and BMC components, got %v", components)
+	}
+
+	spec := components["NodeSpec"]
+	if !reflect.DeepEqual(spec.Required, []string{"hostname"}) || spec.Properties["hostname"].Format != "hostname" {
+		t.Errorf("NodeSpec: %+v", spec)
+	}
+	if bmc := spec.Properties["bmc"]; !bmc.Nullable || len(bmc.AllOf) != 1 || bmc.AllOf[0].Ref != "#/components/schemas/BMC" {
+		t.Errorf("pointers to components should be nullable references, got %+v", bmc)
+	}
+
+	bmc := components["BMC"]
+	if bmc.Properties["address"].Format != "ipv4" || !reflect.DeepEqual(bmc.Required, []string{"address"}) {
+		t.Errorf("BMC: %+v", bmc)
+	}
+	if peer := bmc.Properties["peer"]; len(peer.AllOf) != 1 || peer.AllOf[0].Ref != "#/components/schemas/BMC" {
+		t.Errorf("recursive types should reference their component, got %+v", peer)
+	}
+}
+
+func TestNew(t *testing.T) {
+	def, err := New(reflect.TypeOf(&Node{}), Options{
+		Group:    "inventory.example.com",
+		Versions: []VersionOptions{{Name: "v1", Storage: true}, {Name: "v2beta1"}},
+		Webhook:  &ServiceReference{Name: "conv", Namespace: "inventory", Path: "/convert"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Metadata.Name != "nodes.inventory.example.com" || def.Spec.Names.ListKind != "NodeList" || def.Spec.Scope != ScopeNamespaced {
+		t.Errorf("unexpected definition %+v", def)
+	}
+	if len(def.Spec.Versions) != 2 || !def.Spec.Versions[0].Storage || def.Spec.Versions[1].Storage {
+		t.Errorf("unexpected versions %+v", def.Spec.Versions)
+	}
+	if cols := def.Spec.Versions[0].AdditionalPrinterColumns; len(cols) != 2 || cols[0].JSONPath != ".status.phase" {
+		t.Errorf("unexpected columns %+v", cols)
+	}
+	if def.Spec.Conversion.Strategy != "Webhook" {
+		t.Errorf("unexpected conversion %+v", def.Spec.Conversion)
+	}
+
+	data, err := def.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"apiVersion: apiextensions.k8s.io/v1", "openAPIV3Schema:", "x-kubernetes-preserve-unknown-fields: true", "status: {}"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in\n%s", want, data)
+		}
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		t    reflect.Type
+		opts Options
+		want string
+	}{
+		{"no group", reflect.TypeOf(Node{}), Options{}, "domain name"},
+		{"bad scope", reflect.TypeOf(Node{}), Options{Group: "a.b", Scope: "Global"}, "scope"},
+		{"no spec", reflect.TypeOf(BMC{}), Options{Group: "a.b"}, "no Spec"},
+		{"two storage versions", reflect.TypeOf(Node{}), Options{Group: "a.b", Versions: []VersionOptions{{Name: "v1", Storage: true}, {Name: "v2", Storage: true}}}, "storage version"},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.t, tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestConversionHandler(t *testing.T) {
+	handler := ConversionHandler(func(obj map[string]interface{}, from, to string) error {
+		spec := obj["spec"].(map[string]interface{})
+		if spec["hostname"] == "bad" {
+			return errors.New("no hostname")
+		}
+		spec["converted"] = from + "->" + to
+		return nil
+	})
+	review := func(objects ...string) ConversionResponse {
+		body := `{"apiVersion":"apiextensions.k8s.io/v1","kind":"ConversionReview","request":{"uid":"r1","desiredAPIVersion":"inventory.example.com/v2","objects":[` + strings.Join(objects, ",") + `]}}`
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/convert", strings.NewReader(body)))
+		var out ConversionReview
+		if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil || out.Response == nil {
+			t.Fatalf("invalid response %d %s", rec.Code, rec.Body)
+		}
+		return *out.Response
+	}
+
+	resp := review(
+		`{"apiVersion":"inventory.example.com/v1","metadata":{"name":"a"},"spec":{"hostname":"a"}}`,
+		`{"apiVersion":"inventory.example.com/v2","metadata":{"name":"b"},"spec":{"hostname":"b"}}`,
+	)
+	if resp.UID != "r1" || resp.Result.Status != "Success" || len(resp.ConvertedObjects) != 2 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if spec := resp.ConvertedObjects[0]["spec"].(map[string]interface{}); spec["converted"] != "v1->v2" {
+		t.Errorf("expected a conversion, got %v", spec)
+	}
+	if spec := resp.ConvertedObjects[1]["spec"].(map[string]interface{}); spec["converted"] != nil {
+		t.Errorf("objects in the desired version should be unchanged, got %v", spec)
+	}
+	if resp.ConvertedObjects[0]["apiVersion"] != "inventory.example.com/v2" {
+		t.Errorf("apiVersion not updated: %v", resp.ConvertedObjects[0])
+	}
+
+	resp = review(`{"apiVersion":"inventory.example.com/v1","metadata":{"name":"x"},"spec":{"hostname":"bad"}}`)
+	if resp.Result.Status != "Failure" || !strings.Contains(resp.Result.Message, `"x"`) || resp.ConvertedObjects != nil {
+		t.Errorf("expected a failure, got %+v", resp)
+	}
+	resp = review(`{"apiVersion":"other.example.com/v1","spec":{}}`)
+	if resp.Result.Status != "Failure" {
+		t.Errorf("expected a group mismatch to fail, got %+v", resp)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package crd
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/validation"
+)
+
+// Schema is a structural OpenAPI v3 schema, as accepted by CustomResourceDefinitions
+type Schema struct {
+	// Ref, AllOf and Nullable are only set in schemas from ComponentSchemaOf,
+	// since CustomResourceDefinitions don't accept references
+	Ref      string    `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	AllOf    []*Schema `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	Nullable bool      `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Description          string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MinLength            *int64             `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int64             `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems             *int64             `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *int64             `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	Example              interface{}        `json:"example,omitempty" yaml:"example,omitempty"`
+
+	// PreserveUnknownFields accepts any value, for fields whose Go type
+	// doesn't describe their JSON (interface{}, json.RawMessage, custom marshalers)
+	PreserveUnknownFields bool `json:"x-kubernetes-preserve-unknown-fields,omitempty" yaml:"x-kubernetes-preserve-unknown-fields,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// formats maps validator tags to OpenAPI formats known to Kubernetes
+var formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"cidr":     "cidr",
+	"hostname": "hostname",
+	"datetime": "date-time",
+}
+
+// SchemaOf returns the structural schema of a Go type as encoded by
+// encoding/json.
+//
+// Struct fields use their json names; embedded structs are flattened and
+// fields tagged json:"-" are skipped. Fields with a "required" validate tag
+// are required. Other validate tags become constraints: min/max/gte/lte/gt/lt
+// (bounds of numbers, lengths of strings and lists), len, oneof (enum) and
+// the formats email, url, uuid, ipv4, ipv6, cidr and hostname. An enum tag
+// (see validation.EnumTag) also lists the allowed values. Types whose
+// JSON form can't be known from the Go type (interface{}, json.Marshaler,
+// recursive types) accept any value.
+//
+// Parameters:
+//   - t: The Go type
+//
+// Returns:
+//   - *Schema: The schema of the JSON encoding of t
+func SchemaOf(t reflect.Type) *Schema {
+	b := &schemaBuilder{visiting: map[reflect.Type]bool{}}
+	return b.schemaOf(t)
+}
+
+// ComponentSchemaOf returns the schema of a Go type for an OpenAPI document.
+// It is SchemaOf, except that named struct types for which name returns a
+// component name are described once, in components, and referenced with
+// $ref wherever they appear (including t itself), so nested and recursive
+// types are complete. Pointer fields are nullable.
+//
+// Parameters:
+//   - t: The Go type
+//   - name: Returns the component name of a named struct type, or "" to inline it
+//   - components: Component schemas by name, filled in with those t uses
+//
+// Returns:
+//   - *Schema: The schema of the JSON encoding of t
+func ComponentSchemaOf(t reflect.Type, name func(reflect.Type) string, components map[string]*Schema) *Schema {
+	b := &schemaBuilder{visiting: map[reflect.Type]bool{}, name: name, components: components}
+	return b.schemaOf(t)
+}
+
+// schemaBuilder builds the schemas of Go types, inlining every struct type
+// unless name is set
+type schemaBuilder struct {
+	visiting   map[reflect.Type]bool
+	name       func(reflect.Type) string
+	components map[string]*Schema
+}
+
+func (b *schemaBuilder) schemaOf(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr && b.name != nil {
+		s := b.schemaOf(t.Elem())
+		if s.Ref != "" {
+			// Siblings of $ref are ignored, so the reference is wrapped
+			s = &Schema{AllOf: []*Schema{s}}
+		}
+		s.Nullable = true
+		return s
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Implements(jsonMarshalerType), reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &Schema{PreserveUnknownFields: true}
+	case t.Implements(textMarshalerType), reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s := &Schema{Type: "integer"}
+		switch t.Kind() {
+		case reflect.Int32, reflect.Uint32:
+			s.Format = "int32"
+		case reflect.Int64, reflect.Uint64:
+			s.Format = "int64"
+		}
+		return s
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if b.name != nil && t.Name() != "" {
+			if name := b.name(t); name != "" {
+				if _, ok := b.components[name]; !ok {
+					// Registered before its fields, so references back to
+					// the type end here
+					b.components[name] = &Schema{}
+					*b.components[name] = *b.object(t)
+				}
+				return &Schema{Ref: "#/components/schemas/" + name}
+			}
+		}
+		if b.visiting[t] {
+			return &Schema{Type: "object", PreserveUnknownFields: true}
+		}
+		b.visiting[t] = true
+		defer delete(b.visiting, t)
+		return b.object(t)
+	}
+	return &Schema{PreserveUnknownFields: true}
+}
+
+// object returns the object schema of a struct type
+func (b *schemaBuilder) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	b.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+// addFields adds the JSON fields of a struct to an object schema
+func (b *schemaBuilder) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(s, embedded)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := b.schemaOf(f.Type)
+		if applyValidateTag(field, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		applyEnumTag(field, validation.EnumValues(f.Tag))
+		if example, ok := validation.ExampleValue(f.Tag, f.Type); ok && field.Ref == "" {
+			field.Example = example
+		}
+		s.Properties[name] = field
+	}
+}
+
+// applyValidateTag adds the constraints of a validate tag to a field schema
+// and reports whether the field is required
+func applyValidateTag(s *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// Later rules apply to the items
+			return required
+		case "required":
+			required = true
+		case "min", "gte":
+			setBound(s, param, true, false)
+		case "max", "lte":
+			setBound(s, param, false, false)
+		case "gt":
+			setBound(s, param, true, true)
+		case "lt":
+			setBound(s, param, false, true)
+		case "len":
+			setBound(s, param, true, false)
+			setBound(s, param, false, false)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(s.Type, v))
+			}
+		default:
+			if format, ok := formats[name]; ok && s.Type == "string" {
+				s.Format = format
+			}
+		}
+	}
+	return required
+}
+
+// applyEnumTag sets the allowed values of an enum-tagged field, or of the
+// items of a list field
+func applyEnumTag(s *Schema, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	if s.Type == "array" && s.Items != nil {
+		s = s.Items
+	}
+	s.Enum = nil
+	for _, v := range values {
+		s.Enum = append(s.Enum, enumValue(s.Type, v))
+	}
+}
+
+// setBound sets a lower or upper bound: a value for numbers, a length for
+// strings and arrays
+func setBound(s *Schema, param string, lower, exclusive bool) {
+	switch s.Type {
+	case "integer", "number":
+		v, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			s.Minimum, s.ExclusiveMinimum = &v, exclusive
+		} else {
+			s.Maximum, s.ExclusiveMaximum = &v, exclusive
+		}
+	case "string", "array":
+		n, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return
+		}
+		if exclusive && lower {
+			n++
+		} else if exclusive {
+			n--
+		}
+		switch {
+		case s.Type == "string" && lower:
+			s.MinLength = &n
+		case s.Type == "string":
+			s.MaxLength = &n
+		case lower:
+			s.MinItems = &n
+		default:
+			s.MaxItems = &n
+		}
+	}
+}
+
+// enumValue converts a oneof value to the type of the field
+func enumValue(typ, v string) interface{} {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
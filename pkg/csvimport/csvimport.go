@@ -0,0 +1,537 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package csvimport turns CSV rows into resource create requests.
+//
+// Hardware inventory usually arrives as a spreadsheet from a vendor, with
+// one row per device and the vendor's own column names. A Mapping names the
+// target of each column: the resource name, a label, an annotation, or a
+// spec field. Read converts every row to the JSON body of a create request,
+// checking the types of the cells, and Import creates the resources one row
+// at a time, collecting an error list per row instead of stopping at the
+// first bad row.
+//
+// Mapping targets:
+//
+//	name                 metadata.name
+//	labels.<key>         a label
+//	annotations.<key>    an annotation
+//	<field>              a spec field by JSON name (also spec.<field>)
+//
+// Without a mapping, the CSV header must use the target names directly.
+//
+// Cells are converted according to the Go type of the spec field: numbers
+// and booleans are parsed, lists are split on ";", and other types (maps,
+// structs) are read as JSON. Empty cells are left out of the request.
+//
+// The generated server serves POST /<resources>/import with Import, using its
+// create handler for each row (see Handler), so imported resources get the
+// same validation, quotas and events as resources created one by one.
+//
+// Usage:
+//
+//	rows, err := csvimport.Read(file, columns, csvimport.Options{Mapping: mapping})
+//	if err != nil {
+//	    // the header doesn't match the columns or the mapping
+//	}
+//	result := csvimport.Import(ctx, rows, csvimport.Handler(r, CreateDevice))
+package csvimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+)
+
+// DefaultMaxRows is the row limit used when Options.MaxRows is not set
+const DefaultMaxRows = 10000
+
+// DefaultListSeparator separates the items of list cells
+const DefaultListSeparator = ";"
+
+// Column is a spec field that can be imported
+type Column struct {
+	// Name is the JSON name of the spec field (e.g., "serialNumber")
+	Name string `json:"name"`
+
+	// Type is the Go type of the field (e.g., "string", "int", "[]string")
+	Type string `json:"type"`
+
+	// Required marks fields that every row must set
+	Required bool `json:"required,omitempty"`
+}
+
+// Mapping assigns a target to each CSV column
+type Mapping struct {
+	// Columns maps a CSV column header to its target: "name",
+	// "labels.<key>", "annotations.<key>" or a spec field
+	Columns map[string]string `json:"columns"`
+}
+
+// Options controls Read
+type Options struct {
+	// Mapping names the target of each column. Without one, the headers are
+	// the targets. Columns missing from the mapping are ignored.
+	Mapping *Mapping
+
+	// MaxRows limits the number of data rows (default DefaultMaxRows)
+	MaxRows int
+
+	// ListSeparator splits list cells (default DefaultListSeparator)
+	ListSeparator string
+}
+
+// Row is a data row converted to a create request
+type Row struct {
+	// Line is the line of the row in the CSV file, counting the header as 1
+	Line int
+
+	// Name is the resource name from the row
+	Name string
+
+	// Body is the create request: name, labels, annotations and spec fields
+	Body map[string]interface{}
+
+	// Errors lists the cells that couldn't be converted
+	Errors []string
+}
+
+// Result reports the outcome of an import
+type Result struct {
+	// DryRun is set when the rows were only validated
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Total is the number of data rows
+	Total int `json:"total"`
+
+	// Succeeded counts the rows created (or valid, in a dry run)
+	Succeeded int `json:"succeeded"`
+
+	// Failed counts the rows with errors
+	Failed int `json:"failed"`
+
+	// Rows has one entry per data row, in file order
+	Rows []RowResult `json:"rows"`
+}
+
+// RowResult is the outcome of one row
+type RowResult struct {
+	Line   int      `json:"line"`
+	Name   string   `json:"name,omitempty"`
+	UID    string   `json:"uid,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// CreateFunc creates a resource from the JSON create request of a row.
+// It returns the UID of the new resource (empty in a dry run).
+type CreateFunc func(ctx context.Context, body []byte) (uid string, err error)
+
+// Template returns the mapping of every target to a column of the same
+// name. Renaming the keys to the columns of a vendor's file gives its
+// mapping.
+//
+// Parameters:
+//   - columns: Spec fields of the resource
+//
+// Returns:
+//   - Mapping: Identity mapping of name and the spec fields
+func Template(columns []Column) Mapping {
+	m := Mapping{Columns: map[string]string{"name": "name"}}
+	for _, c := range columns {
+		m.Columns[c.Name] = c.Name
+	}
+	return m
+}
+
+// target is where a CSV column goes
+type target struct {
+	header string
+	kind   string // "name", "labels", "annotations" or "spec"
+	key    string // label or annotation key, or spec field name
+	column *Column
+}
+
+// Read parses CSV rows into create requests.
+//
+// Header problems (an unknown target, a mapped column missing from the file,
+// no name column, a required field without a column) fail the whole read.
+// Cell problems are recorded in the Errors of their row.
+//
+// Parameters:
+//   - r: CSV data with a header row
+//   - columns: Spec fields of the resource
+//   - opts: Mapping and limits
+//
+// Returns:
+//   - []Row: One row per data line
+//   - error: If the CSV is malformed, the header doesn't match, or there
+//     are more than MaxRows rows
+func Read(r io.Reader, columns []Column, opts Options) ([]Row, error) {
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = DefaultMaxRows
+	}
+	if opts.ListSeparator == "" {
+		opts.ListSeparator = DefaultListSeparator
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV has no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(header) > 0 {
+		// Spreadsheets often save UTF-8 with a byte order mark
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	targets, err := resolveHeader(header, columns, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(rows) == opts.MaxRows {
+			return nil, fmt.Errorf("CSV has more than %d rows", opts.MaxRows)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, convertRow(line, header, record, targets, columns, opts.ListSeparator))
+	}
+	return rows, nil
+}
+
+// resolveHeader finds the target of every column of the header
+func resolveHeader(header []string, columns []Column, mapping *Mapping) ([]*target, error) {
+	byName := make(map[string]*Column, len(columns))
+	for i := range columns {
+		byName[columns[i].Name] = &columns[i]
+	}
+
+	positions := make(map[string]int, len(header))
+	duplicates := make(map[string]bool)
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if _, dup := positions[h]; dup {
+			duplicates[h] = true
+			continue
+		}
+		positions[h] = i
+	}
+
+	targets := make([]*target, len(header))
+	assign := func(i int, header, name string) error {
+		// Repeated columns are fine as long as they aren't imported
+		if duplicates[header] {
+			return fmt.Errorf("duplicate column %q", header)
+		}
+		t, err := parseTarget(name, byName)
+		if err != nil {
+			return fmt.Errorf("column %q: %w", header, err)
+		}
+		t.header = header
+		targets[i] = t
+		return nil
+	}
+
+	if mapping == nil {
+		for i, h := range header {
+			if err := assign(i, strings.TrimSpace(h), strings.TrimSpace(h)); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		// Sorted for a deterministic first error
+		headers := make([]string, 0, len(mapping.Columns))
+		for h := range mapping.Columns {
+			headers = append(headers, h)
+		}
+		sort.Strings(headers)
+		for _, h := range headers {
+			i, ok := positions[h]
+			if !ok {
+				return nil, fmt.Errorf("mapped column %q is not in the CSV header", h)
+			}
+			if err := assign(i, h, mapping.Columns[h]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, t := range targets {
+		if t == nil {
+			continue
+		}
+		id := t.kind + "." + t.key
+		if other, dup := seen[id]; dup {
+			return nil, fmt.Errorf("columns %q and %q have the same target", other, t.header)
+		}
+		seen[id] = t.header
+	}
+	if _, ok := seen["name."]; !ok {
+		return nil, errors.New("no column is mapped to the resource name")
+	}
+	for _, c := range columns {
+		if _, ok := seen["spec."+c.Name]; c.Required && !ok {
+			return nil, fmt.Errorf("no column is mapped to the required field %q", c.Name)
+		}
+	}
+	return targets, nil
+}
+
+// parseTarget resolves a mapping target
+func parseTarget(name string, columns map[string]*Column) (*target, error) {
+	switch {
+	case name == "name":
+		return &target{kind: "name"}, nil
+	case strings.HasPrefix(name, "labels."), strings.HasPrefix(name, "annotations."):
+		kind, key, _ := strings.Cut(name, ".")
+		if key == "" {
+			return nil, fmt.Errorf("target %q has no key", name)
+		}
+		return &target{kind: kind, key: key}, nil
+	}
+
+	field := strings.TrimPrefix(name, "spec.")
+	if c, ok := columns[field]; ok {
+		return &target{kind: "spec", key: field, column: c}, nil
+	}
+	// Accept headers that differ only in case, e.g. "SerialNumber"
+	for n, c := range columns {
+		if strings.EqualFold(n, field) {
+			return &target{kind: "spec", key: n, column: c}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown target %q (use name, labels.<key>, annotations.<key> or a spec field)", name)
+}
+
+// convertRow builds the create request of one record
+func convertRow(line int, header, record []string, targets []*target, columns []Column, sep string) Row {
+	row := Row{Line: line, Body: make(map[string]interface{})}
+	if len(record) != len(header) {
+		row.Errors = append(row.Errors, fmt.Sprintf("row has %d columns, header has %d", len(record), len(header)))
+		return row
+	}
+
+	failed := make(map[string]bool)
+	for i, t := range targets {
+		if t == nil {
+			continue
+		}
+		cell := strings.TrimSpace(record[i])
+		if cell == "" {
+			continue
+		}
+		switch t.kind {
+		case "name":
+			row.Name = cell
+			row.Body["name"] = cell
+		case "labels", "annotations":
+			m, _ := row.Body[t.kind].(map[string]string)
+			if m == nil {
+				m = make(map[string]string)
+				row.Body[t.kind] = m
+			}
+			m[t.key] = cell
+		case "spec":
+			v, err := convert(cell, t.column.Type, sep)
+			if err != nil {
+				row.Errors = append(row.Errors, fmt.Sprintf("column %q: %v", t.header, err))
+				failed[t.key] = true
+				continue
+			}
+			row.Body[t.key] = v
+		}
+	}
+
+	if row.Name == "" {
+		row.Errors = append(row.Errors, "name is empty")
+	}
+	for _, c := range columns {
+		if _, ok := row.Body[c.Name]; c.Required && !ok && !failed[c.Name] {
+			row.Errors = append(row.Errors, fmt.Sprintf("%s is required", c.Name))
+		}
+	}
+	return row
+}
+
+// convert parses a cell according to the Go type of its field
+func convert(cell, typ, sep string) (interface{}, error) {
+	typ = strings.TrimPrefix(typ, "*")
+	if elem, ok := strings.CutPrefix(typ, "[]"); ok && elem != "byte" {
+		items := []interface{}{}
+		for _, part := range strings.Split(cell, sep) {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			v, err := convert(part, elem, sep)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	}
+
+	switch typ {
+	case "string", "time.Time", "[]byte":
+		return cell, nil
+	case "bool":
+		v, err := strconv.ParseBool(cell)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", cell)
+		}
+		return v, nil
+	case "int", "int8", "int16", "int32", "int64":
+		v, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", cell)
+		}
+		return v, nil
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		v, err := strconv.ParseUint(cell, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a non-negative integer", cell)
+		}
+		return v, nil
+	case "float32", "float64":
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", cell)
+		}
+		return v, nil
+	default:
+		// Maps, structs and named types are written as JSON
+		var v interface{}
+		if err := json.Unmarshal([]byte(cell), &v); err != nil {
+			return nil, fmt.Errorf("expected JSON for %s: %v", typ, err)
+		}
+		return v, nil
+	}
+}
+
+// Import creates a resource for every row without errors.
+//
+// Rows are processed in order, and a failed row doesn't stop the import.
+// Resources created before a failure are kept.
+//
+// Parameters:
+//   - ctx: Context passed to create
+//   - rows: Rows from Read
+//   - create: Creates (or validates) one resource
+//
+// Returns:
+//   - *Result: Outcome of every row
+func Import(ctx context.Context, rows []Row, create CreateFunc) *Result {
+	result := &Result{Total: len(rows), Rows: make([]RowResult, 0, len(rows))}
+	for _, row := range rows {
+		res := RowResult{Line: row.Line, Name: row.Name, Errors: row.Errors}
+		if len(res.Errors) == 0 {
+			body, err := json.Marshal(row.Body)
+			if err == nil {
+				res.UID, err = create(ctx, body)
+			}
+			if err != nil {
+				res.Errors = []string{err.Error()}
+			}
+		}
+		if len(res.Errors) > 0 {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+		result.Rows = append(result.Rows, res)
+	}
+	return result
+}
+
+// Handler returns a CreateFunc that calls a create handler.
+//
+// Each row is sent to h as a copy of r with the row as its JSON body, so the
+// handler sees the context (authentication, API version) of the import
+// request. A 2xx response succeeds with the metadata.uid of the response;
+// any other response fails with the detail of its problem document.
+//
+// Parameters:
+//   - r: The import request
+//   - h: Create handler of the resource, e.g. CreateDevice
+//
+// Returns:
+//   - CreateFunc: Creates one resource per call
+func Handler(r *http.Request, h http.HandlerFunc) CreateFunc {
+	return func(ctx context.Context, body []byte) (string, error) {
+		req := r.Clone(ctx)
+		req.Method = http.MethodPost
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := &recorder{header: make(http.Header)}
+		h(rec, req)
+
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		if rec.status < 200 || rec.status > 299 {
+			var problem errcode.Problem
+			if err := json.Unmarshal(rec.body.Bytes(), &problem); err == nil && problem.Detail != "" {
+				return "", errors.New(problem.Detail)
+			}
+			return "", fmt.Errorf("create failed with status %d", rec.status)
+		}
+
+		var created struct {
+			Metadata struct {
+				UID string `json:"uid"`
+			} `json:"metadata"`
+		}
+		_ = json.Unmarshal(rec.body.Bytes(), &created)
+		return created.Metadata.UID, nil
+	}
+}
+
+// recorder is the in-memory response of one row
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
@@ -0,0 +1,245 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package csvimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+)
+
+var deviceColumns = []Column{
+	{Name: "serialNumber", Type: "string", Required: true},
+	{Name: "port", Type: "int"},
+	{Name: "managed", Type: "bool"},
+	{Name: "tags", Type: "[]string"},
+	{Name: "properties", Type: "map[string]string"},
+}
+
+func TestRead_Mapping(t *testing.T) {
+	data := "\ufeffHostname,Serial No,Port,Managed,Tags,Rack,Notes,Notes\n" +
+		"sw-1,SN1,22,true,core; spine,R1,a,b\n" +
+		"sw-2,SN2,,,,,,\n"
+	mapping := &Mapping{Columns: map[string]string{
+		"Hostname":  "name",
+		"Serial No": "serialNumber",
+		"Port":      "spec.port",
+		"Managed":   "Managed",
+		"Tags":      "tags",
+		"Rack":      "labels.rack",
+	}}
+
+	rows, err := Read(strings.NewReader(data), deviceColumns, Options{Mapping: mapping})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	want := map[string]interface{}{
+		"name":         "sw-1",
+		"serialNumber": "SN1",
+		"port":         int64(22),
+		"managed":      true,
+		"tags":         []interface{}{"core", "spine"},
+		"labels":       map[string]string{"rack": "R1"},
+	}
+	if !reflect.DeepEqual(rows[0].Body, want) || rows[0].Line != 2 || rows[0].Name != "sw-1" {
+		t.Errorf("row 1 = %+v, want body %v", rows[0], want)
+	}
+	if len(rows[0].Errors) != 0 {
+		t.Errorf("unexpected errors: %v", rows[0].Errors)
+	}
+	if want := map[string]interface{}{"name": "sw-2", "serialNumber": "SN2"}; !reflect.DeepEqual(rows[1].Body, want) {
+		t.Errorf("empty cells should be left out, got %v", rows[1].Body)
+	}
+}
+
+func TestRead_RowErrors(t *testing.T) {
+	data := "name,serialNumber,port,managed,properties\n" +
+		`ok,SN1,1,false,"{""a"":""b""}"` + "\n" +
+		"bad,,x,maybe,{\n" +
+		",SN3,3,true,{}\n" +
+		"short,SN4\n"
+
+	rows, err := Read(strings.NewReader(data), deviceColumns, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantErrors := []int{0, 4, 1, 1}
+	for i, row := range rows {
+		if len(row.Errors) != wantErrors[i] {
+			t.Errorf("line %d: expected %d errors, got %v", row.Line, wantErrors[i], row.Errors)
+		}
+	}
+	if props := rows[0].Body["properties"]; !reflect.DeepEqual(props, map[string]interface{}{"a": "b"}) {
+		t.Errorf("expected JSON cell to be decoded, got %v", props)
+	}
+	if !strings.Contains(strings.Join(rows[1].Errors, "\n"), "serialNumber is required") {
+		t.Errorf("expected a missing required field, got %v", rows[1].Errors)
+	}
+}
+
+func TestRead_HeaderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping *Mapping
+		want    string
+	}{
+		{"empty", "", nil, "no header"},
+		{"unknown column", "name,serialNumber,color\n", nil, `unknown target "color"`},
+		{"no name", "serialNumber\n", nil, "resource name"},
+		{"required missing", "name,port\n", nil, `required field "serialNumber"`},
+		{"duplicate", "name,serialNumber,name\n", nil, "duplicate"},
+		{"missing mapped column", "Host,SN\n", &Mapping{Columns: map[string]string{"Host": "name", "Serial": "serialNumber"}}, `"Serial" is not in the CSV header`},
+		{"same target", "A,B,SN\n", &Mapping{Columns: map[string]string{"A": "name", "B": "name", "SN": "serialNumber"}}, "same target"},
+		{"too many rows", "name,serialNumber\na,1\nb,2\nc,3\n", nil, "more than 2 rows"},
+	}
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.data), deviceColumns, Options{Mapping: tt.mapping, MaxRows: 2})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	m := Template(deviceColumns)
+	if len(m.Columns) != len(deviceColumns)+1 || m.Columns["name"] != "name" || m.Columns["port"] != "port" {
+		t.Errorf("unexpected template %v", m.Columns)
+	}
+	// The template is a valid mapping for a file with the same header
+	data := "name,serialNumber,port,managed,tags,properties\nsw-1,SN1,1,true,a,{}\n"
+	if _, err := Read(strings.NewReader(data), deviceColumns, Options{Mapping: &m}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	rows := []Row{
+		{Line: 2, Name: "a", Body: map[string]interface{}{"name": "a"}},
+		{Line: 3, Name: "b", Errors: []string{"port: bad"}},
+		{Line: 4, Name: "c", Body: map[string]interface{}{"name": "c"}},
+	}
+	var created []string
+	create := func(_ context.Context, body []byte) (string, error) {
+		var req map[string]interface{}
+		json.Unmarshal(body, &req)
+		name := req["name"].(string)
+		if name == "c" {
+			return "", errors.New("name taken")
+		}
+		created = append(created, name)
+		return "dev-" + name, nil
+	}
+
+	result := Import(context.Background(), rows, create)
+	if result.Total != 3 || result.Succeeded != 1 || result.Failed != 2 {
+		t.Errorf("unexpected counts %+v", result)
+	}
+	if !reflect.DeepEqual(created, []string{"a"}) {
+		t.Errorf("rows with errors must not be created, created %v", created)
+	}
+	want := []RowResult{
+		{Line: 2, Name: "a", UID: "dev-a"},
+		{Line: 3, Name: "b", Errors: []string{"port: bad"}},
+		{Line: 4, Name: "c", Errors: []string{"name taken"}},
+	}
+	if !reflect.DeepEqual(result.Rows, want) {
+		t.Errorf("rows = %+v, want %+v", result.Rows, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	createDevice := func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["name"] == "taken" {
+			w.Header().Set("Content-Type", errcode.ContentType)
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(errcode.NewProblem(http.StatusConflict, errors.New("name is already used")))
+			return
+		}
+		if r.Header.Get("X-Tenant") != "t1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"metadata":{"uid":"dev-%s"}}`, req["name"])
+	}
+
+	importReq := httptest.NewRequest(http.MethodPost, "/devices/import", strings.NewReader("name\nx\n"))
+	importReq.Header.Set("Content-Type", ContentType)
+	importReq.Header.Set("X-Tenant", "t1")
+	create := Handler(importReq, createDevice)
+
+	if uid, err := create(context.Background(), []byte(`{"name":"sw-1"}`)); err != nil || uid != "dev-sw-1" {
+		t.Errorf("expected dev-sw-1, got %q, %v", uid, err)
+	}
+	if _, err := create(context.Background(), []byte(`{"name":"taken"}`)); err == nil || err.Error() != "name is already used" {
+		t.Errorf("expected the problem detail, got %v", err)
+	}
+
+	importReq.Header.Del("X-Tenant")
+	if _, err := Handler(importReq, createDevice)(context.Background(), []byte(`{"name":"sw-2"}`)); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected a status error, got %v", err)
+	}
+}
+
+func TestRequestRoundTrip(t *testing.T) {
+	const data = "Host,SN\nsw-1,SN1\n"
+	mapping := &Mapping{Columns: map[string]string{"Host": "name", "SN": "serialNumber"}}
+
+	for _, m := range []*Mapping{nil, mapping} {
+		body, contentType, err := NewRequestBody(strings.NewReader(data), m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/devices/import?dryRun=true", body)
+		r.Header.Set("Content-Type", contentType)
+
+		req, err := ParseRequest(r, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := io.ReadAll(req.CSV)
+		if string(got) != data || !req.DryRun || !reflect.DeepEqual(req.Mapping, m) {
+			t.Errorf("round trip with mapping %v: got %q, dryRun=%v, mapping=%v", m, got, req.DryRun, req.Mapping)
+		}
+	}
+}
+
+func TestParseRequest_Errors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/devices/import", strings.NewReader(strings.Repeat("x", 100)))
+	if _, err := ParseRequest(r, 10); err == nil {
+		t.Error("expected an oversized body to be rejected")
+	} else if code, _ := errcode.Of(err); code != errcode.PayloadTooLarge {
+		t.Errorf("expected PAYLOAD_TOO_LARGE, got %v", err)
+	}
+
+	body, contentType, _ := NewRequestBody(strings.NewReader("name\n"), &Mapping{})
+	data, _ := io.ReadAll(body)
+	r = httptest.NewRequest(http.MethodPost, "/devices/import", strings.NewReader(strings.Replace(string(data), `name="file"`, `name="other"`, 1)))
+	r.Header.Set("Content-Type", contentType)
+	if _, err := ParseRequest(r, 0); err == nil || !strings.Contains(err.Error(), `no "file" part`) {
+		t.Errorf("expected a missing file part, got %v", err)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/devices/import?dryRun=maybe", strings.NewReader("name\n"))
+	if _, err := ParseRequest(r, 0); err == nil {
+		t.Error("expected an invalid dryRun to be rejected")
+	}
+}
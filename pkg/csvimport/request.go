@@ -0,0 +1,154 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package csvimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+)
+
+// ContentType is the media type of a CSV request body
+const ContentType = "text/csv"
+
+// DefaultMaxBytes is the size limit of an import request body (32 MiB)
+const DefaultMaxBytes = 32 << 20
+
+// Multipart form fields of an import request
+const (
+	FileField    = "file"
+	MappingField = "mapping"
+)
+
+// Request is a parsed import request
+type Request struct {
+	// CSV is the uploaded file
+	CSV io.Reader
+
+	// Mapping is the column mapping, if one was sent
+	Mapping *Mapping
+
+	// DryRun is set by ?dryRun=true: rows are validated, nothing is created
+	DryRun bool
+}
+
+// ParseRequest reads an import request.
+//
+// The body is either the CSV file (Content-Type: text/csv), or a
+// multipart/form-data form with the CSV in a "file" part and a JSON Mapping
+// in an optional "mapping" part. The body is read into memory, up to
+// maxBytes.
+//
+// Parameters:
+//   - r: The import request
+//   - maxBytes: Body size limit (DefaultMaxBytes if <= 0)
+//
+// Returns:
+//   - *Request: File, mapping and dry-run flag
+//   - error: Carries errcode.PayloadTooLarge or errcode.InvalidRequest
+func ParseRequest(r *http.Request, maxBytes int64) (*Request, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	req := &Request{}
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errcode.Wrap(errcode.InvalidRequest, fmt.Errorf("invalid dryRun %q", v))
+		}
+		req.DryRun = dryRun
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		return nil, errcode.Wrap(errcode.InvalidRequest, fmt.Errorf("failed to read request body: %w", err))
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, errcode.Wrap(errcode.PayloadTooLarge, fmt.Errorf("import is larger than %d bytes", maxBytes))
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		req.CSV = bytes.NewReader(data)
+		return req, nil
+	}
+
+	parts := multipart.NewReader(bytes.NewReader(data), params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errcode.Wrap(errcode.InvalidRequest, fmt.Errorf("invalid multipart body: %w", err))
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, errcode.Wrap(errcode.InvalidRequest, fmt.Errorf("failed to read part %q: %w", part.FormName(), err))
+		}
+		switch part.FormName() {
+		case FileField:
+			req.CSV = bytes.NewReader(content)
+		case MappingField:
+			var m Mapping
+			if err := json.Unmarshal(content, &m); err != nil {
+				return nil, errcode.Wrap(errcode.InvalidRequest, fmt.Errorf("invalid mapping: %w", err))
+			}
+			req.Mapping = &m
+		}
+	}
+	if req.CSV == nil {
+		return nil, errcode.Wrap(errcode.InvalidRequest, fmt.Errorf("multipart body has no %q part", FileField))
+	}
+	return req, nil
+}
+
+// NewRequestBody encodes a CSV file and a mapping for ParseRequest.
+//
+// Without a mapping, the CSV is sent as a text/csv body. With one, both are
+// sent as a multipart/form-data form.
+//
+// Parameters:
+//   - csv: The CSV file
+//   - mapping: Column mapping (optional)
+//
+// Returns:
+//   - io.Reader: Request body
+//   - string: Content-Type of the body
+//   - error: If the file can't be read
+func NewRequestBody(csv io.Reader, mapping *Mapping) (io.Reader, string, error) {
+	if mapping == nil {
+		return csv, ContentType, nil
+	}
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	file, err := form.CreateFormFile(FileField, "import.csv")
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(file, csv); err != nil {
+		return nil, "", fmt.Errorf("failed to read CSV: %w", err)
+	}
+	data, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := form.WriteField(MappingField, string(data)); err != nil {
+		return nil, "", err
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return &buf, form.FormDataContentType(), nil
+}
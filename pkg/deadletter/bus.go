@@ -0,0 +1,187 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// Bus is an event bus whose handlers are retried, and whose events are
+// dead-lettered when a handler fails every attempt (see Queue.WrapBus).
+type Bus struct {
+	events.EventBus
+	queue *Queue
+
+	mu   sync.RWMutex
+	subs map[events.SubscriptionID]subscription
+
+	// stop ends the backoff of handlers being retried when the bus shuts
+	// down; their events are dead-lettered right away
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// subscription is a handler subscribed through a Bus
+type subscription struct {
+	pattern string
+	handler events.EventHandler
+}
+
+// WrapBus returns bus with its handlers retried: a handler that returns an
+// error is called again after a backoff, up to MaxAttempts times, and the
+// event is added to the queue if every attempt fails. Handlers receive
+// events more than once, so they should be idempotent.
+//
+// Re-driving an event letter calls the handlers currently subscribed with
+// the pattern of the failed one, once, and fails if none is.
+//
+// Example:
+//
+//	bus := queue.WrapBus(events.NewInMemoryEventBus(1000, 10))
+//	events.SetGlobalEventBus(bus)
+func (q *Queue) WrapBus(bus events.EventBus) *Bus {
+	b := &Bus{
+		EventBus: bus,
+		queue:    q,
+		subs:     make(map[events.SubscriptionID]subscription),
+		stop:     make(chan struct{}),
+	}
+	q.SetRedriver(SourceEvent, b.redrive)
+	return b
+}
+
+// FromBus returns the queue of a bus returned by WrapBus, or nil for other
+// buses.
+func FromBus(bus events.EventBus) *Queue {
+	if b, ok := bus.(*Bus); ok {
+		return b.queue
+	}
+	return nil
+}
+
+// Queue returns the dead-letter queue of the bus.
+func (b *Bus) Queue() *Queue {
+	return b.queue
+}
+
+// Subscribe implements events.EventBus.Subscribe, retrying the handler.
+func (b *Bus) Subscribe(eventType string, handler events.EventHandler) (events.SubscriptionID, error) {
+	id, err := b.EventBus.Subscribe(eventType, b.retrying(eventType, handler))
+	if err != nil {
+		return id, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[id] = subscription{pattern: eventType, handler: handler}
+	return id, nil
+}
+
+// Unsubscribe implements events.EventBus.Unsubscribe.
+func (b *Bus) Unsubscribe(id events.SubscriptionID) error {
+	b.mu.Lock()
+	delete(b.subs, id)
+	b.mu.Unlock()
+	return b.EventBus.Unsubscribe(id)
+}
+
+// Close implements events.EventBus.Close. Events whose handlers wait to be
+// retried are dead-lettered.
+func (b *Bus) Close() error {
+	b.stopRetries()
+	return b.EventBus.Close()
+}
+
+// Shutdown implements events.Shutdowner: queued events are delivered as the
+// wrapped bus does (see events.ShutdownBus), and events whose handlers wait
+// to be retried are dead-lettered so they can be re-driven after a restart.
+func (b *Bus) Shutdown(ctx context.Context) error {
+	b.stopRetries()
+	return events.ShutdownBus(ctx, b.EventBus)
+}
+
+// stopRetries ends the backoffs of retried handlers
+func (b *Bus) stopRetries() {
+	b.stopOnce.Do(func() { close(b.stop) })
+}
+
+// retrying wraps a handler subscribed with pattern with retries, adding
+// the events it fails to the queue
+func (b *Bus) retrying(pattern string, handler events.EventHandler) events.EventHandler {
+	return func(ctx context.Context, event events.Event) error {
+		attempt := 1
+		err := handler(ctx, event)
+		for err != nil && attempt < b.queue.opts.MaxAttempts {
+			select {
+			case <-time.After(b.queue.backoff(attempt)):
+			case <-b.stop:
+				err = fmt.Errorf("%w (abandoned at shutdown)", err)
+				b.deadLetter(pattern, event, attempt, err)
+				return err
+			}
+			attempt++
+			err = handler(ctx, event)
+		}
+		if err != nil {
+			b.deadLetter(pattern, event, attempt, err)
+		}
+		return err
+	}
+}
+
+// deadLetter adds an event a handler failed to the queue
+func (b *Bus) deadLetter(pattern string, event events.Event, attempts int, err error) {
+	data, encodeErr := events.EncodeEvent(event)
+	if encodeErr != nil {
+		b.queue.opts.Logger.Error("event not dead-lettered", "event_id", event.ID(), "error", encodeErr)
+		return
+	}
+	if _, addErr := b.queue.Add(context.Background(), DeadLetterSpec{
+		Source:       SourceEvent,
+		Subscription: pattern,
+		EventType:    event.Type(),
+		Event:        data,
+		ResourceKind: event.ResourceKind(),
+		ResourceUID:  event.ResourceUID(),
+		Error:        err.Error(),
+		Attempts:     attempts,
+	}); addErr != nil {
+		b.queue.opts.Logger.Error("event not dead-lettered", "event_id", event.ID(), "error", addErr)
+	}
+}
+
+// redrive delivers the event of a letter to the handlers subscribed with
+// the pattern of the failed one
+func (b *Bus) redrive(ctx context.Context, letter *DeadLetter) error {
+	event, err := events.DecodeEvent(letter.Spec.Event)
+	if err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	var handlers []events.EventHandler
+	for _, sub := range b.subs {
+		if sub.pattern == letter.Spec.Subscription {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.RUnlock()
+	if len(handlers) == 0 {
+		return fmt.Errorf("no handler subscribed to %s", letter.Spec.Subscription)
+	}
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
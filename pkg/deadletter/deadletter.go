@@ -0,0 +1,378 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package deadletter keeps the work event handlers and reconcilers gave up
+// on, so it can be inspected and re-driven instead of being lost.
+//
+// A Queue stores DeadLetters. Wrapping the event bus with Queue.WrapBus
+// retries failed event handlers with exponential backoff and adds the
+// events whose handlers fail every attempt; Queue.Reconciles limits the
+// attempts of a reconcile controller the same way and adds the requests it
+// gives up on:
+//
+//	queue := deadletter.NewQueue(backend, deadletter.Options{MaxAttempts: 5})
+//	bus := queue.WrapBus(events.NewInMemoryEventBus(1000, 10))
+//
+//	controller := reconcile.NewController(bus, backend)
+//	queue.Reconciles(controller)
+//
+//	for _, letter := range queue.List() {
+//	    err := queue.Redrive(ctx, letter.GetUID())
+//	}
+//
+// Re-driving a letter delivers its event again to the handlers subscribed
+// with the pattern of the failed one, or requeues its reconcile request.
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/logging"
+	"github.com/openchami/fabrica/pkg/reconcile"
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Kind is the resource kind used for dead letters.
+const Kind = "DeadLetter"
+
+// Sources of dead letters
+const (
+	// SourceEvent marks events whose handler failed every attempt
+	SourceEvent = "event"
+
+	// SourceReconcile marks reconcile requests a controller gave up on
+	SourceReconcile = "reconcile"
+)
+
+// Defaults of Options
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+)
+
+// Options configure a Queue. Zero values use the defaults.
+type Options struct {
+	MaxAttempts    int           // Attempts per event or reconcile request, including the first (default: 5)
+	InitialBackoff time.Duration // Wait before retrying a failed event handler, doubled for each next retry (default: 1s)
+	MaxBackoff     time.Duration // Longest wait between event handler retries (default: 1m)
+	Logger         *slog.Logger  // Reports letters added and failures to store them (default: the events logger)
+}
+
+// DeadLetterSpec describes the failed work.
+//
+//nolint:revive // "DeadLetterSpec" name is intentional; matches generated <Kind>Spec naming
+type DeadLetterSpec struct {
+	// Source is SourceEvent or SourceReconcile
+	Source string `json:"source" yaml:"source"`
+
+	// Subscription is the pattern the failed event handler subscribed with
+	Subscription string `json:"subscription,omitempty" yaml:"subscription,omitempty"`
+
+	// EventType is the type of the failed event
+	EventType string `json:"eventType,omitempty" yaml:"eventType,omitempty"`
+
+	// Event is the failed event as a structured CloudEvent
+	Event json.RawMessage `json:"event,omitempty" yaml:"event,omitempty"`
+
+	// ResourceKind and ResourceUID identify the resource of a reconcile
+	// request, or of the event when it has one
+	ResourceKind string `json:"resourceKind,omitempty" yaml:"resourceKind,omitempty"`
+	ResourceUID  string `json:"resourceUid,omitempty" yaml:"resourceUid,omitempty"`
+
+	// Error is the error of the last attempt
+	Error string `json:"error" yaml:"error"`
+
+	// Attempts counts the failed attempts
+	Attempts int `json:"attempts" yaml:"attempts"`
+
+	// FailedAt is when the last attempt failed
+	FailedAt time.Time `json:"failedAt" yaml:"failedAt"`
+}
+
+// DeadLetterStatus reports the re-drives of a dead letter.
+//
+//nolint:revive // "DeadLetterStatus" name is intentional; matches generated <Kind>Status naming
+type DeadLetterStatus struct {
+	// Redrives counts the failed re-drives; a successful one removes the letter
+	Redrives int `json:"redrives,omitempty" yaml:"redrives,omitempty"`
+
+	// LastRedriveAt is when the last re-drive failed
+	LastRedriveAt *time.Time `json:"lastRedriveAt,omitempty" yaml:"lastRedriveAt,omitempty"`
+
+	// LastRedriveError describes why the last re-drive failed
+	LastRedriveError string `json:"lastRedriveError,omitempty" yaml:"lastRedriveError,omitempty"`
+}
+
+// DeadLetter is an event or reconcile request given up on.
+type DeadLetter struct {
+	resource.Resource
+	Spec   DeadLetterSpec   `json:"spec" yaml:"spec"`
+	Status DeadLetterStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Redriver delivers the work of a dead letter again. The letter is removed
+// when it returns nil.
+type Redriver func(ctx context.Context, letter *DeadLetter) error
+
+// Queue stores dead letters and re-drives them.
+//
+// Letters are kept in memory and, when a storage backend is supplied,
+// written through to the backend under the "DeadLetter" kind so they
+// survive restarts.
+type Queue struct {
+	mu        sync.RWMutex
+	letters   map[string]*DeadLetter
+	backend   storage.StorageBackend
+	opts      Options
+	redrivers map[string]Redriver
+	now       func() time.Time
+}
+
+// NewQueue creates a dead-letter queue.
+//
+// Parameters:
+//   - backend: Optional storage backend for persistence; nil keeps letters in memory only
+//   - opts: Attempts and backoff of retries
+//
+// Returns:
+//   - *Queue: A queue with any letters already persisted in backend loaded
+func NewQueue(backend storage.StorageBackend, opts Options) *Queue {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.Logger == nil {
+		opts.Logger = logging.Component(slog.Default(), logging.ComponentEvents)
+	}
+
+	q := &Queue{
+		letters:   make(map[string]*DeadLetter),
+		backend:   backend,
+		opts:      opts,
+		redrivers: make(map[string]Redriver),
+		now:       time.Now,
+	}
+	if backend != nil {
+		if raw, err := backend.LoadAll(context.Background(), Kind); err == nil {
+			for _, data := range raw {
+				var letter DeadLetter
+				if err := json.Unmarshal(data, &letter); err == nil && letter.GetUID() != "" {
+					q.letters[letter.GetUID()] = &letter
+				}
+			}
+		}
+	}
+	return q
+}
+
+// MaxAttempts returns the attempts of event handlers and reconcile requests
+// before they are dead-lettered.
+func (q *Queue) MaxAttempts() int {
+	return q.opts.MaxAttempts
+}
+
+// Add stores a dead letter. FailedAt defaults to now.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - spec: The failed work
+//
+// Returns:
+//   - *DeadLetter: The stored letter
+//   - error: An error if the letter can't be saved
+func (q *Queue) Add(ctx context.Context, spec DeadLetterSpec) (*DeadLetter, error) {
+	if spec.FailedAt.IsZero() {
+		spec.FailedAt = q.now()
+	}
+	uid, err := resource.GenerateUID("dlq")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate dead letter UID: %w", err)
+	}
+
+	letter := &DeadLetter{Spec: spec}
+	letter.APIVersion = "v1"
+	letter.Kind = Kind
+	letter.Metadata.Initialize(uid, uid)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.persist(ctx, letter); err != nil {
+		return nil, err
+	}
+	q.letters[uid] = letter
+	q.opts.Logger.Warn("dead letter added", "uid", uid, "source", spec.Source,
+		"event_type", spec.EventType, "kind", spec.ResourceKind, "resource_uid", spec.ResourceUID,
+		"attempts", spec.Attempts, "error", spec.Error)
+	c := *letter
+	return &c, nil
+}
+
+// Get returns the dead letter with the given UID.
+func (q *Queue) Get(uid string) (*DeadLetter, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	letter, ok := q.letters[uid]
+	if !ok {
+		return nil, false
+	}
+	c := *letter
+	return &c, true
+}
+
+// List returns all dead letters, oldest failure first.
+func (q *Queue) List() []*DeadLetter {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	result := make([]*DeadLetter, 0, len(q.letters))
+	for _, letter := range q.letters {
+		c := *letter
+		result = append(result, &c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Spec.FailedAt.Equal(result[j].Spec.FailedAt) {
+			return result[i].Spec.FailedAt.Before(result[j].Spec.FailedAt)
+		}
+		return result[i].GetUID() < result[j].GetUID()
+	})
+	return result
+}
+
+// Delete removes a dead letter without re-driving it.
+//
+// Returns storage.ErrNotFound if the letter doesn't exist.
+func (q *Queue) Delete(ctx context.Context, uid string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.letters[uid]; !ok {
+		return storage.ErrNotFound
+	}
+	if q.backend != nil {
+		if err := q.backend.Delete(ctx, Kind, uid); err != nil {
+			return fmt.Errorf("failed to delete dead letter: %w", err)
+		}
+	}
+	delete(q.letters, uid)
+	return nil
+}
+
+// SetRedriver sets how letters of a source are re-driven. WrapBus and
+// Reconciles set the redrivers of SourceEvent and SourceReconcile.
+func (q *Queue) SetRedriver(source string, redriver Redriver) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.redrivers[source] = redriver
+}
+
+// Redrive delivers the work of a dead letter again and removes the letter
+// if that succeeds. A failed re-drive is recorded in the letter's status,
+// which is kept.
+//
+// Returns storage.ErrNotFound if the letter doesn't exist.
+func (q *Queue) Redrive(ctx context.Context, uid string) error {
+	q.mu.RLock()
+	letter, ok := q.letters[uid]
+	var redriver Redriver
+	if ok {
+		redriver = q.redrivers[letter.Spec.Source]
+	}
+	q.mu.RUnlock()
+	if !ok {
+		return storage.ErrNotFound
+	}
+
+	var err error
+	if redriver == nil {
+		err = fmt.Errorf("no redriver for %s dead letters", letter.Spec.Source)
+	} else {
+		c := *letter
+		err = redriver(ctx, &c)
+	}
+	if err == nil {
+		if err := q.Delete(ctx, uid); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if current, ok := q.letters[uid]; ok {
+		updated := *current
+		now := q.now()
+		updated.Status.Redrives++
+		updated.Status.LastRedriveAt = &now
+		updated.Status.LastRedriveError = err.Error()
+		updated.Touch()
+		_ = q.persist(ctx, &updated)
+		q.letters[uid] = &updated
+	}
+	return fmt.Errorf("failed to redrive dead letter %s: %w", uid, err)
+}
+
+// Reconciles limits the attempts of a controller's failing reconciliations
+// to MaxAttempts, adds the requests it gives up on to the queue, and
+// re-drives them by enqueueing them again in the controller. Call it again
+// for a new controller, e.g. when a replica becomes the leader.
+func (q *Queue) Reconciles(controller *reconcile.Controller) {
+	controller.SetMaxAttempts(q.opts.MaxAttempts, func(request reconcile.ReconcileRequest, attempts int, err error) {
+		_, _ = q.Add(context.Background(), DeadLetterSpec{
+			Source:       SourceReconcile,
+			ResourceKind: request.ResourceKind,
+			ResourceUID:  request.ResourceUID,
+			Error:        err.Error(),
+			Attempts:     attempts,
+		})
+	})
+	q.SetRedriver(SourceReconcile, func(_ context.Context, letter *DeadLetter) error {
+		return controller.Enqueue(reconcile.ReconcileRequest{
+			ResourceKind: letter.Spec.ResourceKind,
+			ResourceUID:  letter.Spec.ResourceUID,
+			Reason:       "Redrive: dead letter " + letter.GetUID(),
+		})
+	})
+}
+
+// backoff returns the wait after a failed attempt
+func (q *Queue) backoff(attempt int) time.Duration {
+	backoff := q.opts.InitialBackoff
+	for i := 1; i < attempt && backoff < q.opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > q.opts.MaxBackoff {
+		backoff = q.opts.MaxBackoff
+	}
+	return backoff
+}
+
+// persist writes a letter to the backend if one is configured. Callers
+// hold q.mu.
+func (q *Queue) persist(ctx context.Context, letter *DeadLetter) error {
+	if q.backend == nil {
+		return nil
+	}
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	if err := q.backend.Save(ctx, Kind, letter.GetUID(), data); err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	return nil
+}
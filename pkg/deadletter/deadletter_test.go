@@ -0,0 +1,224 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/reconcile"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// waitFor polls cond until it holds or a second passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newEvent(t *testing.T) events.Event {
+	t.Helper()
+	previous := events.GetEventConfig()
+	events.SetEventConfig(&events.EventConfig{Enabled: true, EventTypePrefix: "io.test", LifecycleEventsEnabled: true})
+	t.Cleanup(func() { events.SetEventConfig(previous) })
+	event, err := events.NewResourceEvent("created", "Device", "dev-1", map[string]string{"name": "d1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *event
+}
+
+func TestBus_RetriesAndRedrives(t *testing.T) {
+	backend := storage.NewMemoryBackend()
+	queue := NewQueue(backend, Options{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+	inner := events.NewInMemoryEventBus(10, 1)
+	inner.Start()
+	bus := queue.WrapBus(inner)
+	defer bus.Close() //nolint:errcheck
+
+	if FromBus(bus) != queue || FromBus(inner) != nil {
+		t.Fatal("FromBus didn't return the queue of the wrapped bus only")
+	}
+
+	// The flaky handler succeeds on its second attempt, the broken one never
+	var flaky, broken atomic.Int32
+	var healed atomic.Bool
+	if _, err := bus.Subscribe("io.test.**", func(context.Context, events.Event) error {
+		if flaky.Add(1) == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bus.Subscribe("io.test.device.*", func(context.Context, events.Event) error {
+		broken.Add(1)
+		if healed.Load() {
+			return nil
+		}
+		return errors.New("inventory unavailable")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bus.Publish(context.Background(), newEvent(t)); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "a dead letter", func() bool { return len(queue.List()) == 1 })
+
+	letter := queue.List()[0]
+	if letter.Spec.Source != SourceEvent || letter.Spec.Subscription != "io.test.device.*" ||
+		letter.Spec.EventType != "io.test.device.created" || letter.Spec.ResourceUID != "dev-1" ||
+		letter.Spec.Attempts != 3 || letter.Spec.Error != "inventory unavailable" {
+		t.Errorf("Unexpected dead letter %+v", letter.Spec)
+	}
+	if flaky.Load() != 2 || broken.Load() != 3 {
+		t.Errorf("Expected 2 and 3 attempts, got %d and %d", flaky.Load(), broken.Load())
+	}
+
+	// Letters are persisted
+	if reloaded, ok := NewQueue(backend, Options{}).Get(letter.GetUID()); !ok || reloaded.Spec.Event == nil {
+		t.Error("Dead letter wasn't persisted")
+	}
+
+	// A failed re-drive keeps the letter and records the failure
+	if err := queue.Redrive(context.Background(), letter.GetUID()); err == nil {
+		t.Fatal("Redrive succeeded while the handler fails")
+	}
+	if kept, _ := queue.Get(letter.GetUID()); kept.Status.Redrives != 1 || kept.Status.LastRedriveError == "" {
+		t.Errorf("Failed re-drive wasn't recorded: %+v", kept.Status)
+	}
+
+	// Only the failed handler receives the re-driven event
+	healed.Store(true)
+	if err := queue.Redrive(context.Background(), letter.GetUID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := queue.Get(letter.GetUID()); ok {
+		t.Error("Re-driven letter wasn't removed")
+	}
+	if flaky.Load() != 2 || broken.Load() != 5 {
+		t.Errorf("Expected 2 and 5 attempts, got %d and %d", flaky.Load(), broken.Load())
+	}
+	if err := queue.Redrive(context.Background(), letter.GetUID()); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Redrive of a removed letter = %v", err)
+	}
+}
+
+func TestBus_ShutdownDeadLetters(t *testing.T) {
+	queue := NewQueue(nil, Options{MaxAttempts: 5, InitialBackoff: time.Hour})
+	inner := events.NewInMemoryEventBus(10, 1)
+	inner.Start()
+	bus := queue.WrapBus(inner)
+
+	var calls atomic.Int32
+	if _, err := bus.Subscribe("**", func(context.Context, events.Event) error {
+		calls.Add(1)
+		return errors.New("failed")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(context.Background(), newEvent(t)); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the first attempt", func() bool { return calls.Load() == 1 })
+
+	// The handler waits an hour to be retried; shutting down ends the wait
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := events.ShutdownBus(ctx, bus); err != nil {
+		t.Fatal(err)
+	}
+	letters := queue.List()
+	if len(letters) != 1 || letters[0].Spec.Attempts != 1 {
+		t.Fatalf("Expected the event to be dead-lettered at shutdown, got %v", letters)
+	}
+}
+
+// failingReconciler fails until fixed
+type failingReconciler struct {
+	reconcile.BaseReconciler
+	mu    sync.Mutex
+	calls int
+	fixed bool
+}
+
+func (r *failingReconciler) GetResourceKind() string { return "Device" }
+
+func (r *failingReconciler) Reconcile(context.Context, interface{}) (reconcile.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.fixed {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{}, errors.New("BMC unreachable")
+}
+
+func (r *failingReconciler) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func TestQueue_Reconciles(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemoryBackend()
+	data, _ := json.Marshal(map[string]interface{}{"kind": "Device", "metadata": map[string]string{"uid": "dev-1"}})
+	if err := backend.Save(ctx, "Device", "dev-1", data); err != nil {
+		t.Fatal(err)
+	}
+
+	queue := NewQueue(nil, Options{MaxAttempts: 1})
+	inner := events.NewInMemoryEventBus(10, 1)
+	inner.Start()
+	bus := queue.WrapBus(inner)
+	defer bus.Close() //nolint:errcheck
+
+	reconciler := &failingReconciler{}
+	controller := reconcile.NewController(bus, backend)
+	if err := controller.RegisterReconciler(reconciler); err != nil {
+		t.Fatal(err)
+	}
+	queue.Reconciles(controller)
+	if err := controller.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer controller.Stop() //nolint:errcheck
+
+	if err := controller.Enqueue(reconcile.ReconcileRequest{ResourceKind: "Device", ResourceUID: "dev-1"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "a dead letter", func() bool { return len(queue.List()) == 1 })
+	letter := queue.List()[0]
+	if letter.Spec.Source != SourceReconcile || letter.Spec.ResourceKind != "Device" ||
+		letter.Spec.ResourceUID != "dev-1" || letter.Spec.Error != "BMC unreachable" {
+		t.Errorf("Unexpected dead letter %+v", letter.Spec)
+	}
+
+	// Re-driving requeues the request
+	reconciler.mu.Lock()
+	reconciler.fixed = true
+	reconciler.mu.Unlock()
+	if err := queue.Redrive(ctx, letter.GetUID()); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the re-driven reconciliation", func() bool { return reconciler.callCount() == 2 })
+	if len(queue.List()) != 0 {
+		t.Error("Re-driven letter wasn't removed")
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package errcode defines the stable, machine-readable error codes returned by
+// generated Fabrica APIs.
+//
+// Every error response from a generated server is an RFC 9457 problem document
+// (Content-Type: application/problem+json) with a "code" field drawn from this
+// catalog:
+//
+//	{
+//	  "type": "https://openchami.org/fabrica/errors/NAME_CONFLICT",
+//	  "title": "Name conflict",
+//	  "status": 409,
+//	  "detail": "Device name \"bmc-1\" is already used by dev-1a2b3c4d",
+//	  "code": "NAME_CONFLICT",
+//	  "error": "Device name \"bmc-1\" is already used by dev-1a2b3c4d"
+//	}
+//
+// Clients should branch on "code" rather than parsing "detail". Codes are never
+// renamed or removed once published; new codes may be added. The "error" field
+// repeats "detail" for clients written against earlier releases.
+//
+// Usage on the server side:
+//
+//	err := errcode.Wrap(errcode.NameConflict, fmt.Errorf("name %q is taken", name))
+//	respondError(w, http.StatusConflict, err) // code: NAME_CONFLICT
+//
+// Errors that aren't wrapped get the default code for their HTTP status (see ForStatus).
+package errcode
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code is a stable, machine-readable error code.
+type Code string
+
+// Error codes. The HTTP status each code is normally returned with is listed
+// in the catalog (see Lookup).
+const (
+	// InvalidRequest means the request is malformed (bad JSON, missing parameters)
+	InvalidRequest Code = "INVALID_REQUEST"
+	// InvalidQuery means a list filter, label selector or query expression is invalid
+	InvalidQuery Code = "INVALID_QUERY"
+	// ValidationFailed means the resource failed struct-tag or custom validation
+	ValidationFailed Code = "VALIDATION_FAILED"
+	// ChecksumMismatch means uploaded content doesn't match the checksum sent with it
+	ChecksumMismatch Code = "CHECKSUM_MISMATCH"
+	// PayloadTooLarge means the request body exceeds the configured size limit
+	PayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+	// PatchFailed means a well-formed patch could not be applied to the resource
+	PatchFailed Code = "PATCH_FAILED"
+	// BrokenReference means a reference field holds the UID of a resource that doesn't exist
+	BrokenReference Code = "BROKEN_REFERENCE"
+	// Unauthorized means the request lacks valid credentials
+	Unauthorized Code = "UNAUTHORIZED"
+	// Forbidden means the caller may not perform the operation
+	Forbidden Code = "FORBIDDEN"
+	// QuotaExceeded means creating the resource would exceed a quota
+	QuotaExceeded Code = "QUOTA_EXCEEDED"
+	// AdmissionDenied means an admission hook rejected the change
+	AdmissionDenied Code = "ADMISSION_DENIED"
+	// NotFound means the resource (or subresource) doesn't exist
+	NotFound Code = "NOT_FOUND"
+	// Conflict means the request conflicts with the current state of the resource
+	Conflict Code = "CONFLICT"
+	// NameConflict means the resource name is already used by another resource
+	NameConflict Code = "NAME_CONFLICT"
+	// AmbiguousName means a lookup by name matched more than one resource
+	AmbiguousName Code = "AMBIGUOUS_NAME"
+	// LockConflict means the lock is held by another holder
+	LockConflict Code = "LOCK_CONFLICT"
+	// UnsupportedVersion means the requested API version is invalid or not served
+	UnsupportedVersion Code = "UNSUPPORTED_VERSION"
+	// PreconditionFailed means an If-Match/If-Unmodified-Since precondition didn't hold
+	PreconditionFailed Code = "PRECONDITION_FAILED"
+	// ResourceLocked means the resource is locked and the caller isn't the lock holder
+	ResourceLocked Code = "RESOURCE_LOCKED"
+	// Internal means an unexpected server error
+	Internal Code = "INTERNAL"
+	// StorageError means the storage backend failed
+	StorageError Code = "STORAGE_ERROR"
+	// NotImplemented means the server doesn't implement the operation yet
+	NotImplemented Code = "NOT_IMPLEMENTED"
+	// Timeout means the request didn't complete within the server's time limit
+	Timeout Code = "TIMEOUT"
+)
+
+// Entry describes one error code in the catalog.
+type Entry struct {
+	// Code is the machine-readable error code
+	Code Code `json:"code" yaml:"code"`
+
+	// Status is the HTTP status the code is returned with
+	Status int `json:"status" yaml:"status"`
+
+	// Title is a short, human-readable summary of the code
+	Title string `json:"title" yaml:"title"`
+}
+
+// catalog lists every code; the order is used for documentation.
+var catalog = []Entry{
+	{InvalidRequest, http.StatusBadRequest, "Invalid request"},
+	{InvalidQuery, http.StatusBadRequest, "Invalid query"},
+	{ValidationFailed, http.StatusBadRequest, "Validation failed"},
+	{ChecksumMismatch, http.StatusBadRequest, "Checksum mismatch"},
+	{Unauthorized, http.StatusUnauthorized, "Unauthorized"},
+	{Forbidden, http.StatusForbidden, "Forbidden"},
+	{QuotaExceeded, http.StatusForbidden, "Quota exceeded"},
+	{AdmissionDenied, http.StatusForbidden, "Admission denied"},
+	{NotFound, http.StatusNotFound, "Not found"},
+	{UnsupportedVersion, http.StatusNotAcceptable, "Unsupported API version"},
+	{Conflict, http.StatusConflict, "Conflict"},
+	{NameConflict, http.StatusConflict, "Name conflict"},
+	{AmbiguousName, http.StatusConflict, "Ambiguous name"},
+	{LockConflict, http.StatusConflict, "Lock conflict"},
+	{PreconditionFailed, http.StatusPreconditionFailed, "Precondition failed"},
+	{PayloadTooLarge, http.StatusRequestEntityTooLarge, "Payload too large"},
+	{PatchFailed, http.StatusUnprocessableEntity, "Patch failed"},
+	{BrokenReference, http.StatusUnprocessableEntity, "Broken reference"},
+	{ResourceLocked, http.StatusLocked, "Resource locked"},
+	{Internal, http.StatusInternalServerError, "Internal error"},
+	{StorageError, http.StatusInternalServerError, "Storage error"},
+	{NotImplemented, http.StatusNotImplemented, "Not implemented"},
+	{Timeout, http.StatusServiceUnavailable, "Request timed out"},
+}
+
+// Catalog returns every known error code in documentation order.
+func Catalog() []Entry {
+	entries := make([]Entry, len(catalog))
+	copy(entries, catalog)
+	return entries
+}
+
+// Lookup returns the catalog entry for a code.
+//
+// Returns:
+//   - Entry: The catalog entry
+//   - bool: false if the code isn't in the catalog
+func Lookup(code Code) (Entry, bool) {
+	for _, e := range catalog {
+		if e.Code == code {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Status returns the HTTP status for a code, or 500 for unknown codes.
+func Status(code Code) int {
+	if e, ok := Lookup(code); ok {
+		return e.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// ForStatus returns the default code for an HTTP status.
+// It is used for errors that don't carry a code of their own.
+//
+// Example:
+//
+//	errcode.ForStatus(http.StatusNotFound)  // NOT_FOUND
+//	errcode.ForStatus(http.StatusTeapot)    // INVALID_REQUEST (other 4xx)
+//	errcode.ForStatus(http.StatusBadGateway) // INTERNAL (other 5xx)
+func ForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return InvalidRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusNotAcceptable:
+		return UnsupportedVersion
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusPreconditionFailed:
+		return PreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return PatchFailed
+	case http.StatusLocked:
+		return ResourceLocked
+	case http.StatusNotImplemented:
+		return NotImplemented
+	}
+	if status >= 400 && status < 500 {
+		return InvalidRequest
+	}
+	return Internal
+}
+
+// Error is an error annotated with an error code.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+// Error returns the message of the wrapped error.
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap annotates err with a code. It returns nil if err is nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Of returns the code carried by err (or any error it wraps).
+//
+// Returns:
+//   - Code: The code from the outermost *Error in the chain
+//   - bool: false if err carries no code
+func Of(err error) (Code, bool) {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code, true
+	}
+	return "", false
+}
+
+// ContentType is the media type of problem documents.
+const ContentType = "application/problem+json"
+
+// TypeBase prefixes the code in a problem document's "type" URI.
+const TypeBase = "https://openchami.org/fabrica/errors/"
+
+// Problem is an RFC 9457 problem document with a Fabrica error code.
+type Problem struct {
+	// Type is a URI identifying the error code
+	Type string `json:"type"`
+
+	// Title is the catalog title of the code
+	Title string `json:"title"`
+
+	// Status is the HTTP status code
+	Status int `json:"status"`
+
+	// Detail describes this occurrence of the error
+	Detail string `json:"detail,omitempty"`
+
+	// Code is the machine-readable error code
+	Code Code `json:"code"`
+
+	// Error repeats Detail for clients that predate problem documents
+	Error string `json:"error"`
+}
+
+// NewProblem builds a problem document for an error.
+//
+// The code comes from err when it carries one (see Wrap), otherwise from
+// ForStatus(status).
+//
+// Parameters:
+//   - status: HTTP status of the response
+//   - err: Error to describe
+//
+// Returns:
+//   - Problem: Problem document ready to encode as JSON
+func NewProblem(status int, err error) Problem {
+	code, ok := Of(err)
+	if !ok {
+		code = ForStatus(status)
+	}
+	title := http.StatusText(status)
+	if e, found := Lookup(code); found {
+		title = e.Title
+	}
+	detail := ""
+	if err != nil {
+		detail = err.Error()
+	}
+	return Problem{
+		Type:   TypeBase + string(code),
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Error:  detail,
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	seen := map[Code]bool{}
+	for _, e := range Catalog() {
+		if seen[e.Code] {
+			t.Errorf("Duplicate code %s", e.Code)
+		}
+		seen[e.Code] = true
+		if e.Status < 400 || e.Title == "" {
+			t.Errorf("Incomplete catalog entry: %+v", e)
+		}
+		if Status(e.Code) != e.Status {
+			t.Errorf("Status(%s) = %d, want %d", e.Code, Status(e.Code), e.Status)
+		}
+	}
+
+	// Every default code must be documented
+	for _, status := range []int{400, 401, 403, 404, 406, 409, 412, 413, 418, 422, 423, 500, 501, 503} {
+		if _, ok := Lookup(ForStatus(status)); !ok {
+			t.Errorf("ForStatus(%d) = %s, which is not in the catalog", status, ForStatus(status))
+		}
+	}
+}
+
+func TestWrapAndOf(t *testing.T) {
+	if Wrap(NotFound, nil) != nil {
+		t.Error("Expected Wrap(nil) to return nil")
+	}
+
+	base := errors.New("boom")
+	err := fmt.Errorf("saving device: %w", Wrap(StorageError, base))
+	code, ok := Of(err)
+	if !ok || code != StorageError {
+		t.Errorf("Expected STORAGE_ERROR, got %q (%v)", code, ok)
+	}
+	if !errors.Is(err, base) {
+		t.Error("Expected wrapped error to unwrap to the original")
+	}
+	if _, ok := Of(base); ok {
+		t.Error("Expected no code for a plain error")
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	p := NewProblem(http.StatusConflict, Wrap(NameConflict, errors.New("name taken")))
+	if p.Code != NameConflict || p.Title != "Name conflict" || p.Status != 409 {
+		t.Errorf("Unexpected problem: %+v", p)
+	}
+	if p.Detail != "name taken" || p.Error != "name taken" {
+		t.Errorf("Expected detail to be the error message, got %+v", p)
+	}
+	if p.Type != TypeBase+"NAME_CONFLICT" {
+		t.Errorf("Unexpected type %q", p.Type)
+	}
+
+	p = NewProblem(http.StatusNotFound, errors.New("missing"))
+	if p.Code != NotFound {
+		t.Errorf("Expected default code NOT_FOUND, got %s", p.Code)
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package eventlog keeps a short, human-readable history of what happened to
+// each resource, in the spirit of the events shown by "kubectl describe".
+//
+// Generated handlers record an Event on every lifecycle transition (created,
+// spec changed, status changed, rolled back, deleted), and services can add
+// their own, e.g. a reconciler noting that a BMC was unreachable. Operators
+// read them back per resource to see why it is in its current state.
+//
+// Unlike pkg/events, which publishes CloudEvents to subscribers as they
+// happen, the event log is stored and queried later. Events expire after a
+// retention period (TTL) and at most a fixed number are kept per resource.
+// Repeats of the latest event (same type, reason, message and source) are
+// folded into it by raising its Count, so a condition that keeps recurring
+// doesn't push older events out.
+//
+// Event logs are stored through a storage.StorageBackend under the kind
+// "<Kind>Events", one document per resource UID. When no backend is supplied
+// they are kept in memory.
+//
+// Usage:
+//
+//	store := eventlog.NewStore(backend, 24*time.Hour, 100)
+//	err := store.Record(ctx, "Device", device.Metadata, eventlog.Event{
+//	    Type:    eventlog.TypeWarning,
+//	    Reason:  "Unreachable",
+//	    Message: "BMC did not answer within 5s",
+//	    Source:  "device-reconciler",
+//	})
+//
+//	evts, err := store.List(ctx, "Device", uid)
+//	removed, err := store.Prune(ctx, "Device") // drop expired events
+package eventlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Event types
+const (
+	// TypeNormal marks events of expected operation
+	TypeNormal = "Normal"
+	// TypeWarning marks events that may need attention
+	TypeWarning = "Warning"
+)
+
+// Reasons recorded by generated handlers
+const (
+	ReasonCreated       = "Created"
+	ReasonUpdated       = "Updated"
+	ReasonStatusUpdated = "StatusUpdated"
+	ReasonRolledBack    = "RolledBack"
+	ReasonDeleted       = "Deleted"
+	ReasonExpired       = "Expired"
+)
+
+// DefaultTTL is how long events are kept when no retention is configured.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultLimit is the number of events kept per resource when no limit is configured.
+const DefaultLimit = 100
+
+// Event is something that happened to a resource.
+type Event struct {
+	// Type is TypeNormal or TypeWarning
+	Type string `json:"type" yaml:"type"`
+
+	// Reason is a short CamelCase cause, e.g. "Created" or "Unreachable"
+	Reason string `json:"reason" yaml:"reason"`
+
+	// Message describes the event for humans
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Source is the component that reported the event, e.g. "api"
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Count is the number of times the event happened in a row
+	Count int `json:"count" yaml:"count"`
+
+	// FirstTimestamp and LastTimestamp are when the event first and last happened
+	FirstTimestamp time.Time `json:"firstTimestamp" yaml:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp" yaml:"lastTimestamp"`
+
+	// The resource the event is about
+	Kind string `json:"kind" yaml:"kind"`
+	UID  string `json:"uid" yaml:"uid"`
+	Name string `json:"name" yaml:"name"`
+}
+
+// log is the stored document holding a resource's events.
+type log struct {
+	UID    string  `json:"uid"`
+	Events []Event `json:"events"`
+}
+
+// Store records and retrieves event logs.
+type Store struct {
+	mu      sync.Mutex
+	backend storage.StorageBackend
+	ttl     time.Duration
+	limit   int
+	memory  map[string][]byte // used when backend is nil, keyed by kind/uid
+	now     func() time.Time
+}
+
+// NewStore creates an event log store.
+//
+// Parameters:
+//   - backend: Storage backend for event logs; nil keeps them in memory
+//   - ttl: How long events are kept after they last happened; values <= 0 use DefaultTTL
+//   - limit: Maximum events kept per resource; values <= 0 use DefaultLimit
+//
+// Returns:
+//   - *Store: A ready-to-use event log store
+func NewStore(backend storage.StorageBackend, ttl time.Duration, limit int) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Store{
+		backend: backend,
+		ttl:     ttl,
+		limit:   limit,
+		memory:  make(map[string][]byte),
+		now:     time.Now,
+	}
+}
+
+// TTL returns how long events are kept.
+func (s *Store) TTL() time.Duration {
+	return s.ttl
+}
+
+// Record adds an event to a resource's log.
+//
+// The kind, UID and name of the event are taken from kind and meta, and its
+// count and timestamps are set by the store. An event repeating the latest
+// one is folded into it. Expired events and events beyond the store limit
+// are discarded.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - kind: Resource kind (e.g., "Device")
+//   - meta: Resource metadata; the UID and name are recorded
+//   - event: The event; Type defaults to TypeNormal and Reason is required
+//
+// Returns:
+//   - error: Any error that occurred while recording
+func (s *Store) Record(ctx context.Context, kind string, meta resource.Metadata, event Event) error {
+	if event.Reason == "" {
+		return fmt.Errorf("event reason is required")
+	}
+	if event.Type == "" {
+		event.Type = TypeNormal
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.load(ctx, kind, meta.UID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	l.Events = s.unexpired(l.Events, now)
+	if n := len(l.Events); n > 0 && l.Events[n-1].repeatedBy(event) {
+		last := &l.Events[n-1]
+		last.Count++
+		last.LastTimestamp = now
+		last.Name = meta.Name
+	} else {
+		event.Kind, event.UID, event.Name = kind, meta.UID, meta.Name
+		event.Count = 1
+		event.FirstTimestamp, event.LastTimestamp = now, now
+		l.Events = append(l.Events, event)
+	}
+	if len(l.Events) > s.limit {
+		l.Events = l.Events[len(l.Events)-s.limit:]
+	}
+
+	return s.save(ctx, kind, l)
+}
+
+// List returns the unexpired events of a resource, oldest first.
+//
+// Returns an empty slice if the resource has no recorded events. Events of
+// deleted resources remain listed until they expire.
+func (s *Store) List(ctx context.Context, kind, uid string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.load(ctx, kind, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.unexpired(l.Events, s.now().UTC()), nil
+}
+
+// Delete removes the event log of a resource.
+//
+// Safe to call for resources without events.
+func (s *Store) Delete(ctx context.Context, kind, uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.backend == nil {
+		delete(s.memory, memoryKey(kind, uid))
+		return nil
+	}
+	if err := s.backend.Delete(ctx, logKind(kind), uid); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to delete event log: %w", err)
+	}
+	return nil
+}
+
+// Prune removes the expired events of every resource of a kind, and the
+// logs left empty, including those of deleted resources.
+//
+// Returns:
+//   - int: The number of events removed
+//   - error: Any error that occurred while pruning
+func (s *Store) Prune(ctx context.Context, kind string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var uids []string
+	if s.backend == nil {
+		prefix := memoryKey(kind, "")
+		for key := range s.memory {
+			if uid, ok := strings.CutPrefix(key, prefix); ok {
+				uids = append(uids, uid)
+			}
+		}
+		sort.Strings(uids)
+	} else {
+		listed, err := s.backend.List(ctx, logKind(kind))
+		if err != nil {
+			return 0, fmt.Errorf("failed to list event logs: %w", err)
+		}
+		uids = listed
+	}
+
+	now := s.now().UTC()
+	removed := 0
+	for _, uid := range uids {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		l, err := s.load(ctx, kind, uid)
+		if err != nil {
+			return removed, err
+		}
+		kept := s.unexpired(l.Events, now)
+		if len(kept) == len(l.Events) {
+			continue
+		}
+		removed += len(l.Events) - len(kept)
+
+		if len(kept) == 0 {
+			if s.backend == nil {
+				delete(s.memory, memoryKey(kind, uid))
+			} else if err := s.backend.Delete(ctx, logKind(kind), uid); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return removed, fmt.Errorf("failed to delete event log: %w", err)
+			}
+			continue
+		}
+		l.Events = kept
+		if err := s.save(ctx, kind, l); err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// ChangedFields returns the top-level JSON fields that differ between two
+// values, sorted, e.g. the spec fields an update changed. Handlers use it to
+// describe a change in an event message.
+//
+// Values that can't be encoded as JSON objects have no fields to compare, so
+// nil is returned for them.
+func ChangedFields(before, after interface{}) []string {
+	fieldsBefore, okBefore := topLevelFields(before)
+	fieldsAfter, okAfter := topLevelFields(after)
+	if !okBefore || !okAfter {
+		return nil
+	}
+
+	var changed []string
+	for name, value := range fieldsAfter {
+		if old, ok := fieldsBefore[name]; !ok || !bytes.Equal(old, value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range fieldsBefore {
+		if _, ok := fieldsAfter[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// topLevelFields encodes v and returns its fields, compacted
+func topLevelFields(v interface{}) (map[string][]byte, bool) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false
+	}
+	fields := make(map[string][]byte, len(raw))
+	for name, value := range raw {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, value); err != nil {
+			return nil, false
+		}
+		fields[name] = buf.Bytes()
+	}
+	return fields, true
+}
+
+// repeatedBy reports whether next is a repeat of e
+func (e Event) repeatedBy(next Event) bool {
+	return e.Type == next.Type && e.Reason == next.Reason && e.Message == next.Message && e.Source == next.Source
+}
+
+// unexpired returns the events that last happened within the TTL
+func (s *Store) unexpired(events []Event, now time.Time) []Event {
+	kept := make([]Event, 0, len(events))
+	for _, e := range events {
+		if now.Sub(e.LastTimestamp) < s.ttl {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// load reads an event log document. Callers hold s.mu.
+func (s *Store) load(ctx context.Context, kind, uid string) (*log, error) {
+	var data []byte
+	if s.backend == nil {
+		data = s.memory[memoryKey(kind, uid)]
+	} else {
+		raw, err := s.backend.Load(ctx, logKind(kind), uid)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load event log: %w", err)
+		}
+		data = raw
+	}
+
+	l := &log{UID: uid, Events: []Event{}}
+	if len(data) == 0 {
+		return l, nil
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event log: %w", err)
+	}
+	return l, nil
+}
+
+// save writes an event log document. Callers hold s.mu.
+func (s *Store) save(ctx context.Context, kind string, l *log) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event log: %w", err)
+	}
+	if s.backend == nil {
+		s.memory[memoryKey(kind, l.UID)] = data
+		return nil
+	}
+	if err := s.backend.Save(ctx, logKind(kind), l.UID, data); err != nil {
+		return fmt.Errorf("failed to save event log: %w", err)
+	}
+	return nil
+}
+
+// logKind returns the storage kind used for a resource kind's event logs.
+func logKind(kind string) string {
+	return kind + "Events"
+}
+
+func memoryKey(kind, uid string) string {
+	return kind + "/" + uid
+}
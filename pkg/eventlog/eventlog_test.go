@@ -0,0 +1,180 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package eventlog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+func testMetadata() resource.Metadata {
+	var meta resource.Metadata
+	meta.Initialize("device-1", "dev-12345678")
+	return meta
+}
+
+// newTestStore returns a store whose clock is advanced by the returned func
+func newTestStore(backend storage.StorageBackend, ttl time.Duration, limit int) (*Store, func(time.Duration)) {
+	s := NewStore(backend, ttl, limit)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestStore_RecordAndList(t *testing.T) {
+	ctx := context.Background()
+	s, advance := newTestStore(nil, 0, 0)
+	meta := testMetadata()
+
+	if err := s.Record(ctx, "Device", meta, Event{Reason: ReasonCreated}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	advance(time.Minute)
+	if err := s.Record(ctx, "Device", meta, Event{Type: TypeWarning, Reason: "Unreachable", Message: "BMC timeout", Source: "reconciler"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	events, err := s.List(ctx, "Device", meta.UID)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %+v", events)
+	}
+	first, second := events[0], events[1]
+	if first.Type != TypeNormal || first.Reason != ReasonCreated || first.Count != 1 {
+		t.Errorf("Unexpected first event: %+v", first)
+	}
+	if first.Kind != "Device" || first.UID != meta.UID || first.Name != "device-1" {
+		t.Errorf("Expected the involved resource to be recorded, got %+v", first)
+	}
+	if second.Type != TypeWarning || second.Source != "reconciler" || !second.FirstTimestamp.After(first.FirstTimestamp) {
+		t.Errorf("Unexpected second event: %+v", second)
+	}
+
+	if err := s.Record(ctx, "Device", meta, Event{Message: "no reason"}); err == nil {
+		t.Error("Expected an event without a reason to be rejected")
+	}
+}
+
+func TestStore_FoldsRepeats(t *testing.T) {
+	ctx := context.Background()
+	s, advance := newTestStore(nil, 0, 0)
+	meta := testMetadata()
+
+	warning := Event{Type: TypeWarning, Reason: "Unreachable", Message: "BMC timeout"}
+	for i := 0; i < 3; i++ {
+		if err := s.Record(ctx, "Device", meta, warning); err != nil {
+			t.Fatal(err)
+		}
+		advance(time.Minute)
+	}
+
+	events, _ := s.List(ctx, "Device", meta.UID)
+	if len(events) != 1 || events[0].Count != 3 {
+		t.Fatalf("Expected one event with count 3, got %+v", events)
+	}
+	if got := events[0].LastTimestamp.Sub(events[0].FirstTimestamp); got != 2*time.Minute {
+		t.Errorf("Expected 2m between the first and last occurrence, got %s", got)
+	}
+
+	// A different message starts a new event
+	warning.Message = "BMC refused the connection"
+	_ = s.Record(ctx, "Device", meta, warning)
+	if events, _ := s.List(ctx, "Device", meta.UID); len(events) != 2 {
+		t.Errorf("Expected a new event for a new message, got %+v", events)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	ctx := context.Background()
+	s, advance := newTestStore(nil, time.Hour, 3)
+	meta := testMetadata()
+
+	for _, reason := range []string{"A", "B", "C", "D"} {
+		_ = s.Record(ctx, "Device", meta, Event{Reason: reason})
+		advance(20 * time.Minute)
+	}
+	var reasons []string
+	events, _ := s.List(ctx, "Device", meta.UID)
+	for _, e := range events {
+		reasons = append(reasons, e.Reason)
+	}
+	// A fell to the limit; B expired an hour after it happened
+	if !reflect.DeepEqual(reasons, []string{"C", "D"}) {
+		t.Errorf("Expected events [C D], got %v", reasons)
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend failed: %v", err)
+	}
+	s, advance := newTestStore(backend, time.Hour, 0)
+
+	deleted := testMetadata()
+	live := testMetadata()
+	live.Initialize("device-2", "dev-87654321")
+
+	_ = s.Record(ctx, "Device", deleted, Event{Reason: ReasonDeleted})
+	_ = s.Record(ctx, "Device", live, Event{Reason: ReasonCreated})
+	advance(50 * time.Minute)
+	_ = s.Record(ctx, "Device", live, Event{Reason: ReasonUpdated})
+	advance(20 * time.Minute)
+
+	removed, err := s.Prune(ctx, "Device")
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 expired events removed, got %d", removed)
+	}
+	uids, _ := backend.List(ctx, "DeviceEvents")
+	if !reflect.DeepEqual(uids, []string{live.UID}) {
+		t.Errorf("Expected only the live log to remain, got %v", uids)
+	}
+	if events, _ := s.List(ctx, "Device", live.UID); len(events) != 1 || events[0].Reason != ReasonUpdated {
+		t.Errorf("Expected the recent event to be kept, got %+v", events)
+	}
+
+	// A fresh store reads the same logs from the backend
+	fresh := NewStore(backend, time.Hour, 0)
+	fresh.now = s.now
+	if events, _ := fresh.List(ctx, "Device", live.UID); len(events) != 1 {
+		t.Errorf("Expected the log to be persisted, got %+v", events)
+	}
+	if err := s.Delete(ctx, "Device", live.UID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if events, _ := s.List(ctx, "Device", live.UID); len(events) != 0 {
+		t.Errorf("Expected no events after Delete, got %+v", events)
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	type spec struct {
+		Rack  string   `json:"rack"`
+		Model string   `json:"model,omitempty"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+	before := spec{Rack: "r1", Model: "x", Tags: []string{"a"}}
+	after := spec{Rack: "r2", Tags: []string{"a"}}
+	if got := ChangedFields(before, after); !reflect.DeepEqual(got, []string{"model", "rack"}) {
+		t.Errorf("Expected [model rack], got %v", got)
+	}
+	if got := ChangedFields(before, before); len(got) != 0 {
+		t.Errorf("Expected no changes, got %v", got)
+	}
+	if got := ChangedFields("a", "b"); got != nil {
+		t.Errorf("Expected nil for non-objects, got %v", got)
+	}
+}
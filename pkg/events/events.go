@@ -16,6 +16,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -46,6 +47,32 @@ type EventConfig struct {
 	// Source sets the default source identifier for events
 	// Example: "fabrica-api" or "inventory-system"
 	Source string `json:"source" yaml:"source"`
+
+	// DataFormat selects the data of resource lifecycle events:
+	// DataFormatEnvelope (the default when empty) or DataFormatResource
+	DataFormat string `json:"dataFormat,omitempty" yaml:"dataFormat,omitempty"`
+}
+
+// Data formats of resource lifecycle events (see EventConfig.DataFormat)
+const (
+	// DataFormatEnvelope makes the data a ResourceChangeData: the action,
+	// kind, UID, name and change metadata, with the resource
+	DataFormatEnvelope = "envelope"
+
+	// DataFormatResource makes the data the resource itself, as the API
+	// returns it, so consumers need no Fabrica-specific schema. The change
+	// metadata is carried in extension attributes.
+	DataFormatResource = "resource"
+)
+
+// ValidDataFormat reports whether format is a data format of resource
+// lifecycle events, or empty for the default.
+func ValidDataFormat(format string) bool {
+	switch format {
+	case "", DataFormatEnvelope, DataFormatResource:
+		return true
+	}
+	return false
 }
 
 // DefaultEventConfig returns sensible defaults for event configuration
@@ -57,6 +84,7 @@ func DefaultEventConfig() *EventConfig {
 		EventTypePrefix:        "io.fabrica",
 		ConditionEventPrefix:   "io.fabrica.condition",
 		Source:                 "fabrica-api",
+		DataFormat:             DataFormatEnvelope,
 	}
 }
 
@@ -84,6 +112,7 @@ func GetEventConfig() *EventConfig {
 		EventTypePrefix:        globalEventConfig.EventTypePrefix,
 		ConditionEventPrefix:   globalEventConfig.ConditionEventPrefix,
 		Source:                 globalEventConfig.Source,
+		DataFormat:             globalEventConfig.DataFormat,
 	}
 }
 
@@ -109,17 +138,19 @@ type Event struct {
 	cloudevents.Event
 }
 
-// NewEvent creates a CloudEvents-compliant event
+// NewEvent creates a CloudEvents 1.0 event with JSON data.
+// A nil data creates an event without data.
 func NewEvent(eventType, source string, data interface{}) (*Event, error) {
-	event := cloudevents.NewEvent()
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
 	event.SetID(generateEventID())
 	event.SetType(eventType)
 	event.SetSource(source)
 	event.SetTime(time.Now())
-	event.SetDataContentType("application/json")
 
-	if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
-		return nil, fmt.Errorf("failed to set event data: %w", err)
+	if data != nil {
+		if err := event.SetData(cloudevents.ApplicationJSON, data); err != nil {
+			return nil, fmt.Errorf("failed to set event data: %w", err)
+		}
 	}
 
 	return &Event{Event: event}, nil
@@ -172,6 +203,9 @@ func NewResourceEvent(action, resourceKind, resourceUID string, data interface{}
 		return nil, err
 	}
 
+	// The subject identifies the resource within the source
+	event.SetSubject(resourceUID)
+
 	// Add resource-specific extension attributes
 	event.SetExtension("resourcekind", resourceKind)
 	event.SetExtension("resourceuid", resourceUID)
@@ -252,7 +286,14 @@ type EventHandler func(ctx context.Context, event Event) error
 // SubscriptionID uniquely identifies a subscription
 type SubscriptionID string
 
-// EventBus manages event publishing and subscription
+// EventBus manages event publishing and subscription.
+//
+// Generated servers, reconcilers and middleware only use this interface, so
+// other transports (Redis Streams, NATS, Pub/Sub) plug in by implementing
+// it and registering with RegisterBus. Implementations must be safe for
+// concurrent use, deliver events to every subscription whose pattern
+// matches the event type (see the pattern rules of InMemoryEventBus), and
+// may implement Shutdowner to deliver queued events on shutdown.
 type EventBus interface {
 	// Publish a CloudEvent
 	Publish(ctx context.Context, event Event) error
@@ -289,6 +330,7 @@ func GetGlobalEventBus() EventBus {
 //
 // This is the main entry point for publishing resource events. It respects
 // the global event configuration and only publishes if events are enabled.
+// With a ctx from WithOutbox, the event is added to the outbox instead.
 //
 // Parameters:
 //   - ctx: Context for the publish operation
@@ -304,6 +346,12 @@ func GetGlobalEventBus() EventBus {
 //
 //	err := PublishResourceEvent(ctx, "created", "Device", device.GetUID(), device)
 func PublishResourceEvent(ctx context.Context, action, resourceKind, resourceUID string, data interface{}) error {
+	return publishResourceEvent(ctx, action, resourceKind, resourceUID, data, nil)
+}
+
+// publishResourceEvent publishes a resource event with extension attributes
+// for the change metadata (see setMetadataExtensions)
+func publishResourceEvent(ctx context.Context, action, resourceKind, resourceUID string, data interface{}, metadata map[string]interface{}) error {
 	// Check specific event type enablement first
 	lifecycleActions := map[string]bool{
 		"created": true, "create": true,
@@ -322,8 +370,9 @@ func PublishResourceEvent(ctx context.Context, action, resourceKind, resourceUID
 		return nil
 	}
 
+	outbox := outboxFromContext(ctx)
 	bus := GetGlobalEventBus()
-	if bus == nil {
+	if outbox == nil && bus == nil {
 		return fmt.Errorf("no event bus configured")
 	}
 
@@ -331,7 +380,12 @@ func PublishResourceEvent(ctx context.Context, action, resourceKind, resourceUID
 	if err != nil {
 		return fmt.Errorf("failed to create resource event: %w", err)
 	}
+	setMetadataExtensions(event, metadata)
 
+	if outbox != nil {
+		// Published by the relay of the outbox once the change is committed
+		return outbox.Add(ctx, *event)
+	}
 	return bus.Publish(ctx, *event)
 }
 
@@ -424,47 +478,62 @@ type ResourceChangeData struct {
 	Resource interface{} `json:"resource,omitempty"`
 }
 
-// PublishResourceCreated publishes a "created" event for a resource
-func PublishResourceCreated(ctx context.Context, resourceKind, resourceUID, resourceName string, resource interface{}) error {
+// PublishResourceChange publishes a lifecycle event for a resource, with
+// the data of the configured EventConfig.DataFormat:
+//   - DataFormatEnvelope: a ResourceChangeData holding the resource and
+//     metadata
+//   - DataFormatResource: the resource itself, and the metadata as extension
+//     attributes (e.g. "updatedAt" as "updatedat"); a nil resource publishes
+//     an event without data
+//
+// Parameters:
+//   - ctx: Context for the publish operation
+//   - action: The action that occurred (e.g., "created", "updated", "deleted")
+//   - resourceKind: Kind of resource (e.g., "Device")
+//   - resourceUID: Unique identifier of the resource
+//   - resourceName: Name of the resource, or ""
+//   - resource: The resource after the change, or before a deletion (optional)
+//   - metadata: Context of the change (optional)
+//
+// Returns:
+//   - error: If event creation or publishing fails
+//
+// Example:
+//
+//	err := PublishResourceChange(ctx, "deleted", "Device", device.GetUID(), device.GetName(), device, nil)
+//	// With DataFormatResource, publishes a "io.fabrica.device.deleted"
+//	// event whose data is the deleted device
+func PublishResourceChange(ctx context.Context, action, resourceKind, resourceUID, resourceName string, resource interface{}, metadata map[string]interface{}) error {
+	if GetEventConfig().DataFormat == DataFormatResource {
+		return publishResourceEvent(ctx, action, resourceKind, resourceUID, resource, metadata)
+	}
+
 	data := ResourceChangeData{
-		Action:       "created",
+		Action:       action,
 		ResourceKind: resourceKind,
 		ResourceUID:  resourceUID,
 		ResourceName: resourceName,
 		ChangeTime:   time.Now(),
 		Resource:     resource,
+		Metadata:     metadata,
 	}
+	return PublishResourceEvent(ctx, action, resourceKind, resourceUID, data)
+}
 
-	return PublishResourceEvent(ctx, "created", resourceKind, resourceUID, data)
+// PublishResourceCreated publishes a "created" event for a resource
+func PublishResourceCreated(ctx context.Context, resourceKind, resourceUID, resourceName string, resource interface{}) error {
+	return PublishResourceChange(ctx, "created", resourceKind, resourceUID, resourceName, resource, nil)
 }
 
 // PublishResourceUpdated publishes an "updated" event for a resource
 func PublishResourceUpdated(ctx context.Context, resourceKind, resourceUID, resourceName string, resource interface{}, metadata map[string]interface{}) error {
-	data := ResourceChangeData{
-		Action:       "updated",
-		ResourceKind: resourceKind,
-		ResourceUID:  resourceUID,
-		ResourceName: resourceName,
-		ChangeTime:   time.Now(),
-		Resource:     resource,
-		Metadata:     metadata,
-	}
-
-	return PublishResourceEvent(ctx, "updated", resourceKind, resourceUID, data)
+	return PublishResourceChange(ctx, "updated", resourceKind, resourceUID, resourceName, resource, metadata)
 }
 
-// PublishResourceDeleted publishes a "deleted" event for a resource
+// PublishResourceDeleted publishes a "deleted" event for a resource, without
+// the resource (see PublishResourceChange to include it)
 func PublishResourceDeleted(ctx context.Context, resourceKind, resourceUID, resourceName string, metadata map[string]interface{}) error {
-	data := ResourceChangeData{
-		Action:       "deleted",
-		ResourceKind: resourceKind,
-		ResourceUID:  resourceUID,
-		ResourceName: resourceName,
-		ChangeTime:   time.Now(),
-		Metadata:     metadata,
-	}
-
-	return PublishResourceEvent(ctx, "deleted", resourceKind, resourceUID, data)
+	return PublishResourceChange(ctx, "deleted", resourceKind, resourceUID, resourceName, nil, metadata)
 }
 
 // PublishResourcePatched publishes a "patched" event for a resource (for partial updates)
@@ -472,18 +541,39 @@ func PublishResourcePatched(ctx context.Context, resourceKind, resourceUID, reso
 	metadata := map[string]interface{}{
 		"patchData": patchData,
 	}
-
-	data := ResourceChangeData{
-		Action:       "patched",
-		ResourceKind: resourceKind,
-		ResourceUID:  resourceUID,
-		ResourceName: resourceName,
-		ChangeTime:   time.Now(),
-		Resource:     resource,
-		Metadata:     metadata,
+	if GetEventConfig().DataFormat == DataFormatResource {
+		// Each entry becomes an extension attribute
+		metadata = patchData
 	}
 
-	return PublishResourceEvent(ctx, "patched", resourceKind, resourceUID, data)
+	return PublishResourceChange(ctx, "patched", resourceKind, resourceUID, resourceName, resource, metadata)
+}
+
+// resourceExtensions are the extension attributes of resource events, which
+// metadata can't override
+var resourceExtensions = map[string]bool{"resourcekind": true, "resourceuid": true, "action": true}
+
+// setMetadataExtensions sets change metadata on an event as extension
+// attributes named by the lowercased keys. Values CloudEvents attributes
+// can't hold, such as maps, are JSON-encoded. Keys that aren't valid
+// attribute names (letters and digits), or name a CloudEvents attribute or
+// one of resourceExtensions, are skipped.
+func setMetadataExtensions(event *Event, metadata map[string]interface{}) {
+	for key, value := range metadata {
+		name := strings.ToLower(key)
+		if resourceExtensions[name] {
+			continue
+		}
+		if err := event.Context.SetExtension(name, value); err == nil {
+			continue
+		}
+		// Not an attribute value, or an invalid name the retry rejects too
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		_ = event.Context.SetExtension(name, string(encoded))
+	}
 }
 
 // generateEventID generates a unique event ID
@@ -0,0 +1,119 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// recordingBus is an EventBus keeping the published events
+type recordingBus struct {
+	events []Event
+}
+
+func (b *recordingBus) Publish(ctx context.Context, event Event) error {
+	b.events = append(b.events, event)
+	return nil
+}
+
+func (b *recordingBus) Subscribe(string, EventHandler) (SubscriptionID, error) { return "", nil }
+func (b *recordingBus) Unsubscribe(SubscriptionID) error                       { return nil }
+func (b *recordingBus) Close() error                                           { return nil }
+
+// useEvents enables events with a data format and a recording bus for a test
+func useEvents(t *testing.T, dataFormat string) *recordingBus {
+	t.Helper()
+	config := DefaultEventConfig()
+	config.Enabled = true
+	config.EventTypePrefix = "com.openchami"
+	config.DataFormat = dataFormat
+	SetEventConfig(config)
+	bus := &recordingBus{}
+	SetGlobalEventBus(bus)
+	t.Cleanup(func() {
+		SetEventConfig(DefaultEventConfig())
+		SetGlobalEventBus(nil)
+	})
+	return bus
+}
+
+type testDevice struct {
+	Kind     string            `json:"kind"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func TestPublishResourceChange_ResourceFormat(t *testing.T) {
+	bus := useEvents(t, DataFormatResource)
+	device := testDevice{Kind: "Device", Metadata: map[string]string{"uid": "dev-1", "name": "node1"}}
+
+	err := PublishResourceUpdated(context.Background(), "Device", "dev-1", "node1", device, map[string]interface{}{
+		"updateType": "status",
+		"changes":    map[string]int{"spec": 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PublishResourceDeleted(context.Background(), "Device", "dev-1", "node1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.events) != 2 {
+		t.Fatalf("published %d events, want 2", len(bus.events))
+	}
+
+	// The structured JSON of the event is plain CloudEvents 1.0 with the
+	// resource as data
+	encoded, err := json.Marshal(bus.events[0].Event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"specversion":     "1.0",
+		"type":            "com.openchami.device.updated",
+		"subject":         "dev-1",
+		"datacontenttype": "application/json",
+		"resourcekind":    "Device",
+		"updatetype":      "status",
+		"changes":         `{"spec":1}`,
+	}
+	for key, value := range want {
+		if doc[key] != value {
+			t.Errorf("%s = %v, want %v", key, doc[key], value)
+		}
+	}
+	if doc["id"] == "" || doc["source"] == "" {
+		t.Errorf("event needs an id and source: %s", encoded)
+	}
+	var data testDevice
+	if err := bus.events[0].DataAs(&data); err != nil || data.Metadata["uid"] != "dev-1" {
+		t.Errorf("data = %+v (%v), want the device", data, err)
+	}
+
+	// Deletions without the resource have no data
+	if deleted := bus.events[1]; deleted.Type() != "com.openchami.device.deleted" || deleted.Data() != nil {
+		t.Errorf("deleted event = %s with data %s, want no data", deleted.Type(), deleted.Data())
+	}
+}
+
+func TestPublishResourceChange_EnvelopeFormat(t *testing.T) {
+	bus := useEvents(t, "")
+	device := testDevice{Kind: "Device", Metadata: map[string]string{"uid": "dev-1"}}
+
+	if err := PublishResourceCreated(context.Background(), "Device", "dev-1", "node1", device); err != nil {
+		t.Fatal(err)
+	}
+	var data ResourceChangeData
+	if err := bus.events[0].DataAs(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Action != "created" || data.ResourceUID != "dev-1" || data.ResourceName != "node1" || data.Resource == nil {
+		t.Errorf("data = %+v, want a ResourceChangeData of the device", data)
+	}
+}
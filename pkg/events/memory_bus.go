@@ -8,8 +8,11 @@ package events
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
+
+	"github.com/openchami/fabrica/pkg/logging"
 )
 
 // InMemoryEventBus implements EventBus with in-memory channels.
@@ -24,6 +27,7 @@ import (
 //   - No durability (events lost on restart)
 //   - Thread-safe
 //   - Support for wildcard subscriptions
+//   - Graceful shutdown that delivers queued events (see Shutdown)
 type InMemoryEventBus struct {
 	subscribers map[string][]subscription
 	eventQueue  chan Event
@@ -35,6 +39,14 @@ type InMemoryEventBus struct {
 	wg          sync.WaitGroup
 	nextSubID   int
 	subIDMu     sync.Mutex
+
+	// pubMu guards closed, so no event is queued after shutdown begins
+	pubMu     sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+
+	// inflight counts queued events and running handlers
+	inflight sync.WaitGroup
 }
 
 // subscription represents an event subscription
@@ -94,6 +106,7 @@ func (b *InMemoryEventBus) worker() {
 			return
 		case event := <-b.eventQueue:
 			b.dispatch(event)
+			b.inflight.Done()
 		}
 	}
 }
@@ -110,13 +123,16 @@ func (b *InMemoryEventBus) dispatch(event Event) {
 		for _, sub := range subs {
 			if matchesPattern(eventType, sub.pattern) {
 				// Call handler in a goroutine to avoid blocking
+				b.inflight.Add(1)
 				go func(h EventHandler) {
+					defer b.inflight.Done()
+
 					// Create a new context for this handler
 					ctx := context.Background()
 					if err := h(ctx, event); err != nil {
 						// Log error but don't stop processing
-						// In production, this should use a proper logger
-						fmt.Printf("Error handling event %s: %v\n", event.ID(), err)
+						logging.Component(slog.Default(), logging.ComponentEvents).Error("event handler failed",
+							"event_id", event.ID(), "event_type", eventType, "error", err)
 					}
 				}(sub.handler)
 			}
@@ -135,14 +151,25 @@ func (b *InMemoryEventBus) dispatch(event Event) {
 // Returns:
 //   - error: If the event queue is full or the bus is closed
 func (b *InMemoryEventBus) Publish(ctx context.Context, event Event) error {
+	b.pubMu.RLock()
+	defer b.pubMu.RUnlock()
+
+	if b.closed {
+		return fmt.Errorf("event bus is closed")
+	}
+
+	b.inflight.Add(1)
 	select {
 	case <-b.ctx.Done():
+		b.inflight.Done()
 		return fmt.Errorf("event bus is closed")
 	case <-ctx.Done():
+		b.inflight.Done()
 		return ctx.Err()
 	case b.eventQueue <- event:
 		return nil
 	default:
+		b.inflight.Done()
 		return fmt.Errorf("event queue is full")
 	}
 }
@@ -223,14 +250,66 @@ func (b *InMemoryEventBus) Unsubscribe(id SubscriptionID) error {
 // Close shuts down the event bus
 //
 // This stops all workers and waits for them to finish processing.
-// After Close() is called, no more events can be published.
+// Events still queued are dropped; use Shutdown to deliver them first.
+// After Close() is called, no more events can be published. Calling Close
+// more than once has no effect.
 func (b *InMemoryEventBus) Close() error {
-	b.cancel()
-	b.wg.Wait()
-	close(b.eventQueue)
+	b.closeOnce.Do(func() {
+		b.stopPublishing()
+		b.cancel()
+		b.wg.Wait()
+		close(b.eventQueue)
+	})
 	return nil
 }
 
+// Shutdown gracefully shuts down the event bus
+//
+// New events are rejected right away. Events already queued are delivered,
+// and Shutdown waits for their handlers to return before closing the bus.
+// If ctx ends first, the remaining events are dropped and the bus is closed
+// anyway.
+//
+// Parameters:
+//   - ctx: Bounds how long to wait for queued events and handlers
+//
+// Returns:
+//   - error: If ctx ended before every event was handled
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	if err := bus.Shutdown(ctx); err != nil {
+//	    log.Printf("Some events were not delivered: %v", err)
+//	}
+func (b *InMemoryEventBus) Shutdown(ctx context.Context) error {
+	b.stopPublishing()
+
+	drained := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("event bus shutdown: %d events not delivered: %w", len(b.eventQueue), ctx.Err())
+	}
+
+	b.Close() //nolint:errcheck // Close never fails
+	return err
+}
+
+// stopPublishing makes Publish reject new events
+func (b *InMemoryEventBus) stopPublishing() {
+	b.pubMu.Lock()
+	defer b.pubMu.Unlock()
+	b.closed = true
+}
+
 // matchesPattern checks if an event type matches a subscription pattern
 //
 // Pattern matching rules:
@@ -0,0 +1,75 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInMemoryEventBus_ShutdownDeliversQueuedEvents(t *testing.T) {
+	ctx := context.Background()
+	bus := NewInMemoryEventBus(100, 1)
+
+	var handled atomic.Int32
+	if _, err := bus.Subscribe("io.example.**", func(ctx context.Context, event Event) error {
+		time.Sleep(time.Millisecond)
+		handled.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Queue events before the workers start, so they are all pending at shutdown
+	for i := 0; i < 20; i++ {
+		event, err := NewEvent("io.example.device.created", "/test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bus.Publish(ctx, *event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bus.Start()
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := bus.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if got := handled.Load(); got != 20 {
+		t.Errorf("handled %d events before shutdown returned, want 20", got)
+	}
+
+	event, _ := NewEvent("io.example.device.created", "/test", nil)
+	if err := bus.Publish(ctx, *event); err == nil {
+		t.Error("expected Publish to fail after Shutdown")
+	}
+	if err := bus.Close(); err != nil {
+		t.Errorf("Close after Shutdown failed: %v", err)
+	}
+}
+
+func TestInMemoryEventBus_ShutdownDeadline(t *testing.T) {
+	bus := NewInMemoryEventBus(10, 1)
+	release := make(chan struct{})
+	defer close(release)
+	bus.Subscribe("**", func(ctx context.Context, event Event) error { //nolint:errcheck
+		<-release
+		return nil
+	})
+	bus.Start()
+
+	event, _ := NewEvent("io.example.device.created", "/test", nil)
+	bus.Publish(context.Background(), *event) //nolint:errcheck
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := bus.Shutdown(ctx); err == nil {
+		t.Error("expected Shutdown to report the handler still running")
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Outbox stores events for later publishing, so they can be written in the
+// database transaction of the change they report (the transactional outbox
+// pattern): the event is stored if and only if the change is committed, and
+// a Relay publishes it afterwards, even if the process died in between.
+type Outbox interface {
+	// Add stores an event, in the transaction of ctx when it has one
+	Add(ctx context.Context, event Event) error
+}
+
+// OutboxEntry is an event stored in an outbox, with the ID the outbox gave it
+type OutboxEntry struct {
+	ID    int64
+	Event Event
+}
+
+// OutboxStore is an outbox a Relay can drain
+type OutboxStore interface {
+	Outbox
+
+	// Pending returns up to limit stored events, oldest first
+	Pending(ctx context.Context, limit int) ([]OutboxEntry, error)
+
+	// Remove deletes published events
+	Remove(ctx context.Context, ids []int64) error
+}
+
+// outboxKey is the context key of WithOutbox
+type outboxKey struct{}
+
+// WithOutbox returns a context whose resource events are added to outbox
+// instead of being published on the event bus. Storage functions use the
+// transaction of ctx, so events are written with the changes saved in it:
+//
+//	err := storage.WithTx(ctx, func(ctx context.Context) error {
+//	    if err := storage.SaveDevice(ctx, device); err != nil {
+//	        return err
+//	    }
+//	    return events.PublishResourceCreated(events.WithOutbox(ctx, storage.Outbox()), "Device", ...)
+//	})
+//
+// A nil outbox returns ctx unchanged.
+func WithOutbox(ctx context.Context, outbox Outbox) context.Context {
+	if outbox == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, outboxKey{}, outbox)
+}
+
+// outboxFromContext returns the outbox of WithOutbox, or nil
+func outboxFromContext(ctx context.Context) Outbox {
+	outbox, _ := ctx.Value(outboxKey{}).(Outbox)
+	return outbox
+}
+
+// EncodeEvent returns the structured CloudEvents JSON of an event, as stored
+// by outboxes
+func EncodeEvent(event Event) ([]byte, error) {
+	data, err := json.Marshal(event.Event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeEvent parses an event encoded by EncodeEvent
+func DecodeEvent(data []byte) (Event, error) {
+	var event Event
+	if err := json.Unmarshal(data, &event.Event); err != nil {
+		return Event{}, fmt.Errorf("failed to decode event: %w", err)
+	}
+	return event, nil
+}
+
+// Defaults of RelayOptions
+const (
+	DefaultRelayInterval  = time.Second
+	DefaultRelayBatchSize = 100
+)
+
+// RelayOptions configures a Relay. Zero values use the Default* constants.
+type RelayOptions struct {
+	// Interval is how often the outbox is checked for events
+	Interval time.Duration
+
+	// BatchSize is the most events read from the outbox at a time
+	BatchSize int
+
+	// Logger reports failures to read the outbox and publish events
+	Logger *slog.Logger
+}
+
+// Relay publishes the events of an outbox on an event bus, in the order they
+// were stored, and removes them once published.
+//
+// Delivery is at least once: an event whose removal fails, or that another
+// relay reading the same outbox publishes too, is published again. When
+// publishing fails, the remaining events wait for the next check.
+type Relay struct {
+	store   OutboxStore
+	bus     EventBus
+	options RelayOptions
+
+	notify chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewRelay creates a relay of the events of store to bus. Call Start to run
+// it.
+func NewRelay(store OutboxStore, bus EventBus, options RelayOptions) *Relay {
+	if options.Interval <= 0 {
+		options.Interval = DefaultRelayInterval
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultRelayBatchSize
+	}
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+	return &Relay{
+		store:   store,
+		bus:     bus,
+		options: options,
+		notify:  make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start starts relaying events in the background, beginning with those
+// stored before the process started.
+func (r *Relay) Start() {
+	go r.run()
+}
+
+// Notify makes the relay check the outbox now rather than at the next
+// interval, e.g. once a transaction adding events committed.
+func (r *Relay) Notify() {
+	select {
+	case r.notify <- struct{}{}:
+	default: // A check is already due
+	}
+}
+
+// Shutdown stops the relay, waiting for the events being published. Events
+// still in the outbox are published by the next relay.
+func (r *Relay) Shutdown(ctx context.Context) error {
+	r.once.Do(func() { close(r.stop) })
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("outbox relay shutdown: %w", ctx.Err())
+	}
+}
+
+// run relays events until the relay is shut down
+func (r *Relay) run() {
+	defer close(r.done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := r.Drain(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.options.Logger.Warn("failed to relay outbox events", "error", err)
+				}
+				break
+			}
+			if n < r.options.BatchSize {
+				break
+			}
+		}
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		case <-r.notify:
+		}
+	}
+}
+
+// Drain publishes one batch of events of the outbox and removes them,
+// returning how many were published. It stops at the first event that can't
+// be published, removing those published before it.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	entries, err := r.store.Pending(ctx, r.options.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	published := make([]int64, 0, len(entries))
+	var publishErr error
+	for _, entry := range entries {
+		if err := r.bus.Publish(ctx, entry.Event); err != nil {
+			publishErr = fmt.Errorf("failed to publish event %s: %w", entry.Event.ID(), err)
+			break
+		}
+		published = append(published, entry.ID)
+	}
+	if len(published) > 0 {
+		if err := r.store.Remove(ctx, published); err != nil {
+			return 0, fmt.Errorf("failed to remove published events from outbox: %w", err)
+		}
+	}
+	return len(published), publishErr
+}
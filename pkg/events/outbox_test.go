@@ -0,0 +1,195 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryOutbox is an OutboxStore keeping encoded events in memory
+type memoryOutbox struct {
+	mu      sync.Mutex
+	nextID  int64
+	entries map[int64][]byte
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{entries: make(map[int64][]byte)}
+}
+
+func (o *memoryOutbox) Add(ctx context.Context, event Event) error {
+	data, err := EncodeEvent(event)
+	if err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.nextID++
+	o.entries[o.nextID] = data
+	return nil
+}
+
+func (o *memoryOutbox) Pending(ctx context.Context, limit int) ([]OutboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var entries []OutboxEntry
+	for id := int64(1); id <= o.nextID && len(entries) < limit; id++ {
+		data, ok := o.entries[id]
+		if !ok {
+			continue
+		}
+		event, err := DecodeEvent(data)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, OutboxEntry{ID: id, Event: event})
+	}
+	return entries, nil
+}
+
+func (o *memoryOutbox) Remove(ctx context.Context, ids []int64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, id := range ids {
+		delete(o.entries, id)
+	}
+	return nil
+}
+
+func (o *memoryOutbox) len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// failingBus is an EventBus failing the publishing of its nth event
+type failingBus struct {
+	recordingBus
+	failAt int
+}
+
+func (b *failingBus) Publish(ctx context.Context, event Event) error {
+	if len(b.events)+1 == b.failAt {
+		b.failAt = 0
+		return errors.New("bus unavailable")
+	}
+	return b.recordingBus.Publish(ctx, event)
+}
+
+func TestWithOutbox(t *testing.T) {
+	bus := useEvents(t, DataFormatResource)
+	outbox := newMemoryOutbox()
+	device := testDevice{Kind: "Device", Metadata: map[string]string{"uid": "dev-1"}}
+
+	ctx := WithOutbox(context.Background(), outbox)
+	if err := PublishResourceCreated(ctx, "Device", "dev-1", "node1", device); err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.events) != 0 || outbox.len() != 1 {
+		t.Fatalf("published %d events and stored %d, want the event in the outbox", len(bus.events), outbox.len())
+	}
+
+	// The stored event is the event that would have been published
+	entries, err := outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := entries[0].Event
+	var data testDevice
+	if event.Type() != "com.openchami.device.created" || event.Subject() != "dev-1" || event.DataAs(&data) != nil || data.Metadata["uid"] != "dev-1" {
+		t.Errorf("stored event = %s", event)
+	}
+
+	if WithOutbox(context.Background(), nil) != context.Background() {
+		t.Error("WithOutbox(nil) changed the context")
+	}
+}
+
+func TestRelayDrain(t *testing.T) {
+	useEvents(t, DataFormatResource)
+	outbox := newMemoryOutbox()
+	ctx := WithOutbox(context.Background(), outbox)
+	for _, uid := range []string{"dev-1", "dev-2", "dev-3"} {
+		if err := PublishResourceDeleted(ctx, "Device", uid, uid, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Publishing stops at the failing event, which stays in the outbox
+	bus := &failingBus{failAt: 2}
+	relay := NewRelay(outbox, bus, RelayOptions{BatchSize: 10})
+	if n, err := relay.Drain(context.Background()); n != 1 || err == nil {
+		t.Fatalf("Drain = %d, %v; want 1 and an error", n, err)
+	}
+	if outbox.len() != 2 {
+		t.Fatalf("outbox holds %d events, want 2", outbox.len())
+	}
+
+	if n, err := relay.Drain(context.Background()); n != 2 || err != nil {
+		t.Fatalf("Drain = %d, %v; want 2", n, err)
+	}
+	var uids []string
+	for _, event := range bus.events {
+		uids = append(uids, event.Subject())
+	}
+	if len(uids) != 3 || uids[0] != "dev-1" || uids[1] != "dev-2" || uids[2] != "dev-3" {
+		t.Errorf("published %v, want the events in order", uids)
+	}
+	if outbox.len() != 0 {
+		t.Errorf("outbox holds %d published events", outbox.len())
+	}
+}
+
+func TestRelay(t *testing.T) {
+	useEvents(t, DataFormatResource)
+	outbox := newMemoryOutbox()
+	bus := NewInMemoryEventBus(10, 1)
+	bus.Start()
+	defer bus.Close()
+	received := make(chan Event, 10)
+	if _, err := bus.Subscribe("com.openchami.**", func(ctx context.Context, event Event) error {
+		received <- event
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events stored before the relay starts are published too
+	ctx := WithOutbox(context.Background(), outbox)
+	if err := PublishResourceDeleted(ctx, "Device", "dev-1", "node1", nil); err != nil {
+		t.Fatal(err)
+	}
+	relay := NewRelay(outbox, bus, RelayOptions{Interval: time.Hour})
+	relay.Start()
+	defer func() {
+		if err := relay.Shutdown(context.Background()); err != nil {
+			t.Error(err)
+		}
+	}()
+	waitForEvent(t, received, "dev-1")
+
+	// Notify relays without waiting for the interval
+	if err := PublishResourceDeleted(ctx, "Device", "dev-2", "node2", nil); err != nil {
+		t.Fatal(err)
+	}
+	relay.Notify()
+	waitForEvent(t, received, "dev-2")
+}
+
+func waitForEvent(t *testing.T, received <-chan Event, subject string) {
+	t.Helper()
+	select {
+	case event := <-received:
+		if event.Subject() != subject {
+			t.Errorf("received event for %s, want %s", event.Subject(), subject)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("event for %s wasn't relayed", subject)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BusMemory is the name of the built-in in-memory event bus
+const BusMemory = "memory"
+
+// BusOptions configures an event bus created by NewBus
+type BusOptions struct {
+	// BufferSize is the number of events queued for delivery
+	BufferSize int
+
+	// Workers is the number of delivery goroutines
+	Workers int
+
+	// URL locates the broker of buses backed by one (e.g.
+	// "redis://localhost:6379/0?stream=events"); unused by the memory bus
+	URL string
+}
+
+// BusFactory creates a started event bus from its options
+type BusFactory func(options BusOptions) (EventBus, error)
+
+// Shutdowner is implemented by event buses that can deliver the events
+// they queued before closing, such as InMemoryEventBus.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+var (
+	busFactories = map[string]BusFactory{}
+	busFactoryMu sync.RWMutex
+)
+
+func init() {
+	RegisterBus(BusMemory, func(options BusOptions) (EventBus, error) {
+		bus := NewInMemoryEventBus(options.BufferSize, options.Workers)
+		bus.Start()
+		return bus, nil
+	})
+}
+
+// RegisterBus makes an event bus implementation available by name to
+// NewBus, and so to the `event_bus` setting of generated servers.
+//
+// It is meant to be called from the init function of the package
+// implementing the bus, like database/sql drivers: importing the package
+// for its side effect plugs the bus in without changes to generated code.
+// RegisterBus panics if the name is empty or already registered, or if
+// factory is nil.
+//
+// Parameters:
+//   - name: Bus name used in configuration (e.g., "redis-streams")
+//   - factory: Creates a started bus
+//
+// Example:
+//
+//	func init() {
+//	    events.RegisterBus("redis-streams", func(options events.BusOptions) (events.EventBus, error) {
+//	        return NewRedisStreamsBus(options.URL, options.Workers)
+//	    })
+//	}
+func RegisterBus(name string, factory BusFactory) {
+	if name == "" {
+		panic("event bus name cannot be empty")
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("event bus %q has a nil factory", name))
+	}
+
+	busFactoryMu.Lock()
+	defer busFactoryMu.Unlock()
+	if _, exists := busFactories[name]; exists {
+		panic(fmt.Sprintf("event bus %q is already registered", name))
+	}
+	busFactories[name] = factory
+}
+
+// IsBusRegistered reports whether an event bus is registered under name
+func IsBusRegistered(name string) bool {
+	busFactoryMu.RLock()
+	defer busFactoryMu.RUnlock()
+	_, exists := busFactories[name]
+	return exists
+}
+
+// RegisteredBuses returns the names of the registered event buses, sorted
+func RegisteredBuses() []string {
+	busFactoryMu.RLock()
+	defer busFactoryMu.RUnlock()
+	names := make([]string, 0, len(busFactories))
+	for name := range busFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBus creates a started event bus of a registered implementation.
+//
+// Parameters:
+//   - name: Registered bus name (e.g., "memory")
+//   - options: Bus settings
+//
+// Returns:
+//   - EventBus: The started bus
+//   - error: If no bus is registered under name or the factory fails
+//
+// Example:
+//
+//	bus, err := events.NewBus(cfg.EventBus, events.BusOptions{BufferSize: 1000, Workers: 10})
+//	if err != nil {
+//	    return err
+//	}
+//	defer events.ShutdownBus(ctx, bus)
+func NewBus(name string, options BusOptions) (EventBus, error) {
+	busFactoryMu.RLock()
+	factory, exists := busFactories[name]
+	busFactoryMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown event bus %q (registered: %s); import the package that registers it",
+			name, strings.Join(RegisteredBuses(), ", "))
+	}
+
+	bus, err := factory(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s event bus: %w", name, err)
+	}
+	return bus, nil
+}
+
+// ShutdownBus delivers the events a bus has queued, for buses implementing
+// Shutdowner, and closes it.
+//
+// Parameters:
+//   - ctx: Bounds how long to wait for queued events
+//   - bus: The bus to shut down
+//
+// Returns:
+//   - error: If ctx ended before the events were delivered, or Close failed
+func ShutdownBus(ctx context.Context, bus EventBus) error {
+	if s, ok := bus.(Shutdowner); ok {
+		return s.Shutdown(ctx)
+	}
+	return bus.Close()
+}
@@ -0,0 +1,105 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"context"
+	"testing"
+)
+
+// registryBus is a custom bus recording what it publishes
+type registryBus struct {
+	options   BusOptions
+	published []Event
+	closed    bool
+}
+
+func (b *registryBus) Publish(_ context.Context, event Event) error {
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *registryBus) Subscribe(_ string, _ EventHandler) (SubscriptionID, error) {
+	return "sub-1", nil
+}
+
+func (b *registryBus) Unsubscribe(_ SubscriptionID) error { return nil }
+
+func (b *registryBus) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRegisterBus(t *testing.T) {
+	RegisterBus("test-recording", func(options BusOptions) (EventBus, error) {
+		return &registryBus{options: options}, nil
+	})
+	if !IsBusRegistered("test-recording") || !IsBusRegistered(BusMemory) {
+		t.Fatalf("Registered buses = %v", RegisteredBuses())
+	}
+
+	bus, err := NewBus("test-recording", BusOptions{URL: "redis://localhost:6379"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, ok := bus.(*registryBus)
+	if !ok || custom.options.URL != "redis://localhost:6379" {
+		t.Fatalf("NewBus returned %#v", bus)
+	}
+	if err := ShutdownBus(context.Background(), bus); err != nil || !custom.closed {
+		t.Errorf("ShutdownBus didn't close a bus without Shutdown: %v", err)
+	}
+
+	if _, err := NewBus("pubsub", BusOptions{}); err == nil {
+		t.Error("NewBus created an unregistered bus")
+	}
+
+	for name, factory := range map[string]BusFactory{
+		"":               func(BusOptions) (EventBus, error) { return nil, nil },
+		"test-recording": func(BusOptions) (EventBus, error) { return nil, nil },
+		"test-nil":       nil,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterBus(%q) didn't panic", name)
+				}
+			}()
+			RegisterBus(name, factory)
+		}()
+	}
+}
+
+func TestNewBus_Memory(t *testing.T) {
+	bus, err := NewBus(BusMemory, BusOptions{BufferSize: 10, Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := make(chan struct{}, 1)
+	if _, err := bus.Subscribe("io.test.**", func(context.Context, Event) error {
+		delivered <- struct{}{}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	event, err := NewEvent("io.test.device.created", "/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publish(context.Background(), *event); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bus is started, and ShutdownBus delivers queued events
+	if err := ShutdownBus(context.Background(), bus); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-delivered:
+	default:
+		t.Error("Queued event wasn't delivered on shutdown")
+	}
+}
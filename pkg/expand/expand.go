@@ -0,0 +1,239 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package expand inlines referenced resources into responses.
+//
+// Spec fields tagged `fabrica:"ref=<Kind>"` or `fabrica:"parent=<Kind>"`
+// hold the UIDs of other resources, so a client showing a Connection with
+// its Devices needs one request per Device. The expand query parameter of
+// the generated get and list endpoints replaces such UIDs with the
+// referenced resources in one response:
+//
+//	GET /connections/con-1?expand=endpointA.deviceId
+//	{"spec": {"endpointA": {"deviceId": {"kind": "Device", "metadata": {...}, ...}}}}
+//
+// Paths are relative to the spec and may go through nested structs and
+// lists of structs. A list of UIDs becomes a list of resources. UIDs whose
+// resource doesn't exist are left in place.
+//
+// Verify uses the same reference fields to find UIDs whose resource doesn't
+// exist, so handlers can reject them before saving.
+//
+// Usage:
+//
+//	refs, err := expand.Parse(r.URL.Query().Get("expand"), connectionReferences)
+//	expanded, err := expand.New(refs, loadReference).Expand(ctx, connection)
+//	err = expand.Verify(ctx, connection, connectionReferences, loadReference)
+package expand
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Ref is a spec field holding the UIDs of resources of another kind
+type Ref struct {
+	// Path is the dotted JSON path of the field in the spec (e.g., "endpointA.deviceId")
+	Path string
+	// Kind is the referenced kind (e.g., "Device")
+	Kind string
+}
+
+// LoadFunc loads a referenced resource. It returns nil and no error when the
+// resource doesn't exist.
+type LoadFunc func(ctx context.Context, kind, uid string) (interface{}, error)
+
+// Parse reads the expanded fields from an expand parameter: comma-separated
+// paths of reference fields, with or without a "spec." prefix.
+//
+// Parameters:
+//   - input: Field list, usually the expand query parameter
+//   - refs: The reference fields of the resource kind
+//
+// Returns:
+//   - []Ref: The selected reference fields; nil for an empty input
+//   - error: If a path isn't one of refs
+func Parse(input string, refs []Ref) ([]Ref, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	var selected []Ref
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(input, ",") {
+		path := strings.TrimPrefix(strings.TrimSpace(part), "spec.")
+		if seen[path] {
+			continue
+		}
+		ref, ok := find(refs, path)
+		if !ok {
+			return nil, fmt.Errorf("cannot expand %q, expected one of: %s", strings.TrimSpace(part), paths(refs))
+		}
+		seen[path] = true
+		selected = append(selected, ref)
+	}
+	return selected, nil
+}
+
+func find(refs []Ref, path string) (Ref, bool) {
+	for _, ref := range refs {
+		if ref.Path == path {
+			return ref, true
+		}
+	}
+	return Ref{}, false
+}
+
+func paths(refs []Ref) string {
+	if len(refs) == 0 {
+		return "(no reference fields)"
+	}
+	names := make([]string, len(refs))
+	for i, ref := range refs {
+		names[i] = ref.Path
+	}
+	return strings.Join(names, ", ")
+}
+
+// Expander replaces reference fields with the referenced resources. Each
+// referenced resource is loaded once per Expander, so expanding a list
+// doesn't load a resource shared by its items repeatedly.
+type Expander struct {
+	refs   []Ref
+	load   LoadFunc
+	loaded map[string]interface{}
+}
+
+// New creates an Expander.
+//
+// Parameters:
+//   - refs: The fields to expand (see Parse)
+//   - load: Loads referenced resources
+//
+// Returns:
+//   - *Expander: The expander; not safe for concurrent use
+func New(refs []Ref, load LoadFunc) *Expander {
+	return &Expander{refs: refs, load: load, loaded: make(map[string]interface{})}
+}
+
+// Expand returns the JSON form of a resource, or of a list of resources,
+// with the reference fields replaced by the referenced resources.
+//
+// Returns:
+//   - interface{}: A map for a resource, a slice of maps for a list
+//   - error: If obj can't be converted to JSON or loading a resource fails
+func (e *Expander) Expand(ctx context.Context, obj interface{}) (interface{}, error) {
+	doc, err := decode(obj)
+	if err != nil {
+		return nil, err
+	}
+	items, isList := doc.([]interface{})
+	if !isList {
+		items = []interface{}{doc}
+	}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot expand a value of type %T", item)
+		}
+		for _, ref := range e.refs {
+			if err := e.expandPath(ctx, m, "spec", strings.Split(ref.Path, "."), ref.Kind); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+// expandPath replaces the UIDs at path below parent[key]
+func (e *Expander) expandPath(ctx context.Context, parent map[string]interface{}, key string, path []string, kind string) error {
+	value, ok := parent[key]
+	if !ok {
+		return nil
+	}
+	if len(path) == 0 {
+		expanded, err := e.expandValue(ctx, value, kind)
+		if err != nil {
+			return err
+		}
+		parent[key] = expanded
+		return nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return e.expandPath(ctx, v, path[0], path[1:], kind)
+	case []interface{}:
+		// A list of structs: expand the field of each one
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				if err := e.expandPath(ctx, m, path[0], path[1:], kind); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// expandValue replaces a UID or a list of UIDs
+func (e *Expander) expandValue(ctx context.Context, value interface{}, kind string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return e.resource(ctx, kind, v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			uid, ok := item.(string)
+			if !ok {
+				items[i] = item
+				continue
+			}
+			res, err := e.resource(ctx, kind, uid)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = res
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+// resource returns the JSON form of a referenced resource, or the UID if it doesn't exist
+func (e *Expander) resource(ctx context.Context, kind, uid string) (interface{}, error) {
+	if uid == "" {
+		return uid, nil
+	}
+	key := kind + "/" + uid
+	if doc, ok := e.loaded[key]; ok {
+		return doc, nil
+	}
+	res, err := e.load(ctx, kind, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s %s: %w", kind, uid, err)
+	}
+	var doc interface{} = uid
+	if res != nil {
+		if doc, err = decode(res); err != nil {
+			return nil, err
+		}
+	}
+	e.loaded[key] = doc
+	return doc, nil
+}
+
+// decode converts a value to its JSON form
+func decode(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode resource: %w", err)
+	}
+	return doc, nil
+}
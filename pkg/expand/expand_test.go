@@ -0,0 +1,153 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package expand
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type endpoint struct {
+	DeviceID string `json:"deviceId"`
+}
+
+type connection struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		EndpointA endpoint   `json:"endpointA"`
+		Ports     []endpoint `json:"ports,omitempty"`
+		Devices   []string   `json:"devices,omitempty"`
+		Location  string     `json:"location,omitempty"`
+	} `json:"spec"`
+}
+
+var connectionRefs = []Ref{
+	{Path: "endpointA.deviceId", Kind: "Device"},
+	{Path: "ports.deviceId", Kind: "Device"},
+	{Path: "devices", Kind: "Device"},
+	{Path: "location", Kind: "Location"},
+}
+
+// testLoader serves two Devices, fails for Locations and counts loads
+type testLoader struct {
+	loads int
+}
+
+func (l *testLoader) load(_ context.Context, kind, uid string) (interface{}, error) {
+	l.loads++
+	switch {
+	case kind == "Location":
+		return nil, errors.New("backend unavailable")
+	case uid == "dev-1" || uid == "dev-2":
+		return map[string]string{"kind": kind, "uid": uid}, nil
+	}
+	return nil, nil
+}
+
+func TestParse(t *testing.T) {
+	refs, err := Parse("spec.endpointA.deviceId, devices,devices", connectionRefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 2 || refs[0].Path != "endpointA.deviceId" || refs[1].Path != "devices" {
+		t.Errorf("unexpected refs %v", refs)
+	}
+
+	if refs, err := Parse(" ", connectionRefs); refs != nil || err != nil {
+		t.Errorf("expected nothing for an empty input, got %v, %v", refs, err)
+	}
+	if _, err := Parse("medium", connectionRefs); err == nil {
+		t.Error("expected an error for a field that isn't a reference")
+	}
+	if _, err := Parse("devices", nil); err == nil {
+		t.Error("expected an error for a kind without references")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	var c connection
+	c.Kind = "Connection"
+	c.Spec.EndpointA.DeviceID = "dev-1"
+	c.Spec.Ports = []endpoint{{DeviceID: "dev-2"}, {DeviceID: "dev-9"}}
+	c.Spec.Devices = []string{"dev-1", "dev-2"}
+
+	loader := &testLoader{}
+	refs := connectionRefs[:3]
+	expanded, err := New(refs, loader.load).Expand(context.Background(), []connection{c, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(expanded.([]interface{})[1])
+	want := `{"kind":"Connection","spec":{"devices":[{"kind":"Device","uid":"dev-1"},{"kind":"Device","uid":"dev-2"}],` +
+		`"endpointA":{"deviceId":{"kind":"Device","uid":"dev-1"}},` +
+		`"ports":[{"deviceId":{"kind":"Device","uid":"dev-2"}},{"deviceId":"dev-9"}]}}`
+	if string(data) != want {
+		t.Errorf("got  %s\nwant %s", data, want)
+	}
+	if loader.loads != 3 {
+		t.Errorf("expected each resource to be loaded once, got %d loads", loader.loads)
+	}
+}
+
+func TestExpand_LoadError(t *testing.T) {
+	var c connection
+	c.Spec.Location = "loc-1"
+
+	loader := &testLoader{}
+	if _, err := New(connectionRefs[3:], loader.load).Expand(context.Background(), c); err == nil {
+		t.Error("expected the load error to be returned")
+	}
+
+	// Empty references aren't loaded
+	c.Spec.Location = ""
+	if _, err := New(connectionRefs[3:], loader.load).Expand(context.Background(), c); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	var c connection
+	c.Spec.EndpointA.DeviceID = "dev-9"
+	c.Spec.Ports = []endpoint{{DeviceID: "dev-1"}, {DeviceID: "dev-9"}, {}}
+	c.Spec.Devices = []string{"dev-2", "dev-8"}
+
+	loader := &testLoader{}
+	err := Verify(context.Background(), c, connectionRefs[:3], loader.load)
+	var broken *BrokenError
+	if !errors.As(err, &broken) {
+		t.Fatalf("expected a *BrokenError, got %v", err)
+	}
+	want := []Broken{
+		{Path: "spec.endpointA.deviceId", Kind: "Device", UID: "dev-9"},
+		{Path: "spec.ports[1].deviceId", Kind: "Device", UID: "dev-9"},
+		{Path: "spec.devices[1]", Kind: "Device", UID: "dev-8"},
+	}
+	if len(broken.Refs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, broken.Refs)
+	}
+	for i := range want {
+		if broken.Refs[i] != want[i] {
+			t.Errorf("expected %v, got %v", want[i], broken.Refs[i])
+		}
+	}
+	// dev-1, dev-2, dev-8 and dev-9 are each loaded once; the empty UID is skipped
+	if loader.loads != 4 {
+		t.Errorf("expected 4 loads, got %d", loader.loads)
+	}
+
+	c.Spec.EndpointA.DeviceID = "dev-1"
+	c.Spec.Ports = nil
+	c.Spec.Devices = nil
+	if err := Verify(context.Background(), c, connectionRefs[:3], loader.load); err != nil {
+		t.Errorf("expected valid references, got %v", err)
+	}
+	c.Spec.Location = "loc-1"
+	if err := Verify(context.Background(), c, connectionRefs, loader.load); err == nil || errors.As(err, &broken) {
+		t.Errorf("expected the load error, got %v", err)
+	}
+}
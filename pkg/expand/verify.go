@@ -0,0 +1,140 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package expand
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Broken is a reference to a resource that doesn't exist
+type Broken struct {
+	// Path is the JSON path of the reference in the resource, with list
+	// indexes (e.g., "spec.ports[1].deviceId")
+	Path string
+	// Kind is the referenced kind
+	Kind string
+	// UID is the missing resource's UID
+	UID string
+}
+
+// String describes the broken reference, e.g.
+// `spec.endpointA.deviceId: Device "dev-9" does not exist`
+func (b Broken) String() string {
+	return fmt.Sprintf("%s: %s %q does not exist", b.Path, b.Kind, b.UID)
+}
+
+// BrokenError reports the broken references of a resource.
+type BrokenError struct {
+	Refs []Broken
+}
+
+// Error lists the broken references.
+func (e *BrokenError) Error() string {
+	msgs := make([]string, len(e.Refs))
+	for i, ref := range e.Refs {
+		msgs[i] = ref.String()
+	}
+	return "broken references: " + strings.Join(msgs, "; ")
+}
+
+// Verify checks that the resources referenced by a resource exist. Empty
+// UIDs are optional references and aren't checked.
+//
+// Parameters:
+//   - obj: The resource
+//   - refs: The reference fields of its kind
+//   - load: Loads referenced resources
+//
+// Returns:
+//   - error: A *BrokenError listing the references to missing resources in
+//     field order, or the error of converting obj or loading a resource
+func Verify(ctx context.Context, obj interface{}, refs []Ref, load LoadFunc) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	doc, err := decode(obj)
+	if err != nil {
+		return err
+	}
+	m, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("cannot verify a value of type %T", doc)
+	}
+
+	v := &verifier{load: load, exists: make(map[string]bool)}
+	for _, ref := range refs {
+		if err := v.verifyPath(ctx, m["spec"], "spec", strings.Split(ref.Path, "."), ref.Kind); err != nil {
+			return err
+		}
+	}
+	if len(v.broken) > 0 {
+		return &BrokenError{Refs: v.broken}
+	}
+	return nil
+}
+
+// verifier collects broken references, loading each referenced resource once
+type verifier struct {
+	load   LoadFunc
+	exists map[string]bool
+	broken []Broken
+}
+
+// verifyPath checks the UIDs at path below value, whose JSON path is at
+func (v *verifier) verifyPath(ctx context.Context, value interface{}, at string, path []string, kind string) error {
+	if len(path) == 0 {
+		switch uids := value.(type) {
+		case string:
+			return v.verifyUID(ctx, at, kind, uids)
+		case []interface{}:
+			for i, item := range uids {
+				if uid, ok := item.(string); ok {
+					if err := v.verifyUID(ctx, fmt.Sprintf("%s[%d]", at, i), kind, uid); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		return v.verifyPath(ctx, val[path[0]], at+"."+path[0], path[1:], kind)
+	case []interface{}:
+		// A list of structs: check the field of each one
+		for i, item := range val {
+			if m, ok := item.(map[string]interface{}); ok {
+				if err := v.verifyPath(ctx, m[path[0]], fmt.Sprintf("%s[%d].%s", at, i, path[0]), path[1:], kind); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// verifyUID records a broken reference if the resource doesn't exist
+func (v *verifier) verifyUID(ctx context.Context, at, kind, uid string) error {
+	if uid == "" {
+		return nil
+	}
+	key := kind + "/" + uid
+	exists, ok := v.exists[key]
+	if !ok {
+		res, err := v.load(ctx, kind, uid)
+		if err != nil {
+			return fmt.Errorf("failed to load %s %s: %w", kind, uid, err)
+		}
+		exists = res != nil
+		v.exists[key] = exists
+	}
+	if !exists {
+		v.broken = append(v.broken, Broken{Path: at, Kind: kind, UID: uid})
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package graph traverses the references between resources.
+//
+// Resources refer to each other through spec fields holding UIDs, declared
+// with `fabrica:"parent=<Kind>"` or `fabrica:"ref=<Kind>"` tags: a Device
+// sits in a Location, a Connection joins two Devices. Traverse starts at one
+// resource and follows these references in both directions up to a depth,
+// returning the resources reached as nodes and the references as edges. This
+// answers questions like "what is connected to this switch" or "which
+// locations contain this device" in one request.
+//
+// The generated server implements Source with its storage functions and
+// serves the result at GET /<resources>/{uid}/graph.
+//
+// Usage:
+//
+//	g, err := graph.Traverse(ctx, source, relations, "Device", uid, graph.Options{Depth: 2})
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/query"
+)
+
+// Direction selects which references are followed
+type Direction string
+
+// Directions
+const (
+	// Out follows references held by a resource (Device -> its Location)
+	Out Direction = "out"
+	// In follows references to a resource (Location -> Devices in it)
+	In Direction = "in"
+	// Both follows references in both directions
+	Both Direction = "both"
+)
+
+// Limits applied when Options leave them unset
+const (
+	DefaultDepth    = 1
+	MaxDepth        = 10
+	DefaultMaxNodes = 1000
+)
+
+// Relation is a reference from a spec field of one kind to another kind
+type Relation struct {
+	// From is the kind holding the reference (e.g., "Device")
+	From string `json:"from"`
+	// Field is the JSON name of the spec field (e.g., "locationUID"). The
+	// field holds one UID or a list of UIDs.
+	Field string `json:"field"`
+	// To is the referenced kind (e.g., "Location")
+	To string `json:"to"`
+	// Parent marks a parent reference (fabrica:"parent=...")
+	Parent bool `json:"parent,omitempty"`
+}
+
+// Source loads resources for Traverse
+type Source interface {
+	// Load returns the resource of a kind with a UID
+	Load(ctx context.Context, kind, uid string) (interface{}, error)
+
+	// Referencing returns the resources of a kind whose spec field holds uid
+	Referencing(ctx context.Context, kind, field, uid string) ([]interface{}, error)
+}
+
+// Options controls a traversal
+type Options struct {
+	// Depth is the number of references followed from the root
+	// (default DefaultDepth, at most MaxDepth)
+	Depth int
+
+	// Direction selects the references followed (default Both)
+	Direction Direction
+
+	// Kinds limits the nodes to these kinds (default: all). The root is
+	// always included.
+	Kinds []string
+
+	// Filter excludes resources that don't match, together with what is
+	// only reachable through them. The root is always included.
+	Filter query.Expr
+
+	// MaxNodes stops the traversal when reached (default DefaultMaxNodes)
+	MaxNodes int
+
+	// IncludeResources adds the full resource to each node
+	IncludeResources bool
+}
+
+// Node is a resource reached by the traversal
+type Node struct {
+	Kind     string      `json:"kind"`
+	UID      string      `json:"uid"`
+	Name     string      `json:"name,omitempty"`
+	Depth    int         `json:"depth"`
+	Resource interface{} `json:"resource,omitempty"`
+}
+
+// Edge is a reference between two nodes, pointing from the resource holding
+// the reference to the referenced resource
+type Edge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Field  string `json:"field"`
+	Parent bool   `json:"parent,omitempty"`
+}
+
+// Graph is the result of a traversal
+type Graph struct {
+	Root  string `json:"root"`
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	// Truncated is set when MaxNodes stopped the traversal
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ErrNotFound is returned by Traverse when the root resource can't be loaded
+var ErrNotFound = errors.New("resource not found")
+
+// Validate checks the options and fills in defaults
+func (o *Options) Validate() error {
+	if o.Depth == 0 {
+		o.Depth = DefaultDepth
+	}
+	if o.Depth < 0 || o.Depth > MaxDepth {
+		return fmt.Errorf("depth must be between 1 and %d", MaxDepth)
+	}
+	switch o.Direction {
+	case "":
+		o.Direction = Both
+	case Out, In, Both:
+	default:
+		return fmt.Errorf("direction must be %q, %q or %q", Out, In, Both)
+	}
+	if o.MaxNodes <= 0 {
+		o.MaxNodes = DefaultMaxNodes
+	}
+	return nil
+}
+
+// Traverse walks the references around a resource breadth-first.
+//
+// Each resource appears once, at the depth where it was first reached.
+// Edges are reported between every pair of returned nodes connected by a
+// followed reference. References to resources that no longer exist are
+// skipped.
+//
+// Parameters:
+//   - ctx: Context for storage calls
+//   - src: Loads resources
+//   - relations: References between kinds
+//   - kind, uid: The root resource
+//   - opts: Depth, direction and filters
+//
+// Returns:
+//   - *Graph: Nodes and edges, sorted by depth, kind and UID
+//   - error: ErrNotFound for a missing root, or the first storage error
+func Traverse(ctx context.Context, src Source, relations []Relation, kind, uid string, opts Options) (*Graph, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	rootRes, err := src.Load(ctx, kind, uid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %v", ErrNotFound, kind, uid, err)
+	}
+	root, err := newVisit(kind, rootRes)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &traversal{
+		ctx:       ctx,
+		src:       src,
+		relations: relations,
+		opts:      opts,
+		kinds:     make(map[string]bool),
+		nodes:     map[string]*visit{root.uid: root},
+		edges:     make(map[Edge]bool),
+		graph:     &Graph{Root: root.uid},
+	}
+	for _, k := range opts.Kinds {
+		t.kinds[k] = true
+	}
+
+	frontier := []*visit{root}
+	for depth := 1; depth <= opts.Depth && len(frontier) > 0 && !t.graph.Truncated; depth++ {
+		var next []*visit
+		for _, v := range frontier {
+			reached, err := t.expand(v, depth)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, reached...)
+		}
+		frontier = next
+	}
+
+	return t.result(), nil
+}
+
+// visit is a resource reached by the traversal
+type visit struct {
+	kind  string
+	uid   string
+	name  string
+	depth int
+	doc   map[string]interface{}
+	res   interface{}
+}
+
+func newVisit(kind string, res interface{}) (*visit, error) {
+	doc, err := toDocument(res)
+	if err != nil {
+		return nil, err
+	}
+	v := &visit{kind: kind, doc: doc, res: res}
+	if metadata, ok := doc["metadata"].(map[string]interface{}); ok {
+		v.uid, _ = metadata["uid"].(string)
+		v.name, _ = metadata["name"].(string)
+	}
+	if v.uid == "" {
+		return nil, fmt.Errorf("%s resource has no metadata.uid", kind)
+	}
+	return v, nil
+}
+
+type traversal struct {
+	ctx       context.Context
+	src       Source
+	relations []Relation
+	opts      Options
+	kinds     map[string]bool
+	nodes     map[string]*visit
+	edges     map[Edge]bool
+	graph     *Graph
+}
+
+// expand follows the references of v, returning the newly reached resources
+func (t *traversal) expand(v *visit, depth int) ([]*visit, error) {
+	var reached []*visit
+	for _, rel := range t.relations {
+		if t.opts.Direction != In && rel.From == v.kind && t.allowed(rel.To) {
+			for _, target := range refUIDs(v.doc, rel.Field) {
+				target := target
+				next, added, err := t.reach(rel.To, target, depth, func() (interface{}, error) {
+					return t.src.Load(t.ctx, rel.To, target)
+				})
+				if err != nil {
+					return nil, err
+				}
+				if next == nil {
+					continue
+				}
+				t.edges[Edge{From: v.uid, To: next.uid, Field: "spec." + rel.Field, Parent: rel.Parent}] = true
+				if added {
+					reached = append(reached, next)
+				}
+			}
+		}
+		if t.opts.Direction != Out && rel.To == v.kind && t.allowed(rel.From) {
+			items, err := t.src.Referencing(t.ctx, rel.From, rel.Field, v.uid)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s resources referencing %s: %w", rel.From, v.uid, err)
+			}
+			for _, item := range items {
+				item := item
+				next, added, err := t.reach(rel.From, "", depth, func() (interface{}, error) { return item, nil })
+				if err != nil {
+					return nil, err
+				}
+				if next == nil {
+					continue
+				}
+				t.edges[Edge{From: next.uid, To: v.uid, Field: "spec." + rel.Field, Parent: rel.Parent}] = true
+				if added {
+					reached = append(reached, next)
+				}
+			}
+		}
+	}
+	return reached, nil
+}
+
+// reach returns the node of a resource and whether it was added at depth by
+// this call. uid may be empty when the resource is already loaded. It
+// returns nil for missing references, resources excluded by the filter, and
+// once MaxNodes is reached.
+func (t *traversal) reach(kind, uid string, depth int, load func() (interface{}, error)) (*visit, bool, error) {
+	if existing, ok := t.nodes[uid]; ok && uid != "" {
+		return existing, false, nil
+	}
+
+	res, err := load()
+	if err != nil {
+		// Dangling references are skipped
+		return nil, false, nil
+	}
+	v, err := newVisit(kind, res)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing, ok := t.nodes[v.uid]; ok {
+		return existing, false, nil
+	}
+
+	if t.opts.Filter != nil {
+		match, err := query.Match(t.opts.Filter, v.doc)
+		if err != nil {
+			return nil, false, err
+		}
+		if !match {
+			return nil, false, nil
+		}
+	}
+	if len(t.nodes) >= t.opts.MaxNodes {
+		t.graph.Truncated = true
+		return nil, false, nil
+	}
+
+	v.depth = depth
+	t.nodes[v.uid] = v
+	return v, true, nil
+}
+
+// allowed reports whether nodes of a kind may be returned
+func (t *traversal) allowed(kind string) bool {
+	return len(t.kinds) == 0 || t.kinds[kind]
+}
+
+// result builds the sorted graph
+func (t *traversal) result() *Graph {
+	g := t.graph
+	g.Nodes = make([]Node, 0, len(t.nodes))
+	for _, v := range t.nodes {
+		node := Node{Kind: v.kind, UID: v.uid, Name: v.name, Depth: v.depth}
+		if t.opts.IncludeResources {
+			node.Resource = v.res
+		}
+		g.Nodes = append(g.Nodes, node)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		a, b := g.Nodes[i], g.Nodes[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.UID < b.UID
+	})
+
+	g.Edges = make([]Edge, 0, len(t.edges))
+	for e := range t.edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Field < b.Field
+	})
+	return g
+}
+
+// refUIDs returns the UIDs held by a spec field: a string or a list of strings
+func refUIDs(doc map[string]interface{}, field string) []string {
+	spec, _ := doc["spec"].(map[string]interface{})
+	switch v := spec[field].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []interface{}:
+		uids := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				uids = append(uids, s)
+			}
+		}
+		return uids
+	}
+	return nil
+}
+
+// ParseKinds splits a comma-separated list of kinds, as in ?kinds=Device,Location
+func ParseKinds(s string) []string {
+	var kinds []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
+
+// toDocument converts a resource to its decoded JSON form
+func toDocument(obj interface{}) (map[string]interface{}, error) {
+	if doc, ok := obj.(map[string]interface{}); ok {
+		return doc, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode resource: %w", err)
+	}
+	return doc, nil
+}
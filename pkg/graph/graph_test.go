@@ -0,0 +1,211 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/query"
+)
+
+// fakeSource serves resources from memory, keyed by kind and UID
+type fakeSource struct {
+	resources map[string]map[string]map[string]interface{}
+}
+
+func (s *fakeSource) add(kind, uid, name string, spec map[string]interface{}) {
+	if s.resources == nil {
+		s.resources = make(map[string]map[string]map[string]interface{})
+	}
+	if s.resources[kind] == nil {
+		s.resources[kind] = make(map[string]map[string]interface{})
+	}
+	s.resources[kind][uid] = map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"uid": uid, "name": name},
+		"spec":     spec,
+	}
+}
+
+func (s *fakeSource) Load(_ context.Context, kind, uid string) (interface{}, error) {
+	res, ok := s.resources[kind][uid]
+	if !ok {
+		return nil, fmt.Errorf("%s %s not found", kind, uid)
+	}
+	return res, nil
+}
+
+func (s *fakeSource) Referencing(_ context.Context, kind, field, uid string) ([]interface{}, error) {
+	var items []interface{}
+	for _, res := range s.resources[kind] {
+		for _, ref := range refUIDs(res, field) {
+			if ref == uid {
+				items = append(items, res)
+				break
+			}
+		}
+	}
+	return items, nil
+}
+
+var relations = []Relation{
+	{From: "Location", Field: "parentUID", To: "Location", Parent: true},
+	{From: "Device", Field: "locationUID", To: "Location", Parent: true},
+	{From: "Connection", Field: "deviceUIDs", To: "Device"},
+}
+
+// newTopology builds a site with one rack holding two switches and a cable
+// between them; sw-2 also has a cable to a device that was deleted
+func newTopology() *fakeSource {
+	s := &fakeSource{}
+	s.add("Location", "loc-site", "site", map[string]interface{}{})
+	s.add("Location", "loc-rack", "rack-1", map[string]interface{}{"parentUID": "loc-site"})
+	s.add("Device", "dev-sw1", "sw-1", map[string]interface{}{"locationUID": "loc-rack", "role": "spine"})
+	s.add("Device", "dev-sw2", "sw-2", map[string]interface{}{"locationUID": "loc-rack", "role": "leaf"})
+	s.add("Connection", "con-1", "sw1-sw2", map[string]interface{}{"deviceUIDs": []interface{}{"dev-sw1", "dev-sw2"}})
+	s.add("Connection", "con-2", "sw2-gone", map[string]interface{}{"deviceUIDs": []interface{}{"dev-sw2", "dev-gone"}})
+	return s
+}
+
+func nodeUIDs(g *Graph) []string {
+	uids := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		uids = append(uids, n.UID)
+	}
+	return uids
+}
+
+func TestTraverse_Depth(t *testing.T) {
+	src := newTopology()
+
+	g, err := Traverse(context.Background(), src, relations, "Device", "dev-sw1", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"dev-sw1", "con-1", "loc-rack"}; !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("depth 1 nodes = %v, want %v", nodeUIDs(g), want)
+	}
+
+	g, err = Traverse(context.Background(), src, relations, "Device", "dev-sw1", Options{Depth: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"dev-sw1", "con-1", "loc-rack", "dev-sw2", "loc-site"}
+	if !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("depth 2 nodes = %v, want %v", nodeUIDs(g), want)
+	}
+	for _, n := range g.Nodes {
+		if n.UID == "dev-sw2" && (n.Depth != 2 || n.Name != "sw-2" || n.Kind != "Device") {
+			t.Errorf("unexpected node %+v", n)
+		}
+		if n.Resource != nil {
+			t.Errorf("expected no resource without IncludeResources, got %v", n.Resource)
+		}
+	}
+
+	wantEdges := []Edge{
+		{From: "con-1", To: "dev-sw1", Field: "spec.deviceUIDs"},
+		{From: "con-1", To: "dev-sw2", Field: "spec.deviceUIDs"},
+		{From: "dev-sw1", To: "loc-rack", Field: "spec.locationUID", Parent: true},
+		{From: "dev-sw2", To: "loc-rack", Field: "spec.locationUID", Parent: true},
+		{From: "loc-rack", To: "loc-site", Field: "spec.parentUID", Parent: true},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("edges = %+v, want %+v", g.Edges, wantEdges)
+	}
+}
+
+func TestTraverse_Direction(t *testing.T) {
+	src := newTopology()
+
+	g, err := Traverse(context.Background(), src, relations, "Location", "loc-rack", Options{Depth: 3, Direction: Out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"loc-rack", "loc-site"}; !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("out nodes = %v, want %v", nodeUIDs(g), want)
+	}
+
+	g, err = Traverse(context.Background(), src, relations, "Location", "loc-site", Options{Depth: 2, Direction: In})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"loc-site", "loc-rack", "dev-sw1", "dev-sw2"}; !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("in nodes = %v, want %v", nodeUIDs(g), want)
+	}
+}
+
+func TestTraverse_Kinds(t *testing.T) {
+	g, err := Traverse(context.Background(), newTopology(), relations, "Device", "dev-sw1",
+		Options{Depth: 3, Kinds: ParseKinds("Location, ")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The root is kept; the connection and the other switch are not followed
+	if want := []string{"dev-sw1", "loc-rack", "loc-site"}; !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("nodes = %v, want %v", nodeUIDs(g), want)
+	}
+}
+
+func TestTraverse_Filter(t *testing.T) {
+	filter, err := query.Parse(`kind != "Device" || spec.role == "spine"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := Traverse(context.Background(), newTopology(), relations, "Location", "loc-rack",
+		Options{Depth: 2, Direction: In, Filter: filter, IncludeResources: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"loc-rack", "dev-sw1", "con-1"}; !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("nodes = %v, want %v", nodeUIDs(g), want)
+	}
+	if g.Nodes[1].Resource == nil {
+		t.Error("expected the resource with IncludeResources")
+	}
+}
+
+func TestTraverse_DanglingReference(t *testing.T) {
+	g, err := Traverse(context.Background(), newTopology(), relations, "Connection", "con-2", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"con-2", "dev-sw2"}; !reflect.DeepEqual(nodeUIDs(g), want) {
+		t.Errorf("nodes = %v, want %v", nodeUIDs(g), want)
+	}
+	if len(g.Edges) != 1 {
+		t.Errorf("expected 1 edge, got %+v", g.Edges)
+	}
+}
+
+func TestTraverse_MaxNodes(t *testing.T) {
+	g, err := Traverse(context.Background(), newTopology(), relations, "Location", "loc-site",
+		Options{Depth: MaxDepth, MaxNodes: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes) != 3 || !g.Truncated {
+		t.Errorf("expected 3 nodes and Truncated, got %v truncated=%v", nodeUIDs(g), g.Truncated)
+	}
+}
+
+func TestTraverse_Errors(t *testing.T) {
+	src := newTopology()
+
+	_, err := Traverse(context.Background(), src, relations, "Device", "dev-missing", Options{})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	for _, opts := range []Options{{Depth: -1}, {Depth: MaxDepth + 1}, {Direction: "sideways"}} {
+		if _, err := Traverse(context.Background(), src, relations, "Device", "dev-sw1", opts); err == nil {
+			t.Errorf("expected %+v to be rejected", opts)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package https provides TLS for generated servers.
+//
+// Certificates come from one of three sources:
+//
+//   - Files: a PEM certificate and key, e.g. issued by cert-manager or an
+//     internal CA. The files are re-read when they change, or when Reload is
+//     called, so rotated certificates are picked up without a restart.
+//   - PEM values: the certificate and key themselves, e.g. passed in
+//     environment variables by an orchestrator. They can't change while the
+//     server runs.
+//   - ACME: certificates for a list of domains are obtained and renewed
+//     automatically from Let's Encrypt (or another ACME CA) with
+//     golang.org/x/crypto/acme/autocert, and cached in a directory.
+//
+// With a client CA file, clients must present a certificate signed by one of
+// its CAs (mutual TLS), or may present one with ClientAuthOptional. The CA
+// file is reloaded like the certificate files. Handlers read the verified
+// certificate with ClientCertificate.
+//
+// RedirectHandler serves plain HTTP requests by redirecting them to HTTPS. It
+// also answers ACME HTTP-01 challenges when ACME is used.
+//
+// Usage:
+//
+//	t, err := https.New(https.Options{CertFile: "tls.crt", KeyFile: "tls.key"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	server := &http.Server{Addr: ":8443", Handler: r, TLSConfig: t.TLSConfig()}
+//	go http.ListenAndServe(":8080", t.RedirectHandler(8443))
+//	log.Fatal(server.ListenAndServeTLS("", ""))
+package https
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DefaultCacheDir is where ACME certificates are cached when no directory is given
+const DefaultCacheDir = "./certs"
+
+// reloadInterval is how often certificate files are checked for changes
+const reloadInterval = 10 * time.Second
+
+// Client certificate modes of Options.ClientAuth
+const (
+	// ClientAuthRequire rejects clients without a valid certificate (default)
+	ClientAuthRequire = "require"
+	// ClientAuthOptional verifies a certificate when the client presents one,
+	// leaving it to handlers to require it
+	ClientAuthOptional = "optional"
+)
+
+// Options configures TLS.
+//
+// Set either CertFile and KeyFile, CertPEM and KeyPEM, or ACMEDomains. Leave
+// all of them empty to serve plain HTTP.
+type Options struct {
+	// CertFile and KeyFile are PEM files of the server certificate (with any
+	// intermediates) and its private key
+	CertFile string
+	KeyFile  string
+
+	// CertPEM and KeyPEM are the PEM certificate and key themselves, for
+	// deployments passing them in environment variables
+	CertPEM string
+	KeyPEM  string
+
+	// ACMEDomains are the host names to obtain certificates for
+	ACMEDomains []string
+
+	// ACMEEmail is the contact address registered with the CA (optional)
+	ACMEEmail string
+
+	// ACMECacheDir stores account keys and certificates across restarts
+	// (default: DefaultCacheDir)
+	ACMECacheDir string
+
+	// ACMEDirectoryURL is the CA's directory (default: Let's Encrypt
+	// production). Use Let's Encrypt staging while testing to avoid rate limits:
+	// https://acme-staging-v02.api.letsencrypt.org/directory
+	ACMEDirectoryURL string
+
+	// ClientCAFile is a PEM file of the CAs client certificates are verified
+	// against. Setting it enables mutual TLS.
+	ClientCAFile string
+
+	// ClientAuth is ClientAuthRequire or ClientAuthOptional (default:
+	// ClientAuthRequire when ClientCAFile is set)
+	ClientAuth string
+
+	// MinVersion is the lowest TLS version accepted (default: TLS 1.2)
+	MinVersion uint16
+}
+
+// Enabled reports whether TLS is configured
+func (o Options) Enabled() bool {
+	return o.CertFile != "" || o.KeyFile != "" || o.CertPEM != "" || o.KeyPEM != "" || len(o.ACMEDomains) > 0
+}
+
+// Validate checks that the options describe exactly one certificate source
+// and a valid client certificate mode
+func (o Options) Validate() error {
+	hasFiles := o.CertFile != "" || o.KeyFile != ""
+	hasPEM := o.CertPEM != "" || o.KeyPEM != ""
+	sources := 0
+	for _, set := range []bool{hasFiles, hasPEM, len(o.ACMEDomains) > 0} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case hasFiles && (o.CertFile == "" || o.KeyFile == ""):
+		return errors.New("both a TLS certificate file and a key file are required")
+	case hasPEM && (o.CertPEM == "" || o.KeyPEM == ""):
+		return errors.New("both a TLS certificate and a key are required")
+	case sources > 1:
+		return errors.New("only one of TLS certificate files, PEM values and ACME domains can be used")
+	case o.ClientCAFile != "" && sources == 0:
+		return errors.New("a TLS client CA requires a server certificate or ACME domains")
+	case o.ClientAuth != "" && o.ClientCAFile == "":
+		return errors.New("a TLS client auth mode requires a client CA file")
+	case o.ClientAuth != "" && o.ClientAuth != ClientAuthRequire && o.ClientAuth != ClientAuthOptional:
+		return fmt.Errorf("unknown TLS client auth mode %q, expected %s or %s", o.ClientAuth, ClientAuthRequire, ClientAuthOptional)
+	}
+	return nil
+}
+
+// TLS is the TLS setup of a server
+type TLS struct {
+	config  *tls.Config
+	manager *autocert.Manager
+	files   *certReloader // nil when nothing is loaded from files
+}
+
+// New sets up TLS from options.
+//
+// With certificate files or PEM values, the certificate is loaded right away
+// so that a missing or invalid certificate fails at startup; so is the client
+// CA file. With ACME, certificates are obtained on the first handshake for
+// each domain.
+//
+// Parameters:
+//   - opts: Certificate source and settings
+//
+// Returns:
+//   - *TLS: TLS setup to use with an http.Server
+//   - error: If the options are invalid or the certificate can't be loaded
+//
+// Example:
+//
+//	t, err := https.New(https.Options{
+//	    ACMEDomains: []string{"inventory.example.com"},
+//	    ACMEEmail:   "ops@example.com",
+//	})
+func New(opts Options) (*TLS, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if !opts.Enabled() {
+		return nil, errors.New("no TLS certificate source configured")
+	}
+
+	minVersion := opts.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+
+	t := &TLS{}
+	files := &certReloader{certFile: opts.CertFile, keyFile: opts.KeyFile, clientCAFile: opts.ClientCAFile, now: time.Now}
+	if len(files.files()) > 0 {
+		if err := files.load(); err != nil {
+			return nil, err
+		}
+		t.files = files
+	}
+
+	switch {
+	case len(opts.ACMEDomains) > 0:
+		cacheDir := opts.ACMECacheDir
+		if cacheDir == "" {
+			cacheDir = DefaultCacheDir
+		}
+		t.manager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cacheDir),
+			HostPolicy: autocert.HostWhitelist(opts.ACMEDomains...),
+			Email:      opts.ACMEEmail,
+		}
+		if opts.ACMEDirectoryURL != "" {
+			t.manager.Client = &acme.Client{DirectoryURL: opts.ACMEDirectoryURL}
+		}
+		t.config = t.manager.TLSConfig()
+		t.config.MinVersion = minVersion
+	case opts.CertPEM != "":
+		cert, err := tls.X509KeyPair([]byte(opts.CertPEM), []byte(opts.KeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		t.config = &tls.Config{
+			MinVersion:   minVersion,
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"h2", "http/1.1"},
+		}
+	default:
+		t.config = &tls.Config{
+			MinVersion:     minVersion,
+			GetCertificate: files.getCertificate,
+			NextProtos:     []string{"h2", "http/1.1"},
+		}
+	}
+
+	if opts.ClientCAFile != "" {
+		t.config.ClientAuth = tls.RequireAndVerifyClientCert
+		if opts.ClientAuth == ClientAuthOptional {
+			t.config.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		// Each handshake gets the current CAs, so a reloaded CA file applies
+		// to new connections
+		base := t.config
+		t.config = base.Clone()
+		t.config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := base.Clone()
+			config.ClientCAs = files.getClientCAs()
+			return config, nil
+		}
+	}
+	return t, nil
+}
+
+// TLSConfig returns the configuration for http.Server.TLSConfig
+func (t *TLS) TLSConfig() *tls.Config {
+	return t.config
+}
+
+// Reload re-reads the certificate and client CA files right away instead of
+// waiting for the next check, e.g. on SIGHUP. If they can't be loaded, the
+// previous ones stay in use. It does nothing when no files are used.
+func (t *TLS) Reload() error {
+	if t.files == nil {
+		return nil
+	}
+	return t.files.load()
+}
+
+// ClientCertificate returns the verified client certificate of a request, or
+// nil when the request is not over TLS or the client presented none.
+//
+// Example:
+//
+//	if cert := https.ClientCertificate(r); cert != nil {
+//	    logger.Info("request from", "client", cert.Subject.CommonName)
+//	}
+func ClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// RedirectHandler returns the handler of the plain HTTP listener.
+//
+// Requests are redirected to the same host and path over HTTPS with
+// 308 Permanent Redirect, which keeps the method and body of API calls. With
+// ACME, HTTP-01 challenges are answered before redirecting.
+//
+// Parameters:
+//   - httpsPort: Port of the HTTPS server, omitted from the URL when 443
+//
+// Returns:
+//   - http.Handler: Handler for the HTTP server
+func (t *TLS) RedirectHandler(httpsPort int) http.Handler {
+	redirect := RedirectHandler(httpsPort)
+	if t.manager != nil {
+		return t.manager.HTTPHandler(redirect)
+	}
+	return redirect
+}
+
+// RedirectHandler returns a handler redirecting every request to HTTPS on
+// httpsPort (omitted from the URL when 443).
+func RedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		} else if net.ParseIP(host) != nil && net.ParseIP(host).To4() == nil {
+			host = "[" + host + "]"
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// certReloader serves a certificate and client CAs from files, re-reading
+// them when they change. Either may be unset.
+type certReloader struct {
+	certFile, keyFile string
+	clientCAFile      string
+	now               func() time.Time
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// files returns the files that are set
+func (c *certReloader) files() []string {
+	var files []string
+	for _, file := range []string{c.certFile, c.keyFile, c.clientCAFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// load reads the files
+func (c *certReloader) load() error {
+	var cert *tls.Certificate
+	if c.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		cert = &pair
+	}
+	var clientCAs *x509.CertPool
+	if c.clientCAFile != "" {
+		data, err := os.ReadFile(c.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("failed to load TLS client CA: no certificates in %s", c.clientCAFile)
+		}
+	}
+	modTime, err := c.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = cert
+	c.clientCAs = clientCAs
+	c.modTime = modTime
+	c.checkedAt = c.now()
+	return nil
+}
+
+// latestModTime returns the newest modification time of the files
+func (c *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range c.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read TLS certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// refresh reloads the files if they changed since the last check, at most
+// once per reloadInterval. If they can't be loaded (e.g. halfway through a
+// rotation), the previous ones are kept.
+func (c *certReloader) refresh() {
+	c.mu.RLock()
+	stale := c.now().Sub(c.checkedAt) > reloadInterval
+	c.mu.RUnlock()
+	if !stale {
+		return
+	}
+
+	c.mu.Lock()
+	c.checkedAt = c.now()
+	modTime := c.modTime
+	c.mu.Unlock()
+
+	if latest, err := c.latestModTime(); err == nil && latest.After(modTime) {
+		_ = c.load()
+	}
+}
+
+// getCertificate implements tls.Config.GetCertificate
+func (c *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.refresh()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// getClientCAs returns the current client CAs
+func (c *certReloader) getClientCAs() *x509.CertPool {
+	c.refresh()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.clientCAs
+}
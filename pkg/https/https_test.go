@@ -0,0 +1,311 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package https
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate for commonName and returns its paths
+func writeCert(t *testing.T, dir, commonName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"none", Options{}, false},
+		{"files", Options{CertFile: "c", KeyFile: "k"}, false},
+		{"acme", Options{ACMEDomains: []string{"example.com"}}, false},
+		{"cert without key", Options{CertFile: "c"}, true},
+		{"files and acme", Options{CertFile: "c", KeyFile: "k", ACMEDomains: []string{"example.com"}}, true},
+		{"pem", Options{CertPEM: "c", KeyPEM: "k"}, false},
+		{"pem without key", Options{CertPEM: "c"}, true},
+		{"files and pem", Options{CertFile: "c", KeyFile: "k", CertPEM: "c", KeyPEM: "k"}, true},
+		{"mtls", Options{CertFile: "c", KeyFile: "k", ClientCAFile: "ca"}, false},
+		{"optional mtls", Options{CertFile: "c", KeyFile: "k", ClientCAFile: "ca", ClientAuth: ClientAuthOptional}, false},
+		{"client ca without certificate", Options{ClientCAFile: "ca"}, true},
+		{"client auth without ca", Options{CertFile: "c", KeyFile: "k", ClientAuth: ClientAuthRequire}, true},
+		{"unknown client auth", Options{CertFile: "c", KeyFile: "k", ClientCAFile: "ca", ClientAuth: "sometimes"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if _, err := New(Options{}); err == nil {
+		t.Error("expected New to fail without a certificate source")
+	}
+}
+
+func TestNewWithFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "server")
+
+	tlsSetup, err := New(Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := tlsSetup.TLSConfig()
+	if config.MinVersion != tls.VersionTLS12 {
+		t.Errorf("MinVersion = %x, want TLS 1.2", config.MinVersion)
+	}
+	cert, err := config.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil || commonName(t, cert) != "server" {
+		t.Fatalf("GetCertificate = %v, %v", cert, err)
+	}
+
+	if _, err := New(Options{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile}); err == nil {
+		t.Error("expected New to fail for a missing certificate")
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "first")
+	now := time.Now()
+	certs := &certReloader{certFile: certFile, keyFile: keyFile, now: func() time.Time { return now }}
+	if err := certs.load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate the certificate; it is picked up after the reload interval
+	writeCert(t, dir, "second")
+	later := time.Now().Add(time.Minute)
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.Chtimes(file, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cert, _ := certs.getCertificate(nil); commonName(t, cert) != "first" {
+		t.Error("certificate reloaded before the reload interval")
+	}
+	now = now.Add(2 * reloadInterval)
+	if cert, _ := certs.getCertificate(nil); commonName(t, cert) != "second" {
+		t.Error("rotated certificate was not reloaded")
+	}
+
+	// A broken rotation keeps the previous certificate
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(time.Minute)
+	os.Chtimes(certFile, later, later) //nolint:errcheck
+	now = now.Add(2 * reloadInterval)
+	if cert, err := certs.getCertificate(nil); err != nil || commonName(t, cert) != "second" {
+		t.Errorf("expected the previous certificate, got %v", err)
+	}
+}
+
+func TestNewWithPEM(t *testing.T) {
+	certFile, keyFile := writeCert(t, t.TempDir(), "from-env")
+	certPEM, _ := os.ReadFile(certFile)
+	keyPEM, _ := os.ReadFile(keyFile)
+
+	tlsSetup, err := New(Options{CertPEM: string(certPEM), KeyPEM: string(keyPEM)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := tlsSetup.TLSConfig()
+	if len(config.Certificates) != 1 || commonName(t, &config.Certificates[0]) != "from-env" {
+		t.Error("expected the PEM certificate")
+	}
+	if err := tlsSetup.Reload(); err != nil {
+		t.Errorf("Reload() without files = %v", err)
+	}
+
+	if _, err := New(Options{CertPEM: string(certPEM), KeyPEM: "not a key"}); err == nil {
+		t.Error("expected New to fail for an invalid key")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	serverCert, serverKey := writeCert(t, t.TempDir(), "server")
+	caDir := t.TempDir()
+	clientCertFile, clientKeyFile := writeCert(t, caDir, "node-1")
+	clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The self-signed client certificate is its own CA
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	data, _ := os.ReadFile(clientCertFile)
+	if err := os.WriteFile(caFile, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	otherCertFile, otherKeyFile := writeCert(t, t.TempDir(), "stranger")
+	otherCert, err := tls.LoadX509KeyPair(otherCertFile, otherKeyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(t *testing.T, clientAuth string, certs ...tls.Certificate) (string, error) {
+		tlsSetup, err := New(Options{CertFile: serverCert, KeyFile: serverKey, ClientCAFile: caFile, ClientAuth: clientAuth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cert := ClientCertificate(r); cert != nil {
+				_, _ = w.Write([]byte(cert.Subject.CommonName))
+			}
+		}))
+		server.TLS = tlsSetup.TLSConfig()
+		server.StartTLS()
+		defer server.Close()
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true, //nolint:gosec // the server certificate is self-signed
+			Certificates:       certs,
+		}}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), nil
+	}
+
+	if got, err := get(t, "", clientCert); err != nil || got != "node-1" {
+		t.Errorf("trusted client: got %q, %v", got, err)
+	}
+	if _, err := get(t, ""); err == nil {
+		t.Error("expected a client without a certificate to be rejected")
+	}
+	if _, err := get(t, "", otherCert); err == nil {
+		t.Error("expected a client with an untrusted certificate to be rejected")
+	}
+	if got, err := get(t, ClientAuthOptional); err != nil || got != "" {
+		t.Errorf("optional, no certificate: got %q, %v", got, err)
+	}
+	if got, err := get(t, ClientAuthOptional, clientCert); err != nil || got != "node-1" {
+		t.Errorf("optional, trusted client: got %q, %v", got, err)
+	}
+
+	if _, err := New(Options{CertFile: serverCert, KeyFile: serverKey, ClientCAFile: serverKey}); err == nil {
+		t.Error("expected New to fail for a client CA file without certificates")
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "first")
+	tlsSetup, err := New(Options{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload doesn't wait for the reload interval
+	writeCert(t, dir, "second")
+	if err := tlsSetup.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if cert, _ := tlsSetup.TLSConfig().GetCertificate(&tls.ClientHelloInfo{}); commonName(t, cert) != "second" {
+		t.Error("expected the reloaded certificate")
+	}
+
+	if err := os.WriteFile(certFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := tlsSetup.Reload(); err == nil {
+		t.Error("expected Reload to fail for an invalid certificate")
+	}
+	if cert, _ := tlsSetup.TLSConfig().GetCertificate(&tls.ClientHelloInfo{}); commonName(t, cert) != "second" {
+		t.Error("expected the previous certificate after a failed reload")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		port int
+		url  string
+		want string
+	}{
+		{8443, "http://api.example.com:8080/devices?limit=5", "https://api.example.com:8443/devices?limit=5"},
+		{443, "http://api.example.com/devices", "https://api.example.com/devices"},
+		{443, "http://[::1]:8080/health", "https://[::1]/health"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		RedirectHandler(tt.port).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.url, nil))
+		if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != tt.want {
+			t.Errorf("%s: got %d %s, want 308 %s", tt.url, rec.Code, rec.Header().Get("Location"), tt.want)
+		}
+	}
+}
+
+func TestACMERedirectHandlerServesChallenges(t *testing.T) {
+	tlsSetup, err := New(Options{ACMEDomains: []string{"api.example.com"}, ACMECacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := tlsSetup.RedirectHandler(443)
+
+	// Unknown challenge tokens are answered by autocert, not redirected
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://api.example.com/.well-known/acme-challenge/token", nil))
+	if rec.Code == http.StatusPermanentRedirect {
+		t.Error("ACME challenge was redirected")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://api.example.com/devices", nil))
+	if rec.Code != http.StatusPermanentRedirect {
+		t.Errorf("expected a redirect, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package i18n localizes error and validation messages returned by generated APIs.
+//
+// Messages are looked up in per-language catalogs keyed by:
+//   - an error code from pkg/errcode (e.g. "NOT_FOUND"), used for problem titles
+//   - "validation.<tag>" (e.g. "validation.required"), used for field validation messages
+//   - "validation.failed", used to prefix the detail of validation problems
+//
+// Message templates can reference {field}, {param}, {value} and {tag}:
+//
+//	{
+//	  "NOT_FOUND": "Nicht gefunden",
+//	  "validation.failed": "Validierung fehlgeschlagen",
+//	  "validation.required": "{field} ist erforderlich",
+//	  "validation.min": "{field} muss mindestens {param} sein"
+//	}
+//
+// English is built in: when a message is missing from a catalog, the English
+// text produced by pkg/errcode and pkg/validation is used unchanged. Dynamic
+// error details (e.g. "Device not found: dev-123") are not translated.
+//
+// Usage:
+//
+//	i18n.Register("de", i18n.Messages{"NOT_FOUND": "Nicht gefunden"})
+//	// or: i18n.LoadDir("/etc/myapi/i18n") to load de.json, fr.json, ...
+//
+//	lang := i18n.Negotiate(r.Header.Get("Accept-Language")) // "de"
+//	problem = i18n.LocalizeProblem(lang, problem, err)
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/validation"
+)
+
+// DefaultLanguage is the language of built-in messages.
+const DefaultLanguage = "en"
+
+// Messages maps message keys to message templates.
+type Messages map[string]string
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]Messages{}
+)
+
+// Register adds messages for a language, replacing existing messages with
+// the same keys.
+//
+// Parameters:
+//   - lang: BCP 47 language tag (e.g., "de", "pt-BR"); matched case-insensitively
+//   - messages: Message templates keyed by error code or "validation.<tag>"
+func Register(lang string, messages Messages) {
+	lang = normalize(lang)
+	mu.Lock()
+	defer mu.Unlock()
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = Messages{}
+		catalogs[lang] = catalog
+	}
+	for key, msg := range messages {
+		catalog[key] = msg
+	}
+}
+
+// LoadDir registers every <lang>.json file in dir as the catalog for <lang>.
+//
+// Example layout:
+//
+//	i18n/
+//	  de.json
+//	  fr.json
+//	  pt-BR.json
+func LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read message catalog: %w", err)
+		}
+		var messages Messages
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("invalid message catalog %s: %w", file, err)
+		}
+		Register(strings.TrimSuffix(filepath.Base(file), ".json"), messages)
+	}
+	return nil
+}
+
+// Languages returns the supported languages, including DefaultLanguage.
+func Languages() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	langs := []string{DefaultLanguage}
+	for lang := range catalogs {
+		if lang != DefaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs[1:])
+	return langs
+}
+
+// Negotiate picks the best supported language for an Accept-Language header.
+//
+// Languages are tried in order of their q-values; a region-specific request
+// ("de-AT") falls back to its base language ("de"). DefaultLanguage is
+// returned when nothing matches.
+//
+// Example:
+//
+//	i18n.Negotiate("fr-CH, fr;q=0.9, de;q=0.8") // "fr" if registered, else "de", else "en"
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := normalize(fields[0])
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, c := range candidates {
+		if c.lang == "*" {
+			return DefaultLanguage
+		}
+		if supported(c.lang) {
+			return c.lang
+		}
+		if base := baseLanguage(c.lang); supported(base) {
+			return base
+		}
+	}
+	return DefaultLanguage
+}
+
+// Translate returns the message for key in lang, formatted with args.
+// Region-specific languages fall back to their base language.
+//
+// Returns:
+//   - string: The formatted message
+//   - bool: false if no catalog for lang has the key
+func Translate(lang, key string, args map[string]string) (string, bool) {
+	lang = normalize(lang)
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, l := range []string{lang, baseLanguage(lang)} {
+		if msg, ok := catalogs[l][key]; ok {
+			return format(msg, args), true
+		}
+	}
+	return "", false
+}
+
+// LocalizeFieldError returns the message of a validation error in lang.
+// The English message is returned if lang has no message for the tag.
+func LocalizeFieldError(lang string, fe validation.FieldError) string {
+	args := map[string]string{"field": fe.Field, "param": fe.Param, "value": fe.Value, "tag": fe.Tag}
+	if msg, ok := Translate(lang, "validation."+fe.Tag, args); ok {
+		return msg
+	}
+	return fe.Message
+}
+
+// LocalizeProblem translates the title of a problem document and, for
+// validation errors, its detail.
+//
+// Parameters:
+//   - lang: Language negotiated for the request
+//   - p: Problem built from err (see errcode.NewProblem)
+//   - err: The error being reported
+//
+// Returns:
+//   - errcode.Problem: Localized copy of p
+func LocalizeProblem(lang string, p errcode.Problem, err error) errcode.Problem {
+	if title, ok := Translate(lang, string(p.Code), nil); ok {
+		p.Title = title
+	}
+
+	var verrs validation.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return p
+	}
+	prefix, ok := Translate(lang, "validation.failed", nil)
+	if !ok {
+		prefix = "validation failed"
+	}
+	msgs := make([]string, len(verrs.Errors))
+	for i, fe := range verrs.Errors {
+		msgs[i] = LocalizeFieldError(lang, fe)
+	}
+	p.Detail = prefix + ": " + strings.Join(msgs, "; ")
+	p.Error = p.Detail
+	return p
+}
+
+// supported reports whether lang is built in or has a catalog. Callers hold mu.
+func supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok || lang == DefaultLanguage
+}
+
+// normalize lowercases a language tag and uses "-" as its separator.
+func normalize(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// baseLanguage strips the region from a language tag ("pt-br" -> "pt").
+func baseLanguage(lang string) string {
+	base, _, _ := strings.Cut(lang, "-")
+	return base
+}
+
+// format replaces {name} placeholders with args.
+func format(msg string, args map[string]string) string {
+	for name, value := range args {
+		msg = strings.ReplaceAll(msg, "{"+name+"}", value)
+	}
+	return msg
+}
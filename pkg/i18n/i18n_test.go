@@ -0,0 +1,111 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/validation"
+)
+
+func init() {
+	Register("de", Messages{
+		"NOT_FOUND":           "Nicht gefunden",
+		"VALIDATION_FAILED":   "Validierung fehlgeschlagen",
+		"validation.failed":   "Validierung fehlgeschlagen",
+		"validation.required": "{field} ist erforderlich",
+		"validation.min":      "{field} muss mindestens {param} sein",
+	})
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"de-AT", "de"},
+		{"fr-CH, fr;q=0.9, de;q=0.8", "de"},
+		{"de;q=0.5, en;q=0.9", "en"},
+		{"DE_de", "de"},
+		{"de;q=0, ja", "en"},
+		{"*", "en"},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizeProblem(t *testing.T) {
+	p := LocalizeProblem("de-at", errcode.NewProblem(http.StatusNotFound, errors.New("Device not found: dev-1")), nil)
+	if p.Title != "Nicht gefunden" || p.Detail != "Device not found: dev-1" {
+		t.Errorf("Unexpected problem: %+v", p)
+	}
+
+	verr := validation.ValidationErrors{Errors: []validation.FieldError{
+		{Field: "name", Tag: "required", Message: "name is required"},
+		{Field: "port", Tag: "min", Param: "1", Message: "port must be at least 1"},
+		{Field: "mac", Tag: "mac", Message: "mac must be a valid MAC address"},
+	}}
+	err := errcode.Wrap(errcode.ValidationFailed, fmt.Errorf("validation failed: %w", verr))
+	p = LocalizeProblem("de", errcode.NewProblem(http.StatusBadRequest, err), err)
+	want := "Validierung fehlgeschlagen: name ist erforderlich; port muss mindestens 1 sein; mac must be a valid MAC address"
+	if p.Detail != want || p.Error != want {
+		t.Errorf("Detail = %q, want %q", p.Detail, want)
+	}
+
+	// English keeps the built-in messages
+	p = LocalizeProblem("en", errcode.NewProblem(http.StatusBadRequest, err), err)
+	if p.Title != "Validation failed" || p.Detail != "validation failed: name is required; port must be at least 1; mac must be a valid MAC address" {
+		t.Errorf("Unexpected English problem: %+v", p)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "fr.json"), []byte(`{"NOT_FOUND": "Introuvable"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if msg, ok := Translate("fr-FR", "NOT_FOUND", nil); !ok || msg != "Introuvable" {
+		t.Errorf("Translate = %q, %v", msg, ok)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`[`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadDir(dir); err == nil {
+		t.Error("Expected error for invalid catalog")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var gotCtx, gotWriter string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCtx = FromContext(r.Context())
+		gotWriter = LanguageOf(w)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de-CH, en;q=0.5")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if gotCtx != "de" || gotWriter != "de" {
+		t.Errorf("Expected de, got context %q, writer %q", gotCtx, gotWriter)
+	}
+	if LanguageOf(httptest.NewRecorder()) != "" {
+		t.Error("Expected no language without the middleware")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package i18n
+
+import (
+	"context"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// Middleware negotiates the response language from the Accept-Language header.
+//
+// The language is stored in the request context (see FromContext) and on the
+// response writer (see LanguageOf), so error helpers that only receive the
+// writer can still localize their output.
+//
+// Example:
+//
+//	r.Group(func(r chi.Router) {
+//	    r.Use(i18n.Middleware)
+//	    r.Get("/devices/{uid}", GetDevice)
+//	})
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := Negotiate(r.Header.Get("Accept-Language"))
+		ctx := context.WithValue(r.Context(), contextKey{}, lang)
+		next.ServeHTTP(&languageWriter{ResponseWriter: w, lang: lang}, r.WithContext(ctx))
+	})
+}
+
+// FromContext returns the language negotiated by Middleware, or
+// DefaultLanguage if the middleware didn't run.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// LanguageOf returns the language negotiated by Middleware for a response,
+// or "" if the middleware didn't run. Writers wrapped by other middleware are
+// unwrapped through their Unwrap method.
+func LanguageOf(w http.ResponseWriter) string {
+	for w != nil {
+		if lw, ok := w.(*languageWriter); ok {
+			return lw.lang
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return ""
+		}
+		w = u.Unwrap()
+	}
+	return ""
+}
+
+// languageWriter carries the negotiated language with the response
+type languageWriter struct {
+	http.ResponseWriter
+	lang string
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController
+func (w *languageWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,338 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package informer keeps a local cache of resources in sync with a server,
+// in the manner of client-go informers, so reconcilers and dashboards read
+// resources without a request per read.
+//
+// An Informer opens a watch of a kind (GET /{resources}/watch on generated
+// servers), lists the resources, and then applies the changes the watch
+// streams. When the watch breaks it lists again, with a backoff, and
+// reports the differences to its handlers, so the cache converges on the
+// server's state whatever happened in between.
+//
+// Generated clients build informers with New<Kind>Informer:
+//
+//	devices := c.NewDeviceInformer(informer.Options{})
+//	devices.AddIndexer("rack", func(d *device.Device) []string {
+//	    return []string{d.Spec.Rack}
+//	})
+//	devices.AddEventHandler(informer.HandlerFuncs[*device.Device]{
+//	    OnAddFunc:    func(d *device.Device) { ... },
+//	    OnUpdateFunc: func(old, d *device.Device) { ... },
+//	    OnDeleteFunc: func(d *device.Device) { ... },
+//	})
+//	go devices.Run(ctx)
+//	if !devices.WaitForSync(ctx) {
+//	    return ctx.Err()
+//	}
+//	inRack, err := devices.Lister().ByIndex("rack", "R12")
+//
+// Objects handed out by listers and handlers are shared with the cache and
+// must not be modified.
+package informer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Default backoffs between attempts to list and watch
+const (
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// Object is a resource an informer can cache, keyed by its UID.
+type Object interface {
+	GetUID() string
+}
+
+// ListWatch lists and watches the resources of a kind.
+type ListWatch[T any] struct {
+	// List returns every resource
+	List func(ctx context.Context) ([]T, error)
+
+	// Watch opens a stream of the changes made after it returns
+	Watch func(ctx context.Context) (Stream[T], error)
+}
+
+// Options configures an Informer.
+type Options struct {
+	// MinBackoff is the wait before listing and watching again after the
+	// watch broke; DefaultMinBackoff when zero. The wait doubles on each
+	// failure to list or watch, up to MaxBackoff (DefaultMaxBackoff when
+	// zero).
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnError, if set, is called with the errors listing and watching,
+	// which the informer retries.
+	OnError func(error)
+}
+
+// Handler is notified of the changes to an informer's cache.
+type Handler[T any] interface {
+	OnAdd(obj T)
+	OnUpdate(oldObj, newObj T)
+	OnDelete(obj T)
+}
+
+// HandlerFuncs is a Handler calling the functions that are set.
+type HandlerFuncs[T any] struct {
+	OnAddFunc    func(obj T)
+	OnUpdateFunc func(oldObj, newObj T)
+	OnDeleteFunc func(obj T)
+}
+
+// OnAdd calls OnAddFunc if it is set
+func (h HandlerFuncs[T]) OnAdd(obj T) {
+	if h.OnAddFunc != nil {
+		h.OnAddFunc(obj)
+	}
+}
+
+// OnUpdate calls OnUpdateFunc if it is set
+func (h HandlerFuncs[T]) OnUpdate(oldObj, newObj T) {
+	if h.OnUpdateFunc != nil {
+		h.OnUpdateFunc(oldObj, newObj)
+	}
+}
+
+// OnDelete calls OnDeleteFunc if it is set
+func (h HandlerFuncs[T]) OnDelete(obj T) {
+	if h.OnDeleteFunc != nil {
+		h.OnDeleteFunc(obj)
+	}
+}
+
+// Informer caches the resources of a kind and keeps them in sync with a
+// server.
+type Informer[T Object] struct {
+	lw   ListWatch[T]
+	opts Options
+
+	store *store[T]
+
+	// dispatch serializes cache changes with the handlers they notify
+	dispatch sync.Mutex
+	handlers []Handler[T]
+
+	syncOnce sync.Once
+	synced   chan struct{}
+}
+
+// New returns an informer over lw; it does nothing until Run.
+//
+// Parameters:
+//   - lw: Lists and watches the resources
+//   - opts: Backoffs and error reporting
+//
+// Returns:
+//   - *Informer[T]: An informer with an empty cache
+func New[T Object](lw ListWatch[T], opts Options) *Informer[T] {
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultMinBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = opts.MinBackoff
+	}
+	return &Informer[T]{
+		lw:     lw,
+		opts:   opts,
+		store:  newStore[T](),
+		synced: make(chan struct{}),
+	}
+}
+
+// AddIndexer adds an index of the cache, read with Lister().ByIndex. fn
+// returns the values a resource is indexed under. Resources already cached
+// are indexed right away.
+//
+// Returns:
+//   - error: If an index of the same name exists
+func (i *Informer[T]) AddIndexer(name string, fn IndexFunc[T]) error {
+	return i.store.addIndexer(name, fn)
+}
+
+// AddEventHandler registers a handler of the changes to the cache. Handlers
+// added once resources are cached get an OnAdd for each of them first.
+//
+// Handlers run one at a time, on the goroutine calling Run, and should
+// return quickly; they may read the cache, but must not add handlers.
+func (i *Informer[T]) AddEventHandler(h Handler[T]) {
+	i.dispatch.Lock()
+	defer i.dispatch.Unlock()
+	for _, obj := range i.store.list() {
+		h.OnAdd(obj)
+	}
+	i.handlers = append(i.handlers, h)
+}
+
+// Lister returns a reader of the cache.
+func (i *Informer[T]) Lister() Lister[T] {
+	return Lister[T]{store: i.store}
+}
+
+// HasSynced reports whether the resources were listed once, so the cache
+// holds every resource that existed then.
+func (i *Informer[T]) HasSynced() bool {
+	select {
+	case <-i.synced:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForSync blocks until the informer has synced (see HasSynced) and
+// reports whether it did before ctx was done.
+func (i *Informer[T]) WaitForSync(ctx context.Context) bool {
+	select {
+	case <-i.synced:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Run lists and watches the resources until ctx is done, keeping the cache
+// in sync. It is called once, usually on a goroutine of its own.
+//
+// Returns:
+//   - error: ctx.Err()
+func (i *Informer[T]) Run(ctx context.Context) error {
+	backoff := i.opts.MinBackoff
+	for {
+		listed, err := i.listAndWatch(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if listed {
+			backoff = i.opts.MinBackoff
+		}
+		if i.opts.OnError != nil && err != nil {
+			i.opts.OnError(err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if !listed {
+			backoff = min(backoff*2, i.opts.MaxBackoff)
+		}
+	}
+}
+
+// ErrWatchEnded is reported to Options.OnError when the server ended a
+// watch, such as when its write timeout expired; the informer lists and
+// watches again.
+var ErrWatchEnded = errors.New("watch ended by the server")
+
+// listAndWatch opens a watch, lists the resources into the cache, and
+// applies the changes streamed until the watch breaks. listed reports
+// whether the list succeeded.
+func (i *Informer[T]) listAndWatch(ctx context.Context) (listed bool, err error) {
+	// Watch first, so no change made while listing is missed. Changes the
+	// list already holds are applied again, in order, and end in the same
+	// state.
+	stream, err := i.lw.Watch(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close()
+	stop := context.AfterFunc(ctx, func() { stream.Close() })
+	defer stop()
+
+	objs, err := i.lw.List(ctx)
+	if err != nil {
+		return false, err
+	}
+	i.replace(objs)
+	i.syncOnce.Do(func() { close(i.synced) })
+
+	for {
+		event, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			return true, ErrWatchEnded
+		}
+		if err != nil {
+			return true, err
+		}
+		switch event.Type {
+		case storage.WatchSaved:
+			i.save(event.Object)
+		case storage.WatchDeleted:
+			i.delete(event.UID)
+		}
+	}
+}
+
+// replace makes the cache hold objs, notifying handlers of the differences
+func (i *Informer[T]) replace(objs []T) {
+	i.dispatch.Lock()
+	defer i.dispatch.Unlock()
+
+	listed := make(map[string]bool, len(objs))
+	for _, obj := range objs {
+		listed[obj.GetUID()] = true
+		i.saveLocked(obj)
+	}
+	for _, obj := range i.store.list() {
+		if uid := obj.GetUID(); !listed[uid] {
+			i.deleteLocked(uid)
+		}
+	}
+}
+
+// save caches a created or replaced resource
+func (i *Informer[T]) save(obj T) {
+	i.dispatch.Lock()
+	defer i.dispatch.Unlock()
+	i.saveLocked(obj)
+}
+
+func (i *Informer[T]) saveLocked(obj T) {
+	old, existed := i.store.put(obj)
+	switch {
+	case !existed:
+		for _, h := range i.handlers {
+			h.OnAdd(obj)
+		}
+	case !reflect.DeepEqual(old, obj):
+		for _, h := range i.handlers {
+			h.OnUpdate(old, obj)
+		}
+	}
+}
+
+// delete removes a deleted resource from the cache
+func (i *Informer[T]) delete(uid string) {
+	i.dispatch.Lock()
+	defer i.dispatch.Unlock()
+	i.deleteLocked(uid)
+}
+
+func (i *Informer[T]) deleteLocked(uid string) {
+	old, existed := i.store.remove(uid)
+	if !existed {
+		return
+	}
+	for _, h := range i.handlers {
+		h.OnDelete(old)
+	}
+}
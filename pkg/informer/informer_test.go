@@ -0,0 +1,249 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package informer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+type testObj struct {
+	UID  string `json:"uid"`
+	Rack string `json:"rack"`
+}
+
+func (o *testObj) GetUID() string { return o.UID }
+
+// fakeServer lists its objects and streams the events sent to it
+type fakeServer struct {
+	mu      sync.Mutex
+	objs    map[string]*testObj
+	streams chan *fakeStream
+}
+
+func newFakeServer(objs ...*testObj) *fakeServer {
+	s := &fakeServer{objs: make(map[string]*testObj), streams: make(chan *fakeStream, 10)}
+	for _, obj := range objs {
+		s.objs[obj.UID] = obj
+	}
+	return s
+}
+
+func (s *fakeServer) listWatch() ListWatch[*testObj] {
+	return ListWatch[*testObj]{
+		List: func(ctx context.Context) ([]*testObj, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			var objs []*testObj
+			for _, obj := range s.objs {
+				objs = append(objs, obj)
+			}
+			return objs, nil
+		},
+		Watch: func(ctx context.Context) (Stream[*testObj], error) {
+			stream := &fakeStream{events: make(chan Event[*testObj], 10), done: make(chan struct{})}
+			s.streams <- stream
+			return stream, nil
+		},
+	}
+}
+
+func (s *fakeServer) set(objs ...*testObj) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objs = make(map[string]*testObj)
+	for _, obj := range objs {
+		s.objs[obj.UID] = obj
+	}
+}
+
+// nextStream returns the stream of the informer's latest watch
+func (s *fakeServer) nextStream(t *testing.T) *fakeStream {
+	t.Helper()
+	select {
+	case stream := <-s.streams:
+		return stream
+	case <-time.After(5 * time.Second):
+		t.Fatal("The informer didn't watch")
+		return nil
+	}
+}
+
+type fakeStream struct {
+	events chan Event[*testObj]
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *fakeStream) Next() (Event[*testObj], error) {
+	select {
+	case event, ok := <-s.events:
+		if !ok {
+			return Event[*testObj]{}, io.EOF
+		}
+		return event, nil
+	case <-s.done:
+		return Event[*testObj]{}, errors.New("stream closed")
+	}
+}
+
+func (s *fakeStream) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return nil
+}
+
+// recorder records the notifications of a handler as "add dev-1" etc.
+type recorder struct {
+	notes chan string
+}
+
+func newRecorder() *recorder {
+	return &recorder{notes: make(chan string, 100)}
+}
+
+func (r *recorder) handler() Handler[*testObj] {
+	return HandlerFuncs[*testObj]{
+		OnAddFunc:    func(obj *testObj) { r.notes <- "add " + obj.UID },
+		OnUpdateFunc: func(_, obj *testObj) { r.notes <- "update " + obj.UID },
+		OnDeleteFunc: func(obj *testObj) { r.notes <- "delete " + obj.UID },
+	}
+}
+
+// expect waits for n notifications, compared in any order
+func (r *recorder) expect(t *testing.T, want ...string) {
+	t.Helper()
+	var got []string
+	for range want {
+		select {
+		case note := <-r.notes:
+			got = append(got, note)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %v, got %v", want, got)
+		}
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected notifications %v, got %v", want, got)
+		}
+	}
+}
+
+func uids(objs []*testObj) []string {
+	var out []string
+	for _, obj := range objs {
+		out = append(out, obj.UID)
+	}
+	return out
+}
+
+func TestInformerSyncsAndWatches(t *testing.T) {
+	server := newFakeServer(&testObj{UID: "dev-1", Rack: "R1"}, &testObj{UID: "dev-2", Rack: "R2"})
+	inf := New(server.listWatch(), Options{MinBackoff: time.Millisecond})
+	if err := inf.AddIndexer("rack", func(obj *testObj) []string { return []string{obj.Rack} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := inf.AddIndexer("rack", func(obj *testObj) []string { return nil }); err == nil {
+		t.Error("Expected an error adding an index twice")
+	}
+	rec := newRecorder()
+	inf.AddEventHandler(rec.handler())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- inf.Run(ctx) }()
+
+	stream := server.nextStream(t)
+	if !inf.WaitForSync(ctx) || !inf.HasSynced() {
+		t.Fatal("The informer didn't sync")
+	}
+	rec.expect(t, "add dev-1", "add dev-2")
+	if got := uids(inf.Lister().List()); len(got) != 2 || got[0] != "dev-1" || got[1] != "dev-2" {
+		t.Errorf("Expected dev-1 and dev-2 cached, got %v", got)
+	}
+
+	stream.events <- Event[*testObj]{Type: storage.WatchSaved, UID: "dev-3", Object: &testObj{UID: "dev-3", Rack: "R1"}}
+	stream.events <- Event[*testObj]{Type: storage.WatchSaved, UID: "dev-1", Object: &testObj{UID: "dev-1", Rack: "R2"}}
+	stream.events <- Event[*testObj]{Type: storage.WatchDeleted, UID: "dev-2"}
+	rec.expect(t, "add dev-3", "update dev-1", "delete dev-2")
+
+	lister := inf.Lister()
+	if obj, ok := lister.Get("dev-1"); !ok || obj.Rack != "R2" {
+		t.Errorf("Expected dev-1 in R2, got %+v", obj)
+	}
+	if _, ok := lister.Get("dev-2"); ok {
+		t.Error("Expected dev-2 to be deleted")
+	}
+	r1, err := lister.ByIndex("rack", "R1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := uids(r1); len(got) != 1 || got[0] != "dev-3" {
+		t.Errorf("Expected dev-3 in R1, got %v", got)
+	}
+	if _, err := lister.ByIndex("room", "A"); err == nil {
+		t.Error("Expected an error for an unknown index")
+	}
+
+	// Saving an unchanged resource notifies nobody
+	stream.events <- Event[*testObj]{Type: storage.WatchSaved, UID: "dev-3", Object: &testObj{UID: "dev-3", Rack: "R1"}}
+
+	// Late handlers are told about the cached resources
+	late := newRecorder()
+	inf.AddEventHandler(late.handler())
+	late.expect(t, "add dev-1", "add dev-3")
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected Run to return context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return")
+	}
+	select {
+	case note := <-rec.notes:
+		t.Errorf("Unexpected notification %q", note)
+	default:
+	}
+}
+
+func TestInformerRelistsAfterWatchEnds(t *testing.T) {
+	server := newFakeServer(&testObj{UID: "dev-1"}, &testObj{UID: "dev-2"})
+	errs := make(chan error, 10)
+	inf := New(server.listWatch(), Options{MinBackoff: time.Millisecond, OnError: func(err error) { errs <- err }})
+	rec := newRecorder()
+	inf.AddEventHandler(rec.handler())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go inf.Run(ctx)
+
+	stream := server.nextStream(t)
+	rec.expect(t, "add dev-1", "add dev-2")
+
+	// Changes made while the watch was down are found by the new list
+	server.set(&testObj{UID: "dev-2", Rack: "R9"}, &testObj{UID: "dev-4"})
+	close(stream.events)
+	server.nextStream(t)
+	rec.expect(t, "delete dev-1", "update dev-2", "add dev-4")
+
+	if err := <-errs; !errors.Is(err, ErrWatchEnded) {
+		t.Errorf("Expected ErrWatchEnded to be reported, got %v", err)
+	}
+	if got := uids(inf.Lister().List()); len(got) != 2 || got[0] != "dev-2" || got[1] != "dev-4" {
+		t.Errorf("Expected dev-2 and dev-4 cached, got %v", got)
+	}
+}
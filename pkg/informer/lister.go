@@ -0,0 +1,161 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package informer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// IndexFunc returns the values a resource is indexed under, such as its
+// rack or the UID of its parent. Resources may have any number of values.
+type IndexFunc[T any] func(obj T) []string
+
+// Lister reads an informer's cache.
+type Lister[T Object] struct {
+	store *store[T]
+}
+
+// List returns the cached resources, in UID order.
+func (l Lister[T]) List() []T {
+	return l.store.list()
+}
+
+// Get returns the cached resource with a UID, and whether there is one.
+func (l Lister[T]) Get(uid string) (T, bool) {
+	return l.store.get(uid)
+}
+
+// ByIndex returns the cached resources indexed under value in an index
+// added with AddIndexer, in UID order.
+//
+// Returns:
+//   - []T: The resources indexed under value
+//   - error: If there is no index named name
+func (l Lister[T]) ByIndex(name, value string) ([]T, error) {
+	return l.store.byIndex(name, value)
+}
+
+// store is the indexed cache of an informer
+type store[T Object] struct {
+	mu       sync.RWMutex
+	items    map[string]T
+	indexers map[string]IndexFunc[T]
+	indices  map[string]map[string]map[string]struct{} // index -> value -> UIDs
+}
+
+func newStore[T Object]() *store[T] {
+	return &store[T]{
+		items:    make(map[string]T),
+		indexers: make(map[string]IndexFunc[T]),
+		indices:  make(map[string]map[string]map[string]struct{}),
+	}
+}
+
+func (s *store[T]) addIndexer(name string, fn IndexFunc[T]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.indexers[name]; ok {
+		return fmt.Errorf("index %q already exists", name)
+	}
+	s.indexers[name] = fn
+	s.indices[name] = make(map[string]map[string]struct{})
+	for uid, obj := range s.items {
+		s.index(name, uid, obj)
+	}
+	return nil
+}
+
+// put caches obj, returning the resource it replaced
+func (s *store[T]) put(obj T) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	uid := obj.GetUID()
+	old, existed := s.items[uid]
+	if existed {
+		s.unindex(uid, old)
+	}
+	s.items[uid] = obj
+	for name := range s.indexers {
+		s.index(name, uid, obj)
+	}
+	return old, existed
+}
+
+// remove drops a resource, returning it
+func (s *store[T]) remove(uid string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.items[uid]
+	if existed {
+		s.unindex(uid, old)
+		delete(s.items, uid)
+	}
+	return old, existed
+}
+
+func (s *store[T]) get(uid string) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.items[uid]
+	return obj, ok
+}
+
+func (s *store[T]) list() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	uids := make([]string, 0, len(s.items))
+	for uid := range s.items {
+		uids = append(uids, uid)
+	}
+	return s.collect(uids)
+}
+
+func (s *store[T]) byIndex(name, value string) ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	index, ok := s.indices[name]
+	if !ok {
+		return nil, fmt.Errorf("no index named %q", name)
+	}
+	uids := make([]string, 0, len(index[value]))
+	for uid := range index[value] {
+		uids = append(uids, uid)
+	}
+	return s.collect(uids), nil
+}
+
+// collect returns the items of uids in UID order; s.mu is held
+func (s *store[T]) collect(uids []string) []T {
+	sort.Strings(uids)
+	objs := make([]T, len(uids))
+	for i, uid := range uids {
+		objs[i] = s.items[uid]
+	}
+	return objs
+}
+
+// index adds obj to an index; s.mu is held
+func (s *store[T]) index(name, uid string, obj T) {
+	for _, value := range s.indexers[name](obj) {
+		if s.indices[name][value] == nil {
+			s.indices[name][value] = make(map[string]struct{})
+		}
+		s.indices[name][value][uid] = struct{}{}
+	}
+}
+
+// unindex removes obj from every index; s.mu is held
+func (s *store[T]) unindex(uid string, obj T) {
+	for name, fn := range s.indexers {
+		for _, value := range fn(obj) {
+			delete(s.indices[name][value], uid)
+			if len(s.indices[name][value]) == 0 {
+				delete(s.indices[name], value)
+			}
+		}
+	}
+}
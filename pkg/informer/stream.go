@@ -0,0 +1,91 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package informer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// ContentType is the media type of watch responses: one WatchEvent per
+// line (newline-delimited JSON).
+const ContentType = "application/x-ndjson"
+
+// WatchEvent is a line of a watch response, as written by generated
+// servers:
+//
+//	{"type":"Saved","uid":"dev-1a2b3c4d","object":{"apiVersion":"v1","kind":"Device",...}}
+//	{"type":"Deleted","uid":"dev-1a2b3c4d"}
+type WatchEvent struct {
+	Type storage.WatchEventType `json:"type"`
+	UID  string                 `json:"uid"`
+	// Object is the saved resource; omitted for deletions
+	Object json.RawMessage `json:"object,omitempty"`
+}
+
+// Event is a change to a resource of type T received from a watch.
+type Event[T any] struct {
+	Type storage.WatchEventType
+	UID  string
+	// Object is the saved resource; the zero value for deletions
+	Object T
+}
+
+// Stream is a watch: the changes to resources made after it was opened, in
+// the order they were made.
+type Stream[T any] interface {
+	// Next blocks until the next change and returns it. It returns io.EOF
+	// when the server ended the watch, or another error when the
+	// connection failed or Close was called.
+	Next() (Event[T], error)
+
+	// Close ends the watch, unblocking Next.
+	Close() error
+}
+
+// NewStream returns a Stream reading the WatchEvent lines of a watch
+// response body. Closing the stream closes body.
+func NewStream[T any](body io.ReadCloser) Stream[T] {
+	return &ndjsonStream[T]{body: body, decoder: json.NewDecoder(body)}
+}
+
+// ndjsonStream decodes the events of a watch response body
+type ndjsonStream[T any] struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+}
+
+func (s *ndjsonStream[T]) Next() (Event[T], error) {
+	for {
+		var wire WatchEvent
+		if err := s.decoder.Decode(&wire); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return Event[T]{}, fmt.Errorf("watch ended mid-event: %w", err)
+			}
+			return Event[T]{}, err
+		}
+
+		event := Event[T]{Type: wire.Type, UID: wire.UID}
+		switch wire.Type {
+		case storage.WatchSaved:
+			if err := json.Unmarshal(wire.Object, &event.Object); err != nil {
+				return Event[T]{}, fmt.Errorf("failed to decode %s: %w", wire.UID, err)
+			}
+		case storage.WatchDeleted:
+		default:
+			// Event types added by newer servers
+			continue
+		}
+		return event, nil
+	}
+}
+
+func (s *ndjsonStream[T]) Close() error {
+	return s.body.Close()
+}
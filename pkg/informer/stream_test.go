@@ -0,0 +1,51 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package informer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+func TestStreamDecodesEvents(t *testing.T) {
+	body := `{"type":"Saved","uid":"dev-1","object":{"uid":"dev-1","rack":"R1"}}
+{"type":"Bookmark","uid":""}
+{"type":"Deleted","uid":"dev-1"}
+`
+	stream := NewStream[*testObj](io.NopCloser(strings.NewReader(body)))
+	defer stream.Close()
+
+	saved, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if saved.Type != storage.WatchSaved || saved.UID != "dev-1" || saved.Object == nil || saved.Object.Rack != "R1" {
+		t.Errorf("Unexpected saved event: %+v", saved)
+	}
+
+	// Unknown event types are skipped
+	deleted, err := stream.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if deleted.Type != storage.WatchDeleted || deleted.UID != "dev-1" || deleted.Object != nil {
+		t.Errorf("Unexpected deleted event: %+v", deleted)
+	}
+
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF at the end of the stream, got %v", err)
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	stream := NewStream[*testObj](io.NopCloser(strings.NewReader(`{"type":"Saved","uid":"dev-1","obj`)))
+	if _, err := stream.Next(); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("Expected an error for a truncated event, got %v", err)
+	}
+}
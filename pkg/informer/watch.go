@@ -0,0 +1,144 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package informer
+
+import (
+	"context"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// DefaultWatchBuffer is the default capacity of the channel of Watch
+const DefaultWatchBuffer = 100
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Options configures the reconnections: their backoffs and the
+	// reporting of their errors
+	Options
+
+	// SendInitial, if set, starts the channel with a Saved event per
+	// resource existing when the watch started
+	SendInitial bool
+
+	// Buffer is the capacity of the channel; DefaultWatchBuffer when zero
+	Buffer int
+}
+
+// Watch streams the changes to the resources of lw on a channel until ctx
+// is done, when the channel is closed.
+//
+// When the watch breaks, Watch reconnects with a backoff and resumes where
+// it left off: servers keep no history to replay, so it lists the
+// resources and sends the differences from the last state it saw as
+// events. A resource changed several times while the watch was down gets
+// one event for its latest state. Deleted events carry the last state seen
+// of the resource.
+//
+// Watch keeps the latest state of every resource to compute those
+// differences, as an Informer does. Events are sent in order; a receiver
+// falling behind the buffer holds further events up.
+//
+// Parameters:
+//   - ctx: Ends the watch
+//   - lw: Lists and watches the resources
+//   - opts: Reconnection backoffs, initial events and buffer size
+//
+// Returns:
+//   - <-chan Event[T]: The changes, in order
+//   - error: The error of the first attempt to watch and list; later
+//     errors are reported to opts.OnError and retried
+func Watch[T Object](ctx context.Context, lw ListWatch[T], opts WatchOptions) (<-chan Event[T], error) {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultWatchBuffer
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Errors before the first sync fail Watch; later ones go to OnError
+	firstErr := make(chan error, 1)
+	onError := opts.OnError
+	var inf *Informer[T]
+	opts.Options.OnError = func(err error) {
+		if !inf.HasSynced() {
+			select {
+			case firstErr <- err:
+			default:
+			}
+			return
+		}
+		if onError != nil {
+			onError(err)
+		}
+	}
+	inf = New(lw, opts.Options)
+
+	// Events of the initial list are held until Watch returns, so they
+	// can't fill the buffer before anyone receives; the changes that
+	// follow wait until they are sent.
+	events := make(chan Event[T], opts.Buffer)
+	var initial []Event[T]
+	initialSent := make(chan struct{})
+	send := func(eventType storage.WatchEventType, obj T) {
+		event := Event[T]{Type: eventType, UID: obj.GetUID(), Object: obj}
+		if !inf.HasSynced() {
+			if opts.SendInitial {
+				initial = append(initial, event)
+			}
+			return
+		}
+		select {
+		case <-initialSent:
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	inf.AddEventHandler(HandlerFuncs[T]{
+		OnAddFunc:    func(obj T) { send(storage.WatchSaved, obj) },
+		OnUpdateFunc: func(_, obj T) { send(storage.WatchSaved, obj) },
+		OnDeleteFunc: func(obj T) { send(storage.WatchDeleted, obj) },
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = inf.Run(ctx)
+	}()
+
+	select {
+	case <-inf.synced:
+	case err := <-firstErr:
+		cancel()
+		<-done
+		return nil, err
+	case <-ctx.Done():
+		err := ctx.Err()
+		cancel()
+		<-done
+		return nil, err
+	}
+	// initial was filled before the informer synced, and is no longer
+	// written to
+	go func() {
+		defer close(initialSent)
+		for _, event := range initial {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		<-done
+		cancel()
+		<-initialSent
+		close(events)
+	}()
+	return events, nil
+}
@@ -0,0 +1,116 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package informer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// expectEvents receives events and compares them, in order, to want
+// ("Saved dev-1" etc.)
+func expectEvents(t *testing.T, events <-chan Event[*testObj], want ...string) []Event[*testObj] {
+	t.Helper()
+	var got []Event[*testObj]
+	for _, w := range want {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("Channel closed, expected %q", w)
+			}
+			if s := string(event.Type) + " " + event.UID; s != w {
+				t.Fatalf("Expected %q, got %q", w, s)
+			}
+			got = append(got, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %q", w)
+		}
+	}
+	return got
+}
+
+func TestWatchResumesAfterReconnecting(t *testing.T) {
+	server := newFakeServer(&testObj{UID: "dev-1"}, &testObj{UID: "dev-2"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := Watch(ctx, server.listWatch(), WatchOptions{Options: Options{MinBackoff: time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	stream := server.nextStream(t)
+
+	// Resources existing before the watch aren't sent
+	stream.events <- Event[*testObj]{Type: storage.WatchSaved, UID: "dev-3", Object: &testObj{UID: "dev-3"}}
+	expectEvents(t, events, "Saved dev-3")
+
+	// Changes made while disconnected are sent as their differences
+	server.set(&testObj{UID: "dev-2", Rack: "R9"}, &testObj{UID: "dev-3"})
+	close(stream.events)
+	server.nextStream(t)
+	got := expectEvents(t, events, "Saved dev-2", "Deleted dev-1")
+	if got[0].Object.Rack != "R9" || got[1].Object == nil || got[1].Object.UID != "dev-1" {
+		t.Errorf("Unexpected resumed events: %+v, %+v", got[0].Object, got[1].Object)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("Unexpected event after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The channel wasn't closed")
+	}
+}
+
+func TestWatchSendInitial(t *testing.T) {
+	server := newFakeServer(&testObj{UID: "dev-1"}, &testObj{UID: "dev-2"}, &testObj{UID: "dev-3"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A buffer smaller than the list doesn't block Watch
+	events, err := Watch(ctx, server.listWatch(), WatchOptions{SendInitial: true, Buffer: 1})
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	stream := server.nextStream(t)
+	stream.events <- Event[*testObj]{Type: storage.WatchDeleted, UID: "dev-1"}
+
+	// Initial events come in list order, before the changes
+	initial := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-events:
+			if event.Type != storage.WatchSaved {
+				t.Fatalf("Expected an initial Saved event, got %+v", event)
+			}
+			initial[event.UID] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for the initial events")
+		}
+	}
+	if len(initial) != 3 {
+		t.Errorf("Expected an initial event per resource, got %v", initial)
+	}
+	expectEvents(t, events, "Deleted dev-1")
+}
+
+func TestWatchFailsToStart(t *testing.T) {
+	listErr := errors.New("forbidden")
+	lw := ListWatch[*testObj]{
+		List: func(ctx context.Context) ([]*testObj, error) { return nil, listErr },
+		Watch: func(ctx context.Context) (Stream[*testObj], error) {
+			return &fakeStream{events: make(chan Event[*testObj]), done: make(chan struct{})}, nil
+		},
+	}
+	if _, err := Watch(context.Background(), lw, WatchOptions{}); !errors.Is(err, listErr) {
+		t.Errorf("Expected the list error, got %v", err)
+	}
+}
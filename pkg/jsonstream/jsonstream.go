@@ -0,0 +1,132 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package jsonstream writes JSON arrays one element at a time.
+//
+// Encoding a list with json.Marshal holds the whole slice and its encoded
+// form in memory. ArrayWriter encodes each element as it is handed over and
+// flushes HTTP responses periodically, so a handler reading resources from a
+// storage iterator keeps memory flat however long the list is:
+//
+//	w.Header().Set("Content-Type", "application/json")
+//	list := jsonstream.NewArrayWriter(w, jsonstream.DefaultFlushEvery)
+//	err := storage.EachDevice(ctx, nil, func(d *device.Device) error {
+//	    return list.Write(d)
+//	})
+//	if err == nil {
+//	    err = list.Close()
+//	}
+//
+// The output is the same JSON array json.Marshal produces, apart from
+// whitespace between elements.
+package jsonstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// DefaultFlushEvery is the number of elements written between flushes when
+// the caller has no better value
+const DefaultFlushEvery = 100
+
+// ArrayWriter writes a JSON array element by element.
+//
+// Nothing is written until the first Write or Close, so a caller failing
+// before that can still send an error response instead.
+type ArrayWriter struct {
+	w          io.Writer
+	flush      func() error
+	flushEvery int
+
+	buf   bytes.Buffer
+	enc   *json.Encoder
+	count int
+}
+
+// NewArrayWriter creates a writer for one JSON array.
+//
+// When w is an http.ResponseWriter, the response is flushed every
+// flushEvery elements (see http.ResponseController), so clients receive the
+// array while it is produced. Writers that can't flush are left alone.
+//
+// Parameters:
+//   - w: Destination of the array
+//   - flushEvery: Elements between flushes; zero or less never flushes
+//
+// Returns:
+//   - *ArrayWriter: The writer; Close must be called to end the array
+func NewArrayWriter(w io.Writer, flushEvery int) *ArrayWriter {
+	a := &ArrayWriter{w: w, flushEvery: flushEvery}
+	a.enc = json.NewEncoder(&a.buf)
+	if rw, ok := w.(http.ResponseWriter); ok {
+		a.flush = http.NewResponseController(rw).Flush
+	}
+	return a
+}
+
+// Write encodes one element of the array.
+//
+// An element that fails to encode is not written, so the array written so
+// far stays intact.
+//
+// Returns:
+//   - error: If v can't be encoded, or writing or flushing fails
+func (a *ArrayWriter) Write(v interface{}) error {
+	a.buf.Reset()
+	if a.count == 0 {
+		a.buf.WriteByte('[')
+	} else {
+		a.buf.WriteByte(',')
+	}
+	if err := a.enc.Encode(v); err != nil {
+		return err
+	}
+	if _, err := a.w.Write(a.buf.Bytes()); err != nil {
+		return err
+	}
+	a.count++
+
+	if a.flushEvery > 0 && a.count%a.flushEvery == 0 {
+		return a.Flush()
+	}
+	return nil
+}
+
+// Flush sends the elements written so far to the client, if the underlying
+// writer supports it.
+//
+// Returns:
+//   - error: If flushing fails
+func (a *ArrayWriter) Flush() error {
+	if a.flush == nil {
+		return nil
+	}
+	if err := a.flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// Close ends the array; an array without elements is written as [].
+// It doesn't close the underlying writer.
+//
+// Returns:
+//   - error: If writing fails
+func (a *ArrayWriter) Close() error {
+	end := "]\n"
+	if a.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
+
+// Count returns the number of elements written.
+func (a *ArrayWriter) Count() int {
+	return a.count
+}
@@ -0,0 +1,103 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package jsonstream
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+type item struct {
+	Name string `json:"name"`
+	Tags []int  `json:"tags,omitempty"`
+}
+
+func TestArrayWriter(t *testing.T) {
+	items := []item{{Name: "a", Tags: []int{1, 2}}, {Name: "<b>"}, {}}
+
+	var out bytes.Buffer
+	list := NewArrayWriter(&out, DefaultFlushEvery)
+	for _, it := range items {
+		if err := list.Write(it); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := json.Marshal(items)
+	var got bytes.Buffer
+	if err := json.Compact(&got, out.Bytes()); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("got %s, want %s", got.String(), want)
+	}
+	if list.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", list.Count())
+	}
+}
+
+func TestArrayWriter_Empty(t *testing.T) {
+	var out bytes.Buffer
+	if err := NewArrayWriter(&out, 0).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[]\n" {
+		t.Errorf("got %q, want an empty array", out.String())
+	}
+}
+
+func TestArrayWriter_EncodeError(t *testing.T) {
+	var out bytes.Buffer
+	list := NewArrayWriter(&out, 0)
+	if err := list.Write(item{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Write(func() {}); err == nil {
+		t.Fatal("expected an error for a func")
+	}
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var items []item
+	if err := json.Unmarshal(out.Bytes(), &items); err != nil || len(items) != 1 {
+		t.Errorf("expected the failed element to be left out, got %q (%v)", out.String(), err)
+	}
+}
+
+// flushCounter counts the flushes of a response
+type flushCounter struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (f *flushCounter) Flush() {
+	f.flushes++
+	f.ResponseRecorder.Flush()
+}
+
+func TestArrayWriter_Flush(t *testing.T) {
+	w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	list := NewArrayWriter(w, 2)
+	for i := 0; i < 5; i++ {
+		if err := list.Write(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := list.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.flushes != 2 {
+		t.Errorf("got %d flushes, want 2", w.flushes)
+	}
+	if got := w.Body.String(); got != "[0\n,1\n,2\n,3\n,4\n]\n" {
+		t.Errorf("unexpected body %q", got)
+	}
+}
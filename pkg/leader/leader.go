@@ -0,0 +1,388 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package leader elects one leader among the replicas of a server, so that
+// work that must not run twice, such as reconciliation, runs in one replica
+// while the others stand by to take over.
+//
+// Replicas compete for a Lock, a lease renewed by its holder and taken over
+// by another replica once it expires. An Elector acquires and renews the lock
+// and runs a function while its replica leads:
+//
+//	elector, err := leader.NewElector(leader.Config{
+//	    Lock: leader.NewStorageLock(backend, "reconcilers"),
+//	    OnStartedLeading: func(ctx context.Context) {
+//	        // Lead until ctx is canceled
+//	    },
+//	})
+//	go elector.Run(ctx)
+//
+// A leader stepping down at shutdown releases the lock, so a standby takes
+// over at its next try rather than when the lease expires: rolling
+// deployments hand over without waiting for the lease duration.
+//
+// StorageLock keeps the lease in a storage backend shared by the replicas
+// (e.g., SQL or Ent storage); other stores, such as etcd or NATS key-value
+// buckets, plug in by implementing Lock.
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/lease"
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Kind is the resource kind StorageLock stores leases under.
+const Kind = "LeaderLease"
+
+// Defaults of Config
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// Lock is a lease on leadership shared by the replicas.
+type Lock interface {
+	// Acquire takes the lease for identity, or renews it if identity holds
+	// it, until ttl passes. It returns false when another identity holds an
+	// unexpired lease.
+	Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error)
+
+	// Release gives up the lease if identity holds it, so another replica
+	// can take it right away
+	Release(ctx context.Context, identity string) error
+}
+
+// LeaderLease is the lease of a StorageLock as stored in the backend.
+//
+//nolint:revive // "LeaderLease" name is intentional; "Lease" alone would clash with lease.Lease
+type LeaderLease struct {
+	resource.Resource
+	Spec lease.Lease `json:"spec" yaml:"spec"`
+}
+
+// StorageLock implements Lock with a LeaderLease kept in a storage backend.
+//
+// With a TransactionalBackend (SQL and Ent storage), the lease is read and
+// written in one transaction. Every acquisition then reads the lease back,
+// so a replica whose write was overwritten by a concurrent one doesn't take
+// the lead; a replica that took it at the same time as another finds out at
+// its next renewal and steps down.
+type StorageLock struct {
+	backend storage.StorageBackend
+	name    string
+	now     func() time.Time
+}
+
+// NewStorageLock creates a lock named name in backend. Replicas competing for
+// the same work use the same backend and name.
+func NewStorageLock(backend storage.StorageBackend, name string) *StorageLock {
+	return &StorageLock{backend: backend, name: name, now: time.Now}
+}
+
+// Acquire implements Lock.Acquire.
+func (l *StorageLock) Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	held := false
+	acquire := func(ctx context.Context) error {
+		current, err := l.load(ctx)
+		if err != nil {
+			return err
+		}
+		now := l.now()
+		if current != nil && current.Spec.Holder != identity && now.Before(current.Spec.ExpiresAt) {
+			return nil
+		}
+		record := &LeaderLease{Spec: lease.Lease{Holder: identity, AcquiredAt: now}}
+		record.APIVersion = "v1"
+		record.Kind = Kind
+		record.Metadata.Initialize(l.name, l.name)
+		if current != nil {
+			record.Metadata = current.Metadata
+			if current.Spec.Holder == identity {
+				record.Spec.AcquiredAt = current.Spec.AcquiredAt
+			}
+			record.Touch()
+		}
+		record.Spec.RenewedAt = now
+		record.Spec.ExpiresAt = now.Add(ttl)
+		held = true
+		return l.save(ctx, record)
+	}
+
+	err := storage.WithTx(ctx, l.backend, acquire)
+	if errors.Is(err, storage.ErrTransactionsUnsupported) {
+		err = acquire(ctx)
+	}
+	if err != nil || !held {
+		return false, err
+	}
+
+	// A concurrent write may have replaced ours
+	current, err := l.load(ctx)
+	if err != nil {
+		return false, err
+	}
+	return current != nil && current.Spec.Holder == identity, nil
+}
+
+// Release implements Lock.Release by expiring the lease.
+func (l *StorageLock) Release(ctx context.Context, identity string) error {
+	current, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Spec.Holder != identity {
+		return nil
+	}
+	current.Spec.ExpiresAt = l.now()
+	current.Touch()
+	return l.save(ctx, current)
+}
+
+// Get returns the current lease, or nil if none was ever taken.
+func (l *StorageLock) Get(ctx context.Context) (*lease.Lease, error) {
+	current, err := l.load(ctx)
+	if err != nil || current == nil {
+		return nil, err
+	}
+	return &current.Spec, nil
+}
+
+// load reads the lease, or returns nil if it doesn't exist
+func (l *StorageLock) load(ctx context.Context) (*LeaderLease, error) {
+	data, err := l.backend.Load(ctx, Kind, l.name)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leader lease %s: %w", l.name, err)
+	}
+	var record LeaderLease
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid leader lease %s: %w", l.name, err)
+	}
+	return &record, nil
+}
+
+// save writes the lease
+func (l *StorageLock) save(ctx context.Context, record *LeaderLease) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal leader lease: %w", err)
+	}
+	if err := l.backend.Save(ctx, Kind, l.name, data); err != nil {
+		return fmt.Errorf("failed to save leader lease %s: %w", l.name, err)
+	}
+	return nil
+}
+
+// Config configures an Elector. Zero durations use the Default* constants.
+type Config struct {
+	// Lock is the lease the replicas compete for (required)
+	Lock Lock
+
+	// Identity names this replica in the lease; defaults to the hostname,
+	// process ID and a random suffix
+	Identity string
+
+	// LeaseDuration is how long a lease lasts without renewal, and so how
+	// long a standby waits before replacing a leader that died
+	LeaseDuration time.Duration
+
+	// RetryPeriod is how often the leader renews the lease and standbys try
+	// to acquire it. It must be shorter than LeaseDuration.
+	RetryPeriod time.Duration
+
+	// OnStartedLeading runs when this replica becomes the leader. Its ctx is
+	// canceled when the replica stops leading; it should return then.
+	OnStartedLeading func(ctx context.Context)
+
+	// OnStoppedLeading runs when this replica stops leading, after
+	// OnStartedLeading returned (optional)
+	OnStoppedLeading func()
+
+	// Logger reports leadership changes and lock errors; defaults to
+	// slog.Default()
+	Logger *slog.Logger
+}
+
+// Elector acquires leadership for one replica and runs its work while it
+// leads. It is safe for concurrent use.
+type Elector struct {
+	config Config
+
+	mu     sync.Mutex
+	leader bool
+}
+
+// NewElector creates an elector. Call Run to take part in the election.
+//
+// Returns:
+//   - *Elector: The elector
+//   - error: If Lock or OnStartedLeading is missing or RetryPeriod isn't shorter than LeaseDuration
+func NewElector(config Config) (*Elector, error) {
+	if config.Lock == nil {
+		return nil, fmt.Errorf("leader election requires a lock")
+	}
+	if config.OnStartedLeading == nil {
+		return nil, fmt.Errorf("leader election requires OnStartedLeading")
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = DefaultLeaseDuration
+	}
+	if config.RetryPeriod <= 0 {
+		config.RetryPeriod = DefaultRetryPeriod
+	}
+	if config.RetryPeriod >= config.LeaseDuration {
+		return nil, fmt.Errorf("leader election retry period %s must be shorter than the lease duration %s", config.RetryPeriod, config.LeaseDuration)
+	}
+	if config.Identity == "" {
+		config.Identity = DefaultIdentity()
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	return &Elector{config: config}, nil
+}
+
+// DefaultIdentity returns an identity unique to this process: the hostname,
+// process ID and a random suffix.
+func DefaultIdentity() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Identity returns the identity of this replica in the lease.
+func (e *Elector) Identity() string {
+	return e.config.Identity
+}
+
+// IsLeader reports whether this replica currently leads.
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Run takes part in the election until ctx is canceled, running
+// OnStartedLeading whenever this replica becomes the leader. When ctx is
+// canceled, a leader stops leading, waits for OnStartedLeading to return and
+// releases the lock.
+func (e *Elector) Run(ctx context.Context) {
+	logger := e.config.Logger.With("identity", e.config.Identity)
+	for {
+		if !e.acquire(ctx, logger) {
+			return
+		}
+		e.lead(ctx, logger)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// acquire tries to acquire the lock every RetryPeriod until it succeeds,
+// returning false if ctx is canceled first
+func (e *Elector) acquire(ctx context.Context, logger *slog.Logger) bool {
+	ticker := time.NewTicker(e.config.RetryPeriod)
+	defer ticker.Stop()
+	for {
+		held, err := e.config.Lock.Acquire(ctx, e.config.Identity, e.config.LeaseDuration)
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("failed to acquire leader lease", "error", err)
+		}
+		if held {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs OnStartedLeading and renews the lock every RetryPeriod until
+// renewal fails for longer than the lease is safe or ctx is canceled
+func (e *Elector) lead(ctx context.Context, logger *slog.Logger) {
+	e.setLeader(true)
+	logger.Info("became leader")
+
+	leadCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.config.OnStartedLeading(leadCtx)
+	}()
+
+	// Another replica may take the lease once it expires, so leadership ends
+	// a retry period before that unless the lease is renewed
+	renewed := time.Now()
+	deadline := e.config.LeaseDuration - e.config.RetryPeriod
+	ticker := time.NewTicker(e.config.RetryPeriod)
+	defer ticker.Stop()
+renew:
+	for {
+		select {
+		case <-ctx.Done():
+			break renew
+		case <-done:
+			break renew
+		case <-ticker.C:
+		}
+		held, err := e.config.Lock.Acquire(ctx, e.config.Identity, e.config.LeaseDuration)
+		switch {
+		case held:
+			renewed = time.Now()
+		case err == nil:
+			logger.Warn("leader lease taken by another replica")
+			break renew
+		case time.Since(renewed) >= deadline:
+			logger.Warn("failed to renew leader lease", "error", err)
+			break renew
+		case ctx.Err() == nil:
+			logger.Warn("failed to renew leader lease, retrying", "error", err)
+		}
+	}
+
+	cancel()
+	<-done
+	e.setLeader(false)
+	logger.Info("stopped leading")
+	if e.config.OnStoppedLeading != nil {
+		e.config.OnStoppedLeading()
+	}
+
+	if ctx.Err() != nil {
+		// Hand over to a standby without waiting for the lease to expire
+		releaseCtx, cancelRelease := context.WithTimeout(context.Background(), e.config.RetryPeriod)
+		defer cancelRelease()
+		if err := e.config.Lock.Release(releaseCtx, e.config.Identity); err != nil {
+			logger.Warn("failed to release leader lease", "error", err)
+		}
+	}
+}
+
+// setLeader records whether this replica leads
+func (e *Elector) setLeader(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leader = leader
+}
@@ -0,0 +1,206 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+func TestStorageLock(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemoryBackend()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	lock := NewStorageLock(backend, "reconcilers")
+	lock.now = func() time.Time { return now }
+
+	if held, err := lock.Acquire(ctx, "a", time.Minute); !held || err != nil {
+		t.Fatalf("Acquire(a) = %v, %v; want the free lock", held, err)
+	}
+	if held, err := lock.Acquire(ctx, "b", time.Minute); held || err != nil {
+		t.Fatalf("Acquire(b) = %v, %v; want the lock held by a", held, err)
+	}
+
+	// Renewing keeps the acquisition time
+	now = now.Add(30 * time.Second)
+	if held, _ := lock.Acquire(ctx, "a", time.Minute); !held {
+		t.Fatal("a couldn't renew its lock")
+	}
+	current, err := lock.Get(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Holder != "a" || !current.RenewedAt.Equal(now) || !current.AcquiredAt.Equal(now.Add(-30*time.Second)) {
+		t.Errorf("lease = %+v", current)
+	}
+
+	// An expired lease is taken over
+	now = now.Add(2 * time.Minute)
+	if held, _ := lock.Acquire(ctx, "b", time.Minute); !held {
+		t.Fatal("b couldn't take the expired lock")
+	}
+	if held, _ := lock.Acquire(ctx, "a", time.Minute); held {
+		t.Fatal("a renewed a lock taken by b")
+	}
+
+	// Releasing frees the lock right away, but only for its holder
+	if err := lock.Release(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if held, _ := lock.Acquire(ctx, "a", time.Minute); held {
+		t.Fatal("a released the lock of b")
+	}
+	if err := lock.Release(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if held, _ := lock.Acquire(ctx, "a", time.Minute); !held {
+		t.Fatal("a couldn't take the released lock")
+	}
+}
+
+// flakyLock is a Lock whose acquisitions can be made to fail
+type flakyLock struct {
+	Lock
+	mu  sync.Mutex
+	err error
+}
+
+func (l *flakyLock) Acquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	err := l.err
+	l.mu.Unlock()
+	if err != nil {
+		return false, err
+	}
+	return l.Lock.Acquire(ctx, identity, ttl)
+}
+
+func (l *flakyLock) fail(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.err = err
+}
+
+// startElector runs an elector reporting its leadership on a channel
+func startElector(t *testing.T, ctx context.Context, lock Lock, identity string) (*Elector, <-chan bool, <-chan struct{}) {
+	t.Helper()
+	leading := make(chan bool, 10)
+	elector, err := NewElector(Config{
+		Lock:          lock,
+		Identity:      identity,
+		LeaseDuration: 200 * time.Millisecond,
+		RetryPeriod:   20 * time.Millisecond,
+		OnStartedLeading: func(ctx context.Context) {
+			leading <- true
+			<-ctx.Done()
+		},
+		OnStoppedLeading: func() { leading <- false },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		elector.Run(ctx)
+	}()
+	return elector, leading, done
+}
+
+func expectLeading(t *testing.T, leading <-chan bool, want bool) {
+	t.Helper()
+	select {
+	case got := <-leading:
+		if got != want {
+			t.Fatalf("leading = %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("leadership didn't change to %v", want)
+	}
+}
+
+func TestElectorHandover(t *testing.T) {
+	lock := NewStorageLock(storage.NewMemoryBackend(), "reconcilers")
+
+	ctxA, stopA := context.WithCancel(context.Background())
+	defer stopA()
+	a, leadingA, doneA := startElector(t, ctxA, lock, "a")
+	expectLeading(t, leadingA, true)
+	if !a.IsLeader() {
+		t.Error("a doesn't report its leadership")
+	}
+
+	ctxB, stopB := context.WithCancel(context.Background())
+	defer stopB()
+	b, leadingB, doneB := startElector(t, ctxB, lock, "b")
+	time.Sleep(100 * time.Millisecond)
+	if b.IsLeader() {
+		t.Fatal("b leads alongside a")
+	}
+
+	// Stopping a releases the lock, so b takes over before the lease expires
+	stopA()
+	expectLeading(t, leadingA, false)
+	<-doneA
+	start := time.Now()
+	expectLeading(t, leadingB, true)
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("b took over after %s, want it before the lease expired", elapsed)
+	}
+
+	stopB()
+	expectLeading(t, leadingB, false)
+	<-doneB
+}
+
+func TestElectorStepsDown(t *testing.T) {
+	lock := &flakyLock{Lock: NewStorageLock(storage.NewMemoryBackend(), "reconcilers")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	elector, leading, done := startElector(t, ctx, lock, "a")
+	expectLeading(t, leading, true)
+
+	// A leader unable to renew its lease steps down before it expires...
+	lock.fail(errors.New("storage unavailable"))
+	expectLeading(t, leading, false)
+	if elector.IsLeader() {
+		t.Error("elector still reports its leadership")
+	}
+
+	// ...and leads again once it can
+	lock.fail(nil)
+	expectLeading(t, leading, true)
+
+	cancel()
+	expectLeading(t, leading, false)
+	<-done
+}
+
+func TestNewElector(t *testing.T) {
+	lock := NewStorageLock(storage.NewMemoryBackend(), "reconcilers")
+	lead := func(ctx context.Context) {}
+	for name, config := range map[string]Config{
+		"no lock":           {OnStartedLeading: lead},
+		"no work":           {Lock: lock},
+		"long retry period": {Lock: lock, OnStartedLeading: lead, LeaseDuration: time.Second, RetryPeriod: time.Second},
+	} {
+		if _, err := NewElector(config); err == nil {
+			t.Errorf("%s: NewElector succeeded", name)
+		}
+	}
+
+	elector, err := NewElector(Config{Lock: lock, OnStartedLeading: lead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elector.Identity() == "" || elector.config.LeaseDuration != DefaultLeaseDuration || elector.config.RetryPeriod != DefaultRetryPeriod {
+		t.Errorf("defaults not applied: %+v", elector.config)
+	}
+}
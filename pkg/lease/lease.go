@@ -0,0 +1,243 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package lease provides TTL-based locks on individual resources.
+//
+// A lease names a holder (any identity string chosen by the caller, e.g. the
+// name of an automation job) and expires after a TTL unless renewed. While a
+// lease is active, only its holder can renew or release it. Expired leases are
+// treated as if they don't exist, so a crashed holder never blocks a resource
+// for longer than its TTL.
+//
+// Generated servers expose leases as a lock subresource:
+//
+//	POST   /devices/{uid}/lock   {"holder": "firmware-job", "ttlSeconds": 60}  (acquire or renew)
+//	GET    /devices/{uid}/lock                                              (inspect)
+//	DELETE /devices/{uid}/lock?holder=firmware-job                          (release)
+//
+// Leases are kept in memory by the Manager, so they are local to one server
+// process and are lost on restart.
+//
+// Usage:
+//
+//	leases := lease.NewManager()
+//	l, err := leases.Acquire("Device", uid, "firmware-job", time.Minute)
+//	if errors.Is(err, lease.ErrLocked) {
+//	    // someone else holds the lock
+//	}
+//	defer leases.Release("Device", uid, "firmware-job")
+package lease
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the lease duration used when no TTL is requested.
+const DefaultTTL = 60 * time.Second
+
+// MaxTTL is the longest lease that can be requested.
+const MaxTTL = 24 * time.Hour
+
+var (
+	// ErrLocked is returned when a resource is leased by another holder.
+	ErrLocked = errors.New("resource is locked by another holder")
+
+	// ErrNotHeld is returned when releasing or renewing a lease the caller doesn't hold.
+	ErrNotHeld = errors.New("lease is not held")
+)
+
+// Lease is an active lock on a resource.
+type Lease struct {
+	// Holder identifies who holds the lease
+	Holder string `json:"holder" yaml:"holder"`
+
+	// AcquiredAt is when the holder first acquired the lease
+	AcquiredAt time.Time `json:"acquiredAt" yaml:"acquiredAt"`
+
+	// RenewedAt is when the lease was last acquired or renewed
+	RenewedAt time.Time `json:"renewedAt" yaml:"renewedAt"`
+
+	// ExpiresAt is when the lease lapses unless renewed
+	ExpiresAt time.Time `json:"expiresAt" yaml:"expiresAt"`
+}
+
+// LockedError reports the lease that blocked an operation.
+// It matches ErrLocked with errors.Is.
+type LockedError struct {
+	Lease Lease
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("resource is locked by %q until %s", e.Lease.Holder, e.Lease.ExpiresAt.Format(time.RFC3339))
+}
+
+// Is reports whether target is ErrLocked.
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Manager tracks leases for resources of any kind.
+// It is safe for concurrent use.
+type Manager struct {
+	mu     sync.Mutex
+	leases map[string]Lease // keyed by kind/uid
+	now    func() time.Time
+}
+
+// NewManager creates an empty lease manager.
+func NewManager() *Manager {
+	return &Manager{
+		leases: make(map[string]Lease),
+		now:    time.Now,
+	}
+}
+
+// Acquire takes a lease on a resource, or renews it if holder already has it.
+//
+// Parameters:
+//   - kind: Resource kind (e.g., "Device")
+//   - uid: Resource UID
+//   - holder: Identity of the caller; must not be empty
+//   - ttl: Lease duration; DefaultTTL if zero, capped at MaxTTL
+//
+// Returns:
+//   - *Lease: The active lease
+//   - error: *LockedError if another holder has an active lease
+func (m *Manager) Acquire(kind, uid, holder string, ttl time.Duration) (*Lease, error) {
+	if holder == "" {
+		return nil, fmt.Errorf("lease holder is required")
+	}
+	ttl, err := normalizeTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	l, ok := m.active(kind, uid, now)
+	if ok && l.Holder != holder {
+		return nil, &LockedError{Lease: l}
+	}
+	if !ok {
+		l = Lease{Holder: holder, AcquiredAt: now}
+	}
+	l.RenewedAt = now
+	l.ExpiresAt = now.Add(ttl)
+	m.leases[key(kind, uid)] = l
+	return &l, nil
+}
+
+// Renew extends a lease held by holder.
+//
+// Unlike Acquire, Renew fails with ErrNotHeld when the lease has expired or
+// was never taken, so a holder can detect that it lost the lock.
+func (m *Manager) Renew(kind, uid, holder string, ttl time.Duration) (*Lease, error) {
+	ttl, err := normalizeTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	l, ok := m.active(kind, uid, now)
+	if !ok {
+		return nil, ErrNotHeld
+	}
+	if l.Holder != holder {
+		return nil, &LockedError{Lease: l}
+	}
+	l.RenewedAt = now
+	l.ExpiresAt = now.Add(ttl)
+	m.leases[key(kind, uid)] = l
+	return &l, nil
+}
+
+// Release gives up a lease held by holder.
+//
+// Releasing an expired or missing lease returns ErrNotHeld; releasing another
+// holder's active lease returns a *LockedError.
+func (m *Manager) Release(kind, uid, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.active(kind, uid, m.now())
+	if !ok {
+		return ErrNotHeld
+	}
+	if l.Holder != holder {
+		return &LockedError{Lease: l}
+	}
+	delete(m.leases, key(kind, uid))
+	return nil
+}
+
+// Get returns the active lease on a resource, if any.
+func (m *Manager) Get(kind, uid string) (*Lease, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.active(kind, uid, m.now())
+	if !ok {
+		return nil, false
+	}
+	return &l, true
+}
+
+// Check verifies that holder may modify a resource.
+// It succeeds when the resource has no active lease or holder holds it.
+//
+// Returns:
+//   - error: *LockedError if another holder has an active lease
+func (m *Manager) Check(kind, uid, holder string) error {
+	l, ok := m.Get(kind, uid)
+	if ok && l.Holder != holder {
+		return &LockedError{Lease: *l}
+	}
+	return nil
+}
+
+// Forget drops any lease on a resource, e.g. after the resource is deleted.
+func (m *Manager) Forget(kind, uid string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.leases, key(kind, uid))
+}
+
+// active returns the unexpired lease for a resource, pruning it if expired.
+// The caller must hold m.mu.
+func (m *Manager) active(kind, uid string, now time.Time) (Lease, bool) {
+	k := key(kind, uid)
+	l, ok := m.leases[k]
+	if !ok {
+		return Lease{}, false
+	}
+	if !now.Before(l.ExpiresAt) {
+		delete(m.leases, k)
+		return Lease{}, false
+	}
+	return l, true
+}
+
+func normalizeTTL(ttl time.Duration) (time.Duration, error) {
+	switch {
+	case ttl == 0:
+		return DefaultTTL, nil
+	case ttl < 0:
+		return 0, fmt.Errorf("lease TTL must be positive")
+	case ttl > MaxTTL:
+		return 0, fmt.Errorf("lease TTL must not exceed %s", MaxTTL)
+	}
+	return ttl, nil
+}
+
+func key(kind, uid string) string {
+	return kind + "/" + uid
+}
@@ -0,0 +1,111 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package lease
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestManager returns a manager whose clock is advanced manually.
+func newTestManager() (*Manager, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewManager()
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestManager_AcquireAndRenew(t *testing.T) {
+	m, now := newTestManager()
+
+	l, err := m.Acquire("Device", "dev-1", "job-a", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if l.Holder != "job-a" || !l.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Unexpected lease: %+v", l)
+	}
+
+	_, err = m.Acquire("Device", "dev-1", "job-b", time.Minute)
+	var locked *LockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrLocked) || locked.Lease.Holder != "job-a" {
+		t.Fatalf("Expected LockedError for job-a, got %v", err)
+	}
+
+	*now = now.Add(30 * time.Second)
+	renewed, err := m.Renew("Device", "dev-1", "job-a", time.Minute)
+	if err != nil {
+		t.Fatalf("Renew failed: %v", err)
+	}
+	if !renewed.AcquiredAt.Equal(l.AcquiredAt) || !renewed.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Unexpected renewed lease: %+v", renewed)
+	}
+
+	// Other kinds and UIDs are independent
+	if _, err := m.Acquire("Device", "dev-2", "job-b", 0); err != nil {
+		t.Errorf("Acquire on another resource failed: %v", err)
+	}
+}
+
+func TestManager_Expiry(t *testing.T) {
+	m, now := newTestManager()
+
+	if _, err := m.Acquire("Device", "dev-1", "job-a", time.Minute); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	*now = now.Add(time.Minute)
+
+	if _, ok := m.Get("Device", "dev-1"); ok {
+		t.Error("Expected expired lease to be gone")
+	}
+	if _, err := m.Renew("Device", "dev-1", "job-a", 0); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected ErrNotHeld renewing expired lease, got %v", err)
+	}
+	if _, err := m.Acquire("Device", "dev-1", "job-b", 0); err != nil {
+		t.Errorf("Expected job-b to acquire expired lease, got %v", err)
+	}
+}
+
+func TestManager_ReleaseAndCheck(t *testing.T) {
+	m, _ := newTestManager()
+
+	if err := m.Check("Device", "dev-1", ""); err != nil {
+		t.Errorf("Expected unlocked resource to pass Check, got %v", err)
+	}
+	if _, err := m.Acquire("Device", "dev-1", "job-a", 0); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if err := m.Check("Device", "dev-1", "job-a"); err != nil {
+		t.Errorf("Expected holder to pass Check, got %v", err)
+	}
+	if err := m.Check("Device", "dev-1", "job-b"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked for other holder, got %v", err)
+	}
+
+	if err := m.Release("Device", "dev-1", "job-b"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked releasing another holder's lease, got %v", err)
+	}
+	if err := m.Release("Device", "dev-1", "job-a"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if err := m.Release("Device", "dev-1", "job-a"); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected ErrNotHeld on second release, got %v", err)
+	}
+}
+
+func TestManager_InvalidArguments(t *testing.T) {
+	m, _ := newTestManager()
+
+	if _, err := m.Acquire("Device", "dev-1", "", 0); err == nil {
+		t.Error("Expected error for empty holder")
+	}
+	if _, err := m.Acquire("Device", "dev-1", "job-a", -time.Second); err == nil {
+		t.Error("Expected error for negative TTL")
+	}
+	if _, err := m.Acquire("Device", "dev-1", "job-a", MaxTTL+time.Second); err == nil {
+		t.Error("Expected error for TTL above MaxTTL")
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package limits bounds the size of request bodies and the time requests
+// may take, so one oversized upload or stuck request can't wedge a server.
+//
+//	r.Use(limits.Middleware(limits.Options{
+//	    MaxBodySize: 10 << 20,         // 413 Payload Too Large beyond 10 MiB
+//	    Timeout:     30 * time.Second, // 503 Service Unavailable after 30s
+//	}))
+//
+// Bodies announcing a larger Content-Length are rejected before the handler
+// runs; other bodies are cut off at the limit, and handlers reading them get
+// an error for which TooLarge reports true.
+//
+// The request context gets a deadline, which cancels storage calls and
+// other work started with it. A handler that hasn't started its response by
+// then gets 503 Service Unavailable in its place, and whatever it writes
+// later is dropped. Responses already under way, such as streamed lists,
+// are left to end on their own.
+//
+// Errors are RFC 9457 problem documents with the PAYLOAD_TOO_LARGE and
+// TIMEOUT codes (see errcode).
+package limits
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+)
+
+// Options configures Middleware.
+type Options struct {
+	// MaxBodySize is the largest request body, in bytes. Zero means no
+	// limit.
+	MaxBodySize int64
+
+	// Timeout is how long a request may take. Zero means no timeout.
+	Timeout time.Duration
+
+	// Skip, if set, exempts requests from both limits, e.g. file uploads
+	// whose handlers enforce limits of their own.
+	Skip func(r *http.Request) bool
+}
+
+// Enabled reports whether any limit is set
+func (o Options) Enabled() bool {
+	return o.MaxBodySize > 0 || o.Timeout > 0
+}
+
+// Middleware enforces the body size limit and timeout of opts.
+//
+// Parameters:
+//   - opts: The limits; zero values disable them
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !opts.Enabled() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opts.Skip != nil && opts.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if opts.MaxBodySize > 0 && r.Body != nil && r.Body != http.NoBody {
+				if r.ContentLength > opts.MaxBodySize {
+					writeProblem(w, http.StatusRequestEntityTooLarge, errcode.Wrap(errcode.PayloadTooLarge,
+						fmt.Errorf("request body of %d bytes exceeds the %d byte limit", r.ContentLength, opts.MaxBodySize)))
+					return
+				}
+				r.Body = http.MaxBytesReader(w, r.Body, opts.MaxBodySize)
+			}
+
+			if opts.Timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			serveWithTimeout(w, r, next, opts.Timeout)
+		})
+	}
+}
+
+// serveWithTimeout runs next with a deadline, answering 503 in its place
+// when it hasn't started its response by then
+func serveWithTimeout(w http.ResponseWriter, r *http.Request, next http.Handler, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	tw := &timeoutWriter{w: w, header: w.Header().Clone()}
+	done := make(chan struct{})
+	panicked := make(chan any, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panicked <- p
+				return
+			}
+			close(done)
+		}()
+		next.ServeHTTP(tw, r.WithContext(ctx))
+	}()
+
+	select {
+	case p := <-panicked:
+		// Let the server (or a recoverer) handle it as usual
+		panic(p)
+	case <-done:
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+		if !tw.wroteHeader {
+			tw.writeHeader(http.StatusOK)
+		}
+	case <-ctx.Done():
+		tw.mu.Lock()
+		if tw.wroteHeader {
+			// The response is under way: let the handler end it
+			tw.mu.Unlock()
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+			}
+			return
+		}
+		tw.timedOut = true
+		tw.mu.Unlock()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			writeProblem(w, http.StatusServiceUnavailable, errcode.Wrap(errcode.Timeout,
+				fmt.Errorf("request did not complete within %s", timeout)))
+		}
+	}
+}
+
+// timeoutWriter lets a handler write its response until the request times
+// out. Its headers are kept apart until the response starts, so a timeout
+// response never mixes with them.
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.writeHeader(status)
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	return tw.w.Write(data)
+}
+
+// Flush sends buffered data to the client, starting the response
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	if !tw.wroteHeader {
+		tw.writeHeader(http.StatusOK)
+	}
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeHeader copies the handler's headers and starts the response; tw.mu
+// must be held
+func (tw *timeoutWriter) writeHeader(status int) {
+	header := tw.w.Header()
+	for name := range header {
+		if _, ok := tw.header[name]; !ok {
+			delete(header, name)
+		}
+	}
+	for name, values := range tw.header {
+		header[name] = values
+	}
+	tw.wroteHeader = true
+	tw.w.WriteHeader(status)
+}
+
+// TooLarge reports whether err comes from reading a request body past the
+// size limit, so handlers can answer 413 rather than 400.
+func TooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+// OptionsFromEnv overrides options with environment variables, so a
+// deployment can change the limits without regenerating:
+//   - FABRICA_MAX_BODY_SIZE: largest request body in bytes, 0 for none
+//   - FABRICA_REQUEST_TIMEOUT: request timeout as a duration ("30s") or
+//     seconds, 0 for none
+//
+// Variables that aren't set or don't parse keep the value of opts.
+//
+// Parameters:
+//   - opts: Options to override, e.g. those of .fabrica.yaml
+//
+// Returns:
+//   - Options: The options with the environment applied
+func OptionsFromEnv(opts Options) Options {
+	if v, err := strconv.ParseInt(os.Getenv("FABRICA_MAX_BODY_SIZE"), 10, 64); err == nil && v >= 0 {
+		opts.MaxBodySize = v
+	}
+	if v := os.Getenv("FABRICA_REQUEST_TIMEOUT"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			opts.Timeout = time.Duration(seconds) * time.Second
+		} else if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			opts.Timeout = d
+		}
+	}
+	return opts
+}
+
+// writeProblem writes the problem document of a rejected request
+func writeProblem(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", errcode.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errcode.NewProblem(status, err))
+}
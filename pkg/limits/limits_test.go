@@ -0,0 +1,172 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package limits
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/errcode"
+)
+
+// problemCode decodes the code of a problem response
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) errcode.Code {
+	t.Helper()
+	var problem errcode.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected a problem document, got %q", rec.Body.String())
+	}
+	return problem.Code
+}
+
+// echo answers 400 when the body can't be read, or the body otherwise
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		status := http.StatusBadRequest
+		if TooLarge(err) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		w.WriteHeader(status)
+		return
+	}
+	w.Write(data)
+})
+
+func TestMaxBodySize(t *testing.T) {
+	h := Middleware(Options{MaxBodySize: 8})(echo)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/nodes", strings.NewReader("small")))
+	if rec.Code != http.StatusOK || rec.Body.String() != "small" {
+		t.Errorf("expected a small body to pass, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Content-Length announces the size: rejected before the handler runs
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/nodes", strings.NewReader("much too large")))
+	if rec.Code != http.StatusRequestEntityTooLarge || problemCode(t, rec) != errcode.PayloadTooLarge {
+		t.Errorf("expected 413 PAYLOAD_TOO_LARGE, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	// Without Content-Length the body is cut off at the limit
+	req := httptest.NewRequest(http.MethodPost, "/nodes", io.NopCloser(strings.NewReader("much too large")))
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected the handler to see a TooLarge error, got %d", rec.Code)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	h := Middleware(Options{
+		MaxBodySize: 8,
+		Skip:        func(r *http.Request) bool { return strings.HasPrefix(r.URL.Path, "/files/") },
+	})(echo)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/files/a", strings.NewReader("much too large")))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected a skipped request to pass, got %d", rec.Code)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	h := Middleware(Options{Timeout: 20 * time.Millisecond})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "slow")
+		<-r.Context().Done()
+		// Too late: dropped
+		if _, err := w.Write([]byte("late")); err != http.ErrHandlerTimeout {
+			t.Errorf("expected ErrHandlerTimeout, got %v", err)
+		}
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nodes", nil))
+	if rec.Code != http.StatusServiceUnavailable || problemCode(t, rec) != errcode.Timeout {
+		t.Errorf("expected 503 TIMEOUT, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("X-Handler") != "" {
+		t.Error("expected the handler's headers to be dropped")
+	}
+}
+
+func TestTimeoutAfterResponseStarts(t *testing.T) {
+	h := Middleware(Options{Timeout: 20 * time.Millisecond})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("started"))
+		<-r.Context().Done()
+		w.Write([]byte(" and ended"))
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nodes", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "started and ended" || rec.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("expected the started response to end, got %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+}
+
+func TestFastHandler(t *testing.T) {
+	h := Middleware(Options{Timeout: time.Second})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("expected the request context to have a deadline")
+		}
+		w.Header().Set("Location", "/nodes/1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "abc")
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/nodes", nil))
+	if rec.Code != http.StatusCreated || rec.Header().Get("Location") != "/nodes/1" || rec.Header().Get("X-Request-ID") != "abc" {
+		t.Errorf("expected the handler's response with all headers, got %d %v", rec.Code, rec.Header())
+	}
+
+	// A handler writing nothing still gets its headers sent
+	h = Middleware(Options{Timeout: time.Second})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "quiet")
+	}))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nodes", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Handler") != "quiet" {
+		t.Errorf("expected 200 with the handler's headers, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	h := Middleware(Options{Timeout: time.Second})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("expected the panic to reach the caller, got %v", p)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nodes", nil))
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	defaults := Options{MaxBodySize: 1 << 20, Timeout: 30 * time.Second}
+	if got := OptionsFromEnv(defaults); got.MaxBodySize != defaults.MaxBodySize || got.Timeout != defaults.Timeout {
+		t.Errorf("expected the options unchanged without variables, got %+v", got)
+	}
+
+	t.Setenv("FABRICA_MAX_BODY_SIZE", "1024")
+	t.Setenv("FABRICA_REQUEST_TIMEOUT", "90")
+	if got := OptionsFromEnv(defaults); got.MaxBodySize != 1024 || got.Timeout != 90*time.Second {
+		t.Errorf("OptionsFromEnv = %+v", got)
+	}
+	t.Setenv("FABRICA_REQUEST_TIMEOUT", "1m30s")
+	if got := OptionsFromEnv(defaults); got.Timeout != 90*time.Second {
+		t.Errorf("expected a duration to parse, got %s", got.Timeout)
+	}
+	t.Setenv("FABRICA_MAX_BODY_SIZE", "0")
+	t.Setenv("FABRICA_REQUEST_TIMEOUT", "0")
+	if OptionsFromEnv(defaults).Enabled() {
+		t.Error("expected zero values to disable the limits")
+	}
+}
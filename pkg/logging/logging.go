@@ -0,0 +1,285 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package logging sets up structured logging with log/slog for generated
+// servers.
+//
+// A server builds one root logger from its configuration and derives a logger
+// per component (handlers, storage, reconcile, events) with Component, so
+// every line says where it came from. The logger travels in the request or
+// reconciliation context: RequestID gives each request an X-Request-ID,
+// Middleware stores a request-scoped logger carrying the request ID, method
+// and path, and FromContext retrieves it in handlers and user code.
+// Generated code and user code therefore log the same way:
+//
+//	logging.FromContext(r.Context()).Info("device registered", "uid", device.GetUID())
+//
+// Usage:
+//
+//	logger, err := logging.New(os.Stderr, logging.Options{Level: "info", Format: "json"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	slog.SetDefault(logger)
+//	r.Use(logging.RequestID)
+//	r.Use(logging.Middleware(logging.Component(logger, logging.ComponentHandlers), logging.RequestIDFromContext))
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Component names used by generated code
+const (
+	ComponentServer    = "server"
+	ComponentHandlers  = "handlers"
+	ComponentStorage   = "storage"
+	ComponentReconcile = "reconcile"
+	ComponentEvents    = "events"
+)
+
+// ComponentKey is the attribute naming the component that logged a line
+const ComponentKey = "component"
+
+// Options configures a logger
+type Options struct {
+	// Level is the minimum level logged: debug, info, warn or error
+	// (default: info)
+	Level string
+
+	// Format is "text" (key=value) or "json" (default: text)
+	Format string
+
+	// AddSource adds the file and line of the log call
+	AddSource bool
+}
+
+// Validate checks the level and format
+func (o Options) Validate() error {
+	if _, err := ParseLevel(o.Level); err != nil {
+		return err
+	}
+	switch strings.ToLower(o.Format) {
+	case "", FormatText, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("unknown log format %q (valid: text, json)", o.Format)
+	}
+}
+
+// ParseLevel converts a level name to a slog.Level.
+//
+// Names are case-insensitive, and "warning" is accepted for "warn". An empty
+// name is info.
+//
+// Parameters:
+//   - name: Level name
+//
+// Returns:
+//   - slog.Level: Parsed level
+//   - error: If the name is not a known level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (valid: debug, info, warn, error)", name)
+	}
+}
+
+// New creates a logger writing to w.
+//
+// Parameters:
+//   - w: Destination of log lines, usually os.Stderr
+//   - opts: Level and format
+//
+// Returns:
+//   - *slog.Logger: Root logger of the server
+//   - error: If the level or format is invalid
+//
+// Example:
+//
+//	logger, err := logging.New(os.Stderr, logging.Options{Level: "debug", Format: "json"})
+func New(w io.Writer, opts Options) (*slog.Logger, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	level, _ := ParseLevel(opts.Level)
+	handlerOpts := &slog.HandlerOptions{Level: level, AddSource: opts.AddSource}
+
+	if strings.ToLower(opts.Format) == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, handlerOpts)), nil
+	}
+	return slog.New(slog.NewTextHandler(w, handlerOpts)), nil
+}
+
+// Component returns a logger whose lines carry component=name
+func Component(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With(ComponentKey, name)
+}
+
+// contextKey is the context key of the logger
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by NewContext or Middleware.
+//
+// Without one, slog.Default() is returned, so callers never get nil.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return slog.Default()
+}
+
+// requestAttrs collects the attributes added to the line logged when a
+// request completes
+type requestAttrs struct {
+	mu   sync.Mutex
+	args []any
+}
+
+// requestAttrsKey is the context key of a request's requestAttrs
+type requestAttrsKey struct{}
+
+// AddRequestAttrs adds attributes to the line Middleware logs when the
+// request completes, such as the caller's identity once it is known.
+// Arguments are key-value pairs or slog.Attr values, as for slog.Logger.With.
+// Outside requests served through Middleware it does nothing.
+//
+// Example:
+//
+//	logging.AddRequestAttrs(r.Context(), "subject", claims.Subject)
+func AddRequestAttrs(ctx context.Context, args ...any) {
+	if ctx == nil {
+		return
+	}
+	if attrs, ok := ctx.Value(requestAttrsKey{}).(*requestAttrs); ok {
+		attrs.mu.Lock()
+		attrs.args = append(attrs.args, args...)
+		attrs.mu.Unlock()
+	}
+}
+
+// Middleware logs every request and stores a request-scoped logger in the
+// request context.
+//
+// The request logger carries the request ID (if requestID is given and
+// returns one), method and path. When the request completes, one line is
+// logged with the status, response size, duration and any attributes added
+// with AddRequestAttrs: at error level for 5xx responses, at info level
+// otherwise.
+//
+// Parameters:
+//   - logger: Logger of the handlers component
+//   - requestID: Returns the request ID from the context, e.g.
+//     RequestIDFromContext (optional)
+//
+// Returns:
+//   - func(http.Handler) http.Handler: Middleware for any router
+//
+// Example:
+//
+//	r.Use(logging.RequestID)
+//	r.Use(logging.Middleware(handlersLogger, logging.RequestIDFromContext))
+func Middleware(logger *slog.Logger, requestID func(context.Context) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			reqLogger := logger
+			if requestID != nil {
+				if id := requestID(r.Context()); id != "" {
+					reqLogger = reqLogger.With("request_id", id)
+				}
+			}
+			reqLogger = reqLogger.With("method", r.Method, "path", r.URL.Path)
+
+			attrs := &requestAttrs{}
+			ctx := context.WithValue(NewContext(r.Context(), reqLogger), requestAttrsKey{}, attrs)
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			attrs.mu.Lock()
+			if len(attrs.args) > 0 {
+				reqLogger = reqLogger.With(attrs.args...)
+			}
+			attrs.mu.Unlock()
+			reqLogger.LogAttrs(r.Context(), level, "request",
+				slog.Int("status", status),
+				slog.Int("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("remote", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// statusRecorder records the status code and size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush supports streaming responses such as watches
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,106 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warning", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, Options{Level: "warn", Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	Component(logger, ComponentStorage).Info("dropped")
+	Component(logger, ComponentStorage).Warn("kept", "uid", "dev-1")
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON line, got %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "kept" || line[ComponentKey] != ComponentStorage || line["uid"] != "dev-1" {
+		t.Errorf("unexpected line: %v", line)
+	}
+
+	if _, err := New(&buf, Options{Format: "xml"}); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Error("expected the default logger without one in the context")
+	}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if FromContext(NewContext(context.Background(), logger)) != logger {
+		t.Error("expected the logger stored in the context")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _ := New(&buf, Options{Format: "json"})
+	requestID := func(context.Context) string { return "req-42" }
+
+	handler := Middleware(logger, requestID)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handling")
+		AddRequestAttrs(r.Context(), "subject", "alice")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("busy"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/devices", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	for _, l := range lines {
+		var line map[string]any
+		if err := json.Unmarshal([]byte(l), &line); err != nil {
+			t.Fatal(err)
+		}
+		if line["request_id"] != "req-42" || line["method"] != "GET" || line["path"] != "/devices" {
+			t.Errorf("missing request attributes: %v", line)
+		}
+	}
+
+	var done map[string]any
+	json.Unmarshal([]byte(lines[1]), &done)
+	if done["level"] != "ERROR" || done["status"] != float64(503) || done["bytes"] != float64(4) || done["subject"] != "alice" {
+		t.Errorf("unexpected request line: %v", done)
+	}
+}
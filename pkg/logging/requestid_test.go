@@ -0,0 +1,57 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"propagated", "abc-123", true},
+		{"missing", "", false},
+		{"spaces", "abc 123", false},
+		{"control characters", "abc\x00", false},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/devices", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if seen == "" || rec.Header().Get(RequestIDHeader) != seen {
+				t.Fatalf("expected the context ID %q in the response header, got %q", seen, rec.Header().Get(RequestIDHeader))
+			}
+			if (seen == tt.header) != tt.keep {
+				t.Errorf("header %q: got ID %q, keep = %v", tt.header, seen, tt.keep)
+			}
+		})
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if id := RequestIDFromContext(nil); id != "" { //nolint:staticcheck // nil contexts are tolerated
+		t.Errorf("expected no ID in a nil context, got %q", id)
+	}
+	if id := NewRequestID(); len(id) != 32 || id == NewRequestID() {
+		t.Errorf("expected distinct 32-character IDs, got %q", id)
+	}
+}
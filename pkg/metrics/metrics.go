@@ -0,0 +1,444 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package metrics exposes Prometheus metrics of generated servers.
+//
+// A Registry holds counters, gauges and histograms with labels and serves them in
+// the Prometheus text exposition format (version 0.0.4), so any Prometheus
+// compatible scraper can collect them without a client library dependency.
+//
+// Generated servers record their standard metrics in the Default registry:
+// HTTP requests by route and status (Middleware), storage operation latency
+// (ObserveStorage), event bus publish failures (InstrumentEventBus) and
+// reconciliations, retries and queue depth (ObserveReconcile). Servers add their own metrics to
+// the same registry:
+//
+//	var powerChanges = metrics.Default.NewCounter("myservice_power_changes_total",
+//	    "Power state changes requested, by target state", "state")
+//
+//	powerChanges.Inc("on")
+//
+// Usage:
+//
+//	r.Handle("/metrics", metrics.Handler())
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the histogram buckets used when none are given, in
+// seconds: from 5ms to 10s, suited to request and storage latencies
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelSeparator joins label values into series keys; it can't appear in
+// valid UTF-8 text
+const labelSeparator = "\xff"
+
+// Default is the registry of the standard metrics and of Handler
+var Default = NewRegistry()
+
+// Registry holds metrics and writes them in the text exposition format.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// metric is a named family of series
+type metric interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// register adds a metric, panicking on duplicate or invalid names since
+// metrics are declared once at startup
+func (r *Registry) register(m metric, labels []string) {
+	if !validName(m.name()) {
+		panic(fmt.Sprintf("metrics: invalid metric name %q", m.name()))
+	}
+	for _, l := range labels {
+		if !validName(l) || strings.HasPrefix(l, "__") || l == "le" {
+			panic(fmt.Sprintf("metrics: invalid label name %q of %s", l, m.name()))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[m.name()] {
+		panic(fmt.Sprintf("metrics: %s registered twice", m.name()))
+	}
+	r.names[m.name()] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// NewCounter registers a counter partitioned by the given labels.
+//
+// Parameters:
+//   - name: Metric name, conventionally ending in _total
+//   - help: One-line description
+//   - labels: Label names; Inc and Add take one value per label
+//
+// Returns:
+//   - *Counter: The registered counter
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{metricName: name, help: help, labels: labels}, values: make(map[string]float64)}
+	r.register(c, labels)
+	return c
+}
+
+// NewGauge registers a gauge partitioned by the given labels.
+//
+// Parameters:
+//   - name: Metric name
+//   - help: One-line description
+//   - labels: Label names; Set and SetFunc take one value per label
+//
+// Returns:
+//   - *Gauge: The registered gauge
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{desc: desc{metricName: name, help: help, labels: labels}, values: make(map[string]float64), funcs: make(map[string]func() float64)}
+	r.register(g, labels)
+	return g
+}
+
+// NewHistogram registers a histogram partitioned by the given labels.
+//
+// Parameters:
+//   - name: Metric name, conventionally ending in the unit (_seconds)
+//   - help: One-line description
+//   - buckets: Upper bounds of the buckets in increasing order, or nil for DefaultBuckets
+//   - labels: Label names; Observe takes one value per label
+//
+// Returns:
+//   - *Histogram: The registered histogram
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics: buckets of %s are not sorted", name))
+	}
+	h := &Histogram{desc: desc{metricName: name, help: help, labels: labels}, buckets: buckets, series: make(map[string]*histogramSeries)}
+	r.register(h, labels)
+	return h
+}
+
+// Handler serves the metrics of the registry to scrapers.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		bw := bufio.NewWriter(w)
+		r.write(bw)
+		_ = bw.Flush()
+	})
+}
+
+// String returns the metrics of the registry in the text exposition format.
+func (r *Registry) String() string {
+	var sb strings.Builder
+	bw := bufio.NewWriter(&sb)
+	r.write(bw)
+	_ = bw.Flush()
+	return sb.String()
+}
+
+func (r *Registry) write(w *bufio.Writer) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler serves the metrics of the Default registry.
+//
+// Example:
+//
+//	r.Handle("/metrics", metrics.Handler())
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// desc describes a metric family
+type desc struct {
+	metricName string
+	help       string
+	labels     []string
+}
+
+func (d *desc) name() string { return d.metricName }
+
+// key joins label values into a series key, panicking on a wrong count
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", d.metricName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, labelSeparator)
+}
+
+// header writes the HELP and TYPE lines of the family
+func (d *desc) header(w *bufio.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.metricName, escapeHelp(d.help), d.metricName, typ)
+}
+
+// labelPairs formats the labels of a series, with extra pairs appended
+func (d *desc) labelPairs(key string, extra ...string) string {
+	if len(d.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var values []string
+	if len(d.labels) > 0 {
+		values = strings.Split(key, labelSeparator)
+	}
+	pairs := make([]string, 0, len(d.labels)+len(extra)/2)
+	for i, l := range d.labels {
+		pairs = append(pairs, l+`="`+escapeLabel(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Counter is a monotonically increasing value per label combination.
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// Inc adds one to the series of the label values.
+func (c *Counter) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds v, which must not be negative, to the series of the label values.
+func (c *Counter) Add(v float64, values ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: %s can't decrease", c.metricName))
+	}
+	k := c.key(values)
+	c.mu.Lock()
+	c.values[k] += v
+	c.mu.Unlock()
+}
+
+// Value returns the value of the series of the label values.
+func (c *Counter) Value(values ...string) float64 {
+	k := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[k]
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	keys := sortedKeys(c.values)
+	values := make([]float64, len(keys))
+	for i, k := range keys {
+		values[i] = c.values[k]
+	}
+	c.mu.Unlock()
+
+	c.header(w, "counter")
+	for i, k := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.labelPairs(k), formatFloat(values[i]))
+	}
+}
+
+// Gauge is a value that goes up and down per label combination, set
+// directly or read from a function when scraped.
+type Gauge struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+	funcs  map[string]func() float64
+}
+
+// Set sets the series of the label values to v.
+func (g *Gauge) Set(v float64, values ...string) {
+	k := g.key(values)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.funcs, k)
+	g.values[k] = v
+}
+
+// SetFunc makes the series of the label values report fn, called whenever
+// the gauge is read, e.g. the length of a queue. Set replaces it.
+func (g *Gauge) SetFunc(fn func() float64, values ...string) {
+	k := g.key(values)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.values, k)
+	g.funcs[k] = fn
+}
+
+// Value returns the value of the series of the label values.
+func (g *Gauge) Value(values ...string) float64 {
+	k := g.key(values)
+	g.mu.Lock()
+	fn, ok := g.funcs[k]
+	v := g.values[k]
+	g.mu.Unlock()
+	if ok {
+		return fn()
+	}
+	return v
+}
+
+func (g *Gauge) write(w *bufio.Writer) {
+	g.mu.Lock()
+	current := make(map[string]float64, len(g.values)+len(g.funcs))
+	for k, v := range g.values {
+		current[k] = v
+	}
+	funcs := make(map[string]func() float64, len(g.funcs))
+	for k, fn := range g.funcs {
+		funcs[k] = fn
+	}
+	g.mu.Unlock()
+
+	// Functions are called outside the lock, as they may take locks of their own
+	for k, fn := range funcs {
+		current[k] = fn()
+	}
+	g.header(w, "gauge")
+	for _, k := range sortedKeys(current) {
+		fmt.Fprintf(w, "%s%s %s\n", g.metricName, g.labelPairs(k), formatFloat(current[k]))
+	}
+}
+
+// Histogram counts observations in buckets per label combination.
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+// histogramSeries holds the observations of one label combination
+type histogramSeries struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// Observe records v in the series of the label values.
+func (h *Histogram) Observe(v float64, values ...string) {
+	k := h.key(values)
+	i := sort.SearchFloat64s(h.buckets, v)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[k]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[k] = s
+	}
+	if i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// Count returns the number of observations in the series of the label values.
+func (h *Histogram) Count(values ...string) uint64 {
+	k := h.key(values)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[k]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	keys := sortedKeys(h.series)
+	series := make([]histogramSeries, len(keys))
+	for i, k := range keys {
+		s := h.series[k]
+		series[i] = histogramSeries{counts: append([]uint64(nil), s.counts...), count: s.count, sum: s.sum}
+	}
+	h.mu.Unlock()
+
+	h.header(w, "histogram")
+	for i, k := range keys {
+		s := series[i]
+		var cumulative uint64
+		for b, upper := range h.buckets {
+			cumulative += s.counts[b]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(k, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(k, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, h.labelPairs(k), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, h.labelPairs(k), s.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// validName reports whether s is a valid metric or label name
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
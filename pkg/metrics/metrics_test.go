@@ -0,0 +1,216 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounter("test_requests_total", "Requests served.", "code")
+	latency := reg.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1})
+	info := reg.NewCounter("test_info_total", "Label with \"quotes\".", "name")
+
+	requests.Inc("200")
+	requests.Inc("200")
+	requests.Add(3, "404")
+	latency.Observe(0.05)
+	latency.Observe(0.1)
+	latency.Observe(2)
+	info.Inc("a\"b\\c\nd")
+
+	want := `# HELP test_info_total Label with "quotes".
+# TYPE test_info_total counter
+test_info_total{name="a\"b\\c\nd"} 1
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 2
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 2.15
+test_latency_seconds_count 3
+# HELP test_requests_total Requests served.
+# TYPE test_requests_total counter
+test_requests_total{code="200"} 2
+test_requests_total{code="404"} 3
+`
+	if got := reg.String(); got != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+
+	if v := requests.Value("200"); v != 2 {
+		t.Errorf("Value(200) = %v, want 2", v)
+	}
+	if n := latency.Count(); n != 3 {
+		t.Errorf("Count() = %d, want 3", n)
+	}
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ContentType)
+	}
+	if rec.Body.String() != want {
+		t.Errorf("handler body differs from String():\n%s", rec.Body.String())
+	}
+}
+
+func TestGauge(t *testing.T) {
+	reg := NewRegistry()
+	depth := reg.NewGauge("test_queue_depth", "Queued items.", "queue")
+
+	depth.Set(3, "a")
+	queued := 5
+	depth.SetFunc(func() float64 { return float64(queued) }, "b")
+	queued = 7
+
+	want := `# HELP test_queue_depth Queued items.
+# TYPE test_queue_depth gauge
+test_queue_depth{queue="a"} 3
+test_queue_depth{queue="b"} 7
+`
+	if got := reg.String(); got != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Set replaces the function
+	depth.Set(1, "b")
+	queued = 9
+	if v := depth.Value("b"); v != 1 {
+		t.Errorf("Value(b) = %v, want 1", v)
+	}
+}
+
+func TestRegistry_Panics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(reg *Registry)
+	}{
+		{"duplicate name", func(reg *Registry) {
+			reg.NewCounter("dup_total", "")
+			reg.NewCounter("dup_total", "")
+		}},
+		{"invalid name", func(reg *Registry) { reg.NewCounter("bad-name", "") }},
+		{"reserved label", func(reg *Registry) { reg.NewHistogram("h_seconds", "", nil, "le") }},
+		{"unsorted buckets", func(reg *Registry) { reg.NewHistogram("h_seconds", "", []float64{1, 0.5}) }},
+		{"label count", func(reg *Registry) { reg.NewCounter("c_total", "", "a", "b").Inc("x") }},
+		{"negative add", func(reg *Registry) { reg.NewCounter("c_total", "").Add(-1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(func(r *http.Request) string {
+		if r.URL.Path == "/missing" {
+			return ""
+		}
+		return "/devices/{uid}"
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			_, _ = io.WriteString(w, "ok")
+		}
+	}))
+
+	before := HTTPRequests.Value(http.MethodGet, "/devices/{uid}", "200")
+	beforeCount := HTTPDuration.Count(http.MethodGet, "/devices/{uid}", "200")
+	beforeMissing := HTTPRequests.Value(http.MethodGet, unmatchedRoute, "404")
+
+	for _, path := range []string{"/devices/dev-1", "/devices/dev-2", "/missing"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := HTTPRequests.Value(http.MethodGet, "/devices/{uid}", "200") - before; got != 2 {
+		t.Errorf("expected 2 requests counted for the route, got %v", got)
+	}
+	if got := HTTPDuration.Count(http.MethodGet, "/devices/{uid}", "200") - beforeCount; got != 2 {
+		t.Errorf("expected 2 durations observed for the route, got %d", got)
+	}
+	if got := HTTPRequests.Value(http.MethodGet, unmatchedRoute, "404") - beforeMissing; got != 1 {
+		t.Errorf("expected 1 unmatched request, got %v", got)
+	}
+}
+
+func TestObserveReconcile(t *testing.T) {
+	success := ReconcileDuration.Count("Device", "success")
+	failure := ReconcileDuration.Count("Device", "error")
+
+	ObserveReconcile("Device", time.Millisecond, nil)
+	ObserveReconcile("Device", time.Millisecond, errors.New("unreachable"))
+
+	if ReconcileDuration.Count("Device", "success") != success+1 || ReconcileDuration.Count("Device", "error") != failure+1 {
+		t.Error("expected one success and one error observation")
+	}
+	if ReconcileTotal.Value("Device", "success") != float64(success+1) || ReconcileTotal.Value("Device", "error") != float64(failure+1) {
+		t.Error("expected reconciliations to be counted by result")
+	}
+	if ReconcileErrors.Value("Device") != float64(failure+1) {
+		t.Error("expected the error to be counted")
+	}
+
+	retries := ReconcileRetries.Value("Device")
+	ObserveReconcileRetry("Device")
+	if ReconcileRetries.Value("Device") != retries+1 {
+		t.Error("expected the retry to be counted")
+	}
+
+	queued := 4
+	ObserveQueueDepth(func() int { return queued })
+	if ReconcileQueueDepth.Value() != 4 {
+		t.Errorf("queue depth = %v, want 4", ReconcileQueueDepth.Value())
+	}
+	ObserveQueueDepth(nil)
+	if ReconcileQueueDepth.Value() != 0 {
+		t.Errorf("queue depth = %v after the controller stopped, want 0", ReconcileQueueDepth.Value())
+	}
+}
+
+func TestInstrumentEventBus(t *testing.T) {
+	bus := events.NewInMemoryEventBus(10, 1)
+	instrumented := InstrumentEventBus(bus)
+	if InstrumentEventBus(instrumented) != instrumented {
+		t.Error("expected instrumenting twice to return the same bus")
+	}
+
+	event, err := events.NewEvent("io.fabrica.device.created", "test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := EventPublishFailures.Value(event.Type())
+
+	// The bus isn't started, so closing it makes every publish fail
+	_ = bus.Close()
+	if err := instrumented.Publish(context.Background(), *event); err == nil {
+		t.Fatal("expected publishing on a closed bus to fail")
+	}
+
+	if got := EventPublishFailures.Value(event.Type()) - before; got != 1 {
+		t.Errorf("expected 1 publish failure, got %v", got)
+	}
+	if !strings.Contains(Default.String(), `fabrica_event_publish_failures_total{type="io.fabrica.device.created"}`) {
+		t.Error("expected the failure in the default registry")
+	}
+}
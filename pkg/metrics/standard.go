@@ -0,0 +1,215 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths
+// can't grow the number of series
+const unmatchedRoute = "unmatched"
+
+// Standard metrics of generated servers
+var (
+	// HTTPRequests counts requests by method, route pattern and status
+	HTTPRequests = Default.NewCounter("fabrica_http_requests_total",
+		"HTTP requests served, by method, route and status.", "method", "route", "status")
+
+	// HTTPDuration observes request durations by method, route pattern and status
+	HTTPDuration = Default.NewHistogram("fabrica_http_request_duration_seconds",
+		"Duration of HTTP requests, by method, route and status.", nil, "method", "route", "status")
+
+	// StorageDuration observes storage operation latency by kind and operation
+	StorageDuration = Default.NewHistogram("fabrica_storage_operation_duration_seconds",
+		"Duration of storage operations, by resource kind and operation.", nil, "kind", "operation")
+
+	// EventPublishFailures counts events the event bus failed to publish, by event type
+	EventPublishFailures = Default.NewCounter("fabrica_event_publish_failures_total",
+		"Events the event bus failed to publish, by event type.", "type")
+
+	// ReconcileDuration observes reconcile durations by kind and result (success or error)
+	ReconcileDuration = Default.NewHistogram("fabrica_reconcile_duration_seconds",
+		"Duration of reconciliations, by resource kind and result.", nil, "kind", "result")
+
+	// ReconcileTotal counts reconciliations by kind and result (success or error)
+	ReconcileTotal = Default.NewCounter("fabrica_reconcile_total",
+		"Reconciliations, by resource kind and result.", "kind", "result")
+
+	// ReconcileErrors counts failed reconciliations by kind
+	ReconcileErrors = Default.NewCounter("fabrica_reconcile_errors_total",
+		"Failed reconciliations, by resource kind.", "kind")
+
+	// ReconcileRetries counts failed reconciliations requeued to be retried, by kind
+	ReconcileRetries = Default.NewCounter("fabrica_reconcile_retries_total",
+		"Failed reconciliations requeued for a retry, by resource kind.", "kind")
+
+	// ReconcileQueueDepth reports the reconcile requests waiting for a worker
+	ReconcileQueueDepth = Default.NewGauge("fabrica_reconcile_queue_depth",
+		"Reconcile requests waiting in the work queue.")
+)
+
+// Middleware records HTTPRequests and HTTPDuration for every request.
+//
+// Requests are labeled with the route pattern rather than the path, so
+// resource UIDs don't create a series each. The pattern is read once the
+// request is served, when the router has matched it.
+//
+// Parameters:
+//   - route: Returns the route pattern of a served request, e.g. chi's
+//     RoutePattern; "" labels the request "unmatched"
+//
+// Returns:
+//   - func(http.Handler) http.Handler: Middleware for any router
+//
+// Example:
+//
+//	r.Use(metrics.Middleware(func(r *http.Request) string {
+//	    return chi.RouteContext(r.Context()).RoutePattern()
+//	}))
+func Middleware(route func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			pattern := route(r)
+			if pattern == "" {
+				pattern = unmatchedRoute
+			}
+			code := strconv.Itoa(status)
+			HTTPRequests.Inc(r.Method, pattern, code)
+			HTTPDuration.Observe(time.Since(start).Seconds(), r.Method, pattern, code)
+		})
+	}
+}
+
+// statusRecorder records the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush supports streaming responses such as watches
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// ObserveStorage records the duration of a storage operation in
+// StorageDuration. It is meant to be deferred at the start of the operation.
+//
+// Example:
+//
+//	func LoadDevice(ctx context.Context, uid string) (*device.Device, error) {
+//	    defer metrics.ObserveStorage("Device", "load", time.Now())
+//	    ...
+//	}
+func ObserveStorage(kind, operation string, start time.Time) {
+	StorageDuration.Observe(time.Since(start).Seconds(), kind, operation)
+}
+
+// ObserveReconcile records a reconciliation in ReconcileTotal,
+// ReconcileErrors and ReconcileDuration. Its signature matches
+// reconcile.ObserveFunc.
+//
+// Example:
+//
+//	controller.SetObserver(metrics.ObserveReconcile)
+func ObserveReconcile(kind string, d time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+		ReconcileErrors.Inc(kind)
+	}
+	ReconcileTotal.Inc(kind, result)
+	ReconcileDuration.Observe(d.Seconds(), kind, result)
+}
+
+// ObserveReconcileRetry records a retry of a failed reconciliation in
+// ReconcileRetries. Its signature matches reconcile.RetryFunc.
+//
+// Example:
+//
+//	controller.SetRetryObserver(metrics.ObserveReconcileRetry)
+func ObserveReconcileRetry(kind string) {
+	ReconcileRetries.Inc(kind)
+}
+
+// ObserveQueueDepth makes ReconcileQueueDepth report depth, e.g. the
+// QueueDepth method of a reconcile.Controller. A nil depth reports 0, for
+// when the controller stops.
+//
+// Example:
+//
+//	metrics.ObserveQueueDepth(controller.QueueDepth)
+func ObserveQueueDepth(depth func() int) {
+	if depth == nil {
+		ReconcileQueueDepth.Set(0)
+		return
+	}
+	ReconcileQueueDepth.SetFunc(func() float64 { return float64(depth()) })
+}
+
+// InstrumentEventBus returns bus counting its publish failures in
+// EventPublishFailures. Instrumenting an instrumented bus returns it as is.
+//
+// Example:
+//
+//	events.SetGlobalEventBus(metrics.InstrumentEventBus(eventBus))
+func InstrumentEventBus(bus events.EventBus) events.EventBus {
+	if _, ok := bus.(*instrumentedBus); ok || bus == nil {
+		return bus
+	}
+	return &instrumentedBus{EventBus: bus}
+}
+
+// instrumentedBus counts the publish failures of an event bus
+type instrumentedBus struct {
+	events.EventBus
+}
+
+func (b *instrumentedBus) Publish(ctx context.Context, event events.Event) error {
+	err := b.EventBus.Publish(ctx, event)
+	if err != nil {
+		EventPublishFailures.Inc(event.Type())
+	}
+	return err
+}
+
+// Shutdown shuts the instrumented bus down (see events.ShutdownBus)
+func (b *instrumentedBus) Shutdown(ctx context.Context) error {
+	return events.ShutdownBus(ctx, b.EventBus)
+}
@@ -0,0 +1,155 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package namespace partitions resources into namespaces for multi-tenancy.
+//
+// A request's namespace travels in its context. Middleware takes it from the
+// URL (e.g., /namespaces/{namespace}/devices), checks that the caller may
+// access it and adds it to the context; storage functions then read and
+// write only the resources of that namespace:
+//
+//	r.Route("/namespaces/{namespace}", func(r chi.Router) {
+//		r.Use(namespace.Middleware(namespace.Options{
+//			Namespace: func(r *http.Request) string { return chi.URLParam(r, "namespace") },
+//		}))
+//		r.Get("/devices", GetDevices)
+//	})
+//
+// Callers may access the namespaces listed in the "namespaces" claim of
+// their token ("*" for all), or only the default namespace without it.
+// Requests without claims (authentication turned off) may access any.
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/openchami/fabrica/pkg/auth"
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/logging"
+)
+
+// Default is the namespace of requests that don't name one. Its resources
+// are stored where resources were stored before namespaces were enabled.
+const Default = "default"
+
+// DefaultClaim is the token claim listing the namespaces a caller may access
+const DefaultClaim = "namespaces"
+
+// Wildcard in the namespaces claim grants access to every namespace
+const Wildcard = "*"
+
+// nameRE matches DNS labels (RFC 1123), which are safe in URLs and paths
+var nameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Validate checks that ns is a valid namespace name: a lowercase DNS label
+// of at most 63 characters.
+func Validate(ns string) error {
+	if len(ns) > 63 || !nameRE.MatchString(ns) {
+		return fmt.Errorf("invalid namespace %q: must be a lowercase DNS label (a-z, 0-9 and '-', at most 63 characters)", ns)
+	}
+	return nil
+}
+
+// contextKey keys the namespace in request contexts
+type contextKey struct{}
+
+// NewContext returns a copy of ctx in namespace ns.
+func NewContext(ctx context.Context, ns string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ns)
+}
+
+// FromContext returns the namespace of ctx, or Default if it has none.
+func FromContext(ctx context.Context) string {
+	if ns, ok := ctx.Value(contextKey{}).(string); ok && ns != "" {
+		return ns
+	}
+	return Default
+}
+
+// StorageType returns the storage resource type of a kind's resources in a
+// namespace: the kind itself in the default namespace, otherwise
+// "<kind>/<namespace>", which FileBackend stores in a subdirectory of the
+// kind's directory.
+func StorageType(kind, ns string) string {
+	if ns == "" || ns == Default {
+		return kind
+	}
+	return kind + "/" + ns
+}
+
+// Allowed reports whether a caller may access a namespace: one listed in
+// the claim, any with the "*" wildcard, or the default namespace if the
+// claim is missing.
+func Allowed(claims *auth.Claims, claim, ns string) bool {
+	granted := claims.Strings(claim)
+	if len(granted) == 0 {
+		return ns == Default
+	}
+	return slices.Contains(granted, ns) || slices.Contains(granted, Wildcard)
+}
+
+// Options configures Middleware.
+type Options struct {
+	// Namespace returns the namespace a request addresses, e.g. from the
+	// router's URL parameters; nil or "" means Default
+	Namespace func(r *http.Request) string
+
+	// Claim is the token claim listing the caller's namespaces (default
+	// "namespaces")
+	Claim string
+
+	// NonEnforcing logs requests for namespaces the caller may not access
+	// and lets them through, for rolling out tenant isolation gradually
+	NonEnforcing bool
+}
+
+// Middleware returns middleware putting requests in the namespace they
+// address. Invalid namespaces are rejected with 400, and callers whose
+// claims don't grant the namespace with 403 (see Allowed).
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	claim := opts.Claim
+	if claim == "" {
+		claim = DefaultClaim
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ns := Default
+			if opts.Namespace != nil {
+				if v := opts.Namespace(r); v != "" {
+					ns = v
+				}
+			}
+			if err := Validate(ns); err != nil {
+				writeProblem(w, http.StatusBadRequest, errcode.Wrap(errcode.InvalidRequest, err))
+				return
+			}
+
+			logger := logging.FromContext(r.Context()).With("namespace", ns)
+			if claims, ok := auth.FromContext(r.Context()); ok && !Allowed(claims, claim, ns) {
+				if !opts.NonEnforcing {
+					logger.Info("namespace access denied")
+					writeProblem(w, http.StatusForbidden, errcode.Wrap(errcode.Forbidden, fmt.Errorf("%q may not access namespace %q", claims.Subject, ns)))
+					return
+				}
+				logger.Warn("namespace access denied (non-enforcing)")
+			}
+
+			ctx := logging.NewContext(NewContext(r.Context(), ns), logger)
+			logging.AddRequestAttrs(ctx, "namespace", ns)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// writeProblem writes the problem document of a rejected request
+func writeProblem(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", errcode.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errcode.NewProblem(status, err))
+}
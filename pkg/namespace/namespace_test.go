@@ -0,0 +1,102 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/auth"
+)
+
+func TestValidate(t *testing.T) {
+	for _, ns := range []string{"default", "tenant-a", "a", "team42"} {
+		if err := Validate(ns); err != nil {
+			t.Errorf("Validate(%q): unexpected error %v", ns, err)
+		}
+	}
+	for _, ns := range []string{"", "Tenant", "-a", "a-", "a_b", "../etc", "a/b", strings.Repeat("a", 64)} {
+		if err := Validate(ns); err == nil {
+			t.Errorf("Validate(%q): expected an error", ns)
+		}
+	}
+}
+
+func TestContextAndStorageType(t *testing.T) {
+	ctx := context.Background()
+	if ns := FromContext(ctx); ns != Default {
+		t.Errorf("Expected the default namespace, got %q", ns)
+	}
+	if ns := FromContext(NewContext(ctx, "tenant-a")); ns != "tenant-a" {
+		t.Errorf("Expected tenant-a, got %q", ns)
+	}
+	if got := StorageType("Device", Default); got != "Device" {
+		t.Errorf("Expected the default namespace to be stored unpartitioned, got %q", got)
+	}
+	if got := StorageType("Device", "tenant-a"); got != "Device/tenant-a" {
+		t.Errorf("Expected Device/tenant-a, got %q", got)
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		raw     map[string]interface{}
+		ns      string
+		allowed bool
+	}{
+		{map[string]interface{}{}, Default, true},
+		{map[string]interface{}{}, "tenant-a", false},
+		{map[string]interface{}{"namespaces": []interface{}{"tenant-a"}}, "tenant-a", true},
+		{map[string]interface{}{"namespaces": []interface{}{"tenant-a"}}, Default, false},
+		{map[string]interface{}{"namespaces": "tenant-a tenant-b"}, "tenant-b", true},
+		{map[string]interface{}{"namespaces": []interface{}{"*"}}, "tenant-c", true},
+	}
+	for _, tt := range tests {
+		claims := &auth.Claims{Subject: "alice", Raw: tt.raw}
+		if got := Allowed(claims, DefaultClaim, tt.ns); got != tt.allowed {
+			t.Errorf("Allowed(%v, %q) = %v, want %v", tt.raw, tt.ns, got, tt.allowed)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen string
+	h := Middleware(Options{
+		Namespace: func(r *http.Request) string { return r.URL.Query().Get("ns") },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	do := func(query string, claims *auth.Claims) int {
+		seen = ""
+		req := httptest.NewRequest(http.MethodGet, "/devices"+query, nil)
+		if claims != nil {
+			req = req.WithContext(auth.NewContext(req.Context(), claims))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	tenant := &auth.Claims{Subject: "alice", Raw: map[string]interface{}{"namespaces": []interface{}{"tenant-a"}}}
+
+	if code := do("", nil); code != http.StatusNoContent || seen != Default {
+		t.Errorf("Expected the default namespace, got %d in %q", code, seen)
+	}
+	if code := do("?ns=tenant-a", tenant); code != http.StatusNoContent || seen != "tenant-a" {
+		t.Errorf("Expected tenant-a, got %d in %q", code, seen)
+	}
+	if code := do("?ns=tenant-b", tenant); code != http.StatusForbidden || seen != "" {
+		t.Errorf("Expected 403 for another tenant's namespace, got %d", code)
+	}
+	if code := do("?ns=tenant-b", nil); code != http.StatusNoContent {
+		t.Errorf("Expected requests without claims through, got %d", code)
+	}
+	if code := do("?ns=Bad_NS", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid namespace, got %d", code)
+	}
+}
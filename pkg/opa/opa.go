@@ -0,0 +1,313 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package opa delegates authorization decisions to Open Policy Agent.
+//
+// For each request an Authorizer builds an Input (resource kind, verb, UID,
+// request object and caller identity) and asks an Evaluator to decide. The
+// Client evaluator queries an OPA sidecar's Data API; embedded Rego policies
+// plug in through the Evaluator interface:
+//
+//	a, err := opa.New(opa.Options{
+//		Evaluator: opa.NewClient("http://localhost:8181", "fabrica/authz", nil),
+//	})
+//	r.With(a.Require("Device", "delete")).Delete("/devices/{uid}", DeleteDevice)
+//
+// Policies return either a boolean or an object with "allow" and an
+// optional "reason":
+//
+//	package fabrica.authz
+//
+//	default allow := false
+//	allow if input.verb in {"get", "list"}
+//	allow if "admins" in input.claims.groups
+//
+// Denied requests are rejected with 403 and the problem document of
+// pkg/rbac, whose "denied" member carries the policy's reason.
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/auth"
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/logging"
+	"github.com/openchami/fabrica/pkg/rbac"
+)
+
+// DefaultMaxObjectSize is the largest request body passed to policies as
+// Input.Object if Options.MaxObjectSize is zero
+const DefaultMaxObjectSize = 1 << 20
+
+// Input is the input document of policy queries.
+type Input struct {
+	// Kind is the resource kind of the request (e.g., "Device")
+	Kind string `json:"kind"`
+	// Verb is get, list, create, update, delete or a custom action's path
+	Verb string `json:"verb"`
+	// UID is the UID of the resource addressed, if any
+	UID string `json:"uid,omitempty"`
+	// Method is the HTTP method of the request
+	Method string `json:"method"`
+	// Path is the URL path of the request
+	Path string `json:"path"`
+	// Object is the JSON request body of creates, updates and actions
+	Object interface{} `json:"object,omitempty"`
+	// Subject is the caller's token subject
+	Subject string `json:"subject,omitempty"`
+	// Claims are all claims of the caller's token
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+// Decision is the result of a policy query.
+type Decision struct {
+	// Allow reports whether the request may proceed
+	Allow bool `json:"allow"`
+	// Reason optionally explains a denial
+	Reason string `json:"reason,omitempty"`
+}
+
+// Evaluator decides policy queries.
+type Evaluator interface {
+	Evaluate(ctx context.Context, input Input) (Decision, error)
+}
+
+// EvaluatorFunc adapts a function to Evaluator, e.g. one evaluating a
+// prepared query of embedded Rego policies.
+type EvaluatorFunc func(ctx context.Context, input Input) (Decision, error)
+
+// Evaluate calls f.
+func (f EvaluatorFunc) Evaluate(ctx context.Context, input Input) (Decision, error) {
+	return f(ctx, input)
+}
+
+// Client queries the Data API of an OPA server, typically a sidecar.
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient creates a Client for the decision at a slash-separated path of
+// an OPA server's data tree (e.g., "fabrica/authz" for package
+// fabrica.authz, or "fabrica/authz/allow" for a boolean rule).
+func NewClient(baseURL, decision string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		url:  strings.TrimRight(baseURL, "/") + "/v1/data/" + strings.Trim(decision, "/"),
+		http: httpClient,
+	}
+}
+
+// Evaluate posts the input to the decision's Data API endpoint.
+//
+// Returns:
+//   - Decision: The decision; undefined decisions deny
+//   - error: An error if OPA is unreachable or answers with an error or an
+//     unexpected result
+func (c *Client) Evaluate(ctx context.Context, input Input) (Decision, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to query OPA: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return Decision{}, fmt.Errorf("OPA query failed with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var answer struct {
+		Result *json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode OPA response: %w", err)
+	}
+	if answer.Result == nil {
+		return Decision{Reason: "policy decision is undefined"}, nil
+	}
+	return ParseResult(*answer.Result)
+}
+
+// ParseResult parses a policy result: a boolean, or an object with an
+// "allow" boolean and an optional "reason" string.
+func ParseResult(result []byte) (Decision, error) {
+	var allow bool
+	if err := json.Unmarshal(result, &allow); err == nil {
+		return Decision{Allow: allow}, nil
+	}
+	var d struct {
+		Allow  *bool  `json:"allow"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(result, &d); err != nil || d.Allow == nil {
+		return Decision{}, fmt.Errorf("unexpected policy result %s: want a boolean or {\"allow\": bool}", result)
+	}
+	return Decision{Allow: *d.Allow, Reason: d.Reason}, nil
+}
+
+// Options configures an Authorizer.
+type Options struct {
+	// Evaluator decides policy queries (required)
+	Evaluator Evaluator
+
+	// UID returns the UID addressed by a request, e.g. from the router's
+	// URL parameters; nil leaves Input.UID empty
+	UID func(r *http.Request) string
+
+	// MaxObjectSize is the largest request body passed as Input.Object
+	// (default DefaultMaxObjectSize); larger bodies are passed without it
+	MaxObjectSize int64
+
+	// NonEnforcing logs denials and lets the requests through, for rolling
+	// out a policy gradually
+	NonEnforcing bool
+}
+
+// Authorizer authorizes requests with an Evaluator.
+type Authorizer struct {
+	evaluator     Evaluator
+	uid           func(r *http.Request) string
+	maxObjectSize int64
+	nonEnforcing  bool
+}
+
+// New creates an Authorizer.
+//
+// Returns:
+//   - *Authorizer: The authorizer
+//   - error: An error if no Evaluator is configured
+func New(opts Options) (*Authorizer, error) {
+	if opts.Evaluator == nil {
+		return nil, errors.New("opa: an evaluator is required")
+	}
+	maxObjectSize := opts.MaxObjectSize
+	if maxObjectSize <= 0 {
+		maxObjectSize = DefaultMaxObjectSize
+	}
+	return &Authorizer{
+		evaluator:     opts.Evaluator,
+		uid:           opts.UID,
+		maxObjectSize: maxObjectSize,
+		nonEnforcing:  opts.NonEnforcing,
+	}, nil
+}
+
+// Authorize asks the policy whether the input's caller may perform its
+// verb on its kind.
+//
+// Returns:
+//   - error: nil if allowed, a *rbac.Denial if denied, or the evaluation error
+func (a *Authorizer) Authorize(ctx context.Context, input Input) error {
+	decision, err := a.evaluator.Evaluate(ctx, input)
+	if err != nil {
+		return err
+	}
+	if !decision.Allow {
+		reason := decision.Reason
+		if reason == "" {
+			reason = "denied by policy"
+		}
+		return &rbac.Denial{Subject: input.Subject, Kind: input.Kind, Verb: input.Verb, Reason: reason}
+	}
+	return nil
+}
+
+// Require returns middleware allowing requests the policy allows. It needs
+// the claims of auth.Authenticator.Middleware: requests without them are
+// rejected with 401, denied requests with 403 and a rbac.Problem document,
+// and requests whose decision fails with 500.
+func (a *Authorizer) Require(kind, verb string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := logging.FromContext(r.Context())
+			claims, ok := auth.FromContext(r.Context())
+			if !ok {
+				if a.nonEnforcing {
+					logger.Warn("unauthenticated request (non-enforcing)", "kind", kind, "verb", verb)
+					next.ServeHTTP(w, r)
+					return
+				}
+				rbac.WriteProblem(w, http.StatusUnauthorized, errcode.Wrap(errcode.Unauthorized, errors.New("authentication required")))
+				return
+			}
+
+			input := Input{
+				Kind:    kind,
+				Verb:    verb,
+				Method:  r.Method,
+				Path:    r.URL.Path,
+				Subject: claims.Subject,
+				Claims:  claims.Raw,
+			}
+			if a.uid != nil {
+				input.UID = a.uid(r)
+			}
+			input.Object = a.object(r)
+
+			err := a.Authorize(r.Context(), input)
+			var denial *rbac.Denial
+			switch {
+			case err == nil:
+			case errors.As(err, &denial):
+				if a.nonEnforcing {
+					logger.Warn("permission denied (non-enforcing)", "kind", kind, "verb", verb, "reason", denial.Reason)
+					break
+				}
+				logger.Info("permission denied", "kind", kind, "verb", verb, "reason", denial.Reason)
+				rbac.WriteProblem(w, http.StatusForbidden, errcode.Wrap(errcode.Forbidden, err))
+				return
+			default:
+				logger.Error("authorization decision failed", "kind", kind, "verb", verb, "error", err)
+				if !a.nonEnforcing {
+					rbac.WriteProblem(w, http.StatusInternalServerError, errcode.Wrap(errcode.Internal, errors.New("authorization decision failed")))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// object decodes the JSON body of a request for the policy input and
+// restores the body for the handler. Bodies that are empty, too large or
+// not JSON are left out.
+func (a *Authorizer) object(r *http.Request) interface{} {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r.Body, a.maxObjectSize+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+	if err != nil || int64(len(data)) > a.maxObjectSize {
+		return nil
+	}
+	var object interface{}
+	if json.Unmarshal(data, &object) != nil {
+		return nil
+	}
+	return object
+}
@@ -0,0 +1,143 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package opa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/auth"
+	"github.com/openchami/fabrica/pkg/rbac"
+)
+
+func TestClient(t *testing.T) {
+	var result string
+	var got struct {
+		Input Input `json:"input"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/data/fabrica/authz" {
+			http.Error(w, "unexpected request "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, result)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL+"/", "/fabrica/authz", nil)
+	input := Input{Kind: "Device", Verb: "power-on", UID: "dev-1", Subject: "alice"}
+	tests := []struct {
+		result  string
+		want    Decision
+		wantErr bool
+	}{
+		{`{"result": true}`, Decision{Allow: true}, false},
+		{`{"result": {"allow": false, "reason": "maintenance window"}}`, Decision{Reason: "maintenance window"}, false},
+		{`{}`, Decision{Reason: "policy decision is undefined"}, false},
+		{`{"result": {"deny": true}}`, Decision{}, true},
+		{`{"result": "yes"}`, Decision{}, true},
+	}
+	for _, tt := range tests {
+		result = tt.result
+		d, err := c.Evaluate(context.Background(), input)
+		if (err != nil) != tt.wantErr || d != tt.want {
+			t.Errorf("%s: expected %+v (error %v), got %+v, %v", tt.result, tt.want, tt.wantErr, d, err)
+		}
+	}
+	if got.Input.Kind != "Device" || got.Input.Verb != "power-on" || got.Input.UID != "dev-1" {
+		t.Errorf("Unexpected input %+v", got.Input)
+	}
+
+	srv.Close()
+	if _, err := c.Evaluate(context.Background(), input); err == nil {
+		t.Error("Expected an unreachable OPA to fail")
+	}
+}
+
+func TestRequire(t *testing.T) {
+	var seen Input
+	policy := EvaluatorFunc(func(ctx context.Context, input Input) (Decision, error) {
+		seen = input
+		switch {
+		case input.Subject == "broken":
+			return Decision{}, errors.New("policy error")
+		case input.Verb == "get":
+			return Decision{Allow: true}, nil
+		}
+		if obj, ok := input.Object.(map[string]interface{}); ok && obj["name"] == "allowed" {
+			return Decision{Allow: true}, nil
+		}
+		return Decision{Reason: "only allowed may be created"}, nil
+	})
+	a, err := New(Options{Evaluator: policy, UID: func(r *http.Request) string { return "dev-1" }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body string
+	do := func(a *Authorizer, method, verb, subject, payload string) *httptest.ResponseRecorder {
+		body = ""
+		h := a.Require("Device", verb)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		req := httptest.NewRequest(method, "/devices", strings.NewReader(payload))
+		if subject != "" {
+			claims := &auth.Claims{Subject: subject, Raw: map[string]interface{}{"sub": subject}}
+			req = req.WithContext(auth.NewContext(req.Context(), claims))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	payload := `{"name": "allowed"}`
+	if rec := do(a, http.MethodPost, "create", "alice", payload); rec.Code != http.StatusNoContent || body != payload {
+		t.Errorf("Expected the create through with its body, got %d %q", rec.Code, body)
+	}
+	if seen.UID != "dev-1" || seen.Claims["sub"] != "alice" || seen.Method != http.MethodPost || seen.Path != "/devices" {
+		t.Errorf("Unexpected input %+v", seen)
+	}
+
+	rec := do(a, http.MethodPost, "create", "alice", `{"name": "other"}`)
+	var p rbac.Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusForbidden || p.Denied == nil || p.Denied.Reason != "only allowed may be created" {
+		t.Errorf("Expected 403 with the policy's reason, got %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := do(a, http.MethodGet, "get", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without claims, got %d", rec.Code)
+	}
+	if rec := do(a, http.MethodGet, "get", "broken", ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 when the decision fails, got %d", rec.Code)
+	}
+
+	// Large bodies reach the handler intact, without being passed to the policy
+	small, _ := New(Options{Evaluator: policy, MaxObjectSize: 8})
+	if rec := do(small, http.MethodPost, "create", "alice", payload); rec.Code != http.StatusForbidden || seen.Object != nil {
+		t.Errorf("Expected a denial without the object, got %d with %v", rec.Code, seen.Object)
+	}
+	lenient, _ := New(Options{Evaluator: policy, MaxObjectSize: 8, NonEnforcing: true})
+	if rec := do(lenient, http.MethodPost, "create", "alice", payload); rec.Code != http.StatusNoContent || body != payload {
+		t.Errorf("Expected a non-enforcing pass with the whole body, got %d %q", rec.Code, body)
+	}
+
+	if _, err := New(Options{}); err == nil {
+		t.Error("Expected options without an evaluator to fail")
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package pagination splits list responses into pages.
+//
+// Two modes are supported:
+//
+//	offset   ?limit=50&page=3          pages are numbered from 1
+//	cursor   ?limit=50&continue=<token> each page returns the token of the next
+//
+// Offset pages are easy to jump between, but items shift between pages when
+// resources are created or deleted while a client walks the list. Cursor
+// pages resume after the last item returned, so a walk neither skips nor
+// repeats items; the token is opaque to clients.
+//
+// In both modes items are ordered by a stable key (the resource UID), or by
+// an Order with the key breaking ties, and the response carries the page
+// metadata in headers, so the body stays a plain JSON array:
+//
+//	X-Total-Count      number of items across all pages
+//	Link               <...>; rel="next" (and "prev", "first", "last" for offset pages)
+//	X-Continue-Token   token of the next page (cursor mode, absent on the last page)
+//
+// Usage:
+//
+//	opts := pagination.Options{Mode: pagination.ModeCursor, DefaultLimit: 100}
+//	page, err := pagination.Paginate(w, r, devices, func(d *device.Device) string { return d.GetUID() }, opts)
+//	if err != nil {
+//	    // invalid limit, page or continue token: respond 400
+//	}
+//	respondJSON(w, http.StatusOK, page)
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Pagination modes
+const (
+	ModeOffset = "offset"
+	ModeCursor = "cursor"
+)
+
+// DefaultMaxLimit is the largest page size when Options.MaxLimit is not set
+const DefaultMaxLimit = 1000
+
+// Response headers
+const (
+	TotalCountHeader = "X-Total-Count"
+	ContinueHeader   = "X-Continue-Token"
+)
+
+// Options configures pagination of a list endpoint
+type Options struct {
+	// Mode is ModeOffset or ModeCursor
+	Mode string
+
+	// DefaultLimit is the page size of requests without a limit. Zero
+	// returns every item unless the client asks for a limit.
+	DefaultLimit int
+
+	// MaxLimit caps the page size (default: DefaultMaxLimit). Larger limits
+	// are lowered to it.
+	MaxLimit int
+}
+
+// Request selects a page
+type Request struct {
+	// Limit is the page size; zero means no limit
+	Limit int
+
+	// Page is the 1-based page number (offset mode)
+	Page int
+
+	// Continue is the token of the page to return (cursor mode)
+	Continue string
+}
+
+// Page describes the page returned to a client
+type Page struct {
+	// Total is the number of items across all pages
+	Total int
+
+	// Limit is the page size applied; zero when everything was returned
+	Limit int
+
+	// Page is the page number (offset mode)
+	Page int
+
+	// Next is the request for the following page, nil on the last page
+	Next *Request
+
+	// Prev is the request for the preceding page (offset mode), nil on the first page
+	Prev *Request
+
+	// Last is the number of the last page (offset mode)
+	Last int
+}
+
+// Order sorts a list by values other than the unique key, e.g. the fields
+// of a ?sort= parameter. Items with equal values are ordered by key.
+type Order[T any] struct {
+	// Name identifies the order in continue tokens, e.g. the sort parameter.
+	// Tokens from a list in another order are rejected.
+	Name string
+
+	// Values returns the sort values of an item. They are stored in continue
+	// tokens, so they must survive a JSON round trip (strings, float64,
+	// bools and nil).
+	Values func(T) ([]interface{}, error)
+
+	// Compare compares the sort values of two items
+	Compare func(a, b []interface{}) int
+}
+
+// cursor is the content of a continue token
+type cursor struct {
+	After  string        `json:"after"`
+	Order  string        `json:"order,omitempty"`
+	Values []interface{} `json:"values,omitempty"`
+}
+
+// ParseRequest reads the limit, page and continue query parameters.
+//
+// Parameters:
+//   - r: The list request
+//   - opts: Pagination mode and limits
+//
+// Returns:
+//   - Request: The requested page, with the default and maximum limits applied
+//   - error: If a parameter is malformed or doesn't apply to the mode
+func ParseRequest(r *http.Request, opts Options) (Request, error) {
+	q := r.URL.Query()
+	req := Request{Limit: opts.DefaultLimit, Page: 1, Continue: q.Get("continue")}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Request{}, fmt.Errorf("limit must be a positive integer, got %q", v)
+		}
+		req.Limit = n
+	}
+	if limit := maxLimit(opts); req.Limit > limit {
+		req.Limit = limit
+	}
+
+	switch opts.Mode {
+	case ModeOffset:
+		if req.Continue != "" {
+			return Request{}, fmt.Errorf("continue tokens are not supported, use page")
+		}
+		if v := q.Get("page"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return Request{}, fmt.Errorf("page must be a positive integer, got %q", v)
+			}
+			req.Page = n
+		}
+	case ModeCursor:
+		if q.Get("page") != "" {
+			return Request{}, fmt.Errorf("page numbers are not supported, use the continue token of the previous page")
+		}
+		if req.Continue != "" {
+			if _, err := decodeCursor(req.Continue); err != nil {
+				return Request{}, err
+			}
+		}
+	default:
+		return Request{}, fmt.Errorf("unknown pagination mode %q", opts.Mode)
+	}
+	return req, nil
+}
+
+// Apply returns one page of items.
+//
+// Items are ordered by key, which must be unique (e.g. the UID); the input
+// slice is not modified. A page past the end is empty. In cursor mode, a
+// page resumes after the key in its token even when that item was deleted.
+//
+// Parameters:
+//   - items: Every item of the list
+//   - key: Returns the unique sort key of an item
+//   - req: The requested page (see ParseRequest)
+//   - mode: ModeOffset or ModeCursor
+//
+// Returns:
+//   - []T: The items of the page
+//   - Page: Page metadata
+//   - error: If the continue token is invalid
+func Apply[T any](items []T, key func(T) string, req Request, mode string) ([]T, Page, error) {
+	return ApplyOrder(items, key, nil, req, mode)
+}
+
+// ApplyOrder is like Apply, but orders the items by order first and by key
+// among items with equal sort values. Continue tokens hold the sort values
+// of the last item of their page, so a page resumes at the right position
+// even when that item was deleted or changed.
+//
+// Parameters:
+//   - items: Every item of the list
+//   - key: Returns the unique key of an item
+//   - order: Sort order; nil orders by key only
+//   - req: The requested page (see ParseRequest)
+//   - mode: ModeOffset or ModeCursor
+//
+// Returns:
+//   - []T: The items of the page
+//   - Page: Page metadata
+//   - error: If the continue token is invalid or doesn't match the order, or
+//     order.Values fails
+func ApplyOrder[T any](items []T, key func(T) string, order *Order[T], req Request, mode string) ([]T, Page, error) {
+	type entry struct {
+		item   T
+		key    string
+		values []interface{}
+	}
+	sorted := make([]entry, len(items))
+	for i, item := range items {
+		sorted[i] = entry{item: item, key: key(item)}
+		if order != nil {
+			values, err := order.Values(item)
+			if err != nil {
+				return nil, Page{}, err
+			}
+			sorted[i].values = values
+		}
+	}
+	// compare orders an entry relative to a position in the list
+	compare := func(e entry, key string, values []interface{}) int {
+		if order != nil {
+			if cmp := order.Compare(e.values, values); cmp != 0 {
+				return cmp
+			}
+		}
+		return strings.Compare(e.key, key)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return compare(sorted[i], sorted[j].key, sorted[j].values) < 0 })
+	unwrap := func(entries []entry) []T {
+		result := make([]T, len(entries))
+		for i, e := range entries {
+			result[i] = e.item
+		}
+		return result
+	}
+
+	page := Page{Total: len(sorted), Limit: req.Limit}
+	if req.Limit <= 0 {
+		page.Limit, page.Page, page.Last = 0, 1, 1
+		return unwrap(sorted), page, nil
+	}
+
+	orderName := ""
+	if order != nil {
+		orderName = order.Name
+	}
+	start := 0
+	switch mode {
+	case ModeCursor:
+		if req.Continue != "" {
+			c, err := decodeCursor(req.Continue)
+			if err != nil {
+				return nil, Page{}, err
+			}
+			if c.Order != orderName {
+				return nil, Page{}, fmt.Errorf("continue token belongs to a list in another order")
+			}
+			start = sort.Search(len(sorted), func(i int) bool { return compare(sorted[i], c.After, c.Values) > 0 })
+		}
+	default:
+		pageNum := req.Page
+		if pageNum < 1 {
+			pageNum = 1
+		}
+		page.Page = pageNum
+		page.Last = (len(sorted) + req.Limit - 1) / req.Limit
+		if page.Last < 1 {
+			page.Last = 1
+		}
+		if pageNum > 1 {
+			page.Prev = &Request{Limit: req.Limit, Page: min(pageNum-1, page.Last)}
+		}
+		start = len(sorted)
+		if pageNum <= page.Last {
+			start = (pageNum - 1) * req.Limit
+		}
+	}
+
+	end := start + req.Limit
+	if end >= len(sorted) {
+		return unwrap(sorted[start:]), page, nil
+	}
+	if mode == ModeCursor {
+		last := sorted[end-1]
+		page.Next = &Request{Limit: req.Limit, Continue: encodeCursor(cursor{After: last.key, Order: orderName, Values: last.values})}
+	} else {
+		page.Next = &Request{Limit: req.Limit, Page: page.Page + 1}
+	}
+	return unwrap(sorted[start:end]), page, nil
+}
+
+// Paginate parses the page request, applies it to items and sets the
+// pagination headers on the response.
+//
+// Parameters:
+//   - w: Response writer to set the headers on
+//   - r: The list request
+//   - items: Every item of the list
+//   - key: Returns the unique sort key of an item
+//   - opts: Pagination mode and limits
+//
+// Returns:
+//   - []T: The items of the page, to be written as the response body
+//   - error: If the request parameters are invalid (respond 400)
+func Paginate[T any](w http.ResponseWriter, r *http.Request, items []T, key func(T) string, opts Options) ([]T, error) {
+	return PaginateOrder(w, r, items, key, nil, opts)
+}
+
+// PaginateOrder is like Paginate, with items in a custom order (see ApplyOrder).
+//
+// Parameters:
+//   - w: Response writer to set the headers on
+//   - r: The list request
+//   - items: Every item of the list
+//   - key: Returns the unique key of an item
+//   - order: Sort order; nil orders by key only
+//   - opts: Pagination mode and limits
+//
+// Returns:
+//   - []T: The items of the page, to be written as the response body
+//   - error: If the request parameters are invalid (respond 400)
+func PaginateOrder[T any](w http.ResponseWriter, r *http.Request, items []T, key func(T) string, order *Order[T], opts Options) ([]T, error) {
+	req, err := ParseRequest(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	result, page, err := ApplyOrder(items, key, order, req, opts.Mode)
+	if err != nil {
+		return nil, err
+	}
+	page.SetHeaders(w, r)
+	return result, nil
+}
+
+// SetHeaders sets the X-Total-Count, Link and X-Continue-Token headers of a page.
+//
+// Link URLs keep the path and the other query parameters (filters) of r.
+func (p Page) SetHeaders(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	h.Set(TotalCountHeader, strconv.Itoa(p.Total))
+
+	var links []string
+	link := func(req *Request, rel string) {
+		u := *r.URL
+		q := u.Query()
+		req.Encode(q)
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", u.RequestURI(), rel))
+	}
+	if p.Next != nil {
+		link(p.Next, "next")
+		if p.Next.Continue != "" {
+			h.Set(ContinueHeader, p.Next.Continue)
+		}
+	}
+	if p.Prev != nil {
+		link(p.Prev, "prev")
+	}
+	if p.Page > 0 && p.Limit > 0 {
+		link(&Request{Limit: p.Limit, Page: 1}, "first")
+		link(&Request{Limit: p.Limit, Page: p.Last}, "last")
+	}
+	if len(links) > 0 {
+		h.Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// Encode sets the query parameters of the request in q, replacing any page
+// parameters already there
+func (req Request) Encode(q url.Values) {
+	q.Del("limit")
+	q.Del("page")
+	q.Del("continue")
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Continue != "" {
+		q.Set("continue", req.Continue)
+	} else if req.Page > 0 {
+		q.Set("page", strconv.Itoa(req.Page))
+	}
+}
+
+// TotalCount returns the X-Total-Count of a list response, or -1 if it has none
+func TotalCount(h http.Header) int {
+	n, err := strconv.Atoi(h.Get(TotalCountHeader))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// NextRequest returns the request for the page following a list response,
+// from the rel="next" entry of its Link header.
+//
+// Parameters:
+//   - h: Headers of a list response
+//
+// Returns:
+//   - *Request: The next page, nil on the last page or without pagination
+func NextRequest(h http.Header) *Request {
+	for _, value := range h.Values("Link") {
+		for _, entry := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(entry), ";")
+			if !ok || !strings.Contains(params, `rel="next"`) {
+				continue
+			}
+			u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				return nil
+			}
+			q := u.Query()
+			req := &Request{Continue: q.Get("continue")}
+			req.Limit, _ = strconv.Atoi(q.Get("limit"))
+			req.Page, _ = strconv.Atoi(q.Get("page"))
+			return req
+		}
+	}
+	return nil
+}
+
+func maxLimit(opts Options) int {
+	if opts.MaxLimit > 0 {
+		return opts.MaxLimit
+	}
+	return DefaultMaxLimit
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c) // nolint:errcheck
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(token string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.After == "" {
+		return cursor{}, fmt.Errorf("invalid continue token")
+	}
+	return c, nil
+}
@@ -0,0 +1,211 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package pagination
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func identity(s string) string { return s }
+
+func TestParseRequest(t *testing.T) {
+	offset := Options{Mode: ModeOffset, DefaultLimit: 10, MaxLimit: 50}
+	cursorOpts := Options{Mode: ModeCursor}
+
+	tests := []struct {
+		name    string
+		target  string
+		opts    Options
+		want    Request
+		wantErr string
+	}{
+		{"defaults", "/devices", offset, Request{Limit: 10, Page: 1}, ""},
+		{"limit and page", "/devices?limit=5&page=3", offset, Request{Limit: 5, Page: 3}, ""},
+		{"limit capped", "/devices?limit=500", offset, Request{Limit: 50, Page: 1}, ""},
+		{"default max", "/devices?limit=5000", cursorOpts, Request{Limit: DefaultMaxLimit, Page: 1}, ""},
+		{"no default limit", "/devices", cursorOpts, Request{Page: 1}, ""},
+		{"bad limit", "/devices?limit=0", offset, Request{}, "limit"},
+		{"bad page", "/devices?page=x", offset, Request{}, "page"},
+		{"continue in offset mode", "/devices?continue=abc", offset, Request{}, "continue"},
+		{"page in cursor mode", "/devices?page=2", cursorOpts, Request{}, "page numbers"},
+		{"bad token", "/devices?continue=!!", cursorOpts, Request{}, "invalid continue token"},
+		{"unknown mode", "/devices", Options{Mode: "keyset"}, Request{}, "unknown"},
+	}
+	for _, tt := range tests {
+		got, err := ParseRequest(httptest.NewRequest("GET", tt.target, nil), tt.opts)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestApply_Offset(t *testing.T) {
+	items := []string{"e", "c", "a", "d", "b"}
+
+	page, info, err := Apply(items, identity, Request{Limit: 2, Page: 2}, ModeOffset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(page, []string{"c", "d"}) {
+		t.Errorf("unexpected page %v", page)
+	}
+	if info.Total != 5 || info.Last != 3 || info.Next.Page != 3 || info.Prev.Page != 1 {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if items[0] != "e" {
+		t.Error("input slice was reordered")
+	}
+
+	page, info, _ = Apply(items, identity, Request{Limit: 2, Page: 3}, ModeOffset)
+	if !reflect.DeepEqual(page, []string{"e"}) || info.Next != nil {
+		t.Errorf("last page: %v %+v", page, info)
+	}
+
+	page, info, _ = Apply(items, identity, Request{Limit: 2, Page: 9}, ModeOffset)
+	if len(page) != 0 || info.Next != nil || info.Prev.Page != 3 {
+		t.Errorf("page past the end: %v %+v", page, info)
+	}
+
+	page, info, _ = Apply(items, identity, Request{}, ModeOffset)
+	if len(page) != 5 || info.Next != nil || info.Limit != 0 {
+		t.Errorf("no limit: %v %+v", page, info)
+	}
+}
+
+func TestApply_Cursor(t *testing.T) {
+	items := []string{"e", "c", "a", "d", "b"}
+
+	var walked []string
+	req := Request{Limit: 2}
+	for pages := 0; pages < 10; pages++ {
+		page, info, err := Apply(items, identity, req, ModeCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pages == 0 && info.Total != 5 {
+			t.Errorf("unexpected total %d", info.Total)
+		}
+		walked = append(walked, page...)
+		if info.Next == nil {
+			break
+		}
+		req = *info.Next
+
+		// Items deleted or created before the cursor don't shift later pages
+		if pages == 0 {
+			items = []string{"e", "c", "d", "0"}
+		}
+	}
+	if !reflect.DeepEqual(walked, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("unexpected walk %v", walked)
+	}
+
+	if _, _, err := Apply(items, identity, Request{Limit: 2, Continue: "bad"}, ModeCursor); err == nil {
+		t.Error("expected an invalid token error")
+	}
+}
+
+func TestApplyOrder(t *testing.T) {
+	// Order by length, longest first; the key breaks ties
+	byLength := &Order[string]{
+		Name:   "-length",
+		Values: func(s string) ([]interface{}, error) { return []interface{}{float64(len(s))}, nil },
+		Compare: func(a, b []interface{}) int {
+			return int(b[0].(float64) - a[0].(float64))
+		},
+	}
+	items := []string{"b", "ccc", "a", "dd", "ee", "f"}
+
+	page, _, err := ApplyOrder(items, identity, byLength, Request{Limit: 4, Page: 1}, ModeOffset)
+	if err != nil || !reflect.DeepEqual(page, []string{"ccc", "dd", "ee", "a"}) {
+		t.Errorf("unexpected offset page %v %v", page, err)
+	}
+
+	var walked []string
+	req := Request{Limit: 2}
+	for pages := 0; pages < 10; pages++ {
+		page, info, err := ApplyOrder(items, identity, byLength, req, ModeCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		walked = append(walked, page...)
+		if info.Next == nil {
+			break
+		}
+		req = *info.Next
+
+		// The last item of the first page is deleted; the walk resumes after its position
+		if pages == 0 {
+			items = []string{"b", "ccc", "a", "ee", "f"}
+		}
+	}
+	if !reflect.DeepEqual(walked, []string{"ccc", "dd", "ee", "a", "b", "f"}) {
+		t.Errorf("unexpected walk %v", walked)
+	}
+
+	_, info, _ := ApplyOrder(items, identity, byLength, Request{Limit: 2}, ModeCursor)
+	if _, _, err := Apply(items, identity, *info.Next, ModeCursor); err == nil || !strings.Contains(err.Error(), "order") {
+		t.Errorf("expected a token of another order to be rejected, got %v", err)
+	}
+}
+
+func TestPaginate_Headers(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/devices?query=x&limit=2&page=2", nil)
+	page, err := Paginate(rec, req, items, identity, Options{Mode: ModeOffset})
+	if err != nil || len(page) != 2 {
+		t.Fatalf("unexpected page %v %v", page, err)
+	}
+	h := rec.Header()
+	if h.Get(TotalCountHeader) != "5" || h.Get(ContinueHeader) != "" {
+		t.Errorf("unexpected headers %v", h)
+	}
+	link := h.Get("Link")
+	for _, want := range []string{
+		`</devices?limit=2&page=3&query=x>; rel="next"`,
+		`</devices?limit=2&page=1&query=x>; rel="prev"`,
+		`</devices?limit=2&page=3&query=x>; rel="last"`,
+	} {
+		if !strings.Contains(link, want) {
+			t.Errorf("expected %s in Link %s", want, link)
+		}
+	}
+	if next := NextRequest(h); next == nil || *next != (Request{Limit: 2, Page: 3}) {
+		t.Errorf("unexpected next request %+v", next)
+	}
+	if TotalCount(h) != 5 {
+		t.Errorf("unexpected total count %d", TotalCount(h))
+	}
+
+	rec = httptest.NewRecorder()
+	_, err = Paginate(rec, httptest.NewRequest("GET", "/devices?limit=4", nil), items, identity, Options{Mode: ModeCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := rec.Header().Get(ContinueHeader)
+	if token == "" || NextRequest(rec.Header()).Continue != token {
+		t.Errorf("expected a continue token, got %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	page, _ = Paginate(rec, httptest.NewRequest("GET", "/devices?limit=4&continue="+token, nil), items, identity, Options{Mode: ModeCursor})
+	if !reflect.DeepEqual(page, []string{"e"}) || rec.Header().Get("Link") != "" || NextRequest(rec.Header()) != nil {
+		t.Errorf("last page: %v %v", page, rec.Header())
+	}
+	if TotalCount(rec.Header()) != 5 || TotalCount(httptest.NewRecorder().Header()) != -1 {
+		t.Errorf("unexpected total counts")
+	}
+}
@@ -23,6 +23,7 @@ package patch
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
@@ -45,6 +46,9 @@ const (
 
 	// StrategicMergePatch represents Kubernetes-style strategic merge patch
 	StrategicMergePatch PatchType = "application/strategic-merge-patch+json"
+
+	// ServerSideApply represents a server-side apply configuration (see package apply)
+	ServerSideApply PatchType = "application/apply-patch+json"
 )
 
 // Operation represents a JSON Patch operation (RFC 6902)
@@ -221,12 +225,62 @@ func DetectPatchType(contentType string) PatchType {
 		return ShorthandPatch
 	case string(StrategicMergePatch):
 		return StrategicMergePatch
+	case string(ServerSideApply):
+		return ServerSideApply
 	default:
 		// Default to JSON Merge Patch for standard application/json
 		return JSONMergePatch
 	}
 }
 
+// IsMergePatch reports whether a Content-Type header names JSON Merge Patch
+// explicitly. Unlike DetectPatchType, plain application/json is not a merge
+// patch.
+func IsMergePatch(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), string(JSONMergePatch))
+}
+
+// MergeInto applies a JSON Merge Patch (RFC 7386) to the JSON form of target
+// and decodes the result back into it.
+//
+// Fields the patch doesn't mention keep their values, and null removes a
+// field, leaving its zero value. Target is reset before decoding, so removed
+// fields don't keep their old values.
+//
+// Parameters:
+//   - target: Pointer to the value to patch, e.g. a spec or update request
+//   - patchData: The merge patch document
+//
+// Returns:
+//   - error: If the patch is invalid JSON, or the merged document doesn't
+//     decode into target (e.g. a string for a number field)
+//
+// Example:
+//
+//	spec := device.Spec
+//	err := patch.MergeInto(&spec, []byte(`{"manufacturer":"HPE","location":null}`))
+func MergeInto(target interface{}, patchData []byte) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("merge target must be a non-nil pointer, got %T", target)
+	}
+	original, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("failed to marshal merge target: %w", err)
+	}
+	merged, err := ApplyMergePatch(original, patchData)
+	if err != nil {
+		return err
+	}
+
+	value.Elem().Set(reflect.Zero(value.Elem().Type()))
+	if err := json.Unmarshal(merged, target); err != nil {
+		return fmt.Errorf("merged document is invalid: %w", err)
+	}
+	return nil
+}
+
 // ApplyPatch applies the appropriate patch based on the patch type
 func ApplyPatch(original []byte, patchData []byte, patchType PatchType) ([]byte, error) {
 	switch patchType {
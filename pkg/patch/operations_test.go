@@ -6,6 +6,7 @@ package patch
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -128,6 +129,7 @@ func TestDetectPatchType(t *testing.T) {
 		{"application/merge-patch+json", JSONMergePatch},
 		{"application/json-patch+json", JSONPatch},
 		{"application/shorthand-patch+json", ShorthandPatch},
+		{"application/apply-patch+json", ServerSideApply},
 		{"application/json", JSONMergePatch}, // Default
 		{"application/json; charset=utf-8", JSONMergePatch},
 	}
@@ -321,6 +323,53 @@ func TestMergePatchFromMap(t *testing.T) {
 	}
 }
 
+func TestIsMergePatch(t *testing.T) {
+	tests := map[string]bool{
+		"application/merge-patch+json":                true,
+		"Application/Merge-Patch+JSON; charset=utf-8": true,
+		"application/json":                            false,
+		"application/json-patch+json":                 false,
+		"":                                            false,
+	}
+	for contentType, want := range tests {
+		if got := IsMergePatch(contentType); got != want {
+			t.Errorf("IsMergePatch(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	type spec struct {
+		Manufacturer string            `json:"manufacturer"`
+		Model        string            `json:"model,omitempty"`
+		Ports        int               `json:"ports,omitempty"`
+		Labels       map[string]string `json:"labels,omitempty"`
+	}
+	original := spec{Manufacturer: "HPE", Model: "DL380", Ports: 4, Labels: map[string]string{"a": "1", "b": "2"}}
+
+	target := original
+	if err := MergeInto(&target, []byte(`{"manufacturer":"Dell","model":null,"labels":{"b":null,"c":"3"}}`)); err != nil {
+		t.Fatalf("MergeInto failed: %v", err)
+	}
+	want := spec{Manufacturer: "Dell", Ports: 4, Labels: map[string]string{"a": "1", "c": "3"}}
+	if !reflect.DeepEqual(target, want) {
+		t.Errorf("got %+v, want %+v", target, want)
+	}
+	if len(original.Labels) != 2 {
+		t.Error("the original labels were modified")
+	}
+
+	if err := MergeInto(&target, []byte(`{"ports":"many"}`)); err == nil {
+		t.Error("expected an error for a string in a number field")
+	}
+	if err := MergeInto(&target, []byte(`{`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if err := MergeInto(target, []byte(`{}`)); err == nil {
+		t.Error("expected an error for a non-pointer target")
+	}
+}
+
 func TestJSONPatchFromOperations(t *testing.T) {
 	ops := []Operation{
 		{Op: "replace", Path: "/age", Value: 31},
@@ -0,0 +1,297 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package protobuf
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	errTruncated        = errors.New("protobuf: message is truncated")
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	errGroup            = errors.New("protobuf: groups are not supported")
+)
+
+// Unmarshal decodes a message encoded by Marshal, or by any protobuf
+// library using the definitions from Schema. Unknown fields are skipped,
+// and repeated numbers are accepted packed or unpacked.
+//
+// Parameters:
+//   - data: The encoded message
+//   - v: Pointer to a struct, or to a slice for a list message; reset first
+//
+// Returns:
+//   - error: If data isn't a valid message for v
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("protobuf: cannot unmarshal into %T, expected a non-nil pointer", v)
+	}
+	rv = rv.Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+
+	switch {
+	case rv.Kind() == reflect.Struct && kindOf(rv.Type()) == kindMessage:
+		return decodeMessage(data, rv)
+	case rv.Kind() == reflect.Slice && shapeOf(rv.Type()).repeated:
+		// A list message: the items are field 1
+		return decodeFields(data, func(number int, val wireValue) error {
+			if number != 1 {
+				return nil
+			}
+			return decodeField(rv, val)
+		})
+	}
+	return fmt.Errorf("protobuf: cannot unmarshal into %T, expected a struct or a slice of structs", v)
+}
+
+// wireValue is an encoded field: n holds varints and fixed-size numbers,
+// b holds length-delimited values
+type wireValue struct {
+	wire int
+	n    uint64
+	b    []byte
+}
+
+// decodeFields calls fn for each field of a message
+func decodeFields(data []byte, fn func(number int, val wireValue) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+		data = data[n:]
+		number, val := int(tag>>3), wireValue{wire: int(tag & 7)}
+		if number == 0 {
+			return errors.New("protobuf: invalid field number 0")
+		}
+
+		switch val.wire {
+		case wireVarint:
+			if val.n, n = binary.Uvarint(data); n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errTruncated
+			}
+			val.n, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errTruncated
+			}
+			val.n, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return errTruncated
+			}
+			val.b, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return errGroup
+		}
+		if err := fn(number, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMessage decodes the fields of a struct, merging into v
+func decodeMessage(data []byte, v reflect.Value) error {
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return err
+	}
+	return decodeFields(data, func(number int, val wireValue) error {
+		for _, f := range fields {
+			if f.number == number {
+				if err := decodeField(settableField(v, f.index), val); err != nil {
+					return fmt.Errorf("%s: %w", f.name, err)
+				}
+				return nil
+			}
+		}
+		return nil // unknown field
+	})
+}
+
+// settableField returns a nested field, allocating nil embedded pointers
+func settableField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// decodeField decodes one occurrence of a field of any shape into v
+func decodeField(v reflect.Value, val wireValue) error {
+	s := shapeOf(v.Type())
+	switch {
+	case s.repeated:
+		if val.wire == wireBytes && kindOf(s.elem).packable() {
+			return decodePacked(v, val.b, s.elem)
+		}
+		item := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeValue(item, val); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, item))
+		return nil
+	case s.isMap:
+		return decodeEntry(v, val)
+	}
+	return decodeValue(v, val)
+}
+
+// decodePacked appends packed numbers or bools to a slice
+func decodePacked(v reflect.Value, data []byte, elem reflect.Type) error {
+	wire := wireVarint
+	switch kindOf(elem) {
+	case kindFloat32:
+		wire = wireFixed32
+	case kindFloat64:
+		wire = wireFixed64
+	}
+	for len(data) > 0 {
+		val := wireValue{wire: wire}
+		switch wire {
+		case wireVarint:
+			var n int
+			if val.n, n = binary.Uvarint(data); n <= 0 {
+				return errTruncated
+			}
+			data = data[n:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errTruncated
+			}
+			val.n, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return errTruncated
+			}
+			val.n, data = binary.LittleEndian.Uint64(data), data[8:]
+		}
+		item := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeValue(item, val); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, item))
+	}
+	return nil
+}
+
+// decodeEntry adds a map entry message (key 1, value 2) to a map
+func decodeEntry(v reflect.Value, val wireValue) error {
+	if val.wire != wireBytes {
+		return fmt.Errorf("unexpected wire type %d for a map entry", val.wire)
+	}
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+	key := reflect.New(v.Type().Key()).Elem()
+	value := reflect.New(v.Type().Elem()).Elem()
+	err := decodeFields(val.b, func(number int, field wireValue) error {
+		switch number {
+		case 1:
+			return decodeValue(key, field)
+		case 2:
+			return decodeValue(value, field)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		value.Set(reflect.New(value.Type().Elem()))
+	}
+	v.SetMapIndex(key, value)
+	return nil
+}
+
+// decodeValue decodes a singular value into v
+func decodeValue(v reflect.Value, val wireValue) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(v.Elem(), val)
+	}
+
+	k := kindOf(v.Type())
+	if want := wireTypeOf(k); val.wire != want {
+		return fmt.Errorf("unexpected wire type %d for %s, expected %d", val.wire, v.Type(), want)
+	}
+	switch k {
+	case kindBool:
+		v.SetBool(val.n != 0)
+	case kindInt:
+		v.SetInt(int64(val.n))
+	case kindUint:
+		v.SetUint(val.n)
+	case kindFloat32:
+		v.SetFloat(float64(math.Float32frombits(uint32(val.n))))
+	case kindFloat64:
+		v.SetFloat(math.Float64frombits(val.n))
+	case kindString:
+		v.SetString(string(val.b))
+	case kindBytes:
+		v.SetBytes(append([]byte{}, val.b...))
+	case kindText:
+		if !reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+			return fmt.Errorf("cannot decode text into %s", v.Type())
+		}
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(val.b)
+	case kindTime:
+		var seconds, nanos int64
+		err := decodeFields(val.b, func(number int, field wireValue) error {
+			switch number {
+			case 1:
+				seconds = int64(field.n)
+			case 2:
+				nanos = int64(field.n)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(time.Unix(seconds, nanos).UTC()))
+	case kindMessage:
+		return decodeMessage(val.b, v)
+	default:
+		return json.Unmarshal(val.b, v.Addr().Interface())
+	}
+	return nil
+}
+
+// wireTypeOf returns the wire type of a singular kind
+func wireTypeOf(k kind) int {
+	switch k {
+	case kindBool, kindInt, kindUint:
+		return wireVarint
+	case kindFloat32:
+		return wireFixed32
+	case kindFloat64:
+		return wireFixed64
+	}
+	return wireBytes
+}
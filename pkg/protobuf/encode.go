@@ -0,0 +1,239 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package protobuf
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Marshal encodes a struct, or a slice of structs, in the protobuf wire
+// format. A slice is encoded as a list message with the items in field 1.
+//
+// Parameters:
+//   - v: A struct, a pointer to one, or a slice of either
+//
+// Returns:
+//   - []byte: The encoded message
+//   - error: If v isn't a struct or slice of structs, or a field can't be encoded
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	switch {
+	case rv.Kind() == reflect.Struct && kindOf(rv.Type()) == kindMessage:
+		return appendMessage(nil, rv)
+	case rv.Kind() == reflect.Slice && shapeOf(rv.Type()).repeated:
+		return appendRepeated(nil, 1, rv, shapeOf(rv.Type()).elem)
+	}
+	return nil, fmt.Errorf("protobuf: cannot marshal %T, expected a struct or a slice of structs", v)
+}
+
+// appendMessage appends the fields of a struct
+func appendMessage(b []byte, v reflect.Value) ([]byte, error) {
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		if b, err = appendField(b, f.number, fv); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return b, nil
+}
+
+// fieldByIndex returns a nested field, or false behind a nil embedded pointer
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// appendField appends a field of any shape
+func appendField(b []byte, number int, v reflect.Value) ([]byte, error) {
+	s := shapeOf(v.Type())
+	switch {
+	case s.repeated:
+		return appendRepeated(b, number, v, s.elem)
+	case s.isMap:
+		return appendMap(b, number, v, s)
+	case s.optional:
+		if v.IsNil() {
+			return b, nil
+		}
+		return appendValue(b, number, v.Elem(), true)
+	}
+	return appendValue(b, number, v, false)
+}
+
+// appendRepeated appends the items of a slice, packed for numbers and bools
+func appendRepeated(b []byte, number int, v reflect.Value, elem reflect.Type) ([]byte, error) {
+	if v.Len() == 0 {
+		return b, nil
+	}
+	var err error
+	if kindOf(elem).packable() {
+		var packed []byte
+		for i := 0; i < v.Len(); i++ {
+			packed = appendScalar(packed, v.Index(i))
+		}
+		b = appendTag(b, number, wireBytes)
+		b = binary.AppendUvarint(b, uint64(len(packed)))
+		return append(b, packed...), nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		// Items are sent even when empty, so the list keeps its length
+		if b, err = appendValue(b, number, v.Index(i), true); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendMap appends one entry message (key 1, value 2) per map entry, in key order
+func appendMap(b []byte, number int, v reflect.Value, s shape) ([]byte, error) {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface()) })
+	for _, key := range keys {
+		entry, err := appendValue(nil, 1, key, true)
+		if err != nil {
+			return nil, err
+		}
+		if entry, err = appendValue(entry, 2, v.MapIndex(key), true); err != nil {
+			return nil, err
+		}
+		b = appendTag(b, number, wireBytes)
+		b = binary.AppendUvarint(b, uint64(len(entry)))
+		b = append(b, entry...)
+	}
+	return b, nil
+}
+
+// appendValue appends a singular value; zero values are left out unless force is set
+func appendValue(b []byte, number int, v reflect.Value, force bool) ([]byte, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !force {
+				return b, nil
+			}
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+		v = v.Elem()
+	}
+	if !force && v.IsZero() {
+		return b, nil
+	}
+
+	switch k := kindOf(v.Type()); k {
+	case kindBool, kindInt, kindUint:
+		b = appendTag(b, number, wireVarint)
+		return appendScalar(b, v), nil
+	case kindFloat32:
+		b = appendTag(b, number, wireFixed32)
+		return appendScalar(b, v), nil
+	case kindFloat64:
+		b = appendTag(b, number, wireFixed64)
+		return appendScalar(b, v), nil
+	case kindString:
+		return appendBytes(b, number, []byte(v.String())), nil
+	case kindBytes:
+		return appendBytes(b, number, v.Bytes()), nil
+	case kindText:
+		m, ok := v.Interface().(encoding.TextMarshaler)
+		if !ok {
+			// MarshalText has a pointer receiver
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			m = p.Interface().(encoding.TextMarshaler)
+		}
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(b, number, text), nil
+	case kindTime:
+		t := v.Interface().(time.Time)
+		var ts []byte
+		if t.Unix() != 0 {
+			ts = appendTag(ts, 1, wireVarint)
+			ts = binary.AppendUvarint(ts, uint64(t.Unix()))
+		}
+		if t.Nanosecond() != 0 {
+			ts = appendTag(ts, 2, wireVarint)
+			ts = binary.AppendUvarint(ts, uint64(t.Nanosecond()))
+		}
+		return appendBytes(b, number, ts), nil
+	case kindMessage:
+		msg, err := appendMessage(nil, v)
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(b, number, msg), nil
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return appendBytes(b, number, data), nil
+	}
+}
+
+// appendScalar appends a number or bool without a tag
+func appendScalar(b []byte, v reflect.Value) []byte {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendUvarint(b, uint64(v.Int()))
+	case reflect.Float32:
+		return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v.Float()))
+	}
+	return binary.AppendUvarint(b, v.Uint())
+}
+
+func appendTag(b []byte, number, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(number)<<3|uint64(wireType))
+}
+
+func appendBytes(b []byte, number int, data []byte) []byte {
+	b = appendTag(b, number, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
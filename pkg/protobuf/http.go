@@ -0,0 +1,95 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package protobuf
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Preferred reports whether an Accept header prefers protobuf to JSON. The
+// media type with the highest quality wins, and the earlier one on a tie.
+// Wildcards count as JSON, so clients have to ask for protobuf explicitly.
+//
+// Parameters:
+//   - accept: Accept header value
+//
+// Returns:
+//   - bool: True if the response should be protobuf
+func Preferred(accept string) bool {
+	best, proto := 0.0, false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		isProto := IsProtobuf(mediaType)
+		switch {
+		case isProto, mediaType == "application/json", mediaType == "application/*", mediaType == "*/*":
+		default:
+			continue
+		}
+		if q > best {
+			best, proto = q, isProto
+		}
+	}
+	return proto
+}
+
+// Middleware negotiates protobuf responses from the Accept header.
+//
+// The result is stored on the response writer (see Requested), so the
+// helpers writing responses can pick the encoding without the request.
+//
+// Example:
+//
+//	r.Group(func(r chi.Router) {
+//	    r.Use(protobuf.Middleware)
+//	    r.Get("/devices/{uid}", GetDevice)
+//	})
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if Preferred(r.Header.Get("Accept")) {
+			w = &protobufWriter{ResponseWriter: w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Requested reports whether Middleware chose protobuf for a response.
+// Writers wrapped by other middleware are unwrapped through their Unwrap
+// method.
+func Requested(w http.ResponseWriter) bool {
+	for w != nil {
+		if _, ok := w.(*protobufWriter); ok {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+	return false
+}
+
+// protobufWriter marks a response that should be protobuf
+type protobufWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController
+func (w *protobufWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
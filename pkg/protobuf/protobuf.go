@@ -0,0 +1,310 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package protobuf encodes resources in the protobuf wire format and
+// describes them with .proto definitions.
+//
+// Generated servers answer requests sent with Accept: application/protobuf
+// in this format, for clients that read resources at high rates. Messages
+// follow the Go types the way encoding/json sees them:
+//
+//   - Struct fields are message fields named after their JSON names and
+//     numbered in declaration order. Embedded structs are flattened, and a
+//     field can choose its number with a `fabrica:"proto=<n>"` tag
+//   - string, bool, integers and floats become string, bool, int64, uint64,
+//     float and double; []byte becomes bytes and time.Time becomes
+//     google.protobuf.Timestamp
+//   - Slices are repeated fields, and maps with string, integer or bool keys
+//     are map fields
+//   - Types with a text encoding (encoding.TextMarshaler) are strings
+//   - Values the wire format can't describe (interface{}, json.RawMessage,
+//     other json.Marshalers, lists of lists, maps of lists) are bytes
+//     holding their JSON
+//
+// A slice of resources is encoded as a list message holding the items in
+// field 1. Numbers follow the field order, so add new fields at the end of
+// a struct, or number them explicitly, to keep existing clients working.
+//
+// Usage:
+//
+//	data, err := protobuf.Marshal(device)
+//	err = protobuf.Unmarshal(data, &device)
+//	proto, err := protobuf.Schema("inventory.v1", reflect.TypeOf(device.Device{}))
+package protobuf
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the media type of protobuf requests and responses
+const ContentType = "application/protobuf"
+
+// IsProtobuf reports whether a Content-Type or Accept media type is
+// protobuf (application/protobuf or application/x-protobuf).
+//
+// Parameters:
+//   - mediaType: Header value, with or without parameters
+//
+// Returns:
+//   - bool: True for protobuf media types
+func IsProtobuf(mediaType string) bool {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	return parsed == ContentType || parsed == "application/x-protobuf"
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// kind is how a singular value is encoded
+type kind int
+
+const (
+	kindBool kind = iota
+	kindInt
+	kindUint
+	kindFloat32
+	kindFloat64
+	kindString
+	kindBytes
+	kindText    // encoding.TextMarshaler, as a string
+	kindJSON    // JSON in a bytes field
+	kindTime    // google.protobuf.Timestamp
+	kindMessage // nested struct
+)
+
+// Wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// implements reports whether a type, or a pointer to it, implements an interface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// kindOf classifies a singular (not repeated, not map) type
+func kindOf(t reflect.Type) kind {
+	switch {
+	case t == timeType:
+		return kindTime
+	case implements(t, jsonMarshalerType):
+		return kindJSON
+	case implements(t, textMarshalerType):
+		return kindText
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return kindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return kindInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindUint
+	case reflect.Float32:
+		return kindFloat32
+	case reflect.Float64:
+		return kindFloat64
+	case reflect.String:
+		return kindString
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindBytes
+		}
+	case reflect.Struct:
+		return kindMessage
+	}
+	return kindJSON
+}
+
+// packable reports whether repeated values of a kind are packed
+func (k kind) packable() bool {
+	switch k {
+	case kindBool, kindInt, kindUint, kindFloat32, kindFloat64:
+		return true
+	}
+	return false
+}
+
+// shape is how a field is encoded: a singular value, a repeated field or a map
+type shape struct {
+	repeated bool
+	isMap    bool
+	optional bool         // pointer to a scalar, sent even when zero
+	elem     reflect.Type // singular type (of the items, or of the map values)
+	key      reflect.Type // map key type
+}
+
+// shapeOf returns the shape of a field type. Pointers to lists, lists of
+// lists and maps without scalar keys or with list values are JSON.
+func shapeOf(t reflect.Type) shape {
+	if t.Kind() == reflect.Ptr {
+		base := deref(t)
+		k := kindOf(base)
+		return shape{elem: base, optional: k != kindMessage && k != kindJSON}
+	}
+	if container(t) {
+		switch t.Kind() {
+		case reflect.Slice:
+			if !container(deref(t.Elem())) {
+				return shape{repeated: true, elem: deref(t.Elem())}
+			}
+		case reflect.Map:
+			key := kindOf(t.Key())
+			if (key == kindString || key == kindInt || key == kindUint || key == kindBool) && !container(deref(t.Elem())) {
+				return shape{isMap: true, key: t.Key(), elem: deref(t.Elem())}
+			}
+		}
+	}
+	return shape{elem: t}
+}
+
+// container reports whether a type is a list or map without its own JSON
+// or text encoding
+func container(t reflect.Type) bool {
+	if implements(t, jsonMarshalerType) || implements(t, textMarshalerType) {
+		return false
+	}
+	return t.Kind() == reflect.Map || (t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8)
+}
+
+// deref returns the type a pointer type points to
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// field is a message field of a struct type
+type field struct {
+	name   string // JSON name
+	number int
+	index  []int // reflect index path, through embedded structs
+	depth  int   // embedding depth, the shallowest field of a name wins
+	typ    reflect.Type
+}
+
+var fieldCache sync.Map // reflect.Type -> fieldsResult
+
+type fieldsResult struct {
+	fields []field
+	err    error
+}
+
+// fieldsOf returns the message fields of a struct type in number order
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if cached, ok := fieldCache.Load(t); ok {
+		r := cached.(fieldsResult)
+		return r.fields, r.err
+	}
+	fields, err := buildFields(t)
+	fieldCache.Store(t, fieldsResult{fields, err})
+	return fields, err
+}
+
+func buildFields(t reflect.Type) ([]field, error) {
+	var fields []field
+	byName := map[string]int{}
+	var collect func(t reflect.Type, index []int, depth int) error
+	collect = func(t reflect.Type, index []int, depth int) error {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			path := append(append([]int{}, index...), i)
+			if f.Anonymous && name == "" {
+				embedded := f.Type
+				if embedded.Kind() == reflect.Ptr {
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					if err := collect(embedded, path, depth+1); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+
+			number := 0
+			if opt := tagOption(f, "proto"); opt != "" {
+				n, err := strconv.Atoi(opt)
+				if err != nil || n < 1 || n > 536870911 || (n >= 19000 && n <= 19999) {
+					return fmt.Errorf("protobuf: invalid field number %q on %s.%s", opt, t.Name(), f.Name)
+				}
+				number = n
+			}
+			next := field{name: name, number: number, index: path, depth: depth, typ: f.Type}
+			if existing, ok := byName[name]; ok {
+				// An outer field hides an embedded one, as in encoding/json
+				if depth < fields[existing].depth {
+					if next.number == 0 {
+						next.number = fields[existing].number
+					}
+					fields[existing] = next
+				}
+				continue
+			}
+			if next.number == 0 {
+				next.number = -1 // numbered below
+			}
+			byName[name] = len(fields)
+			fields = append(fields, next)
+		}
+		return nil
+	}
+	if err := collect(t, nil, 0); err != nil {
+		return nil, err
+	}
+
+	// Fields without a number take the one after the previous field's
+	used := map[int]string{}
+	last := 0
+	for i := range fields {
+		if fields[i].number <= 0 {
+			fields[i].number = last + 1
+		}
+		if other, ok := used[fields[i].number]; ok {
+			return nil, fmt.Errorf("protobuf: fields %s and %s of %s both have number %d", other, fields[i].name, t.Name(), fields[i].number)
+		}
+		used[fields[i].number] = fields[i].name
+		last = fields[i].number
+	}
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].number < fields[j].number })
+	return fields, nil
+}
+
+// tagOption returns the value of a key=value option of a fabrica tag
+func tagOption(f reflect.StructField, key string) string {
+	for _, opt := range strings.Split(f.Tag.Get("fabrica"), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(opt), "="); ok && k == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
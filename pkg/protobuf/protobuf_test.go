@@ -0,0 +1,319 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package protobuf
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type meta struct {
+	Name    string            `json:"name"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Created time.Time         `json:"createdAt"`
+}
+
+type base struct {
+	Kind     string      `json:"kind"`
+	Metadata meta        `json:"metadata"`
+	Spec     interface{} `json:"spec,omitempty"`
+}
+
+type port struct {
+	Number int  `json:"number"`
+	Up     bool `json:"up"`
+}
+
+type Location struct {
+	Rack string `json:"rack"`
+}
+
+type deviceSpec struct {
+	Model    string          `json:"model"`
+	Location Location        `json:"location"`
+	IP       net.IP          `json:"ip,omitempty"`
+	Ports    []port          `json:"ports,omitempty"`
+	Weights  []float64       `json:"weights,omitempty"`
+	Slots    map[int]*port   `json:"slots,omitempty"`
+	Power    *int            `json:"power,omitempty"`
+	Extra    json.RawMessage `json:"extra,omitempty"`
+	Matrix   [][]int         `json:"matrix,omitempty"`
+	Internal string          `json:"-"`
+}
+
+type device struct {
+	base
+	Spec   deviceSpec `json:"spec"`
+	Status struct {
+		Ready bool `json:"ready"`
+	} `json:"status"`
+}
+
+func testDevice() device {
+	power := 0
+	d := device{Spec: deviceSpec{
+		Model:    "r650",
+		Location: Location{Rack: "r12"},
+		IP:       net.ParseIP("10.0.0.7"),
+		Ports:    []port{{Number: 1, Up: true}, {}},
+		Weights:  []float64{0.5, -2},
+		Slots:    map[int]*port{3: {Number: 3}},
+		Power:    &power,
+		Extra:    json.RawMessage(`{"a":[1,2]}`),
+		Matrix:   [][]int{{1}, {2, 3}},
+	}}
+	d.Kind = "Device"
+	d.Metadata = meta{Name: "dev-1", Labels: map[string]string{"rack": "r12"}, Created: time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)}
+	d.Status.Ready = true
+	return d
+}
+
+func TestMarshal_WireFormat(t *testing.T) {
+	data, err := Marshal(port{Number: 150, Up: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// number = 1 (varint 150), up = 2 (true)
+	if want := []byte{0x08, 0x96, 0x01, 0x10, 0x01}; string(data) != string(want) {
+		t.Errorf("got % x, want % x", data, want)
+	}
+
+	// Zero values are left out
+	if data, _ := Marshal(port{}); len(data) != 0 {
+		t.Errorf("expected an empty message, got % x", data)
+	}
+
+	if _, err := Marshal("text"); err == nil {
+		t.Error("expected an error for a string")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := testDevice()
+	data, err := Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out device
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip changed the value:\n in  %+v\n out %+v", in, out)
+	}
+}
+
+func TestRoundTrip_List(t *testing.T) {
+	in := []*device{{}, {}}
+	in[0].Kind = "Device"
+	in[1].Spec.Model = "r750"
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []*device
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[0].Kind != "Device" || out[1].Spec.Model != "r750" {
+		t.Errorf("unexpected items %+v", out)
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	var d device
+	if err := Unmarshal([]byte{0x0a, 0x05, 'a'}, &d); err == nil {
+		t.Error("expected an error for a truncated message")
+	}
+	// kind (1) sent as a varint
+	if err := Unmarshal([]byte{0x08, 0x01}, &d); err == nil {
+		t.Error("expected an error for the wrong wire type")
+	}
+	if err := Unmarshal(nil, d); err == nil {
+		t.Error("expected an error for a non-pointer")
+	}
+
+	// Unknown fields are skipped
+	if err := Unmarshal([]byte{0xf8, 0x01, 0x07, 0x0a, 0x01, 'X'}, &d); err != nil || d.Kind != "X" {
+		t.Errorf("expected the unknown field to be skipped, got %v, %q", err, d.Kind)
+	}
+}
+
+func TestUnmarshal_UnpackedNumbers(t *testing.T) {
+	var s struct {
+		Values []int `json:"values"`
+	}
+	if err := Unmarshal([]byte{0x08, 0x01, 0x08, 0x02}, &s); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Values, []int{1, 2}) {
+		t.Errorf("got %v", s.Values)
+	}
+}
+
+func TestFieldNumbers(t *testing.T) {
+	type numbered struct {
+		A string `json:"a"`
+		B string `json:"b" fabrica:"proto=10"`
+		C string `json:"c"`
+	}
+	fields, err := fieldsOf(reflect.TypeOf(numbered{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields[0].number != 1 || fields[1].number != 10 || fields[2].number != 11 {
+		t.Errorf("unexpected numbers %+v", fields)
+	}
+
+	type duplicate struct {
+		A string `json:"a" fabrica:"proto=2"`
+		B string `json:"b" fabrica:"proto=2"`
+	}
+	if _, err := fieldsOf(reflect.TypeOf(duplicate{})); err == nil {
+		t.Error("expected an error for duplicate numbers")
+	}
+
+	type invalid struct {
+		A string `json:"a" fabrica:"proto=19000"`
+	}
+	if _, err := Marshal(invalid{}); err == nil {
+		t.Error("expected an error for a reserved number")
+	}
+}
+
+func TestSchema(t *testing.T) {
+	proto, err := Schema("inventory.v1", reflect.TypeOf(device{}), reflect.TypeOf(Location{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `syntax = "proto3";
+
+package inventory.v1;
+
+import "google/protobuf/timestamp.proto";
+
+message device {
+  string kind = 1;
+  meta metadata = 2;
+  deviceSpec spec = 3;
+  deviceStatus status = 4;
+}
+
+message deviceList {
+  repeated device items = 1;
+}
+
+message Location {
+  string rack = 1;
+}
+
+message LocationList {
+  repeated Location items = 1;
+}
+
+message meta {
+  string name = 1;
+  map<string, string> labels = 2;
+  google.protobuf.Timestamp createdAt = 3;
+}
+
+message deviceSpec {
+  string model = 1;
+  Location location = 2;
+  string ip = 3;
+  repeated port ports = 4;
+  repeated double weights = 5;
+  map<int64, port> slots = 6;
+  optional int64 power = 7;
+  bytes extra = 8; // JSON
+  bytes matrix = 9; // JSON
+}
+
+message deviceStatus {
+  bool ready = 1;
+}
+
+message port {
+  int64 number = 1;
+  bool up = 2;
+}
+`
+	if proto != want {
+		t.Errorf("got:\n%s\nwant:\n%s", proto, want)
+	}
+
+	if _, err := Schema("inventory.v1", reflect.TypeOf("")); err == nil {
+		t.Error("expected an error for a string")
+	}
+}
+
+func TestSchema_NameCollision(t *testing.T) {
+	// A nested struct named like a resource is prefixed with its package
+	resource := reflect.TypeOf(Location{})
+	type Location struct {
+		Row int `json:"row"`
+	}
+	type holder struct {
+		Here Location `json:"here"`
+	}
+	proto, err := Schema("test", reflect.TypeOf(holder{}), resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(proto, "  ProtobufLocation here = 1;") || !strings.Contains(proto, "message ProtobufLocation {\n  int64 row = 1;") {
+		t.Errorf("expected the nested Location to be renamed:\n%s", proto)
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/protobuf", true},
+		{"application/x-protobuf", true},
+		{"application/protobuf, application/json", true},
+		{"application/json, application/protobuf", false},
+		{"application/protobuf;q=0.5, application/json", false},
+		{"application/protobuf, application/json;q=0.9", true},
+		{"text/html, application/protobuf;q=0.8", true},
+		{"application/protobuf;q=0", false},
+	}
+	for _, tt := range tests {
+		if got := Preferred(tt.accept); got != tt.want {
+			t.Errorf("Preferred(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var requested bool
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = Requested(w)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/devices", nil)
+	req.Header.Set("Accept", ContentType)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !requested {
+		t.Error("expected protobuf to be requested")
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/devices", nil))
+	if requested {
+		t.Error("expected JSON without an Accept header")
+	}
+}
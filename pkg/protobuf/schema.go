@@ -0,0 +1,181 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package protobuf
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Schema returns the .proto definitions (proto3) of the messages Marshal
+// produces for the given struct types. Each type gets a message and a
+// <Name>List message holding a repeated items field, followed by the
+// messages of the structs it contains. A nested struct named like another
+// message is prefixed with its Go package name (e.g., DeviceLocation).
+//
+// Parameters:
+//   - pkg: The protobuf package (e.g., "inventory.v1")
+//   - types: Struct types, usually the resource types
+//
+// Returns:
+//   - string: Contents of a .proto file
+//   - error: If a type isn't a struct or has invalid field numbers
+func Schema(pkg string, types ...reflect.Type) (string, error) {
+	s := &schema{names: make(map[reflect.Type]string), used: make(map[string]bool)}
+
+	// Resources are named first, so they keep their names when a nested
+	// struct has the same one
+	var roots []reflect.Type
+	for _, t := range types {
+		t = deref(t)
+		if kindOf(t) != kindMessage {
+			return "", fmt.Errorf("protobuf: %s is not a struct", t)
+		}
+		s.name(t, "")
+		s.used[s.names[t]+"List"] = true
+		roots = append(roots, t)
+	}
+
+	var body strings.Builder
+	for _, t := range roots {
+		if err := s.message(&body, t); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&body, "\nmessage %sList {\n  repeated %s items = 1;\n}\n", s.names[t], s.names[t])
+	}
+	// Messages of nested structs, which may add more
+	for i := 0; i < len(s.pending); i++ {
+		if err := s.message(&body, s.pending[i]); err != nil {
+			return "", err
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&out, "package %s;\n", pkg)
+	if s.timestamp {
+		out.WriteString("\nimport \"google/protobuf/timestamp.proto\";\n")
+	}
+	out.WriteString(body.String())
+	return out.String(), nil
+}
+
+// schema collects message definitions
+type schema struct {
+	names     map[reflect.Type]string
+	used      map[string]bool
+	pending   []reflect.Type // nested structs to define
+	timestamp bool
+}
+
+// name returns the message name of a struct type, choosing one the first
+// time. Anonymous structs are named after the field holding them (hint).
+func (s *schema) name(t reflect.Type, hint string) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	base := identifier(t.Name())
+	if t.Name() == "" {
+		base = hint
+	}
+	name := base
+	if s.used[name] && t.Name() != "" {
+		name = exported(identifier(path.Base(t.PkgPath()))) + base
+	}
+	for i := 2; s.used[name]; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	s.names[t] = name
+	s.used[name] = true
+	return name
+}
+
+// message writes the definition of a struct type
+func (s *schema) message(out *strings.Builder, t reflect.Type) error {
+	fields, err := fieldsOf(t)
+	if err != nil {
+		return err
+	}
+	name := s.names[t]
+	fmt.Fprintf(out, "\nmessage %s {\n", name)
+	for _, f := range fields {
+		typ, comment := s.fieldType(f.typ, name+exported(identifier(f.name)))
+		fmt.Fprintf(out, "  %s %s = %d;%s\n", typ, identifier(f.name), f.number, comment)
+	}
+	out.WriteString("}\n")
+	return nil
+}
+
+// fieldType returns the .proto type of a field and a trailing comment
+func (s *schema) fieldType(t reflect.Type, hint string) (string, string) {
+	sh := shapeOf(t)
+	elem, comment := s.scalar(sh.elem, hint)
+	switch {
+	case sh.repeated:
+		return "repeated " + elem, comment
+	case sh.isMap:
+		key, _ := s.scalar(sh.key, hint)
+		return fmt.Sprintf("map<%s, %s>", key, elem), comment
+	case sh.optional:
+		return "optional " + elem, comment
+	}
+	return elem, comment
+}
+
+// scalar returns the .proto type of a singular type
+func (s *schema) scalar(t reflect.Type, hint string) (string, string) {
+	switch kindOf(t) {
+	case kindBool:
+		return "bool", ""
+	case kindInt:
+		return "int64", ""
+	case kindUint:
+		return "uint64", ""
+	case kindFloat32:
+		return "float", ""
+	case kindFloat64:
+		return "double", ""
+	case kindString, kindText:
+		return "string", ""
+	case kindBytes:
+		return "bytes", ""
+	case kindTime:
+		s.timestamp = true
+		return "google.protobuf.Timestamp", ""
+	case kindMessage:
+		if _, ok := s.names[t]; !ok {
+			s.name(t, hint)
+			s.pending = append(s.pending, t)
+		}
+		return s.names[t], ""
+	}
+	return "bytes", " // JSON"
+}
+
+// identifier replaces characters that can't appear in .proto names
+func identifier(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			b.WriteRune(r)
+		} else if r == '[' {
+			break // type parameters of generic types
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	id := b.String()
+	if id == "" || !unicode.IsLetter(rune(id[0])) {
+		id = "f" + id
+	}
+	return id
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
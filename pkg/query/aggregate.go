@@ -0,0 +1,148 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AggregateResult summarizes a collection of resources grouped by a field.
+type AggregateResult struct {
+	// GroupBy is the dotted path resources were grouped by
+	GroupBy string `json:"groupBy"`
+
+	// Field is the numeric field statistics were computed for, if any
+	Field string `json:"field,omitempty"`
+
+	// Total is the number of resources aggregated
+	Total int `json:"total"`
+
+	// Groups holds one entry per distinct GroupBy value, largest first
+	Groups []AggregateGroup `json:"groups"`
+}
+
+// AggregateGroup holds the count (and optional statistics) for one group.
+//
+// Min, Max, Avg and Sum are only set when a field was requested and at least
+// one resource in the group has a numeric value for it.
+type AggregateGroup struct {
+	Key   interface{} `json:"key"`
+	Count int         `json:"count"`
+	Min   *float64    `json:"min,omitempty"`
+	Max   *float64    `json:"max,omitempty"`
+	Avg   *float64    `json:"avg,omitempty"`
+	Sum   *float64    `json:"sum,omitempty"`
+
+	numeric int // number of values contributing to the statistics
+}
+
+// Aggregate counts resources by the value at groupBy and, optionally,
+// computes min/max/avg/sum of the numeric value at field for each group.
+//
+// Resources missing the groupBy field are counted under a null key.
+//
+// Parameters:
+//   - items: Resources to aggregate
+//   - groupBy: Dotted JSON path to group by (e.g., "spec.componentType")
+//   - field: Dotted JSON path of a numeric field, or "" for counts only
+//
+// Returns:
+//   - *AggregateResult: Groups sorted by descending count, then key
+//   - error: If groupBy is empty or a resource can't be converted to JSON
+//
+// Example:
+//
+//	result, err := query.Aggregate(devices, "spec.componentType", "status.errorCount")
+func Aggregate[T any](items []T, groupBy, field string) (*AggregateResult, error) {
+	if groupBy == "" {
+		return nil, fmt.Errorf("groupBy is required")
+	}
+	groupPath := strings.Split(groupBy, ".")
+	var fieldPath []string
+	if field != "" {
+		fieldPath = strings.Split(field, ".")
+	}
+
+	groups := map[string]*AggregateGroup{}
+	for _, item := range items {
+		doc, err := toDocument(item)
+		if err != nil {
+			return nil, err
+		}
+
+		key := lookup(doc, groupPath)
+		id, err := json.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode group key: %w", err)
+		}
+		group, ok := groups[string(id)]
+		if !ok {
+			group = &AggregateGroup{Key: key}
+			groups[string(id)] = group
+		}
+		group.Count++
+
+		if fieldPath != nil {
+			if v, ok := lookup(doc, fieldPath).(float64); ok {
+				group.add(v)
+			}
+		}
+	}
+
+	result := &AggregateResult{
+		GroupBy: groupBy,
+		Field:   field,
+		Total:   len(items),
+		Groups:  make([]AggregateGroup, 0, len(groups)),
+	}
+	for _, group := range groups {
+		if group.numeric > 0 {
+			avg := *group.Sum / float64(group.numeric)
+			group.Avg = &avg
+		}
+		result.Groups = append(result.Groups, *group)
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		if result.Groups[i].Count != result.Groups[j].Count {
+			return result.Groups[i].Count > result.Groups[j].Count
+		}
+		return fmt.Sprint(result.Groups[i].Key) < fmt.Sprint(result.Groups[j].Key)
+	})
+	return result, nil
+}
+
+func (g *AggregateGroup) add(v float64) {
+	if g.numeric == 0 {
+		minV, maxV, sum := v, v, 0.0
+		g.Min, g.Max, g.Sum = &minV, &maxV, &sum
+	}
+	g.numeric++
+	*g.Sum += v
+	if v < *g.Min {
+		*g.Min = v
+	}
+	if v > *g.Max {
+		*g.Max = v
+	}
+}
+
+// toDocument converts a resource to its decoded JSON form.
+func toDocument(obj interface{}) (map[string]interface{}, error) {
+	if doc, ok := obj.(map[string]interface{}); ok {
+		return doc, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode resource: %w", err)
+	}
+	return doc, nil
+}
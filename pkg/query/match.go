@@ -0,0 +1,162 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package query
+
+import "strings"
+
+// Match evaluates a query against a resource.
+//
+// The resource is converted to its JSON form, so paths use JSON field names
+// (e.g., spec.componentType, metadata.labels.env).
+//
+// Comparison semantics:
+//   - A missing field equals null; ordering comparisons against it are false
+//   - Numbers compare numerically, strings lexically, booleans only by equality
+//   - startsWith only matches strings
+//   - Comparing values of different types is false (and != is true)
+//
+// Parameters:
+//   - expr: Parsed query
+//   - obj: Resource to evaluate, or a map already decoded from JSON
+//
+// Returns:
+//   - bool: Whether the resource matches
+//   - error: Any error converting the resource to JSON
+func Match(expr Expr, obj interface{}) (bool, error) {
+	doc, err := toDocument(obj)
+	if err != nil {
+		return false, err
+	}
+	return eval(expr, doc), nil
+}
+
+// Filter returns the items that match a query, preserving order.
+func Filter[T any](expr Expr, items []T) ([]T, error) {
+	result := make([]T, 0, len(items))
+	for _, item := range items {
+		ok, err := Match(expr, item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+func eval(expr Expr, doc map[string]interface{}) bool {
+	switch e := expr.(type) {
+	case *And:
+		return eval(e.Left, doc) && eval(e.Right, doc)
+	case *Or:
+		return eval(e.Left, doc) || eval(e.Right, doc)
+	case *Not:
+		return !eval(e.X, doc)
+	case *Comparison:
+		return compare(lookup(doc, e.Path), e)
+	default:
+		return false
+	}
+}
+
+// lookup resolves a path in a decoded JSON document; missing fields are nil.
+func lookup(doc map[string]interface{}, path []string) interface{} {
+	var current interface{} = doc
+	for _, part := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+func compare(actual interface{}, c *Comparison) bool {
+	switch c.Op {
+	case OpEQ:
+		return equal(actual, c.Value)
+	case OpNEQ:
+		return !equal(actual, c.Value)
+	case OpIn:
+		for _, v := range c.Values {
+			if equal(actual, v) {
+				return true
+			}
+		}
+		return false
+	case OpPrefix:
+		s, isString := actual.(string)
+		prefix, _ := c.Value.(string)
+		return isString && strings.HasPrefix(s, prefix)
+	}
+
+	cmp, ok := order(actual, c.Value)
+	if !ok {
+		return false
+	}
+	switch c.Op {
+	case OpLT:
+		return cmp < 0
+	case OpLTE:
+		return cmp <= 0
+	case OpGT:
+		return cmp > 0
+	case OpGTE:
+		return cmp >= 0
+	}
+	return false
+}
+
+func equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case nil:
+		return b == nil
+	case float64:
+		bv, ok := b.(float64)
+		return ok && av == bv
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	default:
+		// Objects and arrays never equal a literal
+		return false
+	}
+}
+
+// order compares two numbers or two strings; ok is false for other combinations.
+func order(a, b interface{}) (cmp int, ok bool) {
+	switch av := a.(type) {
+	case float64:
+		bv, isNum := b.(float64)
+		if !isNum {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		bv, isStr := b.(string)
+		if !isStr {
+			return 0, false
+		}
+		switch {
+		case av < bv:
+			return -1, true
+		case av > bv:
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
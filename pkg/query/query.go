@@ -0,0 +1,479 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package query implements the filter language used by the ?query= list parameter.
+//
+// A query compares resource fields, addressed by dotted JSON paths, against
+// literal values and combines the comparisons with boolean operators:
+//
+//	spec.componentType == "NodeBMC" && status.errorCount > 0
+//	metadata.labels.env in ["prod", "staging"] || !(spec.enabled == true)
+//	metadata.name startsWith "nid" && spec.rack != "r0"
+//
+// Grammar:
+//
+//	expr       = or
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" expr ")" | comparison
+//	comparison = path op literal | path "in" "[" [ literal { "," literal } ] "]"
+//	           | path "startsWith" string
+//	op         = "==" | "!=" | "<" | "<=" | ">" | ">="
+//	path       = ident { "." ident }
+//	literal    = string | number | "true" | "false" | "null"
+//
+// Strings use double or single quotes with Go escape sequences. Numbers are
+// compared as float64. Parse produces an Expr tree that can be evaluated in
+// memory with Match, or translated by storage backends into native
+// predicates (generated Ent storage compiles it to SQL).
+//
+// Usage:
+//
+//	expr, err := query.Parse(`spec.componentType == "NodeBMC"`)
+//	if err != nil {
+//	    // *query.SyntaxError with position information
+//	}
+//	ok, err := query.Match(expr, device)
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Op is a comparison operator.
+type Op string
+
+// Comparison operators
+const (
+	OpEQ  Op = "=="
+	OpNEQ Op = "!="
+	OpLT  Op = "<"
+	OpLTE Op = "<="
+	OpGT  Op = ">"
+	OpGTE Op = ">="
+	OpIn  Op = "in"
+
+	// OpPrefix matches strings that start with the value
+	OpPrefix Op = "startsWith"
+)
+
+// Expr is a node of a parsed query.
+//
+// The concrete types are *And, *Or, *Not and *Comparison.
+type Expr interface {
+	String() string
+	isExpr()
+}
+
+// And is true when both operands are true.
+type And struct {
+	Left, Right Expr
+}
+
+// Or is true when either operand is true.
+type Or struct {
+	Left, Right Expr
+}
+
+// Not negates its operand.
+type Not struct {
+	X Expr
+}
+
+// Comparison compares the value at Path with a literal.
+//
+// Value holds a string, float64, bool or nil. For OpIn, Values holds the list
+// and Value is unused.
+type Comparison struct {
+	Path   []string
+	Op     Op
+	Value  interface{}
+	Values []interface{}
+}
+
+func (*And) isExpr()        {}
+func (*Or) isExpr()         {}
+func (*Not) isExpr()        {}
+func (*Comparison) isExpr() {}
+
+func (e *And) String() string { return "(" + e.Left.String() + " && " + e.Right.String() + ")" }
+func (e *Or) String() string  { return "(" + e.Left.String() + " || " + e.Right.String() + ")" }
+func (e *Not) String() string { return "!(" + e.X.String() + ")" }
+
+func (e *Comparison) String() string {
+	if e.Op == OpIn {
+		parts := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			parts[i] = formatLiteral(v)
+		}
+		return fmt.Sprintf("%s in [%s]", e.Field(), strings.Join(parts, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", e.Field(), e.Op, formatLiteral(e.Value))
+}
+
+// Field returns the dotted path of the compared field.
+func (e *Comparison) Field() string {
+	return strings.Join(e.Path, ".")
+}
+
+// All combines expressions with &&, skipping nil ones.
+//
+// Parameters:
+//   - exprs: Expressions to combine, any of which may be nil
+//
+// Returns:
+//   - Expr: The conjunction, or nil when every expression is nil
+func All(exprs ...Expr) Expr {
+	var result Expr
+	for _, e := range exprs {
+		switch {
+		case e == nil:
+		case result == nil:
+			result = e
+		default:
+			result = &And{Left: result, Right: e}
+		}
+	}
+	return result
+}
+
+// SyntaxError reports an invalid query.
+type SyntaxError struct {
+	Pos int    // Byte offset in the query where the error was detected
+	Msg string // Description of the problem
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses a query string into an expression tree.
+//
+// Parameters:
+//   - input: Query text (see package documentation for the grammar)
+//
+// Returns:
+//   - Expr: Root of the parsed expression
+//   - error: *SyntaxError if the query is invalid
+func Parse(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty query"}
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return expr, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp     // comparison operators
+	tokAnd    // &&
+	tokOr     // ||
+	tokNot    // !
+	tokLParen // (
+	tokRParen // )
+	tokLBrack // [
+	tokRBrack // ]
+	tokComma  // ,
+	tokDot    // .
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(input) && input[end] != c {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, &SyntaxError{Pos: i, Msg: "unterminated string"}
+			}
+			raw := input[i : end+1]
+			if c == '\'' {
+				// Reuse Go unquoting by converting to a double-quoted literal
+				raw = `"` + strings.ReplaceAll(strings.ReplaceAll(raw[1:len(raw)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+			s, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, &SyntaxError{Pos: i, Msg: "invalid string literal"}
+			}
+			tokens = append(tokens, token{kind: tokString, text: s, pos: i})
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(input) && (isDigit(input[end]) || input[end] == '.' || input[end] == 'e' || input[end] == 'E' ||
+				((input[end] == '+' || input[end] == '-') && (input[end-1] == 'e' || input[end-1] == 'E'))) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: input[i:end], pos: i})
+			i = end
+		case isIdentStart(rune(c)):
+			end := i + 1
+			for end < len(input) && isIdentPart(rune(input[end])) {
+				end++
+			}
+			word := input[i:end]
+			if word == string(OpIn) || word == string(OpPrefix) {
+				tokens = append(tokens, token{kind: tokOp, text: word, pos: i})
+			} else {
+				tokens = append(tokens, token{kind: tokIdent, text: word, pos: i})
+			}
+			i = end
+		default:
+			two := ""
+			if i+1 < len(input) {
+				two = input[i : i+2]
+			}
+			switch {
+			case two == "&&":
+				tokens = append(tokens, token{kind: tokAnd, text: two, pos: i})
+				i += 2
+			case two == "||":
+				tokens = append(tokens, token{kind: tokOr, text: two, pos: i})
+				i += 2
+			case two == "==" || two == "!=" || two == "<=" || two == ">=":
+				tokens = append(tokens, token{kind: tokOp, text: two, pos: i})
+				i += 2
+			case c == '<' || c == '>':
+				tokens = append(tokens, token{kind: tokOp, text: string(c), pos: i})
+				i++
+			case c == '!':
+				tokens = append(tokens, token{kind: tokNot, text: "!", pos: i})
+				i++
+			case c == '(':
+				tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+				i++
+			case c == ')':
+				tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+				i++
+			case c == '[':
+				tokens = append(tokens, token{kind: tokLBrack, text: "[", pos: i})
+				i++
+			case c == ']':
+				tokens = append(tokens, token{kind: tokRBrack, text: "]", pos: i})
+				i++
+			case c == ',':
+				tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+				i++
+			case c == '.':
+				tokens = append(tokens, token{kind: tokDot, text: ".", pos: i})
+				i++
+			default:
+				return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, token{kind: tokEOF, text: "end of query", pos: len(input)}), nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(r rune) bool { return r == '_' || unicode.IsLetter(r) }
+
+func isIdentPart(r rune) bool { return isIdentStart(r) || unicode.IsDigit(r) || r == '-' || r == '/' }
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected %s, found %q", what, tok.text)}
+	}
+	return tok, nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch p.peek().kind {
+	case tokNot:
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	case tokLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return x, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	first, err := p.expect(tokIdent, "field path")
+	if err != nil {
+		return nil, err
+	}
+	path := []string{first.text}
+	for p.peek().kind == tokDot {
+		p.next()
+		part, err := p.expect(tokIdent, "field name after \".\"")
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, part.text)
+	}
+
+	opTok, err := p.expect(tokOp, "comparison operator")
+	if err != nil {
+		return nil, err
+	}
+	cmp := &Comparison{Path: path, Op: Op(opTok.text)}
+
+	if cmp.Op == OpIn {
+		if _, err := p.expect(tokLBrack, "\"[\""); err != nil {
+			return nil, err
+		}
+		cmp.Values = []interface{}{}
+		for p.peek().kind != tokRBrack {
+			if len(cmp.Values) > 0 {
+				if _, err := p.expect(tokComma, "\",\" or \"]\""); err != nil {
+					return nil, err
+				}
+			}
+			v, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			cmp.Values = append(cmp.Values, v)
+		}
+		p.next()
+		return cmp, nil
+	}
+
+	v, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	cmp.Value = v
+	if _, isString := v.(string); cmp.Op == OpPrefix && !isString {
+		return nil, &SyntaxError{Pos: opTok.pos, Msg: "operator startsWith needs a string"}
+	}
+	if cmp.Op != OpEQ && cmp.Op != OpNEQ && v == nil {
+		return nil, &SyntaxError{Pos: opTok.pos, Msg: fmt.Sprintf("operator %s cannot be used with null", cmp.Op)}
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseLiteral() (interface{}, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return tok.text, nil
+	case tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("invalid number %q", tok.text)}
+		}
+		return f, nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+	return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected literal value, found %q", tok.text)}
+}
+
+func formatLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(val)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testSpec struct {
+	ComponentType string   `json:"componentType"`
+	Enabled       bool     `json:"enabled"`
+	Ports         int      `json:"ports"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+type testStatus struct {
+	ErrorCount int `json:"errorCount"`
+}
+
+type testResource struct {
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+	Spec   testSpec   `json:"spec"`
+	Status testStatus `json:"status"`
+}
+
+func newTestResource(name, componentType string, errors int) testResource {
+	var r testResource
+	r.Metadata.Name = name
+	r.Metadata.Labels = map[string]string{"env": "prod"}
+	r.Spec = testSpec{ComponentType: componentType, Enabled: true, Ports: 4}
+	r.Status.ErrorCount = errors
+	return r
+}
+
+func TestParse_String(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`spec.componentType == "NodeBMC"`, `spec.componentType == "NodeBMC"`},
+		{`a == 1 && b > 2 || c != 'x'`, `((a == 1 && b > 2) || c != "x")`},
+		{`a == 1 && (b > 2 || c <= -3.5)`, `(a == 1 && (b > 2 || c <= -3.5))`},
+		{`!(spec.enabled == true)`, `!(spec.enabled == true)`},
+		{`metadata.labels.env in ["prod", "dev"]`, `metadata.labels.env in ["prod", "dev"]`},
+		{`status.phase == null`, `status.phase == null`},
+		{`metadata.name startsWith 'nid'`, `metadata.name startsWith "nid"`},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got := expr.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	inputs := []string{
+		``,
+		`spec.type`,
+		`spec.type ==`,
+		`spec.type == "unterminated`,
+		`== 1`,
+		`a == 1 &&`,
+		`(a == 1`,
+		`a in [1, 2`,
+		`a > null`,
+		`a == 1 b == 2`,
+		`a # 1`,
+		`a startsWith 1`,
+		`a startsWith null`,
+	}
+
+	for _, input := range inputs {
+		_, err := Parse(input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) expected SyntaxError, got %v", input, err)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	res := newTestResource("node-1", "NodeBMC", 2)
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`spec.componentType == "NodeBMC" && status.errorCount > 0`, true},
+		{`spec.componentType == "NodeBMC" && status.errorCount > 5`, false},
+		{`spec.componentType != "NodeBMC" || metadata.name == "node-1"`, true},
+		{`spec.enabled == true`, true},
+		{`!(spec.enabled == true)`, false},
+		{`spec.ports >= 4 && spec.ports <= 4`, true},
+		{`metadata.labels.env in ["dev", "prod"]`, true},
+		{`metadata.labels.env in []`, false},
+		{`metadata.labels.missing == null`, true},
+		{`metadata.labels.missing != "x"`, true},
+		{`metadata.labels.missing > 1`, false},
+		{`spec.componentType > 1`, false},
+		{`metadata.name < "node-2"`, true},
+		{`spec.tags == null`, true},
+		{`spec == "x"`, false},
+		{`metadata.name startsWith "node-"`, true},
+		{`metadata.name startsWith "nid"`, false},
+		{`spec.ports startsWith "4"`, false},
+		{`metadata.labels.missing startsWith ""`, false},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.query, err)
+		}
+		got, err := Match(expr, res)
+		if err != nil {
+			t.Fatalf("Match(%q) failed: %v", tt.query, err)
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	items := []testResource{
+		newTestResource("a", "NodeBMC", 0),
+		newTestResource("b", "NodeBMC", 3),
+		newTestResource("c", "Switch", 1),
+	}
+
+	expr, err := Parse(`spec.componentType == "NodeBMC" && status.errorCount > 0`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	got, err := Filter(expr, items)
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Metadata.Name != "b" {
+		t.Errorf("Expected only b to match, got %+v", got)
+	}
+}
+
+func TestAll(t *testing.T) {
+	a := &Comparison{Path: []string{"spec", "a"}, Op: OpEQ, Value: 1.0}
+	b := &Comparison{Path: []string{"spec", "b"}, Op: OpEQ, Value: "x"}
+
+	if All() != nil || All(nil, nil) != nil {
+		t.Error("expected nil without expressions")
+	}
+	if All(nil, a) != a {
+		t.Error("expected a single expression to be returned as is")
+	}
+	if got := All(a, nil, b); got == nil || got.String() != `(spec.a == 1 && spec.b == "x")` {
+		t.Errorf("unexpected conjunction %v", got)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	keys, err := ParseSort(" spec.componentType, -status.errorCount,+metadata.name ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, key := range keys {
+		got = append(got, key.String())
+	}
+	if strings.Join(got, ",") != "spec.componentType,-status.errorCount,metadata.name" {
+		t.Errorf("unexpected keys %v", got)
+	}
+	if keys, err := ParseSort(""); keys != nil || err != nil {
+		t.Errorf("expected no keys, got %v %v", keys, err)
+	}
+	for _, input := range []string{"spec.a,", "-", "spec..a", "spec.a,-spec.a"} {
+		if _, err := ParseSort(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	items := []testResource{
+		newTestResource("a", "Switch", 1),
+		newTestResource("b", "NodeBMC", 3),
+		newTestResource("c", "", 1),
+		newTestResource("d", "NodeBMC", 0),
+	}
+	items[2].Spec.ComponentType = ""
+
+	sorted := func(order string) string {
+		t.Helper()
+		keys, err := ParseSort(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := Sort(items, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, item := range result {
+			names = append(names, item.Metadata.Name)
+		}
+		return strings.Join(names, "")
+	}
+
+	tests := map[string]string{
+		"spec.componentType":                   "cbda",
+		"-spec.componentType,metadata.name":    "abdc",
+		"status.errorCount,-metadata.name":     "dcab",
+		"-status.errorCount":                   "bacd",
+		"spec.missing":                         "abcd",
+		"spec.tags,spec.enabled,metadata.name": "abcd",
+	}
+	for order, want := range tests {
+		if got := sorted(order); got != want {
+			t.Errorf("%s: expected %s, got %s", order, want, got)
+		}
+	}
+	if items[0].Metadata.Name != "a" {
+		t.Error("input slice was reordered")
+	}
+}
+
+func TestCompareSortValues(t *testing.T) {
+	keys := []SortKey{{Path: []string{"a"}}}
+	desc := []SortKey{{Path: []string{"a"}, Desc: true}}
+	tests := []struct {
+		a, b interface{}
+		want int
+	}{
+		{1.0, 2.0, -1},
+		{"b", "a", 1},
+		{false, true, -1},
+		{"2025-01-01T00:00:01Z", "2025-01-01T00:00:00.5Z", 1},
+		{1.0, "a", -1},
+		{nil, "a", 1},
+		{nil, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := CompareSortValues([]interface{}{tt.a}, []interface{}{tt.b}, keys); got != tt.want {
+			t.Errorf("%v vs %v: expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+	// Missing values stay last in descending order
+	if CompareSortValues([]interface{}{nil}, []interface{}{"a"}, desc) != 1 {
+		t.Error("expected a missing value to sort last in descending order")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	items := []testResource{
+		newTestResource("a", "NodeBMC", 0),
+		newTestResource("b", "NodeBMC", 4),
+		newTestResource("c", "Switch", 1),
+	}
+
+	result, err := Aggregate(items, "spec.componentType", "status.errorCount")
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if result.Total != 3 || len(result.Groups) != 2 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+
+	bmc := result.Groups[0]
+	if bmc.Key != "NodeBMC" || bmc.Count != 2 {
+		t.Fatalf("Expected NodeBMC group first with count 2, got %+v", bmc)
+	}
+	if *bmc.Min != 0 || *bmc.Max != 4 || *bmc.Avg != 2 || *bmc.Sum != 4 {
+		t.Errorf("Unexpected stats: min=%v max=%v avg=%v sum=%v", *bmc.Min, *bmc.Max, *bmc.Avg, *bmc.Sum)
+	}
+
+	counts, err := Aggregate(items, "metadata.labels.missing", "")
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+	if len(counts.Groups) != 1 || counts.Groups[0].Key != nil || counts.Groups[0].Min != nil {
+		t.Errorf("Expected a single null group without stats, got %+v", counts.Groups)
+	}
+
+	if _, err := Aggregate(items, "", ""); err == nil {
+		t.Error("Expected error for empty groupBy")
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is one field of a sort order.
+type SortKey struct {
+	Path []string // Dotted JSON path split on dots (e.g., ["spec", "serialNumber"])
+	Desc bool     // Descending order
+}
+
+// Field returns the dotted path of the key.
+func (k SortKey) Field() string {
+	return strings.Join(k.Path, ".")
+}
+
+// String returns the key in ParseSort syntax, e.g. -metadata.createdAt.
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + k.Field()
+	}
+	return k.Field()
+}
+
+// ParseSort parses a sort order: comma-separated dotted paths, each with an
+// optional "-" prefix for descending order.
+//
+// Parameters:
+//   - input: Sort order, e.g. "spec.serialNumber,-metadata.createdAt"
+//
+// Returns:
+//   - []SortKey: The keys in priority order; nil for an empty input
+//   - error: If a key is empty, malformed or repeated
+//
+// Example:
+//
+//	keys, err := query.ParseSort(r.URL.Query().Get("sort"))
+func ParseSort(input string) ([]SortKey, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(input, ",") {
+		field := strings.TrimSpace(part)
+		key := SortKey{}
+		if rest, ok := strings.CutPrefix(field, "-"); ok {
+			field, key.Desc = rest, true
+		} else {
+			field = strings.TrimPrefix(field, "+")
+		}
+		if field == "" {
+			return nil, fmt.Errorf("invalid sort %q: empty field", input)
+		}
+		key.Path = strings.Split(field, ".")
+		for _, segment := range key.Path {
+			if segment == "" {
+				return nil, fmt.Errorf("invalid sort field %q", field)
+			}
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("sort field %q is repeated", field)
+		}
+		seen[field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SortValues returns the values of a resource at each sort key; missing
+// fields are nil.
+//
+// Parameters:
+//   - obj: Resource, or a map already decoded from JSON
+//   - keys: Sort order
+//
+// Returns:
+//   - []interface{}: One value per key
+//   - error: Any error converting the resource to JSON
+func SortValues(obj interface{}, keys []SortKey) ([]interface{}, error) {
+	doc, err := toDocument(obj)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = lookup(doc, key.Path)
+	}
+	return values, nil
+}
+
+// CompareSortValues compares the sort values of two resources (see
+// SortValues) in the order given by keys.
+//
+// Numbers compare numerically, timestamps chronologically and other strings
+// lexically; false sorts before true. Missing values sort last in both
+// directions, and values of different types are ordered by type.
+//
+// Returns:
+//   - int: Negative when a sorts first, positive when b does, zero when equal
+func CompareSortValues(a, b []interface{}, keys []SortKey) int {
+	for i, key := range keys {
+		if i >= len(a) || i >= len(b) {
+			break
+		}
+		if a[i] == nil || b[i] == nil {
+			switch {
+			case a[i] == nil && b[i] == nil:
+				continue
+			case a[i] == nil:
+				return 1
+			default:
+				return -1
+			}
+		}
+		cmp := compareSortValue(a[i], b[i])
+		if key.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return 0
+}
+
+// Sort returns the items ordered by keys; items with equal values keep their
+// order. The input slice is not modified.
+//
+// Parameters:
+//   - items: Resources to sort
+//   - keys: Sort order (see ParseSort)
+//
+// Returns:
+//   - []T: The sorted items
+//   - error: If a resource can't be converted to JSON
+func Sort[T any](items []T, keys []SortKey) ([]T, error) {
+	type entry struct {
+		item   T
+		values []interface{}
+	}
+	entries := make([]entry, len(items))
+	for i, item := range items {
+		values, err := SortValues(item, keys)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry{item, values}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return CompareSortValues(entries[i].values, entries[j].values, keys) < 0
+	})
+
+	sorted := make([]T, len(entries))
+	for i, e := range entries {
+		sorted[i] = e.item
+	}
+	return sorted, nil
+}
+
+// compareSortValue orders two non-nil values
+func compareSortValue(a, b interface{}) int {
+	if ra, rb := typeRank(a), typeRank(b); ra != rb {
+		return ra - rb
+	}
+	switch av := a.(type) {
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		}
+		return 1
+	case string:
+		bv := b.(string)
+		// JSON timestamps have a variable number of fractional digits, so
+		// they don't sort lexically
+		if at, err := time.Parse(time.RFC3339Nano, av); err == nil {
+			if bt, err := time.Parse(time.RFC3339Nano, bv); err == nil {
+				return at.Compare(bt)
+			}
+		}
+		return strings.Compare(av, bv)
+	}
+	cmp, _ := order(a, b)
+	return cmp
+}
+
+// typeRank orders the JSON types of sort values: booleans, numbers,
+// strings, then objects and arrays
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case bool:
+		return 0
+	case float64:
+		return 1
+	case string:
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,491 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package quota provides resource quotas for Fabrica APIs.
+//
+// A Quota limits how many resources of a given kind may exist within a scope.
+// The scope is expressed as a label selector, so a quota can apply to every
+// resource of a kind, to a single tenant (using the TenantLabel), or to any
+// other label-defined group. Quotas count the resources of one namespace at a
+// time: a quota naming a namespace applies only there, and one without a
+// namespace applies to each namespace separately.
+//
+// Generated create handlers call Manager.Reserve before saving a new
+// resource and release the reservation once it is saved, so concurrent
+// creates can't both take the last free slot. When a quota would be exceeded
+// the reservation fails with an *ExceededError, which handlers translate into
+// a 403 Forbidden response.
+//
+// Usage:
+//
+//	manager := quota.NewManager(nil) // in-memory only
+//	_ = manager.Set(ctx, &quota.Quota{
+//	    Spec: quota.QuotaSpec{
+//	        ResourceKind: "Device",
+//	        MaxCount:     100,
+//	        Selector:     map[string]string{quota.TenantLabel: "acme"},
+//	    },
+//	})
+//
+//	release, err := manager.Reserve(ctx, "Device", device.Metadata.Labels, countDevices)
+//	if quota.IsExceeded(err) {
+//	    // respond 403
+//	}
+//	defer release()
+//	// save the device
+//
+// The namespace of a check is that of its context (see namespace.FromContext).
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/namespace"
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Kind is the resource kind used for Quota resources.
+const Kind = "Quota"
+
+// TenantLabel is the conventional label key used to scope quotas to a tenant.
+const TenantLabel = "fabrica.io/tenant"
+
+// QuotaSpec defines the limit enforced by a quota.
+//
+//nolint:revive // "QuotaSpec" name is intentional; matches generated <Kind>Spec naming
+type QuotaSpec struct {
+	// ResourceKind is the kind of resource being limited (e.g., "Device")
+	ResourceKind string `json:"resourceKind" yaml:"resourceKind" validate:"required"`
+
+	// MaxCount is the maximum number of matching resources allowed
+	MaxCount int `json:"maxCount" yaml:"maxCount" validate:"min=0"`
+
+	// Selector restricts the quota to resources whose labels match all entries.
+	// An empty selector applies the quota to every resource of ResourceKind.
+	Selector map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+
+	// Namespace restricts the quota to the resources of one namespace. An
+	// empty namespace applies the quota to each namespace separately.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// QuotaStatus reports the observed usage of a quota.
+//
+//nolint:revive // "QuotaStatus" name is intentional; matches generated <Kind>Status naming
+type QuotaStatus struct {
+	// Used is the number of matching resources at the last check
+	Used int `json:"used" yaml:"used"`
+
+	// Remaining is MaxCount minus Used, floored at zero
+	Remaining int `json:"remaining" yaml:"remaining"`
+
+	// LastChecked is when usage was last computed
+	LastChecked time.Time `json:"lastChecked,omitempty" yaml:"lastChecked,omitempty"`
+}
+
+// Quota limits the number of resources of a kind within a label scope.
+type Quota struct {
+	resource.Resource
+	Spec   QuotaSpec   `json:"spec" yaml:"spec"`
+	Status QuotaStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Matches reports whether the quota applies to a resource of the given kind and labels.
+func (q *Quota) Matches(kind string, labels map[string]string) bool {
+	if q.Spec.ResourceKind != kind {
+		return false
+	}
+	for k, v := range q.Spec.Selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// AppliesIn reports whether the quota limits the resources of namespace ns.
+func (q *Quota) AppliesIn(ns string) bool {
+	return q.Spec.Namespace == "" || q.Spec.Namespace == ns
+}
+
+// setUsage records observed usage in the quota status.
+func (q *Quota) setUsage(used int) {
+	q.Status.Used = used
+	q.Status.Remaining = q.Spec.MaxCount - used
+	if q.Status.Remaining < 0 {
+		q.Status.Remaining = 0
+	}
+	q.Status.LastChecked = time.Now()
+}
+
+// ExceededError is returned when creating a resource would exceed a quota.
+type ExceededError struct {
+	QuotaName string
+	QuotaUID  string
+	Kind      string
+	Used      int
+	Limit     int
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota %q exceeded for %s: %d of %d in use",
+		e.QuotaName, e.Kind, e.Used, e.Limit)
+}
+
+// IsExceeded reports whether err is (or wraps) an *ExceededError.
+func IsExceeded(err error) bool {
+	var exceeded *ExceededError
+	return errors.As(err, &exceeded)
+}
+
+// CountFunc counts existing resources of a kind that match a label selector,
+// in the namespace of ctx.
+//
+// Generated code supplies a CountFunc that loads resources from storage and
+// filters them with resource.MatchesLabels.
+type CountFunc func(ctx context.Context, kind string, selector map[string]string) (int, error)
+
+// Manager stores quotas and enforces them.
+//
+// Quotas are kept in memory and, when a storage backend is supplied, written
+// through to the backend under the "Quota" kind so they survive restarts.
+type Manager struct {
+	mu      sync.RWMutex
+	quotas  map[string]*Quota
+	backend storage.StorageBackend
+
+	// reserved serializes the creates of a kind in a namespace while one
+	// of its quotas applies, keyed by "<namespace>/<kind>"
+	reservedMu sync.Mutex
+	reserved   map[string]*sync.Mutex
+}
+
+// NewManager creates a quota manager.
+//
+// Parameters:
+//   - backend: Optional storage backend for persistence; nil keeps quotas in memory only
+//
+// Returns:
+//   - *Manager: A manager with any quotas already persisted in backend loaded
+func NewManager(backend storage.StorageBackend) *Manager {
+	m := &Manager{
+		quotas:   make(map[string]*Quota),
+		backend:  backend,
+		reserved: make(map[string]*sync.Mutex),
+	}
+	if backend != nil {
+		if raw, err := backend.LoadAll(context.Background(), Kind); err == nil {
+			for _, data := range raw {
+				var q Quota
+				if err := json.Unmarshal(data, &q); err == nil && q.GetUID() != "" {
+					m.quotas[q.GetUID()] = &q
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Set creates or replaces a quota.
+//
+// A UID is generated if the quota doesn't have one yet.
+func (m *Manager) Set(ctx context.Context, q *Quota) error {
+	if q.Spec.ResourceKind == "" {
+		return fmt.Errorf("quota resourceKind is required")
+	}
+	if q.Spec.MaxCount < 0 {
+		return fmt.Errorf("quota maxCount must not be negative")
+	}
+	if q.Spec.Namespace != "" {
+		if err := namespace.Validate(q.Spec.Namespace); err != nil {
+			return err
+		}
+	}
+
+	if q.GetUID() == "" {
+		uid, err := resource.GenerateUID("quo")
+		if err != nil {
+			return fmt.Errorf("failed to generate quota UID: %w", err)
+		}
+		q.Metadata.Initialize(q.GetName(), uid)
+	} else {
+		q.Touch()
+	}
+	q.APIVersion = "v1"
+	q.Kind = Kind
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.persist(ctx, q); err != nil {
+		return err
+	}
+	m.quotas[q.GetUID()] = q
+	return nil
+}
+
+// Get returns the quota with the given UID.
+func (m *Manager) Get(uid string) (*Quota, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	q, ok := m.quotas[uid]
+	return q, ok
+}
+
+// List returns all quotas sorted by UID.
+func (m *Manager) List() []*Quota {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*Quota, 0, len(m.quotas))
+	for _, q := range m.quotas {
+		result = append(result, q)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetUID() < result[j].GetUID()
+	})
+	return result
+}
+
+// Delete removes a quota.
+//
+// Returns storage.ErrNotFound if the quota doesn't exist.
+func (m *Manager) Delete(ctx context.Context, uid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.quotas[uid]; !ok {
+		return storage.ErrNotFound
+	}
+	if m.backend != nil {
+		if err := m.backend.Delete(ctx, Kind, uid); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to delete quota: %w", err)
+		}
+	}
+	delete(m.quotas, uid)
+	return nil
+}
+
+// Refresh recomputes usage for every quota so status reflects current state.
+//
+// Quotas naming a namespace are counted in it; the others are counted in the
+// namespace of ctx.
+func (m *Manager) Refresh(ctx context.Context, count CountFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, q := range m.quotas {
+		countCtx := ctx
+		if q.Spec.Namespace != "" {
+			countCtx = namespace.NewContext(ctx, q.Spec.Namespace)
+		}
+		used, err := count(countCtx, q.Spec.ResourceKind, q.Spec.Selector)
+		if err != nil {
+			return fmt.Errorf("failed to count %s for quota %s: %w", q.Spec.ResourceKind, q.GetUID(), err)
+		}
+		q.setUsage(used)
+	}
+	return nil
+}
+
+// Check verifies that one more resource of kind with labels fits in every applicable quota.
+//
+// Usage of each matching quota is recomputed and recorded in its status.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts, in the namespace of the resource
+//   - kind: Kind of the resource being created
+//   - labels: Labels of the resource being created
+//   - count: Function used to count existing resources
+//
+// Returns:
+//   - error: *ExceededError if a quota would be exceeded, other errors for count failures
+func (m *Manager) Check(ctx context.Context, kind string, labels map[string]string, count CountFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ns := namespace.FromContext(ctx)
+	for _, q := range m.quotas {
+		if !q.Matches(kind, labels) || !q.AppliesIn(ns) {
+			continue
+		}
+		used, err := count(ctx, kind, q.Spec.Selector)
+		if err != nil {
+			return fmt.Errorf("failed to count %s for quota %s: %w", kind, q.GetUID(), err)
+		}
+		q.setUsage(used)
+		if used+1 > q.Spec.MaxCount {
+			return &ExceededError{
+				QuotaName: q.GetName(),
+				QuotaUID:  q.GetUID(),
+				Kind:      kind,
+				Used:      used,
+				Limit:     q.Spec.MaxCount,
+			}
+		}
+	}
+	return nil
+}
+
+// Reserve checks that one more resource of kind with labels fits in every
+// applicable quota, like Check, and holds the slot until release is called.
+//
+// Creates of the same kind in the same namespace wait for each other's
+// release while a quota applies to them, so the count of one create includes
+// the resource saved by the previous one. Call release once the resource is
+// saved, or when the create fails. Reservations are kept in memory: replicas
+// sharing a backend don't see each other's.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts, in the namespace of the resource
+//   - kind: Kind of the resource being created
+//   - labels: Labels of the resource being created
+//   - count: Function used to count existing resources
+//
+// Returns:
+//   - func(): Releases the reservation; safe to call more than once
+//   - error: *ExceededError if a quota would be exceeded, other errors for count failures
+func (m *Manager) Reserve(ctx context.Context, kind string, labels map[string]string, count CountFunc) (func(), error) {
+	ns := namespace.FromContext(ctx)
+	if !m.applies(kind, labels, ns) {
+		return func() {}, nil
+	}
+
+	m.reservedMu.Lock()
+	lock, ok := m.reserved[ns+"/"+kind]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.reserved[ns+"/"+kind] = lock
+	}
+	m.reservedMu.Unlock()
+
+	lock.Lock()
+	if err := m.Check(ctx, kind, labels, count); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return sync.OnceFunc(lock.Unlock), nil
+}
+
+// applies reports whether any quota limits a resource of kind with labels in namespace ns.
+func (m *Manager) applies(kind string, labels map[string]string, ns string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, q := range m.quotas {
+		if q.Matches(kind, labels) && q.AppliesIn(ns) {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage reports how much of a quota a namespace uses.
+type Usage struct {
+	// QuotaName and QuotaUID identify the quota
+	QuotaName string `json:"quotaName" yaml:"quotaName"`
+	QuotaUID  string `json:"quotaUid" yaml:"quotaUid"`
+
+	// ResourceKind and Selector are those of the quota's spec
+	ResourceKind string            `json:"resourceKind" yaml:"resourceKind"`
+	Selector     map[string]string `json:"selector,omitempty" yaml:"selector,omitempty"`
+
+	// MaxCount is the quota's limit
+	MaxCount int `json:"maxCount" yaml:"maxCount"`
+
+	// Used is the number of matching resources in the namespace
+	Used int `json:"used" yaml:"used"`
+
+	// Remaining is MaxCount minus Used, floored at zero
+	Remaining int `json:"remaining" yaml:"remaining"`
+}
+
+// Usage reports the usage of every quota applying to the namespace of ctx.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts, in the namespace to report
+//   - count: Function used to count existing resources
+//
+// Returns:
+//   - []Usage: Usage of each applicable quota, sorted by kind and quota UID
+//   - error: If counting failed
+func (m *Manager) Usage(ctx context.Context, count CountFunc) ([]Usage, error) {
+	ns := namespace.FromContext(ctx)
+	usage := []Usage{}
+	for _, q := range m.List() {
+		if !q.AppliesIn(ns) {
+			continue
+		}
+		used, err := count(ctx, q.Spec.ResourceKind, q.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s for quota %s: %w", q.Spec.ResourceKind, q.GetUID(), err)
+		}
+		usage = append(usage, Usage{
+			QuotaName:    q.GetName(),
+			QuotaUID:     q.GetUID(),
+			ResourceKind: q.Spec.ResourceKind,
+			Selector:     q.Spec.Selector,
+			MaxCount:     q.Spec.MaxCount,
+			Used:         used,
+			Remaining:    max(q.Spec.MaxCount-used, 0),
+		})
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		return usage[i].ResourceKind < usage[j].ResourceKind
+	})
+	return usage, nil
+}
+
+// persist writes a quota to the backend if one is configured. Callers hold m.mu.
+func (m *Manager) persist(ctx context.Context, q *Quota) error {
+	if m.backend == nil {
+		return nil
+	}
+	data, err := json.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quota: %w", err)
+	}
+	if err := m.backend.Save(ctx, Kind, q.GetUID(), data); err != nil {
+		return fmt.Errorf("failed to save quota: %w", err)
+	}
+	return nil
+}
+
+// Global manager instance used by generated handlers
+var (
+	globalManager *Manager
+	globalMutex   sync.RWMutex
+)
+
+// SetGlobalManager sets the manager used by generated handlers.
+func SetGlobalManager(m *Manager) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	globalManager = m
+}
+
+// GetGlobalManager returns the manager used by generated handlers.
+//
+// A memory-only manager is created on first use if none has been set.
+func GetGlobalManager() *Manager {
+	globalMutex.RLock()
+	m := globalManager
+	globalMutex.RUnlock()
+	if m != nil {
+		return m
+	}
+
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+	if globalManager == nil {
+		globalManager = NewManager(nil)
+	}
+	return globalManager
+}
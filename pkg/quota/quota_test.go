@@ -0,0 +1,226 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/namespace"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// fixedCounter returns a CountFunc reporting the given usage per tenant
+func fixedCounter(usage map[string]int) CountFunc {
+	return func(_ context.Context, _ string, selector map[string]string) (int, error) {
+		return usage[selector[TenantLabel]], nil
+	}
+}
+
+func TestManager_CheckWithinLimit(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+
+	q := &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 3, Selector: map[string]string{TenantLabel: "acme"}}}
+	if err := m.Set(ctx, q); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	err := m.Check(ctx, "Device", map[string]string{TenantLabel: "acme"}, fixedCounter(map[string]int{"acme": 2}))
+	if err != nil {
+		t.Fatalf("Expected create within limit to pass, got %v", err)
+	}
+
+	got, _ := m.Get(q.GetUID())
+	if got.Status.Used != 2 || got.Status.Remaining != 1 {
+		t.Errorf("Expected used=2 remaining=1, got %+v", got.Status)
+	}
+}
+
+func TestManager_CheckExceeded(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 2, Selector: map[string]string{TenantLabel: "acme"}}})
+
+	err := m.Check(ctx, "Device", map[string]string{TenantLabel: "acme"}, fixedCounter(map[string]int{"acme": 2}))
+	if !IsExceeded(err) {
+		t.Fatalf("Expected ExceededError, got %v", err)
+	}
+
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Used != 2 || exceeded.Limit != 2 {
+		t.Errorf("Unexpected error details: %+v", exceeded)
+	}
+}
+
+func TestManager_CheckIgnoresOtherScopes(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 0, Selector: map[string]string{TenantLabel: "acme"}}})
+
+	// Different tenant
+	if err := m.Check(ctx, "Device", map[string]string{TenantLabel: "other"}, fixedCounter(nil)); err != nil {
+		t.Errorf("Expected quota for another tenant to be ignored, got %v", err)
+	}
+	// Different kind
+	if err := m.Check(ctx, "Rack", map[string]string{TenantLabel: "acme"}, fixedCounter(nil)); err != nil {
+		t.Errorf("Expected quota for another kind to be ignored, got %v", err)
+	}
+}
+
+func TestManager_Persistence(t *testing.T) {
+	ctx := context.Background()
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend failed: %v", err)
+	}
+
+	m := NewManager(backend)
+	q := &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 5}}
+	if err := m.Set(ctx, q); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	reloaded := NewManager(backend)
+	if _, ok := reloaded.Get(q.GetUID()); !ok {
+		t.Fatal("Expected quota to be reloaded from backend")
+	}
+
+	if err := reloaded.Delete(ctx, q.GetUID()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if len(NewManager(backend).List()) != 0 {
+		t.Error("Expected quota to be removed from backend")
+	}
+}
+
+func TestManager_SetValidation(t *testing.T) {
+	m := NewManager(nil)
+	if err := m.Set(context.Background(), &Quota{}); err == nil {
+		t.Error("Expected error for quota without resourceKind")
+	}
+	if err := m.Set(context.Background(), &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: -1}}); err == nil {
+		t.Error("Expected error for negative maxCount")
+	}
+	if err := m.Set(context.Background(), &Quota{Spec: QuotaSpec{ResourceKind: "Device", Namespace: "Tenant A"}}); err == nil {
+		t.Error("Expected error for an invalid namespace")
+	}
+}
+
+// namespaceCounter returns a CountFunc reporting the given usage per namespace
+func namespaceCounter(usage map[string]int) CountFunc {
+	return func(ctx context.Context, _ string, _ map[string]string) (int, error) {
+		return usage[namespace.FromContext(ctx)], nil
+	}
+}
+
+func TestManager_CheckNamespaces(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 2, Namespace: "tenant-a"}})
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Rack", MaxCount: 1}})
+	count := namespaceCounter(map[string]int{"tenant-a": 2, "tenant-b": 5, namespace.Default: 1})
+
+	tenantA := namespace.NewContext(ctx, "tenant-a")
+	if err := m.Check(tenantA, "Device", nil, count); !IsExceeded(err) {
+		t.Errorf("Expected the tenant-a quota to be exceeded, got %v", err)
+	}
+	if err := m.Check(namespace.NewContext(ctx, "tenant-b"), "Device", nil, count); err != nil {
+		t.Errorf("Expected the tenant-a quota to be ignored in tenant-b, got %v", err)
+	}
+
+	// Quotas without a namespace count each namespace separately
+	if err := m.Check(tenantA, "Rack", nil, namespaceCounter(map[string]int{namespace.Default: 1})); err != nil {
+		t.Errorf("Expected racks of the default namespace not to count in tenant-a, got %v", err)
+	}
+	if err := m.Check(ctx, "Rack", nil, namespaceCounter(map[string]int{namespace.Default: 1})); !IsExceeded(err) {
+		t.Errorf("Expected the rack quota to be exceeded in the default namespace, got %v", err)
+	}
+}
+
+func TestManager_ReserveConcurrentCreates(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 3}})
+
+	var mu sync.Mutex
+	saved := 0
+	count := func(context.Context, string, map[string]string) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return saved, nil
+	}
+
+	var wg sync.WaitGroup
+	var exceeded atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := m.Reserve(ctx, "Device", nil, count)
+			if IsExceeded(err) {
+				exceeded.Add(1)
+				return
+			}
+			if err != nil {
+				t.Errorf("Reserve failed: %v", err)
+				return
+			}
+			defer release()
+			// The save takes a while, as with a remote backend
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			saved++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if saved != 3 || exceeded.Load() != 17 {
+		t.Errorf("Expected 3 saved and 17 rejected creates, got %d saved and %d rejected", saved, exceeded.Load())
+	}
+
+	// Kinds without an applicable quota aren't serialized
+	release, err := m.Reserve(ctx, "Rack", nil, count)
+	if err != nil {
+		t.Fatalf("Expected no quota for racks, got %v", err)
+	}
+	release()
+	release()
+}
+
+func TestManager_Usage(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Rack", MaxCount: 1}})
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 2, Namespace: "tenant-a"}})
+	_ = m.Set(ctx, &Quota{Spec: QuotaSpec{ResourceKind: "Device", MaxCount: 9, Namespace: "tenant-b"}})
+	count := namespaceCounter(map[string]int{"tenant-a": 3})
+
+	usage, err := m.Usage(namespace.NewContext(ctx, "tenant-a"), count)
+	if err != nil {
+		t.Fatalf("Usage failed: %v", err)
+	}
+	if len(usage) != 2 || usage[0].ResourceKind != "Device" || usage[1].ResourceKind != "Rack" {
+		t.Fatalf("Expected the Device and Rack quotas, got %+v", usage)
+	}
+	if usage[0].Used != 3 || usage[0].MaxCount != 2 || usage[0].Remaining != 0 {
+		t.Errorf("Unexpected Device usage: %+v", usage[0])
+	}
+	if usage[1].Used != 3 || usage[1].Remaining != 0 {
+		t.Errorf("Unexpected Rack usage: %+v", usage[1])
+	}
+
+	usage, err = m.Usage(ctx, count)
+	if err != nil || len(usage) != 1 || usage[0].Used != 0 || usage[0].Remaining != 1 {
+		t.Errorf("Expected only the Rack quota, unused, in the default namespace, got %+v %v", usage, err)
+	}
+}
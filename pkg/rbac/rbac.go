@@ -0,0 +1,367 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package rbac authorizes requests by role, per resource kind and verb.
+//
+// A Policy defines roles as rules granting verbs (get, list, create,
+// update, delete, or custom action verbs such as "power-on") on resource
+// kinds, and binds roles to token subjects and groups:
+//
+//	roles:
+//	  operator:
+//	    - kinds: [Device]
+//	      verbs: [get, list, update, power-on]
+//	bindings:
+//	  - role: viewer
+//	    subjects: ["*"]
+//	  - role: operator
+//	    groups: [infra]
+//
+// A caller's roles are those named by the "roles" claim of their token plus
+// those bound to their subject or groups. The predefined viewer, editor and
+// admin roles (see DefaultPolicy) can be used without defining them.
+//
+// Authorizer.Require returns middleware that rejects requests without the
+// permission with 403 and a problem document describing the denial.
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/auth"
+	"github.com/openchami/fabrica/pkg/errcode"
+	"github.com/openchami/fabrica/pkg/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// Standard verbs of resource routes. Custom actions use their URL path
+// segment as verb (e.g., "power-on").
+const (
+	Get    = "get"
+	List   = "list"
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// StandardVerbs are the verbs of every resource kind
+var StandardVerbs = []string{Get, List, Create, Update, Delete}
+
+// Wildcard matches any kind or verb in rules, and any subject in bindings
+const Wildcard = "*"
+
+// Default claims carrying roles and groups
+const (
+	DefaultRoleClaim  = "roles"
+	DefaultGroupClaim = "groups"
+)
+
+// Rule grants verbs on kinds.
+type Rule struct {
+	Kinds []string `yaml:"kinds" json:"kinds"`
+	Verbs []string `yaml:"verbs" json:"verbs"`
+}
+
+// Binding grants a role to token subjects and groups.
+type Binding struct {
+	Role     string   `yaml:"role" json:"role"`
+	Subjects []string `yaml:"subjects,omitempty" json:"subjects,omitempty"`
+	Groups   []string `yaml:"groups,omitempty" json:"groups,omitempty"`
+}
+
+// Policy is a set of roles and bindings.
+type Policy struct {
+	Roles    map[string][]Rule `yaml:"roles" json:"roles"`
+	Bindings []Binding         `yaml:"bindings,omitempty" json:"bindings,omitempty"`
+}
+
+// DefaultPolicy returns the predefined roles, without bindings:
+//   - viewer: get and list on every kind
+//   - editor: get, list, create and update on every kind
+//   - admin: every verb, including custom actions, on every kind
+func DefaultPolicy() *Policy {
+	all := []string{Wildcard}
+	return &Policy{Roles: map[string][]Rule{
+		"viewer": {{Kinds: all, Verbs: []string{Get, List}}},
+		"editor": {{Kinds: all, Verbs: []string{Get, List, Create, Update}}},
+		"admin":  {{Kinds: all, Verbs: all}},
+	}}
+}
+
+// ParsePolicy parses a YAML or JSON policy. Its roles are added to the
+// predefined ones of DefaultPolicy, replacing those with the same name.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var file Policy
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse RBAC policy: %w", err)
+	}
+	policy := DefaultPolicy()
+	for name, rules := range file.Roles {
+		policy.Roles[name] = rules
+	}
+	policy.Bindings = file.Bindings
+	return policy, nil
+}
+
+// LoadPolicy reads a policy file (see ParsePolicy).
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RBAC policy: %w", err)
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// Options configures an Authorizer.
+type Options struct {
+	// Policy defines the roles and bindings (default DefaultPolicy)
+	Policy *Policy
+
+	// RoleClaim is the token claim listing role names (default "roles")
+	RoleClaim string
+
+	// GroupClaim is the token claim listing groups (default "groups")
+	GroupClaim string
+
+	// Permissions lists the verbs of each kind served. When set, New
+	// rejects rules naming other kinds or verbs, which catches typos.
+	Permissions map[string][]string
+
+	// NonEnforcing logs denials and lets the requests through, for rolling
+	// out a policy gradually
+	NonEnforcing bool
+}
+
+// Authorizer decides whether callers may perform verbs on kinds.
+type Authorizer struct {
+	policy       *Policy
+	roleClaim    string
+	groupClaim   string
+	nonEnforcing bool
+}
+
+// New creates an Authorizer.
+//
+// Returns:
+//   - *Authorizer: The authorizer
+//   - error: An error if a binding names an undefined role, or a rule names
+//     a kind or verb missing from opts.Permissions
+func New(opts Options) (*Authorizer, error) {
+	policy := opts.Policy
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	var problems []string
+	for _, b := range policy.Bindings {
+		if _, ok := policy.Roles[b.Role]; !ok {
+			problems = append(problems, fmt.Sprintf("binding of undefined role %q", b.Role))
+		}
+	}
+	if opts.Permissions != nil {
+		for name, rules := range policy.Roles {
+			for _, rule := range rules {
+				problems = append(problems, unknownPermissions(name, rule, opts.Permissions)...)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid RBAC policy: %s", strings.Join(problems, "; "))
+	}
+
+	a := &Authorizer{policy: policy, roleClaim: opts.RoleClaim, groupClaim: opts.GroupClaim, nonEnforcing: opts.NonEnforcing}
+	if a.roleClaim == "" {
+		a.roleClaim = DefaultRoleClaim
+	}
+	if a.groupClaim == "" {
+		a.groupClaim = DefaultGroupClaim
+	}
+	return a, nil
+}
+
+// unknownPermissions describes the kinds and verbs of a rule that aren't served
+func unknownPermissions(role string, rule Rule, permissions map[string][]string) []string {
+	var problems []string
+	var verbs []string
+	for _, kind := range rule.Kinds {
+		if kind == Wildcard {
+			for _, vs := range permissions {
+				verbs = append(verbs, vs...)
+			}
+			continue
+		}
+		vs, ok := permissions[kind]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("role %q: unknown kind %q", role, kind))
+		}
+		verbs = append(verbs, vs...)
+	}
+	for _, verb := range rule.Verbs {
+		if verb != Wildcard && !slices.Contains(verbs, verb) {
+			problems = append(problems, fmt.Sprintf("role %q: unknown verb %q for kinds %v", role, verb, rule.Kinds))
+		}
+	}
+	return problems
+}
+
+// Roles returns the roles of a caller: those named by the role claim and
+// defined by the policy, and those bound to the subject or groups.
+func (a *Authorizer) Roles(claims *auth.Claims) []string {
+	var roles []string
+	add := func(role string) {
+		if _, ok := a.policy.Roles[role]; ok && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	for _, role := range claims.Strings(a.roleClaim) {
+		add(role)
+	}
+	groups := claims.Strings(a.groupClaim)
+	for _, b := range a.policy.Bindings {
+		if slices.Contains(b.Subjects, Wildcard) || slices.Contains(b.Subjects, claims.Subject) ||
+			slices.ContainsFunc(b.Groups, func(g string) bool { return slices.Contains(groups, g) }) {
+			add(b.Role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Authorize checks that a caller may perform a verb on a kind.
+//
+// Returns:
+//   - error: nil if one of the caller's roles grants the permission,
+//     otherwise a *Denial
+func (a *Authorizer) Authorize(claims *auth.Claims, kind, verb string) error {
+	roles := a.Roles(claims)
+	for _, role := range roles {
+		for _, rule := range a.policy.Roles[role] {
+			if matches(rule.Kinds, kind) && matches(rule.Verbs, verb) {
+				return nil
+			}
+		}
+	}
+	return &Denial{Subject: claims.Subject, Kind: kind, Verb: verb, Roles: roles}
+}
+
+// CanGrant checks that a caller holds every permission of a role, so they
+// may hand it out, e.g. as the scope of an API key. Wildcards in the role's
+// rules need wildcards in the caller's. Names that aren't roles grant
+// nothing and pass.
+//
+// Returns:
+//   - error: nil if the caller may grant the role, otherwise the *Denial of
+//     the first permission they lack
+func (a *Authorizer) CanGrant(claims *auth.Claims, role string) error {
+	for _, rule := range a.policy.Roles[role] {
+		for _, kind := range rule.Kinds {
+			for _, verb := range rule.Verbs {
+				if err := a.Authorize(claims, kind, verb); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matches reports whether values hold value or the wildcard
+func matches(values []string, value string) bool {
+	return slices.Contains(values, value) || slices.Contains(values, Wildcard)
+}
+
+// Require returns middleware allowing requests whose caller may perform the
+// verb on the kind. It needs the claims of auth.Authenticator.Middleware:
+// requests without them are rejected with 401, and denied requests with
+// 403 and a Problem document. A non-enforcing Authorizer logs both and lets
+// the requests through.
+func (a *Authorizer) Require(kind, verb string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := logging.FromContext(r.Context())
+			claims, ok := auth.FromContext(r.Context())
+			if !ok {
+				if a.nonEnforcing {
+					logger.Warn("unauthenticated request (non-enforcing)", "kind", kind, "verb", verb)
+					next.ServeHTTP(w, r)
+					return
+				}
+				WriteProblem(w, http.StatusUnauthorized, errcode.Wrap(errcode.Unauthorized, errors.New("authentication required")))
+				return
+			}
+			if err := a.Authorize(claims, kind, verb); err != nil {
+				var denial *Denial
+				errors.As(err, &denial)
+				if a.nonEnforcing {
+					logger.Warn("permission denied (non-enforcing)", "kind", kind, "verb", verb, "roles", denial.Roles)
+					next.ServeHTTP(w, r)
+					return
+				}
+				logger.Info("permission denied", "kind", kind, "verb", verb, "roles", denial.Roles)
+				WriteProblem(w, http.StatusForbidden, errcode.Wrap(errcode.Forbidden, err))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Denial describes a request denied by the policy. It is the "denied"
+// member of 403 problem documents.
+type Denial struct {
+	// Subject is the caller's token subject
+	Subject string `json:"subject,omitempty"`
+	// Kind is the resource kind of the request
+	Kind string `json:"kind"`
+	// Verb is the verb of the request, e.g. "delete" or "power-on"
+	Verb string `json:"verb"`
+	// Roles are the caller's roles, none of which grants the verb
+	Roles []string `json:"roles,omitempty"`
+	// Reason explains the denial of an external policy engine (see pkg/opa)
+	Reason string `json:"reason,omitempty"`
+}
+
+// Error describes the denial, e.g. `"alice" may not delete Device (roles: viewer)`.
+func (d *Denial) Error() string {
+	if d.Reason != "" {
+		return fmt.Sprintf("%q may not %s %s: %s", d.Subject, d.Verb, d.Kind, d.Reason)
+	}
+	roles := "none"
+	if len(d.Roles) > 0 {
+		roles = strings.Join(d.Roles, ", ")
+	}
+	return fmt.Sprintf("%q may not %s %s (roles: %s)", d.Subject, d.Verb, d.Kind, roles)
+}
+
+// Problem is the problem document of a denied request.
+type Problem struct {
+	errcode.Problem
+	// Denied describes the denial
+	Denied *Denial `json:"denied,omitempty"`
+}
+
+// WriteProblem writes the problem document of a rejected request. A
+// *Denial in err's chain becomes its "denied" member, and 401 responses get
+// a Bearer challenge.
+func WriteProblem(w http.ResponseWriter, status int, err error) {
+	var denial *Denial
+	errors.As(err, &denial)
+	w.Header().Set("Content-Type", errcode.ContentType)
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Problem{Problem: errcode.NewProblem(status, err), Denied: denial})
+}
@@ -0,0 +1,215 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/auth"
+)
+
+const testPolicy = `
+roles:
+  operator:
+    - kinds: [Device]
+      verbs: [get, list, update, power-on]
+bindings:
+  - role: viewer
+    subjects: ["*"]
+  - role: operator
+    groups: [infra]
+  - role: admin
+    subjects: [root]
+`
+
+func claims(sub string, raw map[string]interface{}) *auth.Claims {
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return &auth.Claims{Subject: sub, Raw: raw}
+}
+
+func TestAuthorize(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy failed: %v", err)
+	}
+	a, err := New(Options{Policy: policy})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	alice := claims("alice", nil)
+	bob := claims("bob", map[string]interface{}{"groups": []interface{}{"infra"}})
+	carol := claims("carol", map[string]interface{}{"roles": "editor unknown"})
+	root := claims("root", nil)
+
+	tests := []struct {
+		claims  *auth.Claims
+		kind    string
+		verb    string
+		allowed bool
+	}{
+		{alice, "Device", Get, true},
+		{alice, "Rack", List, true},
+		{alice, "Device", Update, false},
+		{bob, "Device", "power-on", true},
+		{bob, "Device", Update, true},
+		{bob, "Rack", Update, false},
+		{bob, "Device", Delete, false},
+		{carol, "Rack", Create, true},
+		{carol, "Rack", Delete, false},
+		{carol, "Device", "power-on", false},
+		{root, "Rack", Delete, true},
+		{root, "Device", "power-off", true},
+	}
+	for _, tt := range tests {
+		err := a.Authorize(tt.claims, tt.kind, tt.verb)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s %s %s: expected allowed=%v, got %v", tt.claims.Subject, tt.verb, tt.kind, tt.allowed, err)
+		}
+	}
+
+	var denial *Denial
+	if err := a.Authorize(carol, "Rack", Delete); !errors.As(err, &denial) {
+		t.Fatalf("Expected a *Denial, got %v", err)
+	}
+	if strings.Join(denial.Roles, ",") != "editor,viewer" || denial.Verb != Delete || denial.Kind != "Rack" {
+		t.Errorf("Unexpected denial %+v", denial)
+	}
+}
+
+func TestCanGrant(t *testing.T) {
+	policy, _ := ParsePolicy([]byte(testPolicy))
+	a, err := New(Options{Policy: policy})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	bob := claims("bob", map[string]interface{}{"groups": []interface{}{"infra"}})
+	carol := claims("carol", map[string]interface{}{"roles": "editor"})
+	root := claims("root", nil)
+
+	tests := []struct {
+		claims  *auth.Claims
+		role    string
+		allowed bool
+	}{
+		{bob, "operator", true},
+		{bob, "viewer", true},
+		{bob, "editor", false},
+		{carol, "viewer", true},
+		{carol, "operator", false}, // power-on
+		{carol, "admin", false},
+		{root, "admin", true},
+		{root, "operator", true},
+		{carol, "devices:sync", true}, // not a role
+	}
+	for _, tt := range tests {
+		err := a.CanGrant(tt.claims, tt.role)
+		if allowed := err == nil; allowed != tt.allowed {
+			t.Errorf("%s grant %s: expected allowed=%v, got %v", tt.claims.Subject, tt.role, tt.allowed, err)
+		}
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	permissions := map[string][]string{
+		"Device": append(StandardVerbs, "power-on"),
+		"Rack":   StandardVerbs,
+	}
+	policy, _ := ParsePolicy([]byte(testPolicy))
+	if _, err := New(Options{Policy: policy, Permissions: permissions}); err != nil {
+		t.Errorf("Expected a valid policy, got %v", err)
+	}
+
+	tests := map[string]string{
+		"unknown kind":  "roles: {r: [{kinds: [Devise], verbs: [get]}]}",
+		"unknown verb":  "roles: {r: [{kinds: [Rack], verbs: [power-on]}]}",
+		"unknown role":  "bindings: [{role: operator, subjects: [alice]}]",
+		"wildcard verb": "roles: {r: [{kinds: ['*'], verbs: [reboot]}]}",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, err := ParsePolicy([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := New(Options{Policy: policy, Permissions: permissions}); err == nil {
+				t.Error("Expected an invalid policy error")
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	data := `{"roles": {"auditor": [{"kinds": ["*"], "verbs": ["list"]}]}, "bindings": [{"role": "auditor", "groups": ["audit"]}]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if _, ok := policy.Roles["auditor"]; !ok {
+		t.Error("Expected the auditor role")
+	}
+	if _, ok := policy.Roles["viewer"]; !ok {
+		t.Error("Expected the predefined roles to be kept")
+	}
+	if _, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected a missing file to fail")
+	}
+}
+
+func TestRequire(t *testing.T) {
+	a, _ := New(Options{})
+	lenient, _ := New(Options{NonEnforcing: true})
+	do := func(a *Authorizer, c *auth.Claims) *httptest.ResponseRecorder {
+		h := a.Require("Device", Delete)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		req := httptest.NewRequest(http.MethodDelete, "/devices/dev-1", nil)
+		if c != nil {
+			req = req.WithContext(auth.NewContext(req.Context(), c))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(a, claims("root", map[string]interface{}{"roles": []interface{}{"admin"}})); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected an admin through, got %d", rec.Code)
+	}
+	if rec := do(a, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without claims, got %d", rec.Code)
+	}
+
+	rec := do(a, claims("alice", map[string]interface{}{"roles": []interface{}{"viewer"}}))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected 403, got %d", rec.Code)
+	}
+	var p Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Code != "FORBIDDEN" || p.Denied == nil || p.Denied.Subject != "alice" || p.Denied.Verb != Delete {
+		t.Errorf("Unexpected problem %s", rec.Body)
+	}
+
+	if rec := do(lenient, claims("alice", nil)); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected a non-enforcing pass, got %d", rec.Code)
+	}
+	if rec := do(lenient, nil); rec.Code != http.StatusNoContent {
+		t.Errorf("Expected a non-enforcing pass without claims, got %d", rec.Code)
+	}
+}
@@ -6,11 +6,13 @@ package reconcile
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/logging"
 	"github.com/openchami/fabrica/pkg/storage"
 )
 
@@ -24,6 +26,8 @@ import (
 //   - Handles requeueing for periodic reconciliation
 type Controller struct {
 	reconcilers map[string]Reconciler
+	predicates  map[string][]Predicate // of reconcilers implementing EventFilter
+	watches     map[string][]Watch     // by watched kind
 	queue       *WorkQueue
 	eventBus    events.EventBus
 	storage     storage.StorageBackend
@@ -31,7 +35,23 @@ type Controller struct {
 	cancel      context.CancelFunc
 	wg          sync.WaitGroup
 	logger      Logger
+	observe     ObserveFunc
+	retry       RetryFunc
 	workerCount int
+
+	// maxAttempts limits the attempts of failed requests, counted in
+	// failures by request; 0 retries without limit
+	maxAttempts int
+	giveUp      GiveUpFunc
+	failuresMu  sync.Mutex
+	failures    map[string]int
+
+	// workCtx is passed to reconcilers. It outlives ctx so in-flight
+	// reconciliations can finish during Shutdown.
+	workCtx    context.Context
+	workCancel context.CancelFunc
+
+	subscription events.SubscriptionID
 }
 
 // NewController creates a new reconciliation controller.
@@ -44,26 +64,36 @@ type Controller struct {
 //   - *Controller: Initialized controller
 func NewController(eventBus events.EventBus, storage storage.StorageBackend) *Controller {
 	ctx, cancel := context.WithCancel(context.Background())
+	workCtx, workCancel := context.WithCancel(context.Background())
 
 	return &Controller{
 		reconcilers: make(map[string]Reconciler),
+		predicates:  make(map[string][]Predicate),
+		watches:     make(map[string][]Watch),
 		queue:       NewWorkQueue(),
 		eventBus:    eventBus,
 		storage:     storage,
 		ctx:         ctx,
 		cancel:      cancel,
+		workCtx:     workCtx,
+		workCancel:  workCancel,
 		logger:      NewDefaultLogger(),
 		workerCount: 5, // Default worker count
+		failures:    make(map[string]int),
 	}
 }
 
 // RegisterReconciler registers a reconciler for a resource kind.
 //
+// If the reconciler implements EventFilter, its predicates filter the
+// events that trigger it. If it implements Watcher, events of the kinds it
+// watches trigger it too.
+//
 // Parameters:
 //   - reconciler: Reconciler implementation for a specific resource type
 //
 // Returns:
-//   - error: If reconciler for this kind is already registered
+//   - error: If reconciler for this kind is already registered or a watch is invalid
 func (c *Controller) RegisterReconciler(reconciler Reconciler) error {
 	kind := reconciler.GetResourceKind()
 
@@ -72,11 +102,82 @@ func (c *Controller) RegisterReconciler(reconciler Reconciler) error {
 	}
 
 	c.reconcilers[kind] = reconciler
+	if filter, ok := reconciler.(EventFilter); ok {
+		c.predicates[kind] = filter.Predicates()
+	}
+	if watcher, ok := reconciler.(Watcher); ok {
+		for _, watch := range watcher.Watches() {
+			if err := c.Watch(watch); err != nil {
+				return fmt.Errorf("reconciler for kind %s: %w", kind, err)
+			}
+		}
+	}
 	c.logger.Infof("Registered reconciler for %s", kind)
 
 	return nil
 }
 
+// SetLogger replaces the controller's logger (default: NewDefaultLogger).
+//
+// With a logger from NewSlogLogger, reconcilers also receive it through
+// their context, tagged with the resource kind and UID.
+//
+// Parameters:
+//   - logger: Logger for controller messages
+func (c *Controller) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+// ObserveFunc receives the duration and error of every reconciliation, e.g.
+// to record metrics (see metrics.ObserveReconcile).
+type ObserveFunc func(kind string, duration time.Duration, err error)
+
+// SetObserver sets a function called after every reconciliation.
+//
+// Parameters:
+//   - observe: Receives the kind, duration and error of each reconciliation
+func (c *Controller) SetObserver(observe ObserveFunc) {
+	c.observe = observe
+}
+
+// RetryFunc receives the kind of every failed reconciliation requeued to
+// be retried, e.g. to count retries (see metrics.ObserveReconcileRetry).
+type RetryFunc func(kind string)
+
+// SetRetryObserver sets a function called when a failed reconciliation is
+// requeued.
+//
+// Parameters:
+//   - retry: Receives the kind of each retried reconciliation
+func (c *Controller) SetRetryObserver(retry RetryFunc) {
+	c.retry = retry
+}
+
+// GiveUpFunc receives a reconcile request the controller stopped retrying,
+// the number of failed attempts and the last error, e.g. to record it in a
+// dead-letter queue (see deadletter.Queue.Reconciles).
+type GiveUpFunc func(request ReconcileRequest, attempts int, err error)
+
+// SetMaxAttempts limits the attempts of failing reconciliations. A resource
+// whose reconciliation fails maxAttempts times in a row isn't requeued
+// again, and giveUp, if not nil, receives its request. The count starts
+// again after a successful reconciliation or giving up, so a later event
+// for the resource gets every attempt again.
+//
+// Parameters:
+//   - maxAttempts: Attempts per request, including the first; 0 retries without limit (the default)
+//   - giveUp: Receives the requests given up on
+func (c *Controller) SetMaxAttempts(maxAttempts int, giveUp GiveUpFunc) {
+	c.maxAttempts = maxAttempts
+	c.giveUp = giveUp
+}
+
+// QueueDepth returns the number of reconcile requests waiting for a worker,
+// e.g. to report it as a metric (see metrics.ObserveQueueDepth).
+func (c *Controller) QueueDepth() int {
+	return c.queue.Len()
+}
+
 // Start begins the reconciliation controller.
 //
 // This:
@@ -93,10 +194,11 @@ func (c *Controller) Start(_ context.Context) error {
 	c.logger.Infof("Starting reconciliation controller with %d workers", c.workerCount)
 
 	// Subscribe to all resource events (generic pattern - can be customized per application)
-	_, err := c.eventBus.Subscribe("**", c.handleEvent)
+	subscription, err := c.eventBus.Subscribe("**", c.handleEvent)
 	if err != nil {
 		return fmt.Errorf("failed to subscribe to events: %w", err)
 	}
+	c.subscription = subscription
 
 	// Start worker goroutines
 	for i := 0; i < c.workerCount; i++ {
@@ -113,15 +215,59 @@ func (c *Controller) Start(_ context.Context) error {
 //
 // This waits for all workers to finish processing their current items.
 func (c *Controller) Stop() error {
+	return c.Shutdown(context.Background())
+}
+
+// Shutdown gracefully shuts down the controller within a deadline.
+//
+// This:
+//   - Stops watching events, so no new reconciliations are queued
+//   - Cancels pending delayed requeues and drops queued requests
+//   - Waits for workers to finish the reconciliations they are running
+//
+// If ctx ends first, the context passed to running reconcilers is canceled
+// and Shutdown returns without waiting for them.
+//
+// Parameters:
+//   - ctx: Bounds how long to wait for running reconciliations
+//
+// Returns:
+//   - error: If ctx ended before the workers stopped
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := controller.Shutdown(ctx); err != nil {
+//	    log.Printf("Reconcilers did not stop in time: %v", err)
+//	}
+func (c *Controller) Shutdown(ctx context.Context) error {
 	c.logger.Infof("Stopping reconciliation controller")
 
+	if c.subscription != "" {
+		if err := c.eventBus.Unsubscribe(c.subscription); err != nil {
+			c.logger.Warnf("Failed to unsubscribe from events: %v", err)
+		}
+		c.subscription = ""
+	}
 	c.cancel()
 	c.queue.ShutDown()
-	c.wg.Wait()
 
-	c.logger.Infof("Reconciliation controller stopped")
+	stopped := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(stopped)
+	}()
 
-	return nil
+	select {
+	case <-stopped:
+		c.workCancel()
+		c.logger.Infof("Reconciliation controller stopped")
+		return nil
+	case <-ctx.Done():
+		c.workCancel()
+		return fmt.Errorf("reconciliation controller shutdown: %w", ctx.Err())
+	}
 }
 
 // Enqueue adds a reconciliation request to the work queue.
@@ -155,6 +301,43 @@ func (c *Controller) EnqueueAfter(request ReconcileRequest, delay time.Duration)
 	}()
 }
 
+// Resync enqueues every stored resource of the registered kinds.
+//
+// Events published while no controller watched (e.g., while another replica
+// led, see pkg/leader) are lost to this controller; resyncing after Start
+// reconciles the resources they changed.
+//
+// Parameters:
+//   - ctx: Context for loading resources
+//
+// Returns:
+//   - error: If resources of a kind can't be loaded
+func (c *Controller) Resync(ctx context.Context) error {
+	for kind := range c.reconcilers {
+		resources, err := c.storage.LoadAll(ctx, kind)
+		if err != nil {
+			return fmt.Errorf("failed to load %s resources: %w", kind, err)
+		}
+		for _, data := range resources {
+			var stored struct {
+				Metadata struct {
+					UID string `json:"uid"`
+				} `json:"metadata"`
+			}
+			if err := json.Unmarshal(data, &stored); err != nil || stored.Metadata.UID == "" {
+				c.logger.Warnf("Skipping %s resource without UID in resync", kind)
+				continue
+			}
+			c.queue.Add(ReconcileRequest{
+				ResourceKind: kind,
+				ResourceUID:  stored.Metadata.UID,
+				Reason:       "Resync",
+			})
+		}
+	}
+	return nil
+}
+
 // worker processes items from the work queue.
 func (c *Controller) worker(id int) {
 	defer c.wg.Done()
@@ -183,7 +366,7 @@ func (c *Controller) worker(id int) {
 
 // processRequest processes a single reconciliation request.
 func (c *Controller) processRequest(request ReconcileRequest) {
-	ctx := context.Background() // TODO: Add timeout/deadline
+	ctx := c.requestContext(c.workCtx, request) // TODO: Add per-reconciliation timeout/deadline
 
 	c.logger.Debugf("Processing reconciliation for %s/%s (reason: %s)",
 		request.ResourceKind, request.ResourceUID, request.Reason)
@@ -203,24 +386,36 @@ func (c *Controller) processRequest(request ReconcileRequest) {
 		return
 	}
 
-	// Call reconciler
-	result, err := reconciler.Reconcile(ctx, resource)
+	result, err := c.reconcile(ctx, reconciler, request, resource)
 	if err != nil {
 		c.logger.Errorf("Reconciliation failed for %s/%s: %v",
 			request.ResourceKind, request.ResourceUID, err)
+		if attempts, exhausted := c.countFailure(request); exhausted {
+			c.logger.Warnf("Giving up on %s/%s after %d attempts",
+				request.ResourceKind, request.ResourceUID, attempts)
+			if c.giveUp != nil {
+				c.giveUp(request, attempts, err)
+			}
+			return
+		}
+		if c.retry != nil {
+			c.retry(request.ResourceKind)
+		}
 
-		// Requeue on error
-		if result.Requeue || result.RequeueAfter > 0 {
-			c.enqueueResult(request, result)
-		} else {
-			// Default retry after 30 seconds
-			c.EnqueueAfter(request, 30*time.Second)
+		// Requeue on error. The request is still being processed, so the
+		// queue would drop an immediate requeue: retry after RequeueAfter,
+		// or 30 seconds by default
+		delay := 30 * time.Second
+		if result.RequeueAfter > 0 {
+			delay = result.RequeueAfter
 		}
+		c.EnqueueAfter(request, delay)
 		return
 	}
 
 	c.logger.Debugf("Reconciliation successful for %s/%s",
 		request.ResourceKind, request.ResourceUID)
+	c.clearFailures(request)
 
 	// Handle requeueing based on result
 	if result.Requeue || result.RequeueAfter > 0 {
@@ -228,6 +423,76 @@ func (c *Controller) processRequest(request ReconcileRequest) {
 	}
 }
 
+// ReconcileNow reconciles the resource of a request once, in the calling
+// goroutine, and returns the result instead of requeueing. The controller
+// doesn't need to be started, so tests and tools can drive reconcilers
+// synchronously.
+//
+// Parameters:
+//   - ctx: Context passed to the reconciler
+//   - request: The resource to reconcile
+//
+// Returns:
+//   - Result: The reconciler's result
+//   - error: If no reconciler handles the kind, loading the resource fails or reconciliation fails
+func (c *Controller) ReconcileNow(ctx context.Context, request ReconcileRequest) (Result, error) {
+	reconciler, exists := c.reconcilers[request.ResourceKind]
+	if !exists {
+		return Result{}, fmt.Errorf("no reconciler registered for kind %s", request.ResourceKind)
+	}
+	ctx = c.requestContext(ctx, request)
+	resource, err := c.loadResource(ctx, request.ResourceKind, request.ResourceUID)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to load resource %s: %w", request, err)
+	}
+	return c.reconcile(ctx, reconciler, request, resource)
+}
+
+// requestContext returns ctx with a logger tagged with the request
+func (c *Controller) requestContext(ctx context.Context, request ReconcileRequest) context.Context {
+	return logging.NewContext(ctx, slogFor(c.logger).With(
+		"kind", request.ResourceKind, "uid", request.ResourceUID, "reason", request.Reason))
+}
+
+// reconcile calls the reconciler and reports its duration to the observer
+func (c *Controller) reconcile(ctx context.Context, reconciler Reconciler, request ReconcileRequest, resource interface{}) (Result, error) {
+	start := time.Now()
+	result, err := reconciler.Reconcile(ctx, resource)
+	if c.observe != nil {
+		c.observe(request.ResourceKind, time.Since(start), err)
+	}
+	return result, err
+}
+
+// countFailure counts a failed attempt of a request. It returns the
+// attempts so far and whether they reached the limit, which resets the
+// count.
+func (c *Controller) countFailure(request ReconcileRequest) (int, bool) {
+	if c.maxAttempts <= 0 {
+		return 0, false
+	}
+	c.failuresMu.Lock()
+	defer c.failuresMu.Unlock()
+	key := request.String()
+	c.failures[key]++
+	attempts := c.failures[key]
+	if attempts < c.maxAttempts {
+		return attempts, false
+	}
+	delete(c.failures, key)
+	return attempts, true
+}
+
+// clearFailures resets the failed attempts of a request
+func (c *Controller) clearFailures(request ReconcileRequest) {
+	if c.maxAttempts <= 0 {
+		return
+	}
+	c.failuresMu.Lock()
+	defer c.failuresMu.Unlock()
+	delete(c.failures, request.String())
+}
+
 // enqueueResult handles requeueing based on reconciliation result.
 func (c *Controller) enqueueResult(request ReconcileRequest, result Result) {
 	if result.Requeue {
@@ -254,6 +519,24 @@ func (c *Controller) loadResource(ctx context.Context, kind, uid string) (interf
 
 // handleEvent processes resource change events.
 func (c *Controller) handleEvent(_ context.Context, event events.Event) error {
+	for _, request := range c.Requests(event) {
+		if err := c.Enqueue(request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Requests returns the reconciliation requests an event triggers: one for
+// its resource if a reconciler handles the kind and its predicates accept
+// the event, and those of the reconcilers watching the kind.
+//
+// Parameters:
+//   - event: A resource event
+//
+// Returns:
+//   - []ReconcileRequest: The requests; nil for events of other kinds and non-resource events
+func (c *Controller) Requests(event events.Event) []ReconcileRequest {
 	// Extract resource kind and UID from event
 	resourceKind := event.ResourceKind()
 	resourceUID := event.ResourceUID()
@@ -263,23 +546,20 @@ func (c *Controller) handleEvent(_ context.Context, event events.Event) error {
 		return nil
 	}
 
-	// Check if we have a reconciler for this kind
-	if _, exists := c.reconcilers[resourceKind]; !exists {
-		// No reconciler registered, skip
-		return nil
-	}
-
-	// Determine reason from event type
-	reason := fmt.Sprintf("Event: %s", event.Type())
+	var requests []ReconcileRequest
 
-	// Enqueue reconciliation request
-	request := ReconcileRequest{
-		ResourceKind: resourceKind,
-		ResourceUID:  resourceUID,
-		Reason:       reason,
+	// Reconcile the resource if we have a reconciler for this kind, unless
+	// its predicates reject the event
+	if _, exists := c.reconcilers[resourceKind]; exists && matchesPredicates(c.predicates[resourceKind], event) {
+		requests = append(requests, ReconcileRequest{
+			ResourceKind: resourceKind,
+			ResourceUID:  resourceUID,
+			Reason:       fmt.Sprintf("Event: %s", event.Type()),
+		})
 	}
 
-	return c.Enqueue(request)
+	// Reconcile the resources whose reconcilers watch this kind
+	return append(requests, c.watchedRequests(event)...)
 }
 
 // ReconcileRequest represents a request to reconcile a resource.
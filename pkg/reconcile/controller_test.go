@@ -5,14 +5,19 @@
 package reconcile
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/logging"
 	"github.com/openchami/fabrica/pkg/storage"
 )
 
@@ -31,7 +36,7 @@ func (m *mockReconciler) Reconcile(ctx context.Context, resource interface{}) (R
 	defer m.mu.Unlock()
 	m.callCount++
 	if m.shouldError {
-		return Result{}, context.DeadlineExceeded
+		return m.result, context.DeadlineExceeded
 	}
 	return m.result, nil
 }
@@ -433,3 +438,276 @@ func TestController_GracefulShutdown(t *testing.T) {
 		t.Fatal("Controller.Stop() did not complete within timeout")
 	}
 }
+
+// blockingReconciler runs until its context is canceled
+type blockingReconciler struct {
+	BaseReconciler
+	started  chan struct{}
+	canceled chan struct{}
+}
+
+func (b *blockingReconciler) Reconcile(ctx context.Context, resource interface{}) (Result, error) { //nolint:revive
+	close(b.started)
+	<-ctx.Done()
+	close(b.canceled)
+	return Result{}, ctx.Err()
+}
+
+func (b *blockingReconciler) GetResourceKind() string {
+	return "TestResource"
+}
+
+func TestController_ShutdownDeadline(t *testing.T) {
+	ctx := context.Background()
+
+	eventBus := events.NewInMemoryEventBus(100, 1)
+	eventBus.Start()
+	defer eventBus.Close() //nolint:errcheck
+
+	fileStorage, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": "test-123"}})
+	if err := fileStorage.Save(ctx, "TestResource", "test-123", resourceData); err != nil {
+		t.Fatalf("Failed to save test resource: %v", err)
+	}
+
+	controller := NewController(eventBus, fileStorage)
+	reconciler := &blockingReconciler{
+		BaseReconciler: BaseReconciler{Logger: NewDefaultLogger()},
+		started:        make(chan struct{}),
+		canceled:       make(chan struct{}),
+	}
+	if err := controller.RegisterReconciler(reconciler); err != nil {
+		t.Fatalf("Failed to register reconciler: %v", err)
+	}
+	if err := controller.Start(ctx); err != nil {
+		t.Fatalf("Failed to start controller: %v", err)
+	}
+
+	controller.Enqueue(ReconcileRequest{ResourceKind: "TestResource", ResourceUID: "test-123"}) //nolint:errcheck
+	select {
+	case <-reconciler.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Reconciler was not called")
+	}
+
+	// The running reconciliation outlives the deadline, so Shutdown gives up
+	// and cancels its context
+	shutdownCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := controller.Shutdown(shutdownCtx); err == nil {
+		t.Error("Expected Shutdown to report the missed deadline")
+	}
+	select {
+	case <-reconciler.canceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Reconciler context was not canceled")
+	}
+
+	// The controller no longer watches events
+	if controller.subscription != "" {
+		t.Error("Expected the event subscription to be removed")
+	}
+}
+
+// loggingReconciler logs through the logger in its context
+type loggingReconciler struct {
+	BaseReconciler
+}
+
+func (l *loggingReconciler) Reconcile(ctx context.Context, _ interface{}) (Result, error) {
+	logging.FromContext(ctx).Info("reconciled")
+	return Result{}, nil
+}
+
+func (l *loggingReconciler) GetResourceKind() string {
+	return "TestResource"
+}
+
+func TestController_ContextLogger(t *testing.T) {
+	ctx := context.Background()
+
+	fileStorage, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": "test-789"}})
+	if err := fileStorage.Save(ctx, "TestResource", "test-789", resourceData); err != nil {
+		t.Fatalf("Failed to save test resource: %v", err)
+	}
+
+	var buf bytes.Buffer
+	controller := NewController(events.NewInMemoryEventBus(10, 1), fileStorage)
+	controller.SetLogger(NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	if err := controller.RegisterReconciler(&loggingReconciler{}); err != nil {
+		t.Fatalf("Failed to register reconciler: %v", err)
+	}
+
+	controller.processRequest(ReconcileRequest{ResourceKind: "TestResource", ResourceUID: "test-789", Reason: "Test"})
+
+	want := "msg=reconciled kind=TestResource uid=test-789 reason=Test"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q in log output:\n%s", want, buf.String())
+	}
+}
+
+func TestController_Observer(t *testing.T) {
+	ctx := context.Background()
+
+	fileStorage, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": "test-321"}})
+	if err := fileStorage.Save(ctx, "TestResource", "test-321", resourceData); err != nil {
+		t.Fatalf("Failed to save test resource: %v", err)
+	}
+
+	controller := NewController(events.NewInMemoryEventBus(10, 1), fileStorage)
+	if err := controller.RegisterReconciler(&mockReconciler{shouldError: true}); err != nil {
+		t.Fatalf("Failed to register reconciler: %v", err)
+	}
+	var kinds []string
+	var errs []error
+	controller.SetObserver(func(kind string, _ time.Duration, err error) {
+		kinds = append(kinds, kind)
+		errs = append(errs, err)
+	})
+	var retried []string
+	controller.SetRetryObserver(func(kind string) {
+		retried = append(retried, kind)
+	})
+
+	controller.processRequest(ReconcileRequest{ResourceKind: "TestResource", ResourceUID: "test-321", Reason: "Test"})
+
+	if len(kinds) != 1 || kinds[0] != "TestResource" {
+		t.Fatalf("Expected one observation of TestResource, got %v", kinds)
+	}
+	if errs[0] != context.DeadlineExceeded {
+		t.Errorf("Expected the reconcile error to be observed, got %v", errs[0])
+	}
+	if len(retried) != 1 || retried[0] != "TestResource" {
+		t.Errorf("Expected the failed reconciliation to be retried, got %v", retried)
+	}
+}
+
+func TestController_MaxAttempts(t *testing.T) {
+	ctx := context.Background()
+
+	backend := storage.NewMemoryBackend()
+	resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": "test-654"}})
+	if err := backend.Save(ctx, "TestResource", "test-654", resourceData); err != nil {
+		t.Fatalf("Failed to save test resource: %v", err)
+	}
+
+	reconciler := &mockReconciler{shouldError: true}
+	controller := NewController(events.NewInMemoryEventBus(10, 1), backend)
+	defer controller.Stop() //nolint:errcheck
+	if err := controller.RegisterReconciler(reconciler); err != nil {
+		t.Fatalf("Failed to register reconciler: %v", err)
+	}
+	var givenUp []string
+	controller.SetMaxAttempts(3, func(request ReconcileRequest, attempts int, err error) {
+		givenUp = append(givenUp, fmt.Sprintf("%s %d %v", request, attempts, err))
+	})
+
+	request := ReconcileRequest{ResourceKind: "TestResource", ResourceUID: "test-654", Reason: "Test"}
+	for i := 0; i < 3; i++ {
+		controller.processRequest(request)
+	}
+	want := "TestResource/test-654 3 context deadline exceeded"
+	if len(givenUp) != 1 || givenUp[0] != want {
+		t.Fatalf("Expected to give up once (%s), got %v", want, givenUp)
+	}
+
+	// A success resets the count
+	controller.processRequest(request)
+	reconciler.mu.Lock()
+	reconciler.shouldError = false
+	reconciler.mu.Unlock()
+	controller.processRequest(request)
+	reconciler.mu.Lock()
+	reconciler.shouldError = true
+	reconciler.mu.Unlock()
+	controller.processRequest(request)
+	controller.processRequest(request)
+	if len(givenUp) != 1 {
+		t.Errorf("Expected the count to restart after a success, got %v", givenUp)
+	}
+}
+
+func TestController_RequeueOnError(t *testing.T) {
+	ctx := context.Background()
+
+	backend := storage.NewMemoryBackend()
+	resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": "test-655"}})
+	if err := backend.Save(ctx, "TestResource", "test-655", resourceData); err != nil {
+		t.Fatalf("Failed to save test resource: %v", err)
+	}
+
+	// An immediate requeue on error would be dropped while the request is
+	// processed; the controller retries after RequeueAfter instead
+	reconciler := &mockReconciler{shouldError: true, result: Result{Requeue: true, RequeueAfter: 10 * time.Millisecond}}
+	eventBus := events.NewInMemoryEventBus(10, 1)
+	eventBus.Start()
+	defer eventBus.Close() //nolint:errcheck
+	controller := NewController(eventBus, backend)
+	if err := controller.RegisterReconciler(reconciler); err != nil {
+		t.Fatalf("Failed to register reconciler: %v", err)
+	}
+	if err := controller.Start(ctx); err != nil {
+		t.Fatalf("Failed to start controller: %v", err)
+	}
+	defer controller.Stop() //nolint:errcheck
+
+	if err := controller.Enqueue(ReconcileRequest{ResourceKind: "TestResource", ResourceUID: "test-655", Reason: "Test"}); err != nil {
+		t.Fatalf("Failed to enqueue: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for reconciler.GetCallCount() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the failing request to be retried, got %d calls", reconciler.GetCallCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestController_Resync(t *testing.T) {
+	ctx := context.Background()
+
+	backend := storage.NewMemoryBackend()
+	for _, uid := range []string{"test-1", "test-2"} {
+		resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": uid}})
+		if err := backend.Save(ctx, "TestResource", uid, resourceData); err != nil {
+			t.Fatalf("Failed to save test resource: %v", err)
+		}
+	}
+	resourceData, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"uid": "other-1"}})
+	if err := backend.Save(ctx, "OtherResource", "other-1", resourceData); err != nil {
+		t.Fatalf("Failed to save other resource: %v", err)
+	}
+
+	controller := NewController(events.NewInMemoryEventBus(10, 1), backend)
+	if err := controller.RegisterReconciler(&mockReconciler{}); err != nil {
+		t.Fatalf("Failed to register reconciler: %v", err)
+	}
+
+	if err := controller.Resync(ctx); err != nil {
+		t.Fatalf("Resync failed: %v", err)
+	}
+
+	// Only resources of registered kinds are queued
+	if controller.QueueDepth() != 2 {
+		t.Fatalf("Expected 2 queued requests, got %d", controller.QueueDepth())
+	}
+	for i := 0; i < 2; i++ {
+		item, _ := controller.queue.Get()
+		request := item.(ReconcileRequest)
+		if request.ResourceKind != "TestResource" || request.Reason != "Resync" {
+			t.Errorf("Unexpected request %v (%s)", request, request.Reason)
+		}
+		controller.queue.Done(item)
+	}
+}
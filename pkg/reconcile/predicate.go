@@ -0,0 +1,165 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package reconcile
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+
+	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+// Predicate reports whether an event should trigger a reconciliation of
+// its resource.
+type Predicate func(event events.Event) bool
+
+// EventFilter is implemented by reconcilers that filter the events
+// triggering them.
+//
+// The controller calls Predicates once, when the reconciler is registered,
+// and enqueues an event's resource only if every predicate accepts the
+// event. Requeues, resyncs and Enqueue aren't filtered.
+//
+// Example:
+//
+//	func (r *DeviceReconciler) Predicates() []reconcile.Predicate {
+//	    return []reconcile.Predicate{
+//	        reconcile.IgnoreStatusUpdates(),
+//	        reconcile.MatchLabels(map[string]string{"managed": "true"}),
+//	    }
+//	}
+type EventFilter interface {
+	Predicates() []Predicate
+}
+
+// IgnoreStatusUpdates returns a predicate rejecting status updates and
+// patches and condition change events. Reconcilers write status; reacting
+// to status changes mostly reconciles resources whose spec didn't change.
+func IgnoreStatusUpdates() Predicate {
+	return func(event events.Event) bool {
+		if _, ok := event.Extensions()["conditiontype"]; ok {
+			return false
+		}
+		return eventChange(event).updateType != "status"
+	}
+}
+
+// MatchLabels returns a predicate accepting events of resources with all
+// the labels of selector. Events without the resource in their data are
+// accepted, since their labels are unknown.
+func MatchLabels(selector map[string]string) Predicate {
+	return func(event events.Event) bool {
+		res := eventChange(event).resource
+		if res == nil {
+			return true
+		}
+		for key, value := range selector {
+			if res.Metadata.Labels[key] != value {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// GenerationChanged returns a predicate rejecting updates that leave the
+// spec unchanged, such as status and label updates, like the generation
+// checks of Kubernetes controllers.
+//
+// Resources have no generation counter, so the predicate keeps a hash of
+// the spec of the last event it saw for each resource. The first event of
+// a resource, deletions and events without the resource in their data are
+// accepted. Use one predicate per reconciler.
+func GenerationChanged() Predicate {
+	var mu sync.Mutex
+	specs := make(map[string][sha256.Size]byte)
+	return func(event events.Event) bool {
+		change := eventChange(event)
+		uid := event.ResourceUID()
+		mu.Lock()
+		defer mu.Unlock()
+		if change.action == "deleted" || change.action == "delete" {
+			delete(specs, uid)
+			return true
+		}
+		if change.resource == nil {
+			return true
+		}
+		spec := sha256.Sum256(change.resource.Spec)
+		previous, seen := specs[uid]
+		specs[uid] = spec
+		return !seen || previous != spec
+	}
+}
+
+// matchesPredicates reports whether every predicate accepts event
+func matchesPredicates(predicates []Predicate, event events.Event) bool {
+	for _, predicate := range predicates {
+		if !predicate(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// changedResource is the part of a resource in event data predicates and
+// watches read
+type changedResource struct {
+	Metadata struct {
+		Labels          map[string]string         `json:"labels"`
+		OwnerReferences []resource.OwnerReference `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+// change is what predicates know of the change an event reports
+type change struct {
+	action     string
+	updateType string
+	resource   *changedResource // nil if the data holds no resource
+}
+
+// eventChange reads the change of a resource event, with either data format:
+// the resource as data and the change metadata as extension attributes
+// (events.DataFormatResource), or an events.ResourceChangeData
+// (events.DataFormatEnvelope).
+func eventChange(event events.Event) change {
+	var c change
+	extensions := event.Extensions()
+	c.action, _ = extensions["action"].(string)
+	c.updateType, _ = extensions["updatetype"].(string)
+
+	var data map[string]json.RawMessage
+	if err := event.DataAs(&data); err != nil {
+		return c
+	}
+	var raw json.RawMessage
+	if _, ok := data["resourceKind"]; ok {
+		raw = data["resource"]
+		var envelope struct {
+			Action   string                 `json:"action"`
+			Metadata map[string]interface{} `json:"metadata"`
+		}
+		if err := event.DataAs(&envelope); err != nil {
+			return c
+		}
+		c.action = envelope.Action
+		if updateType, ok := envelope.Metadata["updateType"].(string); ok {
+			c.updateType = updateType
+		}
+	} else if _, ok := data["metadata"]; ok {
+		raw = event.Data()
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return c
+	}
+	var res changedResource
+	if err := json.Unmarshal(raw, &res); err == nil {
+		c.resource = &res
+	}
+	return c
+}
@@ -0,0 +1,140 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// device is the data of resource events in predicate tests
+type device struct {
+	Kind     string            `json:"kind"`
+	Metadata map[string]any    `json:"metadata"`
+	Spec     map[string]string `json:"spec"`
+	Status   map[string]string `json:"status,omitempty"`
+}
+
+func newDevice(labels map[string]string, address string) device {
+	return device{
+		Kind:     "Device",
+		Metadata: map[string]any{"uid": "dev-1", "labels": labels},
+		Spec:     map[string]string{"address": address},
+	}
+}
+
+// resourceEvent builds a resource event as events.PublishResourceChange
+// publishes it, in both data formats
+func resourceEvent(t *testing.T, envelope bool, action string, resource any, metadata map[string]any) events.Event {
+	t.Helper()
+	events.SetEventConfig(&events.EventConfig{
+		Enabled:                true,
+		EventTypePrefix:        "io.fabrica",
+		LifecycleEventsEnabled: true,
+	})
+	var data any = resource
+	if envelope {
+		data = events.ResourceChangeData{Action: action, ResourceKind: "Device", ResourceUID: "dev-1", Resource: resource, Metadata: metadata}
+	}
+	event, err := events.NewResourceEvent(action, "Device", "dev-1", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !envelope {
+		for key, value := range metadata {
+			event.SetExtension(key, value)
+		}
+	}
+	return *event
+}
+
+func TestPredicates(t *testing.T) {
+	for _, envelope := range []bool{false, true} {
+		format := map[bool]string{false: "resource", true: "envelope"}[envelope]
+		t.Run(format, func(t *testing.T) {
+			prod := newDevice(map[string]string{"tier": "prod"}, "10.0.0.1")
+			created := resourceEvent(t, envelope, "created", prod, nil)
+			statusUpdate := resourceEvent(t, envelope, "updated", prod, map[string]any{"updateType": "status"})
+			deleted := resourceEvent(t, envelope, "deleted", nil, nil)
+
+			ignoreStatus := IgnoreStatusUpdates()
+			if !ignoreStatus(created) || ignoreStatus(statusUpdate) {
+				t.Error("IgnoreStatusUpdates didn't reject only the status update")
+			}
+
+			match := MatchLabels(map[string]string{"tier": "prod"})
+			dev := resourceEvent(t, envelope, "updated", newDevice(map[string]string{"tier": "dev"}, "10.0.0.1"), nil)
+			if !match(created) || match(dev) || !match(deleted) {
+				t.Error("MatchLabels didn't reject only the resource without the label")
+			}
+
+			changed := GenerationChanged()
+			relabeled := resourceEvent(t, envelope, "updated", newDevice(map[string]string{"tier": "test"}, "10.0.0.1"), nil)
+			moved := resourceEvent(t, envelope, "updated", newDevice(map[string]string{"tier": "test"}, "10.0.0.2"), nil)
+			for i, want := range []struct {
+				event events.Event
+				pass  bool
+			}{
+				{created, true},
+				{statusUpdate, false},
+				{relabeled, false},
+				{moved, true},
+				{deleted, true},
+				{created, true}, // Recreated after the deletion
+			} {
+				if got := changed(want.event); got != want.pass {
+					t.Errorf("GenerationChanged(event %d) = %v, want %v", i, got, want.pass)
+				}
+			}
+		})
+	}
+}
+
+func TestIgnoreStatusUpdatesConditionEvents(t *testing.T) {
+	events.SetEventConfig(&events.EventConfig{Enabled: true, EventTypePrefix: "io.fabrica", ConditionEventsEnabled: true})
+	event, err := events.NewConditionEvent("Ready", "True", "Device", "dev-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IgnoreStatusUpdates()(*event) {
+		t.Error("IgnoreStatusUpdates accepted a condition event")
+	}
+}
+
+// filteringReconciler is a mockReconciler implementing EventFilter
+type filteringReconciler struct {
+	mockReconciler
+	predicates []Predicate
+}
+
+func (r *filteringReconciler) Predicates() []Predicate {
+	return r.predicates
+}
+
+func TestController_Predicates(t *testing.T) {
+	controller := NewController(events.NewInMemoryEventBus(10, 1), nil)
+	reconciler := &filteringReconciler{
+		mockReconciler: mockReconciler{kind: "Device"},
+		predicates:     []Predicate{IgnoreStatusUpdates()},
+	}
+	if err := controller.RegisterReconciler(reconciler); err != nil {
+		t.Fatal(err)
+	}
+
+	prod := newDevice(nil, "10.0.0.1")
+	for _, event := range []events.Event{
+		resourceEvent(t, false, "updated", prod, map[string]any{"updateType": "status"}),
+		resourceEvent(t, false, "updated", prod, nil),
+	} {
+		if err := controller.handleEvent(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if controller.queue.Len() != 1 {
+		t.Errorf("Expected only the spec update queued, got %d requests", controller.queue.Len())
+	}
+}
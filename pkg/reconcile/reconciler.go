@@ -30,15 +30,22 @@
 //	controller := reconcile.NewController(eventBus, storage)
 //	controller.RegisterReconciler(deviceReconciler)
 //	controller.Start(ctx)
+//
+// Reconcile is called with a context carrying a logger tagged with the
+// resource kind and UID. Retrieve it with logging.FromContext(ctx).
 package reconcile
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/logging"
+	"github.com/openchami/fabrica/pkg/storage"
 )
 
 // Reconciler handles resource reconciliation.
@@ -97,6 +104,62 @@ type ClientInterface interface {
 	Delete(ctx context.Context, kind, uid string) error
 }
 
+// BatchGetter is implemented by clients that load many resources of a kind in
+// one call, such as the generated storage client. Use GetMany rather than
+// calling it directly, so clients without it still work.
+type BatchGetter interface {
+	// GetMany retrieves the resources with the given UIDs, in request order
+	// without duplicates, and the UIDs that don't exist
+	GetMany(ctx context.Context, kind string, uids []string) ([]interface{}, []string, error)
+}
+
+// GetMany retrieves resources of a kind by UID, for reconcilers resolving
+// many references at once.
+//
+// Clients implementing BatchGetter load them in one call; for other clients
+// GetMany calls Get once per UID.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - client: Client to load the resources through
+//   - kind: Resource kind (e.g., "Device")
+//   - uids: UIDs to load; repeated UIDs are loaded once
+//
+// Returns:
+//   - []interface{}: The resources found, in the order of uids
+//   - []string: The UIDs that don't exist (storage.ErrNotFound)
+//   - error: The first other error
+//
+// Example:
+//
+//	found, missing, err := reconcile.GetMany(ctx, r.Client, "Device", rack.Spec.DeviceUIDs)
+func GetMany(ctx context.Context, client ClientInterface, kind string, uids []string) ([]interface{}, []string, error) {
+	if batch, ok := client.(BatchGetter); ok {
+		return batch.GetMany(ctx, kind, uids)
+	}
+
+	found := make([]interface{}, 0, len(uids))
+	notFound := []string{}
+	seen := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+
+		res, err := client.Get(ctx, kind, uid)
+		if errors.Is(err, storage.ErrNotFound) {
+			notFound = append(notFound, uid)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s %s: %w", kind, uid, err)
+		}
+		found = append(found, res)
+	}
+	return found, notFound, nil
+}
+
 // BaseReconciler provides common functionality for reconcilers.
 //
 // Resource-specific reconcilers should embed this struct to get:
@@ -347,26 +410,64 @@ type Logger interface {
 	Debugf(format string, args ...interface{})
 }
 
-// defaultLogger is a simple logger that writes to stdout
-type defaultLogger struct{}
+// slogLogger writes through a slog.Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// get returns the logger, or the reconcile component of slog.Default() when
+// none was given. The default is looked up on each call so that loggers
+// created before slog.SetDefault still follow the server's configuration.
+func (l *slogLogger) get() *slog.Logger {
+	if l.logger != nil {
+		return l.logger
+	}
+	return logging.Component(slog.Default(), logging.ComponentReconcile)
+}
+
+func (l *slogLogger) Infof(format string, args ...interface{}) {
+	l.get().Info(fmt.Sprintf(format, args...))
+}
 
-func (l *defaultLogger) Infof(format string, args ...interface{}) {
-	fmt.Printf("[INFO] "+format+"\n", args...)
+func (l *slogLogger) Warnf(format string, args ...interface{}) {
+	l.get().Warn(fmt.Sprintf(format, args...))
 }
 
-func (l *defaultLogger) Warnf(format string, args ...interface{}) {
-	fmt.Printf("[WARN] "+format+"\n", args...)
+func (l *slogLogger) Errorf(format string, args ...interface{}) {
+	l.get().Error(fmt.Sprintf(format, args...))
 }
 
-func (l *defaultLogger) Errorf(format string, args ...interface{}) {
-	fmt.Printf("[ERROR] "+format+"\n", args...)
+func (l *slogLogger) Debugf(format string, args ...interface{}) {
+	l.get().Debug(fmt.Sprintf(format, args...))
 }
 
-func (l *defaultLogger) Debugf(format string, args ...interface{}) {
-	fmt.Printf("[DEBUG] "+format+"\n", args...)
+// NewSlogLogger adapts a slog.Logger to Logger.
+//
+// Parameters:
+//   - logger: Destination logger, or nil for the reconcile component of
+//     slog.Default()
+//
+// Returns:
+//   - Logger: Logger for reconcilers and the controller
+//
+// Example:
+//
+//	controller.SetLogger(reconcile.NewSlogLogger(logging.Component(logger, logging.ComponentReconcile)))
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
 }
 
-// NewDefaultLogger creates a simple stdout logger
+// NewDefaultLogger creates a logger writing through slog.Default(), tagged
+// component=reconcile. Levels and format follow the server's slog setup.
 func NewDefaultLogger() Logger {
-	return &defaultLogger{}
+	return NewSlogLogger(nil)
+}
+
+// slogFor returns the slog.Logger behind a Logger, or the reconcile
+// component of slog.Default() for other implementations
+func slogFor(logger Logger) *slog.Logger {
+	if l, ok := logger.(*slogLogger); ok {
+		return l.get()
+	}
+	return logging.Component(slog.Default(), logging.ComponentReconcile)
 }
@@ -0,0 +1,77 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// mapClient is a ClientInterface over a map of UIDs to resources
+type mapClient struct {
+	resources map[string]string
+	gets      int
+}
+
+func (m *mapClient) Get(_ context.Context, _, uid string) (interface{}, error) {
+	m.gets++
+	if uid == "broken" {
+		return nil, errors.New("backend unavailable")
+	}
+	res, ok := m.resources[uid]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return res, nil
+}
+
+func (m *mapClient) List(context.Context, string) ([]interface{}, error) { return nil, nil }
+func (m *mapClient) Update(context.Context, interface{}) error           { return nil }
+func (m *mapClient) Create(context.Context, interface{}) error           { return nil }
+func (m *mapClient) Delete(context.Context, string, string) error        { return nil }
+
+// batchClient answers GetMany itself
+type batchClient struct {
+	mapClient
+	batches int
+}
+
+func (b *batchClient) GetMany(_ context.Context, _ string, uids []string) ([]interface{}, []string, error) {
+	b.batches++
+	return []interface{}{"batched"}, uids[1:], nil
+}
+
+func TestGetMany(t *testing.T) {
+	ctx := context.Background()
+	client := &mapClient{resources: map[string]string{"dev-1": "one", "dev-2": "two"}}
+
+	found, notFound, err := GetMany(ctx, client, "Device", []string{"dev-2", "dev-9", "dev-1", "dev-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(found, []interface{}{"two", "one"}) || !reflect.DeepEqual(notFound, []string{"dev-9"}) {
+		t.Errorf("got found %v, not found %v", found, notFound)
+	}
+	if client.gets != 3 {
+		t.Errorf("expected repeated UIDs to be loaded once, got %d gets", client.gets)
+	}
+
+	if _, _, err := GetMany(ctx, client, "Device", []string{"dev-1", "broken"}); err == nil {
+		t.Error("expected the Get error to be returned")
+	}
+
+	batch := &batchClient{}
+	found, notFound, err = GetMany(ctx, batch, "Device", []string{"dev-1", "dev-2"})
+	if err != nil || batch.batches != 1 || batch.gets != 0 {
+		t.Fatalf("expected one GetMany call, got %d batches, %d gets, %v", batch.batches, batch.gets, err)
+	}
+	if !reflect.DeepEqual(found, []interface{}{"batched"}) || !reflect.DeepEqual(notFound, []string{"dev-2"}) {
+		t.Errorf("got found %v, not found %v", found, notFound)
+	}
+}
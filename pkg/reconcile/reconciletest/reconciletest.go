@@ -0,0 +1,511 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package reconciletest unit tests reconcilers without a running server.
+//
+// A Harness holds a fake client over in-memory storage, an event bus
+// recording the events reconcilers emit, and a reconcile.Controller that is
+// never started: events are injected synchronously, and every reconciliation
+// they trigger has run when Inject returns.
+//
+//	h := reconciletest.New(t)
+//	h.Client.RegisterKind("Device", func() interface{} { return &device.Device{} })
+//	h.Register(reconcilers.NewDefaultDeviceReconciler(h.Client, h.Events))
+//	h.LoadFixtures("testdata/devices.yaml")
+//
+//	for _, outcome := range h.Change("updated", dev) {
+//	    if outcome.Err != nil {
+//	        t.Fatal(outcome.Err)
+//	    }
+//	}
+//	h.AssertCondition("Device", dev.GetUID(), "Ready", "True")
+//
+// Projects with reconciliation get a generated harness in
+// pkg/reconcilers/reconcilerstest that registers their kinds and reconcilers
+// and adds typed accessors.
+package reconciletest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/reconcile"
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// object is the part of a resource the client needs to store it
+type object interface {
+	GetKind() string
+	GetUID() string
+}
+
+// Client is a reconcile.ClientInterface over in-memory storage. Resources
+// are stored as JSON, so reconcilers get copies, as with real storage. Get
+// and List return the types registered with RegisterKind.
+type Client struct {
+	backend *storage.MemoryBackend
+
+	mu    sync.RWMutex
+	kinds map[string]func() interface{}
+}
+
+// Compile-time checks that Client implements reconcile.ClientInterface and
+// loads batches of resources for reconcile.GetMany
+var (
+	_ reconcile.ClientInterface = (*Client)(nil)
+	_ reconcile.BatchGetter     = (*Client)(nil)
+)
+
+// NewClient creates a client with empty storage.
+func NewClient() *Client {
+	return &Client{backend: storage.NewMemoryBackend(), kinds: make(map[string]func() interface{})}
+}
+
+// RegisterKind sets the type Get and List decode resources of kind into.
+// newObject returns a pointer to a new resource, e.g. &device.Device{}.
+func (c *Client) RegisterKind(kind string, newObject func() interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kinds[kind] = newObject
+}
+
+// Backend returns the storage the client reads and writes.
+func (c *Client) Backend() storage.StorageBackend {
+	return c.backend
+}
+
+// Get implements reconcile.ClientInterface.Get.
+func (c *Client) Get(ctx context.Context, kind, uid string) (interface{}, error) {
+	data, err := c.backend.Load(ctx, kind, uid)
+	if err != nil {
+		return nil, err
+	}
+	return c.decode(kind, data)
+}
+
+// GetMany implements reconcile.BatchGetter.
+func (c *Client) GetMany(ctx context.Context, kind string, uids []string) ([]interface{}, []string, error) {
+	var found []interface{}
+	var notFound []string
+	seen := make(map[string]bool)
+	for _, uid := range uids {
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		obj, err := c.Get(ctx, kind, uid)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			notFound = append(notFound, uid)
+		case err != nil:
+			return nil, nil, err
+		default:
+			found = append(found, obj)
+		}
+	}
+	return found, notFound, nil
+}
+
+// List implements reconcile.ClientInterface.List.
+func (c *Client) List(ctx context.Context, kind string) ([]interface{}, error) {
+	items, err := c.backend.LoadAll(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]interface{}, 0, len(items))
+	for _, data := range items {
+		obj, err := c.decode(kind, data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, obj)
+	}
+	return result, nil
+}
+
+// Update implements reconcile.ClientInterface.Update.
+func (c *Client) Update(ctx context.Context, obj interface{}) error {
+	res, ok := obj.(object)
+	if !ok {
+		return fmt.Errorf("resource %T does not implement GetKind and GetUID", obj)
+	}
+	if res.GetKind() == "" || res.GetUID() == "" {
+		return fmt.Errorf("resource %T has no kind or UID", obj)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	return c.backend.Save(ctx, res.GetKind(), res.GetUID(), data)
+}
+
+// Create implements reconcile.ClientInterface.Create. Like the generated
+// storage client, it saves the resource whether or not it exists.
+func (c *Client) Create(ctx context.Context, obj interface{}) error {
+	return c.Update(ctx, obj)
+}
+
+// Delete implements reconcile.ClientInterface.Delete.
+func (c *Client) Delete(ctx context.Context, kind, uid string) error {
+	return c.backend.Delete(ctx, kind, uid)
+}
+
+// decode unmarshals a stored resource into the registered type of kind
+func (c *Client) decode(kind string, data json.RawMessage) (interface{}, error) {
+	c.mu.RLock()
+	newObject, ok := c.kinds[kind]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown resource kind: %s", kind)
+	}
+	obj := newObject()
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", kind, err)
+	}
+	return obj, nil
+}
+
+// Recorder is an events.EventBus recording the events published on it.
+// Subscribers are never called.
+type Recorder struct {
+	mu     sync.Mutex
+	events []events.Event
+	nextID int
+}
+
+// Publish implements events.EventBus.Publish by recording the event.
+func (r *Recorder) Publish(_ context.Context, event events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// Subscribe implements events.EventBus.Subscribe.
+func (r *Recorder) Subscribe(_ string, _ events.EventHandler) (events.SubscriptionID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	return events.SubscriptionID(fmt.Sprintf("recorder-%d", r.nextID)), nil
+}
+
+// Unsubscribe implements events.EventBus.Unsubscribe.
+func (r *Recorder) Unsubscribe(_ events.SubscriptionID) error {
+	return nil
+}
+
+// Close implements events.EventBus.Close.
+func (r *Recorder) Close() error {
+	return nil
+}
+
+// Events returns the recorded events, oldest first.
+func (r *Recorder) Events() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Event(nil), r.events...)
+}
+
+// Types returns the types of the recorded events, oldest first.
+func (r *Recorder) Types() []string {
+	recorded := r.Events()
+	types := make([]string, len(recorded))
+	for i, event := range recorded {
+		types[i] = event.Type()
+	}
+	return types
+}
+
+// Reset forgets the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// Outcome is a reconciliation run by the harness.
+type Outcome struct {
+	Request reconcile.ReconcileRequest
+	Result  reconcile.Result
+	Err     error
+}
+
+// Harness runs reconcilers synchronously against a fake client.
+type Harness struct {
+	// Client is the fake client; pass it to reconcilers
+	Client *Client
+
+	// Events records the events reconcilers emit; pass it to reconcilers
+	Events *Recorder
+
+	// Controller dispatches injected events to the registered reconcilers,
+	// applying their predicates and watches. It is never started.
+	Controller *reconcile.Controller
+
+	tb testing.TB
+}
+
+// New creates a harness with empty storage. Lifecycle events are enabled
+// for the test if they aren't, so Change can build them.
+func New(tb testing.TB) *Harness {
+	tb.Helper()
+	if !events.IsEnabled() || !events.AreLifecycleEventsEnabled() {
+		previous := *events.GetEventConfig()
+		config := previous
+		config.Enabled = true
+		config.LifecycleEventsEnabled = true
+		if config.EventTypePrefix == "" {
+			config.EventTypePrefix = "io.fabrica"
+		}
+		events.SetEventConfig(&config)
+		tb.Cleanup(func() { events.SetEventConfig(&previous) })
+	}
+
+	client := NewClient()
+	recorder := &Recorder{}
+	controller := reconcile.NewController(recorder, client.Backend())
+	controller.SetLogger(reconcile.NewSlogLogger(slog.New(slog.NewTextHandler(testWriter{tb}, nil))))
+	return &Harness{Client: client, Events: recorder, Controller: controller, tb: tb}
+}
+
+// testWriter writes controller logs to the test log
+type testWriter struct {
+	tb testing.TB
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// TB returns the test of the harness.
+func (h *Harness) TB() testing.TB {
+	return h.tb
+}
+
+// Register registers a reconciler with the controller, failing the test on
+// error.
+func (h *Harness) Register(reconciler reconcile.Reconciler) {
+	h.tb.Helper()
+	if err := h.Controller.RegisterReconciler(reconciler); err != nil {
+		h.tb.Fatalf("failed to register reconciler: %v", err)
+	}
+}
+
+// Seed stores resources as they are, without triggering reconciliation.
+// Each must have a kind and a UID.
+func (h *Harness) Seed(objs ...interface{}) {
+	h.tb.Helper()
+	for _, obj := range objs {
+		if err := h.Client.Update(context.Background(), obj); err != nil {
+			h.tb.Fatalf("failed to seed resource: %v", err)
+		}
+	}
+}
+
+// LoadFixtures stores the resources of YAML or JSON files, without
+// triggering reconciliation. A file holds one resource, a list of resources
+// or several YAML documents; each resource has a kind and metadata.uid.
+func (h *Harness) LoadFixtures(paths ...string) {
+	h.tb.Helper()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			h.tb.Fatalf("failed to read fixtures: %v", err)
+		}
+		docs, err := decodeFixtures(data)
+		if err != nil {
+			h.tb.Fatalf("invalid fixtures in %s: %v", path, err)
+		}
+		for i, doc := range docs {
+			kind, _ := doc["kind"].(string)
+			metadata, _ := doc["metadata"].(map[string]interface{})
+			uid, _ := metadata["uid"].(string)
+			if kind == "" || uid == "" {
+				h.tb.Fatalf("fixture %d in %s has no kind or metadata.uid", i, path)
+			}
+			raw, err := json.Marshal(doc)
+			if err != nil {
+				h.tb.Fatalf("invalid fixture %d in %s: %v", i, path, err)
+			}
+			if err := h.Client.backend.Save(context.Background(), kind, uid, raw); err != nil {
+				h.tb.Fatalf("failed to load fixture %s/%s: %v", kind, uid, err)
+			}
+		}
+	}
+}
+
+// decodeFixtures reads the resources of a fixtures file
+func decodeFixtures(data []byte) ([]map[string]interface{}, error) {
+	var docs []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		items, isList := doc.([]interface{})
+		if !isList {
+			items = []interface{}{doc}
+		}
+		for _, item := range items {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected a resource, got %T", item)
+			}
+			docs = append(docs, m)
+		}
+	}
+}
+
+// Inject dispatches an event as the running controller would, and runs the
+// reconciliations it triggers before returning their outcomes. Requeues
+// aren't run; their Result is in the outcome.
+func (h *Harness) Inject(event events.Event) []Outcome {
+	h.tb.Helper()
+	var outcomes []Outcome
+	for _, request := range h.Controller.Requests(event) {
+		result, err := h.Controller.ReconcileNow(context.Background(), request)
+		outcomes = append(outcomes, Outcome{Request: request, Result: result, Err: err})
+	}
+	return outcomes
+}
+
+// Change stores a resource and injects the lifecycle event of action
+// ("created", "updated", "deleted", ...), with the resource as data, as
+// servers publish it. A deleted resource is removed from storage instead.
+func (h *Harness) Change(action string, obj interface{}) []Outcome {
+	h.tb.Helper()
+	res, ok := obj.(object)
+	if !ok {
+		h.tb.Fatalf("resource %T does not implement GetKind and GetUID", obj)
+	}
+	if action == "deleted" || action == "delete" {
+		if err := h.Client.Delete(context.Background(), res.GetKind(), res.GetUID()); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			h.tb.Fatalf("failed to delete %s/%s: %v", res.GetKind(), res.GetUID(), err)
+		}
+	} else {
+		h.Seed(obj)
+	}
+	event, err := events.NewResourceEvent(action, res.GetKind(), res.GetUID(), obj)
+	if err != nil {
+		h.tb.Fatalf("failed to create %s event: %v", action, err)
+	}
+	return h.Inject(*event)
+}
+
+// Reconcile reconciles one resource, as a requeue or resync would.
+func (h *Harness) Reconcile(kind, uid string) (reconcile.Result, error) {
+	return h.Controller.ReconcileNow(context.Background(), reconcile.ReconcileRequest{
+		ResourceKind: kind,
+		ResourceUID:  uid,
+		Reason:       "Test",
+	})
+}
+
+// Get returns a stored resource as its registered type, failing the test if
+// it doesn't exist.
+func (h *Harness) Get(kind, uid string) interface{} {
+	h.tb.Helper()
+	obj, err := h.Client.Get(context.Background(), kind, uid)
+	if err != nil {
+		h.tb.Fatalf("failed to get %s/%s: %v", kind, uid, err)
+	}
+	return obj
+}
+
+// Exists reports whether a resource is stored.
+func (h *Harness) Exists(kind, uid string) bool {
+	h.tb.Helper()
+	_, err := h.Client.backend.Load(context.Background(), kind, uid)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		h.tb.Fatalf("failed to load %s/%s: %v", kind, uid, err)
+	}
+	return err == nil
+}
+
+// Status returns the status of a stored resource as JSON values, failing
+// the test if it doesn't exist.
+func (h *Harness) Status(kind, uid string) map[string]interface{} {
+	h.tb.Helper()
+	data, err := h.Client.backend.Load(context.Background(), kind, uid)
+	if err != nil {
+		h.tb.Fatalf("failed to load %s/%s: %v", kind, uid, err)
+	}
+	var doc struct {
+		Status map[string]interface{} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		h.tb.Fatalf("invalid %s/%s: %v", kind, uid, err)
+	}
+	return doc.Status
+}
+
+// Condition returns a status condition of a stored resource, or nil if it
+// has none of condType.
+func (h *Harness) Condition(kind, uid, condType string) *resource.Condition {
+	h.tb.Helper()
+	raw, err := json.Marshal(h.Status(kind, uid)["conditions"])
+	if err != nil {
+		h.tb.Fatalf("invalid conditions of %s/%s: %v", kind, uid, err)
+	}
+	var conditions []resource.Condition
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		h.tb.Fatalf("invalid conditions of %s/%s: %v", kind, uid, err)
+	}
+	return resource.FindCondition(conditions, condType)
+}
+
+// AssertCondition checks the status of a condition of a stored resource.
+func (h *Harness) AssertCondition(kind, uid, condType, status string) {
+	h.tb.Helper()
+	condition := h.Condition(kind, uid, condType)
+	switch {
+	case condition == nil:
+		h.tb.Errorf("%s/%s has no %s condition", kind, uid, condType)
+	case condition.Status != status:
+		h.tb.Errorf("%s/%s condition %s = %s (%s: %s), want %s",
+			kind, uid, condType, condition.Status, condition.Reason, condition.Message, status)
+	}
+}
+
+// AssertStatus checks a status field of a stored resource, at a dotted JSON
+// path (e.g., "health" or "power.state"). want is compared in its JSON form.
+func (h *Harness) AssertStatus(kind, uid, path string, want interface{}) {
+	h.tb.Helper()
+	var got interface{} = h.Status(kind, uid)
+	for _, key := range strings.Split(path, ".") {
+		m, _ := got.(map[string]interface{})
+		got = m[key]
+	}
+	raw, err := json.Marshal(want)
+	if err != nil {
+		h.tb.Fatalf("invalid expected status %s: %v", path, err)
+	}
+	var wantJSON interface{}
+	if err := json.Unmarshal(raw, &wantJSON); err != nil {
+		h.tb.Fatalf("invalid expected status %s: %v", path, err)
+	}
+	if !reflect.DeepEqual(got, wantJSON) {
+		h.tb.Errorf("%s/%s status.%s = %v, want %v", kind, uid, path, got, wantJSON)
+	}
+}
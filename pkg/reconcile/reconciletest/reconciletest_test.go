@@ -0,0 +1,186 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package reconciletest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/reconcile"
+	"github.com/openchami/fabrica/pkg/resource"
+)
+
+type rackSpec struct {
+	Slots int `json:"slots"`
+}
+
+type rackStatus struct {
+	Devices    int                  `json:"devices"`
+	Conditions []resource.Condition `json:"conditions,omitempty"`
+}
+
+type rack struct {
+	resource.Resource
+	Spec   rackSpec   `json:"spec"`
+	Status rackStatus `json:"status"`
+}
+
+func (r *rack) GetKind() string { return r.Kind }
+
+type device struct {
+	resource.Resource
+	Spec struct {
+		RackID string `json:"rackId"`
+	} `json:"spec"`
+}
+
+func (d *device) GetKind() string { return d.Kind }
+
+// rackReconciler counts the devices of a rack, failing when they don't fit
+type rackReconciler struct {
+	reconcile.BaseReconciler
+}
+
+func (r *rackReconciler) GetResourceKind() string { return "Rack" }
+
+func (r *rackReconciler) Watches() []reconcile.Watch {
+	return []reconcile.Watch{{Kind: "Device", Map: reconcile.EnqueueReferenced("Rack", "rackId")}}
+}
+
+func (r *rackReconciler) Reconcile(ctx context.Context, obj interface{}) (reconcile.Result, error) {
+	var res rack
+	if err := json.Unmarshal(obj.(json.RawMessage), &res); err != nil {
+		return reconcile.Result{}, err
+	}
+	devices, err := r.Client.List(ctx, "Device")
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	res.Status.Devices = 0
+	for _, item := range devices {
+		if item.(*device).Spec.RackID == res.GetUID() {
+			res.Status.Devices++
+		}
+	}
+	var reconcileErr error
+	if res.Status.Devices > res.Spec.Slots {
+		reconcileErr = fmt.Errorf("%d devices in %d slots", res.Status.Devices, res.Spec.Slots)
+		resource.SetCondition(&res.Status.Conditions, "Ready", "False", "Full", reconcileErr.Error())
+	} else {
+		resource.SetCondition(&res.Status.Conditions, "Ready", "True", "Fits", "")
+	}
+	if err := r.UpdateStatus(ctx, &res); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.EmitEvent(ctx, "counted", &res); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, reconcileErr
+}
+
+func newHarness(t *testing.T) *Harness {
+	h := New(t)
+	h.Client.RegisterKind("Rack", func() interface{} { return &rack{} })
+	h.Client.RegisterKind("Device", func() interface{} { return &device{} })
+	h.Register(&rackReconciler{BaseReconciler: reconcile.BaseReconciler{
+		Client:   h.Client,
+		EventBus: h.Events,
+		Logger:   reconcile.NewDefaultLogger(),
+	}})
+	return h
+}
+
+func newDevice(uid, rackID string) *device {
+	d := &device{}
+	d.Kind = "Device"
+	d.Metadata.UID = uid
+	d.Spec.RackID = rackID
+	return d
+}
+
+func TestHarness(t *testing.T) {
+	h := newHarness(t)
+
+	fixtures := filepath.Join(t.TempDir(), "racks.yaml")
+	err := os.WriteFile(fixtures, []byte(`kind: Rack
+metadata: {uid: rack-1}
+spec: {slots: 1}
+---
+- kind: Device
+  metadata: {uid: dev-1}
+  spec: {rackId: rack-1}
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.LoadFixtures(fixtures)
+	if !h.Exists("Device", "dev-1") || h.Get("Rack", "rack-1").(*rack).Spec.Slots != 1 {
+		t.Fatal("fixtures weren't loaded")
+	}
+
+	// Creating a device reconciles its rack through the watch
+	outcomes := h.Change("created", newDevice("dev-2", "rack-1"))
+	if len(outcomes) != 1 || outcomes[0].Request.String() != "Rack/rack-1" || outcomes[0].Err == nil {
+		t.Fatalf("Unexpected outcomes %+v", outcomes)
+	}
+	h.AssertStatus("Rack", "rack-1", "devices", 2)
+	h.AssertCondition("Rack", "rack-1", "Ready", "False")
+	if types := h.Events.Types(); len(types) != 1 || types[0] != "io.fabrica.rack.counted" {
+		t.Errorf("Unexpected events %v", types)
+	}
+
+	// Deleting it reconciles the rack again
+	outcomes = h.Change("deleted", newDevice("dev-2", "rack-1"))
+	if len(outcomes) != 1 || outcomes[0].Err != nil {
+		t.Fatalf("Unexpected outcomes %+v", outcomes)
+	}
+	if h.Exists("Device", "dev-2") {
+		t.Error("Deleted device is still stored")
+	}
+	h.AssertStatus("Rack", "rack-1", "devices", 1)
+	h.AssertCondition("Rack", "rack-1", "Ready", "True")
+
+	if _, err := h.Reconcile("Rack", "rack-1"); err != nil {
+		t.Errorf("Reconcile failed: %v", err)
+	}
+	if _, err := h.Reconcile("Rack", "rack-2"); err == nil {
+		t.Error("Reconcile of a missing rack succeeded")
+	}
+	if h.Condition("Rack", "rack-1", "Degraded") != nil {
+		t.Error("Unexpected Degraded condition")
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient()
+	client.RegisterKind("Device", func() interface{} { return &device{} })
+	for _, uid := range []string{"dev-1", "dev-2"} {
+		if err := client.Create(ctx, newDevice(uid, "rack-1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.Create(ctx, newDevice("", "rack-1")); err == nil {
+		t.Error("Create accepted a resource without UID")
+	}
+
+	found, notFound, err := reconcile.GetMany(ctx, client, "Device", []string{"dev-2", "dev-3", "dev-2"})
+	if err != nil || len(found) != 1 || found[0].(*device).GetUID() != "dev-2" || len(notFound) != 1 || notFound[0] != "dev-3" {
+		t.Errorf("GetMany = %v, %v, %v", found, notFound, err)
+	}
+	if err := client.Delete(ctx, "Device", "dev-1"); err != nil {
+		t.Fatal(err)
+	}
+	if items, err := client.List(ctx, "Device"); err != nil || len(items) != 1 {
+		t.Errorf("List = %v, %v", items, err)
+	}
+	if _, err := client.Get(ctx, "Rack", "rack-1"); err == nil {
+		t.Error("Get of an unregistered kind succeeded")
+	}
+}
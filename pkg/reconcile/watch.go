@@ -0,0 +1,200 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package reconcile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// MapFunc maps an event of a watched kind to the resources to reconcile,
+// e.g. a Connection event to the Devices it connects.
+type MapFunc func(event events.Event) []ReconcileRequest
+
+// Watch re-triggers a reconciler on events of another kind.
+type Watch struct {
+	// Kind is the watched resource kind (e.g., "Connection")
+	Kind string
+
+	// Map returns the resources to reconcile for an event of Kind
+	Map MapFunc
+
+	// Predicates filter the events of Kind before Map (optional). The
+	// reconciler's own predicates don't apply to watched kinds.
+	Predicates []Predicate
+}
+
+// Watcher is implemented by reconcilers whose resources depend on resources
+// of other kinds, so that changes to the dependents re-trigger their
+// reconciliation.
+//
+// The controller calls Watches once, when the reconciler is registered.
+// Generated reconcilers watch the kinds whose spec fields reference their
+// kind with a `fabrica:"parent=<Kind>"` or `fabrica:"ref=<Kind>"` tag.
+//
+// Example:
+//
+//	func (r *DeviceReconciler) Watches() []reconcile.Watch {
+//	    return []reconcile.Watch{{
+//	        Kind: "Connection",
+//	        Map:  reconcile.EnqueueReferenced("Device", "endpointA.deviceId", "endpointB.deviceId"),
+//	    }}
+//	}
+type Watcher interface {
+	Watches() []Watch
+}
+
+// Watch registers a watch of another kind before Start, for reconcilers
+// that don't implement Watcher or resources related at run time, such as
+// through owner references:
+//
+//	controller.Watch(reconcile.Watch{Kind: "Connection", Map: reconcile.EnqueueOwners("Device")})
+//
+// Parameters:
+//   - watch: The watched kind and its mapping
+//
+// Returns:
+//   - error: If the watch has no kind or no mapping function
+func (c *Controller) Watch(watch Watch) error {
+	if watch.Kind == "" || watch.Map == nil {
+		return fmt.Errorf("watch requires a kind and a mapping function")
+	}
+	c.watches[watch.Kind] = append(c.watches[watch.Kind], watch)
+	return nil
+}
+
+// watchedRequests returns the requests the watches of an event's kind map
+// it to, for kinds with a registered reconciler
+func (c *Controller) watchedRequests(event events.Event) []ReconcileRequest {
+	var requests []ReconcileRequest
+	for _, watch := range c.watches[event.ResourceKind()] {
+		if !matchesPredicates(watch.Predicates, event) {
+			continue
+		}
+		for _, request := range watch.Map(event) {
+			if _, exists := c.reconcilers[request.ResourceKind]; !exists || request.ResourceUID == "" {
+				continue
+			}
+			if request.Reason == "" {
+				request.Reason = fmt.Sprintf("Watch: %s %s/%s", event.Type(), event.ResourceKind(), event.ResourceUID())
+			}
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// EnqueueReferenced returns a mapping function reconciling the resources of
+// kind whose UIDs an event's resource holds in its spec.
+//
+// Paths are dotted JSON paths relative to the spec, as in ?expand=, and may
+// go through nested structs and lists of structs; the field may hold a UID
+// or a list of UIDs. Events only carry the new state of a resource, so when
+// an update moves a reference, only the newly referenced resource is
+// reconciled.
+//
+// Parameters:
+//   - kind: The referenced kind (e.g., "Device")
+//   - paths: Spec fields holding its UIDs (e.g., "endpointA.deviceId")
+func EnqueueReferenced(kind string, paths ...string) MapFunc {
+	split := make([][]string, len(paths))
+	for i, path := range paths {
+		split[i] = strings.Split(strings.TrimPrefix(path, "spec."), ".")
+	}
+	return func(event events.Event) []ReconcileRequest {
+		res := eventChange(event).resource
+		if res == nil || len(res.Spec) == 0 {
+			return nil
+		}
+		var spec interface{}
+		if err := json.Unmarshal(res.Spec, &spec); err != nil {
+			return nil
+		}
+		var uids []string
+		for _, path := range split {
+			uids = collectUIDs(spec, path, uids)
+		}
+		return requestsFor(kind, uids)
+	}
+}
+
+// EnqueueOwners returns a mapping function reconciling the owners of kind
+// listed in the owner references of an event's resource (see
+// resource.OwnerReference).
+//
+// Parameters:
+//   - kind: The owner kind (e.g., "Rack")
+func EnqueueOwners(kind string) MapFunc {
+	return func(event events.Event) []ReconcileRequest {
+		res := eventChange(event).resource
+		if res == nil {
+			return nil
+		}
+		var uids []string
+		for _, ref := range res.Metadata.OwnerReferences {
+			if ref.Kind == kind {
+				uids = append(uids, ref.UID)
+			}
+		}
+		return requestsFor(kind, uids)
+	}
+}
+
+// MapAll returns a mapping function combining the requests of several
+// mapping functions.
+func MapAll(maps ...MapFunc) MapFunc {
+	return func(event events.Event) []ReconcileRequest {
+		var requests []ReconcileRequest
+		for _, m := range maps {
+			requests = append(requests, m(event)...)
+		}
+		return requests
+	}
+}
+
+// collectUIDs appends the UIDs at path below value to uids
+func collectUIDs(value interface{}, path []string, uids []string) []string {
+	if len(path) == 0 {
+		switch v := value.(type) {
+		case string:
+			uids = append(uids, v)
+		case []interface{}:
+			for _, item := range v {
+				if uid, ok := item.(string); ok {
+					uids = append(uids, uid)
+				}
+			}
+		}
+		return uids
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return collectUIDs(v[path[0]], path[1:], uids)
+	case []interface{}:
+		// A list of structs: collect the field of each one
+		for _, item := range v {
+			uids = collectUIDs(item, path, uids)
+		}
+	}
+	return uids
+}
+
+// requestsFor returns a request per distinct non-empty UID
+func requestsFor(kind string, uids []string) []ReconcileRequest {
+	var requests []ReconcileRequest
+	seen := make(map[string]bool)
+	for _, uid := range uids {
+		if uid == "" || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		requests = append(requests, ReconcileRequest{ResourceKind: kind, ResourceUID: uid})
+	}
+	return requests
+}
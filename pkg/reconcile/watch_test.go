@@ -0,0 +1,133 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package reconcile
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/events"
+)
+
+// connectionEvent builds a Connection event as events.PublishResourceChange
+// publishes it, in both data formats
+func connectionEvent(t *testing.T, envelope bool, action string, metadata, spec map[string]any) events.Event {
+	t.Helper()
+	events.SetEventConfig(&events.EventConfig{
+		Enabled:                true,
+		EventTypePrefix:        "io.fabrica",
+		LifecycleEventsEnabled: true,
+	})
+	var data any = map[string]any{"kind": "Connection", "metadata": metadata, "spec": spec}
+	if envelope {
+		data = events.ResourceChangeData{Action: action, ResourceKind: "Connection", ResourceUID: "con-1", Resource: data}
+	}
+	event, err := events.NewResourceEvent(action, "Connection", "con-1", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *event
+}
+
+func uidsOf(requests []ReconcileRequest) []string {
+	var uids []string
+	for _, request := range requests {
+		uids = append(uids, request.ResourceKind+"/"+request.ResourceUID)
+	}
+	return uids
+}
+
+func TestEnqueueReferenced(t *testing.T) {
+	spec := map[string]any{
+		"endpointA": map[string]any{"deviceId": "dev-1"},
+		"endpointB": map[string]any{"deviceId": "dev-2"},
+		"hops":      []any{map[string]any{"deviceIds": []any{"dev-3", "dev-1"}}, map[string]any{}},
+		"rackId":    "rack-1",
+	}
+	mapDevices := EnqueueReferenced("Device", "endpointA.deviceId", "spec.endpointB.deviceId", "hops.deviceIds")
+	for _, envelope := range []bool{false, true} {
+		event := connectionEvent(t, envelope, "deleted", map[string]any{"uid": "con-1"}, spec)
+		got := uidsOf(mapDevices(event))
+		want := []string{"Device/dev-1", "Device/dev-2", "Device/dev-3"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("envelope=%v: EnqueueReferenced = %v, want %v", envelope, got, want)
+		}
+	}
+
+	event := connectionEvent(t, false, "created", map[string]any{"uid": "con-1"}, map[string]any{})
+	if requests := mapDevices(event); len(requests) != 0 {
+		t.Errorf("EnqueueReferenced without references = %v", uidsOf(requests))
+	}
+}
+
+func TestEnqueueOwners(t *testing.T) {
+	metadata := map[string]any{
+		"uid": "con-1",
+		"ownerReferences": []any{
+			map[string]any{"kind": "Rack", "uid": "rack-1"},
+			map[string]any{"kind": "Device", "uid": "dev-1"},
+		},
+	}
+	for _, envelope := range []bool{false, true} {
+		event := connectionEvent(t, envelope, "updated", metadata, nil)
+		got := uidsOf(MapAll(EnqueueOwners("Rack"), EnqueueOwners("Chassis"))(event))
+		if want := []string{"Rack/rack-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("envelope=%v: EnqueueOwners = %v, want %v", envelope, got, want)
+		}
+	}
+}
+
+// watchingReconciler is a mockReconciler implementing Watcher
+type watchingReconciler struct {
+	mockReconciler
+	watches []Watch
+}
+
+func (r *watchingReconciler) Watches() []Watch {
+	return r.watches
+}
+
+func TestController_Watches(t *testing.T) {
+	controller := NewController(events.NewInMemoryEventBus(10, 1), nil)
+	reconciler := &watchingReconciler{
+		mockReconciler: mockReconciler{kind: "Device"},
+		watches: []Watch{{
+			Kind:       "Connection",
+			Map:        EnqueueReferenced("Device", "endpointA.deviceId"),
+			Predicates: []Predicate{IgnoreStatusUpdates()},
+		}},
+	}
+	if err := controller.RegisterReconciler(reconciler); err != nil {
+		t.Fatal(err)
+	}
+	// Requests of kinds without a reconciler are dropped
+	if err := controller.Watch(Watch{Kind: "Connection", Map: EnqueueOwners("Rack")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := controller.Watch(Watch{Kind: "Connection"}); err == nil {
+		t.Error("Watch accepted a watch without a mapping function")
+	}
+
+	spec := map[string]any{"endpointA": map[string]any{"deviceId": "dev-1"}}
+	metadata := map[string]any{"uid": "con-1", "ownerReferences": []any{map[string]any{"kind": "Rack", "uid": "rack-1"}}}
+	statusUpdate := connectionEvent(t, false, "updated", metadata, spec)
+	statusUpdate.SetExtension("updateType", "status")
+	for _, event := range []events.Event{statusUpdate, connectionEvent(t, false, "updated", metadata, spec)} {
+		if err := controller.handleEvent(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if controller.QueueDepth() != 1 {
+		t.Fatalf("Expected 1 queued request, got %d", controller.QueueDepth())
+	}
+	item, _ := controller.queue.Get()
+	request := item.(ReconcileRequest)
+	if request.String() != "Device/dev-1" {
+		t.Errorf("Unexpected request %v (%s)", request, request.Reason)
+	}
+	controller.queue.Done(item)
+}
@@ -3,6 +3,17 @@
 // SPDX-License-Identifier: MIT
 
 // Package resource provides Kubernetes-style resource conditions for tracking resource status.
+//
+// By convention a resource reports conditions in a status field named
+// Conditions:
+//
+//	type DeviceStatus struct {
+//	    Conditions []resource.Condition `json:"conditions,omitempty"`
+//	}
+//
+// Generated handlers and reconcilers detect the field and maintain the
+// standard Ready and Degraded conditions, so every generated service reports
+// status the same way.
 package resource
 
 import (
@@ -10,6 +21,24 @@ import (
 	"time"
 )
 
+// Condition status values
+const (
+	ConditionTrue    = "True"
+	ConditionFalse   = "False"
+	ConditionUnknown = "Unknown"
+)
+
+// Standard condition types
+const (
+	// ConditionReady is True when the resource is in its desired state and usable
+	ConditionReady = "Ready"
+	// ConditionDegraded is True when the resource works with reduced
+	// functionality, or its last reconciliation failed
+	ConditionDegraded = "Degraded"
+	// ConditionProgressing is True while the resource moves toward its desired state
+	ConditionProgressing = "Progressing"
+)
+
 // Condition represents a specific condition of a resource.
 //
 // Conditions follow the Kubernetes pattern for representing the status of
@@ -43,10 +72,11 @@ import (
 //	}
 //
 // Common Condition Types:
-//   - "Ready": Resource is ready for use
+//   - "Ready": Resource is ready for use (ConditionReady)
+//   - "Degraded": Resource works with reduced functionality (ConditionDegraded)
+//   - "Progressing": Resource is making progress toward desired state (ConditionProgressing)
 //   - "Healthy": Resource is functioning properly
 //   - "Reachable": Resource can be contacted
-//   - "Progressing": Resource is making progress toward desired state
 type Condition struct {
 	Type               string    `json:"type" yaml:"type"`
 	Status             string    `json:"status" yaml:"status"` // "True", "False", "Unknown"
@@ -89,21 +119,21 @@ func NewCondition(conditionType, status, reason, message string) Condition {
 //	    // Handle true condition
 //	}
 func (c *Condition) IsTrue() bool {
-	return c.Status == "True"
+	return c.Status == ConditionTrue
 }
 
 // IsFalse checks if condition status is "False".
 //
 // This is a convenience method for checking if a condition is in the "False" state.
 func (c *Condition) IsFalse() bool {
-	return c.Status == "False"
+	return c.Status == ConditionFalse
 }
 
 // IsUnknown checks if condition status is "Unknown".
 //
 // This is a convenience method for checking if a condition is in the "Unknown" state.
 func (c *Condition) IsUnknown() bool {
-	return c.Status == "Unknown"
+	return c.Status == ConditionUnknown
 }
 
 // Update updates the condition if status, reason, or message changed.
@@ -262,7 +292,7 @@ func SetConditionWithEvents(ctx context.Context, conditions *[]Condition, condit
 
 	// Publish event for new condition if we have resource info
 	if resourceKind != "" && resourceUID != "" {
-		publishConditionEvent(ctx, conditionType, status, "Unknown", resourceKind, resourceUID, reason, message)
+		publishConditionEvent(ctx, conditionType, status, ConditionUnknown, resourceKind, resourceUID, reason, message)
 	}
 
 	return true
@@ -342,11 +372,41 @@ func IsConditionTrue(conditions []Condition, conditionType string) bool {
 func GetConditionStatus(conditions []Condition, conditionType string) string {
 	condition := FindCondition(conditions, conditionType)
 	if condition == nil {
-		return "Unknown"
+		return ConditionUnknown
 	}
 	return condition.Status
 }
 
+// MergeConditions returns the desired conditions with Kubernetes-style
+// transition times, for status updates that replace a whole conditions list.
+//
+// A condition whose status is unchanged from current keeps its
+// LastTransitionTime. A new or changed condition keeps the time it was sent
+// with, or gets the current time when it has none. Returns nil when desired
+// is nil.
+//
+// Example:
+//
+//	// In a status update handler
+//	update.Conditions = MergeConditions(res.Status.Conditions, update.Conditions)
+func MergeConditions(current, desired []Condition) []Condition {
+	if desired == nil {
+		return nil
+	}
+
+	now := time.Now()
+	merged := make([]Condition, len(desired))
+	for i, condition := range desired {
+		if previous := FindCondition(current, condition.Type); previous != nil && previous.Status == condition.Status {
+			condition.LastTransitionTime = previous.LastTransitionTime
+		} else if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = now
+		}
+		merged[i] = condition
+	}
+	return merged
+}
+
 // ConditionEventPublisher is a function type for publishing condition change events.
 // This allows the conditions package to publish events without directly depending
 // on the events package, maintaining clean separation of concerns.
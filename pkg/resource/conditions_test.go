@@ -0,0 +1,73 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetCondition_KeepsTransitionTime(t *testing.T) {
+	var conditions []Condition
+	if !SetCondition(&conditions, ConditionReady, ConditionFalse, "Provisioning", "Creating chassis") {
+		t.Fatal("Expected adding a condition to report a change")
+	}
+	transitioned := time.Now().Add(-time.Hour)
+	conditions[0].LastTransitionTime = transitioned
+
+	// A new message alone is not a transition
+	if !SetCondition(&conditions, ConditionReady, ConditionFalse, "Provisioning", "Creating blades") {
+		t.Fatal("Expected a new message to report a change")
+	}
+	if !conditions[0].LastTransitionTime.Equal(transitioned) {
+		t.Error("Expected the transition time to be kept when the status is unchanged")
+	}
+	if SetCondition(&conditions, ConditionReady, ConditionFalse, "Provisioning", "Creating blades") {
+		t.Error("Expected an identical condition to report no change")
+	}
+
+	SetCondition(&conditions, ConditionReady, ConditionTrue, "Provisioned", "All resources created")
+	if !IsConditionTrue(conditions, ConditionReady) || !conditions[0].LastTransitionTime.After(transitioned) {
+		t.Errorf("Expected Ready to transition to True, got %+v", conditions[0])
+	}
+	if GetConditionStatus(conditions, ConditionDegraded) != ConditionUnknown {
+		t.Error("Expected a missing condition to be Unknown")
+	}
+}
+
+func TestMergeConditions(t *testing.T) {
+	transitioned := time.Now().Add(-time.Hour).UTC()
+	sent := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := []Condition{
+		{Type: ConditionReady, Status: ConditionTrue, LastTransitionTime: transitioned},
+		{Type: ConditionDegraded, Status: ConditionFalse, LastTransitionTime: transitioned},
+	}
+	desired := []Condition{
+		{Type: ConditionReady, Status: ConditionTrue, Reason: "Healthy"},
+		{Type: ConditionDegraded, Status: ConditionTrue, Reason: "FanFailure"},
+		{Type: ConditionProgressing, Status: ConditionTrue, LastTransitionTime: sent},
+	}
+
+	merged := MergeConditions(current, desired)
+	if len(merged) != 3 {
+		t.Fatalf("Expected 3 conditions, got %+v", merged)
+	}
+	if !merged[0].LastTransitionTime.Equal(transitioned) || merged[0].Reason != "Healthy" {
+		t.Errorf("Expected an unchanged status to keep its transition time, got %+v", merged[0])
+	}
+	if !merged[1].LastTransitionTime.After(transitioned) {
+		t.Errorf("Expected a changed status to be stamped now, got %+v", merged[1])
+	}
+	if !merged[2].LastTransitionTime.Equal(sent) {
+		t.Errorf("Expected the sent transition time to be kept, got %+v", merged[2])
+	}
+	if !desired[0].LastTransitionTime.IsZero() {
+		t.Error("Expected the desired conditions to be left unmodified")
+	}
+
+	if MergeConditions(current, nil) != nil {
+		t.Error("Expected nil for no desired conditions")
+	}
+}
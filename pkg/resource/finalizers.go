@@ -0,0 +1,120 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+// Finalizers
+//
+// Finalizers are string keys stored in Metadata.Finalizers, recording the
+// controllers that still have cleanup to do for a resource. They are only
+// stored: Fabrica doesn't defer deletion while finalizers remain, so a DELETE
+// removes the resource right away. A controller that must clean up first
+// checks the finalizers before deleting the resource itself. Keys should be
+// namespaced to the component that owns them, for example
+// "fabrica.dev/storage-cleanup".
+//
+// Typical reconciler flow:
+//
+//	if !r.HasFinalizer(MyFinalizer) {
+//	    r.AddFinalizer(MyFinalizer)
+//	    return client.Update(ctx, r)
+//	}
+//	// ... once the cleanup is done:
+//	r.RemoveFinalizer(MyFinalizer)
+//	if len(r.GetFinalizers()) == 0 {
+//	    return client.Delete(ctx, kind, r.GetUID())
+//	}
+
+// AddFinalizer adds a finalizer key to the metadata.
+//
+// Adding a finalizer that is already present is a no-op, so reconcilers
+// can call this on every pass without producing duplicates.
+//
+// Returns true if the finalizer was added, false if it was already present.
+func (m *Metadata) AddFinalizer(finalizer string) bool {
+	if m.HasFinalizer(finalizer) {
+		return false
+	}
+	m.Finalizers = append(m.Finalizers, finalizer)
+	return true
+}
+
+// RemoveFinalizer removes a finalizer key from the metadata.
+//
+// The order of the remaining finalizers is preserved. Safe to call even if
+// the finalizer doesn't exist. When the last finalizer is removed the slice
+// is reset to nil so it is omitted from the JSON representation.
+//
+// Returns true if the finalizer was removed, false if it was not present.
+func (m *Metadata) RemoveFinalizer(finalizer string) bool {
+	removed := false
+	remaining := m.Finalizers[:0]
+	for _, f := range m.Finalizers {
+		if f == finalizer {
+			removed = true
+			continue
+		}
+		remaining = append(remaining, f)
+	}
+	if len(remaining) == 0 {
+		remaining = nil
+	}
+	m.Finalizers = remaining
+	return removed
+}
+
+// HasFinalizer checks if the metadata contains the given finalizer key.
+func (m *Metadata) HasFinalizer(finalizer string) bool {
+	for _, f := range m.Finalizers {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFinalizers returns a copy of the finalizer keys.
+//
+// Modifying the returned slice won't affect the metadata. Returns nil if
+// no finalizers are set.
+func (m *Metadata) GetFinalizers() []string {
+	if m.Finalizers == nil {
+		return nil
+	}
+	result := make([]string, len(m.Finalizers))
+	copy(result, m.Finalizers)
+	return result
+}
+
+// AddFinalizer adds a finalizer key to the resource.
+//
+// This is a convenience method for resource.Metadata.AddFinalizer.
+//
+// Example:
+//
+//	if device.AddFinalizer("inventory.example.com/bmc-cleanup") {
+//	    // persist the change before doing any external work
+//	}
+func (r *Resource) AddFinalizer(finalizer string) bool {
+	return r.Metadata.AddFinalizer(finalizer)
+}
+
+// RemoveFinalizer removes a finalizer key from the resource.
+//
+// This is a convenience method for resource.Metadata.RemoveFinalizer.
+func (r *Resource) RemoveFinalizer(finalizer string) bool {
+	return r.Metadata.RemoveFinalizer(finalizer)
+}
+
+// HasFinalizer checks if the resource has the given finalizer key.
+//
+// This is a convenience method for resource.Metadata.HasFinalizer.
+func (r *Resource) HasFinalizer(finalizer string) bool {
+	return r.Metadata.HasFinalizer(finalizer)
+}
+
+// GetFinalizers returns a copy of the resource's finalizer keys.
+func (r *Resource) GetFinalizers() []string {
+	return r.Metadata.GetFinalizers()
+}
@@ -0,0 +1,80 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFinalizers_AddRemoveHas(t *testing.T) {
+	r := &Resource{}
+
+	if r.HasFinalizer("a") {
+		t.Fatal("Expected no finalizer on empty resource")
+	}
+	if !r.AddFinalizer("a") {
+		t.Error("Expected first AddFinalizer to return true")
+	}
+	if r.AddFinalizer("a") {
+		t.Error("Expected duplicate AddFinalizer to return false")
+	}
+	r.AddFinalizer("b")
+	r.AddFinalizer("c")
+
+	if got := r.GetFinalizers(); len(got) != 3 {
+		t.Fatalf("Expected 3 finalizers, got %v", got)
+	}
+
+	if !r.RemoveFinalizer("b") {
+		t.Error("Expected RemoveFinalizer to return true for existing key")
+	}
+	if r.RemoveFinalizer("b") {
+		t.Error("Expected RemoveFinalizer to return false for missing key")
+	}
+	got := r.GetFinalizers()
+	if len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("Expected [a c], got %v", got)
+	}
+
+	r.RemoveFinalizer("a")
+	r.RemoveFinalizer("c")
+	if r.Metadata.Finalizers != nil {
+		t.Errorf("Expected nil finalizers after removing all, got %v", r.Metadata.Finalizers)
+	}
+}
+
+func TestFinalizers_JSON(t *testing.T) {
+	r := &Resource{}
+	data, _ := json.Marshal(r.Metadata)
+	if strings.Contains(string(data), "finalizers") {
+		t.Errorf("Expected finalizers to be omitted when empty, got %s", data)
+	}
+
+	r.AddFinalizer("example.com/cleanup")
+	data, _ = json.Marshal(r.Metadata)
+
+	var decoded Metadata
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !decoded.HasFinalizer("example.com/cleanup") {
+		t.Errorf("Expected finalizer to round-trip, got %s", data)
+	}
+}
+
+func TestFinalizers_CloneIsIndependent(t *testing.T) {
+	m := &Metadata{}
+	m.AddFinalizer("a")
+
+	clone := m.Clone()
+	clone.AddFinalizer("b")
+	clone.Finalizers[0] = "changed"
+
+	if len(m.Finalizers) != 1 || m.Finalizers[0] != "a" {
+		t.Errorf("Expected original finalizers untouched, got %v", m.Finalizers)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ID Strategies
+//
+// An ID strategy decides how UIDs are minted for a resource kind. Kinds use
+// the registered prefix with random hex digits (dev-1a2b3c4d) unless
+// SetIDStrategy assigns them another strategy:
+//
+//	func init() {
+//	    RegisterResourcePrefix("Device", "dev")
+//	    SetIDStrategy("Device", UUIDv7IDs)
+//	}
+
+// IDStrategy mints the UID of a new resource.
+//
+// NewUID receives the prefix registered for the resource kind and the name
+// the resource is created with, which strategies are free to ignore.
+type IDStrategy interface {
+	NewUID(prefix, name string) (string, error)
+}
+
+// IDStrategyFunc adapts a function to the IDStrategy interface.
+type IDStrategyFunc func(prefix, name string) (string, error)
+
+// NewUID calls f(prefix, name).
+func (f IDStrategyFunc) NewUID(prefix, name string) (string, error) {
+	return f(prefix, name)
+}
+
+// ErrInvalidUIDName is returned by NameIDs for names that cannot be used
+// as a UID.
+var ErrInvalidUIDName = errors.New("name cannot be used as a UID")
+
+var (
+	// PrefixIDs mints <prefix>-<8 random hex digits> UIDs, such as
+	// dev-1a2b3c4d. It is the default strategy.
+	PrefixIDs IDStrategy = IDStrategyFunc(func(prefix, _ string) (string, error) {
+		return GenerateUID(prefix)
+	})
+
+	// PrefixULIDs mints <prefix>-<ULID> UIDs, such as
+	// dev-01j9r8m4xk3v2h7q0c5t6w8y9z. ULIDs sort by creation time and carry
+	// 80 random bits.
+	PrefixULIDs IDStrategy = IDStrategyFunc(func(prefix, _ string) (string, error) {
+		id, err := newULID(time.Now())
+		if err != nil {
+			return "", err
+		}
+		return prefix + "-" + id, nil
+	})
+
+	// UUIDv7IDs mints time-ordered version 7 UUIDs.
+	UUIDv7IDs IDStrategy = IDStrategyFunc(func(_, _ string) (string, error) {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate UUID: %w", err)
+		}
+		return id.String(), nil
+	})
+
+	// UUIDIDs mints random version 4 UUIDs.
+	UUIDIDs IDStrategy = IDStrategyFunc(func(_, _ string) (string, error) {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate UUID: %w", err)
+		}
+		return id.String(), nil
+	})
+
+	// NameIDs uses the name a resource is created with as its UID, so
+	// names identify resources as UIDs do and cannot be reused. Names must be
+	// usable unescaped in URL paths and file names: at most 253 letters,
+	// digits, '-', '_', '.' and '~', other than "." and "..". Other names
+	// are rejected with ErrInvalidUIDName. GenerateUIDForResource, which has
+	// no name, falls back to PrefixIDs.
+	NameIDs IDStrategy = nameIDs{}
+)
+
+// nameIDs is the type of NameIDs, recognized by GenerateUIDForResource
+type nameIDs struct{}
+
+// NewUID returns name if it is a valid UID.
+func (nameIDs) NewUID(_, name string) (string, error) {
+	if !validUIDName(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUIDName, name)
+	}
+	return name, nil
+}
+
+// idStrategies maps the strategy names accepted by IDStrategyByName to
+// their strategies.
+var idStrategies = map[string]IDStrategy{
+	"prefix": PrefixIDs,
+	"ulid":   PrefixULIDs,
+	"uuidv7": UUIDv7IDs,
+	"uuid":   UUIDIDs,
+	"name":   NameIDs,
+}
+
+// IDStrategyByName returns the built-in strategy with the given name: prefix,
+// ulid, uuidv7, uuid or name.
+//
+// Example:
+//
+//	strategy, err := IDStrategyByName("uuidv7")
+func IDStrategyByName(name string) (IDStrategy, error) {
+	strategy, ok := idStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ID strategy %q (want prefix, ulid, uuidv7, uuid or name)", name)
+	}
+	return strategy, nil
+}
+
+// idStrategiesByKind holds the strategies assigned with SetIDStrategy,
+// guarded by resourcePrefixesMutex.
+var idStrategiesByKind = make(map[string]IDStrategy)
+
+// SetIDStrategy assigns the strategy minting UIDs for a resource kind. A nil
+// strategy restores the default, PrefixIDs.
+//
+// Unlike RegisterResourcePrefix, it may be called any number of times; the
+// last call wins.
+func SetIDStrategy(resourceKind string, strategy IDStrategy) {
+	resourcePrefixesMutex.Lock()
+	defer resourcePrefixesMutex.Unlock()
+
+	if strategy == nil {
+		delete(idStrategiesByKind, resourceKind)
+		return
+	}
+	idStrategiesByKind[resourceKind] = strategy
+}
+
+// GenerateUIDForNamedResource creates a UID for a resource of the given kind
+// and name, using the strategy assigned with SetIDStrategy.
+//
+// Returns an error if the resource kind is not registered or the strategy
+// fails, for example with ErrInvalidUIDName.
+//
+// Example:
+//
+//	uid, err := GenerateUIDForNamedResource("Device", "node-1")
+func GenerateUIDForNamedResource(resourceKind, name string) (string, error) {
+	prefix, strategy, err := idStrategyFor(resourceKind)
+	if err != nil {
+		return "", err
+	}
+	return strategy.NewUID(prefix, name)
+}
+
+// idStrategyFor returns the registered prefix of a resource kind and its
+// strategy
+func idStrategyFor(resourceKind string) (string, IDStrategy, error) {
+	resourcePrefixesMutex.RLock()
+	prefix, exists := resourcePrefixes[resourceKind]
+	strategy, assigned := idStrategiesByKind[resourceKind]
+	resourcePrefixesMutex.RUnlock()
+
+	if !exists {
+		return "", nil, fmt.Errorf("resource kind '%s' is not registered - call RegisterResourcePrefix() first", resourceKind)
+	}
+	if !assigned {
+		strategy = PrefixIDs
+	}
+	return prefix, strategy, nil
+}
+
+// validUIDName reports whether NameIDs accepts name as a UID
+func validUIDName(name string) bool {
+	if name == "" || name == "." || name == ".." || len(name) > 253 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.~", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// crockford is the lowercase Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// newULID returns a ULID for t: a 48-bit millisecond timestamp followed by 80
+// random bits, as 26 lowercase Crockford base32 characters.
+func newULID(t time.Time) (string, error) {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	if _, err := rand.Read(id[6:]); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	// 128 bits are 26 characters of 5 bits, the first holding only 3
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:]), nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateUIDForNamedResource_Strategies(t *testing.T) {
+	RegisterResourcePrefix("IDStrategyWidget", "idw")
+	defer SetIDStrategy("IDStrategyWidget", nil)
+
+	uid, err := GenerateUIDForResource("IDStrategyWidget")
+	if err != nil || !IsValidUID(uid) || !strings.HasPrefix(uid, "idw-") {
+		t.Fatalf("Expected default prefix UID, got %q (%v)", uid, err)
+	}
+
+	SetIDStrategy("IDStrategyWidget", PrefixULIDs)
+	uid, err = GenerateUIDForNamedResource("IDStrategyWidget", "w1")
+	if err != nil || !regexp.MustCompile(`^idw-[0-9a-hjkmnp-tv-z]{26}$`).MatchString(uid) {
+		t.Fatalf("Expected prefixed ULID, got %q (%v)", uid, err)
+	}
+
+	SetIDStrategy("IDStrategyWidget", UUIDv7IDs)
+	uid, err = GenerateUIDForNamedResource("IDStrategyWidget", "w1")
+	if id, perr := uuid.Parse(uid); err != nil || perr != nil || id.Version() != 7 {
+		t.Fatalf("Expected UUIDv7, got %q (%v)", uid, err)
+	}
+
+	SetIDStrategy("IDStrategyWidget", UUIDIDs)
+	uid, err = GenerateUIDForNamedResource("IDStrategyWidget", "w1")
+	if id, perr := uuid.Parse(uid); err != nil || perr != nil || id.Version() != 4 {
+		t.Fatalf("Expected UUIDv4, got %q (%v)", uid, err)
+	}
+
+	SetIDStrategy("IDStrategyWidget", NameIDs)
+	if uid, err = GenerateUIDForNamedResource("IDStrategyWidget", "Rack_1.a~b-2"); err != nil || uid != "Rack_1.a~b-2" {
+		t.Fatalf("Expected name as UID, got %q (%v)", uid, err)
+	}
+	for _, name := range []string{"", ".", "..", "a/b", "a b", "a?b", strings.Repeat("a", 254)} {
+		if _, err := GenerateUIDForNamedResource("IDStrategyWidget", name); !errors.Is(err, ErrInvalidUIDName) {
+			t.Errorf("Expected ErrInvalidUIDName for %q, got %v", name, err)
+		}
+	}
+
+	// Without a name, NameIDs falls back to prefix UIDs
+	if uid, err = GenerateUIDForResource("IDStrategyWidget"); err != nil || !IsValidUID(uid) || !strings.HasPrefix(uid, "idw-") {
+		t.Fatalf("Expected prefix UID without a name, got %q (%v)", uid, err)
+	}
+
+	SetIDStrategy("IDStrategyWidget", nil)
+	if uid, err = GenerateUIDForNamedResource("IDStrategyWidget", "w1"); err != nil || !IsValidUID(uid) {
+		t.Fatalf("Expected default restored, got %q (%v)", uid, err)
+	}
+}
+
+func TestGenerateUIDForNamedResource_Unregistered(t *testing.T) {
+	if _, err := GenerateUIDForNamedResource("IDStrategyUnregistered", "x"); err == nil {
+		t.Fatal("Expected error for unregistered kind")
+	}
+}
+
+func TestIDStrategyByName(t *testing.T) {
+	for _, name := range []string{"prefix", "ulid", "uuidv7", "uuid", "name"} {
+		if _, err := IDStrategyByName(name); err != nil {
+			t.Errorf("IDStrategyByName(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := IDStrategyByName("snowflake"); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestNewULID_SortsByTime(t *testing.T) {
+	earlier, err := newULID(time.UnixMilli(1700000000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	later, err := newULID(time.UnixMilli(1700000000001))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(earlier) != 26 || earlier[:10] >= later[:10] {
+		t.Errorf("Expected time-ordered ULIDs, got %q and %q", earlier, later)
+	}
+	// The timestamp is the first 10 characters: 1700000000000 ms
+	if earlier[:10] != "01hf7yat00" {
+		t.Errorf("Unexpected ULID timestamp %q", earlier[:10])
+	}
+}
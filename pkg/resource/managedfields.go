@@ -0,0 +1,109 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import "time"
+
+// Managed fields
+//
+// Metadata.ManagedFields records which field manager owns which fields of a
+// resource, in the style of Kubernetes server-side apply. A field manager is
+// any named client, typically a controller ("rack-controller") or a tool
+// ("fabrica-cli"). Each manager has at most one entry per operation:
+//
+//   - Apply: fields the manager declared in its last applied configuration.
+//     Fields it stops declaring are removed unless another manager owns them.
+//   - Update: fields the manager changed with an ordinary update or patch.
+//
+// Fields are dotted JSON paths of leaf values, such as "spec.bmc.address".
+// See package apply for the merge and conflict rules.
+
+// Managed field operations
+const (
+	// ManagedFieldsApply marks fields owned through server-side apply
+	ManagedFieldsApply = "Apply"
+
+	// ManagedFieldsUpdate marks fields owned through updates and patches
+	ManagedFieldsUpdate = "Update"
+)
+
+// ManagedFieldsEntry lists the fields a field manager owns.
+//
+// Fields:
+//   - Manager: Name of the field manager
+//   - Operation: ManagedFieldsApply or ManagedFieldsUpdate
+//   - Time: When the manager last changed its fields
+//   - Fields: Dotted JSON paths of the owned values, sorted
+type ManagedFieldsEntry struct {
+	Manager   string    `json:"manager" yaml:"manager"`
+	Operation string    `json:"operation" yaml:"operation"`
+	Time      time.Time `json:"time" yaml:"time"`
+	Fields    []string  `json:"fields" yaml:"fields"`
+}
+
+// Clone returns a deep copy of the entry.
+func (e ManagedFieldsEntry) Clone() ManagedFieldsEntry {
+	clone := e
+	if e.Fields != nil {
+		clone.Fields = make([]string, len(e.Fields))
+		copy(clone.Fields, e.Fields)
+	}
+	return clone
+}
+
+// GetManagedFields returns the entry of a field manager for an operation.
+//
+// Parameters:
+//   - manager: Name of the field manager
+//   - operation: ManagedFieldsApply or ManagedFieldsUpdate
+//
+// Returns:
+//   - *ManagedFieldsEntry: The entry, or nil if the manager owns no fields through the operation
+func (m *Metadata) GetManagedFields(manager, operation string) *ManagedFieldsEntry {
+	for i := range m.ManagedFields {
+		if m.ManagedFields[i].Manager == manager && m.ManagedFields[i].Operation == operation {
+			return &m.ManagedFields[i]
+		}
+	}
+	return nil
+}
+
+// FieldManagers returns the managers owning a field, or a value inside or
+// above it, in the order of their entries.
+//
+// Example:
+//
+//	resource.Metadata.FieldManagers("spec.bmc") // ["rack-controller"] if it owns spec.bmc.address
+func (m *Metadata) FieldManagers(field string) []string {
+	var managers []string
+	for _, entry := range m.ManagedFields {
+		for _, owned := range entry.Fields {
+			if FieldsOverlap(owned, field) {
+				managers = appendUnique(managers, entry.Manager)
+				break
+			}
+		}
+	}
+	return managers
+}
+
+// FieldsOverlap reports whether two dotted field paths are equal or one
+// contains the other, e.g. "spec.bmc" and "spec.bmc.address".
+func FieldsOverlap(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || (len(b) > len(a) && b[:len(a)] == a && b[len(a)] == '.')
+}
+
+// appendUnique appends a value to a slice unless it's already present
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
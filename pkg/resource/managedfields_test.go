@@ -0,0 +1,52 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"spec.bmc", "spec.bmc", true},
+		{"spec.bmc", "spec.bmc.address", true},
+		{"spec.bmc.address", "spec", true},
+		{"spec.bmc", "spec.bmcAddress", false},
+		{"spec.rack", "spec.bmc", false},
+	}
+	for _, tt := range tests {
+		if got := FieldsOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("FieldsOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMetadata_ManagedFields(t *testing.T) {
+	m := Metadata{ManagedFields: []ManagedFieldsEntry{
+		{Manager: "rack", Operation: ManagedFieldsApply, Fields: []string{"spec.rack"}},
+		{Manager: "bmc", Operation: ManagedFieldsApply, Fields: []string{"spec.bmc.address"}},
+		{Manager: "cli", Operation: ManagedFieldsUpdate, Fields: []string{"spec.bmc.user", "spec.rack"}},
+	}}
+
+	if got := m.FieldManagers("spec.bmc"); !reflect.DeepEqual(got, []string{"bmc", "cli"}) {
+		t.Errorf("unexpected managers of spec.bmc: %v", got)
+	}
+	if entry := m.GetManagedFields("rack", ManagedFieldsApply); entry == nil || entry.Fields[0] != "spec.rack" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if entry := m.GetManagedFields("rack", ManagedFieldsUpdate); entry != nil {
+		t.Errorf("expected no update entry, got %v", entry)
+	}
+
+	clone := m.Clone()
+	clone.ManagedFields[0].Fields[0] = "spec.changed"
+	if m.ManagedFields[0].Fields[0] != "spec.rack" {
+		t.Error("modifying the clone changed the original")
+	}
+}
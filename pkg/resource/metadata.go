@@ -20,8 +20,13 @@ import "time"
 // Fields:
 //   - Name: Human-readable name, unique within a namespace/scope
 //   - UID: Globally unique identifier, typically generated using GenerateUID()
+//   - Namespace: Tenant the resource belongs to when namespaces are enabled
+//     (see package namespace); empty for the default namespace
 //   - Labels: Key-value pairs for selection and organization
 //   - Annotations: Key-value pairs for arbitrary metadata
+//   - Finalizers: Keys of controllers with pending cleanup; stored, not enforced on delete
+//   - OwnerReferences: Resources this resource depends on (see SetOwnerReference)
+//   - ManagedFields: Fields owned by each field manager (see package apply)
 //   - CreatedAt: Resource creation timestamp
 //   - UpdatedAt: Last modification timestamp
 //
@@ -36,12 +41,16 @@ import "time"
 //	resource.SetAnnotation("deployment.notes", "Deployed during maintenance window")
 //	resource.SetAnnotation("contact.email", "ops@example.com")
 type Metadata struct {
-	Name        string            `json:"name" yaml:"name"`
-	UID         string            `json:"uid" yaml:"uid"`
-	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
-	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	CreatedAt   time.Time         `json:"createdAt" yaml:"createdAt"`
-	UpdatedAt   time.Time         `json:"updatedAt" yaml:"updatedAt"`
+	Name            string               `json:"name" yaml:"name"`
+	UID             string               `json:"uid" yaml:"uid"`
+	Namespace       string               `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Labels          map[string]string    `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations     map[string]string    `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Finalizers      []string             `json:"finalizers,omitempty" yaml:"finalizers,omitempty"`
+	OwnerReferences []OwnerReference     `json:"ownerReferences,omitempty" yaml:"ownerReferences,omitempty"`
+	ManagedFields   []ManagedFieldsEntry `json:"managedFields,omitempty" yaml:"managedFields,omitempty"`
+	CreatedAt       time.Time            `json:"createdAt" yaml:"createdAt"`
+	UpdatedAt       time.Time            `json:"updatedAt" yaml:"updatedAt"`
 }
 
 // Metadata helper methods
@@ -91,7 +100,7 @@ func (m *Metadata) Initialize(name, uid string) {
 // Clone creates a deep copy of metadata.
 //
 // Returns a new Metadata instance with all fields copied. The labels
-// and annotations maps are also deep-copied, so modifications to the
+// and annotations maps, finalizers, owner references and managed fields are also deep-copied, so modifications to the
 // clone will not affect the original.
 //
 // This is useful when you need to create derived resources or when
@@ -123,5 +132,24 @@ func (m *Metadata) Clone() *Metadata {
 		}
 	}
 
+	if m.Finalizers != nil {
+		clone.Finalizers = make([]string, len(m.Finalizers))
+		copy(clone.Finalizers, m.Finalizers)
+	}
+
+	if m.OwnerReferences != nil {
+		clone.OwnerReferences = make([]OwnerReference, len(m.OwnerReferences))
+		for i, ref := range m.OwnerReferences {
+			clone.OwnerReferences[i] = ref.Clone()
+		}
+	}
+
+	if m.ManagedFields != nil {
+		clone.ManagedFields = make([]ManagedFieldsEntry, len(m.ManagedFields))
+		for i, entry := range m.ManagedFields {
+			clone.ManagedFields[i] = entry.Clone()
+		}
+	}
+
 	return clone
 }
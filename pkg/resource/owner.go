@@ -0,0 +1,206 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import "fmt"
+
+// OwnerReference identifies a resource that owns (is depended upon by) another.
+//
+// Owner references are stored in the metadata of the dependent and use the
+// Kubernetes field names:
+//   - A resource may have many owners, but at most one with Controller=true.
+//     The controller owner is the resource whose reconciler manages this one.
+//   - BlockOwnerDeletion is recorded for clients that honor it; Fabrica
+//     doesn't block the deletion of owners.
+//
+// Fabrica has no garbage collector: deleting an owner leaves its dependents
+// in place. Reconcilers watching the dependents with reconcile.EnqueueOwners
+// can remove the dependents of deleted owners themselves.
+//
+// Fields:
+//   - APIVersion: API version of the owner
+//   - Kind: Kind of the owner (e.g., "Rack")
+//   - Name: Name of the owner
+//   - UID: UID of the owner; this is the identity used for matching
+//   - Controller: True if the owner is the managing controller
+//   - BlockOwnerDeletion: Kubernetes' foreground deletion flag, stored but not enforced
+type OwnerReference struct {
+	APIVersion         string `json:"apiVersion" yaml:"apiVersion"`
+	Kind               string `json:"kind" yaml:"kind"`
+	Name               string `json:"name" yaml:"name"`
+	UID                string `json:"uid" yaml:"uid"`
+	Controller         *bool  `json:"controller,omitempty" yaml:"controller,omitempty"`
+	BlockOwnerDeletion *bool  `json:"blockOwnerDeletion,omitempty" yaml:"blockOwnerDeletion,omitempty"`
+}
+
+// IsController reports whether the reference marks the managing controller.
+func (o OwnerReference) IsController() bool {
+	return o.Controller != nil && *o.Controller
+}
+
+// BlocksOwnerDeletion reports whether BlockOwnerDeletion is set.
+func (o OwnerReference) BlocksOwnerDeletion() bool {
+	return o.BlockOwnerDeletion != nil && *o.BlockOwnerDeletion
+}
+
+// Clone creates a deep copy of the owner reference.
+func (o OwnerReference) Clone() OwnerReference {
+	clone := o
+	if o.Controller != nil {
+		v := *o.Controller
+		clone.Controller = &v
+	}
+	if o.BlockOwnerDeletion != nil {
+		v := *o.BlockOwnerDeletion
+		clone.BlockOwnerDeletion = &v
+	}
+	return clone
+}
+
+// NewOwnerReference builds an owner reference pointing at the given resource.
+//
+// Parameters:
+//   - owner: The owning resource
+//   - controller: Whether the owner is the managing controller
+//   - blockOwnerDeletion: Value of BlockOwnerDeletion, stored but not enforced
+//
+// Example:
+//
+//	ref := resource.NewOwnerReference(&rack.Resource, true, true)
+//	if err := node.SetOwnerReference(ref); err != nil {
+//	    return err
+//	}
+func NewOwnerReference(owner *Resource, controller, blockOwnerDeletion bool) OwnerReference {
+	return OwnerReference{
+		APIVersion:         owner.APIVersion,
+		Kind:               owner.Kind,
+		Name:               owner.Metadata.Name,
+		UID:                owner.Metadata.UID,
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
+// SetOwnerReference adds or updates an owner reference.
+//
+// References are matched by UID: if a reference with the same UID already
+// exists it is replaced, otherwise the reference is appended. Setting a
+// second controller reference (Controller=true with a different UID) is
+// rejected, since a resource can only be managed by one controller.
+//
+// Returns an error if the reference has no UID or would add a second controller.
+func (m *Metadata) SetOwnerReference(ref OwnerReference) error {
+	if ref.UID == "" {
+		return fmt.Errorf("owner reference must have a UID")
+	}
+
+	if ref.IsController() {
+		if existing := m.GetControllerOf(); existing != nil && existing.UID != ref.UID {
+			return fmt.Errorf("resource is already controlled by %s %s (%s)",
+				existing.Kind, existing.Name, existing.UID)
+		}
+	}
+
+	for i := range m.OwnerReferences {
+		if m.OwnerReferences[i].UID == ref.UID {
+			m.OwnerReferences[i] = ref.Clone()
+			return nil
+		}
+	}
+
+	m.OwnerReferences = append(m.OwnerReferences, ref.Clone())
+	return nil
+}
+
+// GetOwnerReferences returns a copy of the owner references.
+//
+// Modifying the returned slice won't affect the metadata. Returns nil if
+// the resource has no owners.
+func (m *Metadata) GetOwnerReferences() []OwnerReference {
+	if m.OwnerReferences == nil {
+		return nil
+	}
+	result := make([]OwnerReference, len(m.OwnerReferences))
+	for i, ref := range m.OwnerReferences {
+		result[i] = ref.Clone()
+	}
+	return result
+}
+
+// RemoveOwnerReference removes the owner reference with the given UID.
+//
+// Returns true if a reference was removed.
+func (m *Metadata) RemoveOwnerReference(ownerUID string) bool {
+	for i, ref := range m.OwnerReferences {
+		if ref.UID == ownerUID {
+			m.OwnerReferences = append(m.OwnerReferences[:i], m.OwnerReferences[i+1:]...)
+			if len(m.OwnerReferences) == 0 {
+				m.OwnerReferences = nil
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// IsOwnedBy checks if the metadata has an owner reference with the given UID.
+func (m *Metadata) IsOwnedBy(ownerUID string) bool {
+	for _, ref := range m.OwnerReferences {
+		if ref.UID == ownerUID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetControllerOf returns the controller owner reference, or nil if none is set.
+func (m *Metadata) GetControllerOf() *OwnerReference {
+	for _, ref := range m.OwnerReferences {
+		if ref.IsController() {
+			c := ref.Clone()
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetOwnerReference adds or updates an owner reference on the resource.
+//
+// This is a convenience method for resource.Metadata.SetOwnerReference.
+//
+// Example:
+//
+//	err := node.SetOwnerReference(resource.NewOwnerReference(&rack.Resource, true, false))
+func (r *Resource) SetOwnerReference(ref OwnerReference) error {
+	return r.Metadata.SetOwnerReference(ref)
+}
+
+// GetOwnerReferences returns a copy of the resource's owner references.
+func (r *Resource) GetOwnerReferences() []OwnerReference {
+	return r.Metadata.GetOwnerReferences()
+}
+
+// RemoveOwnerReference removes the owner reference with the given UID.
+func (r *Resource) RemoveOwnerReference(ownerUID string) bool {
+	return r.Metadata.RemoveOwnerReference(ownerUID)
+}
+
+// IsOwnedBy checks if the resource is owned by the given resource.
+//
+// Ownership is matched by UID.
+//
+// Example:
+//
+//	if node.IsOwnedBy(&rack.Resource) {
+//	    // node belongs to this rack
+//	}
+func (r *Resource) IsOwnedBy(owner *Resource) bool {
+	return r.Metadata.IsOwnedBy(owner.Metadata.UID)
+}
+
+// GetControllerOf returns the resource's controller owner reference, or nil.
+func (r *Resource) GetControllerOf() *OwnerReference {
+	return r.Metadata.GetControllerOf()
+}
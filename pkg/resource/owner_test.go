@@ -0,0 +1,77 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import "testing"
+
+func newOwner(kind, name, uid string) *Resource {
+	return &Resource{APIVersion: "v1", Kind: kind, Metadata: Metadata{Name: name, UID: uid}}
+}
+
+func TestOwnerReferences_SetAndIsOwnedBy(t *testing.T) {
+	rack := newOwner("Rack", "rack-1", "rck-00000001")
+	node := &Resource{}
+
+	if node.IsOwnedBy(rack) {
+		t.Fatal("Expected node to have no owners")
+	}
+
+	if err := node.SetOwnerReference(NewOwnerReference(rack, true, true)); err != nil {
+		t.Fatalf("SetOwnerReference failed: %v", err)
+	}
+	if !node.IsOwnedBy(rack) {
+		t.Error("Expected node to be owned by rack")
+	}
+
+	ctrl := node.GetControllerOf()
+	if ctrl == nil || ctrl.UID != rack.GetUID() || !ctrl.BlocksOwnerDeletion() {
+		t.Errorf("Unexpected controller reference: %+v", ctrl)
+	}
+
+	// Updating the same owner replaces instead of appending
+	if err := node.SetOwnerReference(NewOwnerReference(rack, true, false)); err != nil {
+		t.Fatalf("SetOwnerReference update failed: %v", err)
+	}
+	refs := node.GetOwnerReferences()
+	if len(refs) != 1 || refs[0].BlocksOwnerDeletion() {
+		t.Errorf("Expected single updated reference, got %+v", refs)
+	}
+}
+
+func TestOwnerReferences_SingleController(t *testing.T) {
+	node := &Resource{}
+	rack1 := newOwner("Rack", "rack-1", "rck-00000001")
+	rack2 := newOwner("Rack", "rack-2", "rck-00000002")
+
+	if err := node.SetOwnerReference(NewOwnerReference(rack1, true, false)); err != nil {
+		t.Fatalf("SetOwnerReference failed: %v", err)
+	}
+	if err := node.SetOwnerReference(NewOwnerReference(rack2, true, false)); err == nil {
+		t.Error("Expected error when adding a second controller")
+	}
+	if err := node.SetOwnerReference(NewOwnerReference(rack2, false, false)); err != nil {
+		t.Errorf("Expected non-controller owner to be accepted, got %v", err)
+	}
+	if len(node.GetOwnerReferences()) != 2 {
+		t.Errorf("Expected 2 owner references, got %d", len(node.GetOwnerReferences()))
+	}
+}
+
+func TestOwnerReferences_RemoveAndValidation(t *testing.T) {
+	node := &Resource{}
+	if err := node.SetOwnerReference(OwnerReference{Kind: "Rack"}); err == nil {
+		t.Error("Expected error for owner reference without UID")
+	}
+
+	rack := newOwner("Rack", "rack-1", "rck-00000001")
+	_ = node.SetOwnerReference(NewOwnerReference(rack, false, false))
+
+	if !node.RemoveOwnerReference(rack.GetUID()) {
+		t.Error("Expected RemoveOwnerReference to return true")
+	}
+	if node.Metadata.OwnerReferences != nil {
+		t.Errorf("Expected nil owner references, got %+v", node.Metadata.OwnerReferences)
+	}
+}
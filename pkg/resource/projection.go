@@ -0,0 +1,138 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// identityFields are kept by every projection, so clients can tell the
+// projected resources apart
+var identityFields = [][]string{{"apiVersion"}, {"kind"}, {"metadata", "uid"}}
+
+// ParseFields parses a sparse fieldset: comma-separated dotted JSON paths
+// such as "metadata.name,spec.componentType".
+//
+// Parameters:
+//   - input: Field list, usually the fields query parameter
+//
+// Returns:
+//   - []string: The fields; nil for an empty input
+//   - error: If a field is empty or has an empty path segment
+func ParseFields(input string) ([]string, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, part := range strings.Split(input, ",") {
+		field := strings.TrimSpace(part)
+		if field == "" {
+			return nil, fmt.Errorf("invalid fields %q: empty field", input)
+		}
+		for _, segment := range strings.Split(field, ".") {
+			if segment == "" {
+				return nil, fmt.Errorf("invalid field %q", field)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// Project reduces the JSON form of a resource, or of a list of resources,
+// to the given fields.
+//
+// A field selects the value at its dotted path with everything below it:
+// "spec" keeps the whole spec, "spec.bmc.address" only that value. Paths
+// don't descend into arrays. Fields missing from a resource are left out.
+// apiVersion, kind and metadata.uid are always kept.
+//
+// Parameters:
+//   - obj: A resource, a slice of resources, or either already decoded from JSON
+//   - fields: Dotted JSON paths (see ParseFields)
+//
+// Returns:
+//   - interface{}: A map for a resource, a slice of maps for a list
+//   - error: If obj can't be converted to JSON, or isn't an object or array
+//
+// Example:
+//
+//	projected, err := resource.Project(devices, []string{"metadata.name", "spec.componentType"})
+//	// [{"apiVersion":"v1","kind":"Device","metadata":{"name":"node1","uid":"dev-1a2b"},"spec":{"componentType":"Node"}}]
+func Project(obj interface{}, fields []string) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode resource: %w", err)
+	}
+
+	paths := make([][]string, 0, len(fields)+len(identityFields))
+	paths = append(paths, identityFields...)
+	for _, field := range fields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		return projectDocument(v, paths), nil
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot project list item of type %T", item)
+			}
+			items[i] = projectDocument(m, paths)
+		}
+		return items, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot project a value of type %T", doc)
+}
+
+// projectDocument copies the values at paths from doc into a new document
+func projectDocument(doc map[string]interface{}, paths [][]string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, path := range paths {
+		value, ok := lookupPath(doc, path)
+		if !ok {
+			continue
+		}
+		dst := result
+		for _, part := range path[:len(path)-1] {
+			// A shorter path may already have copied the whole parent, in
+			// which case the value is already in place
+			child, ok := dst[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				dst[part] = child
+			}
+			dst = child
+		}
+		dst[path[len(path)-1]] = value
+	}
+	return result
+}
+
+// lookupPath returns the value at a path of a decoded JSON document
+func lookupPath(doc map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,93 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package resource
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type projectedNode struct {
+	Resource
+	Spec struct {
+		Type string `json:"type"`
+		BMC  struct {
+			Address string `json:"address"`
+			User    string `json:"user"`
+		} `json:"bmc"`
+		Tags []string `json:"tags"`
+	} `json:"spec"`
+	Status struct {
+		Phase string `json:"phase"`
+	} `json:"status"`
+}
+
+func newProjectedNode(name string) *projectedNode {
+	n := &projectedNode{Resource: Resource{APIVersion: "v1", Kind: "Node", Metadata: Metadata{Name: name, UID: "nod-" + name}}}
+	n.Spec.Type = "compute"
+	n.Spec.BMC.Address = "10.0.0.1"
+	n.Spec.BMC.User = "root"
+	n.Spec.Tags = []string{"a"}
+	n.Status.Phase = "Ready"
+	return n
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields(" metadata.name, spec.type ")
+	if err != nil || len(fields) != 2 || fields[0] != "metadata.name" || fields[1] != "spec.type" {
+		t.Errorf("unexpected fields %v %v", fields, err)
+	}
+	if fields, err := ParseFields(""); fields != nil || err != nil {
+		t.Errorf("expected no fields, got %v %v", fields, err)
+	}
+	for _, input := range []string{"spec,", "spec..type", ".spec"} {
+		if _, err := ParseFields(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{[]string{"metadata.name", "spec.type"},
+			`{"apiVersion":"v1","kind":"Node","metadata":{"name":"n1","uid":"nod-n1"},"spec":{"type":"compute"}}`},
+		{[]string{"spec.bmc.address", "spec.tags", "status.missing"},
+			`{"apiVersion":"v1","kind":"Node","metadata":{"uid":"nod-n1"},"spec":{"bmc":{"address":"10.0.0.1"},"tags":["a"]}}`},
+		{[]string{"spec.bmc", "spec.bmc.user"},
+			`{"apiVersion":"v1","kind":"Node","metadata":{"uid":"nod-n1"},"spec":{"bmc":{"address":"10.0.0.1","user":"root"}}}`},
+		{[]string{"spec.tags.0", "nothing"},
+			`{"apiVersion":"v1","kind":"Node","metadata":{"uid":"nod-n1"}}`},
+	}
+	for _, tt := range tests {
+		projected, err := Project(newProjectedNode("n1"), tt.fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _ := json.Marshal(projected)
+		if string(got) != tt.want {
+			t.Errorf("%v:\n got %s\nwant %s", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestProject_List(t *testing.T) {
+	projected, err := Project([]*projectedNode{newProjectedNode("n1"), newProjectedNode("n2")}, []string{"status"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(projected)
+	want := `[{"apiVersion":"v1","kind":"Node","metadata":{"uid":"nod-n1"},"status":{"phase":"Ready"}},` +
+		`{"apiVersion":"v1","kind":"Node","metadata":{"uid":"nod-n2"},"status":{"phase":"Ready"}}]`
+	if string(got) != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	if _, err := Project("text", []string{"spec"}); err == nil {
+		t.Error("expected an error for a value that isn't a resource")
+	}
+}
@@ -492,7 +492,10 @@ func GenerateUIDWithLength(prefix string, hexLength int) (string, error) {
 // GenerateUIDForResource creates a UID for a specific resource type using registered prefixes.
 //
 // This is a convenience function that looks up the registered prefix for a resource
-// type and generates an appropriate UID.
+// type and generates an appropriate UID with the strategy assigned to the kind
+// (see SetIDStrategy). Use GenerateUIDForNamedResource for kinds whose
+// strategy derives UIDs from names; without a name, kinds using NameIDs get
+// PrefixIDs UIDs.
 //
 // Parameters:
 //   - resourceKind: The Kind field of the resource (e.g., "Device", "Asset", "Sensor")
@@ -506,15 +509,15 @@ func GenerateUIDWithLength(prefix string, hexLength int) (string, error) {
 //	uid, err := GenerateUIDForResource("Device")
 //	// uid might be "dev-1a2b3c4d"
 func GenerateUIDForResource(resourceKind string) (string, error) {
-	resourcePrefixesMutex.RLock()
-	prefix, exists := resourcePrefixes[resourceKind]
-	resourcePrefixesMutex.RUnlock()
-
-	if !exists {
-		return "", fmt.Errorf("resource kind '%s' is not registered - call RegisterResourcePrefix() first", resourceKind)
+	prefix, strategy, err := idStrategyFor(resourceKind)
+	if err != nil {
+		return "", err
 	}
-
-	return GenerateUID(prefix)
+	if _, named := strategy.(nameIDs); named {
+		// There is no name to use as the UID
+		strategy = PrefixIDs
+	}
+	return strategy.NewUID(prefix, "")
 }
 
 // ParseUID extracts the prefix and random part from a structured UID.
@@ -0,0 +1,92 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrLimiterWait is returned by Limiter.Wait when the context's deadline
+// comes before the request could be sent
+var ErrLimiterWait = errors.New("rate limit wait exceeds the context deadline")
+
+// Limiter is a token bucket pacing requests: it holds up to burst tokens,
+// refilled at rate per second, and each request takes one. A Limiter is
+// safe for concurrent use.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter allowing rate requests per second on
+// average, and bursts of up to burst requests (at least 1)
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// Wait blocks until a request may be sent, or returns an error when ctx is
+// done first or its deadline is too close to wait for the next token
+func (l *Limiter) Wait(ctx context.Context) error {
+	wait, err := l.reserve(ctx)
+	if err != nil || wait <= 0 {
+		return err
+	}
+	if err := sleep(ctx, wait); err != nil {
+		l.cancel()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token, returning how long to wait before it is available
+func (l *Limiter) reserve(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+		return 0, fmt.Errorf("%w (%s)", ErrLimiterWait, wait)
+	}
+	l.tokens--
+	return wait, nil
+}
+
+// cancel returns the token of a request that gave up waiting
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package retry makes HTTP clients ride out transient failures: a
+// Transport retries idempotent requests with exponential backoff and
+// jitter, honoring the Retry-After header of the server, and paces
+// requests with a client-side rate Limiter.
+//
+//	httpClient := &http.Client{Transport: &retry.Transport{
+//	    Policy:  retry.Policy{MaxAttempts: 5},
+//	    Limiter: retry.NewLimiter(20, 5), // 20 requests/s, bursts of 5
+//	}}
+//
+// Requests are retried when they fail to reach the server or are answered
+// with 429 Too Many Requests, 502 Bad Gateway, 503 Service Unavailable or
+// 504 Gateway Timeout. Only idempotent methods (GET, HEAD, OPTIONS, TRACE,
+// PUT and DELETE) are retried: a POST or PATCH that timed out may have been
+// applied, and sending it again could apply it twice.
+//
+// The waits and all attempts of a request count against its context and
+// the Timeout of the http.Client, so a deadline bounds the whole call.
+package retry
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults of Policy
+const (
+	DefaultMaxAttempts   = 3
+	DefaultMinBackoff    = 100 * time.Millisecond
+	DefaultMaxBackoff    = 10 * time.Second
+	DefaultMaxRetryAfter = time.Minute
+)
+
+// Policy configures the retries of a Transport. The zero Policy retries
+// with the defaults.
+type Policy struct {
+	// MaxAttempts is the number of times a request is sent, including
+	// the first (default: 3). 1 disables retries.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry, doubled for each
+	// next retry (default: 100ms)
+	MinBackoff time.Duration
+
+	// MaxBackoff is the longest wait between attempts (default: 10s)
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter is the longest Retry-After the server may ask for; a
+	// response asking for a longer wait is returned instead of retried
+	// (default: 1m)
+	MaxRetryAfter time.Duration
+}
+
+// withDefaults fills in the unset fields of p
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = DefaultMinBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		p.MaxBackoff = p.MinBackoff
+	}
+	if p.MaxRetryAfter <= 0 {
+		p.MaxRetryAfter = DefaultMaxRetryAfter
+	}
+	return p
+}
+
+// Backoff returns the wait before the given retry (1 for the first): the
+// exponential backoff capped at MaxBackoff, of which the second half is
+// random so that clients failing together don't retry together.
+func (p Policy) Backoff(retry int) time.Duration {
+	p = p.withDefaults()
+	backoff := p.MinBackoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// Transport is an http.RoundTripper retrying requests according to Policy
+// and pacing them with Limiter.
+type Transport struct {
+	// Base sends the requests (default: http.DefaultTransport)
+	Base http.RoundTripper
+
+	// Policy configures the retries
+	Policy Policy
+
+	// Limiter, if set, paces the requests, retries included. Share one
+	// Limiter between Transports to pace them together.
+	Limiter *Limiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	policy := t.Policy.withDefaults()
+	ctx := req.Context()
+
+	// Requests whose body can't be read again are sent once
+	retryable := Idempotent(req.Method) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		if t.Limiter != nil {
+			if err := t.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := base.RoundTrip(attemptReq)
+		if !retryable || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			wait = policy.Backoff(attempt)
+		case RetryableStatus(resp.StatusCode):
+			wait = policy.Backoff(attempt)
+			if after, ok := RetryAfter(resp.Header, time.Now()); ok {
+				if after > policy.MaxRetryAfter {
+					return resp, nil
+				}
+				wait = after
+			}
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		attemptReq = req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+	}
+}
+
+// Idempotent reports whether requests with the given method may be sent
+// again without changing their effect
+func Idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RetryableStatus reports whether a response status signals a transient
+// failure worth retrying
+func RetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// RetryAfter returns the wait asked for by the Retry-After header of a
+// response, given in seconds or as an HTTP date
+func RetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
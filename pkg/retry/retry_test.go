@@ -0,0 +1,196 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then answers
+// 200 echoing the request body
+func flakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func fastClient(policy Policy) *http.Client {
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return &http.Client{Transport: &Transport{Policy: policy}}
+}
+
+func TestTransportRetriesIdempotentRequests(t *testing.T) {
+	srv, calls := flakyServer(t, 2, http.StatusServiceUnavailable, "")
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, bytes.NewBufferString("spec"))
+	resp, err := fastClient(Policy{}).Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "spec" {
+		t.Errorf("Expected 200 with the body resent, got %d %q", resp.StatusCode, body)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestTransportGivesUp(t *testing.T) {
+	srv, calls := flakyServer(t, 10, http.StatusBadGateway, "")
+
+	resp, err := fastClient(Policy{MaxAttempts: 4}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected the last 502 to be returned, got %d", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("Expected 4 attempts, got %d", got)
+	}
+}
+
+func TestTransportSkipsNonIdempotentAndPermanentFailures(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusServiceUnavailable, "")
+	resp, err := fastClient(Policy{}).Post(srv.URL, "text/plain", bytes.NewBufferString("x"))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("Expected a POST to be sent once, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+
+	srv, calls = flakyServer(t, 1, http.StatusNotFound, "")
+	resp, err = fastClient(Policy{}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Errorf("Expected a 404 not to be retried, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestTransportHonorsRetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, "1")
+	start := time.Now()
+	resp, err := fastClient(Policy{}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("Expected 200 after 2 attempts, got %d after %d", resp.StatusCode, calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected a wait of Retry-After (1s), waited %s", elapsed)
+	}
+
+	// Waits longer than MaxRetryAfter aren't retried
+	srv, calls = flakyServer(t, 1, http.StatusTooManyRequests, "3600")
+	resp, err = fastClient(Policy{}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Errorf("Expected the 429 to be returned, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestTransportStopsWithContext(t *testing.T) {
+	srv, _ := flakyServer(t, 10, http.StatusServiceUnavailable, "30")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	if _, err := fastClient(Policy{}).Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to end the retries, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retry, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		for i := 0; i < 20; i++ {
+			if got := p.Backoff(retry); got < max/2 || got > max {
+				t.Errorf("Backoff(%d) = %s, expected between %s and %s", retry, got, max/2, max)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		got, ok := RetryAfter(header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RetryAfter(%q) = %s, %v; expected %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(20, 2)
+	ctx := context.Background()
+
+	// The burst passes at once, then requests are paced at 20/s
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected 2 requests beyond the burst to take ~100ms, took %s", elapsed)
+	}
+
+	// Deadlines too close to wait for are reported at once
+	deadline, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if err := l.Wait(deadline); !errors.Is(err, ErrLimiterWait) {
+		t.Errorf("Expected ErrLimiterWait, got %v", err)
+	}
+}
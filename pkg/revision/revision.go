@@ -0,0 +1,258 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package revision keeps a bounded history of resource specs for rollback.
+//
+// Every time a resource's spec changes, generated handlers record a Revision
+// containing the spec and identifying metadata. Only the most recent N
+// revisions are kept per resource. A resource can later be rolled back by
+// copying the spec of an earlier revision onto the live resource.
+//
+// Revisions are stored through a storage.StorageBackend under the kind
+// "<Kind>Revisions", one history document per resource UID, so the history
+// lives next to the resource data. When no backend is supplied the history is
+// kept in memory.
+//
+// Usage:
+//
+//	store := revision.NewStore(backend, 10)
+//	num, err := store.Record(ctx, "Device", device.Metadata, device.Spec)
+//
+//	revs, err := store.List(ctx, "Device", uid)
+//	rev, err := store.Get(ctx, "Device", uid, 3)
+//	prev, err := store.Previous(ctx, "Device", uid) // before the last change
+//	err = json.Unmarshal(rev.Spec, &device.Spec)
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// DefaultLimit is the number of revisions kept per resource when no limit is configured.
+const DefaultLimit = 10
+
+// ErrRevisionNotFound is returned when a requested revision doesn't exist.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision is a recorded spec of a resource at a point in time.
+type Revision struct {
+	// Number increases monotonically per resource, starting at 1
+	Number int64 `json:"revision" yaml:"revision"`
+
+	// CreatedAt is when the revision was recorded
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+
+	UID         string            `json:"uid" yaml:"uid"`
+	Name        string            `json:"name" yaml:"name"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+
+	// Spec is the JSON-encoded spec at this revision
+	Spec json.RawMessage `json:"spec" yaml:"spec"`
+}
+
+// history is the stored document holding a resource's revisions.
+type history struct {
+	UID       string     `json:"uid"`
+	Next      int64      `json:"next"`
+	Revisions []Revision `json:"revisions"`
+}
+
+// Store records and retrieves revision histories.
+type Store struct {
+	mu      sync.Mutex
+	backend storage.StorageBackend
+	limit   int
+	memory  map[string][]byte // used when backend is nil, keyed by kind/uid
+}
+
+// NewStore creates a revision store.
+//
+// Parameters:
+//   - backend: Storage backend for histories; nil keeps histories in memory
+//   - limit: Maximum revisions kept per resource; values <= 0 use DefaultLimit
+//
+// Returns:
+//   - *Store: A ready-to-use revision store
+func NewStore(backend storage.StorageBackend, limit int) *Store {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Store{
+		backend: backend,
+		limit:   limit,
+		memory:  make(map[string][]byte),
+	}
+}
+
+// Limit returns the maximum number of revisions kept per resource.
+func (s *Store) Limit() int {
+	return s.limit
+}
+
+// Record stores a new revision of a resource's spec.
+//
+// Older revisions beyond the store limit are discarded.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - kind: Resource kind (e.g., "Device")
+//   - meta: Resource metadata; UID, name, labels and annotations are recorded
+//   - spec: The resource spec, which must be JSON-serializable
+//
+// Returns:
+//   - int64: The number assigned to the new revision
+//   - error: Any error that occurred while recording
+func (s *Store) Record(ctx context.Context, kind string, meta resource.Metadata, spec interface{}) (int64, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.load(ctx, kind, meta.UID)
+	if err != nil {
+		return 0, err
+	}
+
+	h.Next++
+	clone := meta.Clone()
+	h.Revisions = append(h.Revisions, Revision{
+		Number:      h.Next,
+		CreatedAt:   time.Now().UTC(),
+		UID:         meta.UID,
+		Name:        meta.Name,
+		Labels:      clone.Labels,
+		Annotations: clone.Annotations,
+		Spec:        specJSON,
+	})
+	if len(h.Revisions) > s.limit {
+		h.Revisions = h.Revisions[len(h.Revisions)-s.limit:]
+	}
+
+	if err := s.save(ctx, kind, h); err != nil {
+		return 0, err
+	}
+	return h.Next, nil
+}
+
+// List returns the stored revisions of a resource, oldest first.
+//
+// Returns an empty slice if the resource has no recorded revisions.
+func (s *Store) List(ctx context.Context, kind, uid string) ([]Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, err := s.load(ctx, kind, uid)
+	if err != nil {
+		return nil, err
+	}
+	return h.Revisions, nil
+}
+
+// Get returns a specific revision of a resource.
+//
+// Returns ErrRevisionNotFound if the revision was never recorded or has been pruned.
+func (s *Store) Get(ctx context.Context, kind, uid string, number int64) (*Revision, error) {
+	revisions, err := s.List(ctx, kind, uid)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if revisions[i].Number == number {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s revision %d", ErrRevisionNotFound, kind, uid, number)
+}
+
+// Previous returns the revision recorded before the latest one, the spec a
+// resource had before its last change.
+//
+// Returns ErrRevisionNotFound if fewer than two revisions are stored.
+func (s *Store) Previous(ctx context.Context, kind, uid string) (*Revision, error) {
+	revisions, err := s.List(ctx, kind, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) < 2 {
+		return nil, fmt.Errorf("%w: %s %s has no revision before the latest", ErrRevisionNotFound, kind, uid)
+	}
+	return &revisions[len(revisions)-2], nil
+}
+
+// Delete removes the revision history of a resource.
+//
+// Safe to call for resources without history.
+func (s *Store) Delete(ctx context.Context, kind, uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.backend == nil {
+		delete(s.memory, memoryKey(kind, uid))
+		return nil
+	}
+	if err := s.backend.Delete(ctx, historyKind(kind), uid); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to delete revision history: %w", err)
+	}
+	return nil
+}
+
+// load reads a history document. Callers hold s.mu.
+func (s *Store) load(ctx context.Context, kind, uid string) (*history, error) {
+	var data []byte
+	if s.backend == nil {
+		data = s.memory[memoryKey(kind, uid)]
+	} else {
+		raw, err := s.backend.Load(ctx, historyKind(kind), uid)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load revision history: %w", err)
+		}
+		data = raw
+	}
+
+	h := &history{UID: uid, Revisions: []Revision{}}
+	if len(data) == 0 {
+		return h, nil
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision history: %w", err)
+	}
+	return h, nil
+}
+
+// save writes a history document. Callers hold s.mu.
+func (s *Store) save(ctx context.Context, kind string, h *history) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision history: %w", err)
+	}
+	if s.backend == nil {
+		s.memory[memoryKey(kind, h.UID)] = data
+		return nil
+	}
+	if err := s.backend.Save(ctx, historyKind(kind), h.UID, data); err != nil {
+		return fmt.Errorf("failed to save revision history: %w", err)
+	}
+	return nil
+}
+
+// historyKind returns the storage kind used for a resource kind's histories.
+func historyKind(kind string) string {
+	return kind + "Revisions"
+}
+
+func memoryKey(kind, uid string) string {
+	return kind + "/" + uid
+}
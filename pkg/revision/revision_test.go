@@ -0,0 +1,123 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+type testSpec struct {
+	Location string `json:"location"`
+}
+
+func testMetadata() resource.Metadata {
+	var meta resource.Metadata
+	meta.Initialize("device-1", "dev-12345678")
+	return meta
+}
+
+func TestStore_RecordAndGet(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(nil, 0)
+	meta := testMetadata()
+
+	first, err := s.Record(ctx, "Device", meta, testSpec{Location: "rack-1"})
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	second, _ := s.Record(ctx, "Device", meta, testSpec{Location: "rack-2"})
+	if first != 1 || second != 2 {
+		t.Fatalf("Expected revisions 1 and 2, got %d and %d", first, second)
+	}
+
+	rev, err := s.Get(ctx, "Device", meta.UID, 1)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	var spec testSpec
+	if err := json.Unmarshal(rev.Spec, &spec); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if spec.Location != "rack-1" || rev.Name != "device-1" {
+		t.Errorf("Unexpected revision contents: %+v", rev)
+	}
+}
+
+func TestStore_Limit(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(nil, 2)
+	meta := testMetadata()
+
+	for i := 0; i < 4; i++ {
+		if _, err := s.Record(ctx, "Device", meta, testSpec{}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	revs, _ := s.List(ctx, "Device", meta.UID)
+	if len(revs) != 2 || revs[0].Number != 3 || revs[1].Number != 4 {
+		t.Fatalf("Expected revisions 3 and 4 to be kept, got %+v", revs)
+	}
+	if _, err := s.Get(ctx, "Device", meta.UID, 1); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("Expected ErrRevisionNotFound for pruned revision, got %v", err)
+	}
+}
+
+func TestStore_Previous(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore(nil, 0)
+	meta := testMetadata()
+
+	s.Record(ctx, "Device", meta, testSpec{Location: "rack-1"})
+	if _, err := s.Previous(ctx, "Device", meta.UID); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("Expected ErrRevisionNotFound with one revision, got %v", err)
+	}
+
+	s.Record(ctx, "Device", meta, testSpec{Location: "rack-2"})
+	s.Record(ctx, "Device", meta, testSpec{Location: "rack-3"})
+	rev, err := s.Previous(ctx, "Device", meta.UID)
+	if err != nil {
+		t.Fatalf("Previous failed: %v", err)
+	}
+	if rev.Number != 2 {
+		t.Errorf("Expected revision 2, got %d", rev.Number)
+	}
+}
+
+func TestStore_Persistence(t *testing.T) {
+	ctx := context.Background()
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend failed: %v", err)
+	}
+	meta := testMetadata()
+
+	if _, err := NewStore(backend, 5).Record(ctx, "Device", meta, testSpec{Location: "rack-1"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	reloaded := NewStore(backend, 5)
+	num, err := reloaded.Record(ctx, "Device", meta, testSpec{Location: "rack-2"})
+	if err != nil || num != 2 {
+		t.Fatalf("Expected numbering to continue at 2, got %d (%v)", num, err)
+	}
+
+	if err := reloaded.Delete(ctx, "Device", meta.UID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	revs, _ := reloaded.List(ctx, "Device", meta.UID)
+	if len(revs) != 0 {
+		t.Errorf("Expected empty history after delete, got %d revisions", len(revs))
+	}
+	if err := reloaded.Delete(ctx, "Device", meta.UID); err != nil {
+		t.Errorf("Expected deleting missing history to succeed, got %v", err)
+	}
+}
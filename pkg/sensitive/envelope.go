@@ -0,0 +1,276 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package sensitive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// EnvelopeVersion marks an envelope in stored JSON.
+const EnvelopeVersion = "fabrica.envelope/v1"
+
+// dataKeySize is the size of generated data keys (AES-256).
+const dataKeySize = 32
+
+// maxDataKeyUses is the number of values a data key encrypts before a new
+// one is generated, well below the limit of random GCM nonces.
+const maxDataKeyUses = 1 << 20
+
+// maxCachedDataKeys bounds the unwrapped data keys an Enveloper keeps.
+const maxCachedDataKeys = 1024
+
+// KeyWrapper encrypts and decrypts the data keys of envelope encryption
+// with a key-encryption key. Implement it to keep that key in a KMS: WrapKey
+// and UnwrapKey then call the KMS, which never reveals the key itself.
+//
+// Unwrapped data keys are cached by the Enveloper, so UnwrapKey is called
+// once per data key rather than once per value.
+type KeyWrapper interface {
+	// WrapKey encrypts a data key, returning it with the ID of the
+	// key-encryption key used
+	WrapKey(dataKey []byte) (wrapped []byte, keyID string, err error)
+
+	// UnwrapKey decrypts a data key produced by WrapKey
+	UnwrapKey(wrapped []byte, keyID string) ([]byte, error)
+}
+
+// CipherKeyWrapper wraps data keys with a local AES key.
+type CipherKeyWrapper struct {
+	cipher *Cipher
+}
+
+// NewCipherKeyWrapper creates a KeyWrapper that wraps data keys with c.
+//
+// Example:
+//
+//	c, err := sensitive.CipherFromEnv("FABRICA_ENCRYPTION_KEY")
+//	e := sensitive.NewEnveloper(sensitive.NewCipherKeyWrapper(c))
+func NewCipherKeyWrapper(c *Cipher) *CipherKeyWrapper {
+	return &CipherKeyWrapper{cipher: c}
+}
+
+// WrapKey encrypts a data key with the local key.
+func (w *CipherKeyWrapper) WrapKey(dataKey []byte) ([]byte, string, error) {
+	wrapped, err := w.cipher.seal(dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return wrapped, w.cipher.KeyID(), nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the local key.
+func (w *CipherKeyWrapper) UnwrapKey(wrapped []byte, keyID string) ([]byte, error) {
+	if keyID != "" && keyID != w.cipher.KeyID() {
+		return nil, fmt.Errorf("data key was wrapped with key %s, but the configured key is %s", keyID, w.cipher.KeyID())
+	}
+	return w.cipher.open(wrapped)
+}
+
+// Envelope is an encrypted value as it is stored: the value encrypted with
+// a data key, and the data key encrypted by a KeyWrapper.
+type Envelope struct {
+	Version string `json:"envelope"`
+	KeyID   string `json:"kid,omitempty"`
+	Key     []byte `json:"key"`  // Wrapped data key
+	Data    []byte `json:"data"` // Nonce followed by the ciphertext
+}
+
+// Enveloper encrypts stored JSON with envelope encryption (AES-GCM).
+//
+// Values are encrypted with a data key that is generated by the Enveloper,
+// wrapped by a KeyWrapper and stored with them, so the key-encryption key
+// can live in a KMS and be rotated without re-encrypting data. Data keys are
+// reused for many values and cached once unwrapped.
+//
+// Usage:
+//
+//	e := sensitive.NewEnveloper(wrapper)
+//	data, err := e.SealFields(resourceJSON, "spec", "status")
+//	plain, err := e.Open(data)
+type Enveloper struct {
+	wrapper KeyWrapper
+
+	mu      sync.Mutex
+	current *dataKey
+	keys    map[string]*Cipher // wrapped data key -> data key
+}
+
+// dataKey is the data key new values are encrypted with
+type dataKey struct {
+	cipher  *Cipher
+	wrapped []byte
+	keyID   string
+	uses    int
+}
+
+// NewEnveloper creates an Enveloper whose data keys are wrapped by w.
+func NewEnveloper(w KeyWrapper) *Enveloper {
+	return &Enveloper{wrapper: w, keys: map[string]*Cipher{}}
+}
+
+// Seal encrypts a JSON value, returning the JSON of its Envelope.
+func (e *Enveloper) Seal(plaintext []byte) ([]byte, error) {
+	key, err := e.dataKey()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := key.cipher.seal(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Envelope{
+		Version: EnvelopeVersion,
+		KeyID:   key.keyID,
+		Key:     key.wrapped,
+		Data:    sealed,
+	})
+}
+
+// SealFields encrypts the named top-level fields of a JSON object, leaving
+// the others readable (e.g., metadata that storage indexes). Fields that are
+// missing or null are left as-is.
+func (e *Enveloper) SealFields(doc []byte, fields ...string) ([]byte, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &object); err != nil {
+		return nil, fmt.Errorf("failed to seal fields: %w", err)
+	}
+	for _, field := range fields {
+		value, ok := object[field]
+		if !ok || string(value) == "null" {
+			continue
+		}
+		sealed, err := e.Seal(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field, err)
+		}
+		object[field] = sealed
+	}
+	return json.Marshal(object)
+}
+
+// Open reverses Seal and SealFields: it decrypts data that is an envelope,
+// or the top-level fields of an object that are. Other data is returned
+// unchanged, so existing plaintext data keeps working and is encrypted on
+// its next save.
+func (e *Enveloper) Open(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(EnvelopeVersion)) {
+		return data, nil
+	}
+	if env, ok := parseEnvelope(data); ok {
+		return e.open(env)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return data, nil
+	}
+	opened := false
+	for field, value := range object {
+		env, ok := parseEnvelope(value)
+		if !ok {
+			continue
+		}
+		plaintext, err := e.open(env)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field, err)
+		}
+		object[field] = plaintext
+		opened = true
+	}
+	if !opened {
+		return data, nil
+	}
+	return json.Marshal(object)
+}
+
+// IsEnvelope reports whether data was produced by Seal.
+func IsEnvelope(data []byte) bool {
+	_, ok := parseEnvelope(data)
+	return ok
+}
+
+// parseEnvelope decodes data if it is an envelope
+func parseEnvelope(data []byte) (Envelope, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' || !bytes.Contains(data, []byte(EnvelopeVersion)) {
+		return Envelope{}, false
+	}
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Version != EnvelopeVersion {
+		return Envelope{}, false
+	}
+	return env, true
+}
+
+// open decrypts an envelope
+func (e *Enveloper) open(env Envelope) ([]byte, error) {
+	key, err := e.unwrap(env.Key, env.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	return key.open(env.Data)
+}
+
+// dataKey returns the data key to encrypt a value with, generating a new
+// one when there is none or the current one is used up
+func (e *Enveloper) dataKey() (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.current == nil || e.current.uses >= maxDataKeyUses {
+		raw := make([]byte, dataKeySize)
+		if _, err := io.ReadFull(rand.Reader, raw); err != nil {
+			return nil, fmt.Errorf("failed to generate data key: %w", err)
+		}
+		c, err := NewCipher(raw)
+		if err != nil {
+			return nil, err
+		}
+		wrapped, keyID, err := e.wrapper.WrapKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap data key: %w", err)
+		}
+		e.current = &dataKey{cipher: c, wrapped: wrapped, keyID: keyID}
+		e.cache(wrapped, c)
+	}
+	e.current.uses++
+	return e.current, nil
+}
+
+// unwrap returns the data key of an envelope, from the cache when possible
+func (e *Enveloper) unwrap(wrapped []byte, keyID string) (*Cipher, error) {
+	e.mu.Lock()
+	c, ok := e.keys[string(wrapped)]
+	e.mu.Unlock()
+	if ok {
+		return c, nil
+	}
+
+	raw, err := e.wrapper.UnwrapKey(wrapped, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	c, err = NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.cache(wrapped, c)
+	e.mu.Unlock()
+	return c, nil
+}
+
+// cache remembers an unwrapped data key. The caller holds e.mu.
+func (e *Enveloper) cache(wrapped []byte, c *Cipher) {
+	if len(e.keys) >= maxCachedDataKeys {
+		e.keys = map[string]*Cipher{}
+	}
+	e.keys[string(wrapped)] = c
+}
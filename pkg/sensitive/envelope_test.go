@@ -0,0 +1,128 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package sensitive
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// countingWrapper counts the data keys it unwraps
+type countingWrapper struct {
+	KeyWrapper
+	unwrapped int
+}
+
+func (w *countingWrapper) UnwrapKey(wrapped []byte, keyID string) ([]byte, error) {
+	w.unwrapped++
+	return w.KeyWrapper.UnwrapKey(wrapped, keyID)
+}
+
+func TestEnveloperSealFieldsAndOpen(t *testing.T) {
+	e := NewEnveloper(NewCipherKeyWrapper(testCipher(t)))
+	doc := []byte(`{"kind":"Device","metadata":{"name":"bmc-1"},"spec":{"password":"hunter2"},"status":null}`)
+
+	sealed, err := e.SealFields(doc, "spec", "status")
+	if err != nil {
+		t.Fatalf("SealFields failed: %v", err)
+	}
+	if strings.Contains(string(sealed), "hunter2") {
+		t.Errorf("sealed document contains the plaintext spec: %s", sealed)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(sealed, &object); err != nil {
+		t.Fatalf("sealed document is not a JSON object: %v", err)
+	}
+	if !IsEnvelope(object["spec"]) {
+		t.Errorf("spec = %s, want an envelope", object["spec"])
+	}
+	if string(object["metadata"]) != `{"name":"bmc-1"}` || string(object["status"]) != "null" {
+		t.Errorf("unsealed fields changed: %s", sealed)
+	}
+
+	opened, err := e.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	var got, want map[string]interface{}
+	json.Unmarshal(opened, &got) // nolint:errcheck
+	json.Unmarshal(doc, &want)   // nolint:errcheck
+	if !jsonEqual(got, want) {
+		t.Errorf("Open = %s, want %s", opened, doc)
+	}
+}
+
+func TestEnveloperSealAndOpen(t *testing.T) {
+	e := NewEnveloper(NewCipherKeyWrapper(testCipher(t)))
+	value := []byte(`{"password":"hunter2"}`)
+
+	sealed, err := e.Seal(value)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if !IsEnvelope(sealed) {
+		t.Fatalf("Seal = %s, want an envelope", sealed)
+	}
+	opened, err := e.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if !bytes.Equal(opened, value) {
+		t.Errorf("Open = %s, want %s", opened, value)
+	}
+
+	// Plaintext written before encryption was enabled is returned as-is
+	if opened, err := e.Open(value); err != nil || !bytes.Equal(opened, value) {
+		t.Errorf("Open(plaintext) = %s, %v", opened, err)
+	}
+}
+
+func TestEnveloperCachesDataKeys(t *testing.T) {
+	wrapper := &countingWrapper{KeyWrapper: NewCipherKeyWrapper(testCipher(t))}
+	var sealed [][]byte
+	writer := NewEnveloper(wrapper)
+	for i := 0; i < 3; i++ {
+		data, err := writer.Seal([]byte(`"value"`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sealed = append(sealed, data)
+	}
+
+	// A new process unwraps the shared data key once
+	reader := NewEnveloper(wrapper)
+	for _, data := range sealed {
+		if _, err := reader.Open(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if wrapper.unwrapped != 1 {
+		t.Errorf("data key unwrapped %d times, want 1", wrapper.unwrapped)
+	}
+}
+
+func TestEnveloperWrongKey(t *testing.T) {
+	sealed, err := NewEnveloper(NewCipherKeyWrapper(testCipher(t))).Seal([]byte(`"value"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewCipher(bytes.Repeat([]byte{9}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewEnveloper(NewCipherKeyWrapper(other)).Open(sealed)
+	if err == nil || !strings.Contains(err.Error(), "wrapped with key") {
+		t.Errorf("Open with another key: err = %v, want a key mismatch", err)
+	}
+}
+
+// jsonEqual compares decoded JSON values
+func jsonEqual(a, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return bytes.Equal(x, y)
+}
@@ -0,0 +1,122 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package sensitive
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MaskRule masks the value at a dotted JSON path.
+//
+// Path segments match object keys; "*" matches any key. Arrays are traversed
+// transparently, so "spec.credentials.password" masks the password of every
+// element of spec.credentials.
+type MaskRule struct {
+	// Path is the dotted JSON path to mask (e.g., "spec.bmcPassword", "metadata.annotations.owner")
+	Path string `json:"path" yaml:"path" mapstructure:"path"`
+
+	// Keep is the number of trailing characters of string values left visible
+	Keep int `json:"keep,omitempty" yaml:"keep,omitempty" mapstructure:"keep"`
+}
+
+// Masker produces copies of resources with sensitive values masked.
+//
+// Fields tagged `fabrica:"sensitive"` are always masked; rules mask
+// additional fields, e.g. PII that isn't encrypted in storage.
+type Masker struct {
+	rules []MaskRule
+}
+
+// NewMasker creates a masker with additional masking rules.
+//
+// Example:
+//
+//	m := sensitive.NewMasker([]sensitive.MaskRule{
+//	    {Path: "spec.serialNumber", Keep: 4},
+//	    {Path: "metadata.annotations.owner"},
+//	})
+//	masked, err := m.Mask(devices)
+func NewMasker(rules []MaskRule) *Masker {
+	return &Masker{rules: rules}
+}
+
+// Mask returns a masked copy of v in its decoded JSON form.
+// v is not modified.
+//
+// Returns:
+//   - interface{}: Masked value (maps, slices and scalars as decoded from JSON)
+//   - error: If v can't be converted to JSON
+func (m *Masker) Mask(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value for masking: %w", err)
+	}
+
+	// Redact tagged fields on a typed copy, so struct tags are still available
+	if v != nil && HasSensitiveFields(reflect.TypeOf(v)) {
+		clone := reflect.New(reflect.TypeOf(v))
+		if err := json.Unmarshal(data, clone.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to copy value for masking: %w", err)
+		}
+		Redact(clone.Interface())
+		if data, err = json.Marshal(clone.Interface()); err != nil {
+			return nil, fmt.Errorf("failed to marshal masked value: %w", err)
+		}
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode value for masking: %w", err)
+	}
+	for _, rule := range m.rules {
+		if rule.Path == "" {
+			continue
+		}
+		doc = maskPath(doc, strings.Split(rule.Path, "."), rule.Keep)
+	}
+	return doc, nil
+}
+
+// maskPath masks the values at path within a decoded JSON document.
+func maskPath(node interface{}, path []string, keep int) interface{} {
+	switch n := node.(type) {
+	case []interface{}:
+		for i := range n {
+			n[i] = maskPath(n[i], path, keep)
+		}
+		return n
+	case map[string]interface{}:
+		if len(path) == 0 {
+			return maskValue(n, keep)
+		}
+		for key, child := range n {
+			if path[0] == "*" || path[0] == key {
+				n[key] = maskPath(child, path[1:], keep)
+			}
+		}
+		return n
+	default:
+		if len(path) == 0 {
+			return maskValue(n, keep)
+		}
+		return n
+	}
+}
+
+// maskValue replaces a value, keeping the last keep characters of strings.
+func maskValue(v interface{}, keep int) interface{} {
+	s, ok := v.(string)
+	switch {
+	case v == nil || (ok && s == ""):
+		return v
+	case ok && keep > 0 && keep < len(s):
+		return strings.Repeat("*", len(s)-keep) + s[len(s)-keep:]
+	default:
+		return RedactedValue
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package sensitive encrypts and redacts resource fields marked as sensitive.
+//
+// Fields are marked with a struct tag:
+//
+//	type DeviceSpec struct {
+//	    BMCUser     string `json:"bmcUser"`
+//	    BMCPassword string `json:"bmcPassword" fabrica:"sensitive"`
+//	}
+//
+// Only string and *string fields can be marked. Marked fields inside nested
+// structs, pointers to structs and slices of structs are found as well;
+// fields inside maps are not.
+//
+// Generated storage seals resources before persisting them, so marked fields
+// are stored as "enc:v1:<base64>" values encrypted with AES-GCM, and opens them
+// again on load. Values without the prefix are left as-is when opening, so
+// existing plaintext data keeps working and is encrypted on its next save.
+//
+// An Enveloper encrypts whole stored values instead (envelope encryption):
+// each value is encrypted with a data key that is itself encrypted by a
+// KeyWrapper, such as a local key or a KMS.
+//
+// Usage:
+//
+//	c, err := sensitive.CipherFromEnv("FABRICA_ENCRYPTION_KEY")
+//	data, err := sensitive.Seal(c, device)     // JSON with encrypted fields
+//	err = sensitive.Open(c, data, &loaded)     // JSON back to plaintext fields
+//	sensitive.Redact(devices)                  // replace values with "[REDACTED]"
+package sensitive
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TagName is the struct tag used to mark fields.
+const TagName = "fabrica"
+
+// Prefix marks an encrypted value.
+const Prefix = "enc:v1:"
+
+// RedactedValue replaces sensitive values in redacted output.
+const RedactedValue = "[REDACTED]"
+
+// Cipher encrypts and decrypts field values with AES-GCM.
+type Cipher struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+// NewCipher creates a cipher from a raw AES key.
+//
+// Parameters:
+//   - key: 16, 24 or 32 bytes (AES-128, AES-192 or AES-256)
+//
+// Returns:
+//   - *Cipher: Cipher ready for use
+//   - error: If the key length is invalid
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM cipher: %w", err)
+	}
+	sum := sha256.Sum256(key)
+	return &Cipher{aead: aead, keyID: hex.EncodeToString(sum[:8])}, nil
+}
+
+// KeyID returns a fingerprint of the key, which identifies it without
+// revealing it.
+func (c *Cipher) KeyID() string {
+	return c.keyID
+}
+
+// CipherFromEnv creates a cipher from a base64-encoded key in an environment variable.
+//
+// Example:
+//
+//	export FABRICA_ENCRYPTION_KEY=$(openssl rand -base64 32)
+func CipherFromEnv(name string) (*Cipher, error) {
+	encoded := os.Getenv(name)
+	if encoded == "" {
+		return nil, fmt.Errorf("encryption key not configured: set %s to a base64-encoded AES key", name)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key in %s: %w", name, err)
+	}
+	return NewCipher(key)
+}
+
+// Encrypt encrypts a value, returning it with the Prefix.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	sealed, err := c.seal([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt.
+// Values without the Prefix are returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	plaintext, err := c.open(sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// seal encrypts data with a random nonce, returning the nonce followed by
+// the ciphertext.
+func (c *Cipher) seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data produced by seal.
+func (c *Cipher) open(sealed []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("invalid encrypted value: too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value (wrong key?): %w", err)
+	}
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether a value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Seal returns the JSON encoding of v with sensitive fields encrypted.
+// v itself is not modified. Values that are already encrypted are kept.
+func Seal(c *Cipher, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || v == nil || !HasSensitiveFields(reflect.TypeOf(v)) {
+		return data, err
+	}
+
+	// Work on a copy so the caller keeps its plaintext values
+	clone := reflect.New(reflect.TypeOf(v))
+	if err := json.Unmarshal(data, clone.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to copy value: %w", err)
+	}
+	err = walk(clone, func(s string) (string, error) {
+		if s == "" || IsEncrypted(s) {
+			return s, nil
+		}
+		return c.Encrypt(s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(clone.Interface())
+}
+
+// Open decodes JSON produced by Seal into v and decrypts its sensitive fields.
+// v must be a pointer.
+func Open(c *Cipher, data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if !HasSensitiveFields(reflect.TypeOf(v)) {
+		return nil
+	}
+	return walk(reflect.ValueOf(v), c.Decrypt)
+}
+
+// Redact replaces non-empty sensitive values in v with RedactedValue.
+// v must be a pointer, or a slice of pointers; it is modified in place.
+func Redact(v interface{}) {
+	if v == nil || !HasSensitiveFields(reflect.TypeOf(v)) {
+		return
+	}
+	_ = walk(reflect.ValueOf(v), func(s string) (string, error) {
+		if s == "" {
+			return s, nil
+		}
+		return RedactedValue, nil
+	})
+}
+
+// HasSensitiveFields reports whether a type contains fields marked sensitive.
+func HasSensitiveFields(t reflect.Type) bool {
+	if cached, ok := typeCache.Load(t); ok {
+		return cached.(bool)
+	}
+	result := hasSensitive(t, map[reflect.Type]bool{})
+	typeCache.Store(t, result)
+	return result
+}
+
+var typeCache sync.Map // reflect.Type -> bool
+
+func hasSensitive(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isSensitive(field) || hasSensitive(field.Type, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitive reports whether a struct field carries the sensitive marker.
+func isSensitive(field reflect.StructField) bool {
+	for _, opt := range strings.Split(field.Tag.Get(TagName), ",") {
+		if strings.TrimSpace(opt) == "sensitive" {
+			return true
+		}
+	}
+	return false
+}
+
+// walk applies fn to every sensitive string reachable from v.
+func walk(v reflect.Value, fn func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walk(v.Elem(), fn)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walk(v.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			if !isSensitive(field) {
+				if err := walk(fv, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := apply(field, fv, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply transforms a single sensitive field value.
+func apply(field reflect.StructField, fv reflect.Value, fn func(string) (string, error)) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() != reflect.String {
+		return fmt.Errorf("field %s: %s:\"sensitive\" is only supported on string fields", field.Name, TagName)
+	}
+	if !fv.CanSet() {
+		return fmt.Errorf("field %s: value is not addressable", field.Name)
+	}
+	out, err := fn(fv.String())
+	if err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	fv.SetString(out)
+	return nil
+}
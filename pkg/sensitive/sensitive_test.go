@@ -0,0 +1,179 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package sensitive
+
+import (
+	"bytes"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testCredential struct {
+	User     string `json:"user"`
+	Password string `json:"password" fabrica:"sensitive"`
+}
+
+type testSpec struct {
+	Name        string           `json:"name"`
+	Token       *string          `json:"token,omitempty" fabrica:"sensitive"`
+	Credentials []testCredential `json:"credentials"`
+	Primary     *testCredential  `json:"primary,omitempty"`
+}
+
+type testResource struct {
+	Spec testSpec `json:"spec"`
+}
+
+func testCipher(t *testing.T) *Cipher {
+	t.Helper()
+	c, err := NewCipher(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher failed: %v", err)
+	}
+	return c
+}
+
+func newTestResource() *testResource {
+	token := "tok-123"
+	return &testResource{Spec: testSpec{
+		Name:        "bmc-1",
+		Token:       &token,
+		Credentials: []testCredential{{User: "root", Password: "hunter2"}},
+		Primary:     &testCredential{User: "admin", Password: "s3cret"},
+	}}
+}
+
+func TestSealAndOpen(t *testing.T) {
+	c := testCipher(t)
+	res := newTestResource()
+
+	data, err := Seal(c, res)
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	for _, secret := range []string{"tok-123", "hunter2", "s3cret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Sealed JSON contains plaintext %q: %s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), `"bmc-1"`) || !strings.Contains(string(data), `"root"`) {
+		t.Errorf("Sealed JSON lost non-sensitive fields: %s", data)
+	}
+	if res.Spec.Credentials[0].Password != "hunter2" {
+		t.Error("Seal modified its argument")
+	}
+
+	var opened testResource
+	if err := Open(c, data, &opened); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if *opened.Spec.Token != "tok-123" || opened.Spec.Credentials[0].Password != "hunter2" || opened.Spec.Primary.Password != "s3cret" {
+		t.Errorf("Unexpected opened resource: %+v", opened.Spec)
+	}
+
+	// Already-encrypted values are not encrypted twice
+	resealed, err := Seal(c, &testResource{Spec: testSpec{Credentials: []testCredential{{Password: "enc:v1:abc"}}}})
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	if !strings.Contains(string(resealed), `"enc:v1:abc"`) {
+		t.Errorf("Expected encrypted value to be kept, got %s", resealed)
+	}
+}
+
+func TestOpen_PlaintextPassthrough(t *testing.T) {
+	c := testCipher(t)
+	var res testResource
+	if err := Open(c, []byte(`{"spec":{"credentials":[{"password":"legacy"}]}}`), &res); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if res.Spec.Credentials[0].Password != "legacy" {
+		t.Errorf("Expected plaintext to pass through, got %q", res.Spec.Credentials[0].Password)
+	}
+}
+
+func TestOpen_WrongKey(t *testing.T) {
+	data, err := Seal(testCipher(t), newTestResource())
+	if err != nil {
+		t.Fatalf("Seal failed: %v", err)
+	}
+	other, _ := NewCipher(bytes.Repeat([]byte{9}, 32))
+	var res testResource
+	if err := Open(other, data, &res); err == nil {
+		t.Error("Expected error opening with the wrong key")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	items := []*testResource{newTestResource(), newTestResource()}
+	items[1].Spec.Primary = nil
+
+	Redact(items)
+	for _, item := range items {
+		if *item.Spec.Token != RedactedValue || item.Spec.Credentials[0].Password != RedactedValue {
+			t.Errorf("Expected sensitive fields redacted, got %+v", item.Spec)
+		}
+		if item.Spec.Credentials[0].User != "root" {
+			t.Errorf("Non-sensitive field was redacted: %+v", item.Spec)
+		}
+	}
+}
+
+func TestHasSensitiveFields(t *testing.T) {
+	type plain struct {
+		Name string `json:"name"`
+	}
+	if HasSensitiveFields(reflect.TypeOf(plain{})) {
+		t.Error("Expected no sensitive fields")
+	}
+	if !HasSensitiveFields(reflect.TypeOf([]*testResource{})) {
+		t.Error("Expected sensitive fields in nested slice")
+	}
+}
+
+func TestCipherFromEnv(t *testing.T) {
+	t.Setenv("TEST_FIELD_KEY", "")
+	if _, err := CipherFromEnv("TEST_FIELD_KEY"); err == nil {
+		t.Error("Expected error for missing key")
+	}
+	t.Setenv("TEST_FIELD_KEY", base64.StdEncoding.EncodeToString([]byte("short")))
+	if _, err := CipherFromEnv("TEST_FIELD_KEY"); err == nil {
+		t.Error("Expected error for invalid key length")
+	}
+	t.Setenv("TEST_FIELD_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+	if _, err := CipherFromEnv("TEST_FIELD_KEY"); err != nil {
+		t.Errorf("CipherFromEnv failed: %v", err)
+	}
+}
+
+func TestMasker(t *testing.T) {
+	res := newTestResource()
+	m := NewMasker([]MaskRule{
+		{Path: "spec.name", Keep: 2},
+		{Path: "spec.credentials.user"},
+		{Path: "spec.missing.field"},
+	})
+
+	masked, err := m.Mask([]*testResource{res})
+	if err != nil {
+		t.Fatalf("Mask failed: %v", err)
+	}
+	spec := masked.([]interface{})[0].(map[string]interface{})["spec"].(map[string]interface{})
+	if spec["name"] != "***-1" {
+		t.Errorf("Expected partial mask, got %v", spec["name"])
+	}
+	if spec["token"] != RedactedValue {
+		t.Errorf("Expected tagged field masked, got %v", spec["token"])
+	}
+	cred := spec["credentials"].([]interface{})[0].(map[string]interface{})
+	if cred["user"] != RedactedValue || cred["password"] != RedactedValue {
+		t.Errorf("Expected array elements masked, got %v", cred)
+	}
+	if res.Spec.Name != "bmc-1" || res.Spec.Credentials[0].Password != "hunter2" {
+		t.Error("Mask modified its argument")
+	}
+}
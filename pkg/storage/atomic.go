@@ -0,0 +1,103 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// tempSuffix ends the names of the temp files of WriteFileAtomic
+const tempSuffix = ".tmp"
+
+// WriteFileAtomic writes data to the file at path so that a crash at any
+// point leaves either the previous content or the new content, never a
+// partial file.
+//
+// The data is written to a temp file next to path (named after it, ending
+// in ".tmp"), flushed to disk, and renamed over path; the directory is then
+// flushed so the rename itself survives a crash. Temp files left by a crash
+// are removed by NewFileBackend.
+//
+// Parameters:
+//   - path: File to write
+//   - data: Content of the file
+//   - perm: Permissions of the file
+//
+// Returns:
+//   - error: If the file can't be written; path is then unchanged
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	temp, err := os.CreateTemp(dir, name+".*"+tempSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %w", path, err)
+	}
+	tempPath := temp.Name()
+	written := false
+	defer func() {
+		if !written {
+			temp.Close()
+			_ = os.Remove(tempPath)
+		}
+	}()
+
+	if _, err := temp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp file %s: %w", tempPath, err)
+	}
+	if err := temp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to set permissions of temp file %s: %w", tempPath, err)
+	}
+	if err := temp.Sync(); err != nil {
+		return fmt.Errorf("failed to flush temp file %s: %w", tempPath, err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file %s: %w", tempPath, err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to rename temp file %s to %s: %w", tempPath, path, err)
+	}
+	written = true
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes the entries of a directory, so renames and removals in it
+// survive a crash. It is best effort: some platforms (e.g., Windows) can't
+// flush directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	d.Close()
+}
+
+// removeIncompleteWrites removes the temp files of writes interrupted by a
+// crash from a directory tree
+func removeIncompleteWrites(baseDir string) error {
+	err := filepath.WalkDir(baseDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), tempSuffix) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove incomplete writes in %s: %w", baseDir, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dev-1.json")
+
+	if err := WriteFileAtomic(path, []byte(`{"v":1}`), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte(`{"v":2}`), 0640); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"v":2}` {
+		t.Fatalf("file = %q, %v; want the second write", data, err)
+	}
+	if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0640 {
+		t.Errorf("permissions = %v, want 0640", info.Mode().Perm())
+	}
+
+	// Failed writes leave the file and no temp file behind
+	if err := WriteFileAtomic(filepath.Join(dir, "missing", "dev-2.json"), []byte(`{}`), 0644); err == nil {
+		t.Error("expected an error writing to a missing directory")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries after the writes, want only the file", len(entries))
+	}
+}
+
+func TestFileBackendRecoversIncompleteWrites(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Save(ctx, "Device", "dev-1", device("dev-1", "node-1", nil, "x1")); err != nil {
+		t.Fatal(err)
+	}
+	backend.Close()
+
+	// A crash during the next write of dev-1 leaves a partial temp file,
+	// including one from the naming of earlier releases
+	devices := filepath.Join(dir, "devices")
+	for _, name := range []string{"dev-1.json.123456.tmp", "dev-1.json.tmp"} {
+		if err := os.WriteFile(filepath.Join(devices, name), []byte(`{"metadata":`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backend, err = NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	raw, err := backend.LoadAll(ctx, "Device")
+	assertUIDs(t, "LoadAll after a crash", raw, err, "dev-1")
+	entries, _ := os.ReadDir(devices)
+	if len(entries) != 1 || entries[0].Name() != "dev-1.json" {
+		t.Errorf("temp files weren't removed: %v", entries)
+	}
+}
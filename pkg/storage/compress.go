@@ -0,0 +1,170 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression encodings of stored payloads
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// CompressMinSize is the smallest payload Compress compresses. Smaller
+// payloads gain little and are stored as-is.
+const CompressMinSize = 512
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	// compressedPrefix starts the JSON of every CompressedValue
+	compressedPrefix = []byte(`{"compressed":"`)
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// zstdCodec returns the shared zstd encoder and decoder, which are safe for
+// concurrent use through EncodeAll and DecodeAll
+func zstdCodec() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// ValidCompression reports whether encoding is a supported compression
+// encoding.
+func ValidCompression(encoding string) bool {
+	return encoding == CompressionGzip || encoding == CompressionZstd
+}
+
+// Compress compresses a stored payload with an encoding (CompressionGzip or
+// CompressionZstd). Payloads smaller than CompressMinSize are returned
+// unchanged.
+//
+// The result starts with the encoding's magic number, so Decompress
+// recognizes it without being told the encoding; JSON never starts that way.
+func Compress(encoding string, data []byte) ([]byte, error) {
+	if len(data) < CompressMinSize {
+		return data, nil
+	}
+	switch encoding {
+	case CompressionZstd:
+		encoder, _, err := zstdCodec()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		return encoder.EncodeAll(data, make([]byte, 0, len(data)/4)), nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to gzip payload: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to gzip payload: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q (must be %q or %q)", encoding, CompressionGzip, CompressionZstd)
+	}
+}
+
+// Decompress reverses Compress, detecting the encoding from the payload's
+// magic number. Other payloads, such as plain JSON, are returned unchanged,
+// so data written before compression was enabled keeps working.
+func Decompress(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, zstdMagic):
+		_, decoder, err := zstdCodec()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		out, err := decoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress zstd payload: %w", err)
+		}
+		return out, nil
+	case bytes.HasPrefix(data, gzipMagic):
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip payload: %w", err)
+		}
+		defer r.Close()
+		out, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip payload: %w", err)
+		}
+		return out, nil
+	default:
+		return data, nil
+	}
+}
+
+// CompressedValue is a compressed payload stored where JSON is required,
+// such as a JSON database column.
+type CompressedValue struct {
+	Encoding string `json:"compressed"`
+	Data     []byte `json:"data"` // Output of Compress
+}
+
+// CompressJSON compresses a JSON payload like Compress, returning the JSON of
+// a CompressedValue. Payloads smaller than CompressMinSize are returned
+// unchanged.
+func CompressJSON(encoding string, data []byte) ([]byte, error) {
+	if len(data) < CompressMinSize {
+		return data, nil
+	}
+	compressed, err := Compress(encoding, data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(CompressedValue{Encoding: encoding, Data: compressed})
+}
+
+// DecompressJSON reverses CompressJSON. Other JSON is returned unchanged.
+func DecompressJSON(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, compressedPrefix) {
+		return data, nil
+	}
+	var value CompressedValue
+	if err := json.Unmarshal(data, &value); err != nil || !ValidCompression(value.Encoding) {
+		return data, nil
+	}
+	return Decompress(value.Data)
+}
+
+// readResourceFile reads a stored resource, decompressing it if needed. It
+// reports false for files that can't be read or don't hold valid JSON.
+func readResourceFile(path string) (json.RawMessage, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	if data, err = Decompress(data); err != nil || !json.Valid(data) {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,118 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// largeDevice is a device whose JSON is well above CompressMinSize
+func largeDevice(uid, name string) []byte {
+	return device(uid, name, map[string]string{"rack": "r1"}, strings.Repeat("model-x1 ", 200))
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	data := largeDevice("dev-1", "node-1")
+	for _, encoding := range []string{CompressionGzip, CompressionZstd} {
+		compressed, err := Compress(encoding, data)
+		if err != nil {
+			t.Fatalf("%s: Compress failed: %v", encoding, err)
+		}
+		if len(compressed) >= len(data)/2 {
+			t.Errorf("%s: compressed %d bytes to %d", encoding, len(data), len(compressed))
+		}
+		got, err := Decompress(compressed)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: Decompress = %s, %v", encoding, got, err)
+		}
+
+		wrapped, err := CompressJSON(encoding, data)
+		if err != nil {
+			t.Fatalf("%s: CompressJSON failed: %v", encoding, err)
+		}
+		if got, err := DecompressJSON(wrapped); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("%s: DecompressJSON = %s, %v", encoding, got, err)
+		}
+	}
+
+	// Small and plain payloads pass through
+	small := device("dev-2", "node-2", nil, "x1")
+	if got, err := Compress(CompressionZstd, small); err != nil || !bytes.Equal(got, small) {
+		t.Errorf("Compress(small) = %s, %v", got, err)
+	}
+	if got, err := Decompress(small); err != nil || !bytes.Equal(got, small) {
+		t.Errorf("Decompress(plain) = %s, %v", got, err)
+	}
+	if got, err := DecompressJSON(small); err != nil || !bytes.Equal(got, small) {
+		t.Errorf("DecompressJSON(plain) = %s, %v", got, err)
+	}
+	if _, err := Compress("lz4", data); err == nil {
+		t.Error("Compress with an unsupported encoding succeeded")
+	}
+}
+
+func TestFileBackendCompression(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// A plain file written before compression was enabled
+	first, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Save(ctx, "Device", "dev-1", largeDevice("dev-1", "node-1")) // nolint:errcheck
+	first.Close()
+
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.EnableCompression(CompressionGzip); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.EnableIndexFile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Save(ctx, "Device", "dev-2", largeDevice("dev-2", "node-2")); err != nil {
+		t.Fatal(err)
+	}
+	backend.Close()
+
+	stored, err := os.ReadFile(filepath.Join(dir, "devices", "dev-2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(stored, gzipMagic) {
+		t.Fatalf("dev-2.json is not gzip-compressed: %q", stored[:16])
+	}
+
+	// Both files are read back, from the directory and from the index file
+	for _, indexFile := range []bool{false, true} {
+		reader, err := NewFileBackend(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if indexFile {
+			if err := reader.EnableIndexFile(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		raw, err := reader.LoadByLabels(ctx, "Device", map[string]string{"rack": "r1"})
+		assertUIDs(t, "LoadByLabels", raw, err, "dev-1", "dev-2")
+		if data, err := reader.Load(ctx, "Device", "dev-2"); err != nil || !bytes.Equal(data, largeDevice("dev-2", "node-2")) {
+			t.Errorf("Load(dev-2) = %s, %v", data, err)
+		}
+		reader.Close()
+	}
+
+	if err := backend.EnableCompression("lz4"); err == nil {
+		t.Error("EnableCompression with an unsupported encoding succeeded")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,31 +28,65 @@ import (
 //	│   ├── product-789.json
 //	│   └── product-abc.json
 //	└── orders/
-//	    └── order-def.json
+//	    ├── order-def.json
+//	    └── tenant-a/          (resource type "Order/tenant-a")
+//	        └── order-123.json
 //
 // Features:
-//   - Thread-safe: Uses file locking for concurrent access
-//   - Atomic writes: Uses temp files + rename for atomicity
+//   - In-memory index: Reads are served from an index of every resource,
+//     built from disk on first use and updated on writes (see BuildIndex)
+//   - Index file: Optionally persists the index's metadata, so startup
+//     doesn't read every file (see EnableIndexFile)
+//   - Thread-safe: Writes lock only the resource they change (lock striping),
+//     and reads share the index, so requests for different resources don't
+//     wait for each other
+//   - Crash-safe writes: Files are written to flushed temp files renamed over
+//     the originals (see WriteFileAtomic), and temp files left by a crash are
+//     removed at startup
+//   - Compression: Optionally compresses large files with gzip or zstd (see
+//     EnableCompression)
+//   - Pages: LoadPage serves pages in UID order from the index, with continue
+//     tokens
 //   - Auto-creation: Creates directories as needed
 //   - Validation: Checks JSON format before saving
 //   - Error recovery: Continues operation even if some files are corrupted
 //
 // Limitations:
-//   - Performance: Not optimized for large numbers of resources
-//   - Scalability: File system limits apply
-//   - Consistency: No transactions across multiple resources
-//   - Locking: File locking may not work on all file systems
+//   - Memory: The index holds every resource, so memory rather than disk
+//     reads bounds how many resources the backend serves well
+//   - Scalability: File system limits apply, such as files per directory
+//   - Consistency: Each write is atomic, but writes of several resources
+//     aren't: FileBackend doesn't implement TransactionalBackend, so WithTx
+//     returns ErrTransactionsUnsupported
+//   - Single process: Write locks are held in memory, and files changed by
+//     other processes aren't seen once indexed
 //
 // This backend is suitable for:
 //   - Development and testing
-//   - Small to medium deployments
-//   - Environments where simplicity is preferred over performance
+//   - Small to medium deployments, whose resources fit in memory
+//   - Single-replica servers
 //   - Situations where human-readable storage is valuable
 type FileBackend struct {
-	baseDir         string
+	baseDir string
+
+	// mu guards closed and versionRegistry. Every operation holds it shared,
+	// so Close waits for operations in flight.
 	mu              sync.RWMutex
 	closed          bool
 	versionRegistry VersionRegistry // Version registry for conversion support
+
+	// stripes serialize writes to the same resource; see resourceLock
+	stripes [lockStripes]sync.Mutex
+
+	indexMu sync.RWMutex
+	indexes map[string]*fileIndex // per resource type, see file_index.go
+	journal *indexJournal         // index file, see file_index_journal.go
+
+	compression string // encoding of written files, see EnableCompression
+
+	dirs map[string]string // directory of each kind set with SetPlural
+
+	watchers watchers // see watch.go
 }
 
 // VersionRegistry is an interface for version conversion support
@@ -81,7 +116,9 @@ type VersionConverter interface {
 //   - *FileBackend: Configured file backend
 //   - error: Any error that occurred during initialization
 //
-// The function will create the base directory if it doesn't exist.
+// The function will create the base directory if it doesn't exist, and
+// removes the temp files of writes interrupted by a crash, whose resources
+// keep their previous content.
 //
 // Example:
 //
@@ -95,6 +132,9 @@ func NewFileBackend(baseDir string) (*FileBackend, error) {
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create base directory %s: %w", baseDir, err)
 	}
+	if err := removeIncompleteWrites(baseDir); err != nil {
+		return nil, err
+	}
 
 	backend := &FileBackend{
 		baseDir: baseDir,
@@ -103,16 +143,110 @@ func NewFileBackend(baseDir string) (*FileBackend, error) {
 	return backend, nil
 }
 
-// resourceTypeToDir maps resource type names to directory names
+// EnableCompression compresses the files written from now on with an
+// encoding, CompressionGzip or CompressionZstd, which suits large, repetitive
+// resources such as big status payloads.
+//
+// Files smaller than CompressMinSize stay plain JSON. Files keep their names
+// and compressed files are recognized by their content, so plain files
+// written before are still read (and compressed on their next save), and
+// compression can be disabled again with an empty encoding. The index keeps
+// resources uncompressed in memory.
+//
+// Example:
+//
+//	backend, _ := storage.NewFileBackend("./data")
+//	if err := backend.EnableCompression(storage.CompressionZstd); err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *FileBackend) EnableCompression(encoding string) error {
+	if encoding != "" && !ValidCompression(encoding) {
+		return fmt.Errorf("unsupported compression %q (must be %q or %q)", encoding, CompressionGzip, CompressionZstd)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkClosed(); err != nil {
+		return err
+	}
+	f.compression = encoding
+	return nil
+}
+
+// lockStripes is the number of write locks shared by all resources.
+// Writes to resources hashing to different stripes run in parallel.
+const lockStripes = 64
+
+// resourceLock returns the write lock of a resource
+func (f *FileBackend) resourceLock(resourceType, uid string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(resourceType))
+	h.Write([]byte{0})
+	h.Write([]byte(uid))
+	return &f.stripes[h.Sum32()%lockStripes]
+}
+
+// SetPlural stores the resources of kind in the directory named plural,
+// such as "inventories" for "Inventory" or "chassis" for "Chassis", so the
+// data directory is laid out like the API's routes. Generated servers call
+// it for every resource before building the index.
+//
+// Kinds without a plural set take an "s" unless their lowercase name already
+// ends in one ("quotas", "chassis"), which is how every kind was stored
+// before plurals were inflected. A data directory written that way keeps
+// being used: when the directory of that rule exists and the plural's
+// doesn't, the kind stays in the former.
+//
+// Example:
+//
+//	backend, _ := storage.NewFileBackend("./data")
+//	backend.SetPlural("Inventory", "inventories")
+func (f *FileBackend) SetPlural(kind, plural string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dirs == nil {
+		f.dirs = make(map[string]string)
+	}
+	dir := plural
+	if legacy := legacyTypeDir(kind); legacy != plural && dirExists(filepath.Join(f.baseDir, legacy)) && !dirExists(filepath.Join(f.baseDir, plural)) {
+		dir = legacy
+	}
+	f.dirs[kind] = dir
+}
+
+// resourceTypeToDir maps resource type names to directory names: the
+// plural set with SetPlural, or the kind with an "s". Types of the form
+// "<Kind>/<partition>" (see namespace.StorageType) map to a subdirectory
+// of the kind's directory.
 func (f *FileBackend) resourceTypeToDir(resourceType string) string {
-	// Convert to lowercase and pluralize by adding 's' if not already plural
-	dir := strings.ToLower(resourceType)
+	kind, partition, partitioned := strings.Cut(resourceType, "/")
+	dir, ok := f.dirs[kind]
+	if !ok {
+		dir = legacyTypeDir(kind)
+	}
+	if partitioned {
+		return filepath.Join(dir, filepath.Base(partition))
+	}
+	return dir
+}
+
+// legacyTypeDir returns the directory of a kind without a plural set: its
+// lowercase name with an "s", if it doesn't already end in one
+func legacyTypeDir(kind string) string {
+	dir := strings.ToLower(kind)
 	if !strings.HasSuffix(dir, "s") {
 		dir = dir + "s"
 	}
 	return dir
 }
 
+// dirExists reports whether path is an existing directory
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 // getFilePath returns the file path for a specific resource
 func (f *FileBackend) getFilePath(resourceType, uid string) string {
 	dir := f.resourceTypeToDir(resourceType)
@@ -141,9 +275,11 @@ func (f *FileBackend) LoadAll(ctx context.Context, resourceType string) ([]json.
 	if err := f.checkClosed(); err != nil {
 		return nil, err
 	}
+	return f.loadAll(ctx, resourceType)
+}
 
-	dirPath := f.getDirPath(resourceType)
-
+// loadAll returns every resource of a type from the index. The caller holds f.mu.
+func (f *FileBackend) loadAll(ctx context.Context, resourceType string) ([]json.RawMessage, error) {
 	// Check if context is cancelled before starting
 	select {
 	case <-ctx.Done():
@@ -151,44 +287,11 @@ func (f *FileBackend) LoadAll(ctx context.Context, resourceType string) ([]json.
 	default:
 	}
 
-	entries, err := os.ReadDir(dirPath)
+	idx, err := f.index(ctx, resourceType)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []json.RawMessage{}, nil // Empty slice, not an error
-		}
-		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-	}
-
-	var resources []json.RawMessage
-	for _, entry := range entries {
-		// Check for cancellation periodically
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
-			continue
-		}
-
-		filePath := filepath.Join(dirPath, entry.Name())
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			// Log warning but continue with other files
-			continue
-		}
-
-		// Validate JSON format
-		if !json.Valid(data) {
-			// Log warning but continue with other files
-			continue
-		}
-
-		resources = append(resources, json.RawMessage(data))
+		return nil, err
 	}
-
-	return resources, nil
+	return idx.all(), nil
 }
 
 // Load implements StorageBackend.Load
@@ -199,7 +302,11 @@ func (f *FileBackend) Load(ctx context.Context, resourceType, uid string) (json.
 	if err := f.checkClosed(); err != nil {
 		return nil, err
 	}
+	return f.load(ctx, resourceType, uid)
+}
 
+// load returns a resource from the index. The caller holds f.mu.
+func (f *FileBackend) load(ctx context.Context, resourceType, uid string) (json.RawMessage, error) {
 	// Check if context is cancelled
 	select {
 	case <-ctx.Done():
@@ -207,33 +314,30 @@ func (f *FileBackend) Load(ctx context.Context, resourceType, uid string) (json.
 	default:
 	}
 
-	filePath := f.getFilePath(resourceType, uid)
-
-	data, err := os.ReadFile(filePath)
+	idx, err := f.index(ctx, resourceType)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, ErrNotFound
-		}
-		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+		return nil, err
 	}
-
-	// Validate JSON format
-	if !json.Valid(data) {
-		return nil, fmt.Errorf("invalid JSON in file %s: %w", filePath, ErrInvalidData)
+	data, ok := idx.get(uid)
+	if !ok {
+		return nil, ErrNotFound
 	}
-
-	return json.RawMessage(data), nil
+	return data, nil
 }
 
 // Save implements StorageBackend.Save
 func (f *FileBackend) Save(ctx context.Context, resourceType, uid string, data json.RawMessage) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
 	if err := f.checkClosed(); err != nil {
 		return err
 	}
+	return f.save(ctx, resourceType, uid, data)
+}
 
+// save writes a resource and updates the index. The caller holds f.mu.
+func (f *FileBackend) save(ctx context.Context, resourceType, uid string, data json.RawMessage) error {
 	// Check if context is cancelled
 	select {
 	case <-ctx.Done():
@@ -246,6 +350,10 @@ func (f *FileBackend) Save(ctx context.Context, resourceType, uid string, data j
 		return fmt.Errorf("invalid JSON data: %w", ErrInvalidData)
 	}
 
+	lock := f.resourceLock(resourceType, uid)
+	lock.Lock()
+	defer lock.Unlock()
+
 	filePath := f.getFilePath(resourceType, uid)
 
 	// Ensure directory exists
@@ -254,26 +362,42 @@ func (f *FileBackend) Save(ctx context.Context, resourceType, uid string, data j
 		return fmt.Errorf("failed to create directory %s: %w", dirPath, err)
 	}
 
-	// Use atomic write: write to temp file, then rename
-	tempPath := filePath + ".tmp"
+	stored := []byte(data)
+	if f.compression != "" {
+		var err error
+		if stored, err = Compress(f.compression, data); err != nil {
+			return err
+		}
+	}
 
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file %s: %w", tempPath, err)
+	// A crash mid-write leaves the previous version of the file
+	if err := WriteFileAtomic(filePath, stored, 0644); err != nil {
+		return err
 	}
 
-	if err := os.Rename(tempPath, filePath); err != nil {
-		// Clean up temp file on error
-		_ = os.Remove(tempPath)
-		return fmt.Errorf("failed to rename temp file %s to %s: %w", tempPath, filePath, err)
+	idx, err := f.index(ctx, resourceType)
+	if err != nil {
+		return err
 	}
+	idx.put(uid, data)
+	if f.journal != nil {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to stat file %s: %w", filePath, err)
+		}
+		if err := f.journal.put(resourceType, uid, filePath, data, info); err != nil {
+			return err
+		}
+	}
+	f.watchers.notify(WatchEvent{Type: WatchSaved, ResourceType: resourceType, UID: uid, Data: data})
 
 	return nil
 }
 
 // Delete implements StorageBackend.Delete
 func (f *FileBackend) Delete(ctx context.Context, resourceType, uid string) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
 	if err := f.checkClosed(); err != nil {
 		return err
@@ -286,6 +410,10 @@ func (f *FileBackend) Delete(ctx context.Context, resourceType, uid string) erro
 	default:
 	}
 
+	lock := f.resourceLock(resourceType, uid)
+	lock.Lock()
+	defer lock.Unlock()
+
 	filePath := f.getFilePath(resourceType, uid)
 
 	// Check if file exists
@@ -299,10 +427,35 @@ func (f *FileBackend) Delete(ctx context.Context, resourceType, uid string) erro
 	if err := os.Remove(filePath); err != nil {
 		return fmt.Errorf("failed to delete file %s: %w", filePath, err)
 	}
+	syncDir(filepath.Dir(filePath))
+
+	idx, err := f.index(ctx, resourceType)
+	if err != nil {
+		return err
+	}
+	idx.remove(uid)
+	if f.journal != nil {
+		if err := f.journal.delete(resourceType, uid); err != nil {
+			return err
+		}
+	}
+	f.watchers.notify(WatchEvent{Type: WatchDeleted, ResourceType: resourceType, UID: uid})
 
 	return nil
 }
 
+// Watch implements WatchableBackend.Watch. Only changes made through this
+// backend are reported; files written by other processes are not.
+func (f *FileBackend) Watch(ctx context.Context, resourceType string) (<-chan WatchEvent, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if err := f.checkClosed(); err != nil {
+		return nil, err
+	}
+	return f.watchers.add(ctx, resourceType)
+}
+
 // Exists implements StorageBackend.Exists
 func (f *FileBackend) Exists(ctx context.Context, resourceType, uid string) (bool, error) {
 	f.mu.RLock()
@@ -319,17 +472,12 @@ func (f *FileBackend) Exists(ctx context.Context, resourceType, uid string) (boo
 	default:
 	}
 
-	filePath := f.getFilePath(resourceType, uid)
-
-	_, err := os.Stat(filePath)
+	idx, err := f.index(ctx, resourceType)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to stat file %s: %w", filePath, err)
+		return false, err
 	}
-
-	return true, nil
+	_, ok := idx.get(uid)
+	return ok, nil
 }
 
 // List implements StorageBackend.List
@@ -341,8 +489,6 @@ func (f *FileBackend) List(ctx context.Context, resourceType string) ([]string,
 		return nil, err
 	}
 
-	dirPath := f.getDirPath(resourceType)
-
 	// Check if context is cancelled
 	select {
 	case <-ctx.Done():
@@ -350,41 +496,24 @@ func (f *FileBackend) List(ctx context.Context, resourceType string) ([]string,
 	default:
 	}
 
-	entries, err := os.ReadDir(dirPath)
+	idx, err := f.index(ctx, resourceType)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil // Empty slice, not an error
-		}
-		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
-	}
-
-	var uids []string
-	for _, entry := range entries {
-		// Check for cancellation periodically
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
-			continue
-		}
-
-		// Extract UID from filename (remove .json extension)
-		uid := strings.TrimSuffix(entry.Name(), ".json")
-		uids = append(uids, uid)
+		return nil, err
 	}
-
-	return uids, nil
+	return idx.list(), nil
 }
 
-// Close implements StorageBackend.Close
+// Close implements StorageBackend.Close, ending every watch and closing the
+// index file
 func (f *FileBackend) Close() error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	f.closed = true
+	f.watchers.close()
+	if f.journal != nil {
+		return f.journal.close()
+	}
 	return nil
 }
 
@@ -417,7 +546,7 @@ func (f *FileBackend) LoadWithVersion(ctx context.Context, resourceType, uid, ve
 	}
 
 	// Load the raw resource (stored in default version)
-	rawData, err := f.Load(ctx, resourceType, uid)
+	rawData, err := f.load(ctx, resourceType, uid)
 	if err != nil {
 		return nil, "", err
 	}
@@ -492,7 +621,7 @@ func (f *FileBackend) LoadAllWithVersion(ctx context.Context, resourceType, vers
 	}
 
 	// Load all resources in default version
-	rawResources, err := f.LoadAll(ctx, resourceType)
+	rawResources, err := f.loadAll(ctx, resourceType)
 	if err != nil {
 		return nil, err
 	}
@@ -562,8 +691,8 @@ func (f *FileBackend) LoadAllWithVersion(ctx context.Context, resourceType, vers
 
 // SaveWithVersion implements StorageBackend.SaveWithVersion
 func (f *FileBackend) SaveWithVersion(ctx context.Context, resourceType, uid string, data json.RawMessage, version string) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 
 	if err := f.checkClosed(); err != nil {
 		return err
@@ -584,12 +713,12 @@ func (f *FileBackend) SaveWithVersion(ctx context.Context, resourceType, uid str
 	defaultVersion := f.versionRegistry.GetDefaultVersion(resourceType)
 	if defaultVersion == "" {
 		// No versioning configured, save as-is
-		return f.Save(ctx, resourceType, uid, data)
+		return f.save(ctx, resourceType, uid, data)
 	}
 
 	// If data is already in default version, save as-is
 	if version == "" || version == defaultVersion {
-		return f.Save(ctx, resourceType, uid, data)
+		return f.save(ctx, resourceType, uid, data)
 	}
 
 	// Need to convert to storage version
@@ -621,5 +750,5 @@ func (f *FileBackend) SaveWithVersion(ctx context.Context, resourceType, uid str
 	}
 
 	// Save in storage version
-	return f.Save(ctx, resourceType, uid, json.RawMessage(storageData))
+	return f.save(ctx, resourceType, uid, json.RawMessage(storageData))
 }
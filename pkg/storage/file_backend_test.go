@@ -0,0 +1,86 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBackend_PartitionedTypes(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := backend.Save(ctx, "Device", "dev-1", json.RawMessage(`{"uid":"dev-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Save(ctx, "Device/tenant-a", "dev-2", json.RawMessage(`{"uid":"dev-2"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "devices", "tenant-a", "dev-2.json")); err != nil {
+		t.Errorf("Expected the partition in a subdirectory: %v", err)
+	}
+
+	// Partitions don't see each other's resources, also after a restart
+	for _, b := range []*FileBackend{backend, mustFileBackend(t, dir)} {
+		if uids, _ := b.List(ctx, "Device"); len(uids) != 1 || uids[0] != "dev-1" {
+			t.Errorf("Expected only dev-1 unpartitioned, got %v", uids)
+		}
+		if uids, _ := b.List(ctx, "Device/tenant-a"); len(uids) != 1 || uids[0] != "dev-2" {
+			t.Errorf("Expected only dev-2 in tenant-a, got %v", uids)
+		}
+		if _, err := b.Load(ctx, "Device/tenant-a", "dev-1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound across partitions, got %v", err)
+		}
+	}
+}
+
+func TestFileBackend_SetPlural(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend := mustFileBackend(t, dir)
+	backend.SetPlural("Inventory", "inventories")
+
+	if err := backend.Save(ctx, "Inventory/tenant-a", "inv-1", json.RawMessage(`{"uid":"inv-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "inventories", "tenant-a", "inv-1.json")); err != nil {
+		t.Errorf("Expected the resource under the plural: %v", err)
+	}
+
+	// Data directories written before plurals were inflected keep being used
+	legacy := t.TempDir()
+	if err := mustFileBackend(t, legacy).Save(ctx, "Inventory", "inv-2", json.RawMessage(`{"uid":"inv-2"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(legacy, "inventorys", "inv-2.json")); err != nil {
+		t.Fatalf("Expected the resource under the kind with an s: %v", err)
+	}
+	backend = mustFileBackend(t, legacy)
+	backend.SetPlural("Inventory", "inventories")
+	if _, err := backend.Load(ctx, "Inventory", "inv-2"); err != nil {
+		t.Errorf("Expected the resource from the existing directory, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(legacy, "inventories")); !os.IsNotExist(err) {
+		t.Errorf("Expected no directory for the plural, got %v", err)
+	}
+}
+
+func mustFileBackend(t *testing.T, dir string) *FileBackend {
+	t.Helper()
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend
+}
@@ -0,0 +1,454 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Compile-time check that FileBackend implements IndexedBackend
+var _ IndexedBackend = (*FileBackend)(nil)
+
+// fileIndex is the in-memory index of one resource type in a FileBackend.
+//
+// It holds every resource's stored JSON plus the fields used for lookups
+// (UID, name and labels), so reads don't touch the filesystem. It is built
+// from the resource directory on first use (or by BuildIndex) and updated
+// by Save and Delete. With an index file (see EnableIndexFile), resources
+// that didn't change since the index file was written are indexed from it,
+// and their JSON is read on first access.
+type fileIndex struct {
+	mu      sync.RWMutex
+	entries map[string]*indexEntry
+	uids    []string                       // sorted, to match directory order
+	byName  map[string]map[string]struct{} // name -> UIDs
+	byLabel map[string]map[string]struct{} // "key=value" -> UIDs
+}
+
+// indexEntry is one indexed resource
+type indexEntry struct {
+	name   string
+	labels map[string]string
+
+	// raw is the stored JSON. Entries indexed from the index file read it
+	// from path on first access; it stays nil if the file can't be read.
+	rawOnce sync.Once
+	raw     json.RawMessage
+	path    string
+
+	// doc is raw decoded, built by the first LoadMatching that needs it
+	docOnce sync.Once
+	doc     map[string]interface{}
+}
+
+// indexedMetadata is the part of a stored resource the index reads
+type indexedMetadata struct {
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+}
+
+func newFileIndex() *fileIndex {
+	return &fileIndex{
+		entries: map[string]*indexEntry{},
+		byName:  map[string]map[string]struct{}{},
+		byLabel: map[string]map[string]struct{}{},
+	}
+}
+
+// BuildIndex loads the given resource types into the in-memory index, so the
+// first requests don't pay for reading their directories. With an index file
+// (see EnableIndexFile), only the files written since it recorded them are
+// read.
+//
+// Types that aren't built here are indexed on first access. The index assumes
+// this backend is the only writer of its directory; files changed by other
+// processes are not seen until the backend is recreated.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - resourceTypes: Type names (e.g., "Device", "Rack")
+//
+// Returns:
+//   - error: If a resource directory can't be read
+//
+// Example:
+//
+//	backend, _ := storage.NewFileBackend("./data")
+//	if err := backend.BuildIndex(ctx, "Device", "Rack"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *FileBackend) BuildIndex(ctx context.Context, resourceTypes ...string) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if err := f.checkClosed(); err != nil {
+		return err
+	}
+	for _, resourceType := range resourceTypes {
+		if _, err := f.index(ctx, resourceType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadByName implements IndexedBackend.LoadByName
+func (f *FileBackend) LoadByName(ctx context.Context, resourceType, name string) ([]json.RawMessage, error) {
+	idx, err := f.readIndex(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.collect(idx.byName[name]), nil
+}
+
+// LoadByLabels implements IndexedBackend.LoadByLabels
+func (f *FileBackend) LoadByLabels(ctx context.Context, resourceType string, selector map[string]string) ([]json.RawMessage, error) {
+	idx, err := f.readIndex(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if len(selector) == 0 {
+		return idx.collectAll(), nil
+	}
+	return idx.collect(idx.matchLabels(selector)), nil
+}
+
+// LoadPage implements PageableBackend.LoadPage. Pages are selected in the
+// index, so only the resources of the page are copied (and read, for
+// entries indexed from the index file).
+func (f *FileBackend) LoadPage(ctx context.Context, resourceType string, opts ListOptions) (ListPage[json.RawMessage], error) {
+	after, err := pageStart(opts)
+	if err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+	idx, err := f.readIndex(ctx, resourceType)
+	if err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	uids := idx.uids
+	if len(opts.LabelSelector) > 0 {
+		uids = sortedSet(idx.matchLabels(opts.LabelSelector))
+	}
+	items, token := pageOf(uids, after, opts.Limit, func(uid string) (json.RawMessage, bool) {
+		raw, ok := idx.entries[uid].data()
+		return copyRaw(raw), ok
+	})
+	return ListPage[json.RawMessage]{Items: items, ContinueToken: token, Total: len(uids)}, nil
+}
+
+// LoadMatching implements IndexedBackend.LoadMatching
+func (f *FileBackend) LoadMatching(ctx context.Context, resourceType string, match func(doc map[string]interface{}) bool) ([]json.RawMessage, error) {
+	idx, err := f.readIndex(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	results := []json.RawMessage{}
+	for _, uid := range idx.uids {
+		e := idx.entries[uid]
+		raw, ok := e.data()
+		if !ok {
+			continue
+		}
+		// Documents are decoded once and kept until the resource changes
+		e.docOnce.Do(func() {
+			_ = json.Unmarshal(raw, &e.doc)
+		})
+		if e.doc != nil && match(e.doc) {
+			results = append(results, copyRaw(raw))
+		}
+	}
+	return results, nil
+}
+
+// Each implements IterableBackend.Each. The index isn't locked while fn
+// runs, so fn may be slow (e.g., writing to a client) without blocking saves.
+func (f *FileBackend) Each(ctx context.Context, resourceType string, fn func(data json.RawMessage) error) error {
+	idx, err := f.readIndex(ctx, resourceType)
+	if err != nil {
+		return err
+	}
+	for _, uid := range idx.list() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, ok := idx.get(uid)
+		if !ok {
+			continue
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readIndex returns the index of a type for a read operation
+func (f *FileBackend) readIndex(ctx context.Context, resourceType string) (*fileIndex, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if err := f.checkClosed(); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	return f.index(ctx, resourceType)
+}
+
+// index returns the index of a type, building it from disk on first use.
+// The caller holds f.mu.
+func (f *FileBackend) index(ctx context.Context, resourceType string) (*fileIndex, error) {
+	f.indexMu.RLock()
+	idx, ok := f.indexes[resourceType]
+	f.indexMu.RUnlock()
+	if ok {
+		return idx, nil
+	}
+
+	f.indexMu.Lock()
+	defer f.indexMu.Unlock()
+
+	if idx, ok := f.indexes[resourceType]; ok {
+		return idx, nil
+	}
+
+	idx = newFileIndex()
+	dirPath := f.getDirPath(resourceType)
+	entries, err := os.ReadDir(dirPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dirPath, err)
+	}
+	if f.journal != nil {
+		if err := f.journal.restore(ctx, idx, resourceType, dirPath, entries); err != nil {
+			return nil, err
+		}
+		entries = nil
+	}
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, ok := readResourceFile(filepath.Join(dirPath, entry.Name()))
+		if !ok {
+			// Skip unreadable or corrupted files, as LoadAll always has
+			continue
+		}
+		idx.put(strings.TrimSuffix(entry.Name(), ".json"), data)
+	}
+
+	if f.indexes == nil {
+		f.indexes = map[string]*fileIndex{}
+	}
+	f.indexes[resourceType] = idx
+	return idx, nil
+}
+
+// get returns the stored JSON of a resource
+func (idx *fileIndex) get(uid string) (json.RawMessage, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	e, ok := idx.entries[uid]
+	if !ok {
+		return nil, false
+	}
+	raw, ok := e.data()
+	if !ok {
+		return nil, false
+	}
+	return copyRaw(raw), true
+}
+
+// all returns the stored JSON of every resource, in UID order
+func (idx *fileIndex) all() []json.RawMessage {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.collectAll()
+}
+
+// list returns the UIDs of every resource, in order
+func (idx *fileIndex) list() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return append([]string{}, idx.uids...)
+}
+
+// collectAll copies the JSON of every resource in UID order.
+// The caller holds idx.mu.
+func (idx *fileIndex) collectAll() []json.RawMessage {
+	results := make([]json.RawMessage, 0, len(idx.uids))
+	for _, uid := range idx.uids {
+		if raw, ok := idx.entries[uid].data(); ok {
+			results = append(results, copyRaw(raw))
+		}
+	}
+	return results
+}
+
+// collect copies the JSON of a set of UIDs in UID order.
+// The caller holds idx.mu.
+func (idx *fileIndex) collect(set map[string]struct{}) []json.RawMessage {
+	uids := sortedSet(set)
+	results := make([]json.RawMessage, 0, len(uids))
+	for _, uid := range uids {
+		if raw, ok := idx.entries[uid].data(); ok {
+			results = append(results, copyRaw(raw))
+		}
+	}
+	return results
+}
+
+// matchLabels returns the UIDs of the resources whose labels include every
+// pair in a non-empty selector. The caller holds idx.mu.
+func (idx *fileIndex) matchLabels(selector map[string]string) map[string]struct{} {
+	// Start from the smallest label set and check the others per resource
+	var smallest map[string]struct{}
+	for k, v := range selector {
+		uids, ok := idx.byLabel[k+"="+v]
+		if !ok {
+			return nil
+		}
+		if smallest == nil || len(uids) < len(smallest) {
+			smallest = uids
+		}
+	}
+	matches := make(map[string]struct{}, len(smallest))
+	for uid := range smallest {
+		if labelsMatch(idx.entries[uid].labels, selector) {
+			matches[uid] = struct{}{}
+		}
+	}
+	return matches
+}
+
+// sortedSet returns the UIDs of a set in order
+func sortedSet(set map[string]struct{}) []string {
+	uids := make([]string, 0, len(set))
+	for uid := range set {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// data returns the stored JSON of an entry, reading it on first access for
+// entries indexed from the index file
+func (e *indexEntry) data() (json.RawMessage, bool) {
+	e.rawOnce.Do(func() {
+		if e.raw != nil {
+			return
+		}
+		if data, ok := readResourceFile(e.path); ok {
+			e.raw = data
+		}
+	})
+	return e.raw, e.raw != nil
+}
+
+// put adds or replaces a resource
+func (idx *fileIndex) put(uid string, data json.RawMessage) {
+	var meta indexedMetadata
+	_ = json.Unmarshal(data, &meta) // resources without metadata are indexed by UID only
+	idx.insert(uid, &indexEntry{raw: copyRaw(data), name: meta.Metadata.Name, labels: meta.Metadata.Labels})
+}
+
+// insert adds or replaces the entry of a resource
+func (idx *fileIndex) insert(uid string, e *indexEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.entries[uid]; ok {
+		idx.unlink(uid)
+	} else {
+		i := sort.SearchStrings(idx.uids, uid)
+		idx.uids = append(idx.uids, "")
+		copy(idx.uids[i+1:], idx.uids[i:])
+		idx.uids[i] = uid
+	}
+	idx.entries[uid] = e
+
+	addToSet(idx.byName, e.name, uid)
+	for k, v := range e.labels {
+		addToSet(idx.byLabel, k+"="+v, uid)
+	}
+}
+
+// remove drops a resource
+func (idx *fileIndex) remove(uid string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.entries[uid]; !ok {
+		return
+	}
+	idx.unlink(uid)
+	delete(idx.entries, uid)
+	if i := sort.SearchStrings(idx.uids, uid); i < len(idx.uids) && idx.uids[i] == uid {
+		idx.uids = append(idx.uids[:i], idx.uids[i+1:]...)
+	}
+}
+
+// unlink removes a resource from the name and label sets. The caller holds idx.mu.
+func (idx *fileIndex) unlink(uid string) {
+	e := idx.entries[uid]
+	removeFromSet(idx.byName, e.name, uid)
+	for k, v := range e.labels {
+		removeFromSet(idx.byLabel, k+"="+v, uid)
+	}
+}
+
+func addToSet(sets map[string]map[string]struct{}, key, uid string) {
+	if sets[key] == nil {
+		sets[key] = map[string]struct{}{}
+	}
+	sets[key][uid] = struct{}{}
+}
+
+func removeFromSet(sets map[string]map[string]struct{}, key, uid string) {
+	delete(sets[key], uid)
+	if len(sets[key]) == 0 {
+		delete(sets, key)
+	}
+}
+
+// labelsMatch reports whether labels include every pair in selector
+func labelsMatch(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,332 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IndexFileName is the name of the index file FileBackend.EnableIndexFile
+// keeps in the base directory
+const IndexFileName = ".index.jsonl"
+
+// indexCompactMin is the number of records below which the index file is
+// never compacted
+const indexCompactMin = 1024
+
+// indexRecord is one line of the index file: the indexed metadata of a
+// saved resource, or a deletion
+type indexRecord struct {
+	Type    string            `json:"type"`
+	UID     string            `json:"uid"`
+	Deleted bool              `json:"deleted,omitempty"`
+	Path    string            `json:"path,omitempty"` // relative to the base directory
+	Name    string            `json:"name,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Size    int64             `json:"size,omitempty"`
+	ModTime int64             `json:"mtime,omitempty"` // Unix nanoseconds
+}
+
+// indexJournal is the index file of a FileBackend: an append-only log of
+// index records. It is replayed when the backend starts and rewritten with
+// only the current records once most of its records are outdated.
+type indexJournal struct {
+	mu      sync.Mutex
+	path    string
+	baseDir string
+	file    *os.File
+	records int                               // lines in the file
+	live    map[string]map[string]indexRecord // type -> UID -> current record
+	count   int                               // records in live
+}
+
+// EnableIndexFile keeps the metadata of the index (each resource's path,
+// name and labels, by type) in an index file, IndexFileName in the base
+// directory, updated by every Save and Delete.
+//
+// Indexing a type then reads only the files written since the index file
+// last recorded them, found by their size and modification time, instead
+// of every file; the JSON of other resources is read on first access. This
+// keeps startup fast for large collections. Files added, changed or removed
+// while the backend wasn't running are still picked up.
+//
+// It must be called before the first operation on the backend.
+//
+// Example:
+//
+//	backend, _ := storage.NewFileBackend("./data")
+//	if err := backend.EnableIndexFile(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	err := backend.BuildIndex(ctx, "Device", "Rack")
+func (f *FileBackend) EnableIndexFile() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkClosed(); err != nil {
+		return err
+	}
+	f.indexMu.Lock()
+	defer f.indexMu.Unlock()
+
+	if f.journal != nil {
+		return nil
+	}
+	if len(f.indexes) > 0 {
+		return fmt.Errorf("the index file must be enabled before resources are indexed")
+	}
+	journal, err := openIndexJournal(f.baseDir)
+	if err != nil {
+		return err
+	}
+	f.journal = journal
+	return nil
+}
+
+// openIndexJournal replays the index file of a base directory, creating it
+// if it doesn't exist
+func openIndexJournal(baseDir string) (*indexJournal, error) {
+	j := &indexJournal{
+		path:    filepath.Join(baseDir, IndexFileName),
+		baseDir: baseDir,
+		live:    map[string]map[string]indexRecord{},
+	}
+
+	torn := false
+	data, err := os.ReadFile(j.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read index file %s: %w", j.path, err)
+	}
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var rec indexRecord
+			if line[len(line)-1] != '\n' || json.Unmarshal(line, &rec) != nil || rec.Type == "" || rec.UID == "" {
+				// A write cut short by a crash; the directory scan of
+				// the type recovers the resource
+				torn = true
+			} else {
+				j.apply(rec)
+				j.records++
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	if torn {
+		// Rewrite the file so new records don't follow a partial line
+		if err := j.compact(); err != nil {
+			return nil, err
+		}
+		return j, nil
+	}
+	j.file, err = os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index file %s: %w", j.path, err)
+	}
+	return j, nil
+}
+
+// apply updates the current records with a record. The caller holds j.mu
+// or owns j.
+func (j *indexJournal) apply(rec indexRecord) {
+	records := j.live[rec.Type]
+	_, existed := records[rec.UID]
+	if rec.Deleted {
+		if existed {
+			delete(records, rec.UID)
+			j.count--
+		}
+		return
+	}
+	if records == nil {
+		records = map[string]indexRecord{}
+		j.live[rec.Type] = records
+	}
+	records[rec.UID] = rec
+	if !existed {
+		j.count++
+	}
+}
+
+// put records a saved resource, from its file's JSON and information
+func (j *indexJournal) put(resourceType, uid, path string, data json.RawMessage, info os.FileInfo) error {
+	var meta indexedMetadata
+	_ = json.Unmarshal(data, &meta)
+	rel, err := filepath.Rel(j.baseDir, path)
+	if err != nil {
+		rel = path
+	}
+	return j.append(indexRecord{
+		Type:    resourceType,
+		UID:     uid,
+		Path:    filepath.ToSlash(rel),
+		Name:    meta.Metadata.Name,
+		Labels:  meta.Metadata.Labels,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+	})
+}
+
+// delete records a deleted resource
+func (j *indexJournal) delete(resourceType, uid string) error {
+	return j.append(indexRecord{Type: resourceType, UID: uid, Deleted: true})
+}
+
+// append writes a record to the index file, compacting it when most of its
+// records are outdated
+func (j *indexJournal) append(rec indexRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if rec.Deleted {
+		if _, ok := j.live[rec.Type][rec.UID]; !ok {
+			return nil
+		}
+	}
+	if j.file == nil {
+		return fmt.Errorf("index file %s is closed", j.path)
+	}
+	if _, err := j.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write index file %s: %w", j.path, err)
+	}
+	j.apply(rec)
+	j.records++
+	if j.records > indexCompactMin && j.records > 2*j.count {
+		return j.compact()
+	}
+	return nil
+}
+
+// compact replaces the index file with the current records, atomically.
+// The caller holds j.mu or owns j.
+func (j *indexJournal) compact() error {
+	var buf bytes.Buffer
+	types := make([]string, 0, len(j.live))
+	for resourceType := range j.live {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	for _, resourceType := range types {
+		uids := make([]string, 0, len(j.live[resourceType]))
+		for uid := range j.live[resourceType] {
+			uids = append(uids, uid)
+		}
+		sort.Strings(uids)
+		for _, uid := range uids {
+			line, err := json.Marshal(j.live[resourceType][uid])
+			if err != nil {
+				return err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+	}
+
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+	if err := WriteFileAtomic(j.path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to compact index file: %w", err)
+	}
+	file, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open index file %s: %w", j.path, err)
+	}
+	j.file = file
+	j.records = j.count
+	return nil
+}
+
+// restore indexes a type from its directory entries: files matching their
+// record are indexed from it, others are read and recorded, and records of
+// missing files are deleted
+func (j *indexJournal) restore(ctx context.Context, idx *fileIndex, resourceType, dirPath string, entries []os.DirEntry) error {
+	j.mu.Lock()
+	records := make(map[string]indexRecord, len(j.live[resourceType]))
+	for uid, rec := range j.live[resourceType] {
+		records[uid] = rec
+	}
+	j.mu.Unlock()
+
+	indexed := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		uid := strings.TrimSuffix(entry.Name(), ".json")
+		path := filepath.Join(dirPath, entry.Name())
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if rec, ok := records[uid]; ok && rec.Size == info.Size() && rec.ModTime == info.ModTime().UnixNano() {
+			idx.insert(uid, &indexEntry{name: rec.Name, labels: rec.Labels, path: path})
+			indexed[uid] = true
+			continue
+		}
+
+		// Written since it was last recorded
+		data, ok := readResourceFile(path)
+		if !ok {
+			// Skip unreadable or corrupted files, as LoadAll always has
+			continue
+		}
+		idx.put(uid, data)
+		indexed[uid] = true
+		if err := j.put(resourceType, uid, path, data, info); err != nil {
+			return err
+		}
+	}
+
+	for uid := range records {
+		if !indexed[uid] {
+			if err := j.delete(resourceType, uid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// close closes the index file
+func (j *indexJournal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
@@ -0,0 +1,145 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newIndexedBackend opens a FileBackend with an index file and indexes Device
+func newIndexedBackend(t *testing.T, dir string) *FileBackend {
+	t.Helper()
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.EnableIndexFile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.BuildIndex(context.Background(), "Device"); err != nil {
+		t.Fatal(err)
+	}
+	return backend
+}
+
+func TestFileBackendIndexFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	first := newIndexedBackend(t, dir)
+	first.Save(ctx, "Device", "dev-1", device("dev-1", "node-1", map[string]string{"rack": "r1"}, "x1")) // nolint:errcheck
+	first.Save(ctx, "Device", "dev-2", device("dev-2", "node-2", map[string]string{"rack": "r1"}, "x1")) // nolint:errcheck
+	first.Save(ctx, "Device", "dev-3", device("dev-3", "node-3", map[string]string{"rack": "r2"}, "x1")) // nolint:errcheck
+	first.Save(ctx, "Device", "dev-4", device("dev-4", "node-4", nil, "x1"))                             // nolint:errcheck
+	first.Delete(ctx, "Device", "dev-4")                                                                 // nolint:errcheck
+	first.Close()
+
+	// Changes made while the backend isn't running
+	devices := filepath.Join(dir, "devices")
+	if err := os.WriteFile(filepath.Join(devices, "dev-2.json"), device("dev-2", "node-2", map[string]string{"rack": "r2", "changed": "yes"}, "x1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(devices, "dev-3.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(devices, "dev-5.json"), device("dev-5", "node-5", map[string]string{"rack": "r1"}, "x1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := newIndexedBackend(t, dir)
+	defer backend.Close()
+
+	// The unchanged resource is indexed from the index file without reading it
+	if e := backend.indexes["Device"].entries["dev-1"]; e == nil || e.raw != nil {
+		t.Fatalf("expected dev-1 to be indexed from the index file, got %+v", e)
+	}
+	if e := backend.indexes["Device"].entries["dev-2"]; e == nil || e.raw == nil {
+		t.Fatalf("expected the changed dev-2 to be read, got %+v", e)
+	}
+
+	raw, err := backend.LoadByLabels(ctx, "Device", map[string]string{"rack": "r1"})
+	assertUIDs(t, "LoadByLabels(rack=r1)", raw, err, "dev-1", "dev-5")
+	raw, err = backend.LoadByLabels(ctx, "Device", map[string]string{"rack": "r2"})
+	assertUIDs(t, "LoadByLabels(rack=r2)", raw, err, "dev-2")
+	raw, err = backend.LoadByName(ctx, "Device", "node-1")
+	assertUIDs(t, "LoadByName(node-1)", raw, err, "dev-1")
+	raw, err = backend.LoadAll(ctx, "Device")
+	assertUIDs(t, "LoadAll", raw, err, "dev-1", "dev-2", "dev-5")
+
+	// The index file was brought up to date
+	journal, err := openIndexJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.close()
+	if got := len(journal.live["Device"]); got != 3 {
+		t.Errorf("index file has %d Device records, want 3: %v", got, journal.live["Device"])
+	}
+	if rec := journal.live["Device"]["dev-2"]; rec.Labels["changed"] != "yes" || rec.Path != "devices/dev-2.json" {
+		t.Errorf("dev-2 record = %+v", rec)
+	}
+}
+
+func TestFileBackendIndexFileCompaction(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend := newIndexedBackend(t, dir)
+
+	for i := 0; i < 3*indexCompactMin; i++ {
+		if err := backend.Save(ctx, "Device", "dev-1", device("dev-1", "node-1", nil, "x1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backend.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, IndexFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines > indexCompactMin+1 {
+		t.Errorf("index file has %d lines after compaction, want at most %d", lines, indexCompactMin+1)
+	}
+
+	backend = newIndexedBackend(t, dir)
+	defer backend.Close()
+	raw, err := backend.LoadAll(ctx, "Device")
+	assertUIDs(t, "LoadAll after compaction", raw, err, "dev-1")
+}
+
+func TestFileBackendIndexFileTornWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	first := newIndexedBackend(t, dir)
+	first.Save(ctx, "Device", "dev-1", device("dev-1", "node-1", nil, "x1")) // nolint:errcheck
+	first.Close()
+
+	// A crash while appending leaves a partial line
+	f, err := os.OpenFile(filepath.Join(dir, IndexFileName), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"type":"Device","uid":"dev-`) // nolint:errcheck
+	f.Close()
+
+	backend := newIndexedBackend(t, dir)
+	if err := backend.Save(ctx, "Device", "dev-2", device("dev-2", "node-2", nil, "x1")); err != nil {
+		t.Fatal(err)
+	}
+	backend.Close()
+
+	journal, err := openIndexJournal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.close()
+	if got := len(journal.live["Device"]); got != 2 {
+		t.Errorf("index file has %d Device records after a torn write, want 2", got)
+	}
+}
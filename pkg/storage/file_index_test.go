@@ -0,0 +1,213 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func device(uid, name string, labels map[string]string, model string) json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"uid": uid, "name": name, "labels": labels},
+		"spec":     map[string]interface{}{"model": model},
+	})
+	return data
+}
+
+func uidsOf(t *testing.T, raw []json.RawMessage) []string {
+	t.Helper()
+	uids := make([]string, 0, len(raw))
+	for _, r := range raw {
+		var doc struct {
+			Metadata struct {
+				UID string `json:"uid"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(r, &doc); err != nil {
+			t.Fatal(err)
+		}
+		uids = append(uids, doc.Metadata.UID)
+	}
+	return uids
+}
+
+func assertUIDs(t *testing.T, what string, raw []json.RawMessage, err error, want ...string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", what, err)
+	}
+	got := uidsOf(t, raw)
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s = %v, want %v", what, got, want)
+		}
+	}
+}
+
+func TestFileBackendIndex(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Resources written before the backend starts are indexed
+	first, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Save(ctx, "Device", "dev-2", device("dev-2", "node-1", map[string]string{"rack": "r1", "role": "compute"}, "x1")) // nolint:errcheck
+	first.Save(ctx, "Device", "dev-1", device("dev-1", "node-1", map[string]string{"rack": "r2", "role": "compute"}, "x2")) // nolint:errcheck
+	first.Save(ctx, "Device", "dev-3", device("dev-3", "node-3", map[string]string{"rack": "r1"}, "x1"))                    // nolint:errcheck
+	first.Close()
+
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	if err := backend.BuildIndex(ctx, "Device"); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := backend.LoadByName(ctx, "Device", "node-1")
+	assertUIDs(t, "LoadByName(node-1)", raw, err, "dev-1", "dev-2")
+	raw, err = backend.LoadByName(ctx, "Device", "missing")
+	assertUIDs(t, "LoadByName(missing)", raw, err)
+
+	raw, err = backend.LoadByLabels(ctx, "Device", map[string]string{"rack": "r1"})
+	assertUIDs(t, "LoadByLabels(rack=r1)", raw, err, "dev-2", "dev-3")
+	raw, err = backend.LoadByLabels(ctx, "Device", map[string]string{"rack": "r1", "role": "compute"})
+	assertUIDs(t, "LoadByLabels(rack=r1,role=compute)", raw, err, "dev-2")
+	raw, err = backend.LoadByLabels(ctx, "Device", map[string]string{"rack": "r9"})
+	assertUIDs(t, "LoadByLabels(rack=r9)", raw, err)
+	raw, err = backend.LoadByLabels(ctx, "Device", nil)
+	assertUIDs(t, "LoadByLabels(nil)", raw, err, "dev-1", "dev-2", "dev-3")
+
+	byModel := func(model string) func(map[string]interface{}) bool {
+		return func(doc map[string]interface{}) bool {
+			spec, _ := doc["spec"].(map[string]interface{})
+			return spec["model"] == model
+		}
+	}
+	raw, err = backend.LoadMatching(ctx, "Device", byModel("x1"))
+	assertUIDs(t, "LoadMatching(x1)", raw, err, "dev-2", "dev-3")
+
+	// Writes update the index, including the decoded documents
+	backend.Save(ctx, "Device", "dev-3", device("dev-3", "node-1", map[string]string{"rack": "r2"}, "x2")) // nolint:errcheck
+	backend.Delete(ctx, "Device", "dev-2")                                                                 // nolint:errcheck
+
+	raw, err = backend.LoadByName(ctx, "Device", "node-1")
+	assertUIDs(t, "LoadByName after writes", raw, err, "dev-1", "dev-3")
+	raw, err = backend.LoadByLabels(ctx, "Device", map[string]string{"rack": "r1"})
+	assertUIDs(t, "LoadByLabels after writes", raw, err)
+	raw, err = backend.LoadMatching(ctx, "Device", byModel("x2"))
+	assertUIDs(t, "LoadMatching after writes", raw, err, "dev-1", "dev-3")
+	raw, err = backend.LoadAll(ctx, "Device")
+	assertUIDs(t, "LoadAll after writes", raw, err, "dev-1", "dev-3")
+	if uids, _ := backend.List(ctx, "Device"); len(uids) != 2 || uids[0] != "dev-1" || uids[1] != "dev-3" {
+		t.Errorf("List after writes = %v", uids)
+	}
+}
+
+func TestFileBackendIndexServesReadsFromMemory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+
+	if err := backend.Save(ctx, "Device", "dev-1", device("dev-1", "a", nil, "x1")); err != nil {
+		t.Fatal(err)
+	}
+	// Removing the file behind the backend's back doesn't affect reads
+	if err := os.Remove(filepath.Join(dir, "devices", "dev-1.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := backend.Load(ctx, "Device", "dev-1"); err != nil {
+		t.Errorf("expected Load to be served from the index, got %v", err)
+	}
+	if exists, _ := backend.Exists(ctx, "Device", "dev-1"); !exists {
+		t.Error("expected Exists to be served from the index")
+	}
+
+	// Returned data must not alias the index
+	raw, _ := backend.Load(ctx, "Device", "dev-1")
+	raw[0] = 'x'
+	if raw, _ := backend.Load(ctx, "Device", "dev-1"); !json.Valid(raw) {
+		t.Error("modifying loaded data changed the index")
+	}
+}
+
+// noVersions is a VersionRegistry without versioned types
+type noVersions struct{}
+
+func (noVersions) GetDefaultVersion(string) string               { return "" }
+func (noVersions) GetVersion(string, string) (VersionInfo, bool) { return nil, false }
+
+func TestFileBackendLocking(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	backend.SetVersionRegistry(noVersions{})
+
+	if err := backend.Save(ctx, "Device", "dev-1", device("dev-1", "a", nil, "x1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Find a resource that uses a different write lock than dev-1
+	other := ""
+	for i := 0; other == ""; i++ {
+		uid := "dev-" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+		if backend.resourceLock("Device", uid) != backend.resourceLock("Device", "dev-1") {
+			other = uid
+		}
+	}
+
+	// While dev-1 is being written, other resources can be written and dev-1 can be read
+	lock := backend.resourceLock("Device", "dev-1")
+	lock.Lock()
+	done := make(chan error, 3)
+	go func() { done <- backend.Save(ctx, "Device", other, device(other, "b", nil, "x1")) }()
+	go func() { _, err := backend.Load(ctx, "Device", "dev-1"); done <- err }()
+	go func() {
+		done <- backend.SaveWithVersion(ctx, "Rack", "rack-1", device("rack-1", "r", nil, ""), "")
+	}()
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("operation blocked behind another resource's write")
+		}
+	}
+
+	// Writes to dev-1 wait for its lock
+	saved := make(chan error, 1)
+	go func() { saved <- backend.Save(ctx, "Device", "dev-1", device("dev-1", "a2", nil, "x1")) }()
+	select {
+	case <-saved:
+		t.Fatal("expected the write to dev-1 to wait for its lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	lock.Unlock()
+	if err := <-saved; err != nil {
+		t.Fatal(err)
+	}
+	raw, err := backend.LoadByName(ctx, "Device", "a2")
+	assertUIDs(t, "LoadByName(a2)", raw, err, "dev-1")
+}
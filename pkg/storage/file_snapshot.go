@@ -0,0 +1,308 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var _ SnapshotBackend = (*FileBackend)(nil)
+
+// SnapshotMaxFileSize is the largest resource file Restore accepts
+const SnapshotMaxFileSize = 64 << 20
+
+// snapshotFile is a resource file read for a snapshot
+type snapshotFile struct {
+	name    string // slash-separated path relative to the base directory
+	data    []byte
+	modTime time.Time
+}
+
+// Snapshot implements SnapshotBackend.Snapshot. The archive is a tar archive
+// of the resource files of every type, uncompressed, at their paths in the
+// base directory (e.g. devices/dev-1a2b3c4d.json), so extracting it gives a
+// data directory NewFileBackend can open. Its documents are the stored
+// resources, so it can also be imported with the backup tar format.
+//
+// Writes wait while the files are read, and reads don't. The archive is
+// written to w after writes resume, so a slow w doesn't hold them up.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	if err := backend.Snapshot(ctx, &buf); err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *FileBackend) Snapshot(ctx context.Context, w io.Writer) error {
+	files, err := f.snapshotFiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    file.name,
+			Mode:    0644,
+			Size:    int64(len(file.data)),
+			ModTime: file.modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotFiles reads every resource file of the base directory with writes
+// paused
+func (f *FileBackend) snapshotFiles(ctx context.Context) ([]snapshotFile, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if err := f.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	// Writes hold the lock of their resource's stripe, so holding every
+	// stripe (in order, as writes take one at a time) pauses them all
+	for i := range f.stripes {
+		f.stripes[i].Lock()
+		defer f.stripes[i].Unlock()
+	}
+
+	var files []snapshotFile
+	err := filepath.WalkDir(f.baseDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		rel, err := filepath.Rel(f.baseDir, filePath)
+		if err != nil || rel == "." {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if entry.IsDir() {
+			if !snapshotName(name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !snapshotName(name) || !strings.Contains(name, "/") || path.Ext(name) != ".json" {
+			return nil
+		}
+
+		data, ok := readResourceFile(filePath)
+		if !ok {
+			// Skip unreadable or corrupted files, as LoadAll always has
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to stat file %s: %w", filePath, err)
+		}
+		files = append(files, snapshotFile{name: name, data: data, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s for snapshot: %w", f.baseDir, err)
+	}
+	return files, nil
+}
+
+// snapshotName reports whether a slash-separated path relative to the base
+// directory may hold resources: paths with a hidden element, such as the
+// index file and the directories of Restore, don't
+func snapshotName(name string) bool {
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// Restore implements SnapshotBackend.Restore. The archive is extracted to a
+// directory next to the resource directories first, so an invalid archive
+// leaves them unchanged; the resource directories are then swapped for the
+// extracted ones. Operations wait while they are swapped. Restored files are
+// compressed as EnableCompression configured.
+//
+// An empty stream is rejected, while the archive of an empty backend
+// removes every resource. Watches don't report the restored changes.
+//
+// Example:
+//
+//	in, _ := os.Open("snapshot.tar")
+//	defer in.Close()
+//	if err := backend.Restore(ctx, in); err != nil {
+//	    log.Fatal(err)
+//	}
+func (f *FileBackend) Restore(ctx context.Context, r io.Reader) error {
+	staging, err := os.MkdirTemp(f.baseDir, ".restore-")
+	if err != nil {
+		return fmt.Errorf("failed to create restore directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := f.extractSnapshot(ctx, r, staging); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.checkClosed(); err != nil {
+		return err
+	}
+
+	// The replaced directories are kept until the restored ones are in
+	// place, so a failed swap can be undone
+	replaced, err := os.MkdirTemp(f.baseDir, ".replaced-")
+	if err != nil {
+		return fmt.Errorf("failed to create restore directory: %w", err)
+	}
+	defer os.RemoveAll(replaced)
+
+	moved, err := moveResourceDirs(f.baseDir, replaced)
+	if err != nil {
+		_, _ = moveNamedDirs(replaced, f.baseDir, moved)
+		return err
+	}
+	restored, err := moveResourceDirs(staging, f.baseDir)
+	if err != nil {
+		_, _ = moveNamedDirs(f.baseDir, staging, restored)
+		_, _ = moveNamedDirs(replaced, f.baseDir, moved)
+		return err
+	}
+	syncDir(f.baseDir)
+
+	// Indexes are rebuilt from the restored files on next use; the index
+	// file catches up, as the files' sizes and times don't match its records
+	f.indexMu.Lock()
+	f.indexes = nil
+	f.indexMu.Unlock()
+	return nil
+}
+
+// extractSnapshot writes the resource files of a snapshot archive to dir
+func (f *FileBackend) extractSnapshot(ctx context.Context, r io.Reader, dir string) error {
+	counter := &countingReader{r: r}
+	tr := tar.NewReader(counter)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid snapshot (%v): %w", err, ErrInvalidData)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if !filepath.IsLocal(name) || !strings.Contains(name, "/") || path.Ext(name) != ".json" || !snapshotName(name) {
+			return fmt.Errorf("invalid snapshot file %q: %w", header.Name, ErrInvalidData)
+		}
+		if header.Size > SnapshotMaxFileSize {
+			return fmt.Errorf("snapshot file %s exceeds %d bytes: %w", name, SnapshotMaxFileSize, ErrInvalidData)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot (%v): %w", err, ErrInvalidData)
+		}
+		if data, err = Decompress(data); err != nil || !json.Valid(data) {
+			return fmt.Errorf("snapshot file %s is not valid JSON: %w", name, ErrInvalidData)
+		}
+		if f.compression != "" {
+			if data, err = Compress(f.compression, data); err != nil {
+				return err
+			}
+		}
+
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(filePath), err)
+		}
+		if err := WriteFileAtomic(filePath, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	if counter.n == 0 {
+		return fmt.Errorf("empty snapshot: %w", ErrInvalidData)
+	}
+	return nil
+}
+
+// moveResourceDirs moves the resource directories of from to to, returning
+// the names of those it moved
+func moveResourceDirs(from, to string) ([]string, error) {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", from, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && snapshotName(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	return moveNamedDirs(from, to, names)
+}
+
+// moveNamedDirs moves the named entries of from to to, returning the names
+// of those it moved
+func moveNamedDirs(from, to string, names []string) ([]string, error) {
+	moved := make([]string, 0, len(names))
+	for _, name := range names {
+		if err := os.Rename(filepath.Join(from, name), filepath.Join(to, name)); err != nil {
+			return moved, fmt.Errorf("failed to move %s: %w", name, err)
+		}
+		moved = append(moved, name)
+	}
+	return moved, nil
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
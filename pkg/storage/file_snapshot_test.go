@@ -0,0 +1,128 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBackend_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	backend := mustFileBackend(t, t.TempDir())
+	if err := backend.EnableIndexFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSave := func(resourceType, uid, data string) {
+		t.Helper()
+		if err := backend.Save(ctx, resourceType, uid, json.RawMessage(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustSave("Device", "dev-1", `{"uid":"dev-1"}`)
+	mustSave("Device/tenant-a", "dev-2", `{"uid":"dev-2"}`)
+	mustSave("Rack", "rack-1", `{"uid":"rack-1"}`)
+
+	var snapshot bytes.Buffer
+	if err := Snapshot(ctx, backend, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes made after the snapshot are undone by restoring it
+	mustSave("Device", "dev-1", `{"uid":"dev-1","changed":true}`)
+	mustSave("Device", "dev-3", `{"uid":"dev-3"}`)
+	if err := backend.Delete(ctx, "Rack", "rack-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Restore(ctx, backend, bytes.NewReader(snapshot.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := backend.Load(ctx, "Device", "dev-1"); err != nil || string(data) != `{"uid":"dev-1"}` {
+		t.Errorf("Load(dev-1) = %s, %v; want the snapshot's version", data, err)
+	}
+	if _, err := backend.Load(ctx, "Device", "dev-3"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(dev-3) = %v, want ErrNotFound", err)
+	}
+	if uids, _ := backend.List(ctx, "Device/tenant-a"); len(uids) != 1 || uids[0] != "dev-2" {
+		t.Errorf("Expected dev-2 in tenant-a, got %v", uids)
+	}
+	if ok, _ := backend.Exists(ctx, "Rack", "rack-1"); !ok {
+		t.Error("Expected rack-1 to be restored")
+	}
+
+	// A new backend on the directory sees the restored resources, also
+	// through the index file
+	reopened := mustFileBackend(t, backend.baseDir)
+	if err := reopened.EnableIndexFile(); err != nil {
+		t.Fatal(err)
+	}
+	if uids, _ := reopened.List(ctx, "Device"); len(uids) != 1 || uids[0] != "dev-1" {
+		t.Errorf("Expected only dev-1 after reopening, got %v", uids)
+	}
+	reopened.Close()
+
+	// Restore leaves no directories of its own behind
+	entries, err := os.ReadDir(backend.baseDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !snapshotName(entry.Name()) {
+			t.Errorf("Restore left %s behind", entry.Name())
+		}
+	}
+}
+
+func TestFileBackend_RestoreInvalid(t *testing.T) {
+	ctx := context.Background()
+	backend := mustFileBackend(t, t.TempDir())
+	if err := backend.Save(ctx, "Device", "dev-1", json.RawMessage(`{"uid":"dev-1"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := func(name, data string) []byte {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}) //nolint:errcheck
+		tw.Write([]byte(data))                                                      //nolint:errcheck
+		tw.Close()                                                                  //nolint:errcheck
+		return buf.Bytes()
+	}
+	tests := map[string][]byte{
+		"empty":        nil,
+		"not a tar":    []byte("not a tar archive"),
+		"invalid JSON": archive("devices/dev-2.json", "{"),
+		"escaping":     archive("../devices/dev-2.json", "{}"),
+		"hidden":       archive(".index.jsonl/dev-2.json", "{}"),
+		"top-level":    archive("dev-2.json", "{}"),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := backend.Restore(ctx, bytes.NewReader(data)); !errors.Is(err, ErrInvalidData) {
+				t.Errorf("Restore = %v, want ErrInvalidData", err)
+			}
+			if ok, _ := backend.Exists(ctx, "Device", "dev-1"); !ok {
+				t.Error("An invalid snapshot changed the stored resources")
+			}
+			if _, err := os.Stat(filepath.Join(backend.baseDir, "devices", "dev-2.json")); !os.IsNotExist(err) {
+				t.Error("An invalid snapshot wrote a resource file")
+			}
+		})
+	}
+}
+
+func TestSnapshotUnsupported(t *testing.T) {
+	if err := Snapshot(context.Background(), NewMemoryBackend(), &bytes.Buffer{}); !errors.Is(err, ErrSnapshotsUnsupported) {
+		t.Errorf("Snapshot = %v, want ErrSnapshotsUnsupported", err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// AllKinds registers a storage hook for every resource kind.
+const AllKinds = "*"
+
+// BeforeSaveHook is called with a pointer to a resource before the generated
+// storage functions save it. It may change the resource; returning an error
+// aborts the save with it.
+type BeforeSaveHook func(ctx context.Context, kind string, resource interface{}) error
+
+// AfterLoadHook is called with a pointer to each resource the generated
+// storage functions load, before it is returned. It may change the resource;
+// returning an error fails the load with it.
+type AfterLoadHook func(ctx context.Context, kind string, resource interface{}) error
+
+// OnDeleteHook is called after the generated storage functions delete a
+// resource.
+type OnDeleteHook func(ctx context.Context, kind, uid string)
+
+// Hooks holds the instrumentation hooks of the generated storage functions,
+// so caching, metrics or replication can be attached to the storage layer
+// without editing generated code. Hooks are registered per resource kind
+// (or for AllKinds) and run in registration order, with AllKinds hooks
+// first. It is safe for concurrent use.
+//
+// Hooks run in the context of the storage call: within a transaction, a
+// BeforeSave hook's error rolls the transaction back, and OnDelete hooks run
+// before it commits.
+//
+// Usage:
+//
+//	hooks := storage.NewHooks()
+//	hooks.RegisterAfterLoad("Device", func(ctx context.Context, kind string, resource interface{}) error {
+//	    deviceCache.Add(resource.(*device.Device))
+//	    return nil
+//	})
+//	hooks.RegisterOnDelete(storage.AllKinds, func(ctx context.Context, kind, uid string) {
+//	    replicator.Delete(kind, uid)
+//	})
+//
+//	if err := hooks.BeforeSave(ctx, "Device", device); err != nil {
+//	    return err
+//	}
+type Hooks struct {
+	mu         sync.RWMutex
+	beforeSave map[string][]BeforeSaveHook
+	afterLoad  map[string][]AfterLoadHook
+	onDelete   map[string][]OnDeleteHook
+}
+
+// NewHooks returns a Hooks without hooks.
+func NewHooks() *Hooks {
+	return &Hooks{
+		beforeSave: make(map[string][]BeforeSaveHook),
+		afterLoad:  make(map[string][]AfterLoadHook),
+		onDelete:   make(map[string][]OnDeleteHook),
+	}
+}
+
+// RegisterBeforeSave adds a hook run before resources of kind (or AllKinds)
+// are saved.
+//
+// Panics on a nil hook, as registration happens at startup.
+func (h *Hooks) RegisterBeforeSave(kind string, hook BeforeSaveHook) {
+	if hook == nil {
+		panic("storage: nil BeforeSave hook")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.beforeSave[kind] = append(h.beforeSave[kind], hook)
+}
+
+// RegisterAfterLoad adds a hook run on each loaded resource of kind (or
+// AllKinds).
+//
+// Panics on a nil hook, as registration happens at startup.
+func (h *Hooks) RegisterAfterLoad(kind string, hook AfterLoadHook) {
+	if hook == nil {
+		panic("storage: nil AfterLoad hook")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.afterLoad[kind] = append(h.afterLoad[kind], hook)
+}
+
+// RegisterOnDelete adds a hook run after resources of kind (or AllKinds)
+// are deleted.
+//
+// Panics on a nil hook, as registration happens at startup.
+func (h *Hooks) RegisterOnDelete(kind string, hook OnDeleteHook) {
+	if hook == nil {
+		panic("storage: nil OnDelete hook")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onDelete[kind] = append(h.onDelete[kind], hook)
+}
+
+// BeforeSave runs the BeforeSave hooks of kind on resource and returns the
+// first error, wrapped with the kind.
+func (h *Hooks) BeforeSave(ctx context.Context, kind string, resource interface{}) error {
+	h.mu.RLock()
+	hooks := forKind(h.beforeSave, kind)
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx, kind, resource); err != nil {
+			return fmt.Errorf("before-save hook of %s: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// AfterLoad runs the AfterLoad hooks of kind on resource and returns the
+// first error, wrapped with the kind.
+func (h *Hooks) AfterLoad(ctx context.Context, kind string, resource interface{}) error {
+	h.mu.RLock()
+	hooks := forKind(h.afterLoad, kind)
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(ctx, kind, resource); err != nil {
+			return fmt.Errorf("after-load hook of %s: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// OnDelete runs the OnDelete hooks of kind for the deleted resource uid.
+func (h *Hooks) OnDelete(ctx context.Context, kind, uid string) {
+	h.mu.RLock()
+	hooks := forKind(h.onDelete, kind)
+	h.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(ctx, kind, uid)
+	}
+}
+
+// forKind returns the AllKinds hooks followed by those of kind. The result
+// doesn't alias the registered slices, so it can be used after unlocking.
+func forKind[H any](hooks map[string][]H, kind string) []H {
+	all, own := hooks[AllKinds], hooks[kind]
+	if len(all)+len(own) == 0 {
+		return nil
+	}
+	return append(append(make([]H, 0, len(all)+len(own)), all...), own...)
+}
@@ -0,0 +1,80 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+	hooks := NewHooks()
+
+	var calls []string
+	record := func(name string) BeforeSaveHook {
+		return func(ctx context.Context, kind string, resource interface{}) error {
+			calls = append(calls, name+":"+kind)
+			return nil
+		}
+	}
+	hooks.RegisterBeforeSave("Device", record("device"))
+	hooks.RegisterBeforeSave(AllKinds, record("all"))
+
+	if err := hooks.BeforeSave(ctx, "Device", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := hooks.BeforeSave(ctx, "Rack", nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"all:Device", "device:Device", "all:Rack"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("BeforeSave calls = %v, want %v", calls, want)
+	}
+
+	// AfterLoad hooks may change the resource; the first error stops the rest
+	denied := errors.New("denied")
+	hooks.RegisterAfterLoad("Device", func(ctx context.Context, kind string, resource interface{}) error {
+		*resource.(*string) = "loaded"
+		return nil
+	})
+	hooks.RegisterAfterLoad("Device", func(ctx context.Context, kind string, resource interface{}) error {
+		return denied
+	})
+	hooks.RegisterAfterLoad("Device", func(ctx context.Context, kind string, resource interface{}) error {
+		t.Error("AfterLoad hook ran after an error")
+		return nil
+	})
+	value := ""
+	if err := hooks.AfterLoad(ctx, "Device", &value); !errors.Is(err, denied) {
+		t.Errorf("AfterLoad = %v, want the hook's error", err)
+	}
+	if value != "loaded" {
+		t.Errorf("AfterLoad hook didn't change the resource: %q", value)
+	}
+	if err := hooks.AfterLoad(ctx, "Rack", &value); err != nil {
+		t.Errorf("AfterLoad without hooks = %v", err)
+	}
+
+	var deleted []string
+	hooks.RegisterOnDelete(AllKinds, func(ctx context.Context, kind, uid string) {
+		deleted = append(deleted, kind+"/"+uid)
+	})
+	hooks.OnDelete(ctx, "Device", "dev-1")
+	hooks.OnDelete(ctx, "Rack", "rack-1")
+	if want := []string{"Device/dev-1", "Rack/rack-1"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("OnDelete calls = %v, want %v", deleted, want)
+	}
+}
+
+func TestHooksNilHook(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterBeforeSave(nil) didn't panic")
+		}
+	}()
+	NewHooks().RegisterBeforeSave("Device", nil)
+}
@@ -12,6 +12,13 @@
 //   - StorageBackend: Main interface for CRUD operations
 //   - ResourceStorage: Type-safe operations for specific resource types
 //   - FileStorage: File-based implementation (default)
+//   - MemoryBackend: In-memory implementation for tests and fake servers
+//   - IndexedBackend: Optional name, label and field lookups (FileBackend)
+//   - IterableBackend: Optional one-at-a-time iteration for streaming lists
+//   - PageableBackend: Optional pages of lists with continue tokens (see LoadPage)
+//   - TransactionalBackend: Optional atomic writes of several resources (see WithTx)
+//   - SnapshotBackend: Optional point-in-time archives of all resources (see Snapshot)
+//   - WatchableBackend: Optional change notifications (see Watch)
 //   - Future: DatabaseStorage, CloudStorage, etc.
 //
 // Usage:
@@ -46,6 +53,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
 // Common storage errors
@@ -53,6 +61,17 @@ var (
 	ErrNotFound      = fmt.Errorf("resource not found")
 	ErrAlreadyExists = fmt.Errorf("resource already exists")
 	ErrInvalidData   = fmt.Errorf("invalid data")
+
+	// ErrInvalidContinueToken is returned for malformed ListOptions.ContinueToken values
+	ErrInvalidContinueToken = fmt.Errorf("invalid continue token")
+
+	// ErrTransactionsUnsupported is returned by WithTx for backends that
+	// don't implement TransactionalBackend
+	ErrTransactionsUnsupported = fmt.Errorf("storage backend does not support transactions")
+
+	// ErrSnapshotsUnsupported is returned by Snapshot and Restore for
+	// backends that don't implement SnapshotBackend
+	ErrSnapshotsUnsupported = fmt.Errorf("storage backend does not support snapshots")
 )
 
 // StorageBackend defines the core storage operations that any storage implementation must provide.
@@ -301,6 +320,99 @@ type StorageBackend interface {
 	SaveWithVersion(ctx context.Context, resourceType, uid string, data json.RawMessage, version string) error
 }
 
+// IndexedBackend is implemented by backends that keep an index of resource
+// metadata, so lookups by name, labels or field values don't load and decode
+// every resource. FileBackend implements it.
+//
+// Callers should check for it with a type assertion and fall back to LoadAll:
+//
+//	if indexed, ok := backend.(storage.IndexedBackend); ok {
+//	    raw, err = indexed.LoadByName(ctx, "Device", "node-1")
+//	}
+type IndexedBackend interface {
+	StorageBackend
+
+	// LoadByName returns the resources whose metadata.name is name, in UID order.
+	LoadByName(ctx context.Context, resourceType, name string) ([]json.RawMessage, error)
+
+	// LoadByLabels returns the resources whose metadata.labels include every
+	// pair in selector, in UID order. An empty selector matches everything.
+	LoadByLabels(ctx context.Context, resourceType string, selector map[string]string) ([]json.RawMessage, error)
+
+	// LoadMatching returns the resources for which match returns true, in UID order.
+	// match receives the stored JSON decoded into a map, which it must not modify.
+	LoadMatching(ctx context.Context, resourceType string, match func(doc map[string]interface{}) bool) ([]json.RawMessage, error)
+}
+
+// IterableBackend is implemented by backends that can hand out the resources
+// of a type one at a time, so callers streaming large lists never hold every
+// decoded resource at once. FileBackend, MemoryBackend and the generated
+// EntBackend implement it.
+//
+// Callers should check for it with a type assertion and fall back to LoadAll:
+//
+//	if iterable, ok := backend.(storage.IterableBackend); ok {
+//	    err = iterable.Each(ctx, "Device", func(data json.RawMessage) error {
+//	        return enc.Encode(data)
+//	    })
+//	}
+type IterableBackend interface {
+	StorageBackend
+
+	// Each calls fn with the stored JSON of every resource of a type, in UID
+	// order. Resources deleted during the iteration are skipped. It stops at
+	// the first error returned by fn and returns it.
+	Each(ctx context.Context, resourceType string, fn func(data json.RawMessage) error) error
+}
+
+// PageableBackend is implemented by backends that can load one page of a
+// list without loading every resource of the type. FileBackend,
+// MemoryBackend and the generated EntBackend implement it.
+//
+// Callers should use LoadPage, which falls back to List and Load for other
+// backends.
+type PageableBackend interface {
+	StorageBackend
+
+	// LoadPage returns the resources of a type selected by opts, in UID order
+	LoadPage(ctx context.Context, resourceType string, opts ListOptions) (ListPage[json.RawMessage], error)
+}
+
+// TransactionalBackend is implemented by backends that can write several
+// resources atomically, such as the generated SQLBackend and EntBackend.
+//
+// Callers should use WithTx, which returns ErrTransactionsUnsupported for
+// other backends.
+type TransactionalBackend interface {
+	StorageBackend
+
+	// WithTx runs fn in a transaction. Calls to the backend with the context
+	// passed to fn read and write through the transaction, which commits when
+	// fn returns nil and rolls back when it returns an error or panics. A
+	// WithTx call with the context of a transaction joins it.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// SnapshotBackend is implemented by backends that can archive every stored
+// resource, of all types, at a point in time, and replace their content with
+// such an archive, such as FileBackend.
+//
+// Callers should use Snapshot and Restore, which return
+// ErrSnapshotsUnsupported for other backends.
+type SnapshotBackend interface {
+	StorageBackend
+
+	// Snapshot writes an archive of every stored resource to w, as stored
+	// at one point in time: writes made while it runs are not partially in
+	// the archive.
+	Snapshot(ctx context.Context, w io.Writer) error
+
+	// Restore replaces every stored resource with those of an archive
+	// written by Snapshot. An invalid archive returns an error wrapping
+	// ErrInvalidData and leaves the stored resources unchanged.
+	Restore(ctx context.Context, r io.Reader) error
+}
+
 // ResourceStorage provides type-safe storage operations for a specific resource type.
 //
 // This interface wraps StorageBackend to provide type safety and convenience
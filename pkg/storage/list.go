@@ -0,0 +1,169 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ListOptions selects a page of the resources of a type. Pages are in UID
+// order.
+type ListOptions struct {
+	// Limit is the maximum number of resources; zero returns every one
+	Limit int
+
+	// ContinueToken resumes a list after the previous page (see
+	// ListPage.ContinueToken); empty starts at the first resource
+	ContinueToken string
+
+	// LabelSelector selects the resources whose metadata.labels include
+	// every pair; empty selects all
+	LabelSelector map[string]string
+}
+
+// ListPage is a page of resources loaded with ListOptions.
+type ListPage[T any] struct {
+	// Items are the resources of the page, in UID order
+	Items []T
+
+	// ContinueToken is the token of the next page, or empty on the last page.
+	// The next page may be empty if resources were deleted meanwhile.
+	ContinueToken string
+
+	// Total is the number of resources selected across all pages, or -1 if
+	// the backend can't count them without loading them
+	Total int
+}
+
+// continueToken is the content of a continue token. It has the format of
+// the cursors of package pagination, so tokens of storage pages and of
+// pages built in memory are interchangeable. Tokens of lists sorted by other
+// fields have an order and are rejected.
+type continueToken struct {
+	After string `json:"after"`
+	Order string `json:"order,omitempty"`
+}
+
+// EncodeContinueToken returns the continue token of a list resuming after
+// the resource with the given UID.
+func EncodeContinueToken(after string) string {
+	raw, _ := json.Marshal(continueToken{After: after}) // nolint:errcheck
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeContinueToken returns the UID a continue token resumes after.
+// It returns ErrInvalidContinueToken for malformed tokens.
+func DecodeContinueToken(token string) (string, error) {
+	var c continueToken
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(raw, &c)
+	}
+	if err != nil || c.After == "" || c.Order != "" {
+		return "", ErrInvalidContinueToken
+	}
+	return c.After, nil
+}
+
+// LoadPage loads one page of the resources of a type.
+//
+// Backends implementing PageableBackend load the page directly. Other
+// backends list the UIDs of the type and load resources until the page is
+// full, so only the resources of the page (and, with a label selector, the
+// ones skipped) are loaded; Total is then -1 with a label selector.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - backend: Backend to load from
+//   - resourceType: Type name (e.g., "Device")
+//   - opts: Page size, continue token and label selector
+//
+// Returns:
+//   - ListPage: The page and the token of the next one
+//   - error: ErrInvalidContinueToken for a malformed token, or a backend error
+//
+// Example:
+//
+//	page, err := storage.LoadPage(ctx, backend, "Device", storage.ListOptions{Limit: 100})
+//	for page.ContinueToken != "" && err == nil {
+//	    page, err = storage.LoadPage(ctx, backend, "Device", storage.ListOptions{Limit: 100, ContinueToken: page.ContinueToken})
+//	}
+func LoadPage(ctx context.Context, backend StorageBackend, resourceType string, opts ListOptions) (ListPage[json.RawMessage], error) {
+	if pageable, ok := backend.(PageableBackend); ok {
+		return pageable.LoadPage(ctx, resourceType, opts)
+	}
+
+	after, err := pageStart(opts)
+	if err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+	uids, err := backend.List(ctx, resourceType)
+	if err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+	sort.Strings(uids)
+
+	page := ListPage[json.RawMessage]{Items: []json.RawMessage{}, Total: len(uids)}
+	if len(opts.LabelSelector) > 0 {
+		page.Total = -1
+	}
+	for i := sort.Search(len(uids), func(i int) bool { return uids[i] > after }); i < len(uids); i++ {
+		if opts.Limit > 0 && len(page.Items) == opts.Limit {
+			page.ContinueToken = EncodeContinueToken(uids[i-1])
+			break
+		}
+		data, err := backend.Load(ctx, resourceType, uids[i])
+		if errors.Is(err, ErrNotFound) {
+			continue // Deleted since it was listed
+		}
+		if err != nil {
+			return ListPage[json.RawMessage]{}, fmt.Errorf("failed to load %s %s: %w", resourceType, uids[i], err)
+		}
+		if len(opts.LabelSelector) > 0 && !labelsMatch(storedLabels(data), opts.LabelSelector) {
+			continue
+		}
+		page.Items = append(page.Items, data)
+	}
+	return page, nil
+}
+
+// pageOf returns the page of a sorted UID list that starts after a UID, and
+// the continue token of the next page. get returns the stored JSON of a
+// resource, or false to skip it.
+func pageOf(uids []string, after string, limit int, get func(uid string) (json.RawMessage, bool)) ([]json.RawMessage, string) {
+	items := []json.RawMessage{}
+	for i := sort.SearchStrings(uids, after); i < len(uids); i++ {
+		if uids[i] == after {
+			continue
+		}
+		if limit > 0 && len(items) == limit {
+			return items, EncodeContinueToken(uids[i-1])
+		}
+		if data, ok := get(uids[i]); ok {
+			items = append(items, data)
+		}
+	}
+	return items, ""
+}
+
+// pageStart returns the UID a page starts after, or "" for the first page
+func pageStart(opts ListOptions) (string, error) {
+	if opts.ContinueToken == "" {
+		return "", nil
+	}
+	return DecodeContinueToken(opts.ContinueToken)
+}
+
+// storedLabels returns the metadata.labels of a stored resource
+func storedLabels(data json.RawMessage) map[string]string {
+	var meta indexedMetadata
+	_ = json.Unmarshal(data, &meta) // resources without metadata have no labels
+	return meta.Metadata.Labels
+}
@@ -0,0 +1,92 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// unpageable hides the PageableBackend implementation of a backend
+type unpageable struct {
+	StorageBackend
+}
+
+func TestLoadPage(t *testing.T) {
+	ctx := context.Background()
+	file, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	backends := map[string]StorageBackend{
+		"file":     file,
+		"memory":   NewMemoryBackend(),
+		"fallback": unpageable{NewMemoryBackend()},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			for _, uid := range []string{"dev-4", "dev-2", "dev-5", "dev-1", "dev-3"} {
+				rack := "r1"
+				if uid == "dev-2" || uid == "dev-5" {
+					rack = "r2"
+				}
+				if err := backend.Save(ctx, "Device", uid, device(uid, uid, map[string]string{"rack": rack}, "x1")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			page, err := LoadPage(ctx, backend, "Device", ListOptions{Limit: 2})
+			assertUIDs(t, "first page", page.Items, err, "dev-1", "dev-2")
+			if page.Total != 5 || page.ContinueToken == "" {
+				t.Fatalf("first page: total %d, token %q", page.Total, page.ContinueToken)
+			}
+			page, err = LoadPage(ctx, backend, "Device", ListOptions{Limit: 2, ContinueToken: page.ContinueToken})
+			assertUIDs(t, "second page", page.Items, err, "dev-3", "dev-4")
+
+			// A resource deleted between pages doesn't break the list
+			if err := backend.Delete(ctx, "Device", "dev-4"); err != nil {
+				t.Fatal(err)
+			}
+			page, err = LoadPage(ctx, backend, "Device", ListOptions{Limit: 2, ContinueToken: page.ContinueToken})
+			assertUIDs(t, "last page", page.Items, err, "dev-5")
+			if page.ContinueToken != "" {
+				t.Errorf("last page has continue token %q", page.ContinueToken)
+			}
+
+			page, err = LoadPage(ctx, backend, "Device", ListOptions{})
+			assertUIDs(t, "unlimited page", page.Items, err, "dev-1", "dev-2", "dev-3", "dev-5")
+
+			selector := map[string]string{"rack": "r1"}
+			page, err = LoadPage(ctx, backend, "Device", ListOptions{Limit: 1, LabelSelector: selector})
+			assertUIDs(t, "selected first page", page.Items, err, "dev-1")
+			page, err = LoadPage(ctx, backend, "Device", ListOptions{LabelSelector: selector, ContinueToken: page.ContinueToken})
+			assertUIDs(t, "selected rest", page.Items, err, "dev-3")
+
+			page, err = LoadPage(ctx, backend, "Other", ListOptions{Limit: 2})
+			assertUIDs(t, "empty type", page.Items, err)
+
+			if _, err := LoadPage(ctx, backend, "Device", ListOptions{ContinueToken: "not-a-token"}); !errors.Is(err, ErrInvalidContinueToken) {
+				t.Errorf("invalid token: got %v, want ErrInvalidContinueToken", err)
+			}
+		})
+	}
+}
+
+func TestContinueToken(t *testing.T) {
+	token := EncodeContinueToken("dev-1")
+	if after, err := DecodeContinueToken(token); err != nil || after != "dev-1" {
+		t.Errorf("DecodeContinueToken = %q, %v", after, err)
+	}
+	sorted := base64.RawURLEncoding.EncodeToString([]byte(`{"after":"dev-1","order":"-metadata.name"}`))
+	for _, token := range []string{"", "!!", EncodeContinueToken(""), sorted} {
+		if _, err := DecodeContinueToken(token); !errors.Is(err, ErrInvalidContinueToken) {
+			t.Errorf("DecodeContinueToken(%q) = %v, want ErrInvalidContinueToken", token, err)
+		}
+	}
+}
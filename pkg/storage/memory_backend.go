@@ -0,0 +1,298 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryBackend implements StorageBackend with in-memory maps.
+//
+// Resources live only as long as the backend, which makes it suitable for
+// tests, fake servers and short-lived tools. Data is copied on the way in and
+// out, so callers can't modify stored resources through returned slices.
+//
+// LoadAll, List, Each and LoadPage return resources ordered by UID.
+type MemoryBackend struct {
+	mu              sync.RWMutex
+	resources       map[string]map[string]json.RawMessage // resourceType -> uid -> data
+	closed          bool
+	versionRegistry VersionRegistry
+	watchers        watchers
+}
+
+// NewMemoryBackend creates an empty in-memory storage backend.
+//
+// Example:
+//
+//	backend := storage.NewMemoryBackend()
+//	err := backend.Save(ctx, "Device", "dev-1", data)
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{resources: make(map[string]map[string]json.RawMessage)}
+}
+
+// checkClosed returns an error if the backend has been closed
+func (m *MemoryBackend) checkClosed() error {
+	if m.closed {
+		return fmt.Errorf("storage backend has been closed")
+	}
+	return nil
+}
+
+// copyRaw returns a copy of data
+func copyRaw(data json.RawMessage) json.RawMessage {
+	return append(json.RawMessage(nil), data...)
+}
+
+// sortedUIDs returns the UIDs of a resource type in order; callers hold the lock
+func (m *MemoryBackend) sortedUIDs(resourceType string) []string {
+	uids := make([]string, 0, len(m.resources[resourceType]))
+	for uid := range m.resources[resourceType] {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// LoadAll implements StorageBackend.LoadAll
+func (m *MemoryBackend) LoadAll(ctx context.Context, resourceType string) ([]json.RawMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if err := m.checkClosed(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resources := make([]json.RawMessage, 0, len(m.resources[resourceType]))
+	for _, uid := range m.sortedUIDs(resourceType) {
+		resources = append(resources, copyRaw(m.resources[resourceType][uid]))
+	}
+	return resources, nil
+}
+
+// Each implements IterableBackend.Each. The backend isn't locked while fn
+// runs.
+func (m *MemoryBackend) Each(ctx context.Context, resourceType string, fn func(data json.RawMessage) error) error {
+	uids, err := m.List(ctx, resourceType)
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		data, err := m.Load(ctx, resourceType, uid)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadPage implements PageableBackend.LoadPage
+func (m *MemoryBackend) LoadPage(ctx context.Context, resourceType string, opts ListOptions) (ListPage[json.RawMessage], error) {
+	after, err := pageStart(opts)
+	if err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if err := m.checkClosed(); err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return ListPage[json.RawMessage]{}, err
+	}
+
+	uids := m.sortedUIDs(resourceType)
+	if len(opts.LabelSelector) > 0 {
+		matches := uids[:0]
+		for _, uid := range uids {
+			if labelsMatch(storedLabels(m.resources[resourceType][uid]), opts.LabelSelector) {
+				matches = append(matches, uid)
+			}
+		}
+		uids = matches
+	}
+	items, token := pageOf(uids, after, opts.Limit, func(uid string) (json.RawMessage, bool) {
+		return copyRaw(m.resources[resourceType][uid]), true
+	})
+	return ListPage[json.RawMessage]{Items: items, ContinueToken: token, Total: len(uids)}, nil
+}
+
+// Load implements StorageBackend.Load
+func (m *MemoryBackend) Load(ctx context.Context, resourceType, uid string) (json.RawMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if err := m.checkClosed(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, ok := m.resources[resourceType][uid]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyRaw(data), nil
+}
+
+// Save implements StorageBackend.Save
+func (m *MemoryBackend) Save(ctx context.Context, resourceType, uid string, data json.RawMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkClosed(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return fmt.Errorf("invalid JSON data: %w", ErrInvalidData)
+	}
+
+	if m.resources[resourceType] == nil {
+		m.resources[resourceType] = make(map[string]json.RawMessage)
+	}
+	m.resources[resourceType][uid] = copyRaw(data)
+	m.watchers.notify(WatchEvent{Type: WatchSaved, ResourceType: resourceType, UID: uid, Data: data})
+	return nil
+}
+
+// Delete implements StorageBackend.Delete
+func (m *MemoryBackend) Delete(ctx context.Context, resourceType, uid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.checkClosed(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := m.resources[resourceType][uid]; !ok {
+		return ErrNotFound
+	}
+	delete(m.resources[resourceType], uid)
+	m.watchers.notify(WatchEvent{Type: WatchDeleted, ResourceType: resourceType, UID: uid})
+	return nil
+}
+
+// Watch implements WatchableBackend.Watch
+func (m *MemoryBackend) Watch(ctx context.Context, resourceType string) (<-chan WatchEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if err := m.checkClosed(); err != nil {
+		return nil, err
+	}
+	return m.watchers.add(ctx, resourceType)
+}
+
+// Exists implements StorageBackend.Exists
+func (m *MemoryBackend) Exists(ctx context.Context, resourceType, uid string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if err := m.checkClosed(); err != nil {
+		return false, err
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	_, ok := m.resources[resourceType][uid]
+	return ok, nil
+}
+
+// List implements StorageBackend.List
+func (m *MemoryBackend) List(ctx context.Context, resourceType string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if err := m.checkClosed(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.sortedUIDs(resourceType), nil
+}
+
+// Close implements StorageBackend.Close. Stored resources are discarded and
+// watches end.
+func (m *MemoryBackend) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.watchers.close()
+	m.resources = make(map[string]map[string]json.RawMessage)
+	return nil
+}
+
+// SetVersionRegistry sets the version registry for version-aware operations.
+func (m *MemoryBackend) SetVersionRegistry(registry VersionRegistry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.versionRegistry = registry
+}
+
+// registry returns the version registry, or an error if none is set
+func (m *MemoryBackend) registry() (VersionRegistry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.versionRegistry == nil {
+		return nil, fmt.Errorf("version registry not set")
+	}
+	return m.versionRegistry, nil
+}
+
+// LoadWithVersion implements StorageBackend.LoadWithVersion
+func (m *MemoryBackend) LoadWithVersion(ctx context.Context, resourceType, uid, version string) (json.RawMessage, string, error) {
+	registry, err := m.registry()
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := m.Load(ctx, resourceType, uid)
+	if err != nil {
+		return nil, "", err
+	}
+	return ConvertFromStorageVersion(registry, resourceType, data, version)
+}
+
+// LoadAllWithVersion implements StorageBackend.LoadAllWithVersion.
+// Resources that fail to convert are skipped.
+func (m *MemoryBackend) LoadAllWithVersion(ctx context.Context, resourceType, version string) ([]json.RawMessage, error) {
+	registry, err := m.registry()
+	if err != nil {
+		return nil, err
+	}
+	all, err := m.LoadAll(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertAllFromStorageVersion(registry, resourceType, all, version)
+}
+
+// SaveWithVersion implements StorageBackend.SaveWithVersion
+func (m *MemoryBackend) SaveWithVersion(ctx context.Context, resourceType, uid string, data json.RawMessage, version string) error {
+	registry, err := m.registry()
+	if err != nil {
+		return err
+	}
+	stored, err := ConvertToStorageVersion(registry, resourceType, data, version)
+	if err != nil {
+		return err
+	}
+	return m.Save(ctx, resourceType, uid, stored)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type testResource struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+func (r *testResource) GetUID() string { return r.UID }
+
+func TestMemoryBackend(t *testing.T) {
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	devices := NewResourceStorage[*testResource](backend, "Device")
+
+	for _, r := range []*testResource{{UID: "dev-2", Name: "b"}, {UID: "dev-1", Name: "a"}} {
+		if err := devices.Save(ctx, r); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	got, err := devices.Load(ctx, "dev-1")
+	if err != nil || got.Name != "a" {
+		t.Fatalf("Load = %+v, %v", got, err)
+	}
+	uids, _ := devices.List(ctx)
+	if len(uids) != 2 || uids[0] != "dev-1" || uids[1] != "dev-2" {
+		t.Errorf("Expected sorted UIDs, got %v", uids)
+	}
+	if uids, _ := backend.List(ctx, "Other"); len(uids) != 0 {
+		t.Errorf("Expected resource types to be separate, got %v", uids)
+	}
+
+	// Returned data must not alias stored data
+	raw, _ := backend.Load(ctx, "Device", "dev-1")
+	raw[0] = 'x'
+	if raw, _ := backend.Load(ctx, "Device", "dev-1"); !json.Valid(raw) {
+		t.Error("Modifying loaded data changed the stored resource")
+	}
+
+	if err := backend.Save(ctx, "Device", "bad", json.RawMessage("{")); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("Expected ErrInvalidData, got %v", err)
+	}
+	if err := devices.Delete(ctx, "dev-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := backend.Load(ctx, "Device", "dev-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := backend.Delete(ctx, "Device", "dev-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+	if exists, _ := backend.Exists(ctx, "Device", "dev-2"); !exists {
+		t.Error("Expected dev-2 to exist")
+	}
+
+	backend.Close()
+	if _, err := backend.LoadAll(ctx, "Device"); err == nil {
+		t.Error("Expected error after Close")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Snapshot writes an archive of every resource stored in backend to w, as
+// stored at one point in time.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - backend: Backend to archive
+//   - w: Destination of the archive
+//
+// Returns:
+//   - error: A backend or write error, or ErrSnapshotsUnsupported if backend
+//     doesn't implement SnapshotBackend
+//
+// Example:
+//
+//	out, _ := os.Create("snapshot.tar")
+//	defer out.Close()
+//	if err := storage.Snapshot(ctx, backend, out); err != nil {
+//	    log.Fatal(err)
+//	}
+func Snapshot(ctx context.Context, backend StorageBackend, w io.Writer) error {
+	snapshots, ok := backend.(SnapshotBackend)
+	if !ok {
+		return ErrSnapshotsUnsupported
+	}
+	return snapshots.Snapshot(ctx, w)
+}
+
+// Restore replaces every resource stored in backend with those of an
+// archive written by Snapshot.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - backend: Backend to restore
+//   - r: Source of the archive
+//
+// Returns:
+//   - error: An error wrapping ErrInvalidData for invalid archives, which
+//     leave the stored resources unchanged, a backend error, or
+//     ErrSnapshotsUnsupported if backend doesn't implement SnapshotBackend
+func Restore(ctx context.Context, backend StorageBackend, r io.Reader) error {
+	snapshots, ok := backend.(SnapshotBackend)
+	if !ok {
+		return ErrSnapshotsUnsupported
+	}
+	return snapshots.Restore(ctx, r)
+}
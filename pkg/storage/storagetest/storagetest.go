@@ -0,0 +1,576 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package storagetest provides a conformance suite for storage backends.
+//
+// Every StorageBackend implementation should behave the same way as far as
+// handlers and reconcilers can observe. RunConformance checks that contract:
+//   - CRUD: Save creates and replaces, Load returns what was saved, Delete removes.
+//     Backends may add fields on Load (e.g., timestamps) but must not drop or change any.
+//   - Preconditions: Load and Delete of missing resources return storage.ErrNotFound,
+//     invalid JSON is rejected with storage.ErrInvalidData
+//   - Listing: LoadAll and List return every resource of a type and nothing else,
+//     and an unknown type is an empty list rather than an error
+//   - Iteration: Each visits resources in UID order and stops at a callback error
+//     (only for backends implementing storage.IterableBackend)
+//   - Watch: saves and deletions are reported in order, and the channel closes
+//     when the watch's context ends (only for backends implementing
+//     storage.WatchableBackend)
+//   - Transactions: writes in a transaction are all kept on commit and all
+//     undone on rollback, and nested transactions join the outer one (only
+//     for backends implementing storage.TransactionalBackend)
+//   - Concurrency: parallel writers and readers don't lose or corrupt data
+//   - Cancellation: operations with a cancelled context fail
+//
+// Pagination semantics are not part of StorageBackend yet; the suite will
+// cover them once the interface defines them.
+//
+// Usage:
+//
+//	func TestMyBackendConformance(t *testing.T) {
+//	    backend := mybackend.New(...)
+//	    defer backend.Close()
+//	    storagetest.RunConformance(t, backend)
+//	}
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// concurrency is the number of goroutines used by the concurrency checks
+const concurrency = 16
+
+// RunConformance runs the storage conformance suite against impl.
+//
+// Each check uses its own resource type (prefixed with "Conformance") and
+// deletes its resources afterwards, so impl may already contain other data.
+// UIDs are never reused across resource types, matching Fabrica's prefixed UIDs.
+// The caller owns impl and is responsible for closing it.
+//
+// Parameters:
+//   - t: The test to report results to; each check runs as a subtest
+//   - impl: The backend under test
+//
+// Example:
+//
+//	backend := storage.NewMemoryBackend()
+//	storagetest.RunConformance(t, backend)
+func RunConformance(t *testing.T, impl storage.StorageBackend) {
+	t.Helper()
+
+	checks := []struct {
+		name string
+		run  func(t *testing.T, impl storage.StorageBackend, resourceType string)
+	}{
+		{"CRUD", testCRUD},
+		{"Preconditions", testPreconditions},
+		{"Listing", testListing},
+		{"Iteration", testIteration},
+		{"Watch", testWatch},
+		{"Isolation", testIsolation},
+		{"Transactions", testTransactions},
+		{"ConcurrentWrites", testConcurrentWrites},
+		{"ConcurrentUpdates", testConcurrentUpdates},
+		{"Cancellation", testCancellation},
+	}
+
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			check.run(t, impl, resourceType(t, impl, check.name))
+		})
+	}
+}
+
+// resourceType returns a resource type that is empty for the duration of a check
+func resourceType(t *testing.T, impl storage.StorageBackend, name string) string {
+	t.Helper()
+	kind := "Conformance" + name
+	cleanup := func() {
+		uids, err := impl.List(context.Background(), kind)
+		if err != nil {
+			t.Errorf("List(%s) failed during cleanup: %v", kind, err)
+			return
+		}
+		for _, uid := range uids {
+			if err := impl.Delete(context.Background(), kind, uid); err != nil && !errors.Is(err, storage.ErrNotFound) {
+				t.Errorf("Delete(%s, %s) failed during cleanup: %v", kind, uid, err)
+			}
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+	return kind
+}
+
+// testUID returns a UID for a test resource of the given type
+func testUID(kind, suffix string) string {
+	return strings.ToLower(kind) + "-" + suffix
+}
+
+// resource returns the JSON of a test resource
+func resource(kind, uid string, generation int) json.RawMessage {
+	data, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"uid": uid, "name": uid},
+		"spec":       map[string]interface{}{"generation": generation, "tags": []string{"a", "b"}},
+	})
+	return data
+}
+
+// containsJSON reports whether got holds every field of want with the same value.
+// Objects in got may have extra fields; arrays and scalars must match exactly.
+func containsJSON(got, want json.RawMessage) bool {
+	var vg, vw interface{}
+	if json.Unmarshal(got, &vg) != nil || json.Unmarshal(want, &vw) != nil {
+		return false
+	}
+	return contains(vg, vw)
+}
+
+// contains is the recursive part of containsJSON
+func contains(got, want interface{}) bool {
+	wantObj, ok := want.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(got, want)
+	}
+	gotObj, ok := got.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, value := range wantObj {
+		if !contains(gotObj[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// generationOf returns spec.generation of a test resource
+func generationOf(t *testing.T, data json.RawMessage) int {
+	t.Helper()
+	var r struct {
+		Spec struct {
+			Generation int `json:"generation"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("stored resource is not valid JSON: %v: %s", err, data)
+	}
+	return r.Spec.Generation
+}
+
+func testCRUD(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx := context.Background()
+	uid := testUID(kind, "1")
+
+	if err := impl.Save(ctx, kind, uid, resource(kind, uid, 1)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	got, err := impl.Load(ctx, kind, uid)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !containsJSON(got, resource(kind, uid, 1)) {
+		t.Errorf("Load returned %s, want %s", got, resource(kind, uid, 1))
+	}
+	if exists, err := impl.Exists(ctx, kind, uid); err != nil || !exists {
+		t.Errorf("Exists = %v, %v; want true", exists, err)
+	}
+
+	// Save replaces the whole resource
+	if err := impl.Save(ctx, kind, uid, resource(kind, uid, 2)); err != nil {
+		t.Fatalf("Save (update) failed: %v", err)
+	}
+	got, err = impl.Load(ctx, kind, uid)
+	if err != nil || !containsJSON(got, resource(kind, uid, 2)) {
+		t.Errorf("Load after update = %s, %v; want generation 2", got, err)
+	}
+
+	if err := impl.Delete(ctx, kind, uid); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := impl.Load(ctx, kind, uid); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Load after Delete: expected ErrNotFound, got %v", err)
+	}
+	if exists, err := impl.Exists(ctx, kind, uid); err != nil || exists {
+		t.Errorf("Exists after Delete = %v, %v; want false", exists, err)
+	}
+}
+
+func testPreconditions(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx := context.Background()
+	uid := testUID(kind, "1")
+
+	if _, err := impl.Load(ctx, kind, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Load of a missing resource: expected ErrNotFound, got %v", err)
+	}
+	if err := impl.Delete(ctx, kind, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Delete of a missing resource: expected ErrNotFound, got %v", err)
+	}
+	if exists, err := impl.Exists(ctx, kind, "missing"); err != nil || exists {
+		t.Errorf("Exists of a missing resource = %v, %v; want false", exists, err)
+	}
+
+	if err := impl.Save(ctx, kind, uid, resource(kind, uid, 1)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := impl.Save(ctx, kind, uid, json.RawMessage(`{"broken":`)); !errors.Is(err, storage.ErrInvalidData) {
+		t.Errorf("Save of invalid JSON: expected ErrInvalidData, got %v", err)
+	}
+	got, err := impl.Load(ctx, kind, uid)
+	if err != nil || !containsJSON(got, resource(kind, uid, 1)) {
+		t.Errorf("a rejected Save must leave the stored resource unchanged, got %s, %v", got, err)
+	}
+}
+
+func testListing(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx := context.Background()
+
+	all, err := impl.LoadAll(ctx, kind)
+	if err != nil || len(all) != 0 {
+		t.Fatalf("LoadAll of an empty type = %d items, %v; want none and no error", len(all), err)
+	}
+	uids, err := impl.List(ctx, kind)
+	if err != nil || len(uids) != 0 {
+		t.Fatalf("List of an empty type = %v, %v; want none and no error", uids, err)
+	}
+
+	want := []string{testUID(kind, "a"), testUID(kind, "b"), testUID(kind, "c")}
+	for i, uid := range want {
+		if err := impl.Save(ctx, kind, uid, resource(kind, uid, i)); err != nil {
+			t.Fatalf("Save(%s) failed: %v", uid, err)
+		}
+	}
+
+	uids, err = impl.List(ctx, kind)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	sort.Strings(uids)
+	if !reflect.DeepEqual(uids, want) {
+		t.Errorf("List = %v, want %v", uids, want)
+	}
+
+	all, err = impl.LoadAll(ctx, kind)
+	if err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+	if len(all) != len(want) {
+		t.Fatalf("LoadAll returned %d resources, want %d", len(all), len(want))
+	}
+	seen := map[int]bool{}
+	for _, data := range all {
+		seen[generationOf(t, data)] = true
+	}
+	for i := range want {
+		if !seen[i] {
+			t.Errorf("LoadAll is missing the resource with generation %d", i)
+		}
+	}
+}
+
+// testIteration checks IterableBackend.Each, for backends implementing it
+func testIteration(t *testing.T, impl storage.StorageBackend, kind string) {
+	iterable, ok := impl.(storage.IterableBackend)
+	if !ok {
+		t.Skip("backend doesn't implement storage.IterableBackend")
+	}
+	ctx := context.Background()
+
+	want := []string{testUID(kind, "a"), testUID(kind, "b"), testUID(kind, "c")}
+	for i, uid := range want {
+		if err := impl.Save(ctx, kind, uid, resource(kind, uid, i)); err != nil {
+			t.Fatalf("Save(%s) failed: %v", uid, err)
+		}
+	}
+
+	var generations []int
+	err := iterable.Each(ctx, kind, func(data json.RawMessage) error {
+		generations = append(generations, generationOf(t, data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each failed: %v", err)
+	}
+	if !reflect.DeepEqual(generations, []int{0, 1, 2}) {
+		t.Errorf("Each visited generations %v, want [0 1 2] in UID order", generations)
+	}
+
+	// An error from the callback stops the iteration
+	stop := errors.New("stop")
+	calls := 0
+	err = iterable.Each(ctx, kind, func(json.RawMessage) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Each after a callback error = %v with %d calls, want the error after 1 call", err, calls)
+	}
+}
+
+// testWatch checks WatchableBackend.Watch, for backends implementing it
+func testWatch(t *testing.T, impl storage.StorageBackend, kind string) {
+	watchable, ok := impl.(storage.WatchableBackend)
+	if !ok {
+		t.Skip("backend doesn't implement storage.WatchableBackend")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := watchable.Watch(ctx, kind)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	other := resourceType(t, impl, "WatchOther")
+	uid := testUID(kind, "1")
+	if err := impl.Save(ctx, other, testUID(other, "1"), resource(other, testUID(other, "1"), 0)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	for generation := 1; generation <= 2; generation++ {
+		if err := impl.Save(ctx, kind, uid, resource(kind, uid, generation)); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	if err := impl.Delete(ctx, kind, uid); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	want := []storage.WatchEventType{storage.WatchSaved, storage.WatchSaved, storage.WatchDeleted}
+	for i, wantType := range want {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("watch ended after %d events, want %d", i, len(want))
+			}
+			if event.Type != wantType || event.ResourceType != kind || event.UID != uid {
+				t.Errorf("event %d = %s %s/%s, want %s %s/%s", i, event.Type, event.ResourceType, event.UID, wantType, kind, uid)
+			}
+			if wantType == storage.WatchSaved && generationOf(t, event.Data) != i+1 {
+				t.Errorf("event %d has generation %d, want %d", i, generationOf(t, event.Data), i+1)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+
+	// Ending the context ends the watch
+	cancel()
+	select {
+	case event, ok := <-events:
+		if ok {
+			t.Errorf("unexpected event after the last change: %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("the watch channel wasn't closed when its context ended")
+	}
+}
+
+func testIsolation(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx := context.Background()
+	other := resourceType(t, impl, "IsolationOther")
+	mine, theirs := testUID(kind, "1"), testUID(other, "1")
+
+	if err := impl.Save(ctx, kind, mine, resource(kind, mine, 1)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := impl.Save(ctx, other, theirs, resource(other, theirs, 2)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if _, err := impl.Load(ctx, other, mine); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Load with the wrong resource type: expected ErrNotFound, got %v", err)
+	}
+	if err := impl.Delete(ctx, other, mine); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Delete with the wrong resource type: expected ErrNotFound, got %v", err)
+	}
+	if uids, err := impl.List(ctx, other); err != nil || !reflect.DeepEqual(uids, []string{theirs}) {
+		t.Errorf("List(%s) = %v, %v; want only %s", other, uids, err, theirs)
+	}
+	if exists, err := impl.Exists(ctx, kind, mine); err != nil || !exists {
+		t.Errorf("operations on one resource type must not affect another, Exists = %v, %v", exists, err)
+	}
+}
+
+func testTransactions(t *testing.T, impl storage.StorageBackend, kind string) {
+	if _, ok := impl.(storage.TransactionalBackend); !ok {
+		t.Skip("backend doesn't implement storage.TransactionalBackend")
+	}
+	ctx := context.Background()
+	a, b, c := testUID(kind, "a"), testUID(kind, "b"), testUID(kind, "c")
+	if err := impl.Save(ctx, kind, a, resource(kind, a, 0)); err != nil {
+		t.Fatalf("Save(%s) failed: %v", a, err)
+	}
+
+	// A failed transaction undoes all of its writes, including those of a
+	// nested transaction
+	rollback := errors.New("rollback")
+	err := storage.WithTx(ctx, impl, func(ctx context.Context) error {
+		if err := impl.Save(ctx, kind, a, resource(kind, a, 1)); err != nil {
+			return err
+		}
+		if err := impl.Save(ctx, kind, b, resource(kind, b, 1)); err != nil {
+			return err
+		}
+		if err := storage.WithTx(ctx, impl, func(ctx context.Context) error {
+			return impl.Save(ctx, kind, c, resource(kind, c, 1))
+		}); err != nil {
+			return err
+		}
+		if data, err := impl.Load(ctx, kind, b); err != nil || generationOf(t, data) != 1 {
+			t.Errorf("Load in the transaction = %s, %v, want its own write", data, err)
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("WithTx = %v, want the error of fn", err)
+	}
+	if data, err := impl.Load(ctx, kind, a); err != nil || generationOf(t, data) != 0 {
+		t.Errorf("Load(%s) after a rollback = %s, %v, want generation 0", a, data, err)
+	}
+	for _, uid := range []string{b, c} {
+		if exists, err := impl.Exists(ctx, kind, uid); err != nil || exists {
+			t.Errorf("Exists(%s) after a rollback = %v, %v, want false", uid, exists, err)
+		}
+	}
+
+	// A successful transaction keeps all of its writes
+	err = storage.WithTx(ctx, impl, func(ctx context.Context) error {
+		if err := impl.Save(ctx, kind, b, resource(kind, b, 2)); err != nil {
+			return err
+		}
+		return impl.Delete(ctx, kind, a)
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if exists, err := impl.Exists(ctx, kind, a); err != nil || exists {
+		t.Errorf("Exists(%s) after a commit = %v, %v, want false", a, exists, err)
+	}
+	if data, err := impl.Load(ctx, kind, b); err != nil || generationOf(t, data) != 2 {
+		t.Errorf("Load(%s) after a commit = %s, %v, want generation 2", b, data, err)
+	}
+}
+
+func testConcurrentWrites(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency*2)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			uid := testUID(kind, fmt.Sprintf("%02d", i))
+			if err := impl.Save(ctx, kind, uid, resource(kind, uid, i)); err != nil {
+				errs <- fmt.Errorf("Save(%s): %w", uid, err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := impl.LoadAll(ctx, kind); err != nil {
+				errs <- fmt.Errorf("LoadAll: %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	uids, err := impl.List(ctx, kind)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(uids) != concurrency {
+		t.Errorf("expected %d resources after concurrent writes, got %d", concurrency, len(uids))
+	}
+	for i := 0; i < concurrency; i++ {
+		uid := testUID(kind, fmt.Sprintf("%02d", i))
+		got, err := impl.Load(ctx, kind, uid)
+		if err != nil || generationOf(t, got) != i {
+			t.Errorf("Load(%s) = %s, %v; want generation %d", uid, got, err, i)
+		}
+	}
+}
+
+func testConcurrentUpdates(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx := context.Background()
+	uid := testUID(kind, "1")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency*2)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if err := impl.Save(ctx, kind, uid, resource(kind, uid, i)); err != nil {
+				errs <- fmt.Errorf("Save: %w", err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			data, err := impl.Load(ctx, kind, uid)
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				errs <- fmt.Errorf("Load: %w", err)
+				return
+			}
+			if err == nil && !json.Valid(data) {
+				errs <- fmt.Errorf("Load returned a partially written resource: %s", data)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// The last writer wins; the result must be one complete resource
+	got, err := impl.Load(ctx, kind, uid)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if generation := generationOf(t, got); generation < 0 || generation >= concurrency {
+		t.Errorf("unexpected generation %d after concurrent updates", generation)
+	}
+	if uids, err := impl.List(ctx, kind); err != nil || len(uids) != 1 {
+		t.Errorf("expected exactly one resource after concurrent updates, got %v, %v", uids, err)
+	}
+}
+
+func testCancellation(t *testing.T, impl storage.StorageBackend, kind string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	uid := testUID(kind, "1")
+
+	if err := impl.Save(ctx, kind, uid, resource(kind, uid, 1)); err == nil {
+		t.Error("Save with a cancelled context should fail")
+	}
+	if _, err := impl.Load(ctx, kind, uid); err == nil {
+		t.Error("Load with a cancelled context should fail")
+	}
+	if _, err := impl.LoadAll(ctx, kind); err == nil {
+		t.Error("LoadAll with a cancelled context should fail")
+	}
+	if _, err := impl.List(ctx, kind); err == nil {
+		t.Error("List with a cancelled context should fail")
+	}
+	if exists, _ := impl.Exists(context.Background(), kind, uid); exists {
+		t.Error("a Save with a cancelled context must not store the resource")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storagetest
+
+import (
+	"testing"
+
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+func TestMemoryBackendConformance(t *testing.T) {
+	backend := storage.NewMemoryBackend()
+	defer backend.Close()
+	RunConformance(t, backend)
+}
+
+func TestFileBackendConformance(t *testing.T) {
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	RunConformance(t, backend)
+}
+
+func TestFileBackendWithIndexFileConformance(t *testing.T) {
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.EnableIndexFile(); err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	RunConformance(t, backend)
+}
+
+func TestFileBackendWithCompressionConformance(t *testing.T) {
+	backend, err := storage.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.EnableCompression(storage.CompressionZstd); err != nil {
+		t.Fatal(err)
+	}
+	defer backend.Close()
+	RunConformance(t, backend)
+}
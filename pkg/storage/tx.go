@@ -0,0 +1,37 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import "context"
+
+// WithTx runs fn in a transaction of backend, so that the resources fn saves
+// and deletes with the context it is given are written all together or not at
+// all.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - backend: Backend to write to
+//   - fn: Reads and writes through the backend with the context it is given
+//
+// Returns:
+//   - error: The error of fn, a transaction error, or
+//     ErrTransactionsUnsupported if backend doesn't implement
+//     TransactionalBackend (fn is then not called)
+//
+// Example:
+//
+//	err := storage.WithTx(ctx, backend, func(ctx context.Context) error {
+//	    if err := backend.Save(ctx, "Device", device.GetUID(), deviceJSON); err != nil {
+//	        return err
+//	    }
+//	    return backend.Delete(ctx, "Connection", oldConnectionUID)
+//	})
+func WithTx(ctx context.Context, backend StorageBackend, fn func(ctx context.Context) error) error {
+	transactional, ok := backend.(TransactionalBackend)
+	if !ok {
+		return ErrTransactionsUnsupported
+	}
+	return transactional.WithTx(ctx, fn)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// txBackend records the transactions of a memory backend
+type txBackend struct {
+	*MemoryBackend
+	txs int
+}
+
+func (b *txBackend) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	b.txs++
+	return fn(ctx)
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+
+	called := false
+	err := WithTx(ctx, NewMemoryBackend(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrTransactionsUnsupported) || called {
+		t.Errorf("WithTx on a memory backend = %v (fn called: %v), want ErrTransactionsUnsupported", err, called)
+	}
+
+	backend := &txBackend{MemoryBackend: NewMemoryBackend()}
+	failed := errors.New("failed")
+	if err := WithTx(ctx, backend, func(ctx context.Context) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("WithTx = %v, want the error of fn", err)
+	}
+	if backend.txs != 1 {
+		t.Errorf("WithTx ran %d transactions, want 1", backend.txs)
+	}
+}
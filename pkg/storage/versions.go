@@ -0,0 +1,126 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// versionInfo returns the converter-capable type info of a non-default version
+func versionInfo(registry VersionRegistry, resourceType, version string) (VersionInfo, error) {
+	info, ok := registry.GetVersion(resourceType, version)
+	if !ok {
+		return nil, fmt.Errorf("unsupported version %s for %s", version, resourceType)
+	}
+	if info.Converter() == nil {
+		return nil, fmt.Errorf("no converter available for %s version %s", resourceType, version)
+	}
+	return info, nil
+}
+
+// ConvertFromStorageVersion converts a stored resource (in the default
+// version) to the requested version, returning the version actually served.
+//
+// Backends store every resource in its default version; custom backends can
+// use this to implement StorageBackend.LoadWithVersion.
+//
+// Parameters:
+//   - registry: The version registry set with SetVersionRegistry
+//   - resourceType: Type name (e.g., "Device")
+//   - data: The stored resource
+//   - version: The requested version; empty means the default version
+//
+// Returns:
+//   - json.RawMessage: The converted resource
+//   - string: The version of the returned resource
+//   - error: If the version is unknown or conversion fails
+func ConvertFromStorageVersion(registry VersionRegistry, resourceType string, data json.RawMessage, version string) (json.RawMessage, string, error) {
+	defaultVersion := registry.GetDefaultVersion(resourceType)
+	if defaultVersion == "" {
+		return data, "v1", nil
+	}
+	if version == "" || version == defaultVersion {
+		return data, defaultVersion, nil
+	}
+
+	info, err := versionInfo(registry, resourceType, version)
+	if err != nil {
+		return nil, "", err
+	}
+	defaultInfo, ok := registry.GetVersion(resourceType, defaultVersion)
+	if !ok {
+		return nil, "", fmt.Errorf("failed to get default version info")
+	}
+
+	stored := defaultInfo.Constructor()
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	converted, err := info.Converter().Convert(stored, defaultVersion, version)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to convert from %s to %s: %w", defaultVersion, version, err)
+	}
+	out, err := json.Marshal(converted)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal converted resource: %w", err)
+	}
+	return out, version, nil
+}
+
+// ConvertToStorageVersion converts a resource in the given version to the
+// default (storage) version. Custom backends can use this to implement
+// StorageBackend.SaveWithVersion.
+//
+// Example:
+//
+//	stored, err := storage.ConvertToStorageVersion(registry, "Device", data, "v2beta1")
+//	if err != nil {
+//	    return err
+//	}
+//	return backend.Save(ctx, "Device", uid, stored)
+func ConvertToStorageVersion(registry VersionRegistry, resourceType string, data json.RawMessage, version string) (json.RawMessage, error) {
+	defaultVersion := registry.GetDefaultVersion(resourceType)
+	if defaultVersion == "" || version == "" || version == defaultVersion {
+		return data, nil
+	}
+
+	info, err := versionInfo(registry, resourceType, version)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := info.Constructor()
+	if err := json.Unmarshal(data, resource); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resource: %w", err)
+	}
+	converted, err := info.Converter().Convert(resource, version, defaultVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert from %s to %s: %w", version, defaultVersion, err)
+	}
+	out, err := json.Marshal(converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal converted resource: %w", err)
+	}
+	return out, nil
+}
+
+// ConvertAllFromStorageVersion converts stored resources to the requested
+// version. Resources that fail to convert are skipped; an unknown version is
+// an error.
+func ConvertAllFromStorageVersion(registry VersionRegistry, resourceType string, all []json.RawMessage, version string) ([]json.RawMessage, error) {
+	converted := make([]json.RawMessage, 0, len(all))
+	for _, data := range all {
+		out, _, err := ConvertFromStorageVersion(registry, resourceType, data, version)
+		if err != nil {
+			if _, lookupErr := versionInfo(registry, resourceType, version); lookupErr != nil {
+				return nil, lookupErr
+			}
+			continue
+		}
+		converted = append(converted, out)
+	}
+	return converted, nil
+}
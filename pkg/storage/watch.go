@@ -0,0 +1,160 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrWatchUnsupported is returned when watching a backend that doesn't
+// implement WatchableBackend.
+var ErrWatchUnsupported = errors.New("storage backend does not support watch")
+
+// ErrWatchClosed is returned by callers of Watch, such as the generated
+// Watch functions, when a watch ends before its context: the backend was
+// closed or the watcher fell behind. Load the resources again and start a
+// new watch.
+var ErrWatchClosed = errors.New("watch closed")
+
+// WatchEventType is the kind of change a WatchEvent reports.
+type WatchEventType string
+
+const (
+	// WatchSaved reports a resource that was created or replaced.
+	WatchSaved WatchEventType = "Saved"
+	// WatchDeleted reports a resource that was deleted.
+	WatchDeleted WatchEventType = "Deleted"
+)
+
+// WatchEvent is a change to a stored resource.
+type WatchEvent struct {
+	Type         WatchEventType
+	ResourceType string
+	UID          string
+	// Data is the stored JSON after a save; nil for deletions.
+	Data json.RawMessage
+}
+
+// watchBuffer is the number of events a watcher may fall behind before
+// its channel is closed
+const watchBuffer = 256
+
+// WatchableBackend is implemented by backends that report changes to stored
+// resources as they happen. FileBackend and MemoryBackend implement it for
+// changes made through the backend itself, not by other processes.
+//
+// Callers should check for it with a type assertion (or use Watch):
+//
+//	if watchable, ok := backend.(storage.WatchableBackend); ok {
+//	    events, err := watchable.Watch(ctx, "Device")
+//	}
+type WatchableBackend interface {
+	StorageBackend
+
+	// Watch returns a channel receiving the changes to resources of a type
+	// made after the call, in the order they were made. The channel is
+	// closed when ctx is done, when the backend is closed, or when the
+	// watcher falls too far behind to be sent every change; callers
+	// should then load the resources again and start a new watch.
+	Watch(ctx context.Context, resourceType string) (<-chan WatchEvent, error)
+}
+
+// Ensure the built-in backends can be watched
+var (
+	_ WatchableBackend = (*FileBackend)(nil)
+	_ WatchableBackend = (*MemoryBackend)(nil)
+)
+
+// Watch watches the resources of a type in backend.
+//
+// Parameters:
+//   - ctx: Context ending the watch
+//   - backend: The backend to watch
+//   - resourceType: Type name (e.g., "Device")
+//
+// Returns:
+//   - <-chan WatchEvent: Changes, see WatchableBackend.Watch
+//   - error: ErrWatchUnsupported if backend can't be watched
+func Watch(ctx context.Context, backend StorageBackend, resourceType string) (<-chan WatchEvent, error) {
+	watchable, ok := backend.(WatchableBackend)
+	if !ok {
+		return nil, ErrWatchUnsupported
+	}
+	return watchable.Watch(ctx, resourceType)
+}
+
+// watchers fans changes out to the watchers of each resource type. The zero
+// value is ready to use.
+type watchers struct {
+	mu     sync.Mutex
+	byType map[string]map[chan WatchEvent]func() bool // channel -> stops its context.AfterFunc
+	closed bool
+}
+
+// add registers a watcher of resourceType, removed when ctx is done
+func (w *watchers) add(ctx context.Context, resourceType string) (<-chan WatchEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil, fmt.Errorf("storage backend has been closed")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan WatchEvent, watchBuffer)
+	if w.byType == nil {
+		w.byType = make(map[string]map[chan WatchEvent]func() bool)
+	}
+	if w.byType[resourceType] == nil {
+		w.byType[resourceType] = make(map[chan WatchEvent]func() bool)
+	}
+	w.byType[resourceType][ch] = context.AfterFunc(ctx, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if _, ok := w.byType[resourceType][ch]; ok {
+			delete(w.byType[resourceType], ch)
+			close(ch)
+		}
+	})
+	return ch, nil
+}
+
+// notify sends an event to the watchers of its resource type. Watchers
+// whose buffer is full are dropped rather than blocking the write.
+func (w *watchers) notify(event WatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch, stop := range w.byType[event.ResourceType] {
+		if event.Data != nil {
+			event.Data = copyRaw(event.Data)
+		}
+		select {
+		case ch <- event:
+		default:
+			stop()
+			delete(w.byType[event.ResourceType], ch)
+			close(ch)
+		}
+	}
+}
+
+// close ends every watch
+func (w *watchers) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	for _, chans := range w.byType {
+		for ch, stop := range chans {
+			stop()
+			close(ch)
+		}
+	}
+	w.byType = nil
+}
@@ -0,0 +1,70 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// drain reads events until the channel is closed
+func drain(events <-chan WatchEvent) int {
+	n := 0
+	for range events {
+		n++
+	}
+	return n
+}
+
+func TestWatchSlowWatcherIsDropped(t *testing.T) {
+	backend := NewMemoryBackend()
+	defer backend.Close()
+	ctx := context.Background()
+
+	events, err := backend.Watch(ctx, "Device")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= watchBuffer; i++ {
+		if err := backend.Save(ctx, "Device", "dev-1", json.RawMessage(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Writes never block on the watcher; it gets the buffered events and
+	// then a closed channel telling it to start over
+	if n := drain(events); n != watchBuffer {
+		t.Errorf("expected %d buffered events before the close, got %d", watchBuffer, n)
+	}
+}
+
+func TestWatchEndsOnClose(t *testing.T) {
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := backend.Watch(context.Background(), "Device")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Close()
+	if n := drain(events); n != 0 {
+		t.Errorf("expected no events, got %d", n)
+	}
+	if _, err := backend.Watch(context.Background(), "Device"); err == nil {
+		t.Error("expected watching a closed backend to fail")
+	}
+}
+
+// unwatchable hides the Watch method of a backend
+type unwatchable struct{ StorageBackend }
+
+func TestWatchUnsupported(t *testing.T) {
+	_, err := Watch(context.Background(), unwatchable{NewMemoryBackend()}, "Device")
+	if !errors.Is(err, ErrWatchUnsupported) {
+		t.Errorf("expected ErrWatchUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnumTag is the struct tag listing the allowed values of a field,
+// comma-separated:
+//
+//	ComponentType string `json:"componentType" enum:"compute,switch,chassis"`
+//
+// It applies to string, integer and floating point fields, to pointers to
+// them and to the items of lists of them. Zero values aren't checked; add
+// validate:"required" to make the field mandatory. Generated OpenAPI and CRD
+// schemas list the values as an enum.
+const EnumTag = "enum"
+
+// EnumValues returns the allowed values of a field declared by its enum tag,
+// or nil if it has none.
+func EnumValues(tag reflect.StructTag) []string {
+	list, ok := tag.Lookup(EnumTag)
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// enumTypes caches whether a type holds enum-tagged fields
+var enumTypes sync.Map // reflect.Type -> bool
+
+// hasEnums reports whether t is or holds a struct with enum-tagged fields
+func hasEnums(t reflect.Type) bool {
+	if cached, ok := enumTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	found := findEnums(t, map[reflect.Type]bool{})
+	enumTypes.Store(t, found)
+	return found
+}
+
+func findEnums(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return findEnums(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && (EnumValues(f.Tag) != nil || findEnums(f.Type, visiting)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateEnums checks the enum-tagged fields of v and the structs it holds
+func validateEnums(v reflect.Value) []FieldError {
+	if !v.IsValid() || !hasEnums(v.Type()) {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return validateEnums(v.Elem())
+	case reflect.Slice, reflect.Array:
+		var errs []FieldError
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, validateEnums(v.Index(i))...)
+		}
+		return errs
+	case reflect.Struct:
+		var errs []FieldError
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if allowed := EnumValues(f.Tag); allowed != nil {
+				errs = append(errs, checkEnum(jsonName(f), v.Field(i), allowed)...)
+				continue
+			}
+			errs = append(errs, validateEnums(v.Field(i))...)
+		}
+		return errs
+	}
+	return nil
+}
+
+// checkEnum checks that a field value, or each item of a list, is allowed
+func checkEnum(field string, v reflect.Value, allowed []string) []FieldError {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return checkEnum(field, v.Elem(), allowed)
+	case reflect.Slice, reflect.Array:
+		var errs []FieldError
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, checkEnum(field, v.Index(i), allowed)...)
+		}
+		return errs
+	}
+
+	var value string
+	switch v.Kind() {
+	case reflect.String:
+		value = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		value = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	default:
+		return nil
+	}
+	if v.IsZero() || slices.Contains(allowed, value) {
+		return nil
+	}
+	return []FieldError{{
+		Field:   field,
+		Tag:     EnumTag,
+		Param:   strings.Join(allowed, ","),
+		Value:   value,
+		Message: fmt.Sprintf("%s must be one of: %s", field, strings.Join(allowed, ", ")),
+	}}
+}
+
+// jsonName returns the name of a struct field in its JSON form
+func jsonName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
@@ -0,0 +1,44 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ExampleTag is the struct tag holding an example value of a field, used in
+// place of a generated one by OpenAPI and CRD schemas, generated tests and
+// client help:
+//
+//	Rack  string   `json:"rack" example:"R12"`
+//	Slots int      `json:"slots" example:"48"`
+//	Tags  []string `json:"tags" example:"[\"edge\",\"gpu\"]"`
+//
+// String fields take the value as is; other fields take it as JSON. It
+// isn't checked against the field's validate or enum tags.
+const ExampleTag = "example"
+
+// ExampleValue returns the example value of a field of type t declared by
+// its example tag, decoded from JSON unless t is a string (or a pointer to
+// one), and whether it has one. Values that aren't valid JSON are returned
+// as strings.
+func ExampleValue(tag reflect.StructTag, t reflect.Type) (interface{}, bool) {
+	raw, ok := tag.Lookup(ExampleTag)
+	if !ok {
+		return nil, false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		return raw, true
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw, true
+	}
+	return value, true
+}
@@ -57,6 +57,7 @@ func (ve ValidationErrors) Error() string {
 type FieldError struct {
 	Field   string `json:"field"`
 	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
 	Value   string `json:"value,omitempty"`
 	Message string `json:"message"`
 }
@@ -85,13 +86,20 @@ func init() {
 	_ = validate.RegisterValidation("dnslabel", validateDNSLabel)
 }
 
-// ValidateResource validates a resource using struct tags
+// ValidateResource validates a resource using struct tags, including the
+// allowed values of enum-tagged fields (see EnumTag)
 func ValidateResource(resource interface{}) error {
+	var fieldErrors []FieldError
 	if err := validate.Struct(resource); err != nil {
-		if validationErrs, ok := err.(validator.ValidationErrors); ok {
-			return formatValidationErrors(validationErrs)
+		validationErrs, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
 		}
-		return err
+		fieldErrors = formatValidationErrors(validationErrs).Errors
+	}
+	fieldErrors = append(fieldErrors, validateEnums(reflect.ValueOf(resource))...)
+	if len(fieldErrors) > 0 {
+		return ValidationErrors{Errors: fieldErrors}
 	}
 	return nil
 }
@@ -113,19 +121,43 @@ func ValidateWithContext(ctx context.Context, resource interface{}) error {
 	return nil
 }
 
+// CheckTags reports the first validate tag of a struct type that can't be
+// evaluated: an unknown validation, such as a custom one not registered
+// with RegisterCustomValidator, or malformed parameters. It validates the
+// zero value of the type, so nested structs behind nil pointers and in
+// empty slices or maps aren't checked.
+func CheckTags(t reflect.Type) (err error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	// The validator panics on tags it can't evaluate
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	_ = validate.Struct(reflect.New(t).Interface())
+	return nil
+}
+
 // CustomValidator interface allows resources to implement custom validation logic
 type CustomValidator interface {
 	Validate(ctx context.Context) error
 }
 
 // formatValidationErrors converts validator errors to user-friendly messages
-func formatValidationErrors(errs validator.ValidationErrors) error {
+func formatValidationErrors(errs validator.ValidationErrors) ValidationErrors {
 	var fieldErrors []FieldError
 
 	for _, err := range errs {
 		fieldErrors = append(fieldErrors, FieldError{
 			Field:   err.Field(),
 			Tag:     err.Tag(),
+			Param:   err.Param(),
 			Value:   fmt.Sprintf("%v", err.Value()),
 			Message: getErrorMessage(err),
 		})
@@ -7,6 +7,9 @@ package validation
 import (
 	"context"
 	"errors"
+	"expvar"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
@@ -460,3 +463,176 @@ func TestRegisterCustomValidator(t *testing.T) {
 		t.Error("Expected validation error for invalid custom validation")
 	}
 }
+
+// Test enum tags
+
+type enumPort struct {
+	Speed int `json:"speed,omitempty" enum:"10,25,100"`
+}
+
+type enumSpec struct {
+	ComponentType string     `json:"componentType" enum:"compute, switch, chassis"`
+	Roles         []string   `json:"roles,omitempty" enum:"boot,storage"`
+	Mode          *string    `json:"mode,omitempty" enum:"auto,manual"`
+	Ports         []enumPort `json:"ports,omitempty"`
+}
+
+type enumResource struct {
+	Name string   `json:"name" validate:"required"`
+	Spec enumSpec `json:"spec"`
+}
+
+func TestValidateResource_Enum(t *testing.T) {
+	valid := enumResource{Name: "node-1", Spec: enumSpec{
+		ComponentType: "switch",
+		Roles:         []string{"boot"},
+		Ports:         []enumPort{{Speed: 25}, {}},
+	}}
+	if err := ValidateResource(&valid); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	// Zero values are not checked
+	if err := ValidateResource(&enumResource{Name: "node-1"}); err != nil {
+		t.Errorf("Expected unset enum fields to be valid, got: %v", err)
+	}
+
+	mode := "hybrid"
+	invalid := enumResource{Spec: enumSpec{
+		ComponentType: "router",
+		Roles:         []string{"boot", "compute"},
+		Mode:          &mode,
+		Ports:         []enumPort{{Speed: 40}},
+	}}
+	err := ValidateResource(&invalid)
+	var validationErrs ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+	}
+	want := map[string]string{
+		"name":          "name is required",
+		"componentType": "componentType must be one of: compute, switch, chassis",
+		"roles":         "roles must be one of: boot, storage",
+		"mode":          "mode must be one of: auto, manual",
+		"speed":         "speed must be one of: 10, 25, 100",
+	}
+	if len(validationErrs.Errors) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), validationErrs.Errors)
+	}
+	for _, fe := range validationErrs.Errors {
+		if want[fe.Field] != fe.Message {
+			t.Errorf("Unexpected error for %s: %q", fe.Field, fe.Message)
+		}
+		if fe.Field == "roles" && (fe.Tag != EnumTag || fe.Value != "compute") {
+			t.Errorf("Expected the rejected item to be reported, got %+v", fe)
+		}
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	field, _ := reflect.TypeOf(enumSpec{}).FieldByName("ComponentType")
+	if got := EnumValues(field.Tag); !reflect.DeepEqual(got, []string{"compute", "switch", "chassis"}) {
+		t.Errorf("Expected [compute switch chassis], got %v", got)
+	}
+	field, _ = reflect.TypeOf(enumSpec{}).FieldByName("Ports")
+	if got := EnumValues(field.Tag); got != nil {
+		t.Errorf("Expected no values without an enum tag, got %v", got)
+	}
+}
+
+func TestExampleValue(t *testing.T) {
+	type exampleSpec struct {
+		Rack  string   `json:"rack" example:"42"`
+		Mode  *string  `json:"mode" example:"auto"`
+		Slots int      `json:"slots" example:"48"`
+		Tags  []string `json:"tags" example:"[\"edge\",\"gpu\"]"`
+		Size  int      `json:"size" example:"large"`
+		Note  string   `json:"note"`
+	}
+	tests := []struct {
+		field string
+		want  interface{}
+	}{
+		{"Rack", "42"},
+		{"Mode", "auto"},
+		{"Slots", float64(48)},
+		{"Tags", []interface{}{"edge", "gpu"}},
+		{"Size", "large"},
+	}
+	for _, tt := range tests {
+		field, _ := reflect.TypeOf(exampleSpec{}).FieldByName(tt.field)
+		got, ok := ExampleValue(field.Tag, field.Type)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %#v, got %#v (%v)", tt.field, tt.want, got, ok)
+		}
+	}
+	field, _ := reflect.TypeOf(exampleSpec{}).FieldByName("Note")
+	if _, ok := ExampleValue(field.Tag, field.Type); ok {
+		t.Error("Expected no example without an example tag")
+	}
+}
+
+// Test warn mode helpers
+
+func TestViolationsAndWarnings(t *testing.T) {
+	err := ValidateResource(&TestResource{Description: strings.Repeat("x", 101)})
+	violations := Violations(err)
+	if len(violations) != 2 || violations[0] != "name is required" {
+		t.Fatalf("Expected one violation per field, got %v", violations)
+	}
+	custom := errors.New(`name "forbidden" is not allowed`)
+	if got := Violations(custom); !reflect.DeepEqual(got, []string{custom.Error()}) {
+		t.Errorf("Expected the error message, got %v", got)
+	}
+	if Violations(nil) != nil {
+		t.Error("Expected no violations for nil")
+	}
+
+	if got := WarningHeader(custom.Error() + "\n"); got != `299 - "name \"forbidden\" is not allowed "` {
+		t.Errorf("Unexpected Warning header %s", got)
+	}
+
+	RecordWarnings("TestResource", err)
+	RecordWarnings("TestResource", err)
+	RecordWarnings("TestResource", custom)
+	counts := expvar.Get(WarningMetric).(*expvar.Map)
+	if got := counts.Get("TestResource.name").String(); got != "2" {
+		t.Errorf("Expected 2 warnings for name, got %s", got)
+	}
+	if got := counts.Get("TestResource").String(); got != "1" {
+		t.Errorf("Expected 1 warning without a field, got %s", got)
+	}
+}
+
+func TestCheckTags(t *testing.T) {
+	type unknown struct {
+		Name string `json:"name" validate:"required,nosuchvalidation"`
+	}
+	type nested struct {
+		Inner unknown `json:"inner"`
+	}
+
+	if err := CheckTags(reflect.TypeOf(TestResource{})); err != nil {
+		t.Errorf("CheckTags(TestResource) = %v, want nil", err)
+	}
+	for _, typ := range []reflect.Type{reflect.TypeOf(unknown{}), reflect.TypeOf(&nested{})} {
+		err := CheckTags(typ)
+		if err == nil || !strings.Contains(err.Error(), "nosuchvalidation") {
+			t.Errorf("CheckTags(%s) = %v, want an error naming the unknown validation", typ, err)
+		}
+	}
+
+	// Custom validations are known once registered
+	type custom struct {
+		Name string `json:"name" validate:"checktagscustom"`
+	}
+	if err := CheckTags(reflect.TypeOf(custom{})); err == nil {
+		t.Fatal("CheckTags should report an unregistered custom validation")
+	}
+	if err := RegisterCustomValidator("checktagscustom", func(validator.FieldLevel) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTags(reflect.TypeOf(custom{})); err != nil {
+		t.Errorf("CheckTags(custom) = %v after registering the validation", err)
+	}
+}
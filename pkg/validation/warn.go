@@ -0,0 +1,84 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package validation
+
+import (
+	"errors"
+	"expvar"
+	"strings"
+)
+
+// Validation modes, set with features.validation.mode in .fabrica.yaml
+const (
+	// ModeStrict rejects invalid resources with 400 Bad Request
+	ModeStrict = "strict"
+	// ModeWarn accepts invalid resources and reports the violations in
+	// Warning response headers
+	ModeWarn = "warn"
+	// ModeDisabled skips validation
+	ModeDisabled = "disabled"
+)
+
+// WarningMetric is the expvar name of the violations counted by
+// RecordWarnings, a map keyed by "<Kind>.<field>"
+const WarningMetric = "validation_warnings"
+
+var warningCounts = expvar.NewMap(WarningMetric)
+
+// Violations returns one message per violation of a failed validation:
+// each field error of ValidationErrors, or the message of any other error.
+func Violations(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs.Errors) == 0 {
+		return []string{err.Error()}
+	}
+	msgs := make([]string, len(verrs.Errors))
+	for i, fe := range verrs.Errors {
+		msgs[i] = fe.Message
+	}
+	return msgs
+}
+
+// WarningHeader formats a Warning header value (RFC 9111 warn-code 299,
+// as used by Kubernetes API servers), e.g. `299 - "size must be at least 1"`.
+func WarningHeader(msg string) string {
+	var b strings.Builder
+	b.WriteString(`299 - "`)
+	for _, r := range msg {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < ' ' || r == 0x7f:
+			// Control characters aren't allowed in header values
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// RecordWarnings counts the violations of a failed validation of a kind in
+// the WarningMetric expvar map: one per field error under "<Kind>.<field>",
+// or one under "<Kind>" for other errors. Counts show which rules would
+// reject requests before switching from warn to strict mode.
+func RecordWarnings(kind string, err error) {
+	if err == nil {
+		return
+	}
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs.Errors) == 0 {
+		warningCounts.Add(kind, 1)
+		return
+	}
+	for _, fe := range verrs.Errors {
+		warningCounts.Add(kind+"."+fe.Field, 1)
+	}
+}
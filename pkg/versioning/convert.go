@@ -0,0 +1,371 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package versioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/openchami/fabrica/pkg/errcode"
+)
+
+// SchemaVersionHeader is the response header naming the schema version a
+// resource route served.
+const SchemaVersionHeader = "X-Schema-Version"
+
+// ServeVersion returns middleware serving the resources of kind in version,
+// for route trees such as /v2/devices, whichever version they're stored in.
+// Mount it on the collection route of the kind:
+//
+//	r.Route("/v2", func(r chi.Router) {
+//	    r.Route("/devices", func(r chi.Router) {
+//	        r.Use(versioning.ServeVersion(versioning.GlobalVersionRegistry, "Device", "v2"))
+//	        r.Post("/", CreateDevice)
+//	        // ...
+//	    })
+//	})
+//
+// Resources are stored in the default version of their kind in registry. In
+// other versions, the JSON bodies of creates (POST /devices), updates
+// (PUT /devices/{uid}) and status updates (PUT /devices/{uid}/status) are
+// converted to the default version, and the resources of kind in JSON
+// responses back to version, with the converter registered for the kind.
+// PATCH requests get 405 Method Not Allowed in these versions, as a patch
+// can't be converted without the resource it applies to, and responses are
+// always JSON. Versions not registered for kind get 404 Not Found.
+//
+// Kinds without registered versions are served unchanged in every version.
+//
+// An empty version negotiates it from the Accept header (see NegotiateVersion).
+func ServeVersion(registry *VersionRegistry, kind, version string) func(http.Handler) http.Handler {
+	if version == "" {
+		return NegotiateVersion(registry, kind)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serveVersion(registry, kind, version, http.StatusNotFound, next, w, r)
+		})
+	}
+}
+
+// NegotiateVersion returns middleware serving the resources of kind in the
+// version the Accept header requests, e.g. application/json;version=v2, or
+// in the default version of the kind when it requests none. Versions not
+// registered for kind get 406 Not Acceptable. Conversion works as in
+// ServeVersion.
+func NegotiateVersion(registry *VersionRegistry, kind string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			version := parseVersionFromAcceptHeader(r.Header.Get("Accept"))
+			if version == "" {
+				version = registry.GetDefaultVersion(kind)
+			}
+			if version == "" {
+				// Neither requested nor registered: served as before versioning
+				next.ServeHTTP(w, r)
+				return
+			}
+			serveVersion(registry, kind, version, http.StatusNotAcceptable, next, w, r)
+		})
+	}
+}
+
+// serveVersion serves a request for the resources of kind in version,
+// answering unsupportedStatus when the kind isn't registered in version
+func serveVersion(registry *VersionRegistry, kind, version string, unsupportedStatus int, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	stored := registry.GetDefaultVersion(kind)
+	if stored == "" {
+		ctx := &VersionContext{
+			RequestedVersion: version,
+			DefaultVersion:   version,
+			ServeVersion:     version,
+			GroupVersion:     version,
+			ResourceKind:     kind,
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), VersionContextKeyName, ctx)))
+		return
+	}
+	if _, ok := registry.GetVersion(kind, version); !ok {
+		writeProblem(w, unsupportedStatus, errcode.UnsupportedVersion, fmt.Errorf("%s is not served in version %s (versions: %s)",
+			kind, version, strings.Join(registry.ListVersions(kind), ", ")))
+		return
+	}
+
+	// Handlers work in the stored version; the conversion is done here
+	ctx := &VersionContext{
+		RequestedVersion: version,
+		DefaultVersion:   stored,
+		ServeVersion:     stored,
+		GroupVersion:     stored,
+		ResourceKind:     kind,
+	}
+	r = r.WithContext(context.WithValue(r.Context(), VersionContextKeyName, ctx))
+	w.Header().Set(SchemaVersionHeader, version)
+	if version == stored {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	c := &converter{registry: registry, kind: kind, stored: stored, version: version}
+	if r.Method == http.MethodPatch {
+		writeProblem(w, http.StatusMethodNotAllowed, errcode.UnsupportedVersion,
+			fmt.Errorf("%s can't be patched in version %s; use PUT, or PATCH in version %s", kind, version, stored))
+		return
+	}
+	if part := requestPart(r); part != "" {
+		if contentType := r.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "json") {
+			writeProblem(w, http.StatusUnsupportedMediaType, errcode.ForStatus(http.StatusUnsupportedMediaType),
+				fmt.Errorf("%s bodies in version %s must be JSON, not %s", kind, version, contentType))
+			return
+		}
+		if err := c.convertRequest(r, part); err != nil {
+			writeProblem(w, http.StatusBadRequest, errcode.InvalidRequest, err)
+			return
+		}
+	}
+
+	buf := &bufferedResponse{header: w.Header()}
+	next.ServeHTTP(buf, r)
+	c.writeResponse(w, buf)
+}
+
+// requestPart returns the part of the resource a request body holds that
+// is converted, "spec" or "status", or "" when the body isn't converted
+func requestPart(r *http.Request) string {
+	path := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		// Path within the collection route the middleware is mounted on
+		path = rctx.RoutePath
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] == "" {
+		segments = nil
+	}
+	switch {
+	case r.Method == http.MethodPost && len(segments) == 0:
+		return "spec"
+	case r.Method == http.MethodPut && len(segments) == 1:
+		return "spec"
+	case r.Method == http.MethodPut && len(segments) == 2 && segments[1] == "status":
+		return "status"
+	}
+	return ""
+}
+
+// converter converts the resources of a kind between the stored version
+// and the version a request is served in
+type converter struct {
+	registry *VersionRegistry
+	kind     string
+	stored   string
+	version  string
+}
+
+// convert converts a resource decoded from JSON between versions, returning
+// it decoded from JSON again
+func (c *converter) convert(obj map[string]interface{}, from, to string) (map[string]interface{}, error) {
+	info, ok := c.registry.GetVersion(c.kind, from)
+	if !ok || info.Constructor == nil {
+		return nil, fmt.Errorf("no constructor registered for %s version %s", c.kind, from)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	typed := info.Constructor()
+	if err := json.Unmarshal(data, typed); err != nil {
+		return nil, fmt.Errorf("invalid %s in version %s: %w", c.kind, from, err)
+	}
+	converted, err := c.registry.Convert(c.kind, typed, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s from %s to %s: %w", c.kind, from, to, err)
+	}
+	data, err = json.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := decodeJSON(data, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	out["apiVersion"] = to
+	out["schemaVersion"] = to
+	return out, nil
+}
+
+// convertRequest converts the JSON body of a create, update or status
+// update from the requested version to the stored one. Create and update
+// bodies hold the spec fields with name, labels and annotations next to
+// them; status update bodies hold the status.
+func (c *converter) convertRequest(r *http.Request, part string) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %w", err)
+	}
+	_ = r.Body.Close()
+
+	var body map[string]interface{}
+	if err := decodeJSON(data, &body); err != nil || body == nil {
+		// Left to the handler, which reports invalid bodies
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		return nil
+	}
+
+	obj := map[string]interface{}{"kind": c.kind, "apiVersion": c.version, "schemaVersion": c.version}
+	metadata := map[string]interface{}{}
+	if part == "status" {
+		obj["spec"] = map[string]interface{}{}
+		obj["status"] = body
+	} else {
+		spec := map[string]interface{}{}
+		for key, value := range body {
+			switch key {
+			case "name", "labels", "annotations":
+				metadata[key] = value
+			default:
+				spec[key] = value
+			}
+		}
+		obj["spec"] = spec
+	}
+	obj["metadata"] = metadata
+
+	converted, err := c.convert(obj, c.version, c.stored)
+	if err != nil {
+		return err
+	}
+	var out map[string]interface{}
+	if part == "status" {
+		out, _ = converted["status"].(map[string]interface{})
+	} else {
+		out, _ = converted["spec"].(map[string]interface{})
+		if out == nil {
+			out = map[string]interface{}{}
+		}
+		for key, value := range metadata {
+			out[key] = value
+		}
+	}
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	data, err = json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	r.ContentLength = int64(len(data))
+	r.Header.Set("Content-Length", fmt.Sprint(len(data)))
+	return nil
+}
+
+// writeResponse writes a buffered response, with the resources of the kind
+// in successful JSON responses converted to the requested version
+func (c *converter) writeResponse(w http.ResponseWriter, buf *bufferedResponse) {
+	status := buf.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := buf.body.Bytes()
+	contentType := w.Header().Get("Content-Type")
+	if status >= 200 && status < 300 && buf.body.Len() > 0 &&
+		strings.Contains(contentType, "json") && !strings.Contains(contentType, "problem") {
+		var data interface{}
+		if err := decodeJSON(body, &data); err == nil {
+			converted, err := c.convertResponse(data)
+			if err != nil {
+				writeProblem(w, http.StatusInternalServerError, errcode.Internal, err)
+				return
+			}
+			if body, err = json.Marshal(converted); err != nil {
+				writeProblem(w, http.StatusInternalServerError, errcode.Internal, err)
+				return
+			}
+			body = append(body, '\n')
+		}
+	}
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// convertResponse converts the resources of the kind found in a decoded
+// JSON response: the response itself, or those in its arrays and objects,
+// such as list items
+func (c *converter) convertResponse(data interface{}) (interface{}, error) {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		if kind, _ := value["kind"].(string); kind == c.kind {
+			if _, ok := value["spec"]; ok {
+				return c.convert(value, c.stored, c.version)
+			}
+		}
+		for key, item := range value {
+			converted, err := c.convertResponse(item)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = converted
+		}
+	case []interface{}:
+		for i, item := range value {
+			converted, err := c.convertResponse(item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = converted
+		}
+	}
+	return data, nil
+}
+
+// bufferedResponse holds a response until it is converted. It doesn't
+// unwrap to the writer it buffers for, so handlers answer in JSON.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// decodeJSON decodes JSON keeping numbers as written
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// writeProblem writes a problem document with the error code
+func writeProblem(w http.ResponseWriter, status int, code errcode.Code, err error) {
+	w.Header().Del(SchemaVersionHeader)
+	w.Header().Set("Content-Type", errcode.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errcode.NewProblem(status, errcode.Wrap(code, err)))
+}
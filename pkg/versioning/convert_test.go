@@ -0,0 +1,347 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package versioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Device in the stored version v1, with flat credentials
+type deviceV1 struct {
+	APIVersion    string                 `json:"apiVersion"`
+	Kind          string                 `json:"kind"`
+	SchemaVersion string                 `json:"schemaVersion"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	Spec          struct {
+		Location string `json:"location"`
+		Username string `json:"username"`
+	} `json:"spec"`
+	Status struct {
+		Power string `json:"power,omitempty"`
+	} `json:"status"`
+}
+
+// Device in v2, with the credentials in an auth object and power renamed
+type deviceV2 struct {
+	APIVersion    string                 `json:"apiVersion"`
+	Kind          string                 `json:"kind"`
+	SchemaVersion string                 `json:"schemaVersion"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	Spec          struct {
+		Location string `json:"location"`
+		Auth     struct {
+			Username string `json:"username"`
+		} `json:"auth"`
+	} `json:"spec"`
+	Status struct {
+		PowerState string `json:"powerState,omitempty"`
+	} `json:"status"`
+}
+
+type deviceConverter struct{}
+
+func (deviceConverter) CanConvert(from, to string) bool {
+	return (from == "v1" && to == "v2") || (from == "v2" && to == "v1")
+}
+
+func (deviceConverter) Convert(resource interface{}, from, to string) (interface{}, error) {
+	switch d := resource.(type) {
+	case *deviceV1:
+		out := &deviceV2{APIVersion: d.APIVersion, Kind: d.Kind, Metadata: d.Metadata}
+		out.Spec.Location = d.Spec.Location
+		out.Spec.Auth.Username = d.Spec.Username
+		out.Status.PowerState = d.Status.Power
+		return out, nil
+	case *deviceV2:
+		out := &deviceV1{APIVersion: d.APIVersion, Kind: d.Kind, Metadata: d.Metadata}
+		out.Spec.Location = d.Spec.Location
+		out.Spec.Username = d.Spec.Auth.Username
+		out.Status.Power = d.Status.PowerState
+		return out, nil
+	}
+	return nil, fmt.Errorf("unexpected %T", resource)
+}
+
+func (deviceConverter) ConvertSpec(spec interface{}, from, to string) (interface{}, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (deviceConverter) ConvertStatus(status interface{}, from, to string) (interface{}, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func testRegistry(t *testing.T) *VersionRegistry {
+	t.Helper()
+	registry := NewVersionRegistry()
+	for version, constructor := range map[string]func() interface{}{
+		"v1": func() interface{} { return &deviceV1{} },
+		"v2": func() interface{} { return &deviceV2{} },
+	} {
+		err := registry.RegisterVersion("Device", version, ResourceTypeInfo{
+			Type:        reflect.TypeOf(constructor()),
+			Constructor: constructor,
+			Converter:   deviceConverter{},
+			Metadata:    SchemaVersion{Version: version, IsDefault: version == "v1"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return registry
+}
+
+// deviceStore is a handler storing the devices it's sent in v1, like
+// generated handlers do with the stored version
+type deviceStore struct {
+	devices map[string]map[string]interface{}
+	bodies  []map[string]interface{}
+	ctx     *VersionContext
+}
+
+func (s *deviceStore) routes(r chi.Router) {
+	respond := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	decode := func(r *http.Request) map[string]interface{} {
+		s.ctx = GetVersionContext(r.Context())
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.bodies = append(s.bodies, body)
+		return body
+	}
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		items := []interface{}{}
+		for _, d := range s.devices {
+			items = append(items, d)
+		}
+		respond(w, http.StatusOK, map[string]interface{}{"items": items, "total": len(items)})
+	})
+	r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+		spec := map[string]interface{}{}
+		for key, value := range decode(r) {
+			spec[key] = value
+		}
+		name := spec["name"]
+		delete(spec, "name")
+		delete(spec, "labels")
+		device := map[string]interface{}{
+			"apiVersion":    s.ctx.GroupVersion,
+			"kind":          "Device",
+			"schemaVersion": s.ctx.ServeVersion,
+			"metadata":      map[string]interface{}{"name": name, "uid": "dev-1"},
+			"spec":          spec,
+			"status":        map[string]interface{}{},
+		}
+		s.devices["dev-1"] = device
+		respond(w, http.StatusCreated, device)
+	})
+	r.Get("/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		device, ok := s.devices[chi.URLParam(r, "uid")]
+		if !ok {
+			respond(w, http.StatusNotFound, map[string]string{"error": "not found"})
+			return
+		}
+		respond(w, http.StatusOK, device)
+	})
+	r.Patch("/{uid}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Put("/{uid}/status", func(w http.ResponseWriter, r *http.Request) {
+		device := s.devices[chi.URLParam(r, "uid")]
+		device["status"] = decode(r)
+		respond(w, http.StatusOK, device)
+	})
+	r.Post("/{uid}/actions/reboot", func(w http.ResponseWriter, r *http.Request) {
+		decode(r)
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// versionedServer serves the devices of store under /v1 and /v2, and
+// unversioned with version negotiation
+func versionedServer(registry *VersionRegistry, store *deviceStore) http.Handler {
+	r := chi.NewRouter()
+	for _, version := range []string{"v1", "v2", "v3", ""} {
+		mount := func(r chi.Router) {
+			r.Route("/devices", func(r chi.Router) {
+				r.Use(ServeVersion(registry, "Device", version))
+				store.routes(r)
+			})
+		}
+		if version == "" {
+			mount(r)
+		} else {
+			r.Route("/"+version, mount)
+		}
+	}
+	return r
+}
+
+func do(t *testing.T, h http.Handler, method, path, accept, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var out map[string]interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec, out
+}
+
+func TestServeVersion_ConvertsCreateAndResponses(t *testing.T) {
+	store := &deviceStore{devices: map[string]map[string]interface{}{}}
+	h := versionedServer(testRegistry(t), store)
+
+	rec, created := do(t, h, http.MethodPost, "/v2/devices", "",
+		`{"name":"bmc-1","labels":{"rack":"r1"},"location":"dc1","auth":{"username":"admin"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// The handler is sent the v1 body, and works in v1
+	body := store.bodies[0]
+	if body["username"] != "admin" || body["location"] != "dc1" || body["auth"] != nil {
+		t.Errorf("expected a v1 create body, got %v", body)
+	}
+	if body["name"] != "bmc-1" || body["labels"] == nil {
+		t.Errorf("expected name and labels kept, got %v", body)
+	}
+	if store.ctx.ServeVersion != "v1" || store.ctx.RequestedVersion != "v2" {
+		t.Errorf("expected the handler to serve v1 for v2, got %+v", store.ctx)
+	}
+
+	// The response is v2
+	if created["apiVersion"] != "v2" || created["schemaVersion"] != "v2" {
+		t.Errorf("expected a v2 response, got %v", created)
+	}
+	spec := created["spec"].(map[string]interface{})
+	if auth, _ := spec["auth"].(map[string]interface{}); auth["username"] != "admin" {
+		t.Errorf("expected spec.auth.username in v2, got %v", spec)
+	}
+	if rec.Header().Get(SchemaVersionHeader) != "v2" {
+		t.Errorf("expected %s v2, got %q", SchemaVersionHeader, rec.Header().Get(SchemaVersionHeader))
+	}
+
+	// Stored in v1
+	if stored := store.devices["dev-1"]; stored["schemaVersion"] != "v1" {
+		t.Errorf("expected the device stored in v1, got %v", stored)
+	}
+
+	// Read back in each version
+	_, v1 := do(t, h, http.MethodGet, "/v1/devices/dev-1", "", "")
+	if v1["spec"].(map[string]interface{})["username"] != "admin" {
+		t.Errorf("expected spec.username in v1, got %v", v1)
+	}
+	_, list := do(t, h, http.MethodGet, "/v2/devices", "", "")
+	items := list["items"].([]interface{})
+	if len(items) != 1 || items[0].(map[string]interface{})["apiVersion"] != "v2" {
+		t.Errorf("expected list items in v2, got %v", list)
+	}
+	if list["total"] != float64(1) {
+		t.Errorf("expected other list fields kept, got %v", list)
+	}
+}
+
+func TestServeVersion_ConvertsStatusUpdates(t *testing.T) {
+	store := &deviceStore{devices: map[string]map[string]interface{}{}}
+	h := versionedServer(testRegistry(t), store)
+	do(t, h, http.MethodPost, "/v1/devices", "", `{"name":"bmc-1","location":"dc1","username":"admin"}`)
+
+	rec, updated := do(t, h, http.MethodPut, "/v2/devices/dev-1/status", "", `{"powerState":"on"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := store.bodies[len(store.bodies)-1]; got["power"] != "on" {
+		t.Errorf("expected a v1 status body, got %v", got)
+	}
+	if updated["status"].(map[string]interface{})["powerState"] != "on" {
+		t.Errorf("expected the v2 status in the response, got %v", updated)
+	}
+}
+
+func TestServeVersion_LeavesOtherBodies(t *testing.T) {
+	store := &deviceStore{devices: map[string]map[string]interface{}{}}
+	h := versionedServer(testRegistry(t), store)
+
+	rec, _ := do(t, h, http.MethodPost, "/v2/devices/dev-1/actions/reboot", "", `{"force":true}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := store.bodies[0]; got["force"] != true || len(got) != 1 {
+		t.Errorf("expected the action body unchanged, got %v", got)
+	}
+}
+
+func TestServeVersion_RejectsPatchAndUnknownVersions(t *testing.T) {
+	store := &deviceStore{devices: map[string]map[string]interface{}{}}
+	h := versionedServer(testRegistry(t), store)
+
+	rec, problem := do(t, h, http.MethodPatch, "/v2/devices/dev-1", "", `{"location":"dc2"}`)
+	if rec.Code != http.StatusMethodNotAllowed || problem["code"] != "UNSUPPORTED_VERSION" {
+		t.Errorf("expected 405 UNSUPPORTED_VERSION for a v2 patch, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec, _ := do(t, h, http.MethodPatch, "/v1/devices/dev-1", "", `{"location":"dc2"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected patches in the stored version to pass, got %d", rec.Code)
+	}
+
+	rec, problem = do(t, h, http.MethodGet, "/v3/devices", "", "")
+	if rec.Code != http.StatusNotFound || problem["code"] != "UNSUPPORTED_VERSION" {
+		t.Errorf("expected 404 UNSUPPORTED_VERSION for v3, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	store := &deviceStore{devices: map[string]map[string]interface{}{}}
+	h := versionedServer(testRegistry(t), store)
+	do(t, h, http.MethodPost, "/devices", "", `{"name":"bmc-1","location":"dc1","username":"admin"}`)
+
+	rec, device := do(t, h, http.MethodGet, "/devices/dev-1", "", "")
+	if rec.Code != http.StatusOK || device["schemaVersion"] != "v1" {
+		t.Errorf("expected the default version without Accept, got %d %v", rec.Code, device)
+	}
+
+	rec, device = do(t, h, http.MethodGet, "/devices/dev-1", "application/json;version=v2", "")
+	if rec.Code != http.StatusOK || device["schemaVersion"] != "v2" {
+		t.Errorf("expected v2 for Accept version=v2, got %d %v", rec.Code, device)
+	}
+
+	rec, problem := do(t, h, http.MethodGet, "/devices/dev-1", "application/json;version=v9", "")
+	if rec.Code != http.StatusNotAcceptable || problem["code"] != "UNSUPPORTED_VERSION" {
+		t.Errorf("expected 406 UNSUPPORTED_VERSION for v9, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServeVersion_UnregisteredKind(t *testing.T) {
+	registry := NewVersionRegistry()
+	var ctx *VersionContext
+	h := ServeVersion(registry, "Sensor", "v2")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = GetVersionContext(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"Sensor","spec":{"unit":"C"}}`))
+	}))
+
+	rec, _ := do(t, h, http.MethodGet, "/", "", "")
+	if rec.Body.String() != `{"kind":"Sensor","spec":{"unit":"C"}}` {
+		t.Errorf("expected the response unchanged, got %s", rec.Body.String())
+	}
+	if ctx.GroupVersion != "v2" || ctx.ServeVersion != "v2" {
+		t.Errorf("expected the handler to serve v2, got %+v", ctx)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -156,7 +157,7 @@ func (vr *VersionRegistry) ListVersions(kind string) []string {
 
 	// Sort versions for consistent ordering
 	sort.Slice(versions, func(i, j int) bool {
-		return compareVersions(versions[i], versions[j]) < 0
+		return CompareVersions(versions[i], versions[j]) < 0
 	})
 
 	return versions
@@ -196,30 +197,36 @@ func (vr *VersionRegistry) GetVersionInfo(kind string) map[string]SchemaVersion
 
 // CanConvert checks if conversion is possible between two versions
 func (vr *VersionRegistry) CanConvert(kind, fromVersion, toVersion string) bool {
-	fromInfo, fromExists := vr.GetVersion(kind, fromVersion)
-	if !fromExists || fromInfo.Converter == nil {
-		return false
-	}
-
-	return fromInfo.Converter.CanConvert(fromVersion, toVersion)
+	_, ok := vr.converter(kind, fromVersion, toVersion)
+	return ok
 }
 
 // Convert performs version conversion using the registered converter
 func (vr *VersionRegistry) Convert(kind string, resource interface{}, fromVersion, toVersion string) (interface{}, error) {
-	fromInfo, fromExists := vr.GetVersion(kind, fromVersion)
-	if !fromExists {
+	if _, fromExists := vr.GetVersion(kind, fromVersion); !fromExists {
 		return nil, fmt.Errorf("source version %s not registered for kind %s", fromVersion, kind)
 	}
 
-	if fromInfo.Converter == nil {
-		return nil, fmt.Errorf("no converter available for kind %s version %s", kind, fromVersion)
-	}
-
-	if !fromInfo.Converter.CanConvert(fromVersion, toVersion) {
+	converter, ok := vr.converter(kind, fromVersion, toVersion)
+	if !ok {
 		return nil, fmt.Errorf("conversion not supported: %s -> %s for kind %s", fromVersion, toVersion, kind)
 	}
 
-	return fromInfo.Converter.Convert(resource, fromVersion, toVersion)
+	return converter.Convert(resource, fromVersion, toVersion)
+}
+
+// converter returns the converter of the source version, or else of the
+// target version, that converts between the versions. Falling back to the
+// target lets a later version bring the conversion from and to the stored
+// version, which is registered without a converter.
+func (vr *VersionRegistry) converter(kind, fromVersion, toVersion string) (VersionConverter, bool) {
+	for _, version := range []string{fromVersion, toVersion} {
+		info, exists := vr.GetVersion(kind, version)
+		if exists && info.Converter != nil && info.Converter.CanConvert(fromVersion, toVersion) {
+			return info.Converter, true
+		}
+	}
+	return nil, false
 }
 
 // GetStabilityLevel returns the stability level of a version
@@ -244,18 +251,52 @@ func isValidVersion(version string) bool {
 	return versionRegex.MatchString(version)
 }
 
-// compareVersions compares two version strings for sorting
+// CompareVersions orders Kubernetes-style versions: by major number, then
+// alpha before beta before the stable release, then by pre-release number,
+// so v1alpha1 < v1beta2 < v1 < v2beta1 < v2 < v10. Versions that don't
+// match the format sort after those that do, lexicographically.
 // Returns: -1 if a < b, 0 if a == b, 1 if a > b
-func compareVersions(a, b string) int {
-	// Simple lexicographic comparison for now
-	// Could be enhanced with proper semantic version comparison
-	if a < b {
+func CompareVersions(a, b string) int {
+	ka, okA := versionKey(a)
+	kb, okB := versionKey(b)
+	switch {
+	case okA && !okB:
 		return -1
-	}
-	if a > b {
+	case !okA && okB:
 		return 1
+	case okA && okB:
+		for i := range ka {
+			if ka[i] != kb[i] {
+				if ka[i] < kb[i] {
+					return -1
+				}
+				return 1
+			}
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+var versionFormat = regexp.MustCompile(`^v([0-9]+)(?:(alpha|beta)([0-9]+))?$`)
+
+// versionKey returns the major number, stability rank (alpha 0, beta 1,
+// stable 2) and pre-release number of a version
+func versionKey(version string) ([3]int, bool) {
+	m := versionFormat.FindStringSubmatch(version)
+	if m == nil {
+		return [3]int{}, false
+	}
+	major, _ := strconv.Atoi(m[1])
+	rank, pre := 2, 0
+	if m[2] != "" {
+		rank = 0
+		if m[2] == "beta" {
+			rank = 1
+		}
+		pre, _ = strconv.Atoi(m[3])
 	}
-	return 0
+	return [3]int{major, rank, pre}, true
 }
 
 // GlobalVersionRegistry is the global version registry instance for managing API versions
@@ -0,0 +1,58 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package versioning
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	versions := []string{"v10", "v2", "custom", "v1", "v2beta1", "v1alpha2", "v1beta1", "v1alpha1"}
+	sort.Slice(versions, func(i, j int) bool { return CompareVersions(versions[i], versions[j]) < 0 })
+
+	want := []string{"v1alpha1", "v1alpha2", "v1beta1", "v1", "v2beta1", "v2", "v10", "custom"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("sorted versions = %v, want %v", versions, want)
+	}
+	if CompareVersions("v2", "v2") != 0 {
+		t.Error("a version should compare equal to itself")
+	}
+}
+
+func TestConvert_UsesTargetConverter(t *testing.T) {
+	registry := NewVersionRegistry()
+	err := registry.RegisterVersion("Device", "v1", ResourceTypeInfo{
+		Metadata: SchemaVersion{Version: "v1", IsDefault: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = registry.RegisterVersion("Device", "v2", ResourceTypeInfo{
+		Converter: deviceConverter{},
+		Metadata:  SchemaVersion{Version: "v2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !registry.CanConvert("Device", "v1", "v2") || !registry.CanConvert("Device", "v2", "v1") {
+		t.Fatal("v1 and v2 should convert with the converter of v2")
+	}
+	if registry.CanConvert("Device", "v1", "v3") {
+		t.Error("v1 shouldn't convert to an unregistered version")
+	}
+
+	in := &deviceV1{}
+	in.Spec.Username = "admin"
+	out, err := registry.Convert("Device", in, "v1", "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.(*deviceV2).Spec.Auth.Username; got != "admin" {
+		t.Errorf("converted username = %q, want admin", got)
+	}
+}
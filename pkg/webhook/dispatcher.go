@@ -0,0 +1,404 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/logging"
+)
+
+// Headers of webhook deliveries
+const (
+	// IDHeader carries the CloudEvent ID, the same across retries, so
+	// receivers can drop duplicates
+	IDHeader = "X-Webhook-Id"
+
+	// SubscriptionHeader carries the UID of the subscription
+	SubscriptionHeader = "X-Webhook-Subscription"
+
+	// TimestampHeader carries the Unix time the delivery attempt was signed
+	TimestampHeader = "X-Webhook-Timestamp"
+
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body, keyed with the subscription's secret
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// ContentType is the media type of delivered events: structured CloudEvents
+// in JSON.
+const ContentType = "application/cloudevents+json"
+
+// ErrInvalidSignature is returned by Verify for deliveries not signed with
+// the secret, or signed too long ago.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Defaults of Options
+const (
+	DefaultWorkers        = 4
+	DefaultQueueSize      = 1000
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+	DefaultTimeout        = 10 * time.Second
+)
+
+// Options configure a Dispatcher. Zero values use the defaults.
+type Options struct {
+	Workers        int           // Concurrent deliveries (default: 4)
+	QueueSize      int           // Deliveries waiting for a worker (default: 1000)
+	MaxAttempts    int           // Attempts per delivery, including the first (default: 5)
+	InitialBackoff time.Duration // Wait before the first retry, doubled for each next one (default: 1s)
+	MaxBackoff     time.Duration // Longest wait between retries (default: 1m)
+	Timeout        time.Duration // Timeout of one attempt (default: 10s)
+	Client         *http.Client  // HTTP client (default: one with Timeout)
+}
+
+// delivery is an event queued for a subscription
+type delivery struct {
+	subscription string
+	eventID      string
+	eventType    string
+	body         []byte
+}
+
+// Dispatcher delivers resource events to the matching webhook
+// subscriptions.
+//
+// Deliveries are queued and posted by a pool of workers. Attempts answered
+// with a 2xx status succeed; 4xx statuses other than 408 and 429 fail right
+// away, and other failures are retried with exponential backoff up to
+// MaxAttempts. Each outcome is recorded in the subscription's status.
+//
+// Deliveries are at least once while the process runs: queued deliveries
+// are lost on restart.
+type Dispatcher struct {
+	manager *Manager
+	opts    Options
+	client  *http.Client
+	queue   chan delivery
+	logger  *slog.Logger
+	now     func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// mu guards closed, so no delivery is queued after shutdown begins
+	mu        sync.RWMutex
+	closed    bool
+	startOnce sync.Once
+}
+
+// NewDispatcher creates a dispatcher of the subscriptions of manager.
+//
+// Parameters:
+//   - manager: The webhook subscriptions
+//   - opts: Worker, retry and HTTP settings
+//
+// Returns:
+//   - *Dispatcher: A dispatcher (must call Start())
+func NewDispatcher(manager *Manager, opts Options) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		manager: manager,
+		opts:    opts,
+		client:  client,
+		queue:   make(chan delivery, opts.QueueSize),
+		logger:  logging.Component(slog.Default(), logging.ComponentEvents),
+		now:     time.Now,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start starts the delivery workers. Calling Start more than once has no
+// effect.
+func (d *Dispatcher) Start() {
+	d.startOnce.Do(func() {
+		for i := 0; i < d.opts.Workers; i++ {
+			d.wg.Add(1)
+			go d.worker()
+		}
+	})
+}
+
+// Subscribe delivers the resource events published on bus.
+//
+// Parameters:
+//   - bus: The event bus resource events are published on
+//   - prefix: The resource event type prefix (events.GetEventConfig().EventTypePrefix)
+//
+// Returns:
+//   - events.SubscriptionID: The subscription, for Unsubscribe
+//   - error: If the subscription fails
+func (d *Dispatcher) Subscribe(bus events.EventBus, prefix string) (events.SubscriptionID, error) {
+	return bus.Subscribe(prefix+".**", func(ctx context.Context, event events.Event) error {
+		return d.Dispatch(event)
+	})
+}
+
+// Dispatch queues an event for every subscription matching its resource
+// kind and type. Events without a resource kind are ignored.
+//
+// The event type matched is the "action" extension attribute of resource
+// events, or else the last segment of the CloudEvent type.
+//
+// Returns:
+//   - error: If the event can't be encoded or the dispatcher is shut down
+func (d *Dispatcher) Dispatch(event events.Event) error {
+	kind := event.ResourceKind()
+	if kind == "" {
+		return nil
+	}
+	eventType := eventAction(event)
+	subs := d.manager.matching(kind, eventType)
+	if len(subs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(event.Event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.ID(), err)
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return fmt.Errorf("webhook dispatcher is shut down")
+	}
+	for _, sub := range subs {
+		job := delivery{subscription: sub.GetUID(), eventID: event.ID(), eventType: event.Type(), body: body}
+		select {
+		case d.queue <- job:
+		default:
+			// Dropping is recorded as a failure, so the subscription's
+			// status shows events were missed
+			d.logger.Warn("webhook delivery queue full, dropping event",
+				"subscription", job.subscription, "event_id", job.eventID, "event_type", job.eventType)
+			d.manager.recordDelivery(context.Background(), job.subscription, 0, fmt.Errorf("delivery queue full"))
+		}
+	}
+	return nil
+}
+
+// Shutdown stops queueing deliveries and waits for the queued ones,
+// including their retries. If ctx ends first, the remaining deliveries are
+// abandoned.
+//
+// Parameters:
+//   - ctx: Bounds how long to wait for queued deliveries
+//
+// Returns:
+//   - error: If ctx ended before every delivery finished
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	d.Start() // Workers drain the queue even if the dispatcher never started
+
+	drained := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("webhook dispatcher shutdown: %d deliveries abandoned: %w", len(d.queue), ctx.Err())
+	}
+	d.cancel()
+	<-drained
+	return err
+}
+
+// worker delivers queued events until the queue is closed
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for job := range d.queue {
+		if d.ctx.Err() != nil {
+			continue // Abandoned by Shutdown
+		}
+		d.deliver(job)
+	}
+}
+
+// deliver posts an event to a subscription, retrying failed attempts, and
+// records the outcome
+func (d *Dispatcher) deliver(job delivery) {
+	var (
+		statusCode int
+		err        error
+	)
+	for attempt := 1; attempt <= d.opts.MaxAttempts; attempt++ {
+		url, secret, ok := d.manager.target(job.subscription)
+		if !ok {
+			return // Deleted since the event was queued
+		}
+		var retry bool
+		statusCode, retry, err = d.post(job, url, secret)
+		if err == nil || !retry || attempt == d.opts.MaxAttempts {
+			break
+		}
+
+		backoff := d.backoff(attempt)
+		d.logger.Debug("webhook delivery failed, retrying",
+			"subscription", job.subscription, "event_id", job.eventID, "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-d.ctx.Done():
+			err = fmt.Errorf("%w (abandoned at shutdown)", err)
+			d.manager.recordDelivery(context.Background(), job.subscription, statusCode, err)
+			return
+		}
+	}
+
+	if err != nil {
+		d.logger.Warn("webhook delivery failed",
+			"subscription", job.subscription, "event_id", job.eventID, "event_type", job.eventType, "error", err)
+	}
+	d.manager.recordDelivery(context.Background(), job.subscription, statusCode, err)
+}
+
+// post makes one delivery attempt. It returns the response status, whether
+// a failure may be retried, and the failure.
+func (d *Dispatcher) post(job delivery, url, secret string) (int, bool, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(job.body))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid webhook request: %w", err)
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("User-Agent", "fabrica-webhook")
+	req.Header.Set(IDHeader, job.eventID)
+	req.Header.Set(SubscriptionHeader, job.subscription)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, job.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, true, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+	return resp.StatusCode, retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+// backoff returns the wait after a failed attempt
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	backoff := d.opts.InitialBackoff
+	for i := 1; i < attempt && backoff < d.opts.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.opts.MaxBackoff {
+		backoff = d.opts.MaxBackoff
+	}
+	return backoff
+}
+
+// eventAction returns the type of an event matched by subscriptions
+func eventAction(event events.Event) string {
+	if action, ok := event.Extensions()["action"].(string); ok && action != "" {
+		return strings.ToLower(action)
+	}
+	eventType := event.Type()
+	return eventType[strings.LastIndex(eventType, ".")+1:]
+}
+
+// Sign returns the signature of a delivery body sent at timestamp, the
+// value of the X-Webhook-Signature header.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10))) //nolint:errcheck // hash writes never fail
+	mac.Write([]byte("."))                              //nolint:errcheck
+	mac.Write(body)                                     //nolint:errcheck
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a delivery received by a webhook endpoint.
+//
+// Parameters:
+//   - secret: The secret of the subscription
+//   - header: The request headers
+//   - body: The request body
+//   - tolerance: How old the signature may be; 0 doesn't check its age
+//
+// Returns:
+//   - error: An error wrapping ErrInvalidSignature if the signature is
+//     missing, doesn't match or is too old
+//
+// Example:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	if err := webhook.Verify(secret, r.Header, body, 5*time.Minute); err != nil {
+//	    http.Error(w, err.Error(), http.StatusUnauthorized)
+//	    return
+//	}
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or malformed %s", ErrInvalidSignature, TimestampHeader)
+	}
+	if !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(Sign(secret, timestamp, body))) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+	if tolerance > 0 {
+		if age := time.Since(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: signed %s ago", ErrInvalidSignature, age.Round(time.Second))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+// Package webhook pushes resource events to external systems over HTTP.
+//
+// A WebhookSubscription names a URL, the resource kinds and event types it
+// wants and a secret. A Dispatcher subscribed to the event bus posts every
+// matching event to the URL as a structured CloudEvent, signed with the
+// secret, and retries failed deliveries with exponential backoff:
+//
+//	manager := webhook.NewManager(nil) // in-memory only
+//	sub, err := manager.Create(ctx, "inventory-sync", webhook.WebhookSubscriptionSpec{
+//	    URL:        "https://inventory.example.com/hooks/fabrica",
+//	    Kinds:      []string{"Device"},
+//	    EventTypes: []string{"created", "deleted"},
+//	})
+//
+//	dispatcher := webhook.NewDispatcher(manager, webhook.Options{})
+//	dispatcher.Start()
+//	dispatcher.Subscribe(bus, events.GetEventConfig().EventTypePrefix)
+//
+// Receivers check the X-Webhook-Signature header with Verify.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/resource"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+// Kind is the resource kind used for webhook subscriptions.
+const Kind = "WebhookSubscription"
+
+// WebhookSubscriptionSpec defines where and which events are delivered.
+//
+//nolint:revive // "WebhookSubscriptionSpec" name is intentional; matches generated <Kind>Spec naming
+type WebhookSubscriptionSpec struct {
+	// URL receives the events (http or https)
+	URL string `json:"url" yaml:"url"`
+
+	// Kinds are the resource kinds delivered, e.g. Device; empty delivers
+	// every kind
+	Kinds []string `json:"kinds,omitempty" yaml:"kinds,omitempty"`
+
+	// EventTypes are the event types delivered, the last segment of the
+	// CloudEvent type, e.g. created or deleted; empty delivers every type
+	EventTypes []string `json:"eventTypes,omitempty" yaml:"eventTypes,omitempty"`
+
+	// Secret signs deliveries. It is generated when empty, returned when
+	// the subscription is created and omitted afterwards.
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+
+	// Paused stops deliveries without removing the subscription
+	Paused bool `json:"paused,omitempty" yaml:"paused,omitempty"`
+}
+
+// WebhookSubscriptionStatus reports the deliveries of a subscription.
+//
+//nolint:revive // "WebhookSubscriptionStatus" name is intentional; matches generated <Kind>Status naming
+type WebhookSubscriptionStatus struct {
+	// Delivered counts events the URL accepted
+	Delivered int64 `json:"delivered" yaml:"delivered"`
+
+	// Failed counts events given up on after every attempt
+	Failed int64 `json:"failed" yaml:"failed"`
+
+	// LastDeliveryAt is when the last delivery finished, successful or not
+	LastDeliveryAt *time.Time `json:"lastDeliveryAt,omitempty" yaml:"lastDeliveryAt,omitempty"`
+
+	// LastStatusCode is the HTTP status of the last attempt, 0 if it got no
+	// response
+	LastStatusCode int `json:"lastStatusCode,omitempty" yaml:"lastStatusCode,omitempty"`
+
+	// LastError describes why the last delivery failed; empty once one
+	// succeeds
+	LastError string `json:"lastError,omitempty" yaml:"lastError,omitempty"`
+}
+
+// WebhookSubscription is a subscription of an external system to resource
+// events.
+type WebhookSubscription struct {
+	resource.Resource
+	Spec   WebhookSubscriptionSpec   `json:"spec" yaml:"spec"`
+	Status WebhookSubscriptionStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+// Matches reports whether the subscription wants events of a type for a
+// resource kind.
+func (s *WebhookSubscription) Matches(kind, eventType string) bool {
+	if s.Spec.Paused {
+		return false
+	}
+	return matchesAny(s.Spec.Kinds, kind) && matchesAny(s.Spec.EventTypes, eventType)
+}
+
+// redacted returns a copy of the subscription without its secret
+func (s *WebhookSubscription) redacted() *WebhookSubscription {
+	c := *s
+	c.Spec.Secret = ""
+	return &c
+}
+
+// matchesAny reports whether values is empty or holds value
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Manager creates, updates and deletes webhook subscriptions.
+//
+// Subscriptions are kept in memory and, when a storage backend is supplied,
+// written through to the backend under the "WebhookSubscription" kind so
+// they survive restarts. Their secrets are persisted with them, since
+// deliveries are signed with the secret itself.
+type Manager struct {
+	mu      sync.RWMutex
+	subs    map[string]*WebhookSubscription
+	backend storage.StorageBackend
+	now     func() time.Time
+}
+
+// NewManager creates a webhook subscription manager.
+//
+// Parameters:
+//   - backend: Optional storage backend for persistence; nil keeps subscriptions in memory only
+//
+// Returns:
+//   - *Manager: A manager with any subscriptions already persisted in backend loaded
+func NewManager(backend storage.StorageBackend) *Manager {
+	m := &Manager{
+		subs:    make(map[string]*WebhookSubscription),
+		backend: backend,
+		now:     time.Now,
+	}
+	if backend != nil {
+		if raw, err := backend.LoadAll(context.Background(), Kind); err == nil {
+			for _, data := range raw {
+				var s WebhookSubscription
+				if err := json.Unmarshal(data, &s); err == nil && s.GetUID() != "" && s.Spec.Secret != "" {
+					m.subs[s.GetUID()] = &s
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Create creates a webhook subscription.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts
+//   - name: Name identifying the subscription (e.g., the receiving system)
+//   - spec: URL, filters and optional secret of the subscription
+//
+// Returns:
+//   - *WebhookSubscription: The subscription, with its secret; later reads omit it
+//   - error: An error if the name or spec is invalid or the subscription can't be saved
+func (m *Manager) Create(ctx context.Context, name string, spec WebhookSubscriptionSpec) (*WebhookSubscription, error) {
+	if name == "" {
+		return nil, fmt.Errorf("webhook subscription name is required")
+	}
+	if err := validateSpec(spec); err != nil {
+		return nil, err
+	}
+	if spec.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		spec.Secret = secret
+	}
+	uid, err := resource.GenerateUID("whk")
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook subscription UID: %w", err)
+	}
+
+	sub := &WebhookSubscription{Spec: spec}
+	sub.APIVersion = "v1"
+	sub.Kind = Kind
+	sub.Metadata.Initialize(name, uid)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.persist(ctx, sub); err != nil {
+		return nil, err
+	}
+	m.subs[uid] = sub
+	c := *sub
+	return &c, nil
+}
+
+// Get returns the webhook subscription with the given UID, without its
+// secret.
+func (m *Manager) Get(uid string) (*WebhookSubscription, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.subs[uid]
+	if !ok {
+		return nil, false
+	}
+	return s.redacted(), true
+}
+
+// List returns all webhook subscriptions, without their secrets, sorted by
+// UID.
+func (m *Manager) List() []*WebhookSubscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]*WebhookSubscription, 0, len(m.subs))
+	for _, s := range m.subs {
+		result = append(result, s.redacted())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetUID() < result[j].GetUID()
+	})
+	return result
+}
+
+// Update replaces the spec of a webhook subscription. An empty secret keeps
+// the current one. The status is kept.
+//
+// Returns storage.ErrNotFound if the subscription doesn't exist.
+func (m *Manager) Update(ctx context.Context, uid string, spec WebhookSubscriptionSpec) (*WebhookSubscription, error) {
+	if err := validateSpec(spec); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.subs[uid]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	if spec.Secret == "" {
+		spec.Secret = s.Spec.Secret
+	}
+	updated := *s
+	updated.Spec = spec
+	updated.Touch()
+	if err := m.persist(ctx, &updated); err != nil {
+		return nil, err
+	}
+	m.subs[uid] = &updated
+	return updated.redacted(), nil
+}
+
+// Delete removes a webhook subscription. Deliveries already queued for it
+// are dropped.
+//
+// Returns storage.ErrNotFound if the subscription doesn't exist.
+func (m *Manager) Delete(ctx context.Context, uid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subs[uid]; !ok {
+		return storage.ErrNotFound
+	}
+	if m.backend != nil {
+		if err := m.backend.Delete(ctx, Kind, uid); err != nil {
+			return fmt.Errorf("failed to delete webhook subscription: %w", err)
+		}
+	}
+	delete(m.subs, uid)
+	return nil
+}
+
+// matching returns the subscriptions wanting events of a type for a
+// resource kind, with their secrets
+func (m *Manager) matching(kind, eventType string) []*WebhookSubscription {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []*WebhookSubscription
+	for _, s := range m.subs {
+		if s.Matches(kind, eventType) {
+			c := *s
+			result = append(result, &c)
+		}
+	}
+	return result
+}
+
+// target returns the current URL and secret of a subscription, or false
+// once it was deleted
+func (m *Manager) target(uid string) (string, string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.subs[uid]
+	if !ok {
+		return "", "", false
+	}
+	return s.Spec.URL, s.Spec.Secret, true
+}
+
+// recordDelivery updates the status of a subscription with the outcome of a
+// delivery. Failures to persist it are ignored: the status is informational.
+func (m *Manager) recordDelivery(ctx context.Context, uid string, statusCode int, deliveryErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.subs[uid]
+	if !ok {
+		return
+	}
+	updated := *s
+	now := m.now()
+	updated.Status.LastDeliveryAt = &now
+	updated.Status.LastStatusCode = statusCode
+	if deliveryErr != nil {
+		updated.Status.Failed++
+		updated.Status.LastError = deliveryErr.Error()
+	} else {
+		updated.Status.Delivered++
+		updated.Status.LastError = ""
+	}
+	_ = m.persist(ctx, &updated)
+	m.subs[uid] = &updated
+}
+
+// persist writes a subscription to the backend if one is configured.
+// Callers hold m.mu.
+func (m *Manager) persist(ctx context.Context, s *WebhookSubscription) error {
+	if m.backend == nil {
+		return nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook subscription: %w", err)
+	}
+	if err := m.backend.Save(ctx, Kind, s.GetUID(), data); err != nil {
+		return fmt.Errorf("failed to save webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// validateSpec checks the URL of a subscription
+func validateSpec(spec WebhookSubscriptionSpec) error {
+	if spec.URL == "" {
+		return fmt.Errorf("webhook url is required")
+	}
+	u, err := url.Parse(spec.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook url must be an absolute http or https URL: %s", spec.URL)
+	}
+	return nil
+}
+
+// newSecret generates a random signing secret
+func newSecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(random), nil
+}
@@ -0,0 +1,213 @@
+// Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openchami/fabrica/pkg/events"
+	"github.com/openchami/fabrica/pkg/storage"
+)
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemoryBackend()
+	manager := NewManager(backend)
+
+	if _, err := manager.Create(ctx, "sync", WebhookSubscriptionSpec{URL: "ftp://example.com"}); err == nil {
+		t.Error("Create accepted a non-HTTP URL")
+	}
+	sub, err := manager.Create(ctx, "sync", WebhookSubscriptionSpec{URL: "https://example.com/hook", Kinds: []string{"Device"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Spec.Secret == "" {
+		t.Error("Create didn't generate a secret")
+	}
+	secret := sub.Spec.Secret
+
+	// Reads omit the secret, which updates keep unless they set one
+	if got, ok := manager.Get(sub.GetUID()); !ok || got.Spec.Secret != "" {
+		t.Errorf("Get = %+v, %v; want the subscription without its secret", got, ok)
+	}
+	updated, err := manager.Update(ctx, sub.GetUID(), WebhookSubscriptionSpec{URL: "https://example.com/v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Spec.URL != "https://example.com/v2" || updated.Spec.Secret != "" {
+		t.Errorf("Update = %+v", updated.Spec)
+	}
+	if _, s, _ := manager.target(sub.GetUID()); s != secret {
+		t.Error("Update without a secret changed it")
+	}
+
+	// Subscriptions survive restarts when a backend is configured
+	reloaded := NewManager(backend)
+	if list := reloaded.List(); len(list) != 1 || list[0].Spec.URL != "https://example.com/v2" {
+		t.Errorf("reloaded subscriptions = %+v", list)
+	}
+
+	if err := manager.Delete(ctx, sub.GetUID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Delete(ctx, sub.GetUID()); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+	if len(NewManager(backend).List()) != 0 {
+		t.Error("Delete didn't remove the subscription from the backend")
+	}
+}
+
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	sub := &WebhookSubscription{Spec: WebhookSubscriptionSpec{Kinds: []string{"Device"}, EventTypes: []string{"created", "deleted"}}}
+	tests := []struct {
+		kind, eventType string
+		want            bool
+	}{
+		{"Device", "created", true},
+		{"Device", "updated", false},
+		{"Rack", "created", false},
+	}
+	for _, tt := range tests {
+		if got := sub.Matches(tt.kind, tt.eventType); got != tt.want {
+			t.Errorf("Matches(%s, %s) = %v, want %v", tt.kind, tt.eventType, got, tt.want)
+		}
+	}
+	if (&WebhookSubscription{}).Matches("Rack", "patched") != true {
+		t.Error("A subscription without filters should match every event")
+	}
+	if (&WebhookSubscription{Spec: WebhookSubscriptionSpec{Paused: true}}).Matches("Rack", "patched") {
+		t.Error("A paused subscription matched")
+	}
+}
+
+// receiver is a webhook endpoint answering with the queued statuses, then 200
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	rec := &receiver{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		status := http.StatusOK
+		if len(rec.statuses) > 0 {
+			status, rec.statuses = rec.statuses[0], rec.statuses[1:]
+		}
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return rec, server
+}
+
+func resourceEvent(t *testing.T, action string) events.Event {
+	t.Helper()
+	config := events.DefaultEventConfig()
+	config.Enabled = true
+	config.EventTypePrefix = "com.openchami"
+	events.SetEventConfig(config)
+	t.Cleanup(func() { events.SetEventConfig(events.DefaultEventConfig()) })
+
+	event, err := events.NewResourceEvent(action, "Device", "dev-1", map[string]string{"uid": "dev-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *event
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	rec, server := newReceiver(t, http.StatusServiceUnavailable)
+	manager := NewManager(nil)
+	sub, err := manager.Create(ctx, "sync", WebhookSubscriptionSpec{URL: server.URL, EventTypes: []string{"created"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatcher := NewDispatcher(manager, Options{InitialBackoff: time.Millisecond})
+	dispatcher.Start()
+
+	if err := dispatcher.Dispatch(resourceEvent(t, "updated")); err != nil {
+		t.Fatal(err)
+	}
+	event := resourceEvent(t, "created")
+	if err := dispatcher.Dispatch(event); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The 503 is retried; the updated event isn't delivered at all
+	if len(rec.requests) != 2 {
+		t.Fatalf("received %d requests, want 2", len(rec.requests))
+	}
+	req, body := rec.requests[1], rec.bodies[1]
+	if req.Header.Get("Content-Type") != ContentType || req.Header.Get(IDHeader) != event.ID() || req.Header.Get(SubscriptionHeader) != sub.GetUID() {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+	if err := Verify(sub.Spec.Secret, req.Header, body, time.Minute); err != nil {
+		t.Errorf("Verify = %v", err)
+	}
+	if err := Verify("other", req.Header, body, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify with another secret = %v, want ErrInvalidSignature", err)
+	}
+	if got, _ := manager.Get(sub.GetUID()); got.Status.Delivered != 1 || got.Status.Failed != 0 || got.Status.LastStatusCode != http.StatusOK {
+		t.Errorf("status = %+v, want one delivery", got.Status)
+	}
+
+	if err := dispatcher.Dispatch(event); err == nil {
+		t.Error("Dispatch after Shutdown succeeded")
+	}
+}
+
+func TestDispatcherPermanentFailure(t *testing.T) {
+	ctx := context.Background()
+	rec, server := newReceiver(t, http.StatusBadRequest)
+	manager := NewManager(nil)
+	sub, err := manager.Create(ctx, "sync", WebhookSubscriptionSpec{URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatcher := NewDispatcher(manager, Options{InitialBackoff: time.Millisecond})
+	dispatcher.Start()
+
+	if err := dispatcher.Dispatch(resourceEvent(t, "deleted")); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 400 isn't retried
+	if len(rec.requests) != 1 {
+		t.Errorf("received %d requests, want 1", len(rec.requests))
+	}
+	if got, _ := manager.Get(sub.GetUID()); got.Status.Failed != 1 || got.Status.LastStatusCode != http.StatusBadRequest || got.Status.LastError == "" {
+		t.Errorf("status = %+v, want one failure", got.Status)
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := NewDispatcher(NewManager(nil), Options{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := d.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 Copyright © 2025 OpenCHAMI a Series of LF Projects, LLC
+//
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// Features are enabled in .fabrica.yaml and generated with `fabrica
+// generate`, so these tests cover the generation runner the CLI writes, not
+// only the generator it calls.
+
+func (s *FabricaTestSuite) TestCORSGeneration() {
+	project := s.createProject("cors-test", "github.com/test/cors", "file")
+
+	s.Require().NoError(project.Initialize(s.fabricaBinary))
+	s.Require().NoError(project.AddResource(s.fabricaBinary, "Item"))
+	s.Require().NoError(project.EnableFeature("cors", map[string]interface{}{
+		"allowed_origins": []string{"https://app.example.com"},
+	}))
+	s.Require().NoError(project.Generate(s.fabricaBinary))
+
+	project.AssertFileExists("cmd/server/cors_generated.go")
+	s.Require().NoError(project.Build())
+
+	// Preflights are answered before routing
+	s.Require().NoError(project.StartServer())
+	req, err := http.NewRequest(http.MethodOptions, "http://localhost:8080/items", nil)
+	s.Require().NoError(err)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	resp.Body.Close() //nolint:all
+
+	s.Less(resp.StatusCode, 300, "preflight should succeed")
+	s.Equal("https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func (s *FabricaTestSuite) TestLimitsGeneration() {
+	project := s.createProject("limits-test", "github.com/test/limits", "file")
+
+	s.Require().NoError(project.Initialize(s.fabricaBinary))
+	s.Require().NoError(project.AddResource(s.fabricaBinary, "Item"))
+	s.Require().NoError(project.EnableFeature("limits", map[string]interface{}{
+		"max_body_size": 1024,
+	}))
+	s.Require().NoError(project.Generate(s.fabricaBinary))
+
+	project.AssertFileExists("cmd/server/limits_generated.go")
+	s.Require().NoError(project.Build())
+}
+
+func (s *FabricaTestSuite) TestEncryptionGeneration() {
+	project := s.createProject("encryption-test", "github.com/test/encryption", "file")
+
+	s.Require().NoError(project.Initialize(s.fabricaBinary))
+	s.Require().NoError(project.AddResource(s.fabricaBinary, "Item"))
+	// Without redact_in_list, handlers don't redact sensitive fields
+	s.Require().NoError(project.EnableFeature("encryption", nil))
+	s.Require().NoError(project.Generate(s.fabricaBinary))
+
+	s.Require().NoError(project.Build())
+}
+
+func (s *FabricaTestSuite) TestEncryptionKeyCheckedAtStartup() {
+	project := s.createProject("encryption-key-test", "github.com/test/encryptionkey", "file")
+
+	s.Require().NoError(project.Initialize(s.fabricaBinary))
+	s.Require().NoError(project.AddResource(s.fabricaBinary, "Item"))
+	s.Require().NoError(project.EnableFeature("encryption", map[string]interface{}{
+		"mode": "both",
+	}))
+	s.Require().NoError(project.Generate(s.fabricaBinary))
+	s.Require().NoError(project.Build())
+
+	// Without a key, the server exits instead of failing every request
+	s.T().Setenv("FABRICA_ENCRYPTION_KEY", "")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "./server")
+	cmd.Dir = project.Dir
+	output, err := cmd.CombinedOutput()
+	s.Require().Error(err, "server should exit without an encryption key")
+	s.NotEqual(context.DeadlineExceeded, ctx.Err(), "server should exit, not keep running")
+	s.Contains(string(output), "FABRICA_ENCRYPTION_KEY")
+
+	s.T().Setenv("FABRICA_ENCRYPTION_KEY", "dGVzdC1rZXktZm9yLWdlbmVyYXRlZC10ZXN0cy0wMDA=")
+	s.Require().NoError(project.StartServer())
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
 )
 
 // TestProject represents a fabrica test project
@@ -104,6 +105,37 @@ func (p *TestProject) Initialize(fabricaBinary string) error {
 	return nil
 }
 
+// EnableFeature enables a feature in .fabrica.yaml, with its other
+// settings, before generation
+func (p *TestProject) EnableFeature(feature string, settings map[string]interface{}) error {
+	configPath := filepath.Join(p.Dir, ".fabrica.yaml")
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read .fabrica.yaml: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return fmt.Errorf("failed to parse .fabrica.yaml: %w", err)
+	}
+	features, _ := config["features"].(map[string]interface{})
+	if features == nil {
+		features = make(map[string]interface{})
+		config["features"] = features
+	}
+	section := map[string]interface{}{"enabled": true}
+	for key, value := range settings {
+		section[key] = value
+	}
+	features[feature] = section
+
+	content, err = yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode .fabrica.yaml: %w", err)
+	}
+	return os.WriteFile(configPath, content, 0644)
+}
+
 // AddResource adds a resource to the project
 func (p *TestProject) AddResource(fabricaBinary, resourceName string) error {
 	cmd := exec.Command(fabricaBinary, "add", "resource", resourceName)